@@ -1,174 +1,675 @@
 package main
 
 import (
+	"flag"
 	"fmt"
 	"os"
+	"os/exec"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/seunggabi/claude-dashboard/internal/app"
+	"github.com/seunggabi/claude-dashboard/internal/cli"
+	"github.com/seunggabi/claude-dashboard/internal/config"
+	"github.com/seunggabi/claude-dashboard/internal/conversation"
+	"github.com/seunggabi/claude-dashboard/internal/projects"
 	"github.com/seunggabi/claude-dashboard/internal/setup"
+	"github.com/seunggabi/claude-dashboard/internal/summary"
+	"github.com/seunggabi/claude-dashboard/internal/timing"
 )
 
 var version = "dev"
 
 func main() {
+	startup := timing.New()
 	app.Version = version
-	app.DrainStdin()
 
 	// Always update version cache on startup (important for Homebrew upgrades)
 	// This is silent and fast, so it won't impact user experience
 	if version != "" && version != "dev" {
 		_ = setup.UpdateVersionCache(version)
 	}
+	startup.Mark("version cache")
 
-	// Auto-setup on first run (before any command)
-	// Skip for --version, --help, and setup commands
-	skipAutoSetup := len(os.Args) > 1 && (os.Args[1] == "--version" || os.Args[1] == "-v" ||
-		os.Args[1] == "--help" || os.Args[1] == "-h" || os.Args[1] == "setup")
-	if !skipAutoSetup {
-		runAutoSetup()
-	}
-
-	if len(os.Args) > 1 {
-		switch os.Args[1] {
-		case "--version", "-v":
-			fmt.Printf("claude-dashboard %s\n", version)
-			os.Exit(0)
-		case "--help", "-h":
-			printHelp()
-			os.Exit(0)
-		case "setup":
-			if err := setup.Setup(false, version); err != nil {
-				fmt.Fprintf(os.Stderr, "Setup failed: %v\n", err)
-				os.Exit(1)
-			}
-			os.Exit(0)
-		case "attach":
-			if len(os.Args) < 3 {
-				fmt.Fprintln(os.Stderr, "Usage: claude-dashboard attach <session-name>")
-				os.Exit(1)
-			}
-			if err := app.ExecAttach(os.Args[2]); err != nil {
-				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
-				os.Exit(1)
-			}
-			os.Exit(0)
-		case "new":
-			path, _ := os.Getwd()
-			name := ""
-			claudeArgs := ""
-
-			// Parse args: first non-flag arg (not starting with "-") is name
-			// Flags starting with "-" or "--" that are not --path/--args are passed as claude args
-			argStart := 2
-			if len(os.Args) > 2 && !strings.HasPrefix(os.Args[2], "-") {
-				name = os.Args[2]
-				argStart = 3
-			}
+	// A profile chosen with CLAUDE_DASHBOARD_PROFILE applies unless --profile
+	// picks another; sessions started by the dashboard inherit it.
+	if err := config.SetProfile(os.Getenv(config.ProfileEnv)); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %s: %v\n", config.ProfileEnv, err)
+		os.Exit(1)
+	}
 
-			var extraClaudeArgs []string
-			for i := argStart; i < len(os.Args); i++ {
-				switch os.Args[i] {
-				case "--path":
-					if i+1 < len(os.Args) {
-						path = os.Args[i+1]
-						i++
-					}
-				case "--args":
-					if i+1 < len(os.Args) {
-						claudeArgs = os.Args[i+1]
-						i++
-					}
-				default:
-					// Pass unknown flags (e.g. -r, -c, --resume) as claude args
-					extraClaudeArgs = append(extraClaudeArgs, os.Args[i])
+	var demo bool
+	cmd := &cli.App{
+		Name:     "claude-dashboard",
+		Version:  version,
+		Summary:  "claude-dashboard - k9s-style Claude Code Session Manager",
+		Footer:   helpFooter,
+		Commands: commands(startup, &demo),
+		Globals: func(fs *flag.FlagSet) {
+			fs.Func("profile", "Config profile to use: `NAME` of ~/.claude-dashboard/profiles/NAME.yaml, or default", app.UseProfile)
+		},
+		// Auto-setup on first run, before any command but setup and doctor;
+		// help and version output never get here. The demo needs no setup.
+		Before: func(c *cli.Command) {
+			switch c.Name {
+			case "setup", "doctor", "selftest", "import", "pricing", "summary", "calendar":
+			case "":
+				if !demo {
+					runAutoSetup()
 				}
+			default:
+				runAutoSetup()
 			}
+			startup.Mark("setup check")
+		},
+		Stdout: os.Stdout,
+	}
+	if err := cmd.Run(os.Args[1:]); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+}
 
-			// Merge --args value and extra flags
-			if len(extraClaudeArgs) > 0 {
-				extra := strings.Join(extraClaudeArgs, " ")
-				if claudeArgs != "" {
-					claudeArgs = claudeArgs + " " + extra
-				} else {
-					claudeArgs = extra
+// commands returns the subcommands, in the order the help lists them. The
+// --demo flag of the dashboard is kept in demo.
+func commands(startup *timing.Recorder, demo *bool) []*cli.Command {
+	var (
+		path, claudeArgs string
+		resume           string
+		continueLast     bool
+		namesOnly        bool
+		lines, days      int
+		format, out      string
+		yesterday, post  bool
+		control, tools   bool
+		force            bool
+		date, project    string
+		webAddr, token   string
+		timings          bool
+		configFrom       string
+		assumeYes        bool
+		noTmuxConf       bool
+		updateBindings   bool
+		asJSON           bool
+	)
+	return []*cli.Command{
+		{
+			Usage:   "[--timings] [--demo]",
+			Summary: "Start the TUI dashboard",
+			Flags: func(fs *flag.FlagSet) {
+				fs.BoolVar(&timings, "timings", false, "print how long each startup step takes instead of starting")
+				fs.BoolVar(demo, "demo", false, "show made-up sessions and conversations; needs neither tmux nor claude")
+			},
+			Run: func([]string) error {
+				switch {
+				case timings:
+					return app.WriteTimings(os.Stdout, startup)
+				case *demo:
+					return app.RunDemo()
 				}
-			}
+				return app.Run()
+			},
+		},
+		{
+			Name:    "setup",
+			Usage:   "[options]",
+			Summary: "Install helper scripts and configure tmux",
+			Help: `Installs the helper scripts to ~/.local/bin, the tmux settings to ~/.tmux.conf
+and the status hooks to ~/.claude/settings.json, writing only what differs
+from what is there: running it again changes nothing, so configuration
+management tools can run it on every provisioning. With --json the outcome
+of each step (changed, unchanged, skipped, warning or failed) is printed as
+JSON, with "changed": false when nothing had to change.
 
-			// Default name: path after home dir, e.g. ~/project/foo → project-foo
-			if name == "" {
-				homeDir, _ := os.UserHomeDir()
-				rel := path
-				if strings.HasPrefix(path, homeDir) {
-					rel = strings.TrimPrefix(path, homeDir)
-					rel = strings.TrimPrefix(rel, "/")
+The mouse toggle (F12) and save history (Ctrl+S) keys are set with
+tmux_mouse_toggle_key and tmux_save_history_key in config.yaml, "none"
+leaving one unbound. --update-bindings applies them alone: it rewrites the
+bindings in ~/.tmux.conf, unbinds the keys they replace and reloads tmux,
+asking for each key first when run on a terminal.`,
+			Flags: func(fs *flag.FlagSet) {
+				fs.StringVar(&configFrom, "config-from", "", "install `file` as config.yaml once it validates")
+				fs.BoolVar(&assumeYes, "assume-yes", false, "replace a different config.yaml without asking")
+				fs.BoolVar(&noTmuxConf, "no-tmux-conf", false, "leave ~/.tmux.conf alone")
+				fs.BoolVar(&updateBindings, "update-bindings", false, "only rebind the tmux keys, asking for them on a terminal")
+				fs.BoolVar(&asJSON, "json", false, "print what each step did as JSON")
+			},
+			Run: func([]string) error {
+				if updateBindings {
+					var ask func(question, current string) string
+					if stdinIsTerminal() {
+						ask = askLine
+					}
+					return setup.UpdateBindings(os.Stdout, ask)
 				}
-				name = strings.ReplaceAll(rel, "/", "-")
-				if name == "" {
-					name = filepath.Base(path)
+				opts := setup.Options{Version: version, ConfigFrom: configFrom, AssumeYes: assumeYes, NoTmuxConf: noTmuxConf}
+				if asJSON {
+					return setup.SetupJSON(os.Stdout, opts)
 				}
-			}
+				if stdinIsTerminal() {
+					opts.Confirm = askYesNo
+				}
+				if err := setup.Setup(os.Stdout, opts); err != nil {
+					return fmt.Errorf("setup failed: %w", err)
+				}
+				return nil
+			},
+		},
+		{
+			Name:    "doctor",
+			Summary: "Diagnose the setup and print how to fix each problem",
+			Help: `Checks tmux, the helper scripts, ~/.tmux.conf, the claude binary,
+~/.claude/projects, terminal capabilities and config.yaml. Exits non-zero
+when a check fails; warnings do not count.`,
+			Run: func([]string) error { return app.Doctor(os.Stdout) },
+		},
+		{
+			Name:    "selftest",
+			Summary: "Try creating, listing, reading, prompting and killing a session",
+			Help: `Runs what the dashboard does with tmux end to end on a scratch tmux
+server of its own (tmux -L), leaving your sessions alone: creates a session,
+finds it in the session list, reads its pane, types into it and kills it.
+It needs neither claude nor setup. Exits non-zero when a step fails, so CI
+and bug reports can check that the environment works.`,
+			Run: func([]string) error { return app.Selftest(os.Stdout) },
+		},
+		{
+			Name:    "list",
+			Usage:   "[options]",
+			Summary: "List sessions on this machine and configured hosts",
+			Flags: func(fs *flag.FlagSet) {
+				fs.BoolVar(&namesOnly, "names", false, "print only session names")
+			},
+			Run: func([]string) error { return app.ListSessions(os.Stdout, namesOnly) },
+		},
+		{
+			Name:    "new",
+			Usage:   "[NAME] [options] [CLAUDE-FLAGS...]",
+			Summary: "Create a new session (name defaults to path) and attach",
+			Help: `Attaches to the session instead if it already exists. --resume takes the id
+of a conversation in the directory, or the start of one as long as no other
+conversation shares it (see C in the dashboard). Flags not listed below
+(e.g. -r without an id) are passed on to claude.`,
+			Flags: func(fs *flag.FlagSet) {
+				fs.StringVar(&path, "path", "", "working `dir`ectory (default: current directory)")
+				fs.StringVar(&claudeArgs, "args", "", "`arguments` to pass to claude (e.g. \"--model opus\")")
+				fs.StringVar(&resume, "resume", "", "resume the conversation `id` of the directory")
+				fs.BoolVar(&continueLast, "continue", false, "continue the most recent conversation of the directory")
+			},
+			PassThrough: true,
+			Run: func(args []string) error {
+				return runNew(args, path, claudeArgs, resume, continueLast)
+			},
+		},
+		{
+			Name:    "attach",
+			Usage:   "[HOST:]NAME",
+			Summary: "Attach to a session directly",
+			MinArgs: 1,
+			MaxArgs: 1,
+			Run:     func(args []string) error { return app.ExecAttach(args[0]) },
+		},
+		{
+			Name:    "adopt",
+			Usage:   "TERMINAL|PID [NAME]",
+			Summary: "Move claude running in a terminal into a tmux session",
+			Help: `TERMINAL is a terminal session as listed, e.g. terminal/pts/3, or its claude
+PID. claude there is stopped and its conversation resumed with --resume in a
+new session NAME (default: named after its directory), keeping --model and
+the permission flags it was started with.`,
+			MinArgs: 1,
+			MaxArgs: 2,
+			Run: func(args []string) error {
+				name := ""
+				if len(args) > 1 {
+					name = args[1]
+				}
+				return app.AdoptSession(os.Stdout, args[0], name)
+			},
+		},
+		{
+			Name:    "choose",
+			Summary: "Pick a dashboard session in tmux's own choose-tree (inside tmux)",
+			Help: `Opens choose-tree in the current pane, listing only the sessions the
+dashboard manages; picking one switches to it. Bind it in ~/.tmux.conf to
+reach it from anywhere, e.g.
+  bind-key C run-shell "claude-dashboard choose"`,
+			Run: func([]string) error { return app.ChooseSession() },
+		},
+		{
+			Name:    "kill",
+			Usage:   "[HOST:]NAME... [--force]",
+			Summary: "Kill sessions",
+			Help: `claude is interrupted and told to /exit first, so it is not cut off in the
+middle of a tool call; the session is killed once it has quit, or after
+10 seconds.`,
+			MinArgs: 1,
+			MaxArgs: -1,
+			Flags: func(fs *flag.FlagSet) {
+				fs.BoolVar(&force, "force", false, "kill right away, without letting claude exit")
+			},
+			Run: func(args []string) error { return app.KillSessions(os.Stdout, args, force) },
+		},
+		{
+			Name:    "logs",
+			Usage:   "[HOST:]NAME [options]",
+			Summary: "Print the recent pane output of a session",
+			MinArgs: 1,
+			MaxArgs: 1,
+			Flags: func(fs *flag.FlagSet) {
+				fs.IntVar(&lines, "lines", 200, "number of `lines` of history to print")
+			},
+			Run: func(args []string) error { return app.PrintLogs(os.Stdout, args[0], lines) },
+		},
+		{
+			Name:    "send",
+			Usage:   "[HOST:]NAME PROMPT...",
+			Summary: "Type a prompt into a session and press Enter",
+			// The prompt may contain words that look like flags.
+			PassThrough: true,
+			Run: func(args []string) error {
+				if len(args) < 2 {
+					return cli.UsageError("missing arguments")
+				}
+				return app.SendPrompt(args[0], strings.Join(args[1:], " "))
+			},
+		},
+		{
+			Name:    "lint",
+			Summary: "List sessions whose names break the naming policy, with suggested names",
+			Help: `Checks the managed sessions on this machine and every configured host
+against naming in the config. Exits non-zero when a session breaks it.`,
+			Run: func([]string) error { return app.LintSessions(os.Stdout) },
+		},
+		{
+			Name:    "retitle",
+			Usage:   "[[HOST:]NAME...]",
+			Summary: "Set tmux window names and pane titles of sessions to their project names",
+			Help: `New sessions are titled when they are created, so choose-tree and the tmux
+status line show the same names as the dashboard. Run this to repair
+sessions renamed since, or created by an older version. Without names,
+every session the dashboard created is retitled.`,
+			MaxArgs: -1,
+			Run:     func(args []string) error { return app.RetitleSessions(os.Stdout, args) },
+		},
+		{
+			Name:    "restore",
+			Summary: "Recreate saved sessions that are not running (e.g. after a reboot)",
+			Run:     func([]string) error { return app.RestoreSessions(os.Stdout) },
+		},
+		{
+			Name:    "export",
+			Usage:   "NAME [options]",
+			Summary: "Write a session's full conversation to a file",
+			Help: `The messages format is the conversation as an Anthropic Messages API
+request body, without system reminders and, unless --tools is set, tool
+calls and results, to replay it into another tool or claude elsewhere.`,
+			MinArgs: 1,
+			MaxArgs: 1,
+			Flags: func(fs *flag.FlagSet) {
+				fs.StringVar(&format, "format", "", "output `format`: md, json, html or messages (default: from --out extension, else md)")
+				fs.StringVar(&out, "out", "", "output `file` (default: stdout)")
+				fs.BoolVar(&tools, "tools", false, "keep tool calls and results in the messages format")
+			},
+			Run: func(args []string) error { return runExport(args[0], format, out, tools) },
+		},
+		{
+			Name:    "pr",
+			Usage:   "NAME [--out FILE]",
+			Summary: "Draft a pull request description from a session's conversation",
+			Help: `Sends the session's current conversation to a one-shot 'claude -p' run in
+its directory, asking for a title, summary, list of changes and testing
+notes. D in the dashboard copies the same draft to the clipboard. E.g.
+  claude-dashboard pr api --out PR_BODY.md && gh pr create --body-file PR_BODY.md`,
+			MinArgs: 1,
+			MaxArgs: 1,
+			Flags: func(fs *flag.FlagSet) {
+				fs.StringVar(&out, "out", "", "output `file`, e.g. PR_BODY.md (default: stdout)")
+			},
+			Run: func(args []string) error { return app.WritePRDraft(os.Stdout, args[0], out) },
+		},
+		{
+			Name:    "import",
+			Usage:   "FILE... [options]",
+			Summary: "Add transcripts from elsewhere to the archive view",
+			Help: `Each FILE is a claude .jsonl conversation log, e.g. from another machine's
+~/.claude/projects, or a conversation written by export --format json or
+messages. Imported conversations are listed and searchable in the archive
+view (A) next to archived sessions; .jsonl logs can be restored and resumed
+in --path, or in the directory they were recorded in.`,
+			MinArgs: 1,
+			MaxArgs: -1,
+			Flags: func(fs *flag.FlagSet) {
+				fs.StringVar(&project, "project", "", "`name` of the project the conversations belong to (default: from their directory)")
+				fs.StringVar(&path, "path", "", "local `directory` of that project, to restore them in")
+			},
+			Run: func(args []string) error { return app.ImportTranscripts(os.Stdout, args, project, path) },
+		},
+		{
+			Name:    "pricing",
+			Usage:   "[show|update]",
+			Summary: "Show the model prices used for cost estimates, or download the latest",
+			Help:    "update fetches the price table from pricing_url in the config and keeps it in\n~/.claude-dashboard/pricing.yaml. Without it, or offline, the prices built into\nthe binary are used. Prices under pricing in the config override both.",
+			MaxArgs: 1,
+			Run: func(args []string) error {
+				if len(args) == 0 || args[0] == "show" {
+					return app.ShowPricing(os.Stdout)
+				}
+				if args[0] != "update" {
+					return cli.UsageError(fmt.Sprintf("unknown pricing command %q", args[0]))
+				}
+				return app.UpdatePricing(os.Stdout)
+			},
+		},
+		{
+			Name:    "summary",
+			Usage:   "[options]",
+			Summary: "Summarize a day of sessions: activity, key conversations, commits and spend",
+			Help: `Covers today so far unless --yesterday or --date is given. Commits are
+read from the repositories conversations ran in and those of saved and
+running sessions. To post a daily digest, run it from cron, e.g.
+  0 9 * * 1-5  claude-dashboard summary --yesterday --post`,
+			Flags: func(fs *flag.FlagSet) {
+				fs.BoolVar(&yesterday, "yesterday", false, "summarize yesterday")
+				fs.StringVar(&date, "date", "", "summarize the `day` YYYY-MM-DD")
+				fs.StringVar(&format, "format", "md", "output `format`: md, json or slack")
+				fs.BoolVar(&post, "post", false, "also post the summary to slack_webhook from the config")
+			},
+			Run: func([]string) error { return runSummary(yesterday, date, format, post) },
+		},
+		{
+			Name:    "calendar",
+			Usage:   "[--days N] [--out FILE]",
+			Summary: "Export recent session activity as an iCalendar (.ics) file",
+			Help: `Each stretch of work in a project, across its conversations, becomes an
+event: work with no pause longer than 15 minutes, lasting at least 5.
+Events keep their IDs between exports, so importing again updates them.
+serve --web also serves the last 30 days at /calendar.ics to subscribe to.`,
+			Flags: func(fs *flag.FlagSet) {
+				fs.IntVar(&days, "days", app.CalendarDays, "cover the last `n` days")
+				fs.StringVar(&out, "out", "", "output `file` (default: stdout)")
+			},
+			Run: func([]string) error { return runCalendar(days, out) },
+		},
+		{
+			Name:    "serve",
+			Usage:   "--web ADDR [options]",
+			Summary: "Serve a web dashboard, e.g. to check on agents from a phone",
+			Help: `Shows sessions on this machine and configured hosts, their status and the
+tail of each conversation, updated live. Nothing can be changed from it
+unless --control is set. It has no login of its own: bind it to a trusted
+network (e.g. a VPN address), or set --token and open the printed URL.
 
-			sessionName := "cd-" + name
+With --control (which needs --token) automation can manage sessions on this
+machine:
+  POST   /api/sessions              {"name": "api", "path": "~/src/api", "args": ""}
+  DELETE /api/sessions/NAME
+  POST   /api/sessions/NAME/send    {"prompt": "run the tests"}
 
-			// If session already exists, just attach to it
-			if err := app.CreateSession(name, path, claudeArgs); err != nil {
-				// Session might already exist - try attaching
-				fmt.Printf("Attaching to existing session '%s'...\n", sessionName)
-			} else {
-				fmt.Printf("Session '%s' created in %s\n", sessionName, path)
-			}
+The triggers in the config are served at POST /hooks/NAME whether or not
+--control is set: a payload signed with a trigger's secret, as GitHub signs
+webhooks, starts a session for it and sends it the trigger's prompt.`,
+			Flags: func(fs *flag.FlagSet) {
+				fs.StringVar(&webAddr, "web", "", "listen `addr`ess, e.g. :8080 or 100.64.0.1:8080")
+				fs.StringVar(&token, "token", "", "require this `token` as ?token= or a bearer token on every request")
+				fs.BoolVar(&control, "control", false, "also serve the API that creates, kills and prompts sessions")
+			},
+			Run: func([]string) error {
+				if webAddr == "" {
+					return cli.UsageError("--web is required")
+				}
+				if control && token == "" {
+					return cli.UsageError("--control needs --token")
+				}
+				return app.ServeWeb(os.Stdout, webAddr, token, control)
+			},
+		},
+		{
+			Name:    "git-hook",
+			Usage:   "install|uninstall [DIR]",
+			Summary: "Add a Claude-Session trailer to commits made in dashboard sessions",
+			Help: `install writes a commit-msg hook to the repository containing DIR (default:
+the current directory), following core.hooksPath. While a dashboard session
+runs in the worktree, commits made there get a trailer naming it, e.g.
+  Claude-Session: cd-api
+and 'g' in the archive view lists the commits of an archived session.
+A commit-msg hook not written by claude-dashboard is left alone.`,
+			MinArgs: 1,
+			MaxArgs: 2,
+			Run: func(args []string) error {
+				dir := "."
+				if len(args) > 1 {
+					dir = args[1]
+				}
+				switch args[0] {
+				case "install":
+					return app.InstallGitHook(os.Stdout, dir)
+				case "uninstall":
+					return app.RemoveGitHook(os.Stdout, dir)
+				}
+				return cli.UsageError(fmt.Sprintf("unknown git-hook command %q", args[0]))
+			},
+		},
+		{
+			Name:    "hosts",
+			Usage:   "test [NAME...]",
+			Summary: "Check SSH reachability and tmux version of remote hosts",
+			MinArgs: 1,
+			MaxArgs: -1,
+			Run: func(args []string) error {
+				if args[0] != "test" {
+					return cli.UsageError(fmt.Sprintf("unknown hosts command %q", args[0]))
+				}
+				return app.TestHosts(os.Stdout, args[1:])
+			},
+		},
+	}
+}
 
-			if err := app.ExecAttach(sessionName); err != nil {
-				fmt.Fprintf(os.Stderr, "Error attaching: %v\n", err)
-				os.Exit(1)
-			}
-			os.Exit(0)
+// runNew creates a session and attaches to it. args holds the optional
+// name followed by flags meant for claude. resume and continueLast pick a
+// conversation of path for claude to carry on.
+func runNew(args []string, path, claudeArgs, resume string, continueLast bool) error {
+	if path == "" {
+		path, _ = os.Getwd()
+	}
+	switch {
+	case resume != "" && continueLast:
+		return cli.UsageError("--resume and --continue cannot be used together")
+	case resume != "":
+		dir, err := filepath.Abs(projects.ExpandHome(path))
+		if err != nil {
+			return err
+		}
+		l, err := conversation.FindLog(dir, resume)
+		if err != nil {
+			return err
 		}
+		claudeArgs = strings.TrimSpace(claudeArgs + " --resume " + l.ID())
+	case continueLast:
+		claudeArgs = strings.TrimSpace(claudeArgs + " --continue")
+	}
+	name := ""
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		name, args = args[0], args[1:]
 	}
 
-	if err := app.Run(); err != nil {
-		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
-		os.Exit(1)
+	// Merge --args value and extra flags
+	if len(args) > 0 {
+		extra := strings.Join(args, " ")
+		if claudeArgs != "" {
+			claudeArgs = claudeArgs + " " + extra
+		} else {
+			claudeArgs = extra
+		}
+	}
+
+	if name == "" {
+		name = app.DefaultName(path)
 	}
+
+	sessionName := "cd-" + name
+	if err := app.CheckSessionName(name, path); err != nil {
+		return err
+	}
+
+	// If session already exists, just attach to it
+	if err := app.CreateSession(name, path, claudeArgs); err != nil {
+		// Session might already exist - try attaching
+		fmt.Printf("Attaching to existing session '%s'...\n", sessionName)
+	} else {
+		fmt.Printf("Session '%s' created in %s\n", sessionName, path)
+	}
+
+	if err := app.ExecAttach(sessionName); err != nil {
+		return fmt.Errorf("attaching: %w", err)
+	}
+	return nil
 }
 
-// runAutoSetup runs first-time setup if not already configured.
-func runAutoSetup() {
-	if !setup.CheckSetup() {
-		fmt.Println("📦 First time setup detected...")
-		fmt.Println()
-		if err := setup.Setup(false, version); err != nil {
-			fmt.Fprintf(os.Stderr, "Auto-setup failed: %v\n", err)
-			fmt.Println()
-			fmt.Println("You can run 'claude-dashboard setup' manually later.")
-			fmt.Println()
+// runExport writes the conversation of the named session to out, or to
+// stdout when out is empty. Without a format it follows the extension of
+// out, defaulting to markdown.
+func runExport(name, format, out string, tools bool) error {
+	if format == "" {
+		format = strings.TrimPrefix(filepath.Ext(out), ".")
+		if format == "" {
+			format = "md"
 		}
 	}
+	f, err := conversation.ParseExportFormat(format)
+	if err != nil {
+		return err
+	}
+	opts := conversation.ExportOptions{Format: f, Tools: tools}
+
+	if out == "" {
+		return app.ExportConversation(os.Stdout, name, opts)
+	}
+	file, err := os.Create(out)
+	if err != nil {
+		return err
+	}
+	if err := app.ExportConversation(file, name, opts); err != nil {
+		file.Close()
+		os.Remove(out)
+		return err
+	}
+	if err := file.Close(); err != nil {
+		return err
+	}
+	fmt.Fprintf(os.Stderr, "Conversation exported to %s\n", out)
+	return nil
 }
 
-func printHelp() {
-	fmt.Println(`claude-dashboard - k9s-style Claude Code Session Manager
+// runSummary writes the summary of the chosen day, today by default.
+func runSummary(yesterday bool, date, format string, post bool) error {
+	f, err := summary.ParseFormat(format)
+	if err != nil {
+		return cli.UsageError(err.Error())
+	}
+	now := time.Now()
+	from, to := summary.Day(now)
+	switch {
+	case yesterday && date != "":
+		return cli.UsageError("--yesterday and --date cannot be combined")
+	case yesterday:
+		from, to = summary.Day(from.AddDate(0, 0, -1))
+	case date != "":
+		day, err := time.ParseInLocation("2006-01-02", date, time.Local)
+		if err != nil {
+			return cli.UsageError(fmt.Sprintf("invalid --date %q (want YYYY-MM-DD)", date))
+		}
+		from, to = summary.Day(day)
+	default:
+		to = now
+	}
+	return app.WriteSummary(os.Stdout, from, to, f, post)
+}
 
-Usage:
-  claude-dashboard                                     Start the TUI dashboard
-  claude-dashboard setup                               Install helper scripts and configure tmux
-  claude-dashboard new [NAME] [options]                Create a new session (name defaults to path)
-  claude-dashboard attach NAME                         Attach to a session directly
-  claude-dashboard --version                           Show version
-  claude-dashboard --help                              Show this help
+// runCalendar writes the calendar of the last days days to out, or stdout.
+func runCalendar(days int, out string) error {
+	if days < 1 {
+		return cli.UsageError("--days must be at least 1")
+	}
+	now := time.Now()
+	from := now.AddDate(0, 0, -days)
+	if out == "" {
+		return app.WriteCalendar(os.Stdout, from, now)
+	}
+	file, err := os.Create(out)
+	if err != nil {
+		return err
+	}
+	if err := app.WriteCalendar(file, from, now); err != nil {
+		file.Close()
+		os.Remove(out)
+		return err
+	}
+	if err := file.Close(); err != nil {
+		return err
+	}
+	fmt.Fprintf(os.Stderr, "Calendar written to %s\n", out)
+	return nil
+}
+
+// stdinIsTerminal reports whether someone can answer questions on stdin.
+func stdinIsTerminal() bool {
+	info, err := os.Stdin.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
 
-New Session Options:
-  --path <dir>         Working directory (default: current dir)
-  --args <claude-args> Arguments to pass to claude (e.g. "--model opus")
+// askYesNo asks question on stdout and reads the answer from stdin; only
+// y or yes agrees.
+func askYesNo(question string) bool {
+	fmt.Printf("%s [y/N] ", question)
+	var answer string
+	fmt.Scanln(&answer)
+	answer = strings.ToLower(strings.TrimSpace(answer))
+	return answer == "y" || answer == "yes"
+}
+
+// askLine asks question on stdout, offering current, and reads one word of
+// answer from stdin; empty keeps current.
+func askLine(question, current string) string {
+	fmt.Printf("%s [%s]: ", question, current)
+	var answer string
+	fmt.Scanln(&answer)
+	return strings.TrimSpace(answer)
+}
+
+// runAutoSetup runs first-time setup if not already configured. It runs
+// before most commands, so the common case is a single read of the setup
+// marker.
+func runAutoSetup() {
+	if setup.CheckSetup() {
+		return
+	}
+	// Setup configures tmux; without it the dashboard runs in terminal-only
+	// mode and there is nothing to install.
+	if _, err := exec.LookPath("tmux"); err != nil {
+		return
+	}
+	fmt.Println("📦 First time setup detected...")
+	fmt.Println()
+	if err := setup.Setup(os.Stdout, setup.Options{Version: version}); err != nil {
+		fmt.Fprintf(os.Stderr, "Auto-setup failed: %v\n", err)
+		fmt.Println()
+		fmt.Println("You can run 'claude-dashboard setup' manually later.")
+		fmt.Println()
+	}
+}
+
+const helpFooter = `Run 'claude-dashboard help COMMAND' for the options of a command.
 
 Keybindings:
   enter   Attach to session
   n       New session
   K       Kill session
   ctrl+k  Kill all idle sessions
+  R       Restore saved sessions
   l       View logs
+  p       Send prompt to session
   d       Session detail
   /       Filter
   r       Refresh
@@ -179,5 +680,5 @@ Requirements:
   - tmux must be installed
 
 Config:
-  ~/.claude-dashboard/config.yaml`)
-}
+  ~/.claude-dashboard/config.yaml
+  ~/.claude-dashboard/profiles/NAME.yaml (--profile NAME or CLAUDE_DASHBOARD_PROFILE)`
@@ -0,0 +1,95 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/seunggabi/claude-dashboard/internal/config"
+	"github.com/seunggabi/claude-dashboard/internal/session"
+	"github.com/seunggabi/claude-dashboard/internal/tmux"
+	"github.com/seunggabi/claude-dashboard/internal/ui"
+)
+
+// AdoptMsg reports moving a terminal session into the tmux session Name.
+type AdoptMsg struct {
+	Name string
+	Err  error
+}
+
+// adoptError returns why s cannot be adopted into a tmux session called
+// name, or nil if it can.
+func (m Model) adoptError(s session.Session, name string) error {
+	switch {
+	case m.demo != nil:
+		return session.ErrReadOnly
+	case s.Managed:
+		return fmt.Errorf("%s is already a tmux session", s.Name)
+	case s.Host != "":
+		return fmt.Errorf("only terminal sessions on this machine can be adopted")
+	case m.client == nil:
+		return session.ErrNoTmux
+	case s.Path == "":
+		return fmt.Errorf("the directory of %s is unknown", s.Name)
+	}
+	return checkName(namingPolicy(m.cfg), name, s.Path)
+}
+
+// confirmAdopt asks before adopting the terminal session s (a) into a tmux
+// session named after its directory.
+func (m Model) confirmAdopt(s session.Session) Model {
+	name := DefaultName(s.Path)
+	if err := m.adoptError(s, name); err != nil {
+		m.err = err
+		return m
+	}
+	m.adoptTarget, m.adoptName = s, name
+	m.adopting = true
+	m.confirm = ui.NewYesNo(fmt.Sprintf("Adopt %s as %s%s? claude in the terminal is stopped and its conversation resumed in tmux", s.Name, session.SessionPrefix, name))
+	return m
+}
+
+// adoptSession moves the terminal session s into the tmux session name.
+func (m Model) adoptSession(s session.Session, name string) tea.Cmd {
+	mgr := m.manager
+	return func() tea.Msg {
+		return AdoptMsg{Name: session.SessionPrefix + name, Err: mgr.Adopt(context.Background(), s, name)}
+	}
+}
+
+// AdoptSession moves the terminal session target, named as in the list
+// (e.g. terminal/pts/3) or by its PID, into a tmux session called name, or
+// named after its directory when name is empty, from the CLI.
+func AdoptSession(w io.Writer, target, name string) error {
+	client, err := tmux.NewClient()
+	if err != nil {
+		return fmt.Errorf("tmux is required: %w", err)
+	}
+	mgr := session.NewManager(client)
+	sessions, err := mgr.List(context.Background())
+	if err != nil {
+		return err
+	}
+	var s *session.Session
+	for i := range sessions {
+		if !sessions[i].Managed && (sessions[i].Name == target || sessions[i].PID == target) {
+			s = &sessions[i]
+			break
+		}
+	}
+	if s == nil {
+		return fmt.Errorf("no terminal session %s (see claude-dashboard list)", target)
+	}
+	if name == "" {
+		name = DefaultName(s.Path)
+	}
+	if err := checkName(namingPolicy(config.Load()), name, s.Path); err != nil {
+		return err
+	}
+	if err := mgr.Adopt(context.Background(), *s, name); err != nil {
+		return err
+	}
+	fmt.Fprintf(w, "Adopted %s as %s%s\n", s.Name, session.SessionPrefix, name)
+	return nil
+}
@@ -0,0 +1,76 @@
+package app
+
+import (
+	"context"
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/seunggabi/claude-dashboard/internal/session"
+	"github.com/seunggabi/claude-dashboard/internal/ui"
+)
+
+// AnswerMsg reports answering the prompt the session Name waited on with
+// Choice, approving it or not.
+type AnswerMsg struct {
+	Name     string
+	Approved bool
+	Choice   session.Choice
+	Err      error
+}
+
+// confirmAnswer asks before approving (y) or denying (N) the prompt the
+// waiting session s is on, showing its question as last detected; only
+// that question is answered.
+func (m Model) confirmAnswer(s session.Session, approve bool) Model {
+	switch {
+	case m.demo != nil:
+		m.err = session.ErrReadOnly
+		return m
+	case !s.Managed:
+		m.err = fmt.Errorf("terminal sessions cannot be answered (not a tmux session)")
+		return m
+	case s.Status != session.StatusWaiting:
+		m.notice = qualifiedName(s) + " is not waiting on a prompt"
+		return m
+	case s.Question == "":
+		m.notice = qualifiedName(s) + " asks nothing the dashboard can read; attach to answer"
+		return m
+	}
+	verb := "Deny"
+	if approve {
+		verb = "Approve"
+	}
+	m.answerTarget, m.answerApprove = s, approve
+	m.answering = true
+	m.confirm = ui.NewYesNo(fmt.Sprintf("%s %q in %s? The answer is sent without attaching", verb, s.Question, qualifiedName(s)))
+	return m
+}
+
+// answerPrompt answers the prompt s waits on without attaching, if it still
+// asks s.Question.
+func (m Model) answerPrompt(s session.Session, approve bool) tea.Cmd {
+	mgr, err := m.managerFor(s.Host)
+	return func() tea.Msg {
+		if err != nil {
+			return AnswerMsg{Name: qualifiedName(s), Err: err}
+		}
+		c, err := mgr.Answer(context.Background(), s, s.Question, approve)
+		return AnswerMsg{Name: qualifiedName(s), Approved: approve, Choice: c, Err: err}
+	}
+}
+
+// handleAnswer tells how a prompt was answered, and refreshes so the
+// session no longer shows waiting.
+func (m Model) handleAnswer(msg AnswerMsg) (Model, tea.Cmd) {
+	if msg.Err != nil {
+		m.notice = ""
+		m.err = msg.Err
+		return m, nil
+	}
+	verb := "Denied"
+	if msg.Approved {
+		verb = "Approved"
+	}
+	m.notice = fmt.Sprintf("%s in %s: %s", verb, msg.Name, msg.Choice.Label)
+	return m.refreshSessions()
+}
@@ -6,21 +6,34 @@ import (
 	"os"
 	"os/exec"
 	"regexp"
+	"sort"
 	"strings"
-	"syscall"
 	"time"
 
 	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/seunggabi/claude-dashboard/internal/archive"
 	"github.com/seunggabi/claude-dashboard/internal/config"
+	"github.com/seunggabi/claude-dashboard/internal/conversation"
+	"github.com/seunggabi/claude-dashboard/internal/demo"
+	"github.com/seunggabi/claude-dashboard/internal/filefeed"
+	"github.com/seunggabi/claude-dashboard/internal/git"
 	"github.com/seunggabi/claude-dashboard/internal/monitor"
+	"github.com/seunggabi/claude-dashboard/internal/projects"
 	"github.com/seunggabi/claude-dashboard/internal/session"
 	"github.com/seunggabi/claude-dashboard/internal/styles"
+	"github.com/seunggabi/claude-dashboard/internal/timesheet"
 	"github.com/seunggabi/claude-dashboard/internal/tmux"
 	"github.com/seunggabi/claude-dashboard/internal/ui"
+	"github.com/seunggabi/claude-dashboard/internal/usage"
 )
 
+// gitCacheTTL is how long the git state of a directory is reused; git
+// status can take a while in large repositories.
+const gitCacheTTL = 10 * time.Second
+
 // validSessionName matches only safe tmux session name characters.
 var validSessionName = regexp.MustCompile(`^[a-zA-Z0-9_./-]+$`)
 
@@ -36,41 +49,202 @@ const (
 	ViewDetail
 	ViewCreate
 	ViewHelp
+	ViewMonitor
+	ViewPulse
+	ViewBulk
+	ViewArchive
+	ViewConversations
 )
 
 // Model is the main Bubble Tea model.
 type Model struct {
 	// Core
+	client   *tmux.Client
 	manager  *session.Manager
+	watcher  *monitor.Watcher // nil in polling mode
+	remotes  []remoteHost
 	sessions []session.Session
+	hosts    []session.HostStatus // per-host rollup; empty when no remote hosts are configured
 	cfg      *config.Config
+	demo     *demo.Fleet // made-up sessions of --demo, which cannot be changed; nil otherwise
+
+	// pricingStale is set when the downloaded price table is missing or
+	// old; Init then fetches a new one if costs are shown or capped.
+	pricingStale bool
+
+	// Session changes from every manager; stopEvents ends the subscription
+	// and the webhooks.
+	events     <-chan session.Event
+	stopEvents func()
+
+	// Refresh health, shown in the status bar.
+	refreshing      bool            // a local session listing is in flight
+	listing         map[string]bool // remote hosts whose listing is in flight
+	listed          bool            // the first local listing arrived; until then the table is a skeleton
+	lastRefresh     time.Time       // last refresh that listed sessions without error
+	refreshFailures int             // failed refreshes since lastRefresh
+	recovery        serverRecovery  // recovering from the tmux server going away
 
 	// UI state
-	view         View
-	cursor       int
-	scrollOffset int
-	width        int
-	height       int
-	err          error
-	confirmMsg   string
-	confirming   bool
-	killingIdle  bool // true when confirming bulk kill of idle sessions
+	view          View
+	cursor        int
+	scrollOffset  int
+	width         int
+	height        int
+	err           error
+	notice        string          // outcome of the last action, cleared like err
+	confirm       ui.Confirm      // the y/n prompt or action menu shown, if open
+	killTarget    session.Session // the session the kill menu (K) is for
+	killingIdle   bool            // true when confirming bulk kill of idle sessions
+	restoring     bool            // true when confirming restore of saved sessions
+	adopting      bool            // true when confirming adoption of adoptTarget as adoptName
+	adoptTarget   session.Session
+	adoptName     string
+	answering     bool // true when confirming answering answerTarget's prompt
+	answerTarget  session.Session
+	answerApprove bool
+	profiles      []string // the profiles the switcher (S) offers, by number
+	nextProfile   string   // profile to restart the dashboard with; triggers Quit
 
 	// Sub-views
-	logView    ui.LogView
-	createForm ui.CreateForm
-	filterText textinput.Model
-	filtering  bool
+	logView     ui.LogView
+	fadePending bool // a FadeMsg for logView is on its way; see fadeIfFresh
+	createForm  ui.CreateForm
+	filterText  textinput.Model
+	filtering   bool
+	views       *viewHistory // undo (u) and redo (U) of filter, host, density and preview
+
+	// Onboarding tour, shown over the dashboard until tour_done is set.
+	touring  bool
+	tourStep int
+
+	// Prompt input (p): text typed here is sent to promptTarget.
+	promptInput  textinput.Model
+	prompting    bool
+	promptTarget session.Session
+
+	// Tag input (#): the tags typed here replace those of tagTarget.
+	tagInput  textinput.Model
+	tagging   bool
+	tagTarget session.Session
+
+	// Issue input (I): the key typed here becomes the issue of issueTarget.
+	issueInput   textinput.Model
+	issueEditing bool
+	issueTarget  session.Session
+
+	// Log viewer: logSession is the session being viewed, refetched in
+	// follow mode and whenever the conversation filter changes.
+	logSession  session.Session
+	logIsConv   bool   // logSession is shown as a conversation, not pane output
+	logFile     string // conversation log shown instead of logSession's latest
+	termInput   textinput.Model
+	termEditing bool
+	searchInput textinput.Model // search of pane logs (/)
+	searching   bool
+
+	// Monitor view (m): charts for monitorTarget from the sample history.
+	history          *monitor.History
+	monitorTarget    session.Session
+	monitorWindowIdx int
+	monitorMsgs      []conversation.Message
+
+	// Archive view (A): archived sessions matching archiveQuery;
+	// archiveLog is set while the log viewer shows the saved pane history,
+	// conversation or commits of one. archiving holds the sessions archive_after
+	// has picked, so each is archived once.
+	archived      []archive.Entry
+	archiveCursor int
+	archiveQuery  string
+	archiveLog    bool
+	archiveInput  textinput.Model
+	archiveSearch bool
+	archiving     map[string]bool
+
+	// Conversation picker (C): the conversation logs of convSession, most
+	// recent first; convLog is set while the log viewer shows one of them,
+	// so esc returns to the picker.
+	convSession session.Session
+	convLogs    []conversation.LogFile
+	convCursor  int
+	convLog     bool
+
+	// supervisor restarts claude in crashed sessions when auto_restart is
+	// set; nil otherwise.
+	supervisor *session.Supervisor
+
+	// Detail view (d): recent tool calls of the selected session, and the
+	// feed of file changes in the directories of local sessions, kept
+	// across attaches (see Run).
+	detailTools     []conversation.ToolEvent
+	detailMsgs      []conversation.Message // assistant messages for the forecast
+	detailTimes     timesheet.Totals       // time recorded for the session today
+	detailWindowIdx int                    // span of the CPU / memory graphs (w)
+	files           *filefeed.Feed
+
+	// timesheet records attached and working time of sessions, kept across
+	// attaches (see Run); nil in demo mode.
+	timesheet *timesheet.Sheet
+
+	// Pulse view (P): activity of all sessions from the same history.
+	pulseWindowIdx int
+
+	// Spend of each session, read when a spend cap is set, and the
+	// sessions already announced as over their cap.
+	spend   *spendMeters
+	overCap map[string]bool
+
+	// guard is the state of the resource guard, kept across attaches (see
+	// Run).
+	guard *guardState
+
+	// Git state of session directories, reread at most every gitCacheTTL,
+	// and the sessions whose uncommitted edits overlap.
+	gitCache  *git.Cache
+	conflicts *conflictWatch
+
+	// Fleet header: today's usage and the tmux server, reread at most
+	// every statsInterval; usage is nil before the first read.
+	usage     *usage.Totals
+	usageErr  error
+	server    tmux.ServerInfo
+	serverErr error
+	statsRead time.Time
+
+	// Latest test result of each session, from test_commands run with 't'
+	// or read from its pane when claude stops working, kept across
+	// attaches (see Run).
+	tests *testResults
+
+	// Summary of a bulk operation that partly failed.
+	bulkResult session.BulkResult
+
+	// Preview pane (tab) beside the session table. previewSeq tells the
+	// latest scheduled fetch from those the highlight has since moved past.
+	previewOpen    bool
+	preview        ui.PreviewData
+	previewSeq     int
+	previewFetched time.Time
 
 	// Filter
 	filterQuery string
+	hostFilter  string // host name to show exclusively; empty shows all hosts
 
 	// Attach target (set when user wants to attach, triggers Quit)
 	attachTarget string
+	attachHost   string // remote host of attachTarget; empty for local
+	createHost   string // remote host the create form targets; empty for local
+
+	// Whether, and in which session, the dashboard runs inside tmux.
+	nesting nesting
+
+	// Session to highlight once listed, after returning from an attach.
+	reselect string
 }
 
-// SessionsMsg carries refreshed session list.
+// SessionsMsg carries the refreshed session list of one host.
 type SessionsMsg struct {
+	Host     string // "" for the local machine
 	Sessions []session.Session
 	Err      error
 }
@@ -78,11 +252,16 @@ type SessionsMsg struct {
 // AttachMsg signals to attach to a session.
 type AttachMsg struct {
 	Name string
+	Host string // remote host name; empty for local
 }
 
-// KillMsg signals session was killed.
+// KillMsg signals session was killed, after archiving it or followed by
+// removing its worktree when the kill menu said so.
 type KillMsg struct {
-	Err error
+	Name     string
+	Archived bool
+	Worktree string // the worktree removed
+	Err      error
 }
 
 // CreateMsg signals session was created.
@@ -90,43 +269,145 @@ type CreateMsg struct {
 	Err error
 }
 
-// LogsMsg carries log content.
+// ProjectDirsMsg carries the directories the create form suggests.
+type ProjectDirsMsg struct {
+	Dirs []string
+}
+
+// FadeMsg repaints the log viewer so fresh-line highlights fade out.
+type FadeMsg struct{}
+
+// SendMsg signals a prompt was sent to a session.
+type SendMsg struct {
+	Err error
+}
+
+// LogsMsg carries log content. Messages is set instead of Content for
+// conversation logs.
 type LogsMsg struct {
-	Content string
-	Err     error
+	Content  string
+	Messages []conversation.Message
+	Counts   conversation.Counts
+	Err      error
 }
 
 // New creates a new app model.
 func New() (Model, error) {
+	// Without tmux the dashboard still lists Claude processes running in
+	// terminals; the manager reports tmux-only actions as unavailable.
 	client, err := tmux.NewClient()
 	if err != nil {
-		return Model{}, fmt.Errorf("tmux is required: %w", err)
+		client = nil
 	}
+	return newModel(client, session.NewManager(client)), nil
+}
 
+// newModel creates an app model listing the local sessions of mgr.
+func newModel(client *tmux.Client, mgr *session.Manager) Model {
 	cfg := config.Load()
-	mgr := session.NewManager(client)
+	styles.Apply(styles.ThemeFor(cfg.Theme, cfg.ThemeColors))
+	pricingStale := loadPricing(cfg)
+	applyLocale(cfg)
+	remotes := newRemoteHosts(cfg.Hosts)
+	tracker := shareTracker(mgr, remotes)
+	events, unsubscribe := tracker.Subscribe()
+	stopWebhooks := startWebhooks(cfg.Webhooks, tracker, nil)
+	stopEvents := func() {
+		stopWebhooks()
+		unsubscribe()
+	}
 
 	filterInput := textinput.New()
-	filterInput.Placeholder = "filter..."
-	filterInput.CharLimit = 50
+	filterInput.Placeholder = "filter... (tag:api status:waiting)"
+	filterInput.CharLimit = 100
 	filterInput.Width = 30
 
+	promptInput := textinput.New()
+	promptInput.Placeholder = "prompt to send..."
+	promptInput.CharLimit = 2000
+	promptInput.Width = 60
+
+	tagInput := textinput.New()
+	tagInput.Placeholder = "tags, separated by commas..."
+	tagInput.CharLimit = 200
+	tagInput.Width = 40
+
+	issueInput := textinput.New()
+	issueInput.Placeholder = "issue key, e.g. ENG-123..."
+	issueInput.CharLimit = 40
+	issueInput.Width = 30
+
+	termInput := textinput.New()
+	termInput.Placeholder = "containing..."
+	termInput.CharLimit = 100
+	termInput.Width = 30
+
+	searchInput := textinput.New()
+	searchInput.Placeholder = "search..."
+	searchInput.CharLimit = 100
+	searchInput.Width = 30
+
+	archiveInput := textinput.New()
+	archiveInput.Placeholder = "name, project or conversation text..."
+	archiveInput.CharLimit = 100
+	archiveInput.Width = 40
+
 	m := Model{
-		manager:    mgr,
-		cfg:        cfg,
-		view:       ViewDashboard,
-		filterText: filterInput,
+		client:       client,
+		manager:      mgr,
+		watcher:      startWatcher(cfg, client),
+		remotes:      remotes,
+		events:       events,
+		stopEvents:   stopEvents,
+		cfg:          cfg,
+		pricingStale: pricingStale,
+		view:         ViewDashboard,
+		filterText:   filterInput,
+		promptInput:  promptInput,
+		tagInput:     tagInput,
+		issueInput:   issueInput,
+		termInput:    termInput,
+		searchInput:  searchInput,
+		archiveInput: archiveInput,
+		history:      monitor.NewHistory(monitor.HistorySize),
+		spend:        newSpendMeters(),
+		overCap:      make(map[string]bool),
+		guard:        newGuardState(),
+		archiving:    make(map[string]bool),
+		gitCache:     git.NewCache(gitCacheTTL),
+		conflicts:    newConflictWatch(),
+		tests:        newTestResults(),
+		supervisor:   newSupervisor(cfg),
+		nesting:      detectNesting(client),
+		refreshing:   true, // Init starts the first refresh
+		listing:      make(map[string]bool),
+		hosts:        pendingHosts(remotes),
+		// The pulse view opens on the past hour.
+		pulseWindowIdx: len(ui.MonitorWindows) - 1,
+		touring:        !cfg.TourDone,
 	}
-
-	return m, nil
+	m.views = newViewHistory(m.viewSettings())
+	return m
 }
 
 // Init implements tea.Model.
 func (m Model) Init() tea.Cmd {
-	return tea.Batch(
-		m.refreshSessions,
-		monitor.TickCmd(m.cfg.RefreshInterval),
-	)
+	cmds := []tea.Cmd{
+		m.listLocal,
+		monitor.TickCmd(m.tickInterval()),
+		m.waitForChange(),
+		m.waitForEvent(),
+		m.waitForFiles(),
+	}
+	for _, r := range m.remotes {
+		if m.listing[r.name] {
+			cmds = append(cmds, listHost(r, m.cfg.HasColumn("mode")))
+		}
+	}
+	if m.pricingStale && (m.cfg.ShowCost || m.cfg.SpendCap.Enabled()) {
+		cmds = append(cmds, m.fetchPricing)
+	}
+	return tea.Batch(cmds...)
 }
 
 // Update implements tea.Model.
@@ -141,25 +422,109 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m, nil
 
 	case monitor.TickMsg:
+		m, refresh := m.refreshSessions()
 		return m, tea.Batch(
-			m.refreshSessions,
-			monitor.TickCmd(m.cfg.RefreshInterval),
+			refresh,
+			monitor.TickCmd(m.tickInterval()),
+			m.followLogs(),
+			m.refreshMonitor(),
+			m.refreshDetail(),
+			m.refreshPreview(),
 		)
 
+	case monitor.ChangeMsg:
+		m, refresh := m.refreshSessions()
+		return m, tea.Batch(
+			refresh,
+			m.waitForChange(),
+			m.followLogs(),
+			m.refreshMonitor(),
+			m.refreshDetail(),
+			m.refreshPreview(),
+		)
+
+	case EventMsg:
+		m, cmd := m.handleEvent(msg.Event)
+		return m, tea.Batch(cmd, m.waitForEvent())
+
+	case TestsMsg:
+		return m.showTestResult(msg), nil
+
+	case StatsMsg:
+		return m.showStats(msg), nil
+
+	case PricingMsg:
+		if msg.Err == nil {
+			conversation.SetPrices(msg.Table, overridePrices(m.cfg))
+		}
+		return m, nil
+
+	case PreviewDueMsg:
+		if msg.Seq != m.previewSeq {
+			return m, nil
+		}
+		return m, m.fetchPreview()
+
+	case PreviewMsg:
+		return m.showPreview(msg), nil
+
+	case MonitorMsg:
+		m.monitorMsgs = msg.Messages
+		return m, nil
+
+	case ToolsMsg:
+		if s, ok := m.detailSession(); ok && historyKey(s) == msg.Key {
+			m.detailTools, m.detailMsgs, m.detailTimes = msg.Events, msg.Messages, msg.Times
+		}
+		return m, nil
+
+	case FilesMsg:
+		return m, m.waitForFiles()
+
+	case FadeMsg:
+		m.fadePending = false
+		if m.view == ViewLogs && m.logView.Fade(time.Now()) {
+			m.fadePending = true
+			return m, fadeCmd()
+		}
+		return m, nil
+
 	case SessionsMsg:
-		if msg.Err != nil {
+		if msg.Host == "" {
+			m.refreshing, m.listed = false, true
+			if msg.Err != nil {
+				m.refreshFailures++
+			} else {
+				m.lastRefresh = time.Now()
+				m.refreshFailures = 0
+			}
+		} else {
+			delete(m.listing, msg.Host)
+		}
+		m.setHostStatus(msg)
+		if msg.Err != nil && msg.Host == "" && len(m.remotes) == 0 {
 			m.err = msg.Err
 		} else {
-			m.sessions = msg.Sessions
-			// Build process table once, then aggregate per-session.
-			procTable := monitor.GetProcessTable()
-			for i := range m.sessions {
-				if m.sessions[i].PID != "" {
-					info := monitor.GetChildProcessInfo(m.sessions[i].PID, procTable)
-					m.sessions[i].CPU = info.CPU
-					m.sessions[i].Memory = info.Memory
+			if msg.Host == "" {
+				// Build process table once, then aggregate per-session.
+				procTable := monitor.GetProcessTable()
+				for i := range msg.Sessions {
+					if msg.Sessions[i].PID != "" {
+						info := monitor.GetChildProcessInfo(msg.Sessions[i].PID, procTable)
+						msg.Sessions[i].CPU = info.CPU
+						msg.Sessions[i].Memory = info.Memory
+					}
 				}
 			}
+			m.sessions = m.mergeHost(msg.Host, msg.Sessions)
+			m.recordSamples(time.Now(), msg.Host)
+			if m.timesheet != nil {
+				_ = m.timesheet.Observe(msg.Host, msg.Sessions, time.Now())
+			}
+			if msg.Host == "" {
+				m.syncFiles()
+			}
+			m.applyTests()
 		}
 		if m.cursor >= len(m.sessions) && m.cursor > 0 {
 			m.cursor = len(m.sessions) - 1
@@ -172,14 +537,39 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		if m.scrollOffset < 0 {
 			m.scrollOffset = 0
 		}
-		return m, nil
+		if msg.Err == nil {
+			m.reselectSession()
+		}
+		m, capCmd := m.enforceSpendCaps()
+		m, guardCmd := m.enforceResourceGuard(time.Now())
+		m, archiveCmd := m.archiveIdle(time.Now())
+		m, statsCmd := m.fetchStats(time.Now())
+		m, serverCmd := m.watchServer(msg)
+		var restartCmd tea.Cmd
+		if msg.Err == nil {
+			restartCmd = m.restartCrashed()
+		}
+		return m.followSelection(tea.Batch(capCmd, guardCmd, archiveCmd, statsCmd, serverCmd, restartCmd))
+
+	case ServerProbeMsg:
+		return m.handleServerProbe(msg)
+
+	case GuardMsg:
+		return m.handleGuard(msg), nil
 
 	case KillMsg:
-		if msg.Err != nil {
+		switch {
+		case msg.Err != nil:
+			m.notice = ""
 			m.err = msg.Err
+		case msg.Archived:
+			m.notice = "Archived and killed " + msg.Name
+		case msg.Worktree != "":
+			m.notice = "Killed " + msg.Name + " and removed worktree " + msg.Worktree
+		default:
+			m.notice = "Killed " + msg.Name
 		}
-		m.confirming = false
-		return m, m.refreshSessions
+		return m.refreshSessions()
 
 	case CreateMsg:
 		if msg.Err != nil {
@@ -187,29 +577,176 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m, nil
 		}
 		m.view = ViewDashboard
-		return m, m.refreshSessions
+		return m.refreshSessions()
+
+	case ResumableMsg:
+		if m.view == ViewCreate {
+			m.createForm.SetResumable(msg.Dir, msg.Logs)
+		}
+		return m, nil
+
+	case ProjectDirsMsg:
+		if m.view == ViewCreate && m.createHost == "" {
+			m.createForm.SetDirs(msg.Dirs)
+		}
+		return m, nil
+
+	case SendMsg:
+		if msg.Err != nil {
+			m.err = msg.Err
+		}
+		return m.refreshSessions()
+
+	case TagsMsg:
+		if msg.Err != nil {
+			m.err = msg.Err
+		}
+		return m.refreshSessions()
+
+	case IssueMsg:
+		if msg.Err != nil {
+			m.err = msg.Err
+		}
+		return m.refreshSessions()
+
+	case MissingMsg:
+		if msg.Err != nil {
+			m.err = msg.Err
+			return m, nil
+		}
+		return m.confirmRestore(msg.Missing), nil
+
+	case ArchiveListMsg:
+		return m.showArchive(msg), nil
+
+	case ConversationsMsg:
+		return m.showConversations(msg), nil
+
+	case UnarchiveMsg:
+		if msg.Err != nil {
+			m.err = msg.Err
+			return m, nil
+		}
+		m.notice = "Restored " + msg.Name
+		m.view = ViewDashboard
+		return m.refreshSessions()
+
+	case AnswerMsg:
+		return m.handleAnswer(msg)
+
+	case AdoptMsg:
+		if msg.Err != nil {
+			m.notice = ""
+			m.err = msg.Err
+			return m, nil
+		}
+		m.notice = "Adopted as " + msg.Name + "; enter attaches"
+		return m.refreshSessions()
+
+	case RestartMsg:
+		if msg.Err != nil {
+			m.err = msg.Err
+			return m, nil
+		}
+		m.notice = "Restarted claude in " + msg.Name
+		return m.refreshSessions()
+
+	case BulkMsg:
+		m.confirm = ui.Confirm{}
+		return m.showBulkResult(msg).refreshSessions()
+
+	case ExportMsg:
+		if msg.Err != nil {
+			m.err = msg.Err
+		} else {
+			m.notice = "Conversation exported to " + msg.Path
+		}
+		return m, nil
+
+	case AttachLineMsg:
+		if msg.Err != nil {
+			m.err = fmt.Errorf("copy failed: %w", msg.Err)
+		} else {
+			m.notice = "Copied " + msg.Line
+		}
+		return m, nil
+
+	case PRDraftMsg:
+		if msg.Err != nil {
+			m.notice = ""
+			m.err = fmt.Errorf("drafting a PR description for %s: %w", msg.Name, msg.Err)
+		} else {
+			m.notice = "PR description for " + msg.Name + " copied to the clipboard"
+		}
+		return m, nil
 
 	case LogsMsg:
 		if msg.Err != nil {
 			m.err = msg.Err
 			return m, nil
 		}
+		if msg.Messages != nil || !m.logView.Filter.IsZero() {
+			m.logView.Counts = msg.Counts
+			if m.logView.Ready && m.logView.IsConversation() {
+				m.logView.RefreshMessages(msg.Messages, time.Now())
+				return m.fadeIfFresh()
+			}
+			m.logView.CollapseLines = m.cfg.CollapseLines
+			m.logView.SetMessages(msg.Messages, conversation.FormatOptions{ShowCost: m.cfg.ShowCost})
+			return m, nil
+		}
+		if m.logView.Ready && !m.logView.IsConversation() {
+			m.logView.RefreshContent(msg.Content, time.Now())
+			return m.fadeIfFresh()
+		}
 		m.logView.SetContent(msg.Content)
 		return m, nil
 
+	case OlderMsg:
+		return m.handleOlder(msg)
+
 	case AttachMsg:
+		if m.demo != nil {
+			m.err = session.ErrReadOnly
+			return m, nil
+		}
 		if !validSessionName.MatchString(msg.Name) {
 			m.err = fmt.Errorf("invalid session name: %s", msg.Name)
 			return m, nil
 		}
 		// Set attach target and quit Bubble Tea.
 		// Run() loop will drain stdin, then run tmux attach, then restart.
+		// Names are only unique per tmux server, so make sure the session
+		// still exists on the host it was picked from before attaching.
+		if !m.hasSession(msg.Host, msg.Name) {
+			m.err = fmt.Errorf("session %s not found on %s", msg.Name, hostLabel(msg.Host))
+			return m, nil
+		}
+		if msg.Host == "" && m.nesting.inside {
+			if err := m.nesting.attachError(session.Session{Name: msg.Name}); err != nil {
+				m.err = err
+				return m, nil
+			}
+			return m, m.switchClient(msg.Name)
+		}
 		m.attachTarget = msg.Name
+		m.attachHost = msg.Host
 		return m, tea.Quit
 
+	case SwitchMsg:
+		if msg.Err != nil {
+			m.err = fmt.Errorf("switching to %s: %w", msg.Name, msg.Err)
+		} else {
+			m.notice = fmt.Sprintf("Switched to %s (prefix+L returns here)", msg.Name)
+		}
+		return m, nil
+
 	case tea.KeyMsg:
 		m.err = nil // Clear error on any key press
-		return m.handleKey(msg)
+		m.notice = ""
+		next, cmd := m.handleKey(msg)
+		next.(Model).recordView()
+		return next.(Model).followSelection(cmd)
 	}
 
 	// Update sub-components
@@ -222,8 +759,13 @@ func (m Model) handleKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		return m, tea.Quit
 	}
 
+	// Onboarding tour
+	if m.touring && m.view == ViewDashboard {
+		return m.handleTourKey(msg)
+	}
+
 	// Confirm mode
-	if m.confirming {
+	if m.confirm.Open() {
 		return m.handleConfirmKey(msg)
 	}
 
@@ -232,6 +774,36 @@ func (m Model) handleKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		return m.handleFilterKey(msg)
 	}
 
+	// Prompt mode
+	if m.prompting {
+		return m.handlePromptKey(msg)
+	}
+
+	// Tag input
+	if m.tagging {
+		return m.handleTagKey(msg)
+	}
+
+	// Issue input
+	if m.issueEditing {
+		return m.handleIssueKey(msg)
+	}
+
+	// Conversation term filter input
+	if m.termEditing {
+		return m.handleTermKey(msg)
+	}
+
+	// Log search input
+	if m.searching {
+		return m.handleSearchKey(msg)
+	}
+
+	// Archive search input
+	if m.archiveSearch {
+		return m.handleArchiveSearchKey(msg)
+	}
+
 	// View-specific
 	switch m.view {
 	case ViewDashboard:
@@ -244,6 +816,16 @@ func (m Model) handleKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		return m.handleCreateKey(msg)
 	case ViewHelp:
 		return m.handleHelpKey(msg)
+	case ViewMonitor:
+		return m.handleMonitorKey(msg)
+	case ViewPulse:
+		return m.handlePulseKey(msg)
+	case ViewBulk:
+		return m.handleBulkKey(msg)
+	case ViewArchive:
+		return m.handleArchiveKey(msg)
+	case ViewConversations:
+		return m.handleConversationsKey(msg)
 	}
 
 	return m, nil
@@ -273,12 +855,20 @@ func (m Model) handleDashboardKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		sessions := m.filteredSessions()
 		if len(sessions) > 0 && m.cursor < len(sessions) {
 			if !sessions[m.cursor].Managed {
-				m.err = fmt.Errorf("terminal sessions cannot be attached (not a tmux session)")
+				m.err = fmt.Errorf("terminal sessions cannot be attached (not a tmux session); a adopts one into tmux")
 				return m, nil
 			}
-			return m, m.attachSession(sessions[m.cursor].Name)
+			return m, m.attachSession(sessions[m.cursor])
 		}
 	case "n":
+		if m.demo != nil {
+			m.err = session.ErrReadOnly
+			return m, nil
+		}
+		if m.client == nil && m.hostFilter == "" {
+			m.err = session.ErrNoTmux
+			return m, nil
+		}
 		m.view = ViewCreate
 		defaultDir := m.cfg.DefaultDir
 		if defaultDir == "" {
@@ -288,50 +878,175 @@ func (m Model) handleDashboardKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 				defaultDir, _ = os.UserHomeDir()
 			}
 		}
+		// Create on the host being filtered to; local otherwise.
+		m.createHost = ""
+		if m.hostFilter != "" && m.hostFilter != session.LocalHost {
+			m.createHost = m.hostFilter
+			defaultDir = m.cfg.DefaultDir
+		}
 		m.createForm = ui.NewCreateForm(defaultDir)
-		return m, m.createForm.NameInput.Focus()
+		m.createForm.Host = m.createHost
+		m.createForm.Models = m.cfg.Models
+		if p := namingPolicy(m.cfg); p != nil {
+			m.createForm.Naming = p.String()
+		}
+		if m.createHost != "" {
+			return m, m.createForm.NameInput.Focus()
+		}
+		return m, tea.Batch(m.createForm.NameInput.Focus(), m.discoverProjectDirs())
 	case "K":
 		sessions := m.filteredSessions()
 		if len(sessions) > 0 && m.cursor < len(sessions) {
-			if !sessions[m.cursor].Managed {
-				m.err = fmt.Errorf("terminal sessions cannot be killed from dashboard")
+			if err := m.killError(sessions[m.cursor]); err != nil {
+				m.err = err
 				return m, nil
 			}
-			m.confirming = true
-			m.confirmMsg = fmt.Sprintf("Kill session '%s'? (y/n)", sessions[m.cursor].Name)
+			return m.confirmKill(sessions[m.cursor]), nil
+		}
+	case "a":
+		sessions := m.filteredSessions()
+		if m.cursor < len(sessions) {
+			return m.confirmAdopt(sessions[m.cursor]), nil
 		}
 	case "ctrl+k":
 		// Kill all idle sessions
 		idleSessions := m.getIdleSessions()
 		if len(idleSessions) == 0 {
-			m.err = fmt.Errorf("no idle sessions to kill")
+			m.err = fmt.Errorf("no idle or exited sessions to kill")
 			return m, nil
 		}
-		m.confirming = true
+		m.confirm = ui.NewYesNo(fmt.Sprintf("Kill %d idle or exited session(s)?", len(idleSessions)))
 		m.killingIdle = true
-		m.confirmMsg = fmt.Sprintf("Kill %d idle session(s)? (y/n)", len(idleSessions))
+	case "ctrl+r":
+		if s, ok := m.detailSession(); ok {
+			if !s.Exited() {
+				m.err = fmt.Errorf("claude is still running in %s", s.Name)
+				return m, nil
+			}
+			return m, m.restartSession(s)
+		}
 	case "l":
 		sessions := m.filteredSessions()
 		if len(sessions) > 0 && m.cursor < len(sessions) {
 			s := sessions[m.cursor]
 			m.view = ViewLogs
 			m.logView = ui.NewLogView(s.Name, m.width, m.height)
+			m.logSession = s
+			m.logIsConv = !s.Managed
 			if s.Managed {
-				return m, m.fetchLogs(s.Name)
+				return m, m.fetchLogs(s)
 			}
-			return m, m.fetchConversation(s.Path)
+			return m, m.fetchConversation(s, conversation.Filter{})
 		}
-	case "d":
+	case "C":
+		sessions := m.filteredSessions()
+		if len(sessions) > 0 && m.cursor < len(sessions) {
+			return m, m.listConversations(sessions[m.cursor])
+		}
+	case "D":
 		sessions := m.filteredSessions()
 		if len(sessions) > 0 && m.cursor < len(sessions) {
+			s := sessions[m.cursor]
+			m.notice = "Drafting a PR description for " + s.Name + " with claude -p..."
+			return m, m.draftPRToClipboard(s)
+		}
+	case "d":
+		if s, ok := m.detailSession(); ok {
 			m.view = ViewDetail
+			m.detailTools, m.detailMsgs, m.detailTimes = nil, nil, timesheet.Totals{}
+			return m, m.fetchTools(s)
+		}
+	case "m":
+		sessions := m.filteredSessions()
+		if len(sessions) > 0 && m.cursor < len(sessions) {
+			m.view = ViewMonitor
+			m.monitorTarget = sessions[m.cursor]
+			m.monitorMsgs = nil
+			return m, m.fetchMonitorMessages()
+		}
+	case "P":
+		m.view = ViewPulse
+	case "A":
+		m.archiveQuery = ""
+		return m, m.listArchive
+	case "v":
+		return m.nextDensity()
+	case "R":
+		if m.demo != nil {
+			m.err = session.ErrReadOnly
+			return m, nil
+		}
+		if m.client == nil {
+			m.err = session.ErrNoTmux
+			return m, nil
+		}
+		return m, m.findMissing()
+	case "S":
+		return m.chooseProfile(), nil
+	case "z":
+		sessions := m.filteredSessions()
+		if m.cursor < len(sessions) {
+			return m.resumeSession(sessions[m.cursor])
+		}
+	case "c":
+		sessions := m.filteredSessions()
+		if m.cursor < len(sessions) {
+			return m.copyAttachLine(sessions[m.cursor])
+		}
+	case "y", "N":
+		sessions := m.filteredSessions()
+		if m.cursor < len(sessions) {
+			return m.confirmAnswer(sessions[m.cursor], msg.String() == "y"), nil
+		}
+	case "p":
+		sessions := m.filteredSessions()
+		if len(sessions) > 0 && m.cursor < len(sessions) {
+			if !sessions[m.cursor].Managed {
+				m.err = fmt.Errorf("terminal sessions cannot receive prompts (not a tmux session)")
+				return m, nil
+			}
+			m.prompting = true
+			m.promptTarget = sessions[m.cursor]
+			m.promptInput.SetValue("")
+			return m, m.promptInput.Focus()
+		}
+	case "#":
+		sessions := m.filteredSessions()
+		if len(sessions) > 0 && m.cursor < len(sessions) {
+			return m.startTagging(sessions[m.cursor])
+		}
+	case "I":
+		sessions := m.filteredSessions()
+		if len(sessions) > 0 && m.cursor < len(sessions) {
+			return m.startIssue(sessions[m.cursor])
+		}
+	case "o":
+		sessions := m.filteredSessions()
+		if len(sessions) > 0 && m.cursor < len(sessions) {
+			return m.openIssue(sessions[m.cursor])
 		}
 	case "/":
 		m.filtering = true
 		m.filterText.SetValue(m.filterQuery)
 		return m, m.filterText.Focus()
 	case "r":
-		return m, m.refreshSessions
+		return m.refreshSessions()
+	case "H":
+		m.hostFilter = m.nextHostFilter()
+		m.cursor = 0
+		m.scrollOffset = 0
+	case "0", "1", "2", "3", "4", "5", "6", "7", "8", "9":
+		return m.switchQuickView(msg.String())
+	case "u":
+		return m.stepView(true)
+	case "U":
+		return m.stepView(false)
+	case "t":
+		if s, ok := m.detailSession(); ok {
+			return m.runTests(s)
+		}
+	case "tab":
+		return m.togglePreview()
 	case "?":
 		m.view = ViewHelp
 	}
@@ -339,16 +1054,97 @@ func (m Model) handleDashboardKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 }
 
 func (m Model) handleLogsKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	// Message navigation only makes sense for conversation logs; in pane logs
+	// these keys keep their viewport meaning (u = half page up).
+	if m.logView.IsConversation() {
+		switch msg.String() {
+		case "]":
+			m.logView.NextMessage()
+			return m, nil
+		case "[":
+			m.logView.PrevMessage()
+			return m, m.fetchOlder()
+		case "u":
+			m.logView.PrevRole("user")
+			return m, m.fetchOlder()
+		case "a":
+			m.logView.PrevRole("assistant")
+			return m, m.fetchOlder()
+		case "r":
+			m.logView.ToggleRaw()
+			return m, nil
+		case "e":
+			return m, m.exportConversation(m.logSession, m.logFile)
+		case "R":
+			f := m.logView.Filter
+			f.Role = nextRoleFilter(f.Role)
+			return m.applyLogFilter(f)
+		case "T":
+			f := m.logView.Filter
+			if f.Since.IsZero() {
+				f.Since = conversation.StartOfDay(time.Now())
+			} else {
+				f.Since = time.Time{}
+			}
+			return m.applyLogFilter(f)
+		case "/":
+			m.termEditing = true
+			m.termInput.SetValue(m.logView.Filter.Term)
+			return m, m.termInput.Focus()
+		case "backspace":
+			if m.logView.Filter.IsZero() {
+				return m, nil
+			}
+			return m.applyLogFilter(conversation.Filter{})
+		}
+	}
+
 	switch msg.String() {
 	case "esc":
 		m.view = ViewDashboard
+		if m.archiveLog {
+			m.archiveLog = false
+			m.logFile = ""
+			m.view = ViewArchive
+		}
+		if m.convLog {
+			m.convLog = false
+			m.logFile = ""
+			m.view = ViewConversations
+		}
 		return m, nil
 	case "q":
 		return m, tea.Quit
+	case "F":
+		m.logView.Follow = !m.logView.Follow
+		return m, nil
+	case "/":
+		m.searching = true
+		m.searchInput.SetValue("")
+		return m, m.searchInput.Focus()
+	case "n":
+		m.logView.NextMatch()
+		return m, nil
+	case "N":
+		m.logView.PrevMatch()
+		return m, nil
+	case "enter":
+		m.logView.ToggleExpand()
+		return m, nil
+	case "E":
+		m.logView.ToggleExpandAll()
+		return m, nil
+	case "y":
+		if text, ok := m.logView.CurrentMessageText(); ok {
+			if err := copyToClipboard(text); err != nil {
+				m.err = fmt.Errorf("copy failed: %w", err)
+			}
+		}
+		return m, nil
 	default:
 		var cmd tea.Cmd
 		m.logView.Viewport, cmd = m.logView.Viewport.Update(msg)
-		return m, cmd
+		return m, tea.Batch(cmd, m.fetchOlder())
 	}
 }
 
@@ -361,21 +1157,29 @@ func (m Model) handleDetailKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	case "l":
 		sessions := m.filteredSessions()
 		if m.cursor < len(sessions) {
-			m.view = ViewLogs
 			s := sessions[m.cursor]
+			m.view = ViewLogs
 			m.logView = ui.NewLogView(s.Name, m.width, m.height)
-			return m, m.fetchLogs(s.Name)
+			m.logSession = s
+			m.logIsConv = false
+			return m, m.fetchLogs(s)
 		}
 	case "K":
 		sessions := m.filteredSessions()
 		if m.cursor < len(sessions) {
-			if !sessions[m.cursor].Managed {
-				m.err = fmt.Errorf("terminal sessions cannot be killed from dashboard")
+			if err := m.killError(sessions[m.cursor]); err != nil {
+				m.err = err
 				return m, nil
 			}
-			m.confirming = true
-			m.confirmMsg = fmt.Sprintf("Kill session '%s'? (y/n)", sessions[m.cursor].Name)
+			return m.confirmKill(sessions[m.cursor]), nil
 		}
+	case "o":
+		sessions := m.filteredSessions()
+		if m.cursor < len(sessions) {
+			return m.openIssue(sessions[m.cursor])
+		}
+	case "w":
+		m.detailWindowIdx = (m.detailWindowIdx + 1) % len(ui.DetailWindows)
 	}
 	return m, nil
 }
@@ -387,22 +1191,71 @@ func (m Model) handleCreateKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		return m, nil
 	case "tab":
 		m.createForm.FocusNext()
+		if _, dir := m.createForm.Values(); m.createForm.ResumeFocused() && dir != m.createForm.ResumeDir {
+			return m, listResumable(dir)
+		}
 		return m, nil
+	case "left", "right", "h", "l":
+		if m.createForm.ModelFocused() {
+			delta := 1
+			if k := msg.String(); k == "left" || k == "h" {
+				delta = -1
+			}
+			m.createForm.CycleModel(delta)
+			return m, nil
+		}
+	case "up", "ctrl+p", "down", "ctrl+n":
+		if m.createForm.ModelFocused() {
+			delta := 1
+			if k := msg.String(); k == "up" || k == "ctrl+p" {
+				delta = -1
+			}
+			m.createForm.CycleModel(delta)
+			return m, nil
+		}
+		if m.createForm.ResumeFocused() {
+			delta := 1
+			if k := msg.String(); k == "up" || k == "ctrl+p" {
+				delta = -1
+			}
+			m.createForm.MoveResume(delta)
+			return m, nil
+		}
+		if m.createForm.FocusIdx == 1 {
+			delta := 1
+			if k := msg.String(); k == "up" || k == "ctrl+p" {
+				delta = -1
+			}
+			m.createForm.MoveSelection(delta)
+			return m, nil
+		}
 	case "enter":
+		if m.createForm.FocusIdx == 1 && m.createForm.AcceptSuggestion() {
+			return m, nil
+		}
 		if err := m.createForm.Validate(); err != nil {
 			m.createForm.Err = err.Error()
 			return m, nil
 		}
 		name, dir := m.createForm.Values()
-		return m, m.createSession(name, dir)
+		if err := checkName(namingPolicy(m.cfg), name, dir); err != nil {
+			m.createForm.Err = err.Error()
+			return m, nil
+		}
+		return m, m.createSession(name, dir, m.createForm.ClaudeArgs())
 	}
 
 	// Update the focused input
 	var cmd tea.Cmd
-	if m.createForm.FocusIdx == 0 {
+	switch m.createForm.FocusIdx {
+	case 0:
 		m.createForm.NameInput, cmd = m.createForm.NameInput.Update(msg)
-	} else {
+	case 1:
+		before := m.createForm.DirInput.Value()
 		m.createForm.DirInput, cmd = m.createForm.DirInput.Update(msg)
+		if m.createForm.DirInput.Value() != before {
+			m.createForm.DirEdited()
+		}
 	}
 	return m, cmd
 }
@@ -416,25 +1269,30 @@ func (m Model) handleHelpKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 }
 
 func (m Model) handleConfirmKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
-	switch msg.String() {
-	case "y", "Y":
-		if m.killingIdle {
-			// Kill all idle sessions
-			m.confirming = false
-			m.killingIdle = false
-			return m, m.killIdleSessions()
-		}
-		// Kill single session
-		sessions := m.filteredSessions()
-		if m.cursor < len(sessions) {
-			return m, m.killSession(sessions[m.cursor].Name)
-		}
-		m.confirming = false
-	case "n", "N", "esc":
-		m.confirming = false
-		m.killingIdle = false
+	picked, done := m.confirm.HandleKey(msg.String())
+	if !done {
+		return m, nil
 	}
-	return m, nil
+	m.confirm = ui.Confirm{}
+	killingIdle, restoring, adopting, answering, profiles := m.killingIdle, m.restoring, m.adopting, m.answering, m.profiles
+	m.killingIdle, m.restoring, m.adopting, m.answering, m.profiles = false, false, false, false, nil
+	switch {
+	case picked == "":
+		return m, nil
+	case profiles != nil:
+		m.profiles = profiles
+		return m.switchProfile(picked)
+	case killingIdle:
+		return m, m.killIdleSessions()
+	case restoring:
+		return m, m.restoreSessions()
+	case adopting:
+		m.notice = "Stopping claude in " + m.adoptTarget.Name + "..."
+		return m, m.adoptSession(m.adoptTarget, m.adoptName)
+	case answering:
+		return m, m.answerPrompt(m.answerTarget, m.answerApprove)
+	}
+	return m.killWith(m.killTarget, picked)
 }
 
 func (m Model) handleFilterKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
@@ -457,11 +1315,117 @@ func (m Model) handleFilterKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	return m, cmd
 }
 
+func (m Model) handlePromptKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "enter":
+		text := m.promptInput.Value()
+		m.prompting = false
+		m.promptInput.Blur()
+		return m, m.sendPrompt(m.promptTarget, text)
+	case "esc":
+		m.prompting = false
+		m.promptInput.Blur()
+		return m, nil
+	}
+
+	var cmd tea.Cmd
+	m.promptInput, cmd = m.promptInput.Update(msg)
+	return m, cmd
+}
+
+func (m Model) handleTermKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "enter", "esc":
+		f := m.logView.Filter
+		f.Term = strings.TrimSpace(m.termInput.Value())
+		if msg.String() == "esc" {
+			f.Term = ""
+		}
+		m.termEditing = false
+		m.termInput.Blur()
+		return m.applyLogFilter(f)
+	}
+
+	var cmd tea.Cmd
+	m.termInput, cmd = m.termInput.Update(msg)
+	return m, cmd
+}
+
+func (m Model) handleSearchKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "enter", "esc":
+		term := strings.TrimSpace(m.searchInput.Value())
+		if msg.String() == "esc" {
+			term = ""
+		}
+		m.searching = false
+		m.searchInput.Blur()
+		m.logView.Search(term)
+		return m, nil
+	}
+
+	var cmd tea.Cmd
+	m.searchInput, cmd = m.searchInput.Update(msg)
+	return m, cmd
+}
+
+// followLogs refetches the open log in follow mode.
+func (m Model) followLogs() tea.Cmd {
+	if m.view != ViewLogs || m.archiveLog || !m.logView.Follow || !m.logView.Ready {
+		return nil
+	}
+	if m.logFile != "" {
+		return fetchLogFile(m.logFile, m.logView.Filter)
+	}
+	if m.logIsConv {
+		return m.fetchConversation(m.logSession, m.logView.Filter)
+	}
+	return m.fetchLogs(m.logSession)
+}
+
+// fadeIfFresh schedules the fade of a fresh-line highlight, unless a fade
+// is pending already: that one fades the new lines along, so streaming
+// output does not pile up ticks.
+func (m Model) fadeIfFresh() (Model, tea.Cmd) {
+	if !m.logView.HasFresh() || m.fadePending {
+		return m, nil
+	}
+	m.fadePending = true
+	return m, fadeCmd()
+}
+
+// fadeCmd fires a FadeMsg at each highlight stage.
+func fadeCmd() tea.Cmd {
+	return tea.Tick(ui.FreshDuration/2, func(time.Time) tea.Msg { return FadeMsg{} })
+}
+
+// applyLogFilter sets the conversation viewer filter and rereads the log.
+func (m Model) applyLogFilter(f conversation.Filter) (tea.Model, tea.Cmd) {
+	m.logView.Filter = f
+	m.logView.Ready = false // a new result set, not a refresh to diff against
+	if m.logFile != "" {
+		return m, fetchLogFile(m.logFile, f)
+	}
+	return m, m.fetchConversation(m.logSession, f)
+}
+
+// nextRoleFilter cycles the conversation role filter: all, user, assistant.
+func nextRoleFilter(role string) string {
+	switch role {
+	case "":
+		return "user"
+	case "user":
+		return "assistant"
+	default:
+		return ""
+	}
+}
+
 func (m Model) updateSubComponents(msg tea.Msg) (tea.Model, tea.Cmd) {
 	if m.view == ViewLogs {
 		var cmd tea.Cmd
 		m.logView.Viewport, cmd = m.logView.Viewport.Update(msg)
-		return m, cmd
+		return m, tea.Batch(cmd, m.fetchOlder())
 	}
 	return m, nil
 }
@@ -471,6 +1435,9 @@ func (m Model) View() string {
 	if m.width == 0 {
 		return "Loading..."
 	}
+	if ui.TooSmall(m.width, m.height) {
+		return ui.RenderTooSmall(m.width, m.height)
+	}
 
 	sessions := m.filteredSessions()
 	var b strings.Builder
@@ -478,20 +1445,79 @@ func (m Model) View() string {
 	// Title bar
 	title := styles.Title.Render(" claude-dashboard ")
 	ver := lipgloss.NewStyle().Foreground(styles.ColorMuted).Render(Version)
-	b.WriteString(title + " " + ver + "\n")
+	b.WriteString(title + " " + ver)
+	if len(m.hosts) > 0 {
+		b.WriteString("  " + ui.HostRollup(m.hosts, m.hostFilter))
+	}
+	if p := config.Profile(); p != config.DefaultProfile {
+		b.WriteString("  " + styles.Muted.Render("profile: "+p))
+	}
+	switch {
+	case m.demo != nil:
+		b.WriteString("  " + styles.Waiting.Render("demo: made-up sessions"))
+	case m.client == nil:
+		b.WriteString("  " + styles.Muted.Render("terminal sessions only (tmux not found)"))
+	}
+	if room := m.width - lipgloss.Width(b.String()) - 2; room > 0 && m.nesting.inside {
+		b.WriteString("  " + styles.Waiting.Render(ansi.Truncate(m.nesting.banner(), room, "…")))
+	}
+	if n := m.overCapCount(); n > 0 {
+		b.WriteString("  " + styles.Error.Render(fmt.Sprintf("⚠ %d over spend cap", n)))
+	}
+	if n := m.guard.count(); n > 0 {
+		b.WriteString("  " + styles.Waiting.Render(fmt.Sprintf("⏸ %d paused by resource guard (z resumes)", n)))
+	}
+	if n := m.conflictCount(); n > 0 {
+		b.WriteString("  " + styles.Waiting.Render(fmt.Sprintf("⚠ %d editing the same files", n)))
+	}
+	b.WriteString("\n")
 
 	// Error
-	if m.err != nil {
+	if banner := m.recovery.banner(time.Now()); banner != "" {
+		b.WriteString(styles.Waiting.Render("  " + banner))
+		b.WriteString("\n")
+	} else if m.err != nil {
 		b.WriteString(styles.Error.Render(fmt.Sprintf("  Error: %v", m.err)))
 		b.WriteString("\n")
+	} else if m.notice != "" {
+		b.WriteString(styles.Active.Render("  " + m.notice))
+		b.WriteString("\n")
 	}
 
 	// Main content
 	contentHeight := m.height - 4 // title + status + help
+	tableTop := 0
 	switch m.view {
 	case ViewDashboard:
+		if m.showHeader() {
+			b.WriteString(ui.RenderFleetHeader(m.fleetStats(), time.Now(), m.width))
+			contentHeight -= ui.FleetHeaderRows
+		}
+		tableTop = strings.Count(b.String(), "\n")
 		visibleRows := m.visibleSessionRows()
-		content := ui.RenderDashboard(sessions, m.cursor, m.width, m.scrollOffset, visibleRows)
+		tableWidth, previewWidth := m.width, 0
+		if m.previewOpen {
+			tableWidth, previewWidth = ui.SplitWidth(m.width, previewRatio)
+		}
+		content := ui.RenderDashboard(sessions, m.cursor, tableWidth, m.scrollOffset, visibleRows, ui.DashboardOptions{
+			ShowHost:   len(m.remotes) > 0,
+			ShowBranch: m.cfg.ShowBranch,
+			ShowIssues: !m.cfg.Issues.IsZero(),
+			ShowTests:  m.showTests(),
+			PathStyle:  m.cfg.PathStyle,
+			Icons:      session.Icons(m.cfg.StatusIcons),
+			Density:    m.cfg.Density,
+			Query:      m.filterQuery,
+			Columns:    m.cfg.Columns,
+			Loading:    !m.listed,
+		})
+		if previewWidth > 0 {
+			b.WriteString(ui.JoinPanes(contentHeight,
+				ui.Pane{Content: content, Width: tableWidth},
+				ui.Pane{Content: ui.RenderPreview(m.preview, previewWidth, contentHeight), Width: previewWidth},
+			))
+			break
+		}
 		b.WriteString(content)
 		lines := strings.Count(content, "\n")
 		for i := lines; i < contentHeight; i++ {
@@ -502,18 +1528,32 @@ func (m Model) View() string {
 	case ViewDetail:
 		if m.cursor < len(sessions) {
 			s := sessions[m.cursor]
-			b.WriteString(ui.RenderDetail(&s, m.width))
+			now := time.Now()
+			b.WriteString(ui.RenderDetail(&s, session.Icons(m.cfg.StatusIcons), m.detailTools, m.fileChanges(s), m.detailForecast(s, now), m.detailTimes, m.detailSamples(s, now), m.detailWindow(), now, m.width, contentHeight))
 		}
 	case ViewCreate:
 		b.WriteString(ui.RenderCreateForm(m.createForm, m.width))
 	case ViewHelp:
 		b.WriteString(ui.RenderHelp(m.width))
+	case ViewMonitor:
+		b.WriteString(ui.RenderMonitor(m.monitorData(), m.width, contentHeight))
+	case ViewPulse:
+		b.WriteString(ui.RenderPulse(m.pulseData(), m.width, contentHeight))
+	case ViewBulk:
+		b.WriteString(ui.RenderBulkResult(m.bulkResult, m.width, contentHeight))
+	case ViewArchive:
+		b.WriteString(ui.RenderArchive(m.archived, m.archiveCursor, m.archiveQuery, m.width, contentHeight))
+	case ViewConversations:
+		b.WriteString(ui.RenderConversations(m.convSession.DisplayName(), m.convLogs, m.convCursor, m.width, contentHeight))
 	}
 
 	// Confirm overlay
-	if m.confirming {
+	if m.confirm.Open() {
+		b.WriteString("\n")
+		b.WriteString(ui.RenderConfirm(m.confirm, m.width))
+	} else if hint := m.exitHint(); hint != "" && m.view == ViewDashboard {
 		b.WriteString("\n")
-		b.WriteString(styles.Confirm.Render("  " + m.confirmMsg))
+		b.WriteString(styles.Muted.Render("  " + hint))
 	}
 
 	// Filter bar
@@ -522,13 +1562,57 @@ func (m Model) View() string {
 		b.WriteString(fmt.Sprintf("  / %s", m.filterText.View()))
 	}
 
+	// Conversation term filter bar
+	if m.termEditing {
+		b.WriteString("\n")
+		b.WriteString(fmt.Sprintf("  containing: %s", m.termInput.View()))
+	}
+
+	// Log search bar
+	if m.searching {
+		b.WriteString("\n")
+		b.WriteString(fmt.Sprintf("  search: %s", m.searchInput.View()))
+	}
+
+	// Archive search bar
+	if m.archiveSearch {
+		b.WriteString("\n")
+		b.WriteString(fmt.Sprintf("  search archive: %s", m.archiveInput.View()))
+	}
+
+	// Prompt bar
+	if m.prompting {
+		b.WriteString("\n")
+		b.WriteString(fmt.Sprintf("  %s %s", styles.StatusKey.Render(m.promptTarget.DisplayName()+" ›"), m.promptInput.View()))
+	}
+
+	// Tag bar
+	if m.tagging {
+		b.WriteString("\n")
+		b.WriteString(fmt.Sprintf("  %s %s", styles.StatusKey.Render(m.tagTarget.DisplayName()+" tags:"), m.tagInput.View()))
+	}
+
+	// Issue bar
+	if m.issueEditing {
+		b.WriteString("\n")
+		b.WriteString(fmt.Sprintf("  %s %s", styles.StatusKey.Render(m.issueTarget.DisplayName()+" issue:"), m.issueInput.View()))
+	}
+
 	// Status bar
 	viewName := m.viewName()
 	b.WriteString("\n")
-	b.WriteString(ui.StatusBar(m.width, len(sessions), viewName, m.filterQuery))
+	refresh := ui.RefreshState{Running: m.refreshing, Last: m.lastRefresh, Failures: m.refreshFailures}
+	var quick ui.QuickViews
+	if m.view == ViewDashboard {
+		quick = m.quickViewState()
+	}
+	b.WriteString(ui.StatusBar(m.width, len(sessions), viewName, m.filterQuery, m.hostFilter, refresh, quick))
 	b.WriteString("\n")
 	b.WriteString(ui.HelpBar(m.width, viewName))
 
+	if m.touring && m.view == ViewDashboard {
+		return m.drawTour(b.String(), tableTop)
+	}
 	return b.String()
 }
 
@@ -544,90 +1628,216 @@ func (m Model) viewName() string {
 		return "create"
 	case ViewHelp:
 		return "help"
+	case ViewMonitor:
+		return "monitor"
+	case ViewPulse:
+		return "pulse"
+	case ViewBulk:
+		return "summary"
+	case ViewArchive:
+		return "archive"
+	case ViewConversations:
+		return "conversations"
 	default:
 		return "dashboard"
 	}
 }
 
 func (m Model) filteredSessions() []session.Session {
-	return session.FilterSessions(m.sessions, m.filterQuery)
+	return session.FilterSessions(session.FilterByHost(m.sessions, m.hostFilter), m.filterQuery)
 }
 
-// visibleSessionRows returns how many session rows fit in the content area.
-// Subtracts: title(1) + error(1) + header(1) + status(1) + help(1) + padding(1) = 6
+// visibleSessionRows returns how many sessions fit in the content area.
+// Subtracts: title(1) + error(1) + header(1) + status(1) + help(1) + padding(1) = 6,
+// and the fleet header when it is shown.
 func (m Model) visibleSessionRows() int {
-	rows := m.height - 6
+	height := m.height - 6
+	if m.showHeader() {
+		height -= ui.FleetHeaderRows
+	}
+	rows := height / ui.RowHeight(m.cfg.Density)
 	if rows < 1 {
 		rows = 1
 	}
 	return rows
 }
 
+// nextDensity cycles the row density and saves it in the config.
+func (m Model) nextDensity() (tea.Model, tea.Cmd) {
+	next := config.Densities[0]
+	for i, d := range config.Densities {
+		if d == m.cfg.Density {
+			next = config.Densities[(i+1)%len(config.Densities)]
+		}
+	}
+	return m.setDensity(next)
+}
+
+// setDensity draws rows with density, saving it to the config; only the
+// density setting is written, and not to a config that does not parse.
+func (m Model) setDensity(density string) (tea.Model, tea.Cmd) {
+	m.cfg.Density = density
+	m.scrollOffset = 0
+	if m.cursor >= m.visibleSessionRows() {
+		m.scrollOffset = m.cursor - m.visibleSessionRows() + 1
+	}
+	if err := config.Set("density", density); err != nil {
+		m.err = fmt.Errorf("density not saved: %w", err)
+	}
+	if density == config.DensityDetailed {
+		return m.refreshSessions()
+	}
+	return m, nil
+}
+
 // Commands
 
-func (m Model) refreshSessions() tea.Msg {
+// listLocal lists the sessions of this machine.
+func (m Model) listLocal() tea.Msg {
 	sessions, err := m.manager.List(context.Background())
+	if m.cfg.Density == config.DensityDetailed {
+		for i := range sessions {
+			if sessions[i].Path == "" {
+				continue
+			}
+			if log, err := sessions[i].Log(); err == nil {
+				sessions[i].LastPrompt, _ = conversation.LastPromptIn(log)
+			}
+		}
+	}
+	if m.cfg.SpendCap.Enabled() || m.cfg.HasColumn("tokens") || m.cfg.HasColumn("context") {
+		for i := range sessions {
+			sessions[i].Spend, sessions[i].Context = m.spend.read(sessions[i])
+		}
+	}
+	if m.cfg.HasColumn("activity") {
+		for i := range sessions {
+			sessions[i].LogSize = logSize(sessions[i])
+		}
+	}
+	if m.cfg.HasColumn("mode") {
+		for i := range sessions {
+			m.manager.ReadMode(context.Background(), &sessions[i])
+		}
+	}
+	if m.demo != nil {
+		// Made-up sessions come with their git state and conflicts.
+		return SessionsMsg{Sessions: sessions, Err: err}
+	}
+	for i := range sessions {
+		if sessions[i].Path != "" {
+			sessions[i].Git, _ = m.gitCache.Get(context.Background(), sessions[i].Path)
+		}
+		if sessions[i].Issue == "" {
+			sessions[i].Issue = session.FindIssue(sessions[i].Git.Branch, m.cfg.Issues.Projects)
+		}
+	}
+	conflicts := m.conflicts.find(sessions, time.Now())
+	for i := range sessions {
+		sessions[i].Conflict = conflicts[sessions[i].Name]
+	}
 	return SessionsMsg{Sessions: sessions, Err: err}
 }
 
-func (m Model) attachSession(name string) tea.Cmd {
+func (m Model) attachSession(s session.Session) tea.Cmd {
 	return func() tea.Msg {
-		return AttachMsg{Name: name}
+		return AttachMsg{Name: s.Name, Host: s.Host}
 	}
 }
 
-func (m Model) killSession(name string) tea.Cmd {
+func (m Model) killSession(s session.Session) tea.Cmd {
 	return func() tea.Msg {
-		err := m.manager.Kill(context.Background(), name)
-		return KillMsg{Err: err}
+		mgr, err := m.managerFor(s.Host)
+		if err != nil {
+			return KillMsg{Name: s.Name, Err: err}
+		}
+		return KillMsg{Name: s.Name, Err: mgr.Kill(context.Background(), s.Name)}
 	}
 }
 
 func (m Model) killIdleSessions() tea.Cmd {
+	return m.killMany(m.getIdleSessions())
+}
+
+func (m Model) sendPrompt(s session.Session, text string) tea.Cmd {
 	return func() tea.Msg {
-		idleSessions := m.getIdleSessions()
-		ctx := context.Background()
-		var errors []string
-		for _, s := range idleSessions {
-			if err := m.manager.Kill(ctx, s.Name); err != nil {
-				errors = append(errors, fmt.Sprintf("%s: %v", s.Name, err))
-			}
-		}
-		if len(errors) > 0 {
-			return KillMsg{Err: fmt.Errorf("failed to kill some sessions: %s", strings.Join(errors, "; "))}
+		mgr, err := m.managerFor(s.Host)
+		if err != nil {
+			return SendMsg{Err: err}
 		}
-		return KillMsg{Err: nil}
+		return SendMsg{Err: mgr.SendCommand(context.Background(), s.Name, text)}
 	}
 }
 
+// getIdleSessions returns the sessions ctrl+k kills: idle ones, and those
+// claude exited or crashed in, which have nothing running in them.
 func (m Model) getIdleSessions() []session.Session {
 	var idle []session.Session
 	for _, s := range m.sessions {
-		if s.Status == session.StatusIdle && s.Managed {
+		if (s.Status == session.StatusIdle || s.Exited()) && s.Managed && !m.nesting.isOwn(s) {
 			idle = append(idle, s)
 		}
 	}
 	return idle
 }
 
-func (m Model) createSession(name, dir string) tea.Cmd {
+func (m Model) createSession(name, dir, args string) tea.Cmd {
+	return func() tea.Msg {
+		mgr, err := m.managerFor(m.createHost)
+		if err != nil {
+			return CreateMsg{Err: err}
+		}
+		return CreateMsg{Err: mgr.Create(context.Background(), name, dir, args)}
+	}
+}
+
+// discoverProjectDirs finds the directories the create form suggests: those
+// of local sessions, running then saved ones by most recently created, and
+// the git repositories under the configured project_dirs.
+func (m Model) discoverProjectDirs() tea.Cmd {
+	var recent []string
+	for _, s := range m.sessions {
+		if s.Host == "" && s.Path != "" {
+			recent = append(recent, s.Path)
+		}
+	}
+	roots := m.cfg.ProjectDirs
 	return func() tea.Msg {
-		err := m.manager.Create(context.Background(), name, dir, "")
-		return CreateMsg{Err: err}
+		defs, _ := session.LoadDefinitions(session.DefinitionsPath())
+		sort.SliceStable(defs, func(i, j int) bool { return defs[i].Created.After(defs[j].Created) })
+		for _, d := range defs {
+			recent = append(recent, d.Path)
+		}
+		return ProjectDirsMsg{Dirs: projects.Discover(roots, recent)}
 	}
 }
 
-func (m Model) fetchLogs(name string) tea.Cmd {
+func (m Model) fetchLogs(s session.Session) tea.Cmd {
 	return func() tea.Msg {
-		content, err := m.manager.GetLogs(context.Background(), name, m.cfg.LogHistory)
+		mgr, err := m.managerFor(s.Host)
+		if err != nil {
+			return LogsMsg{Err: err}
+		}
+		get := mgr.GetColoredLogs
+		if m.cfg.PlainLogs {
+			get = mgr.GetLogs
+		}
+		content, err := get(context.Background(), s.Name, m.cfg.LogHistory)
 		return LogsMsg{Content: content, Err: err}
 	}
 }
 
-func (m Model) fetchConversation(path string) tea.Cmd {
+// fetchConversation reads the conversation of s keeping messages that
+// match f. The filter is applied while parsing, so the last 50 matching
+// messages are shown even if they are far back in the log; fetchOlder reads
+// the ones before them.
+func (m Model) fetchConversation(s session.Session, f conversation.Filter) tea.Cmd {
 	return func() tea.Msg {
-		content, err := m.manager.GetConversation(path, 50)
-		return LogsMsg{Content: content, Err: err}
+		messages, counts, err := m.manager.GetConversationMessages(s, 50, f)
+		if err == nil && counts.Total == 0 {
+			return LogsMsg{Content: "No conversation messages found."}
+		}
+		return LogsMsg{Messages: messages, Counts: counts, Err: err}
 	}
 }
 
@@ -656,20 +1866,20 @@ func cleanDA1(name string) {
 	}
 }
 
-// DrainStdin reads and discards any pending data on stdin (e.g. DA1 response).
-// Exported so main.go can call it at startup.
-func DrainStdin() {
-	fd := int(os.Stdin.Fd())
-	_ = syscall.SetNonblock(fd, true)
-	buf := make([]byte, 1024)
-	os.Stdin.Read(buf)
-	time.Sleep(50 * time.Millisecond)
-	os.Stdin.Read(buf)
-	_ = syscall.SetNonblock(fd, false)
-}
-
 // Run starts the TUI application.
 func Run() error {
+	var state *uiState // UI state to return to after a detach
+	// File changes are recorded while attached too.
+	files, err := filefeed.New()
+	if err == nil {
+		defer files.Close()
+	}
+	tests := newTestResults()
+	guard := newGuardState()
+	defer guard.resumeAll()
+	sheet := timesheet.New(timesheet.Dir())
+	defer func() { _ = sheet.Close(time.Now()) }()
+	var views *viewHistory
 	for {
 		// Drain any pending DA1 responses before starting TUI
 		DrainStdin()
@@ -678,25 +1888,59 @@ func Run() error {
 		if err != nil {
 			return err
 		}
+		if state != nil {
+			m.restoreState(*state)
+		}
+		m.files = files
+		m.tests = tests
+		m.guard = guard
+		m.timesheet = sheet
+		if views == nil {
+			views = m.views
+		}
+		m.views = views
 
 		p := tea.NewProgram(m,
 			tea.WithAltScreen(),
 		)
 
 		result, err := p.Run()
+		m.stopWatcher()
+		m.stopEvents()
 		if err != nil {
 			return err
 		}
 
 		model := result.(Model)
+		if model.nextProfile != "" {
+			if err := UseProfile(model.nextProfile); err != nil {
+				return err
+			}
+			saved := model.saveState()
+			saved.hostFilter = "" // the hosts are the new profile's
+			state = &saved
+			continue
+		}
 		if model.attachTarget == "" {
 			return nil // Normal quit
 		}
+		saved := model.saveState()
+		state = &saved
 
 		// Bubble Tea has fully exited alt screen.
 		// Drain stdin to consume any DA1 response (?6c) from the terminal.
 		DrainStdin()
 
+		attached, start := model.attachedSession(), time.Now()
+		if model.attachHost != "" {
+			if err := model.attachRemote(); err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				time.Sleep(2 * time.Second)
+			}
+			_ = sheet.Attached(attached, start, time.Now())
+			continue
+		}
+
 		// Enable mouse scroll
 		name := model.attachTarget
 		_ = exec.Command("tmux", "set-option", "-t", name, "mouse", "on").Run()
@@ -711,18 +1955,32 @@ func Run() error {
 		cmd.Stdout = os.Stdout
 		cmd.Stderr = os.Stderr
 		_ = cmd.Run()
+		_ = sheet.Attached(attached, start, time.Now())
 
 		// User detached, loop back to dashboard
 	}
 }
 
-
-
 // ExecAttach attaches to a tmux session (used by CLI `new` command).
+// A "host:name" target attaches to a session on a configured remote host.
 func ExecAttach(name string) error {
+	if host, target, ok := strings.Cut(name, ":"); ok {
+		return execAttachRemote(host, target)
+	}
 	if !validSessionName.MatchString(name) {
 		return fmt.Errorf("invalid session name: %s", name)
 	}
+	if tmux.Inside() {
+		// Attaching from inside tmux would nest it; move this client instead.
+		client, err := tmux.NewClient()
+		if err != nil {
+			return err
+		}
+		if err := detectNesting(client).attachError(session.Session{Name: name}); err != nil {
+			return err
+		}
+		return client.SwitchClient(context.Background(), name)
+	}
 	// Mouse mode is controlled globally via Ctrl+B m toggle
 	// Don't override user's preference here
 	// Drain stdin right before attach to consume any pending DA1 response
@@ -737,6 +1995,16 @@ func ExecAttach(name string) error {
 	return proc.Run()
 }
 
+// SendPrompt sends text to a session's Claude prompt from the CLI.
+// A "host:name" target sends to a session on a configured remote host.
+func SendPrompt(name, text string) error {
+	client, name, err := targetClient(name)
+	if err != nil {
+		return err
+	}
+	return session.NewManager(client).SendCommand(context.Background(), name, text)
+}
+
 // CreateSession creates a new Claude session from CLI (non-TUI).
 func CreateSession(name, projectDir, claudeArgs string) error {
 	client, err := tmux.NewClient()
@@ -0,0 +1,279 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/seunggabi/claude-dashboard/internal/archive"
+	"github.com/seunggabi/claude-dashboard/internal/conversation"
+	"github.com/seunggabi/claude-dashboard/internal/git"
+	"github.com/seunggabi/claude-dashboard/internal/session"
+	"github.com/seunggabi/claude-dashboard/internal/ui"
+)
+
+// archiveCheckInterval is how often `serve` looks for sessions to archive.
+const archiveCheckInterval = time.Minute
+
+// ArchiveListMsg carries the archived sessions matching the archive search,
+// for the archive view.
+type ArchiveListMsg struct {
+	Entries []archive.Entry
+	Err     error
+}
+
+// UnarchiveMsg reports recreating an archived session.
+type UnarchiveMsg struct {
+	Name string
+	Err  error
+}
+
+// dueForArchive returns the sessions archive_after says to archive now,
+// leaving out the dashboard's own session and those already being archived.
+func (m Model) dueForArchive(now time.Time) []session.Session {
+	if m.cfg.ArchiveAfter <= 0 || m.client == nil {
+		return nil
+	}
+	var due []session.Session
+	for _, s := range session.DueForArchive(m.sessions, m.cfg.ArchiveAfter, now) {
+		if !m.nesting.isOwn(s) && !m.archiving[s.Name] {
+			due = append(due, s)
+		}
+	}
+	return due
+}
+
+// archiveIdle archives the sessions that have been idle for archive_after.
+// A session is tried once; if that fails it is left running.
+func (m Model) archiveIdle(now time.Time) (Model, tea.Cmd) {
+	due := m.dueForArchive(now)
+	if len(due) == 0 {
+		return m, nil
+	}
+	for _, s := range due {
+		m.archiving[s.Name] = true
+	}
+	return m, func() tea.Msg {
+		return BulkMsg{Result: m.manager.ArchiveMany(context.Background(), due, archive.Dir(), now)}
+	}
+}
+
+// listArchive reads the archived sessions matching archiveQuery.
+func (m Model) listArchive() tea.Msg {
+	entries, err := archive.List(archive.Dir())
+	return ArchiveListMsg{Entries: archive.Search(entries, m.archiveQuery), Err: err}
+}
+
+// showArchive opens the archive view on the listed entries.
+func (m Model) showArchive(msg ArchiveListMsg) Model {
+	if msg.Err != nil {
+		m.err = msg.Err
+		return m
+	}
+	m.archived = msg.Entries
+	if m.archiveCursor >= len(m.archived) {
+		m.archiveCursor = max(len(m.archived)-1, 0)
+	}
+	m.view = ViewArchive
+	return m
+}
+
+// unarchive recreates the archived session e.
+func (m Model) unarchive(e archive.Entry) tea.Cmd {
+	return func() tea.Msg {
+		return UnarchiveMsg{Name: e.Name, Err: m.manager.Unarchive(context.Background(), e)}
+	}
+}
+
+// fetchLogFile reads the conversation log at path keeping messages that
+// match f, like fetchConversation.
+func fetchLogFile(path string, f conversation.Filter) tea.Cmd {
+	return func() tea.Msg {
+		messages, counts, err := conversation.ReadLog(path, 50, f)
+		if err == nil && counts.Total == 0 {
+			return LogsMsg{Content: "No conversation messages found."}
+		}
+		return LogsMsg{Messages: messages, Counts: counts, Err: err}
+	}
+}
+
+// fetchArchivedPane reads the pane history saved with e.
+func fetchArchivedPane(e archive.Entry) tea.Cmd {
+	return func() tea.Msg {
+		content, err := e.Pane()
+		return LogsMsg{Content: content, Err: err}
+	}
+}
+
+// fetchArchivedCommits lists the commits whose session trailer names e, in
+// the repository it ran in.
+func fetchArchivedCommits(e archive.Entry) tea.Cmd {
+	return func() tea.Msg {
+		commits, err := git.SessionCommits(context.Background(), e.Path, e.Name)
+		if err != nil {
+			return LogsMsg{Err: err}
+		}
+		return LogsMsg{Content: ui.RenderCommits(e.Name, commits)}
+	}
+}
+
+func (m Model) handleArchiveKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc":
+		m.view = ViewDashboard
+	case "q":
+		return m, tea.Quit
+	case "up", "k":
+		if m.archiveCursor > 0 {
+			m.archiveCursor--
+		}
+	case "down", "j":
+		if m.archiveCursor < len(m.archived)-1 {
+			m.archiveCursor++
+		}
+	case "enter":
+		if m.archiveCursor < len(m.archived) {
+			if m.client == nil {
+				m.err = session.ErrNoTmux
+				return m, nil
+			}
+			return m, m.unarchive(m.archived[m.archiveCursor])
+		}
+	case "/":
+		m.archiveSearch = true
+		m.archiveInput.SetValue(m.archiveQuery)
+		return m, m.archiveInput.Focus()
+	case "backspace":
+		if m.archiveQuery != "" {
+			m.archiveQuery = ""
+			m.archiveCursor = 0
+			return m, m.listArchive
+		}
+	case "c":
+		if m.archiveCursor < len(m.archived) {
+			e := m.archived[m.archiveCursor]
+			path := e.ConversationPath()
+			if path == "" {
+				m.err = fmt.Errorf("no conversation was saved with %s", e.Name)
+				return m, nil
+			}
+			m.view = ViewLogs
+			m.logView = ui.NewLogView(e.Name+" (archived)", m.width, m.height)
+			m.logView.Follow = false
+			m.logSession = session.Session{Name: e.Name, Path: e.Path}
+			m.logIsConv = true
+			m.logFile = path
+			m.archiveLog = true
+			return m, fetchLogFile(path, conversation.Filter{})
+		}
+	case "l":
+		if m.archiveCursor < len(m.archived) {
+			e := m.archived[m.archiveCursor]
+			if e.Imported() {
+				m.err = fmt.Errorf("%s was imported and has no pane history; 'c' shows its conversation", e.Name)
+				return m, nil
+			}
+			m.view = ViewLogs
+			m.logView = ui.NewLogView(e.Name+" (archived)", m.width, m.height)
+			m.logView.Follow = false
+			m.logSession = session.Session{Name: e.Name, Path: e.Path}
+			m.logIsConv = false
+			m.archiveLog = true
+			return m, fetchArchivedPane(e)
+		}
+	case "g":
+		if m.archiveCursor < len(m.archived) {
+			e := m.archived[m.archiveCursor]
+			if e.Path == "" {
+				m.err = fmt.Errorf("%s has no directory to look for commits in", e.Name)
+				return m, nil
+			}
+			m.view = ViewLogs
+			m.logView = ui.NewLogView(e.Name+" commits", m.width, m.height)
+			m.logView.Follow = false
+			m.logSession = session.Session{Name: e.Name, Path: e.Path}
+			m.logIsConv = false
+			m.archiveLog = true
+			return m, fetchArchivedCommits(e)
+		}
+	}
+	return m, nil
+}
+
+// handleArchiveSearchKey edits the archive search; enter searches names,
+// projects and saved conversations.
+func (m Model) handleArchiveSearchKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "enter":
+		m.archiveQuery = strings.TrimSpace(m.archiveInput.Value())
+		m.archiveSearch = false
+		m.archiveInput.Blur()
+		m.archiveCursor = 0
+		return m, m.listArchive
+	case "esc":
+		m.archiveSearch = false
+		m.archiveInput.Blur()
+		return m, nil
+	}
+
+	var cmd tea.Cmd
+	m.archiveInput, cmd = m.archiveInput.Update(msg)
+	return m, cmd
+}
+
+// ImportTranscripts adds transcript files to the archive (see
+// archive.Import), from the CLI. project and path, when set, associate every
+// file with a project and the local directory it is restored in.
+func ImportTranscripts(w io.Writer, files []string, project, path string) error {
+	if path != "" {
+		abs, err := filepath.Abs(path)
+		if err != nil {
+			return err
+		}
+		path = abs
+	}
+	var errs []error
+	for _, file := range files {
+		e, err := archive.Import(archive.Dir(), file, archive.Entry{Project: project, Path: path}, time.Now())
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		fmt.Fprintf(w, "Imported %s as %s\n", file, e.Name)
+	}
+	return errors.Join(errs...)
+}
+
+// runArchiver archives sessions idle for after until ctx is done, for
+// `serve`, reporting each failure to onError.
+func runArchiver(ctx context.Context, mgr *session.Manager, after time.Duration, onError func(error)) {
+	ticker := time.NewTicker(archiveCheckInterval)
+	defer ticker.Stop()
+	tried := make(map[string]bool)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			sessions, err := mgr.List(ctx)
+			if err != nil {
+				continue
+			}
+			var due []session.Session
+			for _, s := range session.DueForArchive(sessions, after, now) {
+				if !tried[s.Name] {
+					tried[s.Name] = true
+					due = append(due, s)
+				}
+			}
+			for _, it := range mgr.ArchiveMany(ctx, due, archive.Dir(), now).Failed() {
+				onError(fmt.Errorf("archiving %s: %w", it.Name, it.Err))
+			}
+		}
+	}
+}
@@ -0,0 +1,82 @@
+package app
+
+import (
+	"context"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/seunggabi/claude-dashboard/internal/session"
+)
+
+// BulkMsg reports the outcome of an operation on several sessions. Err is
+// set when the operation could not start at all.
+type BulkMsg struct {
+	Result session.BulkResult
+	Err    error
+}
+
+// qualifiedName names s as on the command line: host:name for remote
+// sessions.
+func qualifiedName(s session.Session) string {
+	if s.Host != "" {
+		return s.Host + ":" + s.Name
+	}
+	return s.Name
+}
+
+// killMany kills sessions across hosts, one KillMany per host, letting
+// claude exit first.
+func (m Model) killMany(sessions []session.Session) tea.Cmd {
+	return func() tea.Msg {
+		return BulkMsg{Result: killManyWith(sessions, false, m.managerFor)}
+	}
+}
+
+// killManyWith kills sessions with the manager managerFor returns for each
+// host, right away with force. Items are named by qualifiedName.
+func killManyWith(sessions []session.Session, force bool, managerFor func(host string) (*session.Manager, error)) session.BulkResult {
+	ctx := context.Background()
+	result := session.BulkResult{Op: "Killed"}
+	byHost := make(map[string][]string)
+	var hosts []string
+	for _, s := range sessions {
+		if _, ok := byHost[s.Host]; !ok {
+			hosts = append(hosts, s.Host)
+		}
+		byHost[s.Host] = append(byHost[s.Host], s.Name)
+	}
+	for _, host := range hosts {
+		mgr, err := managerFor(host)
+		if err != nil {
+			for _, name := range byHost[host] {
+				result.Add(qualifiedName(session.Session{Name: name, Host: host}), err)
+			}
+			continue
+		}
+		for _, it := range mgr.KillMany(ctx, byHost[host], force).Items {
+			result.Add(qualifiedName(session.Session{Name: it.Name, Host: host}), it.Err)
+		}
+	}
+	return result
+}
+
+// showBulkResult reports a finished bulk operation: a notice when every item
+// succeeded, otherwise a summary listing each outcome.
+func (m Model) showBulkResult(msg BulkMsg) Model {
+	switch {
+	case msg.Err != nil:
+		m.err = msg.Err
+	case len(msg.Result.Failed()) > 0:
+		m.bulkResult = msg.Result
+		m.view = ViewBulk
+	case len(msg.Result.Items) > 0:
+		m.notice = msg.Result.Summary()
+	}
+	return m
+}
+
+// handleBulkKey closes the bulk summary on any key.
+func (m Model) handleBulkKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	m.bulkResult = session.BulkResult{}
+	m.view = ViewDashboard
+	return m, nil
+}
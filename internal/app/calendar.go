@@ -0,0 +1,36 @@
+package app
+
+import (
+	"context"
+	"io"
+	"time"
+
+	"github.com/seunggabi/claude-dashboard/internal/calendar"
+	"github.com/seunggabi/claude-dashboard/internal/config"
+	"github.com/seunggabi/claude-dashboard/internal/conversation"
+)
+
+// CalendarDays is how many days back the calendar covers by default.
+const CalendarDays = 30
+
+// calendarName is the name calendar apps show for the export.
+const calendarName = "Claude sessions"
+
+// WriteCalendar writes the work in conversations between from and to to w
+// as an iCalendar file, for the calendar command.
+func WriteCalendar(w io.Writer, from, to time.Time) error {
+	cfg := config.Load()
+	loadPricing(cfg)
+	applyLocale(cfg)
+	return writeCalendar(context.Background(), w, from, to)
+}
+
+// writeCalendar writes the events of from..to, with pricing and the locale
+// already set up.
+func writeCalendar(ctx context.Context, w io.Writer, from, to time.Time) error {
+	activities, err := conversation.Activities(ctx, from, to)
+	if err != nil {
+		return err
+	}
+	return calendar.Write(w, calendarName, calendar.Events(activities), time.Now())
+}
@@ -0,0 +1,113 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/seunggabi/claude-dashboard/internal/config"
+	"github.com/seunggabi/claude-dashboard/internal/session"
+	"github.com/seunggabi/claude-dashboard/internal/tmux"
+)
+
+// targetClient returns the tmux client for a CLI session target and the
+// session name on that client. A "host:name" target is on a configured
+// remote host.
+func targetClient(target string) (*tmux.Client, string, error) {
+	if host, name, ok := strings.Cut(target, ":"); ok {
+		client, err := remoteClientByName(host)
+		return client, name, err
+	}
+	client, err := tmux.NewClient()
+	return client, target, err
+}
+
+// ListSessions writes the sessions on this machine and every configured
+// host to w, one per line. With namesOnly only the names are written, for
+// scripts.
+func ListSessions(w io.Writer, namesOnly bool) error {
+	client, err := tmux.NewClient()
+	if err != nil {
+		client = nil // terminal-only: terminal sessions are still listed
+	}
+	ctx := context.Background()
+	sessions, err := session.NewManager(client).List(ctx)
+	if err != nil {
+		return err
+	}
+	remote, hosts := listRemoteSessions(ctx, newRemoteHosts(config.Load().Hosts))
+	sessions = append(sessions, remote...)
+
+	if namesOnly {
+		for _, s := range sessions {
+			fmt.Fprintln(w, qualifiedName(s))
+		}
+	} else {
+		tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(tw, "NAME\tSTATUS\tUPTIME\tPATH")
+		for _, s := range sessions {
+			fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", qualifiedName(s), s.StatusString(), s.Uptime(), s.Path)
+		}
+		tw.Flush()
+	}
+
+	var unreachable []string
+	for _, h := range hosts {
+		if !h.Reachable() {
+			unreachable = append(unreachable, h.Name)
+		}
+	}
+	if len(unreachable) > 0 {
+		return fmt.Errorf("unreachable hosts: %s", strings.Join(unreachable, ", "))
+	}
+	return nil
+}
+
+// KillSessions kills the named sessions ("host:name" for remote ones),
+// reporting each on w. Sessions that fail do not stop the others. Unless
+// force is set, claude is given time to exit first (see Manager.Kill).
+func KillSessions(w io.Writer, names []string, force bool) error {
+	var sessions []session.Session
+	for _, name := range names {
+		s := session.Session{Name: name}
+		if host, target, ok := strings.Cut(name, ":"); ok {
+			s = session.Session{Name: target, Host: host}
+		}
+		sessions = append(sessions, s)
+	}
+	result := killManyWith(sessions, force, func(host string) (*session.Manager, error) {
+		if host == "" {
+			client, err := tmux.NewClient()
+			if err != nil {
+				return nil, fmt.Errorf("tmux is required: %w", err)
+			}
+			return session.NewManager(client), nil
+		}
+		client, err := remoteClientByName(host)
+		if err != nil {
+			return nil, err
+		}
+		return session.NewManager(client), nil
+	})
+	for _, name := range result.Succeeded() {
+		fmt.Fprintf(w, "Killed %s\n", name)
+	}
+	return result.Err()
+}
+
+// PrintLogs writes the last lines of a session's pane ("host:name" for a
+// remote one) to w.
+func PrintLogs(w io.Writer, name string, lines int) error {
+	client, name, err := targetClient(name)
+	if err != nil {
+		return err
+	}
+	content, err := session.NewManager(client).GetLogs(context.Background(), name, lines)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintln(w, strings.TrimRight(content, "\n"))
+	return err
+}
@@ -0,0 +1,23 @@
+package app
+
+import (
+	"os"
+
+	"github.com/atotto/clipboard"
+	"github.com/aymanbagabas/go-osc52/v2"
+)
+
+// copyToClipboard puts text on the system clipboard. When no clipboard tool
+// is available (e.g. over SSH without xclip) it falls back to an OSC 52
+// escape sequence, which most modern terminals and tmux understand.
+func copyToClipboard(text string) error {
+	if err := clipboard.WriteAll(text); err == nil {
+		return nil
+	}
+	seq := osc52.New(text)
+	if os.Getenv("TMUX") != "" {
+		seq = seq.Tmux()
+	}
+	_, err := seq.WriteTo(os.Stderr)
+	return err
+}
@@ -0,0 +1,135 @@
+package app
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/seunggabi/claude-dashboard/internal/conversation"
+	"github.com/seunggabi/claude-dashboard/internal/git"
+	"github.com/seunggabi/claude-dashboard/internal/session"
+)
+
+// conflictInterval is how often the files sessions wrote are compared;
+// it takes a git status of every repository with edits.
+const conflictInterval = 10 * time.Second
+
+// conflictWatch finds sessions whose uncommitted edits overlap. The files
+// each session wrote come from its conversation log, read incrementally,
+// and are kept while git still reports them uncommitted. Refreshes can
+// overlap, hence the lock.
+type conflictWatch struct {
+	mu    sync.Mutex
+	logs  map[string]*conversation.EditLog // by historyKey
+	at    time.Time
+	found map[string]session.Conflict // by session name
+}
+
+func newConflictWatch() *conflictWatch {
+	return &conflictWatch{logs: make(map[string]*conversation.EditLog)}
+}
+
+// find returns the conflicts among the local sessions, comparing them
+// again if the last comparison is older than conflictInterval.
+func (w *conflictWatch) find(sessions []session.Session, now time.Time) map[string]session.Conflict {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if now.Sub(w.at) < conflictInterval {
+		return w.found
+	}
+	w.at = now
+
+	snapshots := make(map[string]git.Snapshot) // by session directory
+	var edits []session.Edits
+	for _, s := range sessions {
+		if s.Host != "" || s.Path == "" || !s.Git.Dirty() {
+			continue
+		}
+		written := w.written(s)
+		if len(written) == 0 {
+			continue
+		}
+		snap, ok := snapshots[s.Path]
+		if !ok {
+			var err error
+			if snap, err = git.TakeSnapshot(context.Background(), s.Path); err != nil {
+				continue
+			}
+			snapshots[s.Path] = snap
+		}
+		e := session.Edits{Session: s.Name, Repo: snap.Repo}
+		for _, file := range relativeFiles(snap.Root, s.Path, written) {
+			if snap.Has(file) {
+				e.Files = append(e.Files, file)
+			}
+		}
+		edits = append(edits, e)
+	}
+	w.found = session.Conflicts(edits)
+	return w.found
+}
+
+// written returns the files the current conversation of s wrote.
+func (w *conflictWatch) written(s session.Session) []string {
+	path, err := s.Log()
+	if err != nil {
+		return nil
+	}
+	log, ok := w.logs[historyKey(s)]
+	if !ok {
+		log = &conversation.EditLog{}
+		w.logs[historyKey(s)] = log
+	}
+	files, _ := log.Read(path)
+	return files
+}
+
+// forget drops the log of a session that is gone.
+func (w *conflictWatch) forget(key string) {
+	w.mu.Lock()
+	delete(w.logs, key)
+	w.mu.Unlock()
+}
+
+// relativeFiles turns the files a session in dir wrote into paths relative
+// to root, the top level of its working tree, as git status names them.
+// Files outside the working tree are left out.
+func relativeFiles(root, dir string, files []string) []string {
+	real := dir
+	if r, err := filepath.EvalSymlinks(dir); err == nil {
+		real = r // git reports the top level with symlinks resolved
+	}
+	prefix, err := filepath.Rel(root, real)
+	if err != nil {
+		return nil
+	}
+	var out []string
+	for _, f := range files {
+		if !filepath.IsAbs(f) {
+			f = filepath.Join(dir, f)
+		}
+		rel, err := filepath.Rel(dir, f)
+		if err != nil {
+			continue
+		}
+		rel = filepath.ToSlash(filepath.Join(prefix, rel))
+		if rel == ".." || strings.HasPrefix(rel, "../") {
+			continue
+		}
+		out = append(out, rel)
+	}
+	return out
+}
+
+// conflictCount returns how many listed sessions are in a conflict.
+func (m Model) conflictCount() int {
+	n := 0
+	for _, s := range m.sessions {
+		if !s.Conflict.IsZero() {
+			n++
+		}
+	}
+	return n
+}
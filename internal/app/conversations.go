@@ -0,0 +1,97 @@
+package app
+
+import (
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/seunggabi/claude-dashboard/internal/conversation"
+	"github.com/seunggabi/claude-dashboard/internal/locale"
+	"github.com/seunggabi/claude-dashboard/internal/projects"
+	"github.com/seunggabi/claude-dashboard/internal/session"
+	"github.com/seunggabi/claude-dashboard/internal/ui"
+)
+
+// ConversationsMsg carries the conversation logs of a session's directory,
+// for the conversation picker.
+type ConversationsMsg struct {
+	Session session.Session
+	Logs    []conversation.LogFile
+	Err     error
+}
+
+// ResumableMsg carries the conversations of Dir for the create form to
+// offer for resuming.
+type ResumableMsg struct {
+	Dir  string
+	Logs []conversation.LogFile
+}
+
+// listResumable reads the conversation logs of dir. A directory claude has
+// not run in yet has none.
+func listResumable(dir string) tea.Cmd {
+	return func() tea.Msg {
+		logs, _ := conversation.ListLogs(projects.ExpandHome(dir))
+		return ResumableMsg{Dir: dir, Logs: logs}
+	}
+}
+
+// listConversations reads the conversation logs of s (C).
+func (m Model) listConversations(s session.Session) tea.Cmd {
+	return func() tea.Msg {
+		if s.Host != "" {
+			return ConversationsMsg{Err: fmt.Errorf("conversation logs of remote sessions cannot be listed")}
+		}
+		if s.Path == "" {
+			return ConversationsMsg{Err: fmt.Errorf("no working directory for session %s", s.Name)}
+		}
+		logs, err := conversation.ListLogs(s.Path)
+		return ConversationsMsg{Session: s, Logs: logs, Err: err}
+	}
+}
+
+// showConversations opens the conversation picker on the listed logs.
+func (m Model) showConversations(msg ConversationsMsg) Model {
+	if msg.Err != nil {
+		m.err = msg.Err
+		return m
+	}
+	m.convSession = msg.Session
+	m.convLogs = msg.Logs
+	m.convCursor = 0
+	m.view = ViewConversations
+	return m
+}
+
+func (m Model) handleConversationsKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc":
+		m.view = ViewDashboard
+	case "q":
+		return m, tea.Quit
+	case "up", "k":
+		if m.convCursor > 0 {
+			m.convCursor--
+		}
+	case "down", "j":
+		if m.convCursor < len(m.convLogs)-1 {
+			m.convCursor++
+		}
+	case "enter":
+		if m.convCursor < len(m.convLogs) {
+			l := m.convLogs[m.convCursor]
+			s := m.convSession
+			when := l.Started
+			if when.IsZero() {
+				when = l.Modified
+			}
+			m.view = ViewLogs
+			m.logView = ui.NewLogView(fmt.Sprintf("%s (%s)", s.Name, locale.Current().DateTime(when.Local())), m.width, m.height)
+			m.logSession = s
+			m.logIsConv = true
+			m.logFile = l.Path
+			m.convLog = true
+			return m, fetchLogFile(l.Path, conversation.Filter{})
+		}
+	}
+	return m, nil
+}
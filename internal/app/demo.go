@@ -0,0 +1,52 @@
+package app
+
+import (
+	"os"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/seunggabi/claude-dashboard/internal/demo"
+	"github.com/seunggabi/claude-dashboard/internal/session"
+)
+
+// RunDemo starts the dashboard on made-up sessions and conversations, for
+// screenshots and trying it without tmux or claude. It runs in a temporary
+// home directory, so the user's config and conversation logs are neither
+// read nor changed.
+func RunDemo() error {
+	home, err := os.MkdirTemp("", "claude-dashboard-demo-")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(home)
+	for _, env := range []string{"HOME", "USERPROFILE"} {
+		if err := os.Setenv(env, home); err != nil {
+			return err
+		}
+	}
+
+	now := time.Now()
+	fleet, err := demo.New(home, now)
+	if err != nil {
+		return err
+	}
+	m := newDemoModel(fleet, now)
+	_, err = tea.NewProgram(m, tea.WithAltScreen()).Run()
+	m.stopWatcher()
+	m.stopEvents()
+	return err
+}
+
+// newDemoModel creates an app model listing the sessions of fleet, with
+// their branches and test results shown.
+func newDemoModel(fleet *demo.Fleet, now time.Time) Model {
+	m := newModel(nil, session.NewSourceManager(fleet))
+	m.demo = fleet
+	m.cfg.ShowBranch = true
+	for _, s := range fleet.Sessions(now) {
+		if !s.Test.IsZero() {
+			m.tests.set(historyKey(s), s.Test)
+		}
+	}
+	return m
+}
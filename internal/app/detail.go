@@ -0,0 +1,132 @@
+package app
+
+import (
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/seunggabi/claude-dashboard/internal/conversation"
+	"github.com/seunggabi/claude-dashboard/internal/filefeed"
+	"github.com/seunggabi/claude-dashboard/internal/monitor"
+	"github.com/seunggabi/claude-dashboard/internal/session"
+	"github.com/seunggabi/claude-dashboard/internal/timesheet"
+	"github.com/seunggabi/claude-dashboard/internal/ui"
+)
+
+// detailToolLimit is how many recent tool calls the detail view keeps.
+const detailToolLimit = 200
+
+// detailFileLimit is how many recent file changes the detail view is given.
+const detailFileLimit = 10
+
+// FilesMsg is sent when the file feed recorded changes, so the detail view
+// shows them.
+type FilesMsg struct{}
+
+// ToolsMsg carries the tool timeline of the session shown in the detail
+// view, its assistant messages of the last session.ForecastWindow and the
+// time recorded for it today. Events is nil when the conversation log could
+// not be read.
+type ToolsMsg struct {
+	Key      string // historyKey of the session the events belong to
+	Events   []conversation.ToolEvent
+	Messages []conversation.Message
+	Times    timesheet.Totals
+}
+
+// detailSession returns the session the detail view shows.
+func (m Model) detailSession() (session.Session, bool) {
+	sessions := m.filteredSessions()
+	if m.cursor < len(sessions) {
+		return sessions[m.cursor], true
+	}
+	return session.Session{}, false
+}
+
+// fetchTools reads the tool timeline of s. Remote logs are not reachable,
+// so they yield nil.
+func (m Model) fetchTools(s session.Session) tea.Cmd {
+	key := historyKey(s)
+	return func() tea.Msg {
+		times := m.timesToday(s)
+		if s.Host != "" || s.Path == "" {
+			return ToolsMsg{Key: key, Times: times}
+		}
+		events, err := m.manager.GetToolTimeline(s, detailToolLimit)
+		if err != nil {
+			return ToolsMsg{Key: key, Times: times}
+		}
+		if events == nil {
+			events = []conversation.ToolEvent{}
+		}
+		filter := conversation.Filter{Role: "assistant", Since: time.Now().Add(-session.ForecastWindow)}
+		msgs, _, _ := m.manager.GetConversationMessages(s, 0, filter)
+		return ToolsMsg{Key: key, Events: events, Messages: msgs, Times: times}
+	}
+}
+
+// detailForecast is the outlook of s from its samples and the messages
+// last read for the detail view.
+func (m Model) detailForecast(s session.Session, now time.Time) session.Forecast {
+	samples := m.history.Since(historyKey(s), now.Add(-session.ForecastWindow))
+	return session.Predict(s.Status, samples, m.detailMsgs, now)
+}
+
+// detailWindow returns the time span of the detail view's CPU and memory
+// graphs.
+func (m Model) detailWindow() time.Duration {
+	return ui.DetailWindows[m.detailWindowIdx%len(ui.DetailWindows)]
+}
+
+// detailSamples returns the samples of s the detail view's graphs cover.
+func (m Model) detailSamples(s session.Session, now time.Time) []monitor.Sample {
+	return m.history.Since(historyKey(s), now.Add(-m.detailWindow()))
+}
+
+// fileChanges returns the recent file changes in the directory of s, or
+// nil when it is not watched.
+func (m Model) fileChanges(s session.Session) []filefeed.Change {
+	if m.files == nil || s.Host != "" || s.Path == "" {
+		return nil
+	}
+	return m.files.Recent(s.Path, detailFileLimit)
+}
+
+// syncFiles makes the file feed watch the directories of the local
+// sessions.
+func (m Model) syncFiles() {
+	if m.files == nil {
+		return
+	}
+	var dirs []string
+	for _, s := range m.sessions {
+		if s.Host == "" && s.Path != "" {
+			dirs = append(dirs, s.Path)
+		}
+	}
+	m.files.Sync(dirs)
+}
+
+// waitForFiles delivers the next burst of file changes as a FilesMsg.
+func (m Model) waitForFiles() tea.Cmd {
+	if m.files == nil {
+		return nil
+	}
+	return func() tea.Msg {
+		if _, ok := <-m.files.Events(); !ok {
+			return nil
+		}
+		return FilesMsg{}
+	}
+}
+
+// refreshDetail rereads the tool timeline while the detail view is open.
+func (m Model) refreshDetail() tea.Cmd {
+	if m.view != ViewDetail {
+		return nil
+	}
+	s, ok := m.detailSession()
+	if !ok {
+		return nil
+	}
+	return m.fetchTools(s)
+}
@@ -0,0 +1,219 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/seunggabi/claude-dashboard/internal/config"
+	"github.com/seunggabi/claude-dashboard/internal/conversation"
+	"github.com/seunggabi/claude-dashboard/internal/setup"
+	"github.com/seunggabi/claude-dashboard/internal/tmux"
+)
+
+// doctorLevel grades a doctor check.
+type doctorLevel int
+
+const (
+	doctorPass doctorLevel = iota
+	doctorWarn             // works, but something is degraded
+	doctorFail
+)
+
+// doctorResult is the outcome of one doctor check.
+type doctorResult struct {
+	name   string
+	level  doctorLevel
+	detail string
+	hint   string // what to do about a warning or failure
+}
+
+// doctorChecks are run in order by Doctor.
+var doctorChecks = []func() doctorResult{
+	checkTmux,
+	checkScripts,
+	checkTmuxConf,
+	checkClaude,
+	checkProjects,
+	checkTerminal,
+	checkFlowControl,
+	checkConfig,
+}
+
+// Doctor checks the environment the dashboard depends on and writes one line
+// per check to w, with a hint for each problem. It returns an error if any
+// check fails; warnings do not count.
+func Doctor(w io.Writer) error {
+	failed := 0
+	for _, check := range doctorChecks {
+		r := check()
+		mark := "✓"
+		switch r.level {
+		case doctorWarn:
+			mark = "!"
+		case doctorFail:
+			mark = "✗"
+			failed++
+		}
+		fmt.Fprintf(w, "%s %-16s %s\n", mark, r.name, r.detail)
+		if r.level != doctorPass && r.hint != "" {
+			fmt.Fprintf(w, "  %-16s → %s\n", "", r.hint)
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d check(s) failed", failed)
+	}
+	return nil
+}
+
+func checkTmux() doctorResult {
+	r := doctorResult{name: "tmux"}
+	client, err := tmux.NewClient()
+	if err == nil {
+		r.detail, err = client.Version(context.Background())
+	}
+	if err != nil {
+		r.level = doctorFail
+		r.detail = err.Error()
+		r.hint = "install tmux (e.g. brew install tmux, apt install tmux); without it only terminal sessions are listed"
+	}
+	return r
+}
+
+func checkScripts() doctorResult {
+	r := doctorResult{name: "helper scripts", detail: "installed in ~/.local/bin"}
+	if missing := setup.MissingScripts(); len(missing) > 0 {
+		r.level = doctorFail
+		r.detail = "missing " + strings.Join(missing, ", ")
+		r.hint = "run 'claude-dashboard setup'"
+	}
+	return r
+}
+
+func checkTmuxConf() doctorResult {
+	r := doctorResult{name: "tmux.conf", detail: "dashboard settings present"}
+	missing, err := setup.MissingTmuxConfig()
+	switch {
+	case err != nil:
+		r.level = doctorFail
+		r.detail = err.Error()
+	case len(missing) > 0:
+		// Users may have tuned these lines by hand, so this is only a warning.
+		r.level = doctorWarn
+		r.detail = fmt.Sprintf("%d setting(s) missing, e.g. %s", len(missing), missing[0])
+		r.hint = "run 'claude-dashboard setup' to add them (mouse toggle and save keys, status bar), or 'setup --update-bindings' after changing the keys"
+	}
+	return r
+}
+
+func checkClaude() doctorResult {
+	r := doctorResult{name: "claude"}
+	path, err := exec.LookPath("claude")
+	if err != nil {
+		r.level = doctorFail
+		r.detail = "not found in PATH"
+		r.hint = "install Claude Code (npm install -g @anthropic-ai/claude-code) and make sure it is on PATH"
+		return r
+	}
+	r.detail = path
+	return r
+}
+
+func checkProjects() doctorResult {
+	dir := conversation.ProjectsDir()
+	r := doctorResult{name: "conversations"}
+	entries, err := os.ReadDir(dir)
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+		r.level = doctorWarn
+		r.detail = dir + " does not exist yet"
+		r.hint = "it is created when claude first runs; logs, monitor and export need it"
+	case err != nil:
+		r.level = doctorFail
+		r.detail = err.Error()
+		r.hint = "make " + dir + " readable by this user"
+	default:
+		r.detail = fmt.Sprintf("%s (%d projects)", dir, len(entries))
+	}
+	return r
+}
+
+func checkTerminal() doctorResult {
+	r := doctorResult{name: "terminal"}
+	var problems []string
+	if info, err := os.Stdout.Stat(); err == nil && info.Mode()&os.ModeCharDevice == 0 {
+		problems = append(problems, "output is not a terminal")
+	}
+	term := os.Getenv("TERM")
+	if runtime.GOOS != "windows" && (term == "" || term == "dumb") {
+		problems = append(problems, fmt.Sprintf("TERM=%q", term))
+	}
+	profile := lipgloss.ColorProfile().Name()
+	if profile == "Ascii" {
+		problems = append(problems, "no colour support detected")
+	}
+	locale := os.Getenv("LC_ALL") + os.Getenv("LC_CTYPE") + os.Getenv("LANG")
+	if runtime.GOOS != "windows" && !strings.Contains(strings.ToUpper(locale), "UTF-8") && !strings.Contains(strings.ToUpper(locale), "UTF8") {
+		problems = append(problems, "locale is not UTF-8")
+	}
+	if len(problems) > 0 {
+		r.level = doctorWarn
+		r.detail = strings.Join(problems, "; ")
+		r.hint = "use a UTF-8 locale and TERM=xterm-256color, or set status_icons: ascii in the config"
+		return r
+	}
+	r.detail = fmt.Sprintf("TERM=%s, colours: %s", term, profile)
+	return r
+}
+
+func checkFlowControl() doctorResult {
+	r := doctorResult{name: "flow control"}
+	on, err := setup.FlowControl()
+	switch {
+	case err != nil:
+		r.detail = "not checked (" + err.Error() + ")"
+	case !on:
+		r.detail = "off; Ctrl+S and Ctrl+Q reach tmux"
+	default:
+		r.detail = "on (ixon)"
+		if conflicts := setup.FlowControlConflicts(setup.ConfiguredBindings()); len(conflicts) > 0 {
+			r.level = doctorWarn
+			r.detail = fmt.Sprintf("on (ixon); %s is taken by flow control and can freeze the terminal", strings.Join(conflicts, ", "))
+			r.hint = "add 'stty -ixon' to your shell profile, or bind another key with 'claude-dashboard setup --update-bindings' (e.g. " + setup.FlowControlSaveHistoryKey + ")"
+		}
+	}
+	return r
+}
+
+func checkConfig() doctorResult {
+	path := config.ConfigPath()
+	r := doctorResult{name: "config"}
+	data, err := os.ReadFile(path)
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+		r.detail = "no config file; using defaults"
+		return r
+	case err != nil:
+		r.level = doctorFail
+		r.detail = err.Error()
+		return r
+	}
+	if errs := config.Validate(data); len(errs) > 0 {
+		msgs := make([]string, len(errs))
+		for i, e := range errs {
+			msgs[i] = e.Error()
+		}
+		r.level = doctorFail
+		r.detail = strings.Join(msgs, "; ")
+		r.hint = "fix " + path + "; invalid values fall back to their defaults"
+		return r
+	}
+	r.detail = path
+	return r
+}
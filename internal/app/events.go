@@ -0,0 +1,104 @@
+package app
+
+import (
+	"context"
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/seunggabi/claude-dashboard/internal/config"
+	"github.com/seunggabi/claude-dashboard/internal/conversation"
+	"github.com/seunggabi/claude-dashboard/internal/session"
+	"github.com/seunggabi/claude-dashboard/internal/webhook"
+)
+
+// EventMsg carries one session change reported by the managers.
+type EventMsg struct {
+	Event session.Event
+}
+
+// shareTracker makes the local and remote managers report to one tracker.
+func shareTracker(mgr *session.Manager, remotes []remoteHost) *session.Tracker {
+	tracker := session.NewTracker()
+	mgr.SetTracker(tracker)
+	for _, r := range remotes {
+		if r.manager != nil {
+			r.manager.SetTracker(tracker)
+		}
+	}
+	return tracker
+}
+
+// startWebhooks posts the transitions tracker sees to hooks, reporting
+// failed posts to onError when set, until the returned function is called.
+func startWebhooks(hooks []config.Webhook, tracker *session.Tracker, onError func(error)) func() {
+	if len(hooks) == 0 {
+		return func() {}
+	}
+	events, unsubscribe := tracker.Subscribe()
+	ctx, cancel := context.WithCancel(context.Background())
+	n := webhook.New(hooks)
+	n.OnError = onError
+	n.LastMessage = lastReply
+	go n.Run(ctx, events)
+	return func() {
+		cancel()
+		unsubscribe()
+	}
+}
+
+// lastReply returns the last assistant message of a local session, if its
+// conversation log can be read.
+func lastReply(s session.Session) string {
+	if s.Host != "" || s.Path == "" {
+		return ""
+	}
+	log, err := s.Log()
+	if err != nil {
+		return ""
+	}
+	text, _ := conversation.LastReplyIn(log)
+	return text
+}
+
+// waitForEvent delivers the next session event as an EventMsg.
+func (m Model) waitForEvent() tea.Cmd {
+	if m.events == nil {
+		return nil
+	}
+	return func() tea.Msg {
+		e, ok := <-m.events
+		if !ok {
+			return nil
+		}
+		return EventMsg{Event: e}
+	}
+}
+
+// handleEvent reacts to a session change: history of removed sessions is
+// dropped, a session starting to wait with nobody attached is announced, as
+// is every session having stopped working, and the pane of one that stopped
+// working is read for test results.
+func (m Model) handleEvent(e session.Event) (Model, tea.Cmd) {
+	switch e.Kind {
+	case session.SessionRemoved:
+		m.history.Forget(historyKey(e.Session))
+		m.tests.forget(historyKey(e.Session))
+		m.spend.forget(historyKey(e.Session))
+		m.conflicts.forget(historyKey(e.Session))
+		delete(m.overCap, historyKey(e.Session))
+		m.guard.setPaused(e.Session, false)
+		if e.Session.Host == "" {
+			delete(m.archiving, e.Session.Name)
+		}
+	case session.StatusChanged:
+		if e.Session.Status == session.StatusWaiting && !e.Session.Attached {
+			m.notice = fmt.Sprintf("%s is waiting for input", qualifiedName(e.Session))
+		}
+		if e.From == session.StatusActive {
+			return m, m.readPaneTests(e.Session)
+		}
+	case session.AllQuiet:
+		m.notice = fmt.Sprintf("All %d sessions are waiting on you", len(e.Quiet))
+	}
+	return m, nil
+}
@@ -0,0 +1,115 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/seunggabi/claude-dashboard/internal/config"
+	"github.com/seunggabi/claude-dashboard/internal/conversation"
+	"github.com/seunggabi/claude-dashboard/internal/session"
+	"github.com/seunggabi/claude-dashboard/internal/tmux"
+)
+
+// ExportMsg reports where the log viewer exported a conversation to.
+type ExportMsg struct {
+	Path string
+	Err  error
+}
+
+// ExportConversation writes the full conversation of the named session
+// (with or without the cd- prefix) to w, from the CLI.
+func ExportConversation(w io.Writer, name string, opts conversation.ExportOptions) error {
+	if strings.Contains(name, ":") {
+		return fmt.Errorf("conversation logs of remote sessions cannot be exported")
+	}
+	cfg := config.Load()
+	applyLocale(cfg)
+	s, err := localSession(name)
+	if err != nil {
+		return err
+	}
+	if s.Path == "" {
+		return fmt.Errorf("no working directory for session %s", name)
+	}
+	opts.Issue = issueOf(context.Background(), cfg, s)
+	opts.IssueURL = cfg.Issues.Link(opts.Issue)
+	log, err := s.Log()
+	if err != nil {
+		return err
+	}
+	return conversation.ExportLog(w, log, s.DisplayName(), opts)
+}
+
+// localSession finds the session of this machine called name, with or
+// without the cd- prefix, for the CLI.
+func localSession(name string) (session.Session, error) {
+	client, err := tmux.NewClient()
+	if err != nil {
+		client = nil // terminal-only: terminal sessions are still listed
+	}
+	sessions, err := session.NewManager(client).List(context.Background())
+	if err != nil {
+		return session.Session{}, err
+	}
+	for _, s := range sessions {
+		if s.Name == name || s.DisplayName() == name {
+			return s, nil
+		}
+	}
+	return session.Session{}, fmt.Errorf("session %s not found", name)
+}
+
+// exportDir is where the log viewer saves exports: the Desktop if there is
+// one, like the pane history saved with Ctrl+S, otherwise the home directory.
+func exportDir() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "."
+	}
+	if info, err := os.Stat(filepath.Join(home, "Desktop")); err == nil && info.IsDir() {
+		return filepath.Join(home, "Desktop")
+	}
+	return home
+}
+
+// exportConversation saves the full conversation of s as markdown: the log
+// at logFile, or the current log of s when empty.
+func (m Model) exportConversation(s session.Session, logFile string) tea.Cmd {
+	return func() tea.Msg {
+		if s.Host != "" {
+			return ExportMsg{Err: fmt.Errorf("conversation logs of remote sessions cannot be exported")}
+		}
+		if logFile == "" {
+			var err error
+			if logFile, err = s.Log(); err != nil {
+				return ExportMsg{Err: fmt.Errorf("export failed: %w", err)}
+			}
+		}
+		name := strings.ReplaceAll(s.DisplayName(), "/", "-")
+		path := filepath.Join(exportDir(),
+			fmt.Sprintf("claude-conversation_%s_%s.md", name, time.Now().Format("20060102_150405")))
+		f, err := os.Create(path)
+		if err != nil {
+			return ExportMsg{Err: err}
+		}
+		err = conversation.ExportLog(f, logFile, s.DisplayName(), conversation.ExportOptions{
+			Format:   conversation.ExportMarkdown,
+			Issue:    s.Issue,
+			IssueURL: m.cfg.Issues.Link(s.Issue),
+		})
+		if cerr := f.Close(); err == nil {
+			err = cerr
+		}
+		if err != nil {
+			os.Remove(path)
+			return ExportMsg{Err: fmt.Errorf("export failed: %w", err)}
+		}
+		return ExportMsg{Path: path}
+	}
+}
@@ -0,0 +1,80 @@
+package app
+
+import (
+	"context"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/seunggabi/claude-dashboard/internal/session"
+	"github.com/seunggabi/claude-dashboard/internal/tmux"
+	"github.com/seunggabi/claude-dashboard/internal/ui"
+	"github.com/seunggabi/claude-dashboard/internal/usage"
+)
+
+const (
+	// statsInterval is how often today's usage and the tmux server are
+	// reread for the header; reading every conversation log of the day is
+	// too slow for each refresh.
+	statsInterval = 30 * time.Second
+
+	// headerMinHeight is the least terminal height the header is shown
+	// at, so small terminals keep their rows for sessions.
+	headerMinHeight = 20
+)
+
+// StatsMsg carries today's usage and the state of the tmux server.
+type StatsMsg struct {
+	Usage     usage.Totals
+	UsageErr  error
+	Server    tmux.ServerInfo
+	ServerErr error
+}
+
+// showHeader reports whether the fleet header is drawn above the table.
+func (m Model) showHeader() bool {
+	return m.height >= headerMinHeight
+}
+
+// fetchStats reads today's usage and the tmux server in the background,
+// at most every statsInterval.
+func (m Model) fetchStats(now time.Time) (Model, tea.Cmd) {
+	if !m.showHeader() || now.Sub(m.statsRead) < statsInterval {
+		return m, nil
+	}
+	m.statsRead = now
+	client, fleet := m.client, m.demo
+	return m, func() tea.Msg {
+		ctx := context.Background()
+		var msg StatsMsg
+		msg.Usage, msg.UsageErr = usage.Today(ctx, time.Now())
+		switch {
+		case fleet != nil:
+			msg.Server = fleet.Server()
+		case client != nil:
+			msg.Server, msg.ServerErr = client.ServerInfo(ctx)
+		}
+		return msg
+	}
+}
+
+// showStats keeps the stats in msg for the header.
+func (m Model) showStats(msg StatsMsg) Model {
+	m.usage, m.usageErr = &msg.Usage, msg.UsageErr
+	m.server, m.serverErr = msg.Server, msg.ServerErr
+	return m
+}
+
+// fleetStats is what the header shows: totals of every listed session,
+// whatever the filter.
+func (m Model) fleetStats() ui.FleetStats {
+	return ui.FleetStats{
+		Fleet:     session.Summarize(m.sessions),
+		Icons:     session.Icons(m.cfg.StatusIcons),
+		Guard:     m.cfg.ResourceGuard,
+		Usage:     m.usage,
+		UsageErr:  m.usageErr,
+		NoTmux:    m.client == nil && m.demo == nil,
+		Server:    m.server,
+		ServerErr: m.serverErr,
+	}
+}
@@ -0,0 +1,60 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"path/filepath"
+
+	"github.com/seunggabi/claude-dashboard/internal/git"
+	"github.com/seunggabi/claude-dashboard/internal/session"
+	"github.com/seunggabi/claude-dashboard/internal/setup"
+)
+
+// InstallGitHook installs the commit-msg hook that adds a session trailer
+// (git.SessionTrailer) to commits, in the repository containing dir, from
+// the CLI.
+func InstallGitHook(w io.Writer, dir string) error {
+	hooks, err := hooksDir(dir)
+	if err != nil {
+		return err
+	}
+	path, err := setup.InstallGitHook(hooks, session.SessionPrefix)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(w, "Installed %s\nCommits made in dashboard sessions now get a %s trailer.\n", path, git.SessionTrailer)
+	return nil
+}
+
+// RemoveGitHook removes the hook InstallGitHook installed in the repository
+// containing dir, from the CLI.
+func RemoveGitHook(w io.Writer, dir string) error {
+	hooks, err := hooksDir(dir)
+	if err != nil {
+		return err
+	}
+	removed, err := setup.RemoveGitHook(hooks)
+	if err != nil {
+		return err
+	}
+	if !removed {
+		fmt.Fprintln(w, "No claude-dashboard commit-msg hook is installed.")
+		return nil
+	}
+	fmt.Fprintf(w, "Removed %s\n", filepath.Join(hooks, "commit-msg"))
+	return nil
+}
+
+// hooksDir returns the hooks directory of the repository containing dir.
+func hooksDir(dir string) (string, error) {
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return "", err
+	}
+	hooks, err := git.HooksDir(context.Background(), abs)
+	if err != nil {
+		return "", fmt.Errorf("%s is not in a git repository: %w", abs, err)
+	}
+	return hooks, nil
+}
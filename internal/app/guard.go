@@ -0,0 +1,179 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/seunggabi/claude-dashboard/internal/config"
+	"github.com/seunggabi/claude-dashboard/internal/locale"
+	"github.com/seunggabi/claude-dashboard/internal/session"
+	"github.com/seunggabi/claude-dashboard/internal/webhook"
+)
+
+// GuardMsg reports the resource guard pausing Session, or resuming it with
+// Resumed; Session is empty when neither happened. Err is why pausing,
+// resuming or telling the webhooks failed.
+type GuardMsg struct {
+	Session session.Session
+	Resumed bool
+	Err     error
+}
+
+// guardState is what the resource guard remembers across refreshes, kept
+// across attaches (see Run): whether the sessions were over a threshold at
+// the last refresh, so each crossing is dealt with once, and the sessions
+// it paused.
+type guardState struct {
+	mu     sync.Mutex
+	over   bool
+	paused map[string]session.Session // by historyKey
+}
+
+func newGuardState() *guardState {
+	return &guardState{paused: make(map[string]session.Session)}
+}
+
+// isPaused reports whether the guard paused s.
+func (g *guardState) isPaused(s session.Session) bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	_, ok := g.paused[historyKey(s)]
+	return ok
+}
+
+// setPaused records s as paused or not.
+func (g *guardState) setPaused(s session.Session, paused bool) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if paused {
+		g.paused[historyKey(s)] = s
+	} else {
+		delete(g.paused, historyKey(s))
+	}
+}
+
+// count returns how many sessions the guard paused.
+func (g *guardState) count() int {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return len(g.paused)
+}
+
+// resumeAll resumes every session the guard paused, when the dashboard
+// quits and nothing would be left to resume them.
+func (g *guardState) resumeAll() {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	for key, s := range g.paused {
+		_ = session.Resume(s)
+		delete(g.paused, key)
+	}
+}
+
+// localUsage sums the CPU and memory of the sessions on this machine.
+func localUsage(sessions []session.Session) (cpu, memory float64) {
+	for _, s := range sessions {
+		if s.Host == "" {
+			cpu += s.CPU
+			memory += s.Memory
+		}
+	}
+	return cpu, memory
+}
+
+// enforceResourceGuard announces the local sessions passing the resource
+// guard together, in the title bar and to the webhooks wanting
+// resource_guard, and with the pause action pauses the heaviest of them.
+// Each crossing is dealt with once: the guard acts again only after the
+// totals have been back under the thresholds.
+func (m Model) enforceResourceGuard(now time.Time) (Model, tea.Cmd) {
+	g := m.cfg.ResourceGuard
+	if !g.Enabled() || m.demo != nil {
+		return m, nil
+	}
+	cpu, memory := localUsage(m.sessions)
+	over := g.Exceeded(cpu, memory)
+	crossed := over && !m.guard.over
+	m.guard.over = over
+	if !crossed {
+		return m, nil
+	}
+	loc := locale.Current()
+	m.notice = fmt.Sprintf("Claude sessions use %s CPU and %s memory, over the resource guard", loc.Percent(cpu), loc.Percent(memory))
+	var target *session.Session
+	if g.Action == config.GuardPause {
+		if s, ok := m.heaviestUnattended(g.CPU > 0 && cpu > g.CPU); ok {
+			target = &s
+			m.notice += fmt.Sprintf("; pausing %s (z resumes it)", s.Name)
+		}
+	}
+	hooks := m.cfg.Webhooks
+	return m, func() tea.Msg {
+		var msg GuardMsg
+		if target != nil {
+			if msg.Err = session.Pause(*target); msg.Err == nil {
+				msg.Session = *target
+			} else {
+				target = nil
+			}
+		}
+		if err := webhook.New(hooks).Announce(context.Background(), webhook.NewGuardPayload(cpu, memory, target, now)); err != nil && msg.Err == nil {
+			msg.Err = err
+		}
+		return msg
+	}
+}
+
+// heaviestUnattended returns the local tmux session with nobody attached
+// using the most CPU, or memory unless byCPU, that is not paused already.
+// The session the dashboard runs in is left alone.
+func (m Model) heaviestUnattended(byCPU bool) (session.Session, bool) {
+	var best session.Session
+	found := false
+	load := func(s session.Session) float64 {
+		if byCPU {
+			return s.CPU
+		}
+		return s.Memory
+	}
+	for _, s := range m.sessions {
+		if s.Host != "" || !s.Managed || s.Attached || s.PID == "" || m.nesting.isOwn(s) || m.guard.isPaused(s) {
+			continue
+		}
+		if !found || load(s) > load(best) {
+			best, found = s, true
+		}
+	}
+	return best, found
+}
+
+// handleGuard records a session paused or resumed.
+func (m Model) handleGuard(msg GuardMsg) Model {
+	if msg.Session.Name != "" {
+		m.guard.setPaused(msg.Session, !msg.Resumed)
+		if msg.Resumed {
+			m.notice = "Resumed " + qualifiedName(msg.Session)
+		}
+	}
+	if msg.Err != nil {
+		m.err = msg.Err
+	}
+	return m
+}
+
+// resumeSession resumes s (z) if the resource guard paused it.
+func (m Model) resumeSession(s session.Session) (Model, tea.Cmd) {
+	if !m.guard.isPaused(s) {
+		m.notice = qualifiedName(s) + " is not paused by the resource guard"
+		return m, nil
+	}
+	return m, func() tea.Msg {
+		if err := session.Resume(s); err != nil {
+			return GuardMsg{Err: err}
+		}
+		return GuardMsg{Session: s, Resumed: true}
+	}
+}
@@ -0,0 +1,287 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/seunggabi/claude-dashboard/internal/config"
+	"github.com/seunggabi/claude-dashboard/internal/session"
+	"github.com/seunggabi/claude-dashboard/internal/tmux"
+)
+
+// newHostClient builds a tmux client for a configured remote host.
+func newHostClient(h config.Host) (*tmux.Client, error) {
+	if err := h.Validate(); err != nil {
+		return nil, err
+	}
+	return tmux.NewRemoteClient(tmux.RemoteOptions{
+		Address:      h.Address,
+		Port:         h.Port,
+		ProxyJump:    h.ProxyJump,
+		IdentityFile: h.IdentityFile,
+		TmuxPath:     h.TmuxPath,
+		SocketName:   h.SocketName,
+	})
+}
+
+// remoteHost pairs a configured host with its session manager. manager is nil
+// when the host entry could not be turned into a client; err says why.
+type remoteHost struct {
+	name    string
+	client  *tmux.Client
+	manager *session.Manager
+	err     error
+}
+
+// newRemoteHosts builds a session manager for each configured host.
+func newRemoteHosts(hosts []config.Host) []remoteHost {
+	remotes := make([]remoteHost, 0, len(hosts))
+	for _, h := range hosts {
+		r := remoteHost{name: h.Name}
+		client, err := newHostClient(h)
+		if err != nil {
+			r.err = err
+		} else {
+			r.client = client
+			r.manager = session.NewManager(client)
+			r.manager.SetHost(h.Name)
+		}
+		remotes = append(remotes, r)
+	}
+	return remotes
+}
+
+// listRemoteSessions queries every remote host in parallel. Sessions are
+// tagged with their host name; per-host counts and errors are returned in
+// config order.
+func listRemoteSessions(ctx context.Context, remotes []remoteHost) ([]session.Session, []session.HostStatus) {
+	lists := make([][]session.Session, len(remotes))
+	statuses := make([]session.HostStatus, len(remotes))
+	var wg sync.WaitGroup
+	for i, r := range remotes {
+		statuses[i] = session.HostStatus{Name: r.name, Err: r.err}
+		if r.manager == nil {
+			continue
+		}
+		wg.Add(1)
+		go func(i int, r remoteHost) {
+			defer wg.Done()
+			sessions, err := r.manager.List(ctx)
+			if err != nil {
+				statuses[i].Err = err
+				return
+			}
+			lists[i] = sessions
+			statuses[i].Sessions = len(sessions)
+		}(i, r)
+	}
+	wg.Wait()
+
+	var all []session.Session
+	for _, l := range lists {
+		all = append(all, l...)
+	}
+	return all, statuses
+}
+
+// hostLabel returns a display name for a session host field.
+func hostLabel(host string) string {
+	if host == "" {
+		return session.LocalHost
+	}
+	return host
+}
+
+// remote returns the configured remote host with the given name.
+func (m Model) remote(host string) (remoteHost, error) {
+	for _, r := range m.remotes {
+		if r.name == host {
+			if r.err != nil {
+				return r, fmt.Errorf("host %s: %w", host, r.err)
+			}
+			return r, nil
+		}
+	}
+	return remoteHost{}, fmt.Errorf("unknown host: %s", host)
+}
+
+// managerFor returns the session manager responsible for host.
+func (m Model) managerFor(host string) (*session.Manager, error) {
+	if host == "" {
+		return m.manager, nil
+	}
+	r, err := m.remote(host)
+	if err != nil {
+		return nil, err
+	}
+	return r.manager, nil
+}
+
+// hasSession reports whether a session called name was last seen on host.
+func (m Model) hasSession(host, name string) bool {
+	for _, s := range m.sessions {
+		if s.Host == host && s.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// attachRemote runs an interactive attach to the model's attach target over ssh.
+func (m Model) attachRemote() error {
+	r, err := m.remote(m.attachHost)
+	if err != nil {
+		return err
+	}
+	return runAttach(r.client, m.attachTarget)
+}
+
+// execAttachRemote attaches to name on a configured host (CLI `attach host:name`).
+func execAttachRemote(host, name string) error {
+	if !validSessionName.MatchString(name) {
+		return fmt.Errorf("invalid session name: %s", name)
+	}
+	client, err := remoteClientByName(host)
+	if err != nil {
+		return err
+	}
+	return runAttach(client, name)
+}
+
+// remoteClientByName builds a tmux client for the configured host called name.
+func remoteClientByName(name string) (*tmux.Client, error) {
+	for _, h := range config.Load().Hosts {
+		if h.Name == name {
+			return newHostClient(h)
+		}
+	}
+	return nil, fmt.Errorf("unknown host: %s", name)
+}
+
+// runAttach hands the terminal to an attach command until the user detaches.
+func runAttach(client *tmux.Client, name string) error {
+	cmd := client.AttachCommand(name)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	return cmd.Run()
+}
+
+// nextHostFilter cycles the host filter: all hosts, then each host in turn.
+func (m Model) nextHostFilter() string {
+	if len(m.hosts) == 0 {
+		return ""
+	}
+	if m.hostFilter == "" {
+		return m.hosts[0].Name
+	}
+	for i, h := range m.hosts {
+		if h.Name == m.hostFilter && i+1 < len(m.hosts) {
+			return m.hosts[i+1].Name
+		}
+	}
+	return ""
+}
+
+// hostCheck is the result of probing a single host.
+type hostCheck struct {
+	host    config.Host
+	version string
+	err     error
+}
+
+// TestHosts probes every configured host (or only those named) over SSH and
+// writes reachability and tmux version per host to w. It returns an error if
+// any host is unreachable.
+func TestHosts(w io.Writer, names []string) error {
+	cfg := config.Load()
+	if len(cfg.Hosts) == 0 {
+		fmt.Fprintf(w, "No hosts configured in %s\n", config.ConfigPath())
+		return nil
+	}
+
+	want := make(map[string]bool, len(names))
+	for _, n := range names {
+		want[n] = true
+	}
+	var hosts []config.Host
+	for _, h := range cfg.Hosts {
+		if len(want) == 0 || want[h.Name] {
+			hosts = append(hosts, h)
+		}
+	}
+	if len(hosts) == 0 {
+		return fmt.Errorf("no configured host matches %v", names)
+	}
+
+	// Probe in parallel; each ssh connect can take up to ConnectTimeout.
+	results := make([]hostCheck, len(hosts))
+	var wg sync.WaitGroup
+	for i, h := range hosts {
+		wg.Add(1)
+		go func(i int, h config.Host) {
+			defer wg.Done()
+			results[i] = hostCheck{host: h}
+			client, err := newHostClient(h)
+			if err != nil {
+				results[i].err = err
+				return
+			}
+			results[i].version, results[i].err = client.Version(context.Background())
+		}(i, h)
+	}
+	wg.Wait()
+
+	failed := 0
+	for _, r := range results {
+		if r.err != nil {
+			failed++
+			fmt.Fprintf(w, "✗ %-16s %-28s unreachable: %v\n", r.host.Name, r.host.Address, r.err)
+			continue
+		}
+		fmt.Fprintf(w, "✓ %-16s %-28s %s\n", r.host.Name, r.host.Address, r.version)
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d host(s) unreachable", failed, len(results))
+	}
+	return nil
+}
+
+// AttachLineMsg reports copying Line, the command attaching to a session,
+// to the clipboard.
+type AttachLineMsg struct {
+	Line string
+	Err  error
+}
+
+// copyAttachLine copies the command attaching to s (c) to the clipboard,
+// with ssh for a session on another host, to paste into another terminal
+// or hand to a teammate. The clipboard tool runs in the background, so a
+// slow one does not hold up the dashboard.
+func (m Model) copyAttachLine(s session.Session) (Model, tea.Cmd) {
+	if !s.Managed {
+		m.err = fmt.Errorf("terminal sessions cannot be attached to (not a tmux session)")
+		return m, nil
+	}
+	client := m.client
+	if s.Host != "" {
+		r, err := m.remote(s.Host)
+		if err != nil {
+			m.err = err
+			return m, nil
+		}
+		client = r.client
+	}
+	if client == nil {
+		m.err = session.ErrNoTmux
+		return m, nil
+	}
+	line := client.AttachLine(s.Name)
+	return m, func() tea.Msg {
+		return AttachLineMsg{Line: line, Err: copyToClipboard(line)}
+	}
+}
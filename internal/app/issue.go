@@ -0,0 +1,119 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"runtime"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/seunggabi/claude-dashboard/internal/config"
+	"github.com/seunggabi/claude-dashboard/internal/git"
+	"github.com/seunggabi/claude-dashboard/internal/session"
+)
+
+// IssueMsg signals the issue of a session was set.
+type IssueMsg struct {
+	Err error
+}
+
+// issueOf returns the issue of s outside the dashboard: the one set on it,
+// or the one in the name of its branch.
+func issueOf(ctx context.Context, cfg *config.Config, s session.Session) string {
+	if s.Issue != "" || s.Path == "" {
+		return s.Issue
+	}
+	status, err := git.Read(ctx, s.Path)
+	if err != nil {
+		return ""
+	}
+	return session.FindIssue(status.Branch, cfg.Issues.Projects)
+}
+
+// startIssue opens the issue input (I) for s, filled with its issue key.
+func (m Model) startIssue(s session.Session) (Model, tea.Cmd) {
+	if !s.Managed {
+		m.err = fmt.Errorf("terminal sessions cannot be linked to an issue (not a tmux session)")
+		return m, nil
+	}
+	m.issueEditing = true
+	m.issueTarget = s
+	m.issueInput.SetValue(s.Issue)
+	m.issueInput.CursorEnd()
+	return m, m.issueInput.Focus()
+}
+
+func (m Model) handleIssueKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "enter":
+		key, err := session.ParseIssue(m.issueInput.Value())
+		if err != nil {
+			m.err = err
+			return m, nil
+		}
+		m.issueEditing = false
+		m.issueInput.Blur()
+		return m, m.setIssue(m.issueTarget, key)
+	case "esc":
+		m.issueEditing = false
+		m.issueInput.Blur()
+		return m, nil
+	}
+
+	var cmd tea.Cmd
+	m.issueInput, cmd = m.issueInput.Update(msg)
+	return m, cmd
+}
+
+// setIssue sets the issue key of s in the background.
+func (m Model) setIssue(s session.Session, key string) tea.Cmd {
+	return func() tea.Msg {
+		mgr, err := m.managerFor(s.Host)
+		if err != nil {
+			return IssueMsg{Err: err}
+		}
+		return IssueMsg{Err: mgr.SetIssue(context.Background(), s.Name, key)}
+	}
+}
+
+// openIssue opens the issue of s (o) in the browser, or copies its link
+// when no browser can be started, as over SSH.
+func (m Model) openIssue(s session.Session) (Model, tea.Cmd) {
+	if s.Issue == "" {
+		m.err = fmt.Errorf("%s is not linked to an issue (I to set one)", s.DisplayName())
+		return m, nil
+	}
+	url := m.cfg.Issues.Link(s.Issue)
+	if url == "" {
+		m.err = fmt.Errorf("set issues.url in the config to open %s", s.Issue)
+		return m, nil
+	}
+	if err := openURL(url); err == nil {
+		m.notice = "Opened " + s.Issue
+		return m, nil
+	}
+	if err := copyToClipboard(url); err != nil {
+		m.err = fmt.Errorf("failed to open %s: %w", url, err)
+		return m, nil
+	}
+	m.notice = "Copied " + url
+	return m, nil
+}
+
+// openURL opens url with the system's handler for links.
+func openURL(url string) error {
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "darwin":
+		cmd = exec.Command("open", url)
+	case "windows":
+		cmd = exec.Command("rundll32", "url.dll,FileProtocolHandler", url)
+	default:
+		cmd = exec.Command("xdg-open", url)
+	}
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+	go cmd.Wait()
+	return nil
+}
@@ -0,0 +1,79 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/seunggabi/claude-dashboard/internal/archive"
+	"github.com/seunggabi/claude-dashboard/internal/git"
+	"github.com/seunggabi/claude-dashboard/internal/session"
+	"github.com/seunggabi/claude-dashboard/internal/ui"
+)
+
+// The actions of the kill menu (K), by the key picking them.
+const (
+	killPlain    = "y"
+	killArchive  = "a"
+	killWorktree = "w"
+)
+
+// confirmKill asks how to kill s: a plain y/n prompt, or a menu when s can
+// also be archived first or has a linked worktree to remove afterwards.
+// Both are only offered for local sessions.
+func (m Model) confirmKill(s session.Session) Model {
+	m.killTarget = s
+	prompt := fmt.Sprintf("Kill session '%s'?", s.Name)
+	choices := []ui.ConfirmChoice{{Key: killPlain, Label: "Kill"}}
+	if s.Host == "" && m.client != nil {
+		choices = append(choices, ui.ConfirmChoice{Key: killArchive, Label: "Archive, then kill (it can be brought back from the archive)"})
+	}
+	if s.Host == "" && git.IsLinkedWorktree(s.Path) {
+		choices = append(choices, ui.ConfirmChoice{Key: killWorktree, Label: "Kill, then remove worktree " + s.Path})
+	}
+	if len(choices) == 1 {
+		m.confirm = ui.NewYesNo(prompt)
+	} else {
+		m.confirm = ui.NewConfirmMenu(prompt, choices)
+	}
+	return m
+}
+
+// killWith runs the kill menu action picked for s. A graceful kill takes a
+// moment, so a notice says it has started.
+func (m Model) killWith(s session.Session, action string) (tea.Model, tea.Cmd) {
+	m.notice = "Asking claude in " + s.Name + " to exit..."
+	switch action {
+	case killArchive:
+		return m, m.archiveAndKill(s)
+	case killWorktree:
+		return m, m.killAndRemoveWorktree(s)
+	}
+	return m, m.killSession(s)
+}
+
+// archiveAndKill archives s, as archive_after would, which kills it.
+func (m Model) archiveAndKill(s session.Session) tea.Cmd {
+	mgr := m.manager
+	return func() tea.Msg {
+		_, err := mgr.Archive(context.Background(), s, archive.Dir(), time.Now())
+		return KillMsg{Name: s.Name, Archived: err == nil, Err: err}
+	}
+}
+
+// killAndRemoveWorktree kills s and then removes the worktree it ran in,
+// which git refuses while it has changed or untracked files.
+func (m Model) killAndRemoveWorktree(s session.Session) tea.Cmd {
+	mgr := m.manager
+	return func() tea.Msg {
+		ctx := context.Background()
+		if err := mgr.Kill(ctx, s.Name); err != nil {
+			return KillMsg{Name: s.Name, Err: err}
+		}
+		if err := git.RemoveWorktree(ctx, s.Path); err != nil {
+			return KillMsg{Name: s.Name, Err: fmt.Errorf("killed %s, but its worktree was kept: %w", s.Name, err)}
+		}
+		return KillMsg{Name: s.Name, Worktree: s.Path}
+	}
+}
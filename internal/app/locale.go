@@ -0,0 +1,17 @@
+package app
+
+import (
+	"github.com/seunggabi/claude-dashboard/internal/config"
+	"github.com/seunggabi/claude-dashboard/internal/locale"
+)
+
+// applyLocale sets how numbers, dates and costs are written: in the locale
+// of the config, else of the environment, and in its currency.
+func applyLocale(cfg *config.Config) {
+	l := locale.FromEnv()
+	if cfg.Locale != "" {
+		l, _ = locale.Parse(cfg.Locale) // config.Load drops unknown locales
+	}
+	c, _ := cfg.Currency.Resolve() // and invalid currencies
+	locale.Set(l, c)
+}
@@ -0,0 +1,169 @@
+package app
+
+import (
+	"os"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/seunggabi/claude-dashboard/internal/conversation"
+	"github.com/seunggabi/claude-dashboard/internal/monitor"
+	"github.com/seunggabi/claude-dashboard/internal/session"
+	"github.com/seunggabi/claude-dashboard/internal/ui"
+)
+
+// MonitorMsg carries the conversation messages plotted as token rate in the
+// monitor view. Messages is nil when the log could not be read.
+type MonitorMsg struct {
+	Messages []conversation.Message
+}
+
+// historyKey identifies a session in the sample history. Names are only
+// unique per host.
+func historyKey(s session.Session) string {
+	return s.Host + "/" + s.Name
+}
+
+// activityRefreshes is how many refreshes the activity column can show.
+const activityRefreshes = 32
+
+// recordSamples adds the current state of every session of host to the
+// history. Sessions that are gone are forgotten on their SessionRemoved
+// event, and pruned here too, as events are dropped for a subscriber that
+// falls behind.
+func (m Model) recordSamples(now time.Time, host string) {
+	activity := m.cfg.HasColumn("activity")
+	live := make(map[string]bool)
+	for i, s := range m.sessions {
+		if s.Host != host {
+			continue
+		}
+		live[historyKey(s)] = true
+		m.history.Record(historyKey(s), monitor.Sample{
+			Time:     now,
+			CPU:      s.CPU,
+			Memory:   s.Memory,
+			Status:   string(s.Status),
+			Attached: s.Attached,
+			Activity: s.Activity,
+			LogSize:  s.LogSize,
+		})
+		if activity {
+			m.sessions[i].Output = nil
+			for _, sample := range m.history.Last(historyKey(s), activityRefreshes) {
+				m.sessions[i].Output = append(m.sessions[i].Output, sample.Output)
+			}
+		}
+	}
+	m.history.Prune(host+"/", live)
+}
+
+// logSize returns the size of the current conversation log of s; 0 for
+// remote sessions and those without a log.
+func logSize(s session.Session) int64 {
+	if s.Host != "" || s.Path == "" {
+		return 0
+	}
+	path, err := s.Log()
+	if err != nil {
+		return 0
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return 0
+	}
+	return info.Size()
+}
+
+// monitorWindow returns the time span the monitor view shows.
+func (m Model) monitorWindow() time.Duration {
+	return ui.MonitorWindows[m.monitorWindowIdx%len(ui.MonitorWindows)]
+}
+
+// monitorData collects what the monitor view plots for its target session.
+func (m Model) monitorData() ui.MonitorData {
+	now := time.Now()
+	window := m.monitorWindow()
+	var samples []monitor.Sample
+	if m.monitorTarget.Host == "" { // no CPU or memory readings for remote sessions
+		samples = m.history.Since(historyKey(m.monitorTarget), now.Add(-window))
+	}
+	return ui.MonitorData{
+		Session:  m.monitorTarget,
+		Samples:  samples,
+		Messages: m.monitorMsgs,
+		Window:   window,
+		Now:      now,
+	}
+}
+
+// fetchMonitorMessages reads the assistant messages of the monitor target
+// within the window, plus the minute before it that the rolling token rate
+// looks back over. Remote logs are not reachable, so they yield nil.
+func (m Model) fetchMonitorMessages() tea.Cmd {
+	s := m.monitorTarget
+	since := time.Now().Add(-m.monitorWindow() - time.Minute)
+	return func() tea.Msg {
+		if s.Host != "" || s.Path == "" {
+			return MonitorMsg{}
+		}
+		filter := conversation.Filter{Role: "assistant", Since: since}
+		msgs, _, err := m.manager.GetConversationMessages(s, 0, filter)
+		if err != nil {
+			return MonitorMsg{}
+		}
+		if msgs == nil {
+			msgs = []conversation.Message{}
+		}
+		return MonitorMsg{Messages: msgs}
+	}
+}
+
+// refreshMonitor rereads the token history while the monitor view is open.
+func (m Model) refreshMonitor() tea.Cmd {
+	if m.view != ViewMonitor {
+		return nil
+	}
+	return m.fetchMonitorMessages()
+}
+
+// pulseWindow returns the time span the pulse view shows.
+func (m Model) pulseWindow() time.Duration {
+	return ui.MonitorWindows[m.pulseWindowIdx%len(ui.MonitorWindows)]
+}
+
+// pulseData collects the recent history of every listed session.
+func (m Model) pulseData() ui.PulseData {
+	now := time.Now()
+	window := m.pulseWindow()
+	sessions := m.filteredSessions()
+	rows := make([]ui.PulseRow, len(sessions))
+	for i, s := range sessions {
+		rows[i] = ui.PulseRow{Session: s, Samples: m.history.Since(historyKey(s), now.Add(-window))}
+	}
+	return ui.PulseData{Rows: rows, Window: window, Now: now}
+}
+
+func (m Model) handleMonitorKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc":
+		m.view = ViewDashboard
+	case "q":
+		return m, tea.Quit
+	case "w":
+		m.monitorWindowIdx = (m.monitorWindowIdx + 1) % len(ui.MonitorWindows)
+		return m, m.fetchMonitorMessages()
+	}
+	return m, nil
+}
+
+func (m Model) handlePulseKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc":
+		m.view = ViewDashboard
+	case "q":
+		return m, tea.Quit
+	case "w":
+		m.pulseWindowIdx = (m.pulseWindowIdx + 1) % len(ui.MonitorWindows)
+	}
+	return m, nil
+}
@@ -0,0 +1,104 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/seunggabi/claude-dashboard/internal/config"
+	"github.com/seunggabi/claude-dashboard/internal/session"
+	"github.com/seunggabi/claude-dashboard/internal/tmux"
+)
+
+// namingPolicy returns the session naming policy of cfg, or nil for none.
+func namingPolicy(cfg *config.Config) *config.NamingPolicy {
+	p, _ := cfg.Naming.Policy(session.SessionPrefix) // config.Load drops invalid policies
+	return p
+}
+
+// checkName returns an error, with names that would do, when a session
+// called name in dir would break the naming policy.
+func checkName(p *config.NamingPolicy, name, dir string) error {
+	if p == nil {
+		return nil
+	}
+	err := p.Check(name)
+	if err == nil {
+		return nil
+	}
+	if names := p.Suggest(name, dir); len(names) > 0 {
+		return fmt.Errorf("%w; try %s", err, strings.Join(names, ", "))
+	}
+	return err
+}
+
+// DefaultName names a session after its directory: the path below the home
+// directory with dashes for slashes, e.g. ~/project/foo → project-foo.
+func DefaultName(path string) string {
+	homeDir, _ := os.UserHomeDir()
+	rel := path
+	if strings.HasPrefix(path, homeDir) {
+		rel = strings.TrimPrefix(path, homeDir)
+		rel = strings.TrimPrefix(rel, "/")
+	}
+	if name := strings.ReplaceAll(rel, "/", "-"); name != "" {
+		return name
+	}
+	return filepath.Base(path)
+}
+
+// CheckSessionName returns an error when creating a session called name in
+// dir from the CLI would break the naming policy.
+func CheckSessionName(name, dir string) error {
+	return checkName(namingPolicy(config.Load()), name, dir)
+}
+
+// LintSessions writes the managed sessions on this machine and every
+// configured host whose names break the naming policy to w, with names
+// that would follow it. It fails when there are any, or no policy is set.
+func LintSessions(w io.Writer) error {
+	cfg := config.Load()
+	p := namingPolicy(cfg)
+	if p == nil {
+		return fmt.Errorf("no naming policy in %s", config.ConfigPath())
+	}
+
+	client, err := tmux.NewClient()
+	if err != nil {
+		client = nil // remote sessions can still be checked
+	}
+	ctx := context.Background()
+	sessions, err := session.NewManager(client).List(ctx)
+	if err != nil {
+		return err
+	}
+	remote, _ := listRemoteSessions(ctx, newRemoteHosts(cfg.Hosts))
+	sessions = append(sessions, remote...)
+
+	checked, bad := 0, 0
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	for _, s := range sessions {
+		if !s.Managed {
+			continue // only managed sessions are named here
+		}
+		checked++
+		if p.Check(s.DisplayName()) == nil {
+			continue
+		}
+		if bad == 0 {
+			fmt.Fprintln(tw, "NAME\tSUGGESTED")
+		}
+		bad++
+		fmt.Fprintf(tw, "%s\t%s\n", qualifiedName(s), strings.Join(p.Suggest(s.DisplayName(), s.Path), ", "))
+	}
+	tw.Flush()
+	if bad > 0 {
+		return fmt.Errorf("%d of %d sessions break the naming policy %s", bad, checked, p)
+	}
+	fmt.Fprintf(w, "All %d sessions follow the naming policy %s\n", checked, p)
+	return nil
+}
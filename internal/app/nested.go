@@ -0,0 +1,96 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/seunggabi/claude-dashboard/internal/session"
+	"github.com/seunggabi/claude-dashboard/internal/tmux"
+)
+
+// nesting is where the dashboard runs in tmux. Inside a pane, attaching
+// would nest tmux in itself, so the dashboard switches its client to the
+// session instead, and leaves the session of its own pane alone.
+type nesting struct {
+	inside  bool
+	session string // session of the dashboard's pane; empty when unknown
+}
+
+// detectNesting finds out whether the dashboard runs in a pane of client's
+// tmux server, and in which session.
+func detectNesting(client *tmux.Client) nesting {
+	if client == nil || !tmux.Inside() {
+		return nesting{}
+	}
+	name, _ := client.CurrentSession(context.Background())
+	return nesting{inside: true, session: name}
+}
+
+// isOwn reports whether s is the session the dashboard's pane belongs to.
+func (n nesting) isOwn(s session.Session) bool {
+	return n.session != "" && s.Host == "" && s.Name == n.session
+}
+
+// banner explains the safe mode in the title bar; empty outside tmux.
+func (n nesting) banner() string {
+	switch {
+	case !n.inside:
+		return ""
+	case strings.HasPrefix(n.session, session.SessionPrefix):
+		return fmt.Sprintf("⚠ inside %s: enter switches client (prefix+L back), %s is protected", n.session, n.session)
+	case n.session != "":
+		return fmt.Sprintf("inside tmux (%s): enter switches client (prefix+L back)", n.session)
+	}
+	return "inside tmux: enter switches client (prefix+L back)"
+}
+
+// attachError returns why s cannot be attached from here, if it cannot.
+func (n nesting) attachError(s session.Session) error {
+	if n.isOwn(s) {
+		return fmt.Errorf("already inside %s", s.Name)
+	}
+	return nil
+}
+
+// SwitchMsg reports moving the dashboard's tmux client to a session.
+type SwitchMsg struct {
+	Name string
+	Err  error
+}
+
+// switchClient moves the tmux client the dashboard runs in to name.
+func (m Model) switchClient(name string) tea.Cmd {
+	return func() tea.Msg {
+		if m.client == nil {
+			return SwitchMsg{Name: name, Err: session.ErrNoTmux}
+		}
+		return SwitchMsg{Name: name, Err: m.client.SwitchClient(context.Background(), name)}
+	}
+}
+
+// killError returns why s cannot be killed from the dashboard, if it
+// cannot.
+func (m Model) killError(s session.Session) error {
+	if !s.Managed {
+		return fmt.Errorf("terminal sessions cannot be killed from dashboard")
+	}
+	if m.nesting.isOwn(s) {
+		return fmt.Errorf("the dashboard is running inside %s; quit it before killing that session", s.Name)
+	}
+	return nil
+}
+
+// ChooseSession opens tmux's choose-tree in the current pane, listing only
+// the sessions the dashboard manages.
+func ChooseSession() error {
+	if !tmux.Inside() {
+		return fmt.Errorf("choose opens tmux's own picker and needs to run inside tmux; outside it, use the dashboard or attach")
+	}
+	client, err := tmux.NewClient()
+	if err != nil {
+		return err
+	}
+	return client.ChooseTree(context.Background(), session.TreeFilter)
+}
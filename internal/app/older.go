@@ -0,0 +1,65 @@
+package app
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/seunggabi/claude-dashboard/internal/conversation"
+)
+
+// olderPage is how many messages scrolling to the top of a conversation
+// reads at a time.
+const olderPage = 50
+
+// OlderMsg carries a page of messages from before those shown in the
+// conversation viewer.
+type OlderMsg struct {
+	Log  string
+	Page conversation.Page
+	Err  error
+}
+
+// fetchOlder reads the page before the oldest message shown once the
+// conversation viewer is scrolled to its top. Pages are read backwards from
+// where the last one started, so the log is not parsed again from the
+// start each time.
+func (m *Model) fetchOlder() tea.Cmd {
+	if m.view != ViewLogs || !m.logView.WantsOlder() {
+		return nil
+	}
+	log, end := m.logView.OlderFrom()
+	shown := len(m.logView.Messages)
+	f := m.logView.Filter
+	s, file := m.logSession, m.logFile
+	return func() tea.Msg {
+		if log == "" {
+			// The first page: find where the messages shown start.
+			log = file
+			if log == "" {
+				var err error
+				if log, err = s.Log(); err != nil {
+					return OlderMsg{Err: err}
+				}
+			}
+			p, err := conversation.ReadPage(log, -1, shown, f)
+			if err != nil {
+				return OlderMsg{Err: err}
+			}
+			end = p.Start
+		}
+		p, err := conversation.ReadPage(log, end, olderPage, f)
+		return OlderMsg{Log: log, Page: p, Err: err}
+	}
+}
+
+// handleOlder shows a page read by fetchOlder.
+func (m Model) handleOlder(msg OlderMsg) (tea.Model, tea.Cmd) {
+	if msg.Err != nil {
+		m.logView.OlderFailed()
+		m.err = msg.Err
+		return m, nil
+	}
+	if m.view != ViewLogs || !m.logView.LoadingOlder() {
+		return m, nil // the viewer was closed or reloaded meanwhile
+	}
+	m.logView.PrependMessages(msg.Log, msg.Page)
+	return m, nil
+}
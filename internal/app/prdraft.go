@@ -0,0 +1,134 @@
+package app
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/seunggabi/claude-dashboard/internal/config"
+	"github.com/seunggabi/claude-dashboard/internal/conversation"
+	"github.com/seunggabi/claude-dashboard/internal/session"
+)
+
+const (
+	// prDraftTimeout bounds the claude -p call drafting a PR description.
+	prDraftTimeout = 3 * time.Minute
+
+	// prTranscriptLimit is how much of a conversation is sent to draft from.
+	prTranscriptLimit = 200 * 1024
+)
+
+// PRDraftMsg carries a PR description drafted from a session's
+// conversation, copied to the clipboard.
+type PRDraftMsg struct {
+	Name string
+	Err  error
+}
+
+// draftPR asks a one-shot claude -p to describe the work of the current
+// conversation of s as a pull request. It runs in a temporary directory,
+// not that of s, so its own conversation is not logged among those of the
+// project, where it would pass for the latest one of s. An issue the
+// session is linked to is passed on, so the description can refer to it.
+func draftPR(ctx context.Context, s session.Session, issue string) (string, error) {
+	if s.Host != "" {
+		return "", fmt.Errorf("conversation logs of remote sessions cannot be read")
+	}
+	if s.Path == "" {
+		return "", fmt.Errorf("no working directory for session %s", s.Name)
+	}
+	log, err := s.Log()
+	if err != nil {
+		return "", err
+	}
+	entries, err := conversation.ReadEntries(log)
+	if err != nil {
+		return "", err
+	}
+	if len(entries) == 0 {
+		return "", fmt.Errorf("no conversation messages found for %s", s.Name)
+	}
+	if _, err := exec.LookPath("claude"); err != nil {
+		return "", fmt.Errorf("drafting needs the claude CLI: %w", err)
+	}
+
+	prompt := conversation.PRPrompt
+	if issue != "" {
+		prompt += "\nThe work is for issue " + issue + "; mention it."
+	}
+	dir, err := os.MkdirTemp("", "claude-dashboard-pr-")
+	if err != nil {
+		return "", err
+	}
+	defer os.RemoveAll(dir)
+	ctx, cancel := context.WithTimeout(ctx, prDraftTimeout)
+	defer cancel()
+	cmd := exec.CommandContext(ctx, "claude", "-p", prompt)
+	cmd.Dir = dir
+	cmd.Stdin = strings.NewReader(conversation.PRTranscript(entries, prTranscriptLimit))
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return "", fmt.Errorf("claude -p did not answer within %s", prDraftTimeout)
+	}
+	if err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return "", fmt.Errorf("claude -p: %s", msg)
+		}
+		return "", fmt.Errorf("claude -p: %w", err)
+	}
+	draft := strings.TrimSpace(string(out))
+	if draft == "" {
+		return "", fmt.Errorf("claude -p returned nothing")
+	}
+	return draft + "\n", nil
+}
+
+// draftPRToClipboard drafts a PR description from the conversation of s
+// (D) and copies it to the clipboard.
+func (m Model) draftPRToClipboard(s session.Session) tea.Cmd {
+	cfg := m.cfg
+	return func() tea.Msg {
+		ctx := context.Background()
+		draft, err := draftPR(ctx, s, issueOf(ctx, cfg, s))
+		if err == nil {
+			err = copyToClipboard(draft)
+		}
+		return PRDraftMsg{Name: s.Name, Err: err}
+	}
+}
+
+// WritePRDraft drafts a PR description from the conversation of the named
+// session (with or without the cd- prefix) and writes it to out, or w when
+// out is empty, from the CLI.
+func WritePRDraft(w io.Writer, name, out string) error {
+	if strings.Contains(name, ":") {
+		return fmt.Errorf("conversation logs of remote sessions cannot be read")
+	}
+	s, err := localSession(name)
+	if err != nil {
+		return err
+	}
+	ctx := context.Background()
+	draft, err := draftPR(ctx, s, issueOf(ctx, config.Load(), s))
+	if err != nil {
+		return err
+	}
+	if out == "" {
+		_, err := io.WriteString(w, draft)
+		return err
+	}
+	if err := os.WriteFile(out, []byte(draft), 0644); err != nil {
+		return err
+	}
+	fmt.Fprintf(os.Stderr, "PR description written to %s\n", out)
+	return nil
+}
@@ -0,0 +1,120 @@
+package app
+
+import (
+	"context"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/seunggabi/claude-dashboard/internal/conversation"
+	"github.com/seunggabi/claude-dashboard/internal/ui"
+)
+
+const (
+	// previewThrottle is how long the highlight must rest on a session
+	// before its preview is fetched, and the least time between refreshes,
+	// so scrolling through the list does not capture every pane on the way.
+	previewThrottle = 300 * time.Millisecond
+
+	previewLines    = 100 // pane lines captured for the preview
+	previewMessages = 10  // conversation messages read for the preview
+	previewRatio    = 0.45
+)
+
+// PreviewDueMsg fires when the highlight has rested for previewThrottle.
+type PreviewDueMsg struct {
+	Seq int
+}
+
+// PreviewMsg carries a fetched preview for the session with Key.
+type PreviewMsg struct {
+	Key      string // historyKey of the previewed session
+	Content  string
+	Messages []conversation.Message
+	Err      error
+}
+
+// togglePreview opens or closes the preview pane, fetching the highlighted
+// session at once when it opens.
+func (m Model) togglePreview() (tea.Model, tea.Cmd) {
+	m.previewOpen = !m.previewOpen
+	m.preview = ui.PreviewData{}
+	if !m.previewOpen {
+		return m, nil
+	}
+	s, ok := m.detailSession()
+	if !ok {
+		return m, nil
+	}
+	m.preview = ui.PreviewData{Session: &s, Loading: true}
+	return m, m.fetchPreview()
+}
+
+// followSelection points the preview at the highlighted session after cmd,
+// the result of handling a key, and schedules its fetch once the highlight
+// rests there.
+func (m Model) followSelection(cmd tea.Cmd) (tea.Model, tea.Cmd) {
+	if !m.previewOpen || m.view != ViewDashboard {
+		return m, cmd
+	}
+	s, ok := m.detailSession()
+	switch {
+	case !ok:
+		m.preview = ui.PreviewData{}
+		return m, cmd
+	case m.preview.Session != nil && historyKey(*m.preview.Session) == historyKey(s):
+		m.preview.Session = &s // same session, fresher state
+		return m, cmd
+	}
+	m.preview = ui.PreviewData{Session: &s, Loading: true}
+	m.previewSeq++
+	seq := m.previewSeq
+	due := tea.Tick(previewThrottle, func(time.Time) tea.Msg { return PreviewDueMsg{Seq: seq} })
+	return m, tea.Batch(cmd, due)
+}
+
+// refreshPreview refetches the preview on a refresh, unless it was fetched
+// within previewThrottle.
+func (m Model) refreshPreview() tea.Cmd {
+	if !m.previewOpen || m.view != ViewDashboard || time.Since(m.previewFetched) < previewThrottle {
+		return nil
+	}
+	return m.fetchPreview()
+}
+
+// fetchPreview captures the pane of the previewed session, or reads its
+// last messages when it is not a tmux session.
+func (m Model) fetchPreview() tea.Cmd {
+	if m.preview.Session == nil {
+		return nil
+	}
+	s := *m.preview.Session
+	key := historyKey(s)
+	return func() tea.Msg {
+		if !s.Managed {
+			msgs, _, err := m.manager.GetConversationMessages(s, previewMessages, conversation.Filter{})
+			if err == nil && msgs == nil {
+				msgs = []conversation.Message{}
+			}
+			return PreviewMsg{Key: key, Messages: msgs, Err: err}
+		}
+		mgr, err := m.managerFor(s.Host)
+		if err != nil {
+			return PreviewMsg{Key: key, Err: err}
+		}
+		content, err := mgr.GetLogs(context.Background(), s.Name, previewLines)
+		return PreviewMsg{Key: key, Content: content, Err: err}
+	}
+}
+
+// showPreview stores a fetched preview if it is for the previewed session.
+func (m Model) showPreview(msg PreviewMsg) Model {
+	if m.preview.Session == nil || historyKey(*m.preview.Session) != msg.Key {
+		return m
+	}
+	m.preview.Content = msg.Content
+	m.preview.Messages = msg.Messages
+	m.preview.Err = msg.Err
+	m.preview.Loading = false
+	m.previewFetched = time.Now()
+	return m
+}
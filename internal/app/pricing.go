@@ -0,0 +1,166 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"text/tabwriter"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/seunggabi/claude-dashboard/internal/config"
+	"github.com/seunggabi/claude-dashboard/internal/conversation"
+)
+
+const (
+	// pricingMaxAge is how old the downloaded price table may get before
+	// the dashboard fetches a new one in the background.
+	pricingMaxAge = 7 * 24 * time.Hour
+
+	// pricingTimeout bounds a price table download.
+	pricingTimeout = 10 * time.Second
+
+	// maxPricingSize caps the size of a downloaded price table.
+	maxPricingSize = 1 << 20
+)
+
+// PricingMsg carries a price table downloaded in the background. On error
+// the prices in use are kept, so costs work offline.
+type PricingMsg struct {
+	Table conversation.PriceTable
+	Err   error
+}
+
+// pricingCachePath returns where the downloaded price table is kept.
+func pricingCachePath() string {
+	return filepath.Join(config.ConfigDir(), "pricing.yaml")
+}
+
+// loadPricing sets the prices in use: the bundled table, overlaid by the
+// downloaded one and then by the overrides in cfg. A missing or broken
+// download leaves the bundled prices. It reports whether the download is
+// missing or older than pricingMaxAge.
+func loadPricing(cfg *config.Config) (stale bool) {
+	cached, updated, err := readPricingCache()
+	if err != nil {
+		conversation.SetPrices(overridePrices(cfg))
+		return true
+	}
+	conversation.SetPrices(cached, overridePrices(cfg))
+	return time.Since(updated) > pricingMaxAge
+}
+
+// readPricingCache returns the downloaded price table and when it was
+// downloaded.
+func readPricingCache() (conversation.PriceTable, time.Time, error) {
+	path := pricingCachePath()
+	info, err := os.Stat(path)
+	if err != nil {
+		return conversation.PriceTable{}, time.Time{}, err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return conversation.PriceTable{}, time.Time{}, err
+	}
+	table, err := conversation.ParsePriceTable(data)
+	return table, info.ModTime(), err
+}
+
+// overridePrices returns the pricing overrides of cfg as a price table.
+func overridePrices(cfg *config.Config) conversation.PriceTable {
+	t := conversation.PriceTable{Models: make(map[string]conversation.Price, len(cfg.Pricing))}
+	for model, p := range cfg.Pricing {
+		t.Models[model] = conversation.Price{Input: p.Input, Output: p.Output, CacheWrite: p.CacheWrite, CacheRead: p.CacheRead}
+	}
+	return t
+}
+
+// downloadPricing fetches the price table at url and, if it is valid,
+// saves it as the downloaded table.
+func downloadPricing(ctx context.Context, url string) (conversation.PriceTable, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return conversation.PriceTable{}, err
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return conversation.PriceTable{}, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return conversation.PriceTable{}, fmt.Errorf("fetching %s: %s", url, resp.Status)
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxPricingSize))
+	if err != nil {
+		return conversation.PriceTable{}, err
+	}
+	table, err := conversation.ParsePriceTable(data)
+	if err != nil {
+		return conversation.PriceTable{}, fmt.Errorf("%s: %w", url, err)
+	}
+
+	path := pricingCachePath()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return table, err
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return table, err
+	}
+	return table, os.Rename(tmp, path)
+}
+
+// fetchPricing downloads the price table in the background.
+func (m Model) fetchPricing() tea.Msg {
+	ctx, cancel := context.WithTimeout(context.Background(), pricingTimeout)
+	defer cancel()
+	table, err := downloadPricing(ctx, m.cfg.PricingURL)
+	return PricingMsg{Table: table, Err: err}
+}
+
+// UpdatePricing downloads the price table from the configured pricing_url
+// for the pricing update command.
+func UpdatePricing(w io.Writer) error {
+	cfg := config.Load()
+	ctx, cancel := context.WithTimeout(context.Background(), pricingTimeout)
+	defer cancel()
+	table, err := downloadPricing(ctx, cfg.PricingURL)
+	if err != nil {
+		return fmt.Errorf("%w (the current prices stay in use)", err)
+	}
+	fmt.Fprintf(w, "Updated prices of %d models (checked %s) from %s\n", len(table.Models), table.Updated, cfg.PricingURL)
+	return nil
+}
+
+// ShowPricing writes the prices in use and where they come from.
+func ShowPricing(w io.Writer) error {
+	cfg := config.Load()
+	loadPricing(cfg)
+
+	source := "bundled table of " + conversation.BundledPrices().Updated
+	if cached, updated, err := readPricingCache(); err == nil {
+		source = fmt.Sprintf("downloaded table of %s (fetched %s)", cached.Updated, updated.Format("2006-01-02"))
+	}
+	if len(cfg.Pricing) > 0 {
+		source += fmt.Sprintf(", %d override(s) from %s", len(cfg.Pricing), config.ConfigPath())
+	}
+	fmt.Fprintf(w, "Prices in USD per million tokens; %s\n\n", source)
+
+	prices := conversation.Prices()
+	models := make([]string, 0, len(prices))
+	for model := range prices {
+		models = append(models, model)
+	}
+	sort.Strings(models)
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "MODEL\tINPUT\tOUTPUT\tCACHE WRITE\tCACHE READ")
+	for _, model := range models {
+		p := prices[model]
+		fmt.Fprintf(tw, "%s\t%g\t%g\t%g\t%g\n", model, p.Input, p.Output, p.CacheWrite, p.CacheRead)
+	}
+	return tw.Flush()
+}
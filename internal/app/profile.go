@@ -0,0 +1,62 @@
+package app
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/seunggabi/claude-dashboard/internal/config"
+	"github.com/seunggabi/claude-dashboard/internal/ui"
+)
+
+// UseProfile makes this process, and the commands it starts, use the
+// config profile name.
+func UseProfile(name string) error {
+	if err := config.SetProfile(name); err != nil {
+		return err
+	}
+	return os.Setenv(config.ProfileEnv, name)
+}
+
+// chooseProfile opens the profile switcher (S): the profiles, numbered,
+// with the one in use first selected.
+func (m Model) chooseProfile() Model {
+	if m.demo != nil {
+		m.err = fmt.Errorf("the demo has no config profiles")
+		return m
+	}
+	profiles := config.Profiles()
+	if len(profiles) < 2 {
+		m.notice = fmt.Sprintf("No other profiles; add one as %s/NAME.yaml", config.ProfilesDir())
+		return m
+	}
+	profiles = profiles[:min(len(profiles), 9)]
+	choices := make([]ui.ConfirmChoice, len(profiles))
+	cursor := 0
+	for i, p := range profiles {
+		choices[i] = ui.ConfirmChoice{Key: strconv.Itoa(i + 1), Label: p}
+		if p == config.Profile() {
+			choices[i].Label += " (in use)"
+			cursor = i
+		}
+	}
+	m.profiles = profiles
+	m.confirm = ui.NewConfirmMenu("Switch config profile", choices)
+	m.confirm.Cursor = cursor
+	return m
+}
+
+// switchProfile restarts the dashboard with the picked profile, whose
+// hosts, theme and other settings need a fresh start, keeping the view.
+func (m Model) switchProfile(picked string) (tea.Model, tea.Cmd) {
+	i, err := strconv.Atoi(picked)
+	if err != nil || i < 1 || i > len(m.profiles) {
+		return m, nil
+	}
+	if name := m.profiles[i-1]; name != config.Profile() {
+		m.nextProfile = name
+		return m, tea.Quit
+	}
+	return m, nil
+}
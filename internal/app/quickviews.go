@@ -0,0 +1,56 @@
+package app
+
+import (
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/seunggabi/claude-dashboard/internal/config"
+	"github.com/seunggabi/claude-dashboard/internal/session"
+	"github.com/seunggabi/claude-dashboard/internal/ui"
+)
+
+// builtinViews are the quick views on keys 1-3; those of the config
+// follow on 4-9.
+var builtinViews = []config.QuickView{
+	{Name: "waiting", Filter: "status:waiting"},
+	{Name: "active", Filter: "status:active"},
+	{Name: "mine", Filter: "name:" + session.SessionPrefix}, // started from the dashboard
+}
+
+// quickViews returns the views the number keys switch to, in key order.
+func (m Model) quickViews() []config.QuickView {
+	return append(append([]config.QuickView(nil), builtinViews...), m.cfg.Views...)
+}
+
+// quickViewState returns the quick views for the status bar, marking the
+// one whose filter is applied.
+func (m Model) quickViewState() ui.QuickViews {
+	q := ui.QuickViews{Views: m.quickViews(), Active: -1}
+	query := strings.TrimSpace(m.filterQuery)
+	for i, v := range q.Views {
+		if query != "" && strings.TrimSpace(v.Filter) == query {
+			q.Active = i
+			break
+		}
+	}
+	return q
+}
+
+// switchQuickView applies the quick view of a number key; the key of the
+// view applied, or 0, clears the filter.
+func (m Model) switchQuickView(key string) (tea.Model, tea.Cmd) {
+	i := int(key[0]-'0') - 1
+	views := m.quickViews()
+	if i >= len(views) {
+		return m, nil
+	}
+	query := ""
+	if i >= 0 && i != m.quickViewState().Active {
+		query = views[i].Filter
+	}
+	m.filterQuery = query
+	m.filterText.SetValue(query)
+	m.cursor = 0
+	m.scrollOffset = 0
+	return m, nil
+}
@@ -0,0 +1,189 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/seunggabi/claude-dashboard/internal/config"
+	"github.com/seunggabi/claude-dashboard/internal/conversation"
+	"github.com/seunggabi/claude-dashboard/internal/monitor"
+	"github.com/seunggabi/claude-dashboard/internal/session"
+	"github.com/seunggabi/claude-dashboard/internal/tmux"
+)
+
+// refreshHookIndex is the slot of this dashboard's refresh hooks in tmux's
+// hook arrays, and refreshChannel the wait-for channel they signal. Both
+// are this process's own, so dashboards sharing a tmux server each get
+// their signals, and one quitting leaves the others' hooks in place.
+var (
+	refreshHookIndex = os.Getpid()
+	refreshChannel   = fmt.Sprintf("%s%d", refreshChannelPrefix, refreshHookIndex)
+)
+
+// refreshChannelPrefix starts the refresh channel of every dashboard,
+// followed by its pid.
+const refreshChannelPrefix = "claude-dashboard-refresh-"
+
+// sweepRefreshHooks removes the refresh hooks of dashboards that no longer
+// run, as when one was killed before it could remove its own, so they do
+// not pile up across restarts. Their slot is their pid. Nothing is removed
+// when the process table cannot be read.
+func sweepRefreshHooks(ctx context.Context, client *tmux.Client) {
+	if client.IsRemote() {
+		return
+	}
+	slots := client.RefreshHookSlots(ctx, refreshChannelPrefix)
+	if len(slots) == 0 {
+		return
+	}
+	procs := monitor.GetProcessTable()
+	if len(procs) == 0 {
+		return
+	}
+	for _, slot := range slots {
+		if _, running := procs[strconv.Itoa(slot)]; !running && slot != refreshHookIndex {
+			client.RemoveRefreshHooks(ctx, slot)
+		}
+	}
+}
+
+// startWatcher sets up event-driven refresh from conversation log writes and
+// tmux hooks. It returns nil when the config asks for polling or the watcher
+// cannot start (e.g. ~/.claude/projects does not exist yet); callers then
+// fall back to the fixed refresh interval.
+func startWatcher(cfg *config.Config, client *tmux.Client) *monitor.Watcher {
+	if cfg.RefreshMode != config.RefreshWatch {
+		return nil
+	}
+	root := conversation.ProjectsDir()
+	if root == "" {
+		return nil
+	}
+
+	// Hooks need a running tmux server; without one (or without tmux at all)
+	// only log writes are watched.
+	var waitFor func(context.Context) error
+	if client != nil {
+		sweepRefreshHooks(context.Background(), client)
+	}
+	if client != nil && client.InstallRefreshHooks(context.Background(), refreshChannel, refreshHookIndex) == nil {
+		waitFor = func(ctx context.Context) error {
+			return client.WaitFor(ctx, refreshChannel)
+		}
+	}
+
+	w, err := monitor.NewWatcher(root, waitFor)
+	if err != nil {
+		if waitFor != nil {
+			client.RemoveRefreshHooks(context.Background(), refreshHookIndex)
+		}
+		return nil
+	}
+	return w
+}
+
+// stopWatcher tears down the watcher and this dashboard's tmux hooks.
+func (m Model) stopWatcher() {
+	if m.watcher == nil {
+		return
+	}
+	_ = m.watcher.Close()
+	if m.client != nil {
+		m.client.RemoveRefreshHooks(context.Background(), refreshHookIndex)
+	}
+}
+
+// tickInterval is the periodic refresh interval: the configured one when
+// polling, or a slow safety net when change events drive refresh.
+func (m Model) tickInterval() time.Duration {
+	if m.watcher != nil {
+		return monitor.WatchFallbackInterval
+	}
+	return m.cfg.RefreshInterval
+}
+
+// waitForChange returns the command that delivers the next watcher event.
+func (m Model) waitForChange() tea.Cmd {
+	if m.watcher == nil {
+		return nil
+	}
+	return monitor.WaitCmd(m.watcher)
+}
+
+// refreshSessions lists the sessions of every host. Each host answers with
+// its own SessionsMsg, so local rows do not wait for slow remote hosts; a
+// host still answering the previous refresh is not asked again.
+func (m Model) refreshSessions() (Model, tea.Cmd) {
+	m.refreshing = true
+	cmds := []tea.Cmd{m.listLocal}
+	for _, r := range m.remotes {
+		if r.manager == nil || m.listing[r.name] {
+			continue
+		}
+		m.listing[r.name] = true
+		cmds = append(cmds, listHost(r, m.cfg.HasColumn("mode")))
+	}
+	return m, tea.Batch(cmds...)
+}
+
+// listHost lists the sessions of a remote host, reading their permission
+// modes from their panes with modes.
+func listHost(r remoteHost, modes bool) tea.Cmd {
+	return func() tea.Msg {
+		sessions, err := r.manager.List(context.Background())
+		if modes {
+			for i := range sessions {
+				r.manager.ReadMode(context.Background(), &sessions[i])
+			}
+		}
+		return SessionsMsg{Host: r.name, Sessions: sessions, Err: err}
+	}
+}
+
+// pendingHosts is the host rollup before any host answered; nil without
+// remote hosts.
+func pendingHosts(remotes []remoteHost) []session.HostStatus {
+	if len(remotes) == 0 {
+		return nil
+	}
+	hosts := []session.HostStatus{{Name: session.LocalHost, Pending: true}}
+	for _, r := range remotes {
+		hosts = append(hosts, session.HostStatus{Name: r.name, Err: r.err, Pending: r.err == nil})
+	}
+	return hosts
+}
+
+// setHostStatus records the answer of msg's host in the rollup.
+func (m Model) setHostStatus(msg SessionsMsg) {
+	for i := range m.hosts {
+		if m.hosts[i].Name == hostLabel(msg.Host) {
+			m.hosts[i] = session.HostStatus{Name: m.hosts[i].Name, Sessions: len(msg.Sessions), Err: msg.Err}
+		}
+	}
+}
+
+// mergeHost returns the listed sessions with those of host replaced by
+// sessions: local ones first, then each remote host in config order.
+func (m Model) mergeHost(host string, sessions []session.Session) []session.Session {
+	merged := make([]session.Session, 0, len(m.sessions)+len(sessions))
+	order := []string{""}
+	for _, r := range m.remotes {
+		order = append(order, r.name)
+	}
+	for _, h := range order {
+		if h == host {
+			merged = append(merged, sessions...)
+			continue
+		}
+		for _, s := range m.sessions {
+			if s.Host == h {
+				merged = append(merged, s)
+			}
+		}
+	}
+	return merged
+}
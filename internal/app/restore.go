@@ -0,0 +1,70 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/seunggabi/claude-dashboard/internal/session"
+	"github.com/seunggabi/claude-dashboard/internal/tmux"
+	"github.com/seunggabi/claude-dashboard/internal/ui"
+)
+
+// MissingMsg carries the saved sessions that are not running, found when
+// the user asks to restore.
+type MissingMsg struct {
+	Missing []session.Definition
+	Err     error
+}
+
+// RestoreSessions recreates saved sessions missing from tmux, from the CLI.
+func RestoreSessions(w io.Writer) error {
+	client, err := tmux.NewClient()
+	if err != nil {
+		return fmt.Errorf("tmux is required: %w", err)
+	}
+	result, err := session.NewManager(client).Restore(context.Background())
+	if err != nil {
+		return err
+	}
+	for _, name := range result.Succeeded() {
+		fmt.Fprintf(w, "Restored %s\n", name)
+	}
+	if len(result.Items) == 0 {
+		fmt.Fprintln(w, "All saved sessions are running.")
+	}
+	return result.Err()
+}
+
+// findMissing looks up the saved sessions that are not running.
+func (m Model) findMissing() tea.Cmd {
+	return func() tea.Msg {
+		missing, err := m.manager.MissingDefinitions(context.Background())
+		return MissingMsg{Missing: missing, Err: err}
+	}
+}
+
+// restoreSessions recreates the saved sessions that are not running.
+func (m Model) restoreSessions() tea.Cmd {
+	return func() tea.Msg {
+		result, err := m.manager.Restore(context.Background())
+		return BulkMsg{Result: result, Err: err}
+	}
+}
+
+// confirmRestore asks before recreating the missing sessions.
+func (m Model) confirmRestore(missing []session.Definition) Model {
+	if len(missing) == 0 {
+		m.notice = "All saved sessions are running"
+		return m
+	}
+	names := make([]string, len(missing))
+	for i, d := range missing {
+		names[i] = d.Name
+	}
+	m.confirm = ui.NewYesNo(fmt.Sprintf("Restore %d saved session(s): %s?", len(missing), strings.Join(names, ", ")))
+	m.restoring = true
+	return m
+}
@@ -0,0 +1,183 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/seunggabi/claude-dashboard/internal/session"
+	"github.com/seunggabi/claude-dashboard/internal/tmux"
+)
+
+// selftestMarker is printed by the selftest session, to find in its pane.
+const selftestMarker = "claude-dashboard-selftest"
+
+// selftestWait is how long the selftest waits for output to show in the
+// pane, checking every selftestPoll.
+const (
+	selftestWait = 5 * time.Second
+	selftestPoll = 100 * time.Millisecond
+)
+
+// errSkipped marks a selftest step not run because an earlier one failed.
+var errSkipped = errors.New("skipped")
+
+// selftest is a run of Selftest: a session on a scratch tmux server, so the
+// user's own server and saved sessions are left alone.
+type selftest struct {
+	client *tmux.Client
+	mgr    *session.Manager
+	name   string // full name of the session
+	dir    string // its working directory
+}
+
+// selftestStep is one check of Selftest.
+type selftestStep struct {
+	name string
+	run  func(t *selftest, ctx context.Context) (string, error)
+}
+
+// selftestSteps are run in order by Selftest; once one fails, the rest are
+// skipped, as each needs the ones before.
+var selftestSteps = []selftestStep{
+	{"tmux", (*selftest).version},
+	{"create", (*selftest).create},
+	{"list", (*selftest).list},
+	{"capture", (*selftest).capture},
+	{"send", (*selftest).send},
+	{"kill", (*selftest).kill},
+}
+
+// Selftest exercises what the dashboard does with tmux, end to end, on a
+// scratch tmux server of its own: creating a session, listing it, reading
+// its pane, typing into it and killing it. It writes one line per step to
+// w, the way Doctor does, and returns an error if any step fails. Nothing
+// needs claude, and the scratch server is killed at the end.
+func Selftest(w io.Writer) error {
+	ctx := context.Background()
+	t := &selftest{name: session.SessionPrefix + "selftest"}
+	var setupErr error
+	t.client, setupErr = tmux.NewSocketClient(fmt.Sprintf("claude-dashboard-selftest-%d", os.Getpid()))
+	if setupErr == nil {
+		t.mgr = session.NewManager(t.client)
+		t.mgr.SetDefinitionsPath("")
+		t.dir, setupErr = os.MkdirTemp("", "claude-dashboard-selftest-")
+	}
+	if setupErr == nil {
+		defer os.RemoveAll(t.dir)
+		defer t.client.KillServer(ctx)
+	}
+
+	failed := 0
+	for _, step := range selftestSteps {
+		start := time.Now()
+		detail, err := "", setupErr
+		switch {
+		case failed > 0:
+			err = errSkipped
+		case err == nil:
+			detail, err = step.run(t, ctx)
+		}
+		took := time.Since(start).Round(time.Millisecond)
+		switch {
+		case errors.Is(err, errSkipped):
+			fmt.Fprintf(w, "- %-8s skipped\n", step.name)
+		case err != nil:
+			failed++
+			fmt.Fprintf(w, "✗ %-8s %v\n", step.name, err)
+		default:
+			fmt.Fprintf(w, "✓ %-8s %s (%s)\n", step.name, detail, took)
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("selftest failed; 'claude-dashboard doctor' may say why")
+	}
+	return nil
+}
+
+func (t *selftest) version(ctx context.Context) (string, error) {
+	return t.client.Version(ctx)
+}
+
+// create starts the session on the scratch server, printing the marker and
+// then echoing what is typed, and labels it as Manager.Create does.
+func (t *selftest) create(ctx context.Context) (string, error) {
+	if err := t.client.NewSession(ctx, t.name, t.dir, "echo "+selftestMarker+"; exec cat"); err != nil {
+		return "", fmt.Errorf("cannot start a tmux server with a session: %w", err)
+	}
+	if err := t.client.Mark(ctx, t.name, "selftest"); err != nil {
+		return "", fmt.Errorf("cannot set session options: %w", err)
+	}
+	return "started " + t.name, nil
+}
+
+// list finds the session among those the dashboard detects.
+func (t *selftest) list(ctx context.Context) (string, error) {
+	sessions, err := t.mgr.List(ctx)
+	if err != nil {
+		return "", err
+	}
+	for _, s := range sessions {
+		if s.Name == t.name {
+			if !s.Managed || s.PID == "" {
+				return "", fmt.Errorf("%s listed without its tmux session or pane PID", t.name)
+			}
+			return fmt.Sprintf("found %s (pid %s) among %d sessions", t.name, s.PID, len(sessions)), nil
+		}
+	}
+	return "", fmt.Errorf("%s not among the %d sessions detected", t.name, len(sessions))
+}
+
+// capture reads the marker from the pane.
+func (t *selftest) capture(ctx context.Context) (string, error) {
+	if err := t.waitForPane(ctx, selftestMarker, 1); err != nil {
+		return "", err
+	}
+	return "read the pane", nil
+}
+
+// send types a line into the pane, as prompts are sent, and reads it back:
+// once as typed, once from cat.
+func (t *selftest) send(ctx context.Context) (string, error) {
+	line := "ping " + selftestMarker
+	if err := t.mgr.SendCommand(ctx, t.name, line); err != nil {
+		return "", err
+	}
+	if err := t.waitForPane(ctx, line, 2); err != nil {
+		return "", err
+	}
+	return "typed a line and read it back", nil
+}
+
+// kill kills the session and checks it is gone.
+func (t *selftest) kill(ctx context.Context) (string, error) {
+	if err := t.mgr.Kill(ctx, t.name); err != nil {
+		return "", err
+	}
+	if t.client.HasSession(ctx, t.name) {
+		return "", fmt.Errorf("%s still exists after being killed", t.name)
+	}
+	return "killed " + t.name, nil
+}
+
+// waitForPane waits up to selftestWait for text to show n times in the
+// pane.
+func (t *selftest) waitForPane(ctx context.Context, text string, n int) error {
+	deadline := time.Now().Add(selftestWait)
+	for {
+		pane, err := t.mgr.GetLogs(ctx, t.name, 100)
+		switch {
+		case err != nil:
+			return err
+		case strings.Count(pane, text) >= n:
+			return nil
+		case time.Now().After(deadline):
+			return fmt.Errorf("%q did not show in the pane within %s", text, selftestWait)
+		}
+		time.Sleep(selftestPoll)
+	}
+}
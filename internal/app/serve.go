@@ -0,0 +1,134 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/seunggabi/claude-dashboard/internal/config"
+	"github.com/seunggabi/claude-dashboard/internal/conversation"
+	"github.com/seunggabi/claude-dashboard/internal/git"
+	"github.com/seunggabi/claude-dashboard/internal/session"
+	"github.com/seunggabi/claude-dashboard/internal/tmux"
+	"github.com/seunggabi/claude-dashboard/internal/web"
+)
+
+// webControl is the control API's view of the manager: sessions it creates
+// follow the naming policy, like ones created from the CLI.
+type webControl struct {
+	*session.Manager
+	policy *config.NamingPolicy
+}
+
+func (c webControl) Create(ctx context.Context, name, projectDir, claudeArgs string) error {
+	if err := checkName(c.policy, name, projectDir); err != nil {
+		return err
+	}
+	return c.Manager.Create(ctx, name, projectDir, claudeArgs)
+}
+
+// ServeWeb serves the web dashboard on addr (e.g. ":8080") until
+// interrupted. With a token, requests must carry it as ?token= or a bearer
+// token. With control, which needs a token, the API can also create, kill
+// and prompt sessions on this machine. Configured triggers start sessions
+// for the signed webhooks matching them.
+func ServeWeb(w io.Writer, addr, token string, control bool) error {
+	cfg := config.Load()
+	client, err := tmux.NewClient()
+	if err != nil {
+		client = nil // terminal sessions are still shown
+	}
+	mgr := session.NewManager(client)
+	remotes := newRemoteHosts(cfg.Hosts)
+	logError := func(err error) {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+	}
+	stopWebhooks := startWebhooks(cfg.Webhooks, shareTracker(mgr, remotes), logError)
+	defer stopWebhooks()
+	gitCache := git.NewCache(gitCacheTTL)
+
+	list := func(ctx context.Context) ([]session.Session, error) {
+		sessions, err := mgr.List(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for i := range sessions {
+			if sessions[i].Path != "" {
+				if log, err := sessions[i].Log(); err == nil {
+					sessions[i].LastPrompt, _ = conversation.LastPromptIn(log)
+				}
+				sessions[i].Git, _ = gitCache.Get(ctx, sessions[i].Path)
+			}
+		}
+		remote, _ := listRemoteSessions(ctx, remotes)
+		return append(sessions, remote...), nil
+	}
+	tail := func(s session.Session, n int) ([]conversation.Message, error) {
+		if s.Host != "" {
+			return nil, fmt.Errorf("conversations of remote sessions are not read")
+		}
+		messages, _, err := mgr.GetConversationMessages(s, n, conversation.Filter{})
+		return messages, err
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	if cfg.ArchiveAfter > 0 && client != nil {
+		go runArchiver(ctx, mgr, cfg.ArchiveAfter, logError)
+	}
+	if sv := newSupervisor(cfg); sv != nil && client != nil {
+		go runSupervisor(ctx, mgr, sv, logError)
+	}
+	srv := web.New(list, tail, cfg.RefreshInterval, token)
+	if control {
+		if err := srv.EnableControl(webControl{mgr, namingPolicy(cfg)}); err != nil {
+			return fmt.Errorf("%w: set --token", err)
+		}
+	}
+	if len(cfg.Triggers) > 0 && client != nil {
+		srv.EnableTriggers(cfg.Triggers, webControl{mgr, namingPolicy(cfg)}, logError)
+	}
+	loadPricing(cfg)
+	applyLocale(cfg)
+	srv.EnableCalendar(func(ctx context.Context, w io.Writer) error {
+		now := time.Now()
+		return writeCalendar(ctx, w, now.AddDate(0, 0, -CalendarDays), now)
+	})
+	go srv.Run(ctx)
+
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+	httpSrv := &http.Server{Handler: srv.Handler(), ReadHeaderTimeout: 10 * time.Second}
+	go func() {
+		<-ctx.Done()
+		shutdown, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		httpSrv.Shutdown(shutdown)
+	}()
+
+	url := "http://" + ln.Addr().String()
+	if token != "" {
+		url += "/?token=" + token
+	}
+	mode := "read-only dashboard"
+	if control {
+		mode = "dashboard and control API"
+	}
+	fmt.Fprintf(w, "Serving the %s on %s (Ctrl+C to stop)\n", mode, url)
+	if len(cfg.Triggers) > 0 && client != nil {
+		fmt.Fprintf(w, "Accepting webhooks for %d trigger(s) at http://%s/hooks/NAME\n", len(cfg.Triggers), ln.Addr())
+	}
+	if err := httpSrv.Serve(ln); !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
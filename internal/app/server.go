@@ -0,0 +1,156 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/seunggabi/claude-dashboard/internal/session"
+	"github.com/seunggabi/claude-dashboard/internal/tmux"
+)
+
+// reconnectBase is the wait before the first probe of a lost tmux server,
+// doubled for each probe after up to reconnectMax; after reconnectAttempts
+// the dashboard gives up.
+const (
+	reconnectBase     = time.Second
+	reconnectMax      = 30 * time.Second
+	reconnectAttempts = 8
+)
+
+// ServerProbeMsg reports probe Attempt of the local tmux server: Err when
+// it still cannot be reached, else the saved sessions it took along.
+type ServerProbeMsg struct {
+	Attempt int
+	Missing []session.Definition
+	Err     error
+}
+
+// serverRecovery is the dashboard recovering from the local tmux server
+// going away, so the table does not just empty: it probes for the server
+// with backoff, then offers to restore the saved sessions it took along.
+type serverRecovery struct {
+	probing bool      // a probe is pending
+	lost    bool      // tmux reported the server lost; shown as a banner
+	attempt int       // probes that found it still lost
+	next    time.Time // when the next probe runs
+	running []string  // local tmux sessions at the last good listing
+}
+
+// vanishedAtOnce is how many local tmux sessions have to vanish in one
+// listing for the server to be taken for gone; a last session ending on
+// its own takes the server along too, and is no loss.
+const vanishedAtOnce = 2
+
+// reconnectDelay is the wait before probe attempt.
+func reconnectDelay(attempt int) time.Duration {
+	d := reconnectBase
+	for i := 1; i < attempt && d < reconnectMax; i++ {
+		d *= 2
+	}
+	return min(d, reconnectMax)
+}
+
+// banner describes the recovery under way, or "" when there is none to
+// show.
+func (r serverRecovery) banner(now time.Time) string {
+	if !r.lost {
+		return ""
+	}
+	wait := max(r.next.Sub(now).Round(time.Second), 0)
+	return fmt.Sprintf("⚠ The tmux server exited unexpectedly; reconnecting (attempt %d of %d, next in %s)", r.attempt+1, reconnectAttempts, wait)
+}
+
+// watchServer starts recovering when a local listing shows the tmux server
+// gone: reported lost, or vanishedAtOnce or more tmux sessions vanished in
+// one listing, as when the server crashed and took its socket along. Only
+// the sessions running at the last good listing are offered for restore,
+// not saved ones that ended long before.
+func (m Model) watchServer(msg SessionsMsg) (Model, tea.Cmd) {
+	if msg.Host != "" || m.demo != nil || m.client == nil {
+		return m, nil
+	}
+	before := m.recovery.running
+	if msg.Err == nil {
+		m.recovery.running = nil
+		for _, s := range msg.Sessions {
+			if s.Managed {
+				m.recovery.running = append(m.recovery.running, s.Name)
+			}
+		}
+	}
+	switch {
+	case m.recovery.probing:
+		return m, nil
+	case errors.Is(msg.Err, tmux.ErrServerLost):
+		m.recovery = serverRecovery{probing: true, lost: true, next: time.Now().Add(reconnectDelay(1)), running: before}
+		return m, m.probeServer(1)
+	case msg.Err == nil && len(before) >= vanishedAtOnce && len(m.recovery.running) == 0:
+		m.recovery.probing, m.recovery.running = true, before
+		return m, m.probeServer(0)
+	}
+	return m, nil
+}
+
+// probeServer checks whether the local tmux server answers again, and which
+// of the sessions running before it went are saved but missing, after the
+// backoff of attempt; attempt 0 checks right away.
+func (m Model) probeServer(attempt int) tea.Cmd {
+	client, mgr := m.client, m.manager
+	running := make(map[string]bool, len(m.recovery.running))
+	for _, name := range m.recovery.running {
+		running[name] = true
+	}
+	probe := func() tea.Msg {
+		ctx := context.Background()
+		if _, err := client.ListSessions(ctx, "#{session_name}"); err != nil {
+			return ServerProbeMsg{Attempt: attempt, Err: err}
+		}
+		saved, err := mgr.MissingDefinitions(ctx)
+		var missing []session.Definition
+		for _, d := range saved {
+			if running[session.SessionPrefix+d.Name] {
+				missing = append(missing, d)
+			}
+		}
+		return ServerProbeMsg{Attempt: attempt, Missing: missing, Err: err}
+	}
+	if attempt == 0 {
+		return probe
+	}
+	return tea.Tick(reconnectDelay(attempt), func(time.Time) tea.Msg { return probe() })
+}
+
+// handleServerProbe probes again while the server is still lost, and once
+// it answers, or is plainly not running, offers to restore the saved
+// sessions it is missing.
+func (m Model) handleServerProbe(msg ServerProbeMsg) (Model, tea.Cmd) {
+	if errors.Is(msg.Err, tmux.ErrServerLost) && msg.Attempt < reconnectAttempts {
+		next := msg.Attempt + 1
+		m.recovery.lost, m.recovery.attempt = true, msg.Attempt
+		m.recovery.next = time.Now().Add(reconnectDelay(next))
+		return m, m.probeServer(next)
+	}
+	// The next listing counts the sessions afresh, so those restored or
+	// turned down are not taken for another vanishing.
+	wasLost := m.recovery.lost
+	m.recovery = serverRecovery{}
+	if errors.Is(m.err, tmux.ErrServerLost) {
+		m.err = nil
+	}
+	switch {
+	case msg.Err != nil:
+		m.err = fmt.Errorf("the tmux server did not come back: %w", msg.Err)
+		return m, nil
+	case len(msg.Missing) > 0 && !m.confirm.Open():
+		m = m.confirmRestore(msg.Missing)
+		m.notice = "The tmux server stopped; its saved sessions can be restored"
+	case len(msg.Missing) > 0:
+		m.notice = fmt.Sprintf("The tmux server stopped; R restores %d saved session(s)", len(msg.Missing))
+	case wasLost:
+		m.notice = "Reconnected to the tmux server"
+	}
+	return m.refreshSessions()
+}
@@ -0,0 +1,98 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/seunggabi/claude-dashboard/internal/config"
+	"github.com/seunggabi/claude-dashboard/internal/conversation"
+	"github.com/seunggabi/claude-dashboard/internal/session"
+)
+
+// spendMeters keeps a conversation.SpendMeter per session, so each refresh
+// only reads what the logs gained. Refreshes can overlap, hence the lock.
+type spendMeters struct {
+	mu     sync.Mutex
+	meters map[string]*conversation.SpendMeter // by historyKey
+}
+
+func newSpendMeters() *spendMeters {
+	return &spendMeters{meters: make(map[string]*conversation.SpendMeter)}
+}
+
+// read returns the spend and context use of the current conversation of s.
+// Remote logs are not reachable, so remote sessions have neither.
+func (sm *spendMeters) read(s session.Session) (conversation.Spend, conversation.ContextUse) {
+	if s.Host != "" || s.Path == "" {
+		return conversation.Spend{}, conversation.ContextUse{}
+	}
+	path, err := s.Log()
+	if err != nil {
+		return conversation.Spend{}, conversation.ContextUse{}
+	}
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+	meter, ok := sm.meters[historyKey(s)]
+	if !ok {
+		meter = &conversation.SpendMeter{}
+		sm.meters[historyKey(s)] = meter
+	}
+	spend, _ := meter.Read(path)
+	return spend, meter.Context()
+}
+
+// forget drops the meter of a session that is gone.
+func (sm *spendMeters) forget(key string) {
+	sm.mu.Lock()
+	delete(sm.meters, key)
+	sm.mu.Unlock()
+}
+
+// enforceSpendCaps announces each session that passed its spend cap since
+// the last refresh and, with the interrupt action, stops its turn if it is
+// still working. A session is dealt with once per crossing, so a user who
+// resumes it after the interrupt is not interrupted again.
+func (m Model) enforceSpendCaps() (Model, tea.Cmd) {
+	var cmds []tea.Cmd
+	for _, s := range m.sessions {
+		key := historyKey(s)
+		if !m.cfg.SpendCap.For(s.DisplayName()).Exceeded(s.Spend.Cost, s.Spend.Tokens) {
+			delete(m.overCap, key)
+			continue
+		}
+		if m.overCap[key] {
+			continue
+		}
+		m.overCap[key] = true
+		m.notice = fmt.Sprintf("%s passed its spend cap (%s)", qualifiedName(s), s.Spend)
+		if m.cfg.SpendCap.Action == config.CapInterrupt && s.Managed && s.Status == session.StatusActive {
+			m.notice += "; interrupted"
+			cmds = append(cmds, m.interruptSession(s))
+		}
+	}
+	return m, tea.Batch(cmds...)
+}
+
+// overCapCount returns how many listed sessions are over their spend cap.
+func (m Model) overCapCount() int {
+	n := 0
+	for _, s := range m.sessions {
+		if m.overCap[historyKey(s)] {
+			n++
+		}
+	}
+	return n
+}
+
+// interruptSession stops the current turn of s.
+func (m Model) interruptSession(s session.Session) tea.Cmd {
+	return func() tea.Msg {
+		mgr, err := m.managerFor(s.Host)
+		if err != nil {
+			return SendMsg{Err: err}
+		}
+		return SendMsg{Err: mgr.Interrupt(context.Background(), s.Name)}
+	}
+}
@@ -0,0 +1,80 @@
+package app
+
+// uiState is what the dashboard looks like when it quits to attach, so Run
+// can put it back when the session is detached.
+type uiState struct {
+	view         View
+	cursor       int
+	scrollOffset int
+	selected     string // historyKey of the highlighted session
+	filterQuery  string
+	hostFilter   string
+	previewOpen  bool
+}
+
+// restorableViews are the views drawn from the session list alone; others
+// hold data fetched for them and reopen on the dashboard.
+var restorableViews = map[View]bool{
+	ViewDashboard: true,
+	ViewDetail:    true,
+	ViewPulse:     true,
+	ViewHelp:      true,
+}
+
+// saveState returns the UI state of m.
+func (m Model) saveState() uiState {
+	st := uiState{
+		view:         m.view,
+		cursor:       m.cursor,
+		scrollOffset: m.scrollOffset,
+		filterQuery:  m.filterQuery,
+		hostFilter:   m.hostFilter,
+		previewOpen:  m.previewOpen,
+	}
+	if s, ok := m.detailSession(); ok {
+		st.selected = historyKey(s)
+	}
+	return st
+}
+
+// restoreState puts back st. The highlight moves to the same session, if
+// it is still there, once the first refresh lists it.
+func (m *Model) restoreState(st uiState) {
+	if restorableViews[st.view] {
+		m.view = st.view
+	}
+	m.cursor = st.cursor
+	m.scrollOffset = st.scrollOffset
+	m.reselect = st.selected
+	m.filterQuery = st.filterQuery
+	m.filterText.SetValue(st.filterQuery)
+	m.hostFilter = st.hostFilter
+	m.previewOpen = st.previewOpen
+}
+
+// reselectSession moves the cursor to the session saved by restoreState,
+// scrolling it into view. It keeps trying until every host has answered,
+// as the session may be on a host listed after the others.
+func (m *Model) reselectSession() {
+	key := m.reselect
+	if key == "" {
+		return
+	}
+	for i, s := range m.filteredSessions() {
+		if historyKey(s) != key {
+			continue
+		}
+		m.reselect = ""
+		m.cursor = i
+		rows := m.visibleSessionRows()
+		if m.cursor < m.scrollOffset {
+			m.scrollOffset = m.cursor
+		} else if m.cursor >= m.scrollOffset+rows {
+			m.scrollOffset = m.cursor - rows + 1
+		}
+		return
+	}
+	if m.listed && len(m.listing) == 0 {
+		m.reselect = "" // gone while attached
+	}
+}
@@ -0,0 +1,21 @@
+//go:build !windows
+
+package app
+
+import (
+	"os"
+	"syscall"
+	"time"
+)
+
+// DrainStdin reads and discards any pending data on stdin (e.g. DA1 response).
+// Exported so main.go can call it at startup.
+func DrainStdin() {
+	fd := int(os.Stdin.Fd())
+	_ = syscall.SetNonblock(fd, true)
+	buf := make([]byte, 1024)
+	os.Stdin.Read(buf)
+	time.Sleep(50 * time.Millisecond)
+	os.Stdin.Read(buf)
+	_ = syscall.SetNonblock(fd, false)
+}
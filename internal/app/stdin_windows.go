@@ -0,0 +1,8 @@
+//go:build windows
+
+package app
+
+// DrainStdin is a no-op on Windows: the console delivers terminal replies as
+// input events that Bubble Tea already discards, and stdin cannot be switched
+// to non-blocking mode.
+func DrainStdin() {}
@@ -0,0 +1,97 @@
+package app
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/seunggabi/claude-dashboard/internal/config"
+	"github.com/seunggabi/claude-dashboard/internal/session"
+	"github.com/seunggabi/claude-dashboard/internal/summary"
+	"github.com/seunggabi/claude-dashboard/internal/tmux"
+)
+
+// slackTimeout bounds posting a summary to Slack.
+const slackTimeout = 10 * time.Second
+
+// WriteSummary writes the digest of from..to to w for the summary command.
+// With post, it is also sent to the slack_webhook in the config.
+func WriteSummary(w io.Writer, from, to time.Time, format summary.Format, post bool) error {
+	cfg := config.Load()
+	if post && cfg.SlackWebhook == "" {
+		return fmt.Errorf("no slack_webhook in %s", config.ConfigPath())
+	}
+	loadPricing(cfg)
+	applyLocale(cfg)
+
+	ctx := context.Background()
+	dirs, issues := sessionDirs(ctx, cfg)
+	r, err := summary.Build(ctx, from, to, dirs, issues)
+	if err != nil {
+		return err
+	}
+	if err := r.Write(w, format); err != nil {
+		return err
+	}
+	if post {
+		return postToSlack(ctx, cfg.SlackWebhook, r)
+	}
+	return nil
+}
+
+// sessionDirs returns the working directories of the saved and running
+// local sessions, so their repositories are checked for commits even when
+// no conversation ran there, and the issues the running ones are linked to.
+func sessionDirs(ctx context.Context, cfg *config.Config) ([]string, summary.Issues) {
+	var dirs []string
+	issues := summary.Issues{ByDir: make(map[string][]string), Projects: cfg.Issues.Projects}
+	defs, _ := session.LoadDefinitions(session.DefinitionsPath())
+	for _, d := range defs {
+		dirs = append(dirs, d.Path)
+	}
+	client, err := tmux.NewClient()
+	if err != nil {
+		client = nil // terminal-only: terminal sessions are still listed
+	}
+	sessions, _ := session.NewManager(client).List(ctx)
+	for _, s := range sessions {
+		dirs = append(dirs, s.Path)
+		if key := issueOf(ctx, cfg, s); key != "" {
+			issues.ByDir[s.Path] = append(issues.ByDir[s.Path], key)
+		}
+	}
+	return dirs, issues
+}
+
+// postToSlack sends the report to a Slack incoming webhook.
+func postToSlack(ctx context.Context, webhook string, r summary.Report) error {
+	var text bytes.Buffer
+	if err := r.Write(&text, summary.FormatSlack); err != nil {
+		return err
+	}
+	body, err := json.Marshal(map[string]string{"text": text.String()})
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, slackTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhook, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("posting to Slack: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("posting to Slack: %s", resp.Status)
+	}
+	return nil
+}
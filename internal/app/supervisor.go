@@ -0,0 +1,89 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/seunggabi/claude-dashboard/internal/config"
+	"github.com/seunggabi/claude-dashboard/internal/session"
+)
+
+// restartCheckInterval is how often `serve` looks for crashed sessions.
+const restartCheckInterval = 10 * time.Second
+
+// RestartMsg reports restarting claude in a session from the dashboard.
+type RestartMsg struct {
+	Name string
+	Err  error
+}
+
+// newSupervisor returns the supervisor auto_restart asks for, or nil.
+func newSupervisor(cfg *config.Config) *session.Supervisor {
+	if cfg.AutoRestart <= 0 {
+		return nil
+	}
+	return session.NewSupervisor(cfg.AutoRestart)
+}
+
+// restartCrashed restarts claude in the crashed local sessions the
+// supervisor has restarts left for.
+func (m Model) restartCrashed() tea.Cmd {
+	if m.supervisor == nil || m.client == nil {
+		return nil
+	}
+	due := m.supervisor.Due(m.sessions)
+	if len(due) == 0 {
+		return nil
+	}
+	return func() tea.Msg {
+		return BulkMsg{Result: m.manager.RestartMany(context.Background(), due)}
+	}
+}
+
+// restartSession restarts claude in s, which it has exited in.
+func (m Model) restartSession(s session.Session) tea.Cmd {
+	return func() tea.Msg {
+		mgr, err := m.managerFor(s.Host)
+		if err != nil {
+			return RestartMsg{Name: s.Name, Err: err}
+		}
+		return RestartMsg{Name: s.Name, Err: mgr.Restart(context.Background(), s)}
+	}
+}
+
+// exitHint is the line under the dashboard saying claude is no longer
+// running in the selected session, or "" while it is.
+func (m Model) exitHint() string {
+	s, ok := m.detailSession()
+	if !ok || !s.Exited() {
+		return ""
+	}
+	what := "exited"
+	if s.Status == session.StatusCrashed {
+		what = "crashed"
+	}
+	return fmt.Sprintf("claude %s in %s; press ctrl+r to restart it", what, s.Name)
+}
+
+// runSupervisor restarts claude in crashed sessions until ctx is done, for
+// `serve`, reporting each failure to onError.
+func runSupervisor(ctx context.Context, mgr *session.Manager, sv *session.Supervisor, onError func(error)) {
+	ticker := time.NewTicker(restartCheckInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			sessions, err := mgr.List(ctx)
+			if err != nil {
+				continue
+			}
+			for _, it := range mgr.RestartMany(ctx, sv.Due(sessions)).Failed() {
+				onError(fmt.Errorf("restarting %s: %w", it.Name, it.Err))
+			}
+		}
+	}
+}
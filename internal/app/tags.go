@@ -0,0 +1,61 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/seunggabi/claude-dashboard/internal/session"
+)
+
+// TagsMsg signals the tags of a session were set.
+type TagsMsg struct {
+	Err error
+}
+
+// startTagging opens the tag input (#) for s, filled with its tags.
+func (m Model) startTagging(s session.Session) (Model, tea.Cmd) {
+	if !s.Managed {
+		m.err = fmt.Errorf("terminal sessions cannot be tagged (not a tmux session)")
+		return m, nil
+	}
+	m.tagging = true
+	m.tagTarget = s
+	m.tagInput.SetValue(strings.Join(s.Tags, ", "))
+	m.tagInput.CursorEnd()
+	return m, m.tagInput.Focus()
+}
+
+func (m Model) handleTagKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "enter":
+		tags, err := session.ParseTags(m.tagInput.Value())
+		if err != nil {
+			m.err = err
+			return m, nil
+		}
+		m.tagging = false
+		m.tagInput.Blur()
+		return m, m.setTags(m.tagTarget, tags)
+	case "esc":
+		m.tagging = false
+		m.tagInput.Blur()
+		return m, nil
+	}
+
+	var cmd tea.Cmd
+	m.tagInput, cmd = m.tagInput.Update(msg)
+	return m, cmd
+}
+
+// setTags replaces the tags of s in the background.
+func (m Model) setTags(s session.Session, tags []string) tea.Cmd {
+	return func() tea.Msg {
+		mgr, err := m.managerFor(s.Host)
+		if err != nil {
+			return TagsMsg{Err: err}
+		}
+		return TagsMsg{Err: mgr.SetTags(context.Background(), s.Name, tags)}
+	}
+}
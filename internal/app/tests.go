@@ -0,0 +1,157 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/seunggabi/claude-dashboard/internal/session"
+	"github.com/seunggabi/claude-dashboard/internal/testrun"
+)
+
+// paneTestLines is how many pane lines are searched for a test summary.
+const paneTestLines = 200
+
+// TestsMsg reports a test result recorded for the session with Key.
+type TestsMsg struct {
+	Key string
+}
+
+// testResults keeps the latest test result of each session across
+// attaches (see Run). Test commands finish in the background, possibly
+// after an attach, so it is locked.
+type testResults struct {
+	mu     sync.Mutex
+	latest map[string]testrun.Result // by historyKey
+	pane   map[string]string         // summary last read from each pane
+}
+
+func newTestResults() *testResults {
+	return &testResults{latest: make(map[string]testrun.Result), pane: make(map[string]string)}
+}
+
+func (t *testResults) get(key string) testrun.Result {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.latest[key]
+}
+
+func (t *testResults) set(key string, r testrun.Result) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.latest[key] = r
+}
+
+// fromPane records r, read from the pane of key, unless the pane shows the
+// same summary as when last read or the test command is running, and
+// reports whether it did.
+func (t *testResults) fromPane(key string, r testrun.Result) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.pane[key] == r.Summary || t.latest[key].Running {
+		return false
+	}
+	t.pane[key] = r.Summary
+	t.latest[key] = r
+	return true
+}
+
+func (t *testResults) forget(key string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	delete(t.latest, key)
+	delete(t.pane, key)
+}
+
+// seen reports whether any session has a result.
+func (t *testResults) seen() bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return len(t.latest) > 0
+}
+
+// applyTests sets the test result of every listed session.
+func (m Model) applyTests() {
+	for i := range m.sessions {
+		m.sessions[i].Test = m.tests.get(historyKey(m.sessions[i]))
+	}
+}
+
+// showTests reports whether the dashboard has a TEST column: when test
+// commands are configured or a pane showed a result.
+func (m Model) showTests() bool {
+	return len(m.cfg.TestCommands) > 0 || m.tests.seen()
+}
+
+// runTests runs the test command of the project of s in its directory.
+func (m Model) runTests(s session.Session) (Model, tea.Cmd) {
+	if s.Host != "" {
+		m.err = fmt.Errorf("tests are only run for local sessions")
+		return m, nil
+	}
+	command := m.cfg.TestCommand(s.Project)
+	if command == "" {
+		m.err = fmt.Errorf("no test command for %s; set one under test_commands in the config", s.Project)
+		return m, nil
+	}
+	key := historyKey(s)
+	if m.tests.get(key).Running {
+		m.err = fmt.Errorf("tests are already running in %s", s.Name)
+		return m, nil
+	}
+	m.tests.set(key, testrun.Result{Running: true, Source: testrun.SourceRun, At: time.Now()})
+	m.applyTests()
+	m.notice = fmt.Sprintf("Running %q in %s", command, s.Name)
+	tests := m.tests
+	return m, func() tea.Msg {
+		tests.set(key, testrun.Run(context.Background(), s.Path, command))
+		return TestsMsg{Key: key}
+	}
+}
+
+// readPaneTests looks for a new test summary in the pane of s, as when
+// claude stops working after running the tests itself.
+func (m Model) readPaneTests(s session.Session) tea.Cmd {
+	if s.Host != "" || !s.Managed || m.client == nil {
+		return nil
+	}
+	tests := m.tests
+	return func() tea.Msg {
+		content, err := m.client.CapturePaneContent(context.Background(), s.Name, paneTestLines)
+		if err != nil {
+			return nil
+		}
+		r, ok := testrun.Parse(content)
+		if !ok {
+			return nil
+		}
+		r.At = time.Now()
+		if !tests.fromPane(historyKey(s), r) {
+			return nil
+		}
+		return TestsMsg{Key: historyKey(s)}
+	}
+}
+
+// showTestResult shows the result recorded for msg.Key, announcing the end
+// of a run started from the dashboard.
+func (m Model) showTestResult(msg TestsMsg) Model {
+	m.applyTests()
+	r := m.tests.get(msg.Key)
+	if r.Source != testrun.SourceRun {
+		return m
+	}
+	for _, s := range m.sessions {
+		if historyKey(s) != msg.Key {
+			continue
+		}
+		if r.Passed {
+			m.notice = fmt.Sprintf("Tests passed in %s", s.Name)
+		} else {
+			m.err = fmt.Errorf("tests failed in %s: %s", s.Name, r.Summary)
+		}
+	}
+	return m
+}
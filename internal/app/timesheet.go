@@ -0,0 +1,29 @@
+package app
+
+import (
+	"time"
+
+	"github.com/seunggabi/claude-dashboard/internal/session"
+	"github.com/seunggabi/claude-dashboard/internal/timesheet"
+)
+
+// attachedSession returns the session the model is about to attach to, as
+// last listed, for the timesheet.
+func (m Model) attachedSession() session.Session {
+	for _, s := range m.sessions {
+		if s.Host == m.attachHost && s.Name == m.attachTarget {
+			return s
+		}
+	}
+	return session.Session{Name: m.attachTarget, Host: m.attachHost}
+}
+
+// timesToday returns the time recorded for s today, or nothing without a
+// timesheet.
+func (m Model) timesToday(s session.Session) timesheet.Totals {
+	if m.timesheet == nil {
+		return timesheet.Totals{}
+	}
+	t, _ := m.timesheet.Today(s, time.Now())
+	return t
+}
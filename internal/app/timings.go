@@ -0,0 +1,48 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/seunggabi/claude-dashboard/internal/config"
+	"github.com/seunggabi/claude-dashboard/internal/session"
+	"github.com/seunggabi/claude-dashboard/internal/timing"
+	"github.com/seunggabi/claude-dashboard/internal/tmux"
+)
+
+// WriteTimings goes through the steps the dashboard takes before its first
+// rows are filled in, without starting it, and writes how long each took
+// after the phases rec already holds, such as the setup check.
+func WriteTimings(w io.Writer, rec *timing.Recorder) error {
+	ctx := context.Background()
+	cfg := config.Load()
+	loadPricing(cfg)
+	rec.Mark("config load")
+
+	client, err := tmux.NewClient()
+	if err != nil {
+		client = nil // terminal-only, as the dashboard would run
+	} else {
+		_, _ = client.ServerInfo(ctx) // a first round trip; no server is fine
+	}
+	rec.Mark("tmux probe")
+
+	sessions, err := session.NewManager(client).List(ctx)
+	if err != nil {
+		return err
+	}
+	rec.Mark("first detection")
+
+	var remote []session.Session
+	if len(cfg.Hosts) > 0 {
+		remote, _ = listRemoteSessions(ctx, newRemoteHosts(cfg.Hosts))
+		rec.Mark("remote hosts")
+	}
+
+	if err := rec.Write(w); err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "\n%d local and %d remote session(s) detected\n", len(sessions), len(remote))
+	return err
+}
@@ -0,0 +1,73 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/seunggabi/claude-dashboard/internal/config"
+	"github.com/seunggabi/claude-dashboard/internal/session"
+	"github.com/seunggabi/claude-dashboard/internal/tmux"
+)
+
+// RetitleSessions sets the tmux window name and pane title of dashboard
+// sessions back to their project names, on this machine and every
+// configured host, and writes what it did to w. With names, only those
+// sessions ("name" or "host:name") are retitled.
+func RetitleSessions(w io.Writer, names []string) error {
+	cfg := config.Load()
+	managers := make(map[string]*session.Manager)
+	if client, err := tmux.NewClient(); err == nil {
+		managers[""] = session.NewManager(client)
+	}
+	remotes := newRemoteHosts(cfg.Hosts)
+	for _, r := range remotes {
+		if r.manager != nil {
+			managers[r.name] = r.manager
+		}
+	}
+
+	ctx := context.Background()
+	var sessions []session.Session
+	if mgr := managers[""]; mgr != nil {
+		local, err := mgr.List(ctx)
+		if err != nil {
+			return err
+		}
+		sessions = local
+	}
+	remote, _ := listRemoteSessions(ctx, remotes)
+	sessions = append(sessions, remote...)
+
+	wanted := make(map[string]bool)
+	for _, name := range names {
+		wanted[name] = true
+	}
+	var errs []error
+	done := 0
+	for _, s := range sessions {
+		name := qualifiedName(s)
+		switch {
+		case len(wanted) > 0 && !wanted[name]:
+			continue
+		case len(wanted) == 0 && (!s.Managed || !strings.HasPrefix(s.Name, session.SessionPrefix)):
+			continue // leave sessions the dashboard did not create alone
+		}
+		delete(wanted, name)
+		if err := managers[s.Host].Retitle(ctx, s); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", name, err))
+			continue
+		}
+		done++
+		fmt.Fprintf(w, "%s → %s\n", name, s.Project)
+	}
+	for name := range wanted {
+		errs = append(errs, fmt.Errorf("%s: session not found", name))
+	}
+	if done == 0 && len(errs) == 0 {
+		fmt.Fprintln(w, "No dashboard sessions to retitle")
+	}
+	return errors.Join(errs...)
+}
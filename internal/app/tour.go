@@ -0,0 +1,59 @@
+package app
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/seunggabi/claude-dashboard/internal/config"
+	"github.com/seunggabi/claude-dashboard/internal/ui"
+)
+
+// handleTourKey steps through the onboarding tour, which takes every key
+// while it is shown.
+func (m Model) handleTourKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "right", "l", "enter", " ":
+		if m.tourStep == len(ui.TourSteps)-1 {
+			return m.endTour(), nil
+		}
+		m.tourStep++
+	case "left", "h":
+		if m.tourStep > 0 {
+			m.tourStep--
+		}
+	case "esc", "q":
+		return m.endTour(), nil
+	}
+	return m, nil
+}
+
+// endTour closes the tour and saves that it was shown, so it is not shown
+// again; only tour_done is written, so the rest of config.yaml stays as
+// the user left it.
+func (m Model) endTour() Model {
+	m.touring = false
+	m.cfg.TourDone = true
+	if err := config.Set("tour_done", true); err != nil {
+		m.err = fmt.Errorf("tour not marked done: %w", err)
+	}
+	return m
+}
+
+// drawTour draws the card of the current tour step over the dashboard
+// view, next to what it describes; the table starts at line tableTop.
+func (m Model) drawTour(view string, tableTop int) string {
+	card := ui.RenderTourCard(m.tourStep, m.width)
+	height := strings.Count(card, "\n") + 1
+	lines := strings.Count(view, "\n") + 1
+	var row int
+	switch ui.TourSteps[m.tourStep].Anchor {
+	case ui.TourTable:
+		row = tableTop + 1 // under the column headers
+	case ui.TourStatusBar:
+		row = lines - 2 - height
+	default:
+		row = (lines - height) / 2
+	}
+	return ui.Overlay(view, card, max(row, 0))
+}
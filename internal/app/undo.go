@@ -0,0 +1,120 @@
+package app
+
+import tea "github.com/charmbracelet/bubbletea"
+
+// undoLimit is how many view changes u can undo.
+const undoLimit = 50
+
+// viewSettings are the parts of how the dashboard looks that u undoes:
+// which sessions are shown, how rows are drawn and the preview pane.
+type viewSettings struct {
+	filterQuery string
+	hostFilter  string
+	density     string
+	previewOpen bool
+}
+
+// viewHistory holds the view settings to undo and redo. settled is the
+// settings as last recorded, so a filter typed in several keys is one
+// change. It is shared across attaches, like the UI state.
+type viewHistory struct {
+	undo    []viewSettings
+	redo    []viewSettings
+	settled viewSettings
+}
+
+func newViewHistory(current viewSettings) *viewHistory {
+	return &viewHistory{settled: current}
+}
+
+// record notes current as a change when it differs from the settled
+// settings, which can then be undone; a new change drops what was undone.
+func (h *viewHistory) record(current viewSettings) {
+	if current == h.settled {
+		return
+	}
+	h.undo = append(h.undo, h.settled)
+	if len(h.undo) > undoLimit {
+		h.undo = h.undo[1:]
+	}
+	h.redo = nil
+	h.settled = current
+}
+
+// step moves the settled settings back (undo) or forward (redo) and
+// returns them; ok is false when there is nothing to move to.
+func (h *viewHistory) step(undo bool) (viewSettings, bool) {
+	from, to := &h.redo, &h.undo
+	if !undo {
+		from, to = to, from
+	}
+	if len(*to) == 0 {
+		return viewSettings{}, false
+	}
+	next := (*to)[len(*to)-1]
+	*to = (*to)[:len(*to)-1]
+	*from = append(*from, h.settled)
+	h.settled = next
+	return next, true
+}
+
+// viewSettings returns the current view settings of m.
+func (m Model) viewSettings() viewSettings {
+	return viewSettings{
+		filterQuery: m.filterQuery,
+		hostFilter:  m.hostFilter,
+		density:     m.cfg.Density,
+		previewOpen: m.previewOpen,
+	}
+}
+
+// recordView records a change of the view settings after a key. Nothing is
+// recorded while the filter is typed, only once it is applied.
+func (m Model) recordView() {
+	if m.views == nil || m.filtering {
+		return
+	}
+	m.views.record(m.viewSettings())
+}
+
+// stepView undoes (u) or redoes (U) the last change of the view settings.
+func (m Model) stepView(undo bool) (tea.Model, tea.Cmd) {
+	v, ok := m.views.step(undo)
+	switch {
+	case !ok && undo:
+		m.notice = "Nothing to undo"
+	case !ok:
+		m.notice = "Nothing to redo"
+	case undo:
+		m.notice = "Undid view change (U redoes)"
+	default:
+		m.notice = "Redid view change"
+	}
+	if !ok {
+		return m, nil
+	}
+	return m.applyView(v)
+}
+
+// applyView puts back the view settings v.
+func (m Model) applyView(v viewSettings) (tea.Model, tea.Cmd) {
+	if v.filterQuery != m.filterQuery || v.hostFilter != m.hostFilter {
+		m.filterQuery = v.filterQuery
+		m.filterText.SetValue(v.filterQuery)
+		m.hostFilter = v.hostFilter
+		m.cursor = 0
+		m.scrollOffset = 0
+	}
+	var cmds []tea.Cmd
+	if v.density != m.cfg.Density {
+		next, cmd := m.setDensity(v.density)
+		m = next.(Model)
+		cmds = append(cmds, cmd)
+	}
+	if v.previewOpen != m.previewOpen {
+		next, cmd := m.togglePreview()
+		m = next.(Model)
+		cmds = append(cmds, cmd)
+	}
+	return m, tea.Batch(cmds...)
+}
@@ -0,0 +1,181 @@
+// Package archive keeps the pane history and conversation log of sessions
+// that were killed after idling, so they can be browsed and restored, along
+// with transcripts imported from other machines or people.
+package archive
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/seunggabi/claude-dashboard/internal/config"
+	"gopkg.in/yaml.v3"
+)
+
+// Files of an archive entry directory.
+const (
+	entryFile        = "session.yaml"
+	paneFile         = "pane.txt"
+	conversationFile = "conversation.jsonl"
+)
+
+// Entry is an archived session: what is needed to recreate it, and the
+// directory its pane history and conversation log are saved in.
+type Entry struct {
+	Name         string        `yaml:"name"` // tmux session name
+	Project      string        `yaml:"project,omitempty"`
+	Path         string        `yaml:"path,omitempty"`
+	Args         string        `yaml:"args,omitempty"`
+	Conversation string        `yaml:"conversation,omitempty"` // ID claude can resume the saved log by
+	Archived     time.Time     `yaml:"archived"`               // when archived or imported
+	Idle         time.Duration `yaml:"idle,omitempty"`         // how long it was idle when archived
+	Source       string        `yaml:"source,omitempty"`       // file it was imported from
+
+	Dir string `yaml:"-"`
+}
+
+// Dir returns the directory archived sessions are kept in.
+func Dir() string {
+	return filepath.Join(config.ConfigDir(), "archive")
+}
+
+// Save writes e with its pane history and a copy of the conversation log at
+// logPath (none when empty) to a new directory under root, named after the
+// session and when it was archived.
+func Save(root string, e Entry, pane, logPath string) (Entry, error) {
+	if err := makeDir(root, &e); err != nil {
+		return e, err
+	}
+	if err := os.WriteFile(filepath.Join(e.Dir, paneFile), []byte(pane), 0644); err != nil {
+		return e, err
+	}
+	if logPath != "" {
+		if err := copyFile(logPath, filepath.Join(e.Dir, conversationFile)); err != nil {
+			return e, fmt.Errorf("saving the conversation: %w", err)
+		}
+		e.Conversation = strings.TrimSuffix(filepath.Base(logPath), ".jsonl")
+	}
+	return e, writeEntry(e)
+}
+
+// unsafeName matches what is left out of entry directory names.
+var unsafeName = regexp.MustCompile(`[^A-Za-z0-9_.-]+`)
+
+// makeDir creates the directory of e under root and sets e.Dir.
+func makeDir(root string, e *Entry) error {
+	name := strings.Trim(unsafeName.ReplaceAllString(e.Name, "-"), "-.")
+	e.Dir = filepath.Join(root, name+"-"+e.Archived.Format("20060102-150405"))
+	if err := os.MkdirAll(root, 0755); err != nil {
+		return err
+	}
+	return os.Mkdir(e.Dir, 0755)
+}
+
+// writeEntry writes the entry file of e.
+func writeEntry(e Entry) error {
+	data, err := yaml.Marshal(e)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(e.Dir, entryFile), data, 0644)
+}
+
+// List returns the entries under root, most recently archived first.
+// Directories that are not entries are skipped; a missing root means no
+// entries.
+func List(root string) ([]Entry, error) {
+	dirs, err := os.ReadDir(root)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var entries []Entry
+	for _, d := range dirs {
+		if !d.IsDir() {
+			continue
+		}
+		if e, err := Load(filepath.Join(root, d.Name())); err == nil {
+			entries = append(entries, e)
+		}
+	}
+	sort.SliceStable(entries, func(i, j int) bool { return entries[i].Archived.After(entries[j].Archived) })
+	return entries, nil
+}
+
+// Load reads the entry in dir.
+func Load(dir string) (Entry, error) {
+	data, err := os.ReadFile(filepath.Join(dir, entryFile))
+	if err != nil {
+		return Entry{}, err
+	}
+	var e Entry
+	if err := yaml.Unmarshal(data, &e); err != nil {
+		return Entry{}, fmt.Errorf("invalid %s: %w", filepath.Join(dir, entryFile), err)
+	}
+	e.Dir = dir
+	return e, nil
+}
+
+// Imported reports whether e was imported rather than archived from a
+// session; it has no pane history.
+func (e Entry) Imported() bool {
+	return e.Source != ""
+}
+
+// Pane returns the saved pane history.
+func (e Entry) Pane() (string, error) {
+	data, err := os.ReadFile(filepath.Join(e.Dir, paneFile))
+	return string(data), err
+}
+
+// ConversationPath returns the saved conversation log, or "" if there is
+// none.
+func (e Entry) ConversationPath() string {
+	path := filepath.Join(e.Dir, conversationFile)
+	if _, err := os.Stat(path); err != nil {
+		return ""
+	}
+	return path
+}
+
+// PutBackConversation copies the saved conversation log into logDir, where
+// claude looks for it to resume, unless a log of that ID is already there.
+func (e Entry) PutBackConversation(logDir string) error {
+	if e.Conversation == "" {
+		return nil
+	}
+	dst := filepath.Join(logDir, e.Conversation+".jsonl")
+	if _, err := os.Stat(dst); err == nil {
+		return nil
+	}
+	if err := os.MkdirAll(logDir, 0755); err != nil {
+		return err
+	}
+	return copyFile(e.ConversationPath(), dst)
+}
+
+// copyFile copies src to a new file dst.
+func copyFile(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	out, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
@@ -0,0 +1,174 @@
+package archive
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// ---------------------------------------------------------------------------
+// Save and List
+// ---------------------------------------------------------------------------
+
+func TestSave_keepsPaneConversationAndEntry(t *testing.T) {
+	root := filepath.Join(t.TempDir(), "archive")
+	log := filepath.Join(t.TempDir(), "0b6c-41.jsonl")
+	if err := os.WriteFile(log, []byte(`{"type":"user"}`+"\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	at := time.Date(2025, 11, 24, 9, 30, 0, 0, time.UTC)
+
+	e, err := Save(root, Entry{Name: "cd-api", Path: "/src/api", Args: "--model opus", Archived: at, Idle: 9 * time.Hour}, "$ claude\n> done", log)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if e.Dir != filepath.Join(root, "cd-api-20251124-093000") || e.Conversation != "0b6c-41" {
+		t.Errorf("unexpected entry %+v", e)
+	}
+
+	loaded, err := Load(e.Dir)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if loaded != e {
+		t.Errorf("expected %+v back, got %+v", e, loaded)
+	}
+	if pane, _ := loaded.Pane(); pane != "$ claude\n> done" {
+		t.Errorf("unexpected pane %q", pane)
+	}
+	if data, _ := os.ReadFile(loaded.ConversationPath()); string(data) != `{"type":"user"}`+"\n" {
+		t.Errorf("unexpected conversation copy %q", data)
+	}
+}
+
+func TestSave_withoutConversation(t *testing.T) {
+	e, err := Save(t.TempDir(), Entry{Name: "cd-web", Archived: time.Now()}, "", "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if e.ConversationPath() != "" {
+		t.Errorf("expected no conversation, got %q", e.ConversationPath())
+	}
+}
+
+func TestList_newestFirstSkippingStrayDirectories(t *testing.T) {
+	root := t.TempDir()
+	start := time.Date(2025, 11, 24, 9, 0, 0, 0, time.UTC)
+	for i, name := range []string{"cd-a", "cd-b", "cd-c"} {
+		if _, err := Save(root, Entry{Name: name, Archived: start.Add(time.Duration(i) * time.Hour)}, "", ""); err != nil {
+			t.Fatal(err)
+		}
+	}
+	os.Mkdir(filepath.Join(root, "stray"), 0755)
+
+	entries, err := List(root)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(entries) != 3 || entries[0].Name != "cd-c" || entries[2].Name != "cd-a" {
+		t.Errorf("expected cd-c, cd-b, cd-a, got %+v", entries)
+	}
+	if entries, err := List(filepath.Join(root, "missing")); err != nil || entries != nil {
+		t.Errorf("expected a missing root to be empty, got %v, %v", entries, err)
+	}
+}
+
+// ---------------------------------------------------------------------------
+// PutBackConversation
+// ---------------------------------------------------------------------------
+
+func TestPutBackConversation_onlyWhenMissing(t *testing.T) {
+	log := filepath.Join(t.TempDir(), "abc.jsonl")
+	os.WriteFile(log, []byte("archived\n"), 0644)
+	e, err := Save(t.TempDir(), Entry{Name: "cd-api", Archived: time.Now()}, "", log)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	logDir := filepath.Join(t.TempDir(), "-src-api")
+	if err := e.PutBackConversation(logDir); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if data, _ := os.ReadFile(filepath.Join(logDir, "abc.jsonl")); string(data) != "archived\n" {
+		t.Errorf("expected the log put back, got %q", data)
+	}
+
+	os.WriteFile(filepath.Join(logDir, "abc.jsonl"), []byte("newer\n"), 0644)
+	if err := e.PutBackConversation(logDir); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if data, _ := os.ReadFile(filepath.Join(logDir, "abc.jsonl")); string(data) != "newer\n" {
+		t.Errorf("expected an existing log to be kept, got %q", data)
+	}
+}
+
+// ---------------------------------------------------------------------------
+// Import and Search
+// ---------------------------------------------------------------------------
+
+func TestImport_claudeLogKeepsSessionAndProject(t *testing.T) {
+	src := filepath.Join(t.TempDir(), "0b6c.jsonl")
+	os.WriteFile(src, []byte(`{"type":"user","sessionId":"0b6c","cwd":"/src/api","timestamp":"2025-01-01T10:00:00Z","message":{"role":"user","content":"add retries"}}`+"\n"), 0644)
+	at := time.Date(2025, 11, 24, 9, 0, 0, 0, time.UTC)
+
+	e, err := Import(t.TempDir(), src, Entry{}, at)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if e.Name != "0b6c" || e.Path != "/src/api" || e.Project != "api" || e.Conversation != "0b6c" || e.Source != src || !e.Imported() {
+		t.Errorf("unexpected entry %+v", e)
+	}
+	if e.ConversationPath() == "" {
+		t.Error("expected the log to be kept")
+	}
+}
+
+func TestImport_jsonExportWithProject(t *testing.T) {
+	src := filepath.Join(t.TempDir(), "handoff.json")
+	os.WriteFile(src, []byte(`{"model":"claude-opus","messages":[{"role":"user","content":[{"type":"text","text":"why is CI red?"}]},{"role":"assistant","content":[{"type":"text","text":"A flaky test."}]}]}`), 0644)
+
+	e, err := Import(t.TempDir(), src, Entry{Project: "ci"}, time.Now())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if e.Name != "handoff" || e.Project != "ci" || e.Conversation != "" {
+		t.Errorf("unexpected entry %+v", e)
+	}
+	if got := Search([]Entry{e}, "FLAKY"); len(got) != 1 {
+		t.Errorf("expected the converted conversation to be searchable, got %+v", got)
+	}
+}
+
+func TestImport_rejectsWhatIsNotATranscript(t *testing.T) {
+	dir := t.TempDir()
+	for name, content := range map[string]string{"notes.md": "# notes", "empty.jsonl": `{"type":"summary"}`} {
+		src := filepath.Join(dir, name)
+		os.WriteFile(src, []byte(content), 0644)
+		if _, err := Import(t.TempDir(), src, Entry{}, time.Now()); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
+
+func TestSearch_matchesFieldsAndConversation(t *testing.T) {
+	root := t.TempDir()
+	log := filepath.Join(t.TempDir(), "abc.jsonl")
+	os.WriteFile(log, []byte(`{"type":"user","message":{"role":"user","content":"Fix the Login page"}}`+"\n"), 0644)
+	api, _ := Save(root, Entry{Name: "cd-api", Project: "api", Archived: time.Now()}, "", log)
+	web, _ := Save(root, Entry{Name: "cd-web", Project: "web", Archived: time.Now()}, "", "")
+	entries := []Entry{api, web}
+
+	if got := Search(entries, "web"); len(got) != 1 || got[0].Name != "cd-web" {
+		t.Errorf("expected cd-web by project, got %+v", got)
+	}
+	if got := Search(entries, "login"); len(got) != 1 || got[0].Name != "cd-api" {
+		t.Errorf("expected cd-api by conversation, got %+v", got)
+	}
+	if got := Search(entries, "type"); len(got) != 0 {
+		t.Errorf("expected the log's JSON not to match, got %+v", got)
+	}
+	if got := Search(entries, " "); len(got) != 2 {
+		t.Errorf("expected every entry for a blank term, got %+v", got)
+	}
+}
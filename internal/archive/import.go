@@ -0,0 +1,104 @@
+package archive
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/seunggabi/claude-dashboard/internal/conversation"
+)
+
+// Import adds the transcript at src to the archive under root: a claude
+// .jsonl log, kept as is, or a conversation exported as JSON (see
+// conversation.ParseTranscript), converted to a log. Name, Project and Path
+// of e are filled from the file when empty; only .jsonl logs that name
+// their session can be resumed on restore.
+func Import(root, src string, e Entry, now time.Time) (Entry, error) {
+	abs, err := filepath.Abs(src)
+	if err != nil {
+		return e, err
+	}
+	e.Source = abs
+	e.Archived = now
+	if e.Name == "" {
+		e.Name = strings.TrimSuffix(filepath.Base(src), filepath.Ext(src))
+	}
+
+	var log []byte
+	if strings.EqualFold(filepath.Ext(src), ".jsonl") {
+		entries, err := conversation.ReadEntries(src)
+		if err != nil {
+			return e, err
+		}
+		if len(entries) == 0 {
+			return e, fmt.Errorf("%s: no conversation in it", src)
+		}
+		info, err := conversation.ReadLogInfo(src)
+		if err != nil {
+			return e, err
+		}
+		if e.Path == "" {
+			e.Path = info.Cwd
+		}
+		e.Conversation = info.SessionID
+		if log, err = os.ReadFile(src); err != nil {
+			return e, err
+		}
+	} else {
+		data, err := os.ReadFile(src)
+		if err != nil {
+			return e, err
+		}
+		entries, err := conversation.ParseTranscript(data)
+		if err != nil {
+			return e, fmt.Errorf("%s: %w", src, err)
+		}
+		var buf bytes.Buffer
+		if err := conversation.WriteLog(&buf, entries); err != nil {
+			return e, err
+		}
+		log = buf.Bytes()
+	}
+	if e.Project == "" && e.Path != "" {
+		e.Project = filepath.Base(e.Path)
+	}
+
+	if err := makeDir(root, &e); err != nil {
+		return e, err
+	}
+	if err := os.WriteFile(filepath.Join(e.Dir, conversationFile), log, 0644); err != nil {
+		return e, err
+	}
+	return e, writeEntry(e)
+}
+
+// Search returns the entries whose name, project, path or source contain
+// term, or a message of whose saved conversation does, ignoring case.
+func Search(entries []Entry, term string) []Entry {
+	term = strings.ToLower(strings.TrimSpace(term))
+	if term == "" {
+		return entries
+	}
+	var found []Entry
+	for _, e := range entries {
+		fields := strings.ToLower(strings.Join([]string{e.Name, e.Project, e.Path, e.Source}, "\n"))
+		if strings.Contains(fields, term) || conversationContains(e, term) {
+			found = append(found, e)
+		}
+	}
+	return found
+}
+
+// conversationContains reports whether a message of the saved conversation
+// of e contains term.
+func conversationContains(e Entry, term string) bool {
+	path := e.ConversationPath()
+	if path == "" {
+		return false
+	}
+	_, counts, err := conversation.ReadLog(path, 1, conversation.Filter{Term: term})
+	return err == nil && counts.Matched > 0
+}
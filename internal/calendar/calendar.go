@@ -0,0 +1,195 @@
+// Package calendar turns conversation activity into calendar events, one
+// per stretch of work in a project, and writes them as an iCalendar (ICS)
+// file that calendar apps can import or subscribe to.
+package calendar
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/seunggabi/claude-dashboard/internal/conversation"
+)
+
+// MinDuration is how long a stretch of work must last to become an event.
+const MinDuration = 5 * time.Minute
+
+// maxTopics is how many prompts an event's description lists.
+const maxTopics = 5
+
+// topicWidth is how many characters of a prompt an event's title shows.
+const topicWidth = 60
+
+// Event is a stretch of work in one project, across its conversations.
+type Event struct {
+	Dir       string
+	Start     time.Time
+	End       time.Time
+	Prompts   int
+	ToolCalls int
+	Topics    []string // first prompt of each block, oldest first
+	Spend     conversation.Spend
+}
+
+// Project returns the name of the event's directory.
+func (e Event) Project() string {
+	return filepath.Base(e.Dir)
+}
+
+// Events returns the blocks of activities as events: blocks in the same
+// directory less than conversation.BlockGap apart are merged, and events
+// shorter than MinDuration left out. Events are ordered by start.
+func Events(activities []conversation.Activity) []Event {
+	byDir := make(map[string][]conversation.Block)
+	for _, a := range activities {
+		byDir[a.Dir] = append(byDir[a.Dir], a.Blocks...)
+	}
+	var events []Event
+	for dir, blocks := range byDir {
+		sort.Slice(blocks, func(i, j int) bool { return blocks[i].Start.Before(blocks[j].Start) })
+		var cur *Event
+		flush := func() {
+			if cur != nil && cur.End.Sub(cur.Start) >= MinDuration {
+				events = append(events, *cur)
+			}
+		}
+		for _, b := range blocks {
+			if cur == nil || b.Start.Sub(cur.End) > conversation.BlockGap {
+				flush()
+				cur = &Event{Dir: dir, Start: b.Start, End: b.End}
+			}
+			if b.End.After(cur.End) {
+				cur.End = b.End
+			}
+			cur.Prompts += b.Prompts
+			cur.ToolCalls += b.ToolCalls
+			cur.Spend = cur.Spend.Plus(b.Spend)
+			if b.Topic != "" {
+				cur.Topics = append(cur.Topics, b.Topic)
+			}
+		}
+		flush()
+	}
+	sort.Slice(events, func(i, j int) bool {
+		if !events[i].Start.Equal(events[j].Start) {
+			return events[i].Start.Before(events[j].Start)
+		}
+		return events[i].Dir < events[j].Dir
+	})
+	return events
+}
+
+// uid identifies the event across exports, so re-importing or refreshing a
+// subscription updates it instead of adding a copy.
+func (e Event) uid() string {
+	sum := sha1.Sum([]byte(fmt.Sprintf("%s\x00%d", e.Dir, e.Start.Unix())))
+	return hex.EncodeToString(sum[:8]) + "@claude-dashboard"
+}
+
+// summary is the event's title, e.g. "api: fix the flaky test".
+func (e Event) summary() string {
+	if len(e.Topics) == 0 {
+		return e.Project() + ": claude"
+	}
+	topic := e.Topics[0]
+	if runes := []rune(topic); len(runes) > topicWidth {
+		topic = string(runes[:topicWidth-1]) + "…"
+	}
+	return e.Project() + ": " + topic
+}
+
+// description lists the event's prompts and what the work took.
+func (e Event) description() string {
+	var b strings.Builder
+	for i, t := range e.Topics {
+		if i == maxTopics {
+			fmt.Fprintf(&b, "… and %d more\n", len(e.Topics)-maxTopics)
+			break
+		}
+		fmt.Fprintf(&b, "- %s\n", t)
+	}
+	if b.Len() > 0 {
+		b.WriteString("\n")
+	}
+	fmt.Fprintf(&b, "%s, %s, %s\n%s", plural(e.Prompts, "prompt"), plural(e.ToolCalls, "tool call"), e.Spend, e.Dir)
+	return b.String()
+}
+
+// Write writes events to w as an iCalendar file called name, stamped now.
+func Write(w io.Writer, name string, events []Event, now time.Time) error {
+	lw := &lineWriter{w: w}
+	lw.line("BEGIN:VCALENDAR")
+	lw.line("VERSION:2.0")
+	lw.line("PRODID:-//claude-dashboard//EN")
+	lw.line("CALSCALE:GREGORIAN")
+	lw.line("X-WR-CALNAME:" + escape(name))
+	for _, e := range events {
+		lw.line("BEGIN:VEVENT")
+		lw.line("UID:" + e.uid())
+		lw.line("DTSTAMP:" + stamp(now))
+		lw.line("DTSTART:" + stamp(e.Start))
+		lw.line("DTEND:" + stamp(e.End))
+		lw.line("SUMMARY:" + escape(e.summary()))
+		lw.line("DESCRIPTION:" + escape(e.description()))
+		lw.line("CATEGORIES:" + escape(e.Project()))
+		lw.line("TRANSP:TRANSPARENT")
+		lw.line("END:VEVENT")
+	}
+	lw.line("END:VCALENDAR")
+	return lw.err
+}
+
+// stamp writes t in UTC, as iCalendar wants.
+func stamp(t time.Time) string {
+	return t.UTC().Format("20060102T150405Z")
+}
+
+// escape escapes a text value.
+func escape(s string) string {
+	return strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`).Replace(s)
+}
+
+// maxLine is the longest a line may be, in octets, before it is folded.
+const maxLine = 75
+
+// lineWriter writes content lines ended by CRLF, folding long ones, and
+// keeps the first error.
+type lineWriter struct {
+	w   io.Writer
+	err error
+}
+
+func (lw *lineWriter) line(s string) {
+	if lw.err != nil {
+		return
+	}
+	var b strings.Builder
+	limit := maxLine
+	for len(s) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(s[cut]) {
+			cut--
+		}
+		b.WriteString(s[:cut])
+		b.WriteString("\r\n ")
+		s = s[cut:]
+		limit = maxLine - 1 // the leading space counts
+	}
+	b.WriteString(s)
+	b.WriteString("\r\n")
+	_, lw.err = io.WriteString(lw.w, b.String())
+}
+
+// plural returns "1 prompt" or "3 prompts".
+func plural(n int, noun string) string {
+	if n == 1 {
+		return "1 " + noun
+	}
+	return fmt.Sprintf("%d %ss", n, noun)
+}
@@ -0,0 +1,102 @@
+package calendar
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/seunggabi/claude-dashboard/internal/conversation"
+)
+
+var day = time.Date(2025, 11, 24, 0, 0, 0, 0, time.UTC)
+
+func at(h, m int) time.Time {
+	return day.Add(time.Duration(h)*time.Hour + time.Duration(m)*time.Minute)
+}
+
+func block(start, end time.Time, prompts int, topic string) conversation.Block {
+	return conversation.Block{Start: start, End: end, Prompts: prompts, Topic: topic}
+}
+
+// ---------------------------------------------------------------------------
+// Events
+// ---------------------------------------------------------------------------
+
+func TestEvents_mergesBlocksOfAProject(t *testing.T) {
+	activities := []conversation.Activity{
+		{Dir: "/src/api", Blocks: []conversation.Block{
+			block(at(9, 0), at(9, 40), 3, "fix the flaky test"),
+			block(at(14, 0), at(14, 2), 1, "quick question"), // too short
+		}},
+		// A second conversation in the same project, overlapping the first.
+		{Dir: "/src/api", Blocks: []conversation.Block{block(at(9, 30), at(10, 10), 2, "update the docs")}},
+		{Dir: "/src/web", Blocks: []conversation.Block{block(at(8, 0), at(8, 30), 1, "")}},
+	}
+	events := Events(activities)
+	if len(events) != 2 {
+		t.Fatalf("expected two events, got %+v", events)
+	}
+	web, api := events[0], events[1]
+	if web.Project() != "web" || web.summary() != "web: claude" {
+		t.Errorf("unexpected first event %+v", web)
+	}
+	if !api.Start.Equal(at(9, 0)) || !api.End.Equal(at(10, 10)) || api.Prompts != 5 {
+		t.Errorf("expected the api blocks merged, got %+v", api)
+	}
+	if len(api.Topics) != 2 || api.summary() != "api: fix the flaky test" {
+		t.Errorf("unexpected topics %v", api.Topics)
+	}
+}
+
+// ---------------------------------------------------------------------------
+// Write
+// ---------------------------------------------------------------------------
+
+func TestWrite_writesAValidCalendar(t *testing.T) {
+	e := Event{
+		Dir:    "/src/api",
+		Start:  at(9, 0),
+		End:    at(9, 40),
+		Topics: []string{"fix the flaky test; then, " + strings.Repeat("carefully ", 10)},
+	}
+	var buf bytes.Buffer
+	if err := Write(&buf, "Claude sessions", []Event{e}, at(12, 0)); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	out := buf.String()
+	for _, want := range []string{
+		"BEGIN:VCALENDAR\r\n",
+		"X-WR-CALNAME:Claude sessions\r\n",
+		"DTSTAMP:20251124T120000Z\r\n",
+		"DTSTART:20251124T090000Z\r\n",
+		"DTEND:20251124T094000Z\r\n",
+		"SUMMARY:api: fix the flaky test\\; then\\, ",
+		"UID:" + e.uid() + "\r\n",
+		"END:VCALENDAR\r\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %q in\n%s", want, out)
+		}
+	}
+	for _, line := range strings.Split(strings.TrimSuffix(out, "\r\n"), "\r\n") {
+		if len(line) > maxLine {
+			t.Errorf("expected lines folded at %d octets, got %q", maxLine, line)
+		}
+	}
+	unfolded := strings.ReplaceAll(out, "\r\n ", "")
+	if !strings.Contains(unfolded, "DESCRIPTION:- fix the flaky test\\; then\\, carefully") {
+		t.Errorf("expected the description intact once unfolded, got\n%s", unfolded)
+	}
+}
+
+func TestLineWriter_foldsWithoutSplittingRunes(t *testing.T) {
+	var buf bytes.Buffer
+	lw := &lineWriter{w: &buf}
+	lw.line("SUMMARY:" + strings.Repeat("é", 80))
+	for _, line := range strings.Split(strings.TrimSuffix(buf.String(), "\r\n"), "\r\n") {
+		if !strings.HasPrefix(line, "SUMMARY:") && !strings.HasPrefix(line, " é") {
+			t.Errorf("expected each folded line to start on a whole rune, got %q", line)
+		}
+	}
+}
@@ -0,0 +1,306 @@
+// Package cli dispatches the claude-dashboard command line to subcommands,
+// each with its own flags and help.
+package cli
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+)
+
+// errHelp is returned by parseArgs when -h or --help is given.
+var errHelp = errors.New("help requested")
+
+// UsageError reports bad arguments to a command. The dispatcher follows the
+// message with the command's usage line.
+type UsageError string
+
+func (e UsageError) Error() string { return string(e) }
+
+// Command is one subcommand. The command with an empty Name runs when no
+// subcommand is given.
+type Command struct {
+	Name    string
+	Usage   string // arguments after the name, e.g. "NAME [options]"
+	Summary string // one line for the command list
+	Help    string // longer description for the command's own help; optional
+
+	// MinArgs and MaxArgs bound the positional arguments, unless
+	// PassThrough is set; MaxArgs < 0 means no limit.
+	MinArgs int
+	MaxArgs int
+
+	// Flags declares the command's flags; nil for none.
+	Flags func(fs *flag.FlagSet)
+
+	// PassThrough keeps flags the command does not declare in the arguments
+	// given to Run instead of rejecting them (e.g. claude's own flags).
+	PassThrough bool
+
+	Run func(args []string) error
+}
+
+// App is a command line program made of subcommands.
+type App struct {
+	Name     string
+	Version  string
+	Summary  string // first line of the help
+	Footer   string // printed after the command list, e.g. keybindings
+	Commands []*Command
+
+	// Globals declares the flags every command takes, also before the
+	// command name (e.g. "prog --profile work list"); nil for none.
+	Globals func(fs *flag.FlagSet)
+	globals *flag.FlagSet // declared once, so their values last across commands
+
+	// Before runs after a command's arguments are parsed and before it runs;
+	// it is not called for help or version output.
+	Before func(c *Command)
+
+	Stdout io.Writer
+}
+
+// Lookup returns the command called name, or nil.
+func (a *App) Lookup(name string) *Command {
+	for _, c := range a.Commands {
+		if c.Name == name {
+			return c
+		}
+	}
+	return nil
+}
+
+// Run dispatches args (without the program name) to a command.
+func (a *App) Run(args []string) error {
+	args, err := a.parseLeadingGlobals(args)
+	if err != nil {
+		return err
+	}
+	name := ""
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		name, args = args[0], args[1:]
+	}
+	if name == "" && len(args) > 0 {
+		switch args[0] {
+		case "--version", "-v":
+			fmt.Fprintf(a.Stdout, "%s %s\n", a.Name, a.Version)
+			return nil
+		case "--help", "-h":
+			a.PrintHelp(a.Stdout)
+			return nil
+		}
+	}
+	if name == "help" {
+		if len(args) == 0 {
+			a.PrintHelp(a.Stdout)
+			return nil
+		}
+		c := a.Lookup(args[0])
+		if c == nil {
+			return fmt.Errorf("unknown command %q (see '%s --help')", args[0], a.Name)
+		}
+		a.PrintCommandHelp(a.Stdout, c)
+		return nil
+	}
+
+	c := a.Lookup(name)
+	if c == nil {
+		return fmt.Errorf("unknown command %q (see '%s --help')", name, a.Name)
+	}
+	fs := a.flagSet(c)
+	rest, err := parseArgs(fs, args, c.PassThrough)
+	if errors.Is(err, errHelp) {
+		a.PrintCommandHelp(a.Stdout, c)
+		return nil
+	}
+	if err == nil && !c.PassThrough {
+		switch {
+		case len(rest) < c.MinArgs:
+			err = UsageError("missing arguments")
+		case c.MaxArgs >= 0 && len(rest) > c.MaxArgs:
+			err = UsageError(fmt.Sprintf("unexpected argument %q", rest[c.MaxArgs]))
+		}
+	}
+	if err == nil {
+		if a.Before != nil {
+			a.Before(c)
+		}
+		err = c.Run(rest)
+	}
+	var usage UsageError
+	if errors.As(err, &usage) {
+		return fmt.Errorf("%v\nusage: %s", err, a.usageLine(c))
+	}
+	return err
+}
+
+// flagSet returns the flags of c along with the global ones.
+func (a *App) flagSet(c *Command) *flag.FlagSet {
+	fs := flag.NewFlagSet(c.Name, flag.ContinueOnError)
+	if c.Flags != nil {
+		c.Flags(fs)
+	}
+	if g := a.globalFlags(); g != nil {
+		g.VisitAll(func(f *flag.Flag) { fs.Var(f.Value, f.Name, f.Usage) })
+	}
+	return fs
+}
+
+// globalFlags returns the flags declared by Globals, or nil.
+func (a *App) globalFlags() *flag.FlagSet {
+	if a.Globals != nil && a.globals == nil {
+		a.globals = flag.NewFlagSet(a.Name, flag.ContinueOnError)
+		a.Globals(a.globals)
+	}
+	return a.globals
+}
+
+// parseLeadingGlobals sets the global flags args starts with and returns
+// the rest, from the command name or first other flag on.
+func (a *App) parseLeadingGlobals(args []string) ([]string, error) {
+	fs := a.globalFlags()
+	if fs == nil {
+		return args, nil
+	}
+	for len(args) > 0 && strings.HasPrefix(args[0], "-") {
+		name, _, hasValue := strings.Cut(strings.TrimLeft(args[0], "-"), "=")
+		f := fs.Lookup(name)
+		if f == nil {
+			break
+		}
+		n := 1
+		if !hasValue && !isBoolFlag(f) {
+			n = min(2, len(args))
+		}
+		if _, err := parseArgs(fs, args[:n], false); err != nil {
+			return nil, err
+		}
+		args = args[n:]
+	}
+	return args, nil
+}
+
+// parseArgs sets the flags in args on fs and returns the other arguments in
+// order. Flags may come before, between or after positional arguments; "--"
+// ends flag parsing. With passThrough, undeclared flags are returned as
+// arguments rather than rejected, and so are help flags and "--" after the
+// first positional argument, e.g. in a prompt sent to a session.
+func parseArgs(fs *flag.FlagSet, args []string, passThrough bool) ([]string, error) {
+	var rest []string
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if passThrough && len(rest) > 0 && (arg == "--" || isHelpFlag(arg)) {
+			rest = append(rest, arg)
+			continue
+		}
+		if arg == "--" {
+			return append(rest, args[i+1:]...), nil
+		}
+		if !strings.HasPrefix(arg, "-") || arg == "-" {
+			rest = append(rest, arg)
+			continue
+		}
+		name, value, hasValue := strings.Cut(strings.TrimLeft(arg, "-"), "=")
+		if isHelpFlag(arg) {
+			return nil, errHelp
+		}
+		f := fs.Lookup(name)
+		if f == nil {
+			if passThrough {
+				rest = append(rest, arg)
+				continue
+			}
+			return nil, UsageError(fmt.Sprintf("unknown flag %s", arg))
+		}
+		if !hasValue {
+			if isBoolFlag(f) {
+				value = "true"
+			} else {
+				if i+1 >= len(args) {
+					return nil, UsageError(fmt.Sprintf("flag %s needs a value", arg))
+				}
+				i++
+				value = args[i]
+			}
+		}
+		if err := fs.Set(name, value); err != nil {
+			return nil, UsageError(fmt.Sprintf("invalid value %q for %s: %v", value, arg, err))
+		}
+	}
+	return rest, nil
+}
+
+// isHelpFlag reports whether arg asks for help: -h, --help and the like.
+func isHelpFlag(arg string) bool {
+	name, _, _ := strings.Cut(strings.TrimLeft(arg, "-"), "=")
+	return strings.HasPrefix(arg, "-") && (name == "h" || name == "help")
+}
+
+// isBoolFlag reports whether f takes no value, like flag.BoolVar flags.
+func isBoolFlag(f *flag.Flag) bool {
+	b, ok := f.Value.(interface{ IsBoolFlag() bool })
+	return ok && b.IsBoolFlag()
+}
+
+// usageLine returns "name command usage" for c.
+func (a *App) usageLine(c *Command) string {
+	return strings.Join(strings.Fields(a.Name+" "+c.Name+" "+c.Usage), " ")
+}
+
+// PrintHelp writes the program help: every command with its summary, then
+// the footer.
+func (a *App) PrintHelp(w io.Writer) {
+	fmt.Fprintf(w, "%s\n\nUsage:\n", a.Summary)
+	tw := tabwriter.NewWriter(w, 0, 0, 3, ' ', 0)
+	for _, c := range a.Commands {
+		fmt.Fprintf(tw, "  %s\t%s\n", a.usageLine(c), c.Summary)
+	}
+	fmt.Fprintf(tw, "  %s --version\tShow version\n", a.Name)
+	fmt.Fprintf(tw, "  %s help [COMMAND]\tShow this help, or a command's options\n", a.Name)
+	tw.Flush()
+	if fs := a.globalFlags(); fs != nil {
+		var flags []*flag.Flag
+		fs.VisitAll(func(f *flag.Flag) { flags = append(flags, f) })
+		fmt.Fprintln(w, "\nGlobal options (before or after the command):")
+		printFlags(w, flags)
+	}
+	if a.Footer != "" {
+		fmt.Fprintf(w, "\n%s\n", strings.TrimRight(a.Footer, "\n"))
+	}
+}
+
+// PrintCommandHelp writes the usage, description and flags of c.
+func (a *App) PrintCommandHelp(w io.Writer, c *Command) {
+	fmt.Fprintf(w, "Usage: %s\n\n%s\n", a.usageLine(c), c.Summary)
+	if c.Help != "" {
+		fmt.Fprintf(w, "\n%s\n", strings.TrimRight(c.Help, "\n"))
+	}
+	fs := a.flagSet(c)
+	var flags []*flag.Flag
+	fs.VisitAll(func(f *flag.Flag) { flags = append(flags, f) })
+	if len(flags) == 0 {
+		return
+	}
+	fmt.Fprintln(w, "\nOptions:")
+	printFlags(w, flags)
+}
+
+// printFlags lists flags with their arguments, usage and defaults.
+func printFlags(w io.Writer, flags []*flag.Flag) {
+	tw := tabwriter.NewWriter(w, 0, 0, 3, ' ', 0)
+	for _, f := range flags {
+		arg, usage := flag.UnquoteUsage(f)
+		name := "--" + f.Name
+		if arg != "" {
+			name += " " + arg
+		}
+		if f.DefValue != "" && f.DefValue != "false" && f.DefValue != "0" {
+			usage += fmt.Sprintf(" (default %s)", f.DefValue)
+		}
+		fmt.Fprintf(tw, "  %s\t%s\n", name, usage)
+	}
+	tw.Flush()
+}
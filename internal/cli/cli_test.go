@@ -0,0 +1,185 @@
+package cli
+
+import (
+	"bytes"
+	"flag"
+	"strings"
+	"testing"
+)
+
+// testApp returns an app with a "logs" command that records what it ran with.
+func testApp(out *bytes.Buffer, ran *[]string, lines *int) *App {
+	return &App{
+		Name:    "prog",
+		Version: "1.0",
+		Summary: "prog - test",
+		Stdout:  out,
+		Commands: []*Command{
+			{Summary: "Default", Run: func(args []string) error { *ran = append([]string{"default"}, args...); return nil }},
+			{
+				Name:    "logs",
+				Usage:   "NAME [options]",
+				Summary: "Print logs",
+				MinArgs: 1,
+				MaxArgs: 1,
+				Flags:   func(fs *flag.FlagSet) { fs.IntVar(lines, "lines", 10, "number of `n` lines") },
+				Run:     func(args []string) error { *ran = args; return nil },
+			},
+			{
+				Name:        "new",
+				PassThrough: true,
+				Run:         func(args []string) error { *ran = args; return nil },
+			},
+		},
+	}
+}
+
+// ---------------------------------------------------------------------------
+// App.Run
+// ---------------------------------------------------------------------------
+
+func TestRun_flagsAfterPositionalArguments(t *testing.T) {
+	var out bytes.Buffer
+	var ran []string
+	lines := 0
+	if err := testApp(&out, &ran, &lines).Run([]string{"logs", "cd-x", "--lines", "5"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(ran) != 1 || ran[0] != "cd-x" || lines != 5 {
+		t.Errorf("expected logs cd-x with 5 lines, got %v with %d", ran, lines)
+	}
+}
+
+func TestRun_unknownFlagIsAnErrorWithUsage(t *testing.T) {
+	var out bytes.Buffer
+	var ran []string
+	lines := 0
+	err := testApp(&out, &ran, &lines).Run([]string{"logs", "cd-x", "--tail", "5"})
+	if err == nil || !strings.Contains(err.Error(), "unknown flag --tail") || !strings.Contains(err.Error(), "usage: prog logs NAME [options]") {
+		t.Errorf("expected unknown flag error with usage, got %v", err)
+	}
+	if ran != nil {
+		t.Errorf("expected the command not to run, got %v", ran)
+	}
+}
+
+func TestRun_argumentCountIsChecked(t *testing.T) {
+	var out bytes.Buffer
+	var ran []string
+	lines := 0
+	a := testApp(&out, &ran, &lines)
+	if err := a.Run([]string{"logs"}); err == nil || !strings.Contains(err.Error(), "missing arguments") {
+		t.Errorf("expected missing arguments, got %v", err)
+	}
+	if err := a.Run([]string{"logs", "a", "b"}); err == nil || !strings.Contains(err.Error(), `unexpected argument "b"`) {
+		t.Errorf("expected unexpected argument, got %v", err)
+	}
+}
+
+func TestRun_passThroughKeepsUndeclaredFlags(t *testing.T) {
+	var out bytes.Buffer
+	var ran []string
+	lines := 0
+	if err := testApp(&out, &ran, &lines).Run([]string{"new", "foo", "-r", "--model", "opus"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if strings.Join(ran, " ") != "foo -r --model opus" {
+		t.Errorf("expected arguments kept in order, got %v", ran)
+	}
+}
+
+func TestRun_passThroughKeepsHelpFlagsAfterTheFirstArgument(t *testing.T) {
+	var out bytes.Buffer
+	var ran []string
+	lines := 0
+	a := testApp(&out, &ran, &lines)
+	if err := a.Run([]string{"new", "cd-x", "explain", "-h", "--", "--help"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if strings.Join(ran, " ") != "cd-x explain -h -- --help" || out.Len() != 0 {
+		t.Errorf("expected the words passed on, got %v and help %q", ran, out.String())
+	}
+	ran = nil
+	if err := a.Run([]string{"new", "--help"}); err != nil || ran != nil || !strings.Contains(out.String(), "Usage: prog new") {
+		t.Errorf("expected help before any argument, got %v, %v, %q", err, ran, out.String())
+	}
+}
+
+func TestRun_commandHelpListsFlags(t *testing.T) {
+	var out bytes.Buffer
+	var ran []string
+	lines := 0
+	if err := testApp(&out, &ran, &lines).Run([]string{"logs", "--help"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	got := out.String()
+	if !strings.Contains(got, "Usage: prog logs NAME [options]") || !strings.Contains(got, "--lines n") || !strings.Contains(got, "(default 10)") {
+		t.Errorf("unexpected command help:\n%s", got)
+	}
+	if ran != nil {
+		t.Errorf("expected help not to run the command, got %v", ran)
+	}
+}
+
+func TestRun_noArgumentsRunsDefaultCommand(t *testing.T) {
+	var out bytes.Buffer
+	var ran []string
+	lines := 0
+	if err := testApp(&out, &ran, &lines).Run(nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(ran) != 1 || ran[0] != "default" {
+		t.Errorf("expected the default command to run, got %v", ran)
+	}
+}
+
+func TestRun_unknownCommandIsAnError(t *testing.T) {
+	var out bytes.Buffer
+	var ran []string
+	lines := 0
+	if err := testApp(&out, &ran, &lines).Run([]string{"bogus"}); err == nil || !strings.Contains(err.Error(), `unknown command "bogus"`) {
+		t.Errorf("expected unknown command error, got %v", err)
+	}
+}
+
+func TestRun_versionAndHelp(t *testing.T) {
+	var out bytes.Buffer
+	var ran []string
+	lines := 0
+	a := testApp(&out, &ran, &lines)
+	if err := a.Run([]string{"--version"}); err != nil || out.String() != "prog 1.0\n" {
+		t.Errorf("unexpected version output %q (%v)", out.String(), err)
+	}
+	out.Reset()
+	if err := a.Run([]string{"help"}); err != nil || !strings.Contains(out.String(), "prog logs NAME [options]") {
+		t.Errorf("expected the command list, got %q (%v)", out.String(), err)
+	}
+}
+
+func TestRun_globalFlagsBeforeOrAfterTheCommand(t *testing.T) {
+	var out bytes.Buffer
+	var ran []string
+	lines := 0
+	profile := ""
+	a := testApp(&out, &ran, &lines)
+	a.Globals = func(fs *flag.FlagSet) { fs.StringVar(&profile, "profile", "", "config `name`") }
+
+	if err := a.Run([]string{"--profile", "work", "logs", "api"}); err != nil || profile != "work" || len(ran) != 1 || ran[0] != "api" {
+		t.Errorf("expected work and logs api, got %q, %v (%v)", profile, ran, err)
+	}
+	if err := a.Run([]string{"logs", "api", "--profile=home"}); err != nil || profile != "home" {
+		t.Errorf("expected home after the command, got %q (%v)", profile, err)
+	}
+	if err := a.Run([]string{"--profile", "work"}); err != nil || profile != "work" || ran[0] != "default" {
+		t.Errorf("expected the default command with work, got %q, %v (%v)", profile, ran, err)
+	}
+	if err := a.Run([]string{"--profile"}); err == nil {
+		t.Error("expected a missing value to be an error")
+	}
+
+	out.Reset()
+	a.Run([]string{"help"})
+	if !strings.Contains(out.String(), "Global options") || !strings.Contains(out.String(), "--profile name") {
+		t.Errorf("expected the global options in the help, got:\n%s", out.String())
+	}
+}
@@ -0,0 +1,54 @@
+package config
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Columns are the session table columns the columns setting can list.
+var Columns = []string{"name", "host", "project", "branch", "issue", "status", "mode", "waiting", "test", "uptime", "cpu", "mem", "tokens", "context", "activity", "path"}
+
+// MinColumnWidth is the least width a column can be given.
+const MinColumnWidth = 4
+
+// ColumnSpec is one entry of the columns setting: a column and, when
+// written as "path:40", the width it is drawn at.
+type ColumnSpec struct {
+	Name  string
+	Width int // 0 for the column's own width
+}
+
+// ParseColumn parses an entry of the columns setting such as "name" or
+// "path:40".
+func ParseColumn(s string) (ColumnSpec, error) {
+	name, width, hasWidth := strings.Cut(strings.TrimSpace(s), ":")
+	spec := ColumnSpec{Name: strings.ToLower(name)}
+	known := false
+	for _, c := range Columns {
+		if c == spec.Name {
+			known = true
+		}
+	}
+	if !known {
+		return ColumnSpec{}, fmt.Errorf("unknown column %q (one of %s)", name, strings.Join(Columns, ", "))
+	}
+	if hasWidth {
+		w, err := strconv.Atoi(width)
+		if err != nil || w < MinColumnWidth {
+			return ColumnSpec{}, fmt.Errorf("%s: width %q is not a number of at least %d", name, width, MinColumnWidth)
+		}
+		spec.Width = w
+	}
+	return spec, nil
+}
+
+// HasColumn reports whether the columns setting lists column.
+func (c *Config) HasColumn(column string) bool {
+	for _, s := range c.Columns {
+		if spec, err := ParseColumn(s); err == nil && spec.Name == column {
+			return true
+		}
+	}
+	return false
+}
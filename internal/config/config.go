@@ -1,28 +1,281 @@
 package config
 
 import (
+	"bytes"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
+	"regexp"
+	"strings"
 	"time"
 
+	"github.com/seunggabi/claude-dashboard/internal/locale"
 	"gopkg.in/yaml.v3"
 )
 
 // Config holds application configuration.
 type Config struct {
-	RefreshInterval time.Duration `yaml:"refresh_interval"`
-	SessionPrefix   string        `yaml:"session_prefix"`
-	DefaultDir      string        `yaml:"default_dir"`
-	LogHistory      int           `yaml:"log_history"`
+	RefreshInterval time.Duration         `yaml:"refresh_interval"`
+	SessionPrefix   string                `yaml:"session_prefix"`
+	Naming          Naming                `yaml:"naming"`
+	DefaultDir      string                `yaml:"default_dir"`
+	ProjectDirs     []string              `yaml:"project_dirs"`
+	LogHistory      int                   `yaml:"log_history"`
+	CollapseLines   int                   `yaml:"collapse_lines"` // lines a conversation message shows before it is collapsed
+	PlainLogs       bool                  `yaml:"plain_logs"`     // capture panes without their colors
+	RefreshMode     string                `yaml:"refresh_mode"`
+	ShowCost        bool                  `yaml:"show_cost"`
+	ShowBranch      bool                  `yaml:"show_branch"`
+	PathStyle       string                `yaml:"path_style"`
+	StatusIcons     string                `yaml:"status_icons"`
+	Density         string                `yaml:"density"`
+	Theme           string                `yaml:"theme"`
+	ThemeColors     map[string]string     `yaml:"theme_colors"`
+	PricingURL      string                `yaml:"pricing_url"`
+	Pricing         map[string]ModelPrice `yaml:"pricing"`
+	SpendCap        SpendCap              `yaml:"spend_cap"`
+	ResourceGuard   ResourceGuard         `yaml:"resource_guard"` // CPU and memory of all local sessions together
+	SlackWebhook    string                `yaml:"slack_webhook"`
+	Webhooks        []Webhook             `yaml:"webhooks"`
+	Triggers        []Trigger             `yaml:"triggers"` // inbound webhooks starting sessions under serve
+	Issues          Issues                `yaml:"issues"`
+	ArchiveAfter    time.Duration         `yaml:"archive_after"` // 0 leaves idle sessions running
+	AutoRestart     int                   `yaml:"auto_restart"`  // restarts of claude per crashed session; 0 for none
+	TestCommands    map[string]string     `yaml:"test_commands"` // by project; "*" for any other project
+	Models          []string              `yaml:"models"`        // offered by the create form, passed as --model
+	TourDone        bool                  `yaml:"tour_done"`     // the onboarding tour was shown
+	Views           []QuickView           `yaml:"views"`         // quick views after the built-in ones
+	Columns         []string              `yaml:"columns"`       // table columns in order, e.g. "name" or "path:40"; empty for the default
+	Locale          string                `yaml:"locale"`
+	Currency        Currency              `yaml:"currency"`
+	Hosts           []Host                `yaml:"hosts"`
+
+	// tmux keys setup binds; KeyNone leaves one unbound.
+	TmuxMouseToggleKey string `yaml:"tmux_mouse_toggle_key"`
+	TmuxSaveHistoryKey string `yaml:"tmux_save_history_key"`
+}
+
+// DefaultPricingURL is where `pricing update` fetches the model price table.
+const DefaultPricingURL = "https://raw.githubusercontent.com/seunggabi/claude-dashboard/main/internal/conversation/pricing.yaml"
+
+// ModelPrice overrides the price of the models whose ID starts with its key
+// in the pricing map, in US dollars per million tokens.
+type ModelPrice struct {
+	Input      float64 `yaml:"input"`
+	Output     float64 `yaml:"output"`
+	CacheWrite float64 `yaml:"cache_write"`
+	CacheRead  float64 `yaml:"cache_read"`
+}
+
+// valid reports whether no price is negative.
+func (p ModelPrice) valid() bool {
+	return p.Input >= 0 && p.Output >= 0 && p.CacheWrite >= 0 && p.CacheRead >= 0
+}
+
+// Spend cap actions. CapWarn only announces a session passing its cap;
+// CapInterrupt also stops its current turn.
+const (
+	CapWarn      = "warn"
+	CapInterrupt = "interrupt"
+)
+
+// SpendLimit is the most one session's conversation may cost. A zero field
+// is no limit.
+type SpendLimit struct {
+	Dollars float64 `yaml:"dollars,omitempty"`
+	Tokens  int     `yaml:"tokens,omitempty"` // cache reads included
+}
+
+// IsZero reports whether the limit sets no cap.
+func (l SpendLimit) IsZero() bool {
+	return l.Dollars <= 0 && l.Tokens <= 0
+}
+
+// Exceeded reports whether dollars or tokens passed the limit.
+func (l SpendLimit) Exceeded(dollars float64, tokens int) bool {
+	return (l.Dollars > 0 && dollars > l.Dollars) || (l.Tokens > 0 && tokens > l.Tokens)
+}
+
+// SpendCap caps the spend of every session, with per-session overrides
+// keyed by session name (without SessionPrefix).
+type SpendCap struct {
+	SpendLimit `yaml:",inline"`
+	Action     string                `yaml:"action,omitempty"`
+	Sessions   map[string]SpendLimit `yaml:"sessions,omitempty"`
+}
+
+// For returns the limit of the session called name.
+func (c SpendCap) For(name string) SpendLimit {
+	if l, ok := c.Sessions[name]; ok {
+		return l
+	}
+	return c.SpendLimit
+}
+
+// Enabled reports whether any session has a cap.
+func (c SpendCap) Enabled() bool {
+	if !c.SpendLimit.IsZero() {
+		return true
+	}
+	for _, l := range c.Sessions {
+		if !l.IsZero() {
+			return true
+		}
+	}
+	return false
+}
+
+// isHTTPURL reports whether s is an http or https URL.
+func isHTTPURL(s string) bool {
+	return strings.HasPrefix(s, "https://") || strings.HasPrefix(s, "http://")
+}
+
+// Refresh modes. RefreshWatch reacts to conversation log writes and tmux hooks;
+// RefreshPoll re-scans every RefreshInterval.
+const (
+	RefreshWatch = "watch"
+	RefreshPoll  = "poll"
+)
+
+// Path styles for the PATH column. PathStyleHome shows paths under the home
+// directory as ~/...; PathStyleFull shows them as they are; PathStyleBase
+// shows only the last component. Long paths are shortened in the middle.
+const (
+	PathStyleHome = "home"
+	PathStyleFull = "full"
+	PathStyleBase = "basename"
+)
+
+// Status icon sets. IconsNerd needs a Nerd Font; IconsASCII suits fonts that
+// draw the unicode glyphs double-width.
+const (
+	IconsUnicode = "unicode"
+	IconsNerd    = "nerd"
+	IconsASCII   = "ascii"
+)
+
+// Row densities of the session table, cycled with v in the dashboard.
+// DensityDetailed adds a second line with the session's last prompt.
+const (
+	DensityCompact     = "compact"
+	DensityComfortable = "comfortable"
+	DensityDetailed    = "detailed"
+)
+
+// Densities lists the row densities in the order v cycles through them.
+var Densities = []string{DensityCompact, DensityComfortable, DensityDetailed}
+
+// Color themes. ThemeLight suits terminals with a light background,
+// ThemeHighContrast low vision, and ThemeColorblind red-green color
+// blindness (deuteranopia and protanopia); theme_colors overrides single
+// colors of any theme with hex values.
+const (
+	ThemeDark         = "dark"
+	ThemeLight        = "light"
+	ThemeSolarized    = "solarized"
+	ThemeHighContrast = "high-contrast"
+	ThemeColorblind   = "colorblind"
+)
+
+// ThemeColorKeys are the color names theme_colors may override.
+var ThemeColorKeys = []string{
+	"primary", "secondary", "success", "warning", "danger", "muted",
+	"bg", "bg_light", "text", "text_dim", "selected_text",
+}
+
+// hexColor matches the #RGB and #RRGGBB colors theme_colors accepts.
+var hexColor = regexp.MustCompile(`^#([0-9a-fA-F]{3}|[0-9a-fA-F]{6})$`)
+
+// validThemeColors returns the entries of colors with a known key and a hex
+// value.
+func validThemeColors(colors map[string]string) map[string]string {
+	valid := make(map[string]string)
+	for key, value := range colors {
+		if isThemeColorKey(key) && hexColor.MatchString(value) {
+			valid[key] = value
+		}
+	}
+	return valid
+}
+
+func isThemeColorKey(key string) bool {
+	for _, k := range ThemeColorKeys {
+		if k == key {
+			return true
+		}
+	}
+	return false
+}
+
+// Host describes a remote machine whose tmux sessions are shown alongside
+// local ones. Only Name and Address are required.
+type Host struct {
+	Name         string `yaml:"name"`
+	Address      string `yaml:"address"`       // ssh destination, e.g. dev@devbox
+	Port         int    `yaml:"port"`          // ssh port (default from ssh config)
+	ProxyJump    string `yaml:"proxy_jump"`    // jump host(s), as for ssh -J
+	IdentityFile string `yaml:"identity_file"` // private key, as for ssh -i
+	TmuxPath     string `yaml:"tmux_path"`     // tmux binary on the host (default "tmux")
+	SocketName   string `yaml:"socket_name"`   // tmux socket name, as for tmux -L
+}
+
+// Validate checks that the host entry is usable.
+func (h Host) Validate() error {
+	if h.Name == "" {
+		return fmt.Errorf("host name is required")
+	}
+	if h.Address == "" {
+		return fmt.Errorf("host %s: address is required", h.Name)
+	}
+	if strings.HasPrefix(h.Address, "-") || strings.HasPrefix(h.ProxyJump, "-") {
+		return fmt.Errorf("host %s: address must not start with '-'", h.Name)
+	}
+	if h.Port < 0 || h.Port > 65535 {
+		return fmt.Errorf("host %s: invalid port %d", h.Name, h.Port)
+	}
+	return nil
 }
 
 // configFile is the YAML representation.
 type configFile struct {
-	RefreshInterval string `yaml:"refresh_interval"`
-	SessionPrefix   string `yaml:"session_prefix"`
-	DefaultDir      string `yaml:"default_dir"`
-	LogHistory      int    `yaml:"log_history"`
+	RefreshInterval string                `yaml:"refresh_interval"`
+	SessionPrefix   string                `yaml:"session_prefix"`
+	Naming          Naming                `yaml:"naming,omitempty"`
+	DefaultDir      string                `yaml:"default_dir"`
+	ProjectDirs     []string              `yaml:"project_dirs,omitempty"`
+	LogHistory      int                   `yaml:"log_history"`
+	CollapseLines   int                   `yaml:"collapse_lines,omitempty"`
+	PlainLogs       bool                  `yaml:"plain_logs,omitempty"`
+	RefreshMode     string                `yaml:"refresh_mode"`
+	ShowCost        bool                  `yaml:"show_cost"`
+	ShowBranch      bool                  `yaml:"show_branch,omitempty"`
+	PathStyle       string                `yaml:"path_style"`
+	StatusIcons     string                `yaml:"status_icons"`
+	Density         string                `yaml:"density"`
+	Theme           string                `yaml:"theme,omitempty"`
+	ThemeColors     map[string]string     `yaml:"theme_colors,omitempty"`
+	PricingURL      string                `yaml:"pricing_url,omitempty"`
+	Pricing         map[string]ModelPrice `yaml:"pricing,omitempty"`
+	SpendCap        *SpendCap             `yaml:"spend_cap,omitempty"`
+	ResourceGuard   *ResourceGuard        `yaml:"resource_guard,omitempty"`
+	SlackWebhook    string                `yaml:"slack_webhook,omitempty"`
+	Webhooks        []Webhook             `yaml:"webhooks,omitempty"`
+	Triggers        []Trigger             `yaml:"triggers,omitempty"`
+	Issues          Issues                `yaml:"issues,omitempty"`
+	ArchiveAfter    string                `yaml:"archive_after,omitempty"`
+	AutoRestart     int                   `yaml:"auto_restart,omitempty"`
+	TestCommands    map[string]string     `yaml:"test_commands,omitempty"`
+	Models          []string              `yaml:"models,omitempty"`
+	TourDone        bool                  `yaml:"tour_done,omitempty"`
+	Views           []QuickView           `yaml:"views,omitempty"`
+	Columns         []string              `yaml:"columns,omitempty"`
+	Locale          string                `yaml:"locale,omitempty"`
+	Currency        Currency              `yaml:"currency,omitempty"`
+	Hosts           []Host                `yaml:"hosts,omitempty"`
+
+	TmuxMouseToggleKey string `yaml:"tmux_mouse_toggle_key,omitempty"`
+	TmuxSaveHistoryKey string `yaml:"tmux_save_history_key,omitempty"`
 }
 
 // DefaultConfig returns the default configuration.
@@ -32,9 +285,24 @@ func DefaultConfig() *Config {
 		SessionPrefix:   "cd-",
 		DefaultDir:      "",
 		LogHistory:      1000,
+		CollapseLines:   40,
+		RefreshMode:     RefreshWatch,
+		PathStyle:       PathStyleHome,
+		StatusIcons:     IconsUnicode,
+		Density:         DensityCompact,
+		Theme:           ThemeDark,
+		PricingURL:      DefaultPricingURL,
+		Models:          DefaultModels,
+
+		TmuxMouseToggleKey: DefaultMouseToggleKey,
+		TmuxSaveHistoryKey: DefaultSaveHistoryKey,
 	}
 }
 
+// DefaultModels are the models the create form offers when none are
+// configured; aliases claude resolves to its latest models.
+var DefaultModels = []string{"opus", "sonnet", "haiku"}
+
 // ConfigDir returns the config directory path.
 func ConfigDir() string {
 	home, err := os.UserHomeDir()
@@ -44,8 +312,12 @@ func ConfigDir() string {
 	return filepath.Join(home, ".claude-dashboard")
 }
 
-// ConfigPath returns the config file path.
+// ConfigPath returns the config file path: config.yaml, or the file of the
+// profile in use (see SetProfile).
 func ConfigPath() string {
+	if profile != "" {
+		return filepath.Join(ProfilesDir(), profile+".yaml")
+	}
 	return filepath.Join(ConfigDir(), "config.yaml")
 }
 
@@ -74,16 +346,278 @@ func Load() *Config {
 	if cf.DefaultDir != "" {
 		cfg.DefaultDir = cf.DefaultDir
 	}
+	cfg.ProjectDirs = cf.ProjectDirs
+	if _, err := cf.Naming.Policy(""); err == nil {
+		cfg.Naming = cf.Naming
+	}
 	if cf.LogHistory > 0 {
 		cfg.LogHistory = cf.LogHistory
 	}
+	if cf.CollapseLines > 0 {
+		cfg.CollapseLines = cf.CollapseLines
+	}
+	if cf.RefreshMode == RefreshWatch || cf.RefreshMode == RefreshPoll {
+		cfg.RefreshMode = cf.RefreshMode
+	}
+	switch cf.PathStyle {
+	case PathStyleHome, PathStyleFull, PathStyleBase:
+		cfg.PathStyle = cf.PathStyle
+	}
+	switch cf.StatusIcons {
+	case IconsUnicode, IconsNerd, IconsASCII:
+		cfg.StatusIcons = cf.StatusIcons
+	}
+	switch cf.Density {
+	case DensityCompact, DensityComfortable, DensityDetailed:
+		cfg.Density = cf.Density
+	}
+	switch cf.Theme {
+	case ThemeDark, ThemeLight, ThemeSolarized, ThemeHighContrast, ThemeColorblind:
+		cfg.Theme = cf.Theme
+	}
+	if len(cf.ThemeColors) > 0 {
+		cfg.ThemeColors = validThemeColors(cf.ThemeColors)
+	}
+	if isHTTPURL(cf.PricingURL) {
+		cfg.PricingURL = cf.PricingURL
+	}
+	for model, p := range cf.Pricing {
+		if !p.valid() {
+			continue
+		}
+		if cfg.Pricing == nil {
+			cfg.Pricing = make(map[string]ModelPrice)
+		}
+		cfg.Pricing[model] = p
+	}
+	if isHTTPURL(cf.SlackWebhook) {
+		cfg.SlackWebhook = cf.SlackWebhook
+	}
+	for _, w := range cf.Webhooks {
+		if w.Validate() == nil {
+			cfg.Webhooks = append(cfg.Webhooks, w)
+		}
+	}
+	for _, t := range cf.Triggers {
+		if t.Validate() == nil {
+			cfg.Triggers = append(cfg.Triggers, t)
+		}
+	}
+	if d, err := time.ParseDuration(cf.ArchiveAfter); err == nil && d > 0 {
+		cfg.ArchiveAfter = d
+	}
+	if cf.AutoRestart > 0 {
+		cfg.AutoRestart = cf.AutoRestart
+	}
+	for project, command := range cf.TestCommands {
+		if strings.TrimSpace(command) == "" {
+			continue
+		}
+		if cfg.TestCommands == nil {
+			cfg.TestCommands = make(map[string]string)
+		}
+		cfg.TestCommands[project] = command
+	}
+	if len(cf.Models) > 0 && validModels(cf.Models) == nil {
+		cfg.Models = cf.Models
+	}
+	cfg.TourDone = cf.TourDone
+	for _, c := range cf.Columns {
+		if _, err := ParseColumn(c); err == nil {
+			cfg.Columns = append(cfg.Columns, c)
+		}
+	}
+	for _, v := range cf.Views {
+		if v.Validate() == nil && len(cfg.Views) < MaxQuickViews {
+			cfg.Views = append(cfg.Views, v)
+		}
+	}
+	if _, ok := locale.Parse(cf.Locale); ok {
+		cfg.Locale = cf.Locale
+	}
+	if _, err := cf.Currency.Resolve(); err == nil {
+		cfg.Currency = cf.Currency
+	}
+	if cf.Issues.Validate() == nil {
+		cfg.Issues = cf.Issues
+	}
+	if cf.SpendCap != nil {
+		cfg.SpendCap = *cf.SpendCap
+		if cfg.SpendCap.Action != CapInterrupt {
+			cfg.SpendCap.Action = CapWarn
+		}
+	}
+	if g := cf.ResourceGuard; g != nil && g.Validate() == nil {
+		cfg.ResourceGuard = *g
+		if cfg.ResourceGuard.Action == "" {
+			cfg.ResourceGuard.Action = GuardWarn
+		}
+	}
+	cfg.ShowCost = cf.ShowCost
+	cfg.ShowBranch = cf.ShowBranch
+	cfg.PlainLogs = cf.PlainLogs
+	cfg.Hosts = cf.Hosts
+	if cf.TmuxMouseToggleKey != "" && ValidateTmuxKey(cf.TmuxMouseToggleKey) == nil {
+		cfg.TmuxMouseToggleKey = cf.TmuxMouseToggleKey
+	}
+	if cf.TmuxSaveHistoryKey != "" && ValidateTmuxKey(cf.TmuxSaveHistoryKey) == nil {
+		cfg.TmuxSaveHistoryKey = cf.TmuxSaveHistoryKey
+	}
 
 	return cfg
 }
 
+// Validate reports every problem in the contents of a config file: YAML
+// errors, unknown keys and values Load would ignore. An empty result means
+// the file is used as written.
+func Validate(data []byte) []error {
+	var cf configFile
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	if err := dec.Decode(&cf); err != nil && err != io.EOF {
+		return []error{err}
+	}
+
+	var errs []error
+	oneOf := func(key, value string, allowed ...string) {
+		if value == "" {
+			return
+		}
+		for _, a := range allowed {
+			if value == a {
+				return
+			}
+		}
+		errs = append(errs, fmt.Errorf("%s: %q is not one of %s", key, value, strings.Join(allowed, ", ")))
+	}
+	if cf.RefreshInterval != "" {
+		if d, err := time.ParseDuration(cf.RefreshInterval); err != nil || d <= 0 {
+			errs = append(errs, fmt.Errorf("refresh_interval: %q is not a positive duration such as 2s", cf.RefreshInterval))
+		}
+	}
+	if cf.LogHistory < 0 {
+		errs = append(errs, fmt.Errorf("log_history: must not be negative"))
+	}
+	if cf.CollapseLines < 0 {
+		errs = append(errs, fmt.Errorf("collapse_lines: must not be negative"))
+	}
+	oneOf("refresh_mode", cf.RefreshMode, RefreshWatch, RefreshPoll)
+	oneOf("path_style", cf.PathStyle, PathStyleHome, PathStyleFull, PathStyleBase)
+	oneOf("status_icons", cf.StatusIcons, IconsUnicode, IconsNerd, IconsASCII)
+	oneOf("density", cf.Density, Densities...)
+	oneOf("theme", cf.Theme, ThemeDark, ThemeLight, ThemeSolarized, ThemeHighContrast, ThemeColorblind)
+	for key, value := range cf.ThemeColors {
+		switch {
+		case !isThemeColorKey(key):
+			errs = append(errs, fmt.Errorf("theme_colors: unknown color %q (one of %s)", key, strings.Join(ThemeColorKeys, ", ")))
+		case !hexColor.MatchString(value):
+			errs = append(errs, fmt.Errorf("theme_colors: %s: %q is not a hex color such as #7C3AED", key, value))
+		}
+	}
+	if cf.PricingURL != "" && !isHTTPURL(cf.PricingURL) {
+		errs = append(errs, fmt.Errorf("pricing_url: %q is not an http(s) URL", cf.PricingURL))
+	}
+	if _, err := cf.Naming.Policy(""); err != nil {
+		errs = append(errs, err)
+	}
+	if cf.SlackWebhook != "" && !isHTTPURL(cf.SlackWebhook) {
+		errs = append(errs, fmt.Errorf("slack_webhook: %q is not an http(s) URL", cf.SlackWebhook))
+	}
+	for _, w := range cf.Webhooks {
+		if err := w.Validate(); err != nil {
+			errs = append(errs, fmt.Errorf("webhooks: %w", err))
+		}
+	}
+	for _, t := range cf.Triggers {
+		if err := t.Validate(); err != nil {
+			errs = append(errs, fmt.Errorf("triggers: %w", err))
+		}
+	}
+	if cf.ArchiveAfter != "" {
+		if d, err := time.ParseDuration(cf.ArchiveAfter); err != nil || d <= 0 {
+			errs = append(errs, fmt.Errorf("archive_after: %q is not a positive duration such as 8h", cf.ArchiveAfter))
+		}
+	}
+	if cf.AutoRestart < 0 {
+		errs = append(errs, fmt.Errorf("auto_restart: must not be negative"))
+	}
+	for project, command := range cf.TestCommands {
+		if strings.TrimSpace(command) == "" {
+			errs = append(errs, fmt.Errorf("test_commands: %s: command is empty", project))
+		}
+	}
+	for _, v := range cf.Views {
+		if err := v.Validate(); err != nil {
+			errs = append(errs, fmt.Errorf("views: %w", err))
+		}
+	}
+	if len(cf.Views) > MaxQuickViews {
+		errs = append(errs, fmt.Errorf("views: at most %d are used, for keys 4-9", MaxQuickViews))
+	}
+	for _, c := range cf.Columns {
+		if _, err := ParseColumn(c); err != nil {
+			errs = append(errs, fmt.Errorf("columns: %w", err))
+		}
+	}
+	if err := validModels(cf.Models); err != nil {
+		errs = append(errs, fmt.Errorf("models: %w", err))
+	}
+	if _, ok := locale.Parse(cf.Locale); cf.Locale != "" && !ok {
+		errs = append(errs, fmt.Errorf("locale: unknown locale %q (e.g. en-US, de-DE, fr)", cf.Locale))
+	}
+	if _, err := cf.Currency.Resolve(); err != nil {
+		errs = append(errs, fmt.Errorf("currency: %w", err))
+	}
+	if err := cf.Issues.Validate(); err != nil {
+		errs = append(errs, fmt.Errorf("issues.%w", err))
+	}
+	for model, p := range cf.Pricing {
+		if !p.valid() {
+			errs = append(errs, fmt.Errorf("pricing: %s: prices must not be negative", model))
+		}
+	}
+	if c := cf.SpendCap; c != nil {
+		oneOf("spend_cap.action", c.Action, CapWarn, CapInterrupt)
+		limits := map[string]SpendLimit{"spend_cap": c.SpendLimit}
+		for name, l := range c.Sessions {
+			limits["spend_cap.sessions."+name] = l
+		}
+		for key, l := range limits {
+			if l.Dollars < 0 || l.Tokens < 0 {
+				errs = append(errs, fmt.Errorf("%s: limits must not be negative", key))
+			}
+		}
+	}
+	if g := cf.ResourceGuard; g != nil {
+		if err := g.Validate(); err != nil {
+			errs = append(errs, fmt.Errorf("resource_guard: %w", err))
+		}
+	}
+	if err := ValidateTmuxKey(cf.TmuxMouseToggleKey); err != nil {
+		errs = append(errs, fmt.Errorf("tmux_mouse_toggle_key: %w", err))
+	}
+	if err := ValidateTmuxKey(cf.TmuxSaveHistoryKey); err != nil {
+		errs = append(errs, fmt.Errorf("tmux_save_history_key: %w", err))
+	}
+	if k := cf.TmuxMouseToggleKey; k != "" && k != KeyNone && k == cf.TmuxSaveHistoryKey {
+		errs = append(errs, fmt.Errorf("tmux_save_history_key: %s is also the mouse toggle key", k))
+	}
+	seen := make(map[string]bool)
+	for _, h := range cf.Hosts {
+		if err := h.Validate(); err != nil {
+			errs = append(errs, fmt.Errorf("hosts: %w", err))
+		}
+		if h.Name != "" && seen[h.Name] {
+			errs = append(errs, fmt.Errorf("hosts: %s is listed twice", h.Name))
+		}
+		seen[h.Name] = true
+	}
+	return errs
+}
+
 // Save writes the configuration to file.
 func Save(cfg *Config) error {
-	dir := ConfigDir()
+	dir := filepath.Dir(ConfigPath())
 	if err := os.MkdirAll(dir, 0755); err != nil {
 		return err
 	}
@@ -91,8 +625,50 @@ func Save(cfg *Config) error {
 	cf := configFile{
 		RefreshInterval: cfg.RefreshInterval.String(),
 		SessionPrefix:   cfg.SessionPrefix,
+		Naming:          cfg.Naming,
 		DefaultDir:      cfg.DefaultDir,
+		ProjectDirs:     cfg.ProjectDirs,
 		LogHistory:      cfg.LogHistory,
+		CollapseLines:   cfg.CollapseLines,
+		PlainLogs:       cfg.PlainLogs,
+		RefreshMode:     cfg.RefreshMode,
+		ShowCost:        cfg.ShowCost,
+		ShowBranch:      cfg.ShowBranch,
+		PathStyle:       cfg.PathStyle,
+		StatusIcons:     cfg.StatusIcons,
+		Density:         cfg.Density,
+		Theme:           cfg.Theme,
+		ThemeColors:     cfg.ThemeColors,
+		PricingURL:      cfg.PricingURL,
+		Pricing:         cfg.Pricing,
+		SlackWebhook:    cfg.SlackWebhook,
+		Webhooks:        cfg.Webhooks,
+		Triggers:        cfg.Triggers,
+		Issues:          cfg.Issues,
+		AutoRestart:     cfg.AutoRestart,
+		TestCommands:    cfg.TestCommands,
+		Models:          cfg.Models,
+		TourDone:        cfg.TourDone,
+		Views:           cfg.Views,
+		Columns:         cfg.Columns,
+		Locale:          cfg.Locale,
+		Currency:        cfg.Currency,
+		Hosts:           cfg.Hosts,
+	}
+	if cfg.SpendCap.Enabled() {
+		cf.SpendCap = &cfg.SpendCap
+	}
+	if cfg.ResourceGuard.Enabled() {
+		cf.ResourceGuard = &cfg.ResourceGuard
+	}
+	if cfg.ArchiveAfter > 0 {
+		cf.ArchiveAfter = cfg.ArchiveAfter.String()
+	}
+	if cfg.TmuxMouseToggleKey != DefaultMouseToggleKey {
+		cf.TmuxMouseToggleKey = cfg.TmuxMouseToggleKey
+	}
+	if cfg.TmuxSaveHistoryKey != DefaultSaveHistoryKey {
+		cf.TmuxSaveHistoryKey = cfg.TmuxSaveHistoryKey
 	}
 
 	data, err := yaml.Marshal(&cf)
@@ -102,3 +678,102 @@ func Save(cfg *Config) error {
 
 	return os.WriteFile(ConfigPath(), data, 0644)
 }
+
+// Set changes the top-level setting key of the config file to value,
+// leaving the rest of the file, comments and entries Load would drop
+// included, as it is; a missing file is created holding just key. A file
+// that does not parse is not touched, so it is not replaced by defaults.
+func Set(key string, value any) error {
+	path := ConfigPath()
+	var doc yaml.Node
+	data, err := os.ReadFile(path)
+	switch {
+	case err == nil:
+		if err := yaml.Unmarshal(data, &doc); err != nil {
+			return fmt.Errorf("%s does not parse, so %s was not saved: %w", path, key, err)
+		}
+	case !os.IsNotExist(err):
+		return err
+	}
+	if len(doc.Content) == 0 {
+		doc = yaml.Node{Kind: yaml.DocumentNode, Content: []*yaml.Node{{Kind: yaml.MappingNode, Tag: "!!map"}}}
+	}
+	root := doc.Content[0]
+	if root.Kind != yaml.MappingNode {
+		return fmt.Errorf("%s does not hold settings, so %s was not saved", path, key)
+	}
+
+	var node yaml.Node
+	if err := node.Encode(value); err != nil {
+		return err
+	}
+	found := false
+	for i := 0; i+1 < len(root.Content); i += 2 {
+		if root.Content[i].Value == key {
+			old := root.Content[i+1]
+			node.HeadComment, node.LineComment, node.FootComment = old.HeadComment, old.LineComment, old.FootComment
+			root.Content[i+1], found = &node, true
+		}
+	}
+	if !found {
+		root.Content = append(root.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: key}, &node)
+	}
+
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(&doc); err != nil {
+		return err
+	}
+	if err := enc.Close(); err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, buf.Bytes(), 0644)
+}
+
+// modelName matches what claude takes as its --model: an alias such as
+// opus or a full model ID, possibly with a suffix like [1m].
+var modelName = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._:/@\[\]-]*$`)
+
+// MaxQuickViews is how many views can be configured: the number keys
+// left after the built-in waiting, active and mine views.
+const MaxQuickViews = 6
+
+// QuickView is a named filter the dashboard switches to with a number key.
+type QuickView struct {
+	Name   string `yaml:"name"`
+	Filter string `yaml:"filter"` // as typed after /, e.g. "tag:api status:waiting"
+}
+
+// Validate checks the view has a name and a filter.
+func (v QuickView) Validate() error {
+	if strings.TrimSpace(v.Name) == "" {
+		return fmt.Errorf("a view needs a name")
+	}
+	if strings.TrimSpace(v.Filter) == "" {
+		return fmt.Errorf("%s: filter is empty", v.Name)
+	}
+	return nil
+}
+
+// validModels checks every model is a model name.
+func validModels(models []string) error {
+	for _, model := range models {
+		if !modelName.MatchString(model) {
+			return fmt.Errorf("%q is not a model name", model)
+		}
+	}
+	return nil
+}
+
+// TestCommand returns the test command configured for project, falling
+// back to the "*" entry; "" if there is none.
+func (c *Config) TestCommand(project string) string {
+	if command, ok := c.TestCommands[project]; ok {
+		return command
+	}
+	return c.TestCommands["*"]
+}
@@ -3,6 +3,8 @@ package config
 import (
 	"os"
 	"path/filepath"
+	"reflect"
+	"strings"
 	"testing"
 	"time"
 )
@@ -135,6 +137,15 @@ func TestLoad_overridesLogHistory(t *testing.T) {
 	}
 }
 
+func TestLoad_overridesCollapseLines(t *testing.T) {
+	restore := writeTempConfig(t, "collapse_lines: 15\n")
+	defer restore()
+
+	if cfg := Load(); cfg.CollapseLines != 15 {
+		t.Errorf("expected 15, got %d", cfg.CollapseLines)
+	}
+}
+
 func TestLoad_invalidYAMLFallsBackToDefaults(t *testing.T) {
 	restore := writeTempConfig(t, ":::not valid yaml:::")
 	defer restore()
@@ -202,6 +213,9 @@ func TestSave_writesConfigThatCanBeLoadedBack(t *testing.T) {
 		SessionPrefix:   "test-",
 		DefaultDir:      "/tmp",
 		LogHistory:      250,
+		PlainLogs:       true,
+		TourDone:        true,
+		Issues:          Issues{URL: "https://acme.atlassian.net/browse/{key}", Projects: []string{"ENG"}},
 	}
 	if err := Save(original); err != nil {
 		t.Fatalf("Save() failed: %v", err)
@@ -219,4 +233,844 @@ func TestSave_writesConfigThatCanBeLoadedBack(t *testing.T) {
 	if loaded.LogHistory != original.LogHistory {
 		t.Errorf("LogHistory: expected %d, got %d", original.LogHistory, loaded.LogHistory)
 	}
+	if !loaded.PlainLogs {
+		t.Error("PlainLogs: expected colors to stay off")
+	}
+	if !loaded.TourDone {
+		t.Error("TourDone: expected the tour to stay done")
+	}
+	if loaded.Issues.URL != original.Issues.URL || len(loaded.Issues.Projects) != 1 {
+		t.Errorf("Issues: expected %+v, got %+v", original.Issues, loaded.Issues)
+	}
+}
+
+// ---------------------------------------------------------------------------
+// Hosts
+// ---------------------------------------------------------------------------
+
+func TestLoad_readsHosts(t *testing.T) {
+	restore := writeTempConfig(t, `hosts:
+  - name: dev
+    address: me@devbox
+    proxy_jump: bastion
+    identity_file: ~/.ssh/id_dev
+    tmux_path: /opt/homebrew/bin/tmux
+    socket_name: work
+    port: 2222
+`)
+	defer restore()
+
+	cfg := Load()
+	if len(cfg.Hosts) != 1 {
+		t.Fatalf("expected 1 host, got %d", len(cfg.Hosts))
+	}
+	h := cfg.Hosts[0]
+	if h.Name != "dev" || h.Address != "me@devbox" || h.ProxyJump != "bastion" ||
+		h.IdentityFile != "~/.ssh/id_dev" || h.TmuxPath != "/opt/homebrew/bin/tmux" ||
+		h.SocketName != "work" || h.Port != 2222 {
+		t.Errorf("unexpected host: %+v", h)
+	}
+}
+
+func TestHostValidate_tableTests(t *testing.T) {
+	cases := []struct {
+		name    string
+		host    Host
+		wantErr bool
+	}{
+		{"valid", Host{Name: "dev", Address: "devbox"}, false},
+		{"missing name", Host{Address: "devbox"}, true},
+		{"missing address", Host{Name: "dev"}, true},
+		{"option-like address", Host{Name: "dev", Address: "-oProxyCommand=x"}, true},
+		{"option-like jump", Host{Name: "dev", Address: "devbox", ProxyJump: "-x"}, true},
+		{"bad port", Host{Name: "dev", Address: "devbox", Port: 70000}, true},
+	}
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.host.Validate()
+			if (err != nil) != tc.wantErr {
+				t.Errorf("Validate(): wantErr=%v, got %v", tc.wantErr, err)
+			}
+		})
+	}
+}
+
+// ---------------------------------------------------------------------------
+// RefreshMode
+// ---------------------------------------------------------------------------
+
+func TestDefaultConfig_refreshModeIsWatch(t *testing.T) {
+	if got := DefaultConfig().RefreshMode; got != RefreshWatch {
+		t.Errorf("expected %q, got %q", RefreshWatch, got)
+	}
+}
+
+func TestLoad_overridesRefreshMode(t *testing.T) {
+	restore := writeTempConfig(t, "refresh_mode: poll\n")
+	defer restore()
+
+	if got := Load().RefreshMode; got != RefreshPoll {
+		t.Errorf("expected %q, got %q", RefreshPoll, got)
+	}
+}
+
+func TestLoad_unknownRefreshModeKeepsDefault(t *testing.T) {
+	restore := writeTempConfig(t, "refresh_mode: sometimes\n")
+	defer restore()
+
+	if got := Load().RefreshMode; got != RefreshWatch {
+		t.Errorf("expected default %q, got %q", RefreshWatch, got)
+	}
+}
+
+// ---------------------------------------------------------------------------
+// PathStyle
+// ---------------------------------------------------------------------------
+
+func TestLoad_overridesPathStyle(t *testing.T) {
+	restore := writeTempConfig(t, "path_style: basename\n")
+	defer restore()
+
+	if got := Load().PathStyle; got != PathStyleBase {
+		t.Errorf("expected %q, got %q", PathStyleBase, got)
+	}
+}
+
+func TestLoad_unknownPathStyleKeepsDefault(t *testing.T) {
+	restore := writeTempConfig(t, "path_style: fancy\n")
+	defer restore()
+
+	if got := Load().PathStyle; got != PathStyleHome {
+		t.Errorf("expected default %q, got %q", PathStyleHome, got)
+	}
+}
+
+// ---------------------------------------------------------------------------
+// StatusIcons
+// ---------------------------------------------------------------------------
+
+func TestLoad_overridesStatusIcons(t *testing.T) {
+	restore := writeTempConfig(t, "status_icons: ascii\n")
+	defer restore()
+
+	if got := Load().StatusIcons; got != IconsASCII {
+		t.Errorf("expected %q, got %q", IconsASCII, got)
+	}
+}
+
+func TestLoad_unknownStatusIconsKeepsDefault(t *testing.T) {
+	restore := writeTempConfig(t, "status_icons: emoji\n")
+	defer restore()
+
+	if got := Load().StatusIcons; got != IconsUnicode {
+		t.Errorf("expected default %q, got %q", IconsUnicode, got)
+	}
+}
+
+// ---------------------------------------------------------------------------
+// Density
+// ---------------------------------------------------------------------------
+
+func TestLoad_overridesDensity(t *testing.T) {
+	restore := writeTempConfig(t, "density: detailed\n")
+	defer restore()
+
+	if got := Load().Density; got != DensityDetailed {
+		t.Errorf("expected %q, got %q", DensityDetailed, got)
+	}
+}
+
+func TestLoad_unknownDensityKeepsDefault(t *testing.T) {
+	restore := writeTempConfig(t, "density: roomy\n")
+	defer restore()
+
+	if got := Load().Density; got != DensityCompact {
+		t.Errorf("expected default %q, got %q", DensityCompact, got)
+	}
+}
+
+// ---------------------------------------------------------------------------
+// Theme
+// ---------------------------------------------------------------------------
+
+func TestLoad_defaultThemeIsDark(t *testing.T) {
+	restore := writeTempConfig(t, "")
+	defer restore()
+
+	if got := Load().Theme; got != ThemeDark {
+		t.Errorf("expected %q, got %q", ThemeDark, got)
+	}
+}
+
+func TestLoad_overridesTheme(t *testing.T) {
+	restore := writeTempConfig(t, "theme: solarized\n")
+	defer restore()
+
+	if got := Load().Theme; got != ThemeSolarized {
+		t.Errorf("expected %q, got %q", ThemeSolarized, got)
+	}
+}
+
+func TestLoad_accessibleThemes(t *testing.T) {
+	for _, theme := range []string{ThemeHighContrast, ThemeColorblind} {
+		restore := writeTempConfig(t, "theme: "+theme+"\n")
+		if got := Load().Theme; got != theme {
+			t.Errorf("expected %q, got %q", theme, got)
+		}
+		restore()
+	}
+}
+
+func TestLoad_unknownThemeKeepsDefault(t *testing.T) {
+	restore := writeTempConfig(t, "theme: neon\n")
+	defer restore()
+
+	if got := Load().Theme; got != ThemeDark {
+		t.Errorf("expected default %q, got %q", ThemeDark, got)
+	}
+}
+
+func TestLoad_themeColorsKeepOnlyValidHex(t *testing.T) {
+	restore := writeTempConfig(t, "theme_colors:\n  primary: \"#FF8800\"\n  text: \"#abc\"\n  danger: red\n  sparkle: \"#FFFFFF\"\n")
+	defer restore()
+
+	got := Load().ThemeColors
+	want := map[string]string{"primary": "#FF8800", "text": "#abc"}
+	if len(got) != len(want) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+	for k, v := range want {
+		if got[k] != v {
+			t.Errorf("%s: expected %q, got %q", k, v, got[k])
+		}
+	}
+}
+
+// ---------------------------------------------------------------------------
+// Pricing
+// ---------------------------------------------------------------------------
+
+func TestLoad_pricingOverridesSkipNegativePrices(t *testing.T) {
+	restore := writeTempConfig(t, "pricing:\n  claude-opus-4-5: {input: 4, output: 20}\n  claude-bad: {input: -1}\n")
+	defer restore()
+
+	cfg := Load()
+	if len(cfg.Pricing) != 1 || cfg.Pricing["claude-opus-4-5"].Output != 20 {
+		t.Errorf("expected only the claude-opus-4-5 override, got %+v", cfg.Pricing)
+	}
+	if cfg.PricingURL != DefaultPricingURL {
+		t.Errorf("expected the default pricing URL, got %q", cfg.PricingURL)
+	}
+}
+
+func TestLoad_pricingURLMustBeHTTP(t *testing.T) {
+	restore := writeTempConfig(t, "pricing_url: file:///etc/passwd\n")
+	defer restore()
+
+	if got := Load().PricingURL; got != DefaultPricingURL {
+		t.Errorf("expected the default pricing URL, got %q", got)
+	}
+}
+
+// ---------------------------------------------------------------------------
+// SpendCap
+// ---------------------------------------------------------------------------
+
+func TestLoad_spendCapWithSessionOverrides(t *testing.T) {
+	restore := writeTempConfig(t, "spend_cap:\n  dollars: 5\n  action: interrupt\n  sessions:\n    big: {tokens: 1000}\n")
+	defer restore()
+
+	c := Load().SpendCap
+	if !c.Enabled() || c.Action != CapInterrupt {
+		t.Fatalf("expected an enabled interrupting cap, got %+v", c)
+	}
+	if got := c.For("api"); got.Dollars != 5 {
+		t.Errorf("expected the default $5 cap, got %+v", got)
+	}
+	if got := c.For("big"); got.Dollars != 0 || got.Tokens != 1000 {
+		t.Errorf("expected the session's own 1000 token cap, got %+v", got)
+	}
+}
+
+func TestLoad_unknownSpendCapActionWarns(t *testing.T) {
+	restore := writeTempConfig(t, "spend_cap:\n  dollars: 5\n  action: explode\n")
+	defer restore()
+
+	if got := Load().SpendCap.Action; got != CapWarn {
+		t.Errorf("expected %q, got %q", CapWarn, got)
+	}
+}
+
+func TestSpendLimit_exceeded(t *testing.T) {
+	l := SpendLimit{Dollars: 5, Tokens: 100}
+	if l.Exceeded(5, 100) {
+		t.Error("expected reaching the limit not to exceed it")
+	}
+	if !l.Exceeded(5.01, 0) || !l.Exceeded(0, 101) {
+		t.Error("expected either dimension to exceed the limit")
+	}
+	if (SpendLimit{}).Exceeded(1e9, 1e9) {
+		t.Error("expected a zero limit never to be exceeded")
+	}
+}
+
+// ---------------------------------------------------------------------------
+// ResourceGuard
+// ---------------------------------------------------------------------------
+
+func TestLoad_resourceGuard(t *testing.T) {
+	restore := writeTempConfig(t, "resource_guard:\n  cpu: 400\n  memory: 50\n  action: pause\n")
+	defer restore()
+
+	g := Load().ResourceGuard
+	if !g.Enabled() || g.Action != GuardPause {
+		t.Fatalf("expected an enabled pausing guard, got %+v", g)
+	}
+	if g.Exceeded(400, 50) {
+		t.Error("expected reaching the thresholds not to exceed them")
+	}
+	if !g.Exceeded(401, 0) || !g.Exceeded(0, 51) {
+		t.Error("expected either threshold to be exceeded")
+	}
+}
+
+func TestResourceGuard_validate(t *testing.T) {
+	tests := []struct {
+		guard ResourceGuard
+		ok    bool
+	}{
+		{ResourceGuard{CPU: 400}, true},
+		{ResourceGuard{Memory: 50, Action: GuardPause}, true},
+		{ResourceGuard{CPU: -1}, false},
+		{ResourceGuard{Memory: 150}, false},
+		{ResourceGuard{CPU: 400, Action: "kill"}, false},
+	}
+	for _, tt := range tests {
+		if err := tt.guard.Validate(); (err == nil) != tt.ok {
+			t.Errorf("Validate(%+v) = %v, want ok=%v", tt.guard, err, tt.ok)
+		}
+	}
+}
+
+// ---------------------------------------------------------------------------
+// tmux keys
+// ---------------------------------------------------------------------------
+
+func TestLoad_tmuxKeys(t *testing.T) {
+	restore := writeTempConfig(t, "tmux_mouse_toggle_key: M-m\ntmux_save_history_key: none\n")
+	defer restore()
+
+	cfg := Load()
+	if cfg.TmuxMouseToggleKey != "M-m" || cfg.TmuxSaveHistoryKey != KeyNone {
+		t.Errorf("expected M-m and none, got %q and %q", cfg.TmuxMouseToggleKey, cfg.TmuxSaveHistoryKey)
+	}
+}
+
+func TestSetsSaveHistoryKey(t *testing.T) {
+	restore := writeTempConfig(t, "tmux_mouse_toggle_key: F11\n")
+	if SetsSaveHistoryKey() {
+		t.Error("expected the save history key to be left to the default")
+	}
+	restore()
+
+	restore = writeTempConfig(t, "tmux_save_history_key: C-s\n")
+	defer restore()
+	if !SetsSaveHistoryKey() {
+		t.Error("expected an explicit C-s to count as set")
+	}
+}
+
+func TestSet_changesOnlyTheKey(t *testing.T) {
+	restore := writeTempConfig(t, `# my settings
+theme: dark # not too bright
+hosts:
+  - name: devbox # no address yet
+tmux_save_history_key: C-s # the default
+`)
+	defer restore()
+	if err := Set("tmux_save_history_key", "M-s"); err != nil {
+		t.Fatalf("Set: %v", err)
+	}
+	if err := Set("density", DensityCompact); err != nil {
+		t.Fatalf("Set: %v", err)
+	}
+	data, _ := os.ReadFile(ConfigPath())
+	for _, want := range []string{"# my settings", "theme: dark # not too bright", "- name: devbox # no address yet", "tmux_save_history_key: M-s # the default", "density: compact"} {
+		if !strings.Contains(string(data), want) {
+			t.Errorf("expected %q kept in\n%s", want, data)
+		}
+	}
+}
+
+func TestSet_leavesAFileThatDoesNotParse(t *testing.T) {
+	broken := "theme: dark\nhosts: [devbox\n"
+	restore := writeTempConfig(t, broken)
+	defer restore()
+	if err := Set("tour_done", true); err == nil {
+		t.Error("expected an error for a config that does not parse")
+	}
+	if data, _ := os.ReadFile(ConfigPath()); string(data) != broken {
+		t.Errorf("expected the file left as it was, got %q", data)
+	}
+}
+
+func TestValidateTmuxKey(t *testing.T) {
+	for _, key := range []string{"", "F12", "C-s", "M-m", "C-M-PPage", "none", "~"} {
+		if err := ValidateTmuxKey(key); err != nil {
+			t.Errorf("expected %q to be valid, got %v", key, err)
+		}
+	}
+	for _, key := range []string{"C-", "F12 C-s", `"`, "C-;", "#"} {
+		if ValidateTmuxKey(key) == nil {
+			t.Errorf("expected %q to be refused", key)
+		}
+	}
+	if errs := Validate([]byte("tmux_mouse_toggle_key: F11\ntmux_save_history_key: F11\n")); len(errs) != 1 {
+		t.Errorf("expected one key bound twice, got %v", errs)
+	}
+}
+
+// ---------------------------------------------------------------------------
+// Validate
+// ---------------------------------------------------------------------------
+
+func TestValidate_validFileHasNoProblems(t *testing.T) {
+	data := "refresh_interval: 3s\nrefresh_mode: poll\ndensity: detailed\nhosts:\n  - name: devbox\n    address: dev@devbox\n"
+	if errs := Validate([]byte(data)); len(errs) != 0 {
+		t.Errorf("expected no problems, got %v", errs)
+	}
+	if errs := Validate(nil); len(errs) != 0 {
+		t.Errorf("expected an empty file to be valid, got %v", errs)
+	}
+}
+
+func TestValidate_reportsEveryIgnoredValue(t *testing.T) {
+	data := "refresh_interval: soon\npath_style: short\nlog_history: -1\ncollapse_lines: -5\nhosts:\n  - name: a\n  - name: a\n    address: x\n"
+	errs := Validate([]byte(data))
+	var all []string
+	for _, err := range errs {
+		all = append(all, err.Error())
+	}
+	got := strings.Join(all, "\n")
+	for _, want := range []string{"refresh_interval", `path_style: "short"`, "log_history", "collapse_lines", "host a: address is required", "a is listed twice"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("expected a problem mentioning %q, got:\n%s", want, got)
+		}
+	}
+}
+
+func TestValidate_unknownKeyIsAProblem(t *testing.T) {
+	errs := Validate([]byte("refresh_intervall: 3s\n"))
+	if len(errs) != 1 || !strings.Contains(errs[0].Error(), "refresh_intervall") {
+		t.Errorf("expected the misspelt key reported, got %v", errs)
+	}
+}
+
+func TestValidate_reportsBadThemeColors(t *testing.T) {
+	data := "theme: neon\ntheme_colors:\n  primary: purple\n  sparkle: \"#FFFFFF\"\n  text: \"#111827\"\n"
+	errs := Validate([]byte(data))
+	if len(errs) != 3 {
+		t.Fatalf("expected 3 problems, got %v", errs)
+	}
+	var all []string
+	for _, err := range errs {
+		all = append(all, err.Error())
+	}
+	got := strings.Join(all, "\n")
+	for _, want := range []string{`theme: "neon"`, `primary: "purple"`, `"sparkle"`} {
+		if !strings.Contains(got, want) {
+			t.Errorf("expected a problem mentioning %q, got:\n%s", want, got)
+		}
+	}
+}
+
+func TestValidate_reportsBadPricing(t *testing.T) {
+	errs := Validate([]byte("pricing_url: ftp://prices\npricing:\n  claude-x: {output: -2}\n"))
+	if len(errs) != 2 {
+		t.Fatalf("expected 2 problems, got %v", errs)
+	}
+}
+
+func TestValidate_reportsBadSlackWebhook(t *testing.T) {
+	if errs := Validate([]byte("slack_webhook: hooks.slack.com/services/x\n")); len(errs) != 1 {
+		t.Fatalf("expected 1 problem, got %v", errs)
+	}
+	if errs := Validate([]byte("slack_webhook: https://hooks.slack.com/services/x\n")); len(errs) != 0 {
+		t.Fatalf("expected no problems, got %v", errs)
+	}
+}
+
+func TestValidate_reportsBadIssues(t *testing.T) {
+	if errs := Validate([]byte("issues:\n  url: https://linear.app/acme/issue\n")); len(errs) != 1 {
+		t.Fatalf("expected a URL without {key} to be a problem, got %v", errs)
+	}
+	if errs := Validate([]byte("issues:\n  projects: [eng]\n")); len(errs) != 1 {
+		t.Fatalf("expected a lower-case project to be a problem, got %v", errs)
+	}
+	if errs := Validate([]byte("issues:\n  url: https://acme.atlassian.net/browse/{key}\n  projects: [ENG, OPS2]\n")); len(errs) != 0 {
+		t.Fatalf("expected no problems, got %v", errs)
+	}
+}
+
+func TestLoad_issues(t *testing.T) {
+	restore := writeTempConfig(t, "issues:\n  url: https://linear.app/acme/issue/{key}\n  projects: [ENG]\n")
+	defer restore()
+	cfg := Load()
+	if got := cfg.Issues.Link("ENG-12"); got != "https://linear.app/acme/issue/ENG-12" {
+		t.Errorf("unexpected link %q", got)
+	}
+	if len(cfg.Issues.Projects) != 1 {
+		t.Errorf("expected the project, got %v", cfg.Issues.Projects)
+	}
+}
+
+func TestValidate_reportsBadSpendCap(t *testing.T) {
+	errs := Validate([]byte("spend_cap:\n  dollars: -1\n  action: explode\n  sessions:\n    big: {tokens: -5}\n"))
+	if len(errs) != 3 {
+		t.Fatalf("expected 3 problems, got %v", errs)
+	}
+}
+
+// ---------------------------------------------------------------------------
+// Naming
+// ---------------------------------------------------------------------------
+
+func TestNamingPolicy_templateWithValues(t *testing.T) {
+	n := Naming{Template: "cd-{team}-{project}", Values: map[string][]string{"team": {"core", "infra"}}}
+	p, err := n.Policy("cd-")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, name := range []string{"core-api", "infra-claude-dashboard"} {
+		if err := p.Check(name); err != nil {
+			t.Errorf("expected %q to follow the policy: %v", name, err)
+		}
+	}
+	for _, name := range []string{"web-api", "core-", "Core-API", "cd-core-api"} {
+		if err := p.Check(name); err == nil {
+			t.Errorf("expected %q to break the policy", name)
+		}
+	}
+	got := p.Suggest("My API", "/src/Billing Service")
+	want := []string{"core-billing-service", "infra-billing-service"}
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+}
+
+func TestNamingPolicy_regexOnly(t *testing.T) {
+	p, err := Naming{Regex: `^[a-z]+$`}.Policy("cd-")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if p.Check("api") != nil || p.Check("api2") == nil {
+		t.Error("expected only lower case letters allowed")
+	}
+	if got := p.Suggest("API", "/src/web"); strings.Join(got, ",") != "api,web" {
+		t.Errorf("expected api and web suggested, got %v", got)
+	}
+}
+
+func TestNamingPolicy_zeroIsNil(t *testing.T) {
+	if p, err := (Naming{}).Policy("cd-"); p != nil || err != nil {
+		t.Errorf("expected no policy, got %v, %v", p, err)
+	}
+}
+
+func TestValidate_reportsBadNaming(t *testing.T) {
+	for _, data := range []string{
+		"naming:\n  template: \"{team\"\n",
+		"naming:\n  regex: \"([a-z\"\n",
+		"naming:\n  template: \"{team}-{project}\"\n  values:\n    owner: [me]\n",
+	} {
+		if errs := Validate([]byte(data)); len(errs) != 1 {
+			t.Errorf("%q: expected 1 problem, got %v", data, errs)
+		}
+	}
+}
+
+// ---------------------------------------------------------------------------
+// Locale and Currency
+// ---------------------------------------------------------------------------
+
+func TestLoad_localeAndCurrency(t *testing.T) {
+	restore := writeTempConfig(t, "locale: de-DE\ncurrency:\n  code: EUR\n  rate: 0.92\n")
+	defer restore()
+
+	cfg := Load()
+	if cfg.Locale != "de-DE" {
+		t.Errorf("expected locale de-DE, got %q", cfg.Locale)
+	}
+	c, err := cfg.Currency.Resolve()
+	if err != nil || c.Code != "EUR" || c.Symbol != "€" || c.Rate != 0.92 {
+		t.Errorf("expected EUR at 0.92, got %+v, %v", c, err)
+	}
+}
+
+func TestLoad_dropsUnknownLocaleAndBadCurrency(t *testing.T) {
+	restore := writeTempConfig(t, "locale: klingon\ncurrency:\n  code: EUR\n")
+	defer restore()
+
+	cfg := Load()
+	if cfg.Locale != "" || !cfg.Currency.IsZero() {
+		t.Errorf("expected the defaults, got %q and %+v", cfg.Locale, cfg.Currency)
+	}
+}
+
+func TestValidate_reportsBadLocaleAndCurrency(t *testing.T) {
+	errs := Validate([]byte("locale: klingon\ncurrency:\n  code: euro\n  rate: 1\n"))
+	if len(errs) != 2 {
+		t.Fatalf("expected 2 problems, got %v", errs)
+	}
+	if errs := Validate([]byte("locale: fr_FR.UTF-8\ncurrency: {code: USD}\n")); len(errs) != 0 {
+		t.Fatalf("expected no problems, got %v", errs)
+	}
+}
+
+// ---------------------------------------------------------------------------
+// Webhooks
+// ---------------------------------------------------------------------------
+
+func TestLoad_webhooks(t *testing.T) {
+	restore := writeTempConfig(t, `webhooks:
+  - url: https://ci.example.com/hook
+    events: [waiting, idle]
+    idle_after: 5m
+  - url: https://n8n.example.com/hook
+  - url: https://hooks.slack.com/services/x
+    format: slack
+    long_task: 2m
+  - url: ftp://nope
+`)
+	defer restore()
+
+	hooks := Load().Webhooks
+	if len(hooks) != 3 {
+		t.Fatalf("expected the three valid webhooks, got %+v", hooks)
+	}
+	if !hooks[0].Wants(WebhookWaiting) || hooks[0].Wants(WebhookCrashed) || hooks[0].Idle() != 5*time.Minute {
+		t.Errorf("unexpected first webhook %+v", hooks[0])
+	}
+	if !hooks[1].Wants(WebhookFinished) || hooks[1].Idle() != DefaultIdleAfter || hooks[1].Long() != DefaultLongTask {
+		t.Errorf("expected every event after the default times, got %+v", hooks[1])
+	}
+	if hooks[2].Format != WebhookSlack || hooks[2].Long() != 2*time.Minute {
+		t.Errorf("expected a Slack webhook for 2m tasks, got %+v", hooks[2])
+	}
+}
+
+func TestValidate_reportsBadWebhooks(t *testing.T) {
+	errs := Validate([]byte("webhooks:\n  - url: nope\n  - url: https://x\n    events: [exploded]\n  - url: https://y\n    format: teams\n"))
+	if len(errs) != 3 {
+		t.Fatalf("expected 3 problems, got %v", errs)
+	}
+	if errs := Validate([]byte("webhooks:\n  - url: https://x\n    idle_after: soon\n")); len(errs) != 1 {
+		t.Fatalf("expected a bad duration to be a problem, got %v", errs)
+	}
+}
+
+// ---------------------------------------------------------------------------
+// Archive
+// ---------------------------------------------------------------------------
+
+func TestLoad_archiveAfter(t *testing.T) {
+	restore := writeTempConfig(t, "archive_after: 8h\n")
+	defer restore()
+
+	if got := Load().ArchiveAfter; got != 8*time.Hour {
+		t.Errorf("expected 8h, got %v", got)
+	}
+	if got := DefaultConfig().ArchiveAfter; got != 0 {
+		t.Errorf("expected archiving to be off by default, got %v", got)
+	}
+}
+
+func TestValidate_reportsBadArchiveAfter(t *testing.T) {
+	for _, v := range []string{"soon", "-1h", "0s"} {
+		if errs := Validate([]byte("archive_after: " + v + "\n")); len(errs) != 1 {
+			t.Errorf("%s: expected 1 problem, got %v", v, errs)
+		}
+	}
+}
+
+func TestLoad_autoRestart(t *testing.T) {
+	restore := writeTempConfig(t, "auto_restart: 3\n")
+	defer restore()
+
+	if got := Load().AutoRestart; got != 3 {
+		t.Errorf("expected 3, got %d", got)
+	}
+	if errs := Validate([]byte("auto_restart: -1\n")); len(errs) != 1 {
+		t.Errorf("expected 1 problem, got %v", errs)
+	}
+}
+
+func TestLoad_testCommandsByProjectWithFallback(t *testing.T) {
+	restore := writeTempConfig(t, "test_commands:\n  api: go test ./...\n  \"*\": make test\n  web: \"  \"\n")
+	defer restore()
+
+	cfg := Load()
+	if got := cfg.TestCommand("api"); got != "go test ./..." {
+		t.Errorf("expected the api command, got %q", got)
+	}
+	if got := cfg.TestCommand("web"); got != "make test" {
+		t.Errorf("expected an empty command to fall back to *, got %q", got)
+	}
+	if got := DefaultConfig().TestCommand("api"); got != "" {
+		t.Errorf("expected no command by default, got %q", got)
+	}
+	if errs := Validate([]byte("test_commands:\n  web: \"\"\n")); len(errs) != 1 {
+		t.Errorf("expected 1 problem, got %v", errs)
+	}
+}
+
+// ---------------------------------------------------------------------------
+// Models
+// ---------------------------------------------------------------------------
+
+func TestLoad_models(t *testing.T) {
+	restore := writeTempConfig(t, "models: [claude-opus-4-1, \"sonnet[1m]\"]\n")
+	defer restore()
+
+	if got := Load().Models; !reflect.DeepEqual(got, []string{"claude-opus-4-1", "sonnet[1m]"}) {
+		t.Errorf("expected the configured models, got %v", got)
+	}
+	if got := DefaultConfig().Models; !reflect.DeepEqual(got, DefaultModels) {
+		t.Errorf("expected the default models, got %v", got)
+	}
+}
+
+func TestLoad_badModelsKeepDefaults(t *testing.T) {
+	restore := writeTempConfig(t, "models: [opus, \"sonnet; rm -rf ~\"]\n")
+	defer restore()
+
+	if got := Load().Models; !reflect.DeepEqual(got, DefaultModels) {
+		t.Errorf("expected the default models, got %v", got)
+	}
+	if errs := Validate([]byte("models: [opus, \"\", \"a b\"]\n")); len(errs) != 1 {
+		t.Errorf("expected 1 problem, got %v", errs)
+	}
+}
+
+// ---------------------------------------------------------------------------
+// Views
+// ---------------------------------------------------------------------------
+
+func TestLoad_viewsSkipIncompleteOnes(t *testing.T) {
+	restore := writeTempConfig(t, "views:\n  - name: frontend\n    filter: tag:frontend\n  - name: empty\n")
+	defer restore()
+
+	if got := Load().Views; !reflect.DeepEqual(got, []QuickView{{Name: "frontend", Filter: "tag:frontend"}}) {
+		t.Errorf("expected only the frontend view, got %v", got)
+	}
+	if errs := Validate([]byte("views:\n  - name: empty\n  - filter: status:idle\n")); len(errs) != 2 {
+		t.Errorf("expected 2 problems, got %v", errs)
+	}
+}
+
+// ---------------------------------------------------------------------------
+// Columns
+// ---------------------------------------------------------------------------
+
+func TestParseColumn_nameAndWidth(t *testing.T) {
+	if spec, err := ParseColumn("Path:40"); err != nil || spec != (ColumnSpec{Name: "path", Width: 40}) {
+		t.Errorf("expected path at 40, got %+v (%v)", spec, err)
+	}
+	for _, bad := range []string{"colour", "name:wide", "name:2"} {
+		if _, err := ParseColumn(bad); err == nil {
+			t.Errorf("%q: expected an error", bad)
+		}
+	}
+}
+
+func TestLoad_columnsKeepKnownOnes(t *testing.T) {
+	restore := writeTempConfig(t, "columns: [name, status, colour, tokens, \"path:30\"]\n")
+	defer restore()
+
+	cfg := Load()
+	if want := []string{"name", "status", "tokens", "path:30"}; !reflect.DeepEqual(cfg.Columns, want) {
+		t.Errorf("expected %v, got %v", want, cfg.Columns)
+	}
+	if !cfg.HasColumn("tokens") || cfg.HasColumn("cpu") {
+		t.Errorf("expected tokens listed and cpu not, got %v", cfg.Columns)
+	}
+	if errs := Validate([]byte("columns: [name, colour]\n")); len(errs) != 1 {
+		t.Errorf("expected 1 problem, got %v", errs)
+	}
+}
+
+// ---------------------------------------------------------------------------
+// Triggers
+// ---------------------------------------------------------------------------
+
+func TestLoad_triggersNeedSecretSessionAndPath(t *testing.T) {
+	restore := writeTempConfig(t, `triggers:
+  - name: github
+    secret: s3cret
+    event: issues
+    match: {label.name: ai-fix}
+    session: fix-{issue.number}
+    path: ~/src/{repository.name}
+  - name: unsigned
+    session: x
+    path: /src
+`)
+	defer restore()
+
+	triggers := Load().Triggers
+	if len(triggers) != 1 || triggers[0].Name != "github" || triggers[0].Match["label.name"] != "ai-fix" {
+		t.Errorf("expected only the github trigger, got %+v", triggers)
+	}
+}
+
+func TestValidate_reportsBadTriggers(t *testing.T) {
+	errs := Validate([]byte("triggers:\n  - name: a/b\n    secret: s\n    session: x\n    path: /\n  - name: ok\n    secret: s\n    path: /\n"))
+	if len(errs) != 2 {
+		t.Fatalf("expected 2 problems, got %v", errs)
+	}
+}
+
+// ---------------------------------------------------------------------------
+// Profiles
+// ---------------------------------------------------------------------------
+
+func TestSetProfile_switchesConfigPath(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	defer SetProfile("")
+
+	if err := SetProfile("work"); err == nil {
+		t.Error("expected an error for a profile without a file")
+	}
+	if err := SetProfile("../work"); err == nil {
+		t.Error("expected an error for a name that is not a file name")
+	}
+	if err := os.MkdirAll(ProfilesDir(), 0755); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"work", "home"} {
+		if err := os.WriteFile(filepath.Join(ProfilesDir(), name+".yaml"), []byte("session_prefix: \""+name+"-\"\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if got := Profiles(); strings.Join(got, ",") != "default,home,work" {
+		t.Errorf("Profiles() = %v, want default, home, work", got)
+	}
+
+	if err := SetProfile("work"); err != nil {
+		t.Fatalf("SetProfile(work): %v", err)
+	}
+	if Profile() != "work" || filepath.Base(ConfigPath()) != "work.yaml" {
+		t.Errorf("profile %q uses %s, want work.yaml", Profile(), ConfigPath())
+	}
+	if got := Load().SessionPrefix; got != "work-" {
+		t.Errorf("SessionPrefix = %q, want the profile's work-", got)
+	}
+
+	if err := SetProfile(DefaultProfile); err != nil {
+		t.Fatalf("SetProfile(default): %v", err)
+	}
+	if Profile() != DefaultProfile || filepath.Base(ConfigPath()) != "config.yaml" {
+		t.Errorf("profile %q uses %s, want config.yaml", Profile(), ConfigPath())
+	}
 }
@@ -0,0 +1,24 @@
+package config
+
+import "github.com/seunggabi/claude-dashboard/internal/locale"
+
+// Currency is what costs are shown in. They are computed in US dollars and
+// converted at Rate; spend caps stay in dollars.
+type Currency struct {
+	Code   string  `yaml:"code"`             // ISO 4217, e.g. EUR
+	Rate   float64 `yaml:"rate"`             // units per US dollar, e.g. 0.92
+	Symbol string  `yaml:"symbol,omitempty"` // default: the usual one for Code
+}
+
+// IsZero reports whether no currency is set, leaving costs in dollars.
+func (c Currency) IsZero() bool {
+	return c == Currency{}
+}
+
+// Resolve returns the currency to format costs in: USD when none is set.
+func (c Currency) Resolve() (locale.Currency, error) {
+	if c.IsZero() {
+		return locale.USD, nil
+	}
+	return locale.NewCurrency(c.Code, c.Symbol, c.Rate)
+}
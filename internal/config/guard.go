@@ -0,0 +1,45 @@
+package config
+
+import "fmt"
+
+// Resource guard actions. GuardWarn only announces the claude sessions
+// passing a threshold together; GuardPause also pauses the heaviest session
+// nobody is attached to, until it is resumed.
+const (
+	GuardWarn  = "warn"
+	GuardPause = "pause"
+)
+
+// ResourceGuard sets how much CPU and memory the claude sessions on this
+// machine may use together. A zero threshold is no limit.
+type ResourceGuard struct {
+	CPU    float64 `yaml:"cpu,omitempty"`    // percent of one core, summed over the sessions, e.g. 400 for four cores
+	Memory float64 `yaml:"memory,omitempty"` // percent of physical memory, summed likewise
+	Action string  `yaml:"action,omitempty"` // default GuardWarn
+}
+
+// Enabled reports whether the guard has a threshold.
+func (g ResourceGuard) Enabled() bool {
+	return g.CPU > 0 || g.Memory > 0
+}
+
+// Exceeded reports whether cpu or memory passed its threshold.
+func (g ResourceGuard) Exceeded(cpu, memory float64) bool {
+	return (g.CPU > 0 && cpu > g.CPU) || (g.Memory > 0 && memory > g.Memory)
+}
+
+// Validate checks that the thresholds are not negative, memory is at most
+// all of it, and the action is known.
+func (g ResourceGuard) Validate() error {
+	switch {
+	case g.CPU < 0 || g.Memory < 0:
+		return fmt.Errorf("thresholds must not be negative")
+	case g.Memory > 100:
+		return fmt.Errorf("memory is a percentage of physical memory, at most 100")
+	}
+	switch g.Action {
+	case "", GuardWarn, GuardPause:
+		return nil
+	}
+	return fmt.Errorf("action %q is not one of %s, %s", g.Action, GuardWarn, GuardPause)
+}
@@ -0,0 +1,44 @@
+package config
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Issues is the issue tracker sessions are linked to, such as Jira or
+// Linear.
+type Issues struct {
+	URL      string   `yaml:"url,omitempty"`      // of an issue, with {key} for its key, e.g. https://acme.atlassian.net/browse/{key}
+	Projects []string `yaml:"projects,omitempty"` // key prefixes found in branch names, e.g. ENG; empty for upper-case keys only
+}
+
+// issueProject is what the prefix of an issue key looks like.
+var issueProject = regexp.MustCompile(`^[A-Z][A-Z0-9]*$`)
+
+// IsZero reports whether no issue tracker is set.
+func (i Issues) IsZero() bool {
+	return i.URL == "" && len(i.Projects) == 0
+}
+
+// Validate checks that URL is an http(s) URL with {key} in it and that
+// Projects are upper-case key prefixes.
+func (i Issues) Validate() error {
+	if i.URL != "" && (!isHTTPURL(i.URL) || !strings.Contains(i.URL, "{key}")) {
+		return fmt.Errorf("url: %q is not an http(s) URL with {key} in it", i.URL)
+	}
+	for _, p := range i.Projects {
+		if !issueProject.MatchString(p) {
+			return fmt.Errorf("projects: %q is not a key prefix such as ENG", p)
+		}
+	}
+	return nil
+}
+
+// Link returns the URL of the issue key, or "" without a URL.
+func (i Issues) Link(key string) string {
+	if i.URL == "" || key == "" {
+		return ""
+	}
+	return strings.ReplaceAll(i.URL, "{key}", key)
+}
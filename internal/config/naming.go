@@ -0,0 +1,191 @@
+package config
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// Naming is a policy for session names (without the session prefix): a
+// template of {placeholders} and literal text such as "{team}-{project}", a
+// regular expression, or both. A placeholder matches lower case letters,
+// digits and - _ . unless Values lists what it may be.
+type Naming struct {
+	Template string              `yaml:"template,omitempty"`
+	Regex    string              `yaml:"regex,omitempty"`
+	Values   map[string][]string `yaml:"values,omitempty"`
+}
+
+// IsZero reports whether no policy is set.
+func (n Naming) IsZero() bool {
+	return n.Template == "" && n.Regex == ""
+}
+
+// NamingPolicy is a compiled Naming.
+type NamingPolicy struct {
+	naming   Naming
+	template *regexp.Regexp
+	regex    *regexp.Regexp
+	pieces   []templatePiece
+}
+
+// templatePiece is literal text or, when field is set, a placeholder.
+type templatePiece struct {
+	text, field string
+}
+
+// placeholder matches one {field} of a template.
+var placeholder = regexp.MustCompile(`\{([a-z_]+)\}`)
+
+// freeField is what a placeholder without listed values matches.
+const freeField = `[a-z0-9][a-z0-9_.-]*?`
+
+// Policy compiles the policy. A template starting with prefix (e.g.
+// "cd-{team}") has it removed, since names are checked without it. It
+// returns nil for no policy.
+func (n Naming) Policy(prefix string) (*NamingPolicy, error) {
+	if n.IsZero() {
+		return nil, nil
+	}
+	p := &NamingPolicy{naming: n}
+	if n.Regex != "" {
+		re, err := regexp.Compile(n.Regex)
+		if err != nil {
+			return nil, fmt.Errorf("naming.regex: %w", err)
+		}
+		p.regex = re
+	}
+	if n.Template == "" {
+		return p, nil
+	}
+
+	tmpl := strings.TrimPrefix(n.Template, prefix)
+	if strings.ContainsAny(placeholder.ReplaceAllString(tmpl, ""), "{}") {
+		return nil, fmt.Errorf("naming.template: %q has a malformed placeholder (use {name})", n.Template)
+	}
+	var expr strings.Builder
+	expr.WriteString("^")
+	last := 0
+	for _, m := range placeholder.FindAllStringSubmatchIndex(tmpl, -1) {
+		if text := tmpl[last:m[0]]; text != "" {
+			p.pieces = append(p.pieces, templatePiece{text: text})
+			expr.WriteString(regexp.QuoteMeta(text))
+		}
+		field := tmpl[m[2]:m[3]]
+		p.pieces = append(p.pieces, templatePiece{field: field})
+		if values := n.Values[field]; len(values) > 0 {
+			quoted := make([]string, len(values))
+			for i, v := range values {
+				quoted[i] = regexp.QuoteMeta(v)
+			}
+			expr.WriteString("(" + strings.Join(quoted, "|") + ")")
+		} else {
+			expr.WriteString("(" + freeField + ")")
+		}
+		last = m[1]
+	}
+	if text := tmpl[last:]; text != "" {
+		p.pieces = append(p.pieces, templatePiece{text: text})
+		expr.WriteString(regexp.QuoteMeta(text))
+	}
+	expr.WriteString("$")
+	p.template = regexp.MustCompile(expr.String())
+	for field := range n.Values {
+		if !p.hasField(field) {
+			return nil, fmt.Errorf("naming.values: %s is not a placeholder of %q", field, n.Template)
+		}
+	}
+	return p, nil
+}
+
+func (p *NamingPolicy) hasField(field string) bool {
+	for _, piece := range p.pieces {
+		if piece.field == field {
+			return true
+		}
+	}
+	return false
+}
+
+// String describes the policy, e.g. "{team}-{project}".
+func (p *NamingPolicy) String() string {
+	switch {
+	case p.template != nil && p.regex != nil:
+		return fmt.Sprintf("%s matching /%s/", p.naming.Template, p.naming.Regex)
+	case p.template != nil:
+		return p.naming.Template
+	}
+	return "/" + p.naming.Regex + "/"
+}
+
+// Check returns an error when name does not follow the policy.
+func (p *NamingPolicy) Check(name string) error {
+	if (p.template != nil && !p.template.MatchString(name)) || (p.regex != nil && !p.regex.MatchString(name)) {
+		return fmt.Errorf("session name %q does not follow the naming policy %s", name, p)
+	}
+	return nil
+}
+
+// maxSuggestions is how many names Suggest returns at most.
+const maxSuggestions = 3
+
+// Suggest returns names following the policy for a session called name in
+// dir. A placeholder called project, repo or dir is filled from the
+// directory's name, others from name, and ones with listed values with each
+// value in turn.
+func (p *NamingPolicy) Suggest(name, dir string) []string {
+	cleanName := slug(name)
+	base := slug(filepath.Base(dir))
+	if dir == "" {
+		base = ""
+	}
+
+	candidates := []string{cleanName}
+	if p.template != nil {
+		candidates = []string{""}
+		for _, piece := range p.pieces {
+			options := []string{piece.text}
+			if piece.field != "" {
+				options = p.naming.Values[piece.field]
+				if len(options) == 0 {
+					options = []string{cleanName}
+					if base != "" && (piece.field == "project" || piece.field == "repo" || piece.field == "dir") {
+						options = []string{base}
+					}
+				}
+			}
+			var next []string
+			for _, c := range candidates {
+				for _, o := range options {
+					next = append(next, c+o)
+				}
+			}
+			candidates = next
+		}
+	} else if base != "" {
+		candidates = append(candidates, base)
+	}
+
+	var out []string
+	seen := make(map[string]bool)
+	for _, c := range candidates {
+		if c == "" || seen[c] || p.Check(c) != nil {
+			continue
+		}
+		seen[c] = true
+		out = append(out, c)
+		if len(out) == maxSuggestions {
+			break
+		}
+	}
+	return out
+}
+
+// slugUnsafe matches runs of characters a placeholder does not take.
+var slugUnsafe = regexp.MustCompile(`[^a-z0-9_.]+`)
+
+// slug lower-cases s and joins its words with -, e.g. "My API" -> "my-api".
+func slug(s string) string {
+	return strings.Trim(slugUnsafe.ReplaceAllString(strings.ToLower(s), "-"), "-_.")
+}
@@ -0,0 +1,73 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// ProfileEnv names the config profile to use when --profile is not given,
+// so commands started from a dashboard, e.g. in tmux, use the same one.
+const ProfileEnv = "CLAUDE_DASHBOARD_PROFILE"
+
+// DefaultProfile is the name config.yaml goes by among the profiles.
+const DefaultProfile = "default"
+
+// profile is the config profile Load and Save use; "" for config.yaml.
+var profile string
+
+// profileName is what a profile name looks like; it names a file.
+var profileName = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+// ProfilesDir returns the directory holding the config profiles, one
+// NAME.yaml each, written like config.yaml.
+func ProfilesDir() string {
+	return filepath.Join(ConfigDir(), "profiles")
+}
+
+// Profile returns the name of the config profile in use.
+func Profile() string {
+	if profile == "" {
+		return DefaultProfile
+	}
+	return profile
+}
+
+// SetProfile makes Load and Save use the config profile name from now on:
+// its own hosts, theme, spend cap and every other setting, in place of
+// config.yaml. Session definitions, archives and other state are shared
+// by all profiles.
+func SetProfile(name string) error {
+	if name == "" || name == DefaultProfile {
+		profile = ""
+		return nil
+	}
+	if !profileName.MatchString(name) {
+		return fmt.Errorf("invalid profile %q: use letters, digits, - and _", name)
+	}
+	path := filepath.Join(ProfilesDir(), name+".yaml")
+	if _, err := os.Stat(path); err != nil {
+		return fmt.Errorf("no profile %s: create %s, e.g. from a copy of config.yaml", name, path)
+	}
+	profile = name
+	return nil
+}
+
+// Profiles returns the names of the config profiles: DefaultProfile, then
+// those in ProfilesDir in order.
+func Profiles() []string {
+	names := []string{DefaultProfile}
+	entries, _ := os.ReadDir(ProfilesDir())
+	var found []string
+	for _, e := range entries {
+		name, ok := strings.CutSuffix(e.Name(), ".yaml")
+		if ok && !e.IsDir() && profileName.MatchString(name) && name != DefaultProfile {
+			found = append(found, name)
+		}
+	}
+	sort.Strings(found)
+	return append(names, found...)
+}
@@ -0,0 +1,47 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Default tmux keys setup binds to the mouse mode toggle and to saving the
+// pane history.
+const (
+	DefaultMouseToggleKey = "F12"
+	DefaultSaveHistoryKey = "C-s"
+)
+
+// KeyNone as a tmux key leaves its action unbound, e.g. Ctrl+S where it
+// freezes terminals with flow control on.
+const KeyNone = "none"
+
+// tmuxKeyName matches the tmux key names setup can write unquoted into
+// ~/.tmux.conf: modifiers (C-, M-, S-) before a named key such as F12 or
+// BSpace, a letter or digit, or a punctuation character tmux reads as is.
+var tmuxKeyName = regexp.MustCompile(`^([CMS]-)*([A-Za-z][A-Za-z0-9]*|[0-9]|[-!$%&*+,./:<=>?@^_|~])$`)
+
+// ValidateTmuxKey checks that key is a tmux key name or KeyNone. Empty
+// is the default key.
+func ValidateTmuxKey(key string) error {
+	if key == "" || key == KeyNone || tmuxKeyName.MatchString(key) {
+		return nil
+	}
+	return fmt.Errorf("%q is not a tmux key such as F12, C-s or M-m, or %s", key, KeyNone)
+}
+
+// SetsSaveHistoryKey reports whether the config file sets
+// tmux_save_history_key itself, rather than leaving it to the default.
+func SetsSaveHistoryKey() bool {
+	data, err := os.ReadFile(ConfigPath())
+	if err != nil {
+		return false
+	}
+	var cf struct {
+		Key string `yaml:"tmux_save_history_key"`
+	}
+	return yaml.Unmarshal(data, &cf) == nil && cf.Key != ""
+}
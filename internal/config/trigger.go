@@ -0,0 +1,41 @@
+package config
+
+import (
+	"fmt"
+	"regexp"
+)
+
+// Trigger starts a session when a signed webhook matching it is posted to
+// /hooks/NAME of serve, e.g. GitHub's when an issue is labelled ai-fix.
+// Session, Path, Args and Prompt are templates: {a.b} is replaced by that
+// field of the JSON payload, such as {issue.number}.
+type Trigger struct {
+	Name    string            `yaml:"name"`             // in the URL; triggers sharing one are tried in order
+	Secret  string            `yaml:"secret"`           // HMAC-SHA256 key of the X-Hub-Signature-256 header
+	Event   string            `yaml:"event,omitempty"`  // X-GitHub-Event header, e.g. issues; empty for any
+	Match   map[string]string `yaml:"match,omitempty"`  // payload fields and their values, e.g. label.name: ai-fix
+	Session string            `yaml:"session"`          // name of the session, e.g. fix-{issue.number}
+	Path    string            `yaml:"path"`             // working directory, e.g. ~/src/{repository.name}
+	Args    string            `yaml:"args,omitempty"`   // claude arguments
+	Prompt  string            `yaml:"prompt,omitempty"` // sent once claude is ready, on one line
+}
+
+// triggerName is what a trigger name in a URL looks like.
+var triggerName = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+// Validate checks that the trigger has a name fit for a URL, a secret, and
+// the session and path to start.
+func (t Trigger) Validate() error {
+	if !triggerName.MatchString(t.Name) {
+		return fmt.Errorf("name %q must be letters, digits, - and _", t.Name)
+	}
+	switch {
+	case t.Secret == "":
+		return fmt.Errorf("%s: secret is required to verify payloads", t.Name)
+	case t.Session == "":
+		return fmt.Errorf("%s: session is required", t.Name)
+	case t.Path == "":
+		return fmt.Errorf("%s: path is required", t.Name)
+	}
+	return nil
+}
@@ -0,0 +1,106 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Webhook events: a session starts waiting for input (e.g. to approve a
+// tool), stops after working for LongTask (done), stays idle for IdleAfter,
+// sees claude fail or goes away while working (crashed), or sees claude
+// exit or goes away otherwise (finished); or the last working session stops,
+// leaving several idle or waiting (all_quiet); or the sessions on this
+// machine pass the resource guard together (resource_guard).
+const (
+	WebhookWaiting  = "waiting"
+	WebhookDone     = "done"
+	WebhookIdle     = "idle"
+	WebhookCrashed  = "crashed"
+	WebhookFinished = "finished"
+	WebhookAllQuiet = "all_quiet"
+	WebhookGuard    = "resource_guard"
+)
+
+// WebhookEvents lists every webhook event.
+var WebhookEvents = []string{WebhookWaiting, WebhookDone, WebhookIdle, WebhookCrashed, WebhookFinished, WebhookAllQuiet, WebhookGuard}
+
+// Webhook formats. WebhookJSON posts the full payload; WebhookSlack and
+// WebhookDiscord post a message to a Slack incoming webhook or a Discord
+// channel webhook.
+const (
+	WebhookJSON    = "json"
+	WebhookSlack   = "slack"
+	WebhookDiscord = "discord"
+)
+
+// Defaults of Webhook.IdleAfter and Webhook.LongTask.
+const (
+	DefaultIdleAfter = 10 * time.Minute
+	DefaultLongTask  = 5 * time.Minute
+)
+
+// Webhook is a URL a payload is posted to when a session goes through one
+// of Events (all of them when empty).
+type Webhook struct {
+	URL       string        `yaml:"url"`
+	Format    string        `yaml:"format,omitempty"` // default WebhookJSON
+	Events    []string      `yaml:"events,omitempty"`
+	IdleAfter time.Duration `yaml:"idle_after,omitempty"` // default DefaultIdleAfter
+	LongTask  time.Duration `yaml:"long_task,omitempty"`  // default DefaultLongTask
+}
+
+// Wants reports whether the webhook is sent for event.
+func (w Webhook) Wants(event string) bool {
+	if len(w.Events) == 0 {
+		return true
+	}
+	for _, e := range w.Events {
+		if e == event {
+			return true
+		}
+	}
+	return false
+}
+
+// Idle returns how long a session is idle before the idle event.
+func (w Webhook) Idle() time.Duration {
+	if w.IdleAfter <= 0 {
+		return DefaultIdleAfter
+	}
+	return w.IdleAfter
+}
+
+// Long returns how long a session works before stopping is the done event.
+func (w Webhook) Long() time.Duration {
+	if w.LongTask <= 0 {
+		return DefaultLongTask
+	}
+	return w.LongTask
+}
+
+// Validate checks that the webhook has an http(s) URL, a known format and
+// known events.
+func (w Webhook) Validate() error {
+	if !isHTTPURL(w.URL) {
+		return fmt.Errorf("%q is not an http(s) URL", w.URL)
+	}
+	switch w.Format {
+	case "", WebhookJSON, WebhookSlack, WebhookDiscord:
+	default:
+		return fmt.Errorf("%s: format %q is not one of %s, %s, %s", w.URL, w.Format, WebhookJSON, WebhookSlack, WebhookDiscord)
+	}
+	for _, e := range w.Events {
+		known := false
+		for _, k := range WebhookEvents {
+			known = known || e == k
+		}
+		if !known {
+			return fmt.Errorf("%s: unknown event %q (one of %s)", w.URL, e, strings.Join(WebhookEvents, ", "))
+		}
+	}
+	if w.IdleAfter < 0 || w.LongTask < 0 {
+		return fmt.Errorf("%s: idle_after and long_task must not be negative", w.URL)
+	}
+	return nil
+}
@@ -0,0 +1,156 @@
+package conversation
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Activity is what happened in one conversation log during a time window.
+type Activity struct {
+	Path      string    `json:"path"`    // the log
+	Dir       string    `json:"dir"`     // working directory the conversation ran in
+	Start     time.Time `json:"start"`   // first entry in the window
+	End       time.Time `json:"end"`     // last entry in the window
+	Prompts   int       `json:"prompts"` // prompts the user typed
+	ToolCalls int       `json:"tool_calls"`
+	Topic     string    `json:"topic"` // first prompt in the window, on one line
+	Spend     Spend     `json:"spend"`
+	Blocks    []Block   `json:"blocks,omitempty"` // stretches of work, split at pauses longer than BlockGap
+}
+
+// BlockGap is the longest pause within a block of activity.
+const BlockGap = 15 * time.Minute
+
+// Block is a stretch of a conversation without pauses longer than
+// BlockGap.
+type Block struct {
+	Start     time.Time `json:"start"`
+	End       time.Time `json:"end"`
+	Prompts   int       `json:"prompts"`
+	ToolCalls int       `json:"tool_calls"`
+	Topic     string    `json:"topic"` // first prompt of the block, on one line
+	Spend     Spend     `json:"spend"`
+}
+
+// activityLine is the part of a log line Activity needs.
+type activityLine struct {
+	Type      string `json:"type"`
+	Cwd       string `json:"cwd"`
+	Timestamp string `json:"timestamp"`
+	Message   *struct {
+		msgEntry
+		ID string `json:"id"`
+	} `json:"message"`
+}
+
+// ReadActivity returns the activity of the log at path between from and to.
+// Entries without a timestamp are left out. Start is zero when nothing
+// happened in the window.
+func ReadActivity(ctx context.Context, path string, from, to time.Time) (Activity, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return Activity{}, err
+	}
+	defer f.Close()
+
+	a := Activity{Path: path}
+	lastID := ""
+	scanner := newLogScanner(f)
+	for scanner.Scan() {
+		if err := ctx.Err(); err != nil {
+			return a, err
+		}
+		var line activityLine
+		if err := json.Unmarshal(scanner.Bytes(), &line); err != nil || line.Message == nil {
+			continue
+		}
+		if line.Cwd != "" {
+			a.Dir = line.Cwd
+		}
+		ts, err := time.Parse(time.RFC3339Nano, line.Timestamp)
+		if err != nil || ts.Before(from) || !ts.Before(to) {
+			continue
+		}
+		if a.Start.IsZero() {
+			a.Start = ts
+		}
+		a.End = ts
+		if n := len(a.Blocks); n == 0 || ts.Sub(a.Blocks[n-1].End) > BlockGap {
+			a.Blocks = append(a.Blocks, Block{Start: ts})
+		}
+		block := &a.Blocks[len(a.Blocks)-1]
+		block.End = ts
+
+		switch line.Type {
+		case "user":
+			if text := strings.TrimSpace(extractContent(&line.Message.msgEntry)); text != "" {
+				topic := strings.Join(strings.Fields(text), " ")
+				a.Prompts++
+				block.Prompts++
+				if a.Topic == "" {
+					a.Topic = topic
+				}
+				if block.Topic == "" {
+					block.Topic = topic
+				}
+			}
+		case "assistant":
+			if blocks, ok := line.Message.Content.([]interface{}); ok {
+				for _, b := range blocks {
+					if m, ok := b.(map[string]interface{}); ok && m["type"] == "tool_use" {
+						a.ToolCalls++
+						block.ToolCalls++
+					}
+				}
+			}
+			// Like SpendMeter, count a message logged over several lines once.
+			u := line.Message.Usage
+			if u == nil || (line.Message.ID != "" && line.Message.ID == lastID) {
+				continue
+			}
+			lastID = line.Message.ID
+			a.Spend.add(line.Message.Model, *u)
+			block.Spend.add(line.Message.Model, *u)
+		}
+	}
+	return a, scanner.Err()
+}
+
+// Activities returns the activity between from and to of every conversation
+// log under ProjectsDir, skipping logs last written before from. Logs with
+// no activity in the window are left out; the rest are ordered by start.
+func Activities(ctx context.Context, from, to time.Time) ([]Activity, error) {
+	return activitiesIn(ctx, ProjectsDir(), from, to)
+}
+
+func activitiesIn(ctx context.Context, projectsDir string, from, to time.Time) ([]Activity, error) {
+	logs, err := filepath.Glob(filepath.Join(projectsDir, "*", "*.jsonl"))
+	if err != nil {
+		return nil, err
+	}
+	var out []Activity
+	for _, path := range logs {
+		info, err := os.Stat(path)
+		if err != nil || info.ModTime().Before(from) {
+			continue
+		}
+		a, err := ReadActivity(ctx, path, from, to)
+		if ctx.Err() != nil {
+			return out, ctx.Err()
+		}
+		if err != nil || a.Start.IsZero() {
+			continue // an unreadable log should not sink the rest
+		}
+		if a.Dir == "" {
+			a.Dir = filepath.Base(filepath.Dir(path)) // older logs lack cwd
+		}
+		out = append(out, a)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Start.Before(out[j].Start) })
+	return out, nil
+}
@@ -0,0 +1,99 @@
+package conversation
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// ---------------------------------------------------------------------------
+// ReadActivity
+// ---------------------------------------------------------------------------
+
+// activityLines span two days: one prompt on the 23rd, then a prompt, a
+// tool call logged over two lines of one message and a tool result on the
+// 24th.
+var activityLines = []string{
+	`{"type":"user","cwd":"/work/api","timestamp":"2025-11-23T23:00:00Z","message":{"role":"user","content":"old work"}}`,
+	`{"type":"user","cwd":"/work/api","timestamp":"2025-11-24T09:00:00Z","message":{"role":"user","content":"fix the\nflaky test"}}`,
+	`{"type":"assistant","cwd":"/work/api","timestamp":"2025-11-24T09:00:05Z","message":{"id":"m1","model":"claude-sonnet-4-5","content":[{"type":"text","text":"ok"}],"usage":{"output_tokens":100}}}`,
+	`{"type":"assistant","cwd":"/work/api","timestamp":"2025-11-24T09:00:06Z","message":{"id":"m1","model":"claude-sonnet-4-5","content":[{"type":"tool_use","id":"t1","name":"Bash","input":{}}],"usage":{"output_tokens":100}}}`,
+	`{"type":"user","cwd":"/work/api","timestamp":"2025-11-24T09:01:00Z","message":{"role":"user","content":[{"type":"tool_result","tool_use_id":"t1","content":"ok"}]}}`,
+}
+
+func TestReadActivity_countsOnlyTheWindow(t *testing.T) {
+	from := time.Date(2025, 11, 24, 0, 0, 0, 0, time.UTC)
+	a, err := ReadActivity(context.Background(), writeJSONLFile(t, activityLines), from, from.AddDate(0, 0, 1))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if a.Dir != "/work/api" || a.Prompts != 1 || a.ToolCalls != 1 || a.Topic != "fix the flaky test" {
+		t.Errorf("unexpected activity %+v", a)
+	}
+	if a.Spend.Tokens != 100 {
+		t.Errorf("expected the message counted once, got %+v", a.Spend)
+	}
+	if want := from.Add(9*time.Hour + time.Minute); !a.End.Equal(want) {
+		t.Errorf("expected the last entry at %v, got %v", want, a.End)
+	}
+}
+
+func TestReadActivity_splitsBlocksAtPauses(t *testing.T) {
+	lines := append(activityLines[1:],
+		`{"type":"user","cwd":"/work/api","timestamp":"2025-11-24T09:30:00Z","message":{"role":"user","content":"now the docs"}}`,
+		`{"type":"assistant","cwd":"/work/api","timestamp":"2025-11-24T09:40:00Z","message":{"id":"m2","model":"claude-sonnet-4-5","content":[{"type":"text","text":"done"}],"usage":{"output_tokens":50}}}`,
+	)
+	from := time.Date(2025, 11, 24, 0, 0, 0, 0, time.UTC)
+	a, err := ReadActivity(context.Background(), writeJSONLFile(t, lines), from, from.AddDate(0, 0, 1))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(a.Blocks) != 2 {
+		t.Fatalf("expected two blocks, got %+v", a.Blocks)
+	}
+	first, second := a.Blocks[0], a.Blocks[1]
+	if first.Topic != "fix the flaky test" || first.ToolCalls != 1 || !first.End.Equal(from.Add(9*time.Hour+time.Minute)) {
+		t.Errorf("unexpected first block %+v", first)
+	}
+	if second.Topic != "now the docs" || second.Prompts != 1 || second.Spend.Tokens != 50 || second.End.Sub(second.Start) != 10*time.Minute {
+		t.Errorf("unexpected second block %+v", second)
+	}
+}
+
+func TestReadActivity_nothingInWindow(t *testing.T) {
+	from := time.Date(2025, 12, 1, 0, 0, 0, 0, time.UTC)
+	a, err := ReadActivity(context.Background(), writeJSONLFile(t, activityLines), from, from.AddDate(0, 0, 1))
+	if err != nil || !a.Start.IsZero() || a.Prompts != 0 {
+		t.Errorf("expected no activity, got %+v, %v", a, err)
+	}
+}
+
+// ---------------------------------------------------------------------------
+// Activities
+// ---------------------------------------------------------------------------
+
+func TestActivities_skipsLogsNotWrittenInWindow(t *testing.T) {
+	projects := t.TempDir()
+	write := func(project, name string, modTime time.Time) {
+		dir := filepath.Join(projects, project)
+		os.MkdirAll(dir, 0755)
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(activityLines[1]+"\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+		os.Chtimes(path, modTime, modTime)
+	}
+	from := time.Date(2025, 11, 24, 0, 0, 0, 0, time.UTC)
+	write("-work-api", "new.jsonl", from.Add(10*time.Hour))
+	write("-work-web", "stale.jsonl", from.Add(-time.Hour))
+
+	got, err := activitiesIn(context.Background(), projects, from, from.AddDate(0, 0, 1))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(got) != 1 || filepath.Base(got[0].Path) != "new.jsonl" {
+		t.Errorf("expected only new.jsonl, got %+v", got)
+	}
+}
@@ -0,0 +1,99 @@
+package conversation
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"os"
+)
+
+// editTools are the tools that write files, with the input field naming
+// the file.
+var editTools = map[string]string{
+	"Edit":         "file_path",
+	"MultiEdit":    "file_path",
+	"Write":        "file_path",
+	"NotebookEdit": "notebook_path",
+}
+
+// EditLog collects the files a conversation's tools wrote. Like SpendMeter,
+// each Read parses only the lines appended since the previous one.
+type EditLog struct {
+	path   string
+	offset int64
+	files  []string
+	seen   map[string]bool
+}
+
+// editLine is the part of a log line EditLog needs.
+type editLine struct {
+	Type    string `json:"type"`
+	Message *struct {
+		Content json.RawMessage `json:"content"`
+	} `json:"message"`
+}
+
+// Read returns the files written in the log at path, as the tool calls name
+// them, in the order first written. A different path than last time, or a
+// log that shrank, is read from the start.
+func (l *EditLog) Read(path string) ([]string, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return l.files, err
+	}
+	if path != l.path || info.Size() < l.offset {
+		*l = EditLog{path: path, seen: make(map[string]bool)}
+	}
+	if info.Size() == l.offset {
+		return l.files, nil
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return l.files, err
+	}
+	defer f.Close()
+	if _, err := f.Seek(l.offset, io.SeekStart); err != nil {
+		return l.files, err
+	}
+	r := bufio.NewReaderSize(f, 64*1024)
+	for {
+		line, err := r.ReadBytes('\n')
+		if err == io.EOF {
+			return l.files, nil // a partial last line is read again next time
+		}
+		if err != nil {
+			return l.files, err
+		}
+		l.offset += int64(len(line))
+		l.add(line)
+	}
+}
+
+// add records the files written by the tool calls of one log line.
+func (l *EditLog) add(line []byte) {
+	var e editLine
+	if err := json.Unmarshal(line, &e); err != nil || e.Type != "assistant" || e.Message == nil {
+		return
+	}
+	var blocks []struct {
+		Type  string                     `json:"type"`
+		Name  string                     `json:"name"`
+		Input map[string]json.RawMessage `json:"input"`
+	}
+	if err := json.Unmarshal(e.Message.Content, &blocks); err != nil {
+		return // plain text content
+	}
+	for _, b := range blocks {
+		field, ok := editTools[b.Name]
+		if b.Type != "tool_use" || !ok {
+			continue
+		}
+		var file string
+		if err := json.Unmarshal(b.Input[field], &file); err != nil || file == "" || l.seen[file] {
+			continue
+		}
+		l.seen[file] = true
+		l.files = append(l.files, file)
+	}
+}
@@ -0,0 +1,46 @@
+package conversation
+
+import (
+	"os"
+	"strings"
+	"testing"
+)
+
+// ---------------------------------------------------------------------------
+// EditLog
+// ---------------------------------------------------------------------------
+
+var editLines = []string{
+	`{"type":"user","message":{"role":"user","content":"fix it"}}`,
+	`{"type":"assistant","message":{"id":"m1","content":[{"type":"tool_use","id":"t1","name":"Read","input":{"file_path":"/w/a.go"}},{"type":"tool_use","id":"t2","name":"Edit","input":{"file_path":"/w/b.go"}}]}}`,
+	`{"type":"assistant","message":{"id":"m2","content":[{"type":"tool_use","id":"t3","name":"Write","input":{"file_path":"/w/c.go"}},{"type":"tool_use","id":"t4","name":"MultiEdit","input":{"file_path":"/w/b.go"}}]}}`,
+	`{"type":"assistant","message":{"id":"m3","content":[{"type":"tool_use","id":"t5","name":"NotebookEdit","input":{"notebook_path":"/w/n.ipynb"}}]}}`,
+}
+
+func TestEditLog_filesWrittenOnceInOrder(t *testing.T) {
+	var log EditLog
+	files, err := log.Read(writeJSONLFile(t, editLines))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := strings.Join(files, ","); got != "/w/b.go,/w/c.go,/w/n.ipynb" {
+		t.Errorf("expected the written files, got %s", got)
+	}
+}
+
+func TestEditLog_readsAppendedLines(t *testing.T) {
+	path := writeJSONLFile(t, editLines[:2])
+	var log EditLog
+	if files, _ := log.Read(path); len(files) != 1 {
+		t.Fatalf("expected one file, got %v", files)
+	}
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.WriteString(editLines[2] + "\n")
+	f.Close()
+	if files, _ := log.Read(path); len(files) != 2 || files[1] != "/w/c.go" {
+		t.Errorf("expected the appended file, got %v", files)
+	}
+}
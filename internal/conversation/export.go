@@ -0,0 +1,210 @@
+package conversation
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"io"
+	"strings"
+	"time"
+)
+
+// ExportFormat is an output format for Export.
+type ExportFormat string
+
+// ExportMessages is the conversation as Anthropic Messages API JSON (see
+// Export.Messages), for replaying it elsewhere.
+const (
+	ExportMarkdown ExportFormat = "md"
+	ExportJSON     ExportFormat = "json"
+	ExportHTML     ExportFormat = "html"
+	ExportMessages ExportFormat = "messages"
+)
+
+// ParseExportFormat validates a format name given on the command line.
+func ParseExportFormat(s string) (ExportFormat, error) {
+	switch f := ExportFormat(strings.ToLower(s)); f {
+	case ExportMarkdown, ExportJSON, ExportHTML, ExportMessages:
+		return f, nil
+	case "markdown":
+		return ExportMarkdown, nil
+	case "anthropic":
+		return ExportMessages, nil
+	}
+	return "", fmt.Errorf("unknown export format %q (want md, json, html or messages)", s)
+}
+
+// ExportOptions says how to export a conversation.
+type ExportOptions struct {
+	Format ExportFormat
+	Tools  bool // keep tool calls and results in the messages format
+
+	Issue    string // key of the issue the session is linked to, if any
+	IssueURL string // link to it, if known
+}
+
+// Export describes an exported conversation.
+type Export struct {
+	Title    string    `json:"title"`
+	Source   string    `json:"source"` // path of the .jsonl log
+	Exported time.Time `json:"exported"`
+	Issue    string    `json:"issue,omitempty"`
+	IssueURL string    `json:"issue_url,omitempty"`
+	Entries  []Entry   `json:"entries"`
+	Tools    bool      `json:"-"` // see ExportOptions
+}
+
+// Write writes the export in the given format.
+func (e Export) Write(w io.Writer, format ExportFormat) error {
+	switch format {
+	case ExportMarkdown:
+		_, err := io.WriteString(w, e.markdown())
+		return err
+	case ExportJSON:
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(e)
+	case ExportHTML:
+		return htmlExport.Execute(w, e)
+	case ExportMessages:
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(e.Messages(e.Tools))
+	}
+	return fmt.Errorf("unknown export format %q", format)
+}
+
+// ExportConversation reads the whole latest conversation log of workDir and
+// writes it to w.
+func ExportConversation(w io.Writer, workDir, title string, opts ExportOptions) error {
+	path, err := LatestLog(workDir)
+	if err != nil {
+		return err
+	}
+	return ExportLog(w, path, title, opts)
+}
+
+// ExportLog reads the whole .jsonl log at path and writes it to w.
+func ExportLog(w io.Writer, path, title string, opts ExportOptions) error {
+	entries, err := ReadEntries(path)
+	if err != nil {
+		return err
+	}
+	e := Export{Title: title, Source: path, Exported: time.Now(), Issue: opts.Issue, IssueURL: opts.IssueURL, Entries: entries, Tools: opts.Tools}
+	return e.Write(w, opts.Format)
+}
+
+// entryHeading returns the role, time, model and token usage of an entry.
+func entryHeading(e Entry) string {
+	role := "User"
+	if e.Role == "assistant" {
+		role = "Assistant"
+	}
+	parts := []string{role, e.Timestamp.Local().Format("2006-01-02 15:04:05")}
+	if e.Model != "" {
+		parts = append(parts, e.Model)
+	}
+	if e.Usage != nil && !e.Usage.IsZero() {
+		parts = append(parts, fmt.Sprintf("ctx %s, out %s",
+			FormatTokens(e.Usage.ContextTokens()), FormatTokens(e.Usage.OutputTokens)))
+	}
+	return strings.Join(parts, " · ")
+}
+
+// toolInput pretty-prints a tool call's JSON input.
+func toolInput(c ToolCall) string {
+	var b bytes.Buffer
+	if err := json.Indent(&b, c.Input, "", "  "); err != nil {
+		return string(c.Input)
+	}
+	return b.String()
+}
+
+func (e Export) markdown() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# %s\n\n", e.Title)
+	fmt.Fprintf(&b, "_%d entries from `%s`, exported %s_\n",
+		len(e.Entries), e.Source, e.Exported.Local().Format("2006-01-02 15:04:05"))
+	switch {
+	case e.IssueURL != "":
+		fmt.Fprintf(&b, "\nIssue: [%s](%s)\n", e.Issue, e.IssueURL)
+	case e.Issue != "":
+		fmt.Fprintf(&b, "\nIssue: %s\n", e.Issue)
+	}
+	for _, entry := range e.Entries {
+		fmt.Fprintf(&b, "\n## %s\n\n", entryHeading(entry))
+		if entry.Text != "" {
+			b.WriteString(entry.Text)
+			b.WriteString("\n")
+		}
+		for _, c := range entry.ToolCalls {
+			fmt.Fprintf(&b, "\n**Tool call:** `%s`\n\n", c.Name)
+			writeFenced(&b, "json", toolInput(c))
+		}
+		for _, r := range entry.ToolResults {
+			label := "Tool result"
+			if r.IsError {
+				label = "Tool error"
+			}
+			fmt.Fprintf(&b, "\n**%s:**\n\n", label)
+			writeFenced(&b, "", r.Content)
+		}
+	}
+	return b.String()
+}
+
+// writeFenced writes s as a fenced code block, with a fence longer than any
+// backtick run inside s so the block cannot end early.
+func writeFenced(b *strings.Builder, lang, s string) {
+	longest, run := 0, 0
+	for _, r := range s {
+		if r == '`' {
+			run++
+			if run > longest {
+				longest = run
+			}
+		} else {
+			run = 0
+		}
+	}
+	fence := strings.Repeat("`", max(3, longest+1))
+	fmt.Fprintf(b, "%s%s\n%s\n%s\n", fence, lang, strings.TrimRight(s, "\n"), fence)
+}
+
+var htmlExport = template.Must(template.New("export").Funcs(template.FuncMap{
+	"heading":   entryHeading,
+	"toolInput": toolInput,
+	"time":      func(t time.Time) string { return t.Local().Format("2006-01-02 15:04:05") },
+}).Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>{{.Title}}</title>
+<style>
+body { font-family: -apple-system, BlinkMacSystemFont, "Segoe UI", sans-serif; max-width: 60rem; margin: 2rem auto; padding: 0 1rem; color: #1f2937; }
+.meta { color: #6b7280; }
+.entry { border-left: 4px solid #06b6d4; padding: 0 1rem; margin: 1.5rem 0; }
+.entry.assistant { border-color: #7c3aed; }
+h2 { font-size: 0.9rem; color: #6b7280; font-weight: normal; }
+.text, pre { white-space: pre-wrap; word-wrap: break-word; }
+pre { background: #f3f4f6; padding: 0.5rem; font-size: 0.85rem; }
+.error pre { background: #fee2e2; }
+</style>
+</head>
+<body>
+<h1>{{.Title}}</h1>
+<p class="meta">{{len .Entries}} entries from <code>{{.Source}}</code>, exported {{time .Exported}}</p>
+{{if .IssueURL}}<p class="meta">Issue: <a href="{{.IssueURL}}">{{.Issue}}</a></p>
+{{else if .Issue}}<p class="meta">Issue: {{.Issue}}</p>
+{{end}}{{range .Entries}}<div class="entry {{.Role}}">
+<h2>{{heading .}}</h2>
+{{if .Text}}<div class="text">{{.Text}}</div>
+{{end}}{{range .ToolCalls}}<p><strong>Tool call:</strong> <code>{{.Name}}</code></p>
+<pre>{{toolInput .}}</pre>
+{{end}}{{range .ToolResults}}<div{{if .IsError}} class="error"{{end}}><p><strong>{{if .IsError}}Tool error{{else}}Tool result{{end}}:</strong></p>
+<pre>{{.Content}}</pre></div>
+{{end}}</div>
+{{end}}</body>
+</html>
+`))
@@ -0,0 +1,177 @@
+package conversation
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+)
+
+// transcriptLines is a short log with a tool round trip.
+var transcriptLines = []string{
+	`{"type":"user","timestamp":"2025-01-01T10:00:00Z","message":{"role":"user","content":"list files"}}`,
+	`{"type":"assistant","timestamp":"2025-01-01T10:00:01Z","message":{"role":"assistant","model":"claude-opus","content":[{"type":"text","text":"Listing."},{"type":"tool_use","id":"tu1","name":"Bash","input":{"command":"ls"}}],"usage":{"input_tokens":10,"output_tokens":20}}}`,
+	`{"type":"user","timestamp":"2025-01-01T10:00:02Z","message":{"role":"user","content":[{"type":"tool_result","tool_use_id":"tu1","content":[{"type":"text","text":"a.go"}],"is_error":true}]}}`,
+	`{"type":"assistant","timestamp":"2025-01-01T10:00:03Z","message":{"role":"assistant","content":[{"type":"thinking","thinking":"..."}]}}`,
+	`{"type":"summary","summary":"ignored"}`,
+}
+
+// ---------------------------------------------------------------------------
+// ReadEntries
+// ---------------------------------------------------------------------------
+
+func TestReadEntries_keepsToolCallsAndResults(t *testing.T) {
+	entries, err := ReadEntries(writeJSONLFile(t, transcriptLines))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(entries) != 3 {
+		t.Fatalf("expected 3 entries (thinking-only and summary skipped), got %d", len(entries))
+	}
+	call := entries[1].ToolCalls
+	if len(call) != 1 || call[0].Name != "Bash" || string(call[0].Input) != `{"command":"ls"}` {
+		t.Errorf("unexpected tool calls: %+v", call)
+	}
+	if entries[1].Usage == nil || entries[1].Usage.OutputTokens != 20 {
+		t.Errorf("expected usage on the assistant entry, got %+v", entries[1].Usage)
+	}
+	res := entries[2].ToolResults
+	if len(res) != 1 || res[0].ToolUseID != "tu1" || res[0].Content != "a.go" || !res[0].IsError {
+		t.Errorf("unexpected tool results: %+v", res)
+	}
+}
+
+// ---------------------------------------------------------------------------
+// Export
+// ---------------------------------------------------------------------------
+
+func exportFixture(t *testing.T) Export {
+	t.Helper()
+	entries, err := ReadEntries(writeJSONLFile(t, transcriptLines))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return Export{Title: "demo", Source: "log.jsonl", Exported: time.Unix(1700000000, 0), Entries: entries}
+}
+
+func TestExport_markdownIncludesToolActivity(t *testing.T) {
+	var b bytes.Buffer
+	if err := exportFixture(t).Write(&b, ExportMarkdown); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	out := b.String()
+	for _, want := range []string{"# demo", "list files", "**Tool call:** `Bash`", `"command": "ls"`, "**Tool error:**", "out 20"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %q in markdown output:\n%s", want, out)
+		}
+	}
+}
+
+func TestExport_markdownLinksTheIssue(t *testing.T) {
+	e := exportFixture(t)
+	e.Issue, e.IssueURL = "ENG-7", "https://linear.app/acme/issue/ENG-7"
+	var b bytes.Buffer
+	e.Write(&b, ExportMarkdown)
+	if !strings.Contains(b.String(), "Issue: [ENG-7](https://linear.app/acme/issue/ENG-7)\n") {
+		t.Errorf("expected the issue linked in markdown output:\n%s", b.String())
+	}
+}
+
+func TestExport_jsonRoundTrips(t *testing.T) {
+	var b bytes.Buffer
+	if err := exportFixture(t).Write(&b, ExportJSON); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var got Export
+	if err := json.Unmarshal(b.Bytes(), &got); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if len(got.Entries) != 3 || got.Entries[1].ToolCalls[0].Name != "Bash" {
+		t.Errorf("unexpected entries: %+v", got.Entries)
+	}
+}
+
+func TestExport_htmlEscapesContent(t *testing.T) {
+	e := Export{Title: "demo", Entries: []Entry{{Role: "user", Text: "<script>"}}}
+	var b bytes.Buffer
+	if err := e.Write(&b, ExportHTML); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if strings.Contains(b.String(), "<script>") || !strings.Contains(b.String(), "&lt;script&gt;") {
+		t.Errorf("expected message text to be escaped:\n%s", b.String())
+	}
+}
+
+func TestExport_messagesWithoutTools(t *testing.T) {
+	got := exportFixture(t).Messages(false)
+	if got.Model != "claude-opus" || len(got.Messages) != 2 {
+		t.Fatalf("expected a user and an assistant message, got %+v", got)
+	}
+	if m := got.Messages[1]; m.Role != "assistant" || len(m.Content) != 1 || m.Content[0].Text != "Listing." {
+		t.Errorf("expected only the assistant's text, got %+v", m)
+	}
+}
+
+func TestExport_messagesWithTools(t *testing.T) {
+	e := exportFixture(t)
+	e.Tools = true
+	var b bytes.Buffer
+	if err := e.Write(&b, ExportMessages); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var got APIMessages
+	if err := json.Unmarshal(b.Bytes(), &got); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if len(got.Messages) != 3 {
+		t.Fatalf("expected the tool round trip, got %+v", got.Messages)
+	}
+	use, result := got.Messages[1].Content[1], got.Messages[2].Content[0]
+	var input bytes.Buffer
+	json.Compact(&input, use.Input)
+	if use.Type != "tool_use" || use.ID != "tu1" || input.String() != `{"command":"ls"}` {
+		t.Errorf("unexpected tool use %+v", use)
+	}
+	if result.Type != "tool_result" || result.ToolUseID != "tu1" || result.Content != "a.go" || !result.IsError {
+		t.Errorf("unexpected tool result %+v", result)
+	}
+}
+
+func TestExport_messagesStripInjectedTextAndAlternate(t *testing.T) {
+	e := Export{Entries: []Entry{
+		{Role: "assistant", Text: "left over"},
+		{Role: "user", Text: "Caveat: The messages below were generated by the user while running local commands. DO NOT respond."},
+		{Role: "user", Text: "<command-name>/clear</command-name>\n<command-message>clear</command-message>"},
+		{Role: "user", Text: "fix the bug<system-reminder>\nbe brief\n</system-reminder>"},
+		{Role: "user", Text: "in main.go"},
+		{Role: "assistant", Text: "Done.", ToolCalls: []ToolCall{{ID: "orphan", Name: "Bash"}}},
+	}, Tools: true}
+	got := e.Messages(true).Messages
+	if len(got) != 2 || got[0].Role != "user" || got[1].Role != "assistant" {
+		t.Fatalf("expected a user then an assistant message, got %+v", got)
+	}
+	if c := got[0].Content; len(c) != 2 || c[0].Text != "fix the bug" || c[1].Text != "in main.go" {
+		t.Errorf("expected the typed text only, merged, got %+v", c)
+	}
+	if c := got[1].Content; len(c) != 1 {
+		t.Errorf("expected the tool call without a result to be left out, got %+v", c)
+	}
+}
+
+func TestParseExportFormat_rejectsUnknownFormat(t *testing.T) {
+	if f, err := ParseExportFormat("HTML"); err != nil || f != ExportHTML {
+		t.Errorf("expected html, got %q (%v)", f, err)
+	}
+	if _, err := ParseExportFormat("pdf"); err == nil {
+		t.Error("expected an error for pdf")
+	}
+}
+
+func TestWriteFenced_fenceOutlastsBackticksInContent(t *testing.T) {
+	var b strings.Builder
+	writeFenced(&b, "", "```go\nx\n```")
+	if !strings.HasPrefix(b.String(), "````\n") {
+		t.Errorf("expected a four-backtick fence, got:\n%s", b.String())
+	}
+}
@@ -0,0 +1,66 @@
+package conversation
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Filter selects which messages are kept while a log is parsed. The zero
+// value keeps everything.
+type Filter struct {
+	Role  string    // "user" or "assistant"; empty for both
+	Since time.Time // drop messages older than this; zero for no limit
+	Term  string    // case-insensitive substring of the content
+}
+
+// IsZero reports whether the filter keeps every message.
+func (f Filter) IsZero() bool {
+	return f.Role == "" && f.Since.IsZero() && f.Term == ""
+}
+
+// Match reports whether msg passes the filter.
+func (f Filter) Match(msg Message) bool {
+	if f.Role != "" && msg.Role != f.Role {
+		return false
+	}
+	if !f.Since.IsZero() && msg.Timestamp.Before(f.Since) {
+		return false
+	}
+	if f.Term != "" && !strings.Contains(strings.ToLower(msg.Content), strings.ToLower(f.Term)) {
+		return false
+	}
+	return true
+}
+
+// String describes the active filters, e.g. `user · today · "panic"`.
+func (f Filter) String() string {
+	var parts []string
+	if f.Role != "" {
+		parts = append(parts, f.Role)
+	}
+	if !f.Since.IsZero() {
+		if f.Since.Equal(StartOfDay(time.Now())) {
+			parts = append(parts, "today")
+		} else {
+			parts = append(parts, "since "+f.Since.Format("2006-01-02 15:04"))
+		}
+	}
+	if f.Term != "" {
+		parts = append(parts, fmt.Sprintf("%q", f.Term))
+	}
+	return strings.Join(parts, " · ")
+}
+
+// StartOfDay returns midnight of t's day in t's location.
+func StartOfDay(t time.Time) time.Time {
+	y, m, d := t.Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, t.Location())
+}
+
+// Counts reports how many messages a filtered read kept out of all the
+// messages in the log.
+type Counts struct {
+	Matched int
+	Total   int
+}
@@ -0,0 +1,34 @@
+package conversation
+
+import "fmt"
+
+// FormatOptions controls optional parts of the message headers.
+type FormatOptions struct {
+	ShowCost bool // append per-message dollar cost to assistant headers
+}
+
+// FormatHeader renders the separator line that introduces a message.
+// maxWeight scales the heat glyph; see MaxWeight.
+func FormatHeader(msg Message, maxWeight int, opts FormatOptions) string {
+	ts := msg.Timestamp.Format("15:04:05")
+	tokens := tokenAnnotation(msg, maxWeight, opts.ShowCost)
+	switch msg.Role {
+	case "user":
+		return fmt.Sprintf("─── User [%s] ─── %s", ts, tokens)
+	case "assistant":
+		return fmt.Sprintf("─── Assistant [%s] ─── %s", ts, tokens)
+	default:
+		return fmt.Sprintf("─── %s [%s] ─── %s", msg.Role, ts, tokens)
+	}
+}
+
+// MaxWeight returns the heaviest message weight, used to scale heat glyphs.
+func MaxWeight(messages []Message) int {
+	maxWeight := 0
+	for _, msg := range messages {
+		if w := msg.Weight(); w > maxWeight {
+			maxWeight = w
+		}
+	}
+	return maxWeight
+}
@@ -0,0 +1,133 @@
+package conversation
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// previewHead is how much of the start of a log ListLogs reads for its
+// first prompt.
+const previewHead = 256 * 1024
+
+// LogFile is one conversation log of a working directory.
+type LogFile struct {
+	Path     string
+	Modified time.Time // last written
+	Size     int64
+	Started  time.Time // time of the first message; zero if none was found
+	Preview  string    // first prompt, on one line
+}
+
+// ID returns the id claude knows the conversation of l by, as taken by
+// claude --resume.
+func (l LogFile) ID() string {
+	return strings.TrimSuffix(filepath.Base(l.Path), ".jsonl")
+}
+
+// FindLog returns the conversation log of workDir whose id is id or, like
+// a short git hash, starts with it.
+func FindLog(workDir, id string) (LogFile, error) {
+	projectDir := mapToProjectDir(workDir)
+	if projectDir == "" {
+		return LogFile{}, fmt.Errorf("could not map working directory")
+	}
+	logs, err := listLogs(projectDir)
+	if err != nil {
+		return LogFile{}, err
+	}
+	return findLog(logs, id)
+}
+
+// findLog returns the one of logs whose id is or starts with id.
+func findLog(logs []LogFile, id string) (LogFile, error) {
+	var found []LogFile
+	for _, l := range logs {
+		switch {
+		case l.ID() == id:
+			return l, nil
+		case id != "" && strings.HasPrefix(l.ID(), id):
+			found = append(found, l)
+		}
+	}
+	switch len(found) {
+	case 0:
+		return LogFile{}, fmt.Errorf("no conversation %s in this directory", id)
+	case 1:
+		return found[0], nil
+	}
+	return LogFile{}, fmt.Errorf("conversation %s is ambiguous: %d conversations start with it", id, len(found))
+}
+
+// ListLogs returns the conversation logs of workDir, most recently written
+// first, each with the time and first prompt of its conversation.
+func ListLogs(workDir string) ([]LogFile, error) {
+	projectDir := mapToProjectDir(workDir)
+	if projectDir == "" {
+		return nil, fmt.Errorf("could not map working directory")
+	}
+	logs, err := listLogs(projectDir)
+	if err != nil {
+		return nil, err
+	}
+	for i := range logs {
+		logs[i].Started, logs[i].Preview = readPreview(logs[i].Path)
+	}
+	return logs, nil
+}
+
+// listLogs returns the .jsonl files in projectDir, most recently written
+// first, without reading them.
+func listLogs(projectDir string) ([]LogFile, error) {
+	entries, err := os.ReadDir(projectDir)
+	if err != nil {
+		return nil, fmt.Errorf("no conversation logs found")
+	}
+	var logs []LogFile
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".jsonl") {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+		logs = append(logs, LogFile{
+			Path:     filepath.Join(projectDir, entry.Name()),
+			Modified: info.ModTime(),
+			Size:     info.Size(),
+		})
+	}
+	sort.SliceStable(logs, func(i, j int) bool {
+		return logs[i].Modified.After(logs[j].Modified)
+	})
+	return logs, nil
+}
+
+// readPreview returns the time of the first message of the log at path and
+// its first prompt, looking only at the start of the log. Logs that cannot
+// be read have neither.
+func readPreview(path string) (time.Time, string) {
+	f, err := os.Open(path)
+	if err != nil {
+		return time.Time{}, ""
+	}
+	defer f.Close()
+
+	var started time.Time
+	scanner := newLogScanner(io.LimitReader(f, previewHead))
+	for scanner.Scan() {
+		msg, ok := scanLine(scanner.Bytes())
+		if started.IsZero() && !msg.Timestamp.IsZero() {
+			started = msg.Timestamp
+		}
+		if ok && msg.Role == "user" {
+			return started, strings.Join(strings.Fields(msg.Content), " ")
+		}
+	}
+	return started, ""
+}
@@ -0,0 +1,117 @@
+package conversation
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// ---------------------------------------------------------------------------
+// listLogs
+// ---------------------------------------------------------------------------
+
+func TestListLogs_newestFirst(t *testing.T) {
+	dir := t.TempDir()
+	at := time.Date(2025, 11, 24, 9, 0, 0, 0, time.UTC)
+	for i, name := range []string{"b.jsonl", "a.jsonl", "c.jsonl"} {
+		path := filepath.Join(dir, name)
+		_ = os.WriteFile(path, []byte(`{}`), 0644)
+		mod := at.Add(time.Duration(i) * time.Hour)
+		_ = os.Chtimes(path, mod, mod)
+	}
+	_ = os.WriteFile(filepath.Join(dir, "notes.txt"), []byte("hello"), 0644)
+
+	logs, err := listLogs(dir)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(logs) != 3 || filepath.Base(logs[0].Path) != "c.jsonl" || filepath.Base(logs[2].Path) != "b.jsonl" {
+		t.Fatalf("expected the three logs newest first, got %+v", logs)
+	}
+	if !logs[0].Modified.Equal(at.Add(2*time.Hour)) || logs[0].Size != 2 {
+		t.Errorf("unexpected file details %+v", logs[0])
+	}
+}
+
+// ---------------------------------------------------------------------------
+// findLog
+// ---------------------------------------------------------------------------
+
+func TestFindLog_byIDOrUniquePrefix(t *testing.T) {
+	logs := []LogFile{
+		{Path: "/p/1a2b3c4d-0000-4000-8000-000000000001.jsonl"},
+		{Path: "/p/1a2b9999-0000-4000-8000-000000000002.jsonl"},
+	}
+	l, err := findLog(logs, "1a2b3")
+	if err != nil || l.ID() != "1a2b3c4d-0000-4000-8000-000000000001" {
+		t.Errorf("expected the first conversation, got %+v, %v", l, err)
+	}
+	if _, err := findLog(logs, "1a2b"); err == nil || !strings.Contains(err.Error(), "ambiguous") {
+		t.Errorf("expected a prefix of both to be ambiguous, got %v", err)
+	}
+	if _, err := findLog(logs, "ffff"); err == nil {
+		t.Error("expected an unknown id to be an error")
+	}
+	if _, err := findLog(logs, ""); err == nil {
+		t.Error("expected an empty id not to match everything")
+	}
+}
+
+// ---------------------------------------------------------------------------
+// readPreview
+// ---------------------------------------------------------------------------
+
+func TestReadPreview_firstPromptOnOneLine(t *testing.T) {
+	path := writeJSONLFile(t, []string{
+		`{"type":"summary","summary":"earlier work"}`,
+		`{"type":"assistant","timestamp":"2025-11-24T09:00:00Z","message":{"role":"assistant","content":[{"type":"text","text":"hello"}]}}`,
+		`{"type":"user","timestamp":"2025-11-24T09:01:00Z","message":{"role":"user","content":"fix the\n  flaky test"}}`,
+		`{"type":"user","timestamp":"2025-11-24T09:02:00Z","message":{"role":"user","content":"thanks"}}`,
+	})
+	started, preview := readPreview(path)
+	if !started.Equal(time.Date(2025, 11, 24, 9, 0, 0, 0, time.UTC)) {
+		t.Errorf("expected the time of the first message, got %v", started)
+	}
+	if preview != "fix the flaky test" {
+		t.Errorf("expected the first prompt, got %q", preview)
+	}
+}
+
+func TestReadPreview_noPrompt(t *testing.T) {
+	started, preview := readPreview(writeJSONLFile(t, []string{`not json`}))
+	if !started.IsZero() || preview != "" {
+		t.Errorf("expected nothing, got %v %q", started, preview)
+	}
+	if started, preview := readPreview("/nonexistent/log.jsonl"); !started.IsZero() || preview != "" {
+		t.Errorf("expected nothing for a missing log, got %v %q", started, preview)
+	}
+}
+
+// ---------------------------------------------------------------------------
+// findSessionLog
+// ---------------------------------------------------------------------------
+
+func TestFindSessionLog_prefersTheProjectDirectory(t *testing.T) {
+	projects := t.TempDir()
+	for _, dir := range []string{"-src-api", "-src-web"} {
+		_ = os.MkdirAll(filepath.Join(projects, dir), 0755)
+	}
+	here := filepath.Join(projects, "-src-api", "abc.jsonl")
+	elsewhere := filepath.Join(projects, "-src-web", "def.jsonl")
+	_ = os.WriteFile(here, []byte(`{}`), 0644)
+	_ = os.WriteFile(elsewhere, []byte(`{}`), 0644)
+
+	if got := findSessionLog(projects, filepath.Join(projects, "-src-api"), "abc"); got != here {
+		t.Errorf("expected %s, got %q", here, got)
+	}
+	if got := findSessionLog(projects, filepath.Join(projects, "-src-api"), "def"); got != elsewhere {
+		t.Errorf("expected the log in another project, %s, got %q", elsewhere, got)
+	}
+	for _, id := range []string{"", "missing", "*"} {
+		if got := findSessionLog(projects, filepath.Join(projects, "-src-api"), id); got != "" {
+			t.Errorf("%q: expected no log, got %q", id, got)
+		}
+	}
+}
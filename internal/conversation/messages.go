@@ -0,0 +1,126 @@
+package conversation
+
+import (
+	"cmp"
+	"encoding/json"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// APIMessages is a conversation in the shape of an Anthropic Messages API
+// request, so its context can be replayed into another tool or a fresh
+// claude on another machine.
+type APIMessages struct {
+	Model    string       `json:"model,omitempty"` // the last model used
+	Messages []APIMessage `json:"messages"`
+}
+
+// APIMessage is one message of APIMessages.
+type APIMessage struct {
+	Role    string     `json:"role"`
+	Content []APIBlock `json:"content"`
+}
+
+// APIBlock is a content block: text, tool_use or tool_result.
+type APIBlock struct {
+	Type      string          `json:"type"`
+	Text      string          `json:"text,omitempty"`
+	ID        string          `json:"id,omitempty"`
+	Name      string          `json:"name,omitempty"`
+	Input     json.RawMessage `json:"input,omitempty"`
+	ToolUseID string          `json:"tool_use_id,omitempty"`
+	Content   string          `json:"content,omitempty"`
+	IsError   bool            `json:"is_error,omitempty"`
+}
+
+// injectedTags are what Claude Code adds to user turns rather than the user
+// typing it: system reminders, slash command markup and its local output.
+const injectedTags = "system-reminder|command-name|command-message|command-args|local-command-stdout|local-command-stderr"
+
+var injected = regexp.MustCompile(`(?s)<(?:` + injectedTags + `)>.*?</(?:` + injectedTags + `)>`)
+
+// caveat starts the note Claude Code puts before local command output.
+const caveat = "Caveat: The messages below were generated by the user while running local commands."
+
+// cleanText removes injected text from a turn.
+func cleanText(s string) string {
+	s = injected.ReplaceAllString(s, "")
+	if strings.HasPrefix(strings.TrimSpace(s), caveat) {
+		return ""
+	}
+	return strings.TrimSpace(s)
+}
+
+// Messages converts the entries to API messages. System reminders and
+// other injected text are left out, and so are tool calls and results
+// unless tools is set; tool calls without a result (or the other way
+// round) are always left out. Consecutive turns of one role are merged and
+// leading assistant turns dropped, so roles alternate starting with the
+// user, as the API requires.
+func (e Export) Messages(tools bool) APIMessages {
+	results := make(map[string]bool)
+	calls := make(map[string]bool)
+	for _, entry := range e.Entries {
+		for _, r := range entry.ToolResults {
+			results[r.ToolUseID] = true
+		}
+		for _, c := range entry.ToolCalls {
+			calls[c.ID] = true
+		}
+	}
+
+	var out APIMessages
+	for _, entry := range e.Entries {
+		var blocks []APIBlock
+		if entry.Role == "assistant" {
+			out.Model = cmp.Or(entry.Model, out.Model)
+		}
+		if tools {
+			for _, r := range entry.ToolResults {
+				if calls[r.ToolUseID] {
+					blocks = append(blocks, APIBlock{Type: "tool_result", ToolUseID: r.ToolUseID, Content: r.Content, IsError: r.IsError})
+				}
+			}
+		}
+		text := entry.Text
+		if entry.Role == "user" {
+			text = cleanText(text)
+		}
+		if strings.TrimSpace(text) != "" {
+			blocks = append(blocks, APIBlock{Type: "text", Text: text})
+		}
+		if tools {
+			for _, c := range entry.ToolCalls {
+				if results[c.ID] {
+					input := c.Input
+					if len(input) == 0 {
+						input = json.RawMessage("{}")
+					}
+					blocks = append(blocks, APIBlock{Type: "tool_use", ID: c.ID, Name: c.Name, Input: input})
+				}
+			}
+		}
+		if len(blocks) == 0 {
+			continue
+		}
+		n := len(out.Messages)
+		switch {
+		case n == 0 && entry.Role != "user":
+			continue
+		case n > 0 && out.Messages[n-1].Role == entry.Role:
+			merged := append(out.Messages[n-1].Content, blocks...)
+			// The API wants tool results before any text of a turn.
+			sort.SliceStable(merged, func(i, j int) bool {
+				return merged[i].Type == "tool_result" && merged[j].Type != "tool_result"
+			})
+			out.Messages[n-1].Content = merged
+		default:
+			out.Messages = append(out.Messages, APIMessage{Role: entry.Role, Content: blocks})
+		}
+	}
+	if out.Messages == nil {
+		out.Messages = []APIMessage{}
+	}
+	return out
+}
@@ -0,0 +1,119 @@
+package conversation
+
+import (
+	"bytes"
+	"io"
+	"os"
+)
+
+// pageChunk is how much of a log ReadPage reads at a time. It doubles while
+// no whole line fits, for logs with very long lines.
+const pageChunk = 64 * 1024
+
+// Page is a run of messages of a log, read backwards from an offset so
+// older messages can be loaded without parsing the log from the start.
+type Page struct {
+	Messages []Message // oldest first
+	Start    int64     // offset of the line of the oldest message; the next older page ends here
+}
+
+// More reports whether the log has lines before the page.
+func (p Page) More() bool {
+	return p.Start > 0
+}
+
+// ReadPage returns the last n messages matching f in the log at path that
+// start before offset end, or before the end of the log when end is
+// negative. Lines are read from end backwards, so the cost depends on how
+// far back the page is, not on the size of the log. ContextDelta is worked
+// out as Messages does.
+func ReadPage(path string, end int64, n int, f Filter) (Page, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return Page{}, err
+	}
+	defer file.Close()
+	if end < 0 {
+		info, err := file.Stat()
+		if err != nil {
+			return Page{}, err
+		}
+		end = info.Size()
+	}
+
+	var newest []Message // newest first
+	pending := -1        // index in newest of a message waiting for the usage before it
+	start := int64(0)
+	err = eachLineBackward(file, end, func(line []byte, off int64) bool {
+		e, ok := scanEntry(line)
+		if !ok {
+			return true
+		}
+		msg := Message{Role: e.Role, Content: e.Text, Timestamp: e.Timestamp, Model: e.Model}
+		if e.Usage != nil && !e.Usage.IsZero() {
+			msg.Usage = *e.Usage
+			if pending >= 0 {
+				newest[pending].ContextDelta = newest[pending].Usage.ContextTokens() - msg.Usage.ContextTokens()
+			}
+			pending = -1
+		}
+		if len(newest) < n && msg.Content != "" && f.Match(msg) {
+			newest = append(newest, msg)
+			start = off
+			if !msg.Usage.IsZero() {
+				pending = len(newest) - 1
+			}
+		}
+		return len(newest) < n || pending >= 0
+	})
+	if err != nil {
+		return Page{}, err
+	}
+	if len(newest) < n {
+		start = 0 // read to the start of the log
+	}
+
+	p := Page{Messages: make([]Message, len(newest)), Start: start}
+	for i, msg := range newest {
+		p.Messages[len(newest)-1-i] = msg
+	}
+	return p, nil
+}
+
+// eachLineBackward calls fn with each non-empty line of r before offset
+// end, last first, and the offset the line starts at, until fn returns
+// false.
+func eachLineBackward(r io.ReaderAt, end int64, fn func(line []byte, off int64) bool) error {
+	var buf []byte // read but not yet split: buf starts at pos
+	pos := end
+	chunk := int64(pageChunk)
+	for pos > 0 {
+		size := min(chunk, pos)
+		pos -= size
+		read := make([]byte, size, size+int64(len(buf)))
+		if _, err := r.ReadAt(read, pos); err != nil && err != io.EOF {
+			return err
+		}
+		buf = append(read, buf...)
+
+		split := false
+		for {
+			i := bytes.LastIndexByte(buf, '\n')
+			if i < 0 {
+				break
+			}
+			split = true
+			if line := buf[i+1:]; len(bytes.TrimSpace(line)) > 0 && !fn(line, pos+int64(i)+1) {
+				return nil
+			}
+			buf = buf[:i]
+		}
+		if !split {
+			chunk *= 2
+		}
+	}
+	if len(bytes.TrimSpace(buf)) > 0 {
+		fn(buf, 0)
+	}
+	return nil
+}
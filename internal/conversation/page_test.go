@@ -0,0 +1,86 @@
+package conversation
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// pageLines returns a log of n turns: a prompt, a tool call without text
+// and a reply, each reply with a growing context.
+func pageLines(n int) []string {
+	var lines []string
+	for i := range n {
+		ts := fmt.Sprintf("2025-11-24T09:%02d:00Z", i)
+		lines = append(lines,
+			fmt.Sprintf(`{"type":"user","timestamp":%q,"message":{"role":"user","content":"prompt %d"}}`, ts, i),
+			fmt.Sprintf(`{"type":"assistant","timestamp":%q,"message":{"role":"assistant","content":[{"type":"tool_use","id":"t%d","name":"Bash","input":{}}],"usage":{"input_tokens":%d,"output_tokens":10}}}`, ts, i, 1000*(2*i+1)),
+			`not json`,
+			fmt.Sprintf(`{"type":"assistant","timestamp":%q,"message":{"role":"assistant","content":[{"type":"text","text":"reply %d"}],"usage":{"input_tokens":%d,"output_tokens":10}}}`, ts, i, 1000*(2*i+2)),
+		)
+	}
+	return lines
+}
+
+// ---------------------------------------------------------------------------
+// ReadPage
+// ---------------------------------------------------------------------------
+
+func TestReadPage_pagesBackToTheStart(t *testing.T) {
+	path := writeJSONLFile(t, pageLines(7))
+	want, err := parseJSONL(path, 0)
+	if err != nil {
+		t.Fatalf("parseJSONL: %v", err)
+	}
+
+	var got []Message
+	end := int64(-1)
+	for {
+		p, err := ReadPage(path, end, 4, Filter{})
+		if err != nil {
+			t.Fatalf("ReadPage: %v", err)
+		}
+		got = append(p.Messages, got...)
+		if !p.More() {
+			break
+		}
+		end = p.Start
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("expected the pages to add up to the log\n got %+v\nwant %+v", got, want)
+	}
+}
+
+func TestReadPage_filters(t *testing.T) {
+	path := writeJSONLFile(t, pageLines(5))
+	p, err := ReadPage(path, -1, 2, Filter{Role: "user"})
+	if err != nil {
+		t.Fatalf("ReadPage: %v", err)
+	}
+	if len(p.Messages) != 2 || p.Messages[0].Content != "prompt 3" || p.Messages[1].Content != "prompt 4" || !p.More() {
+		t.Fatalf("unexpected page %+v", p)
+	}
+	older, err := ReadPage(path, p.Start, 10, Filter{Role: "user"})
+	if err != nil {
+		t.Fatalf("ReadPage: %v", err)
+	}
+	if len(older.Messages) != 3 || older.Messages[0].Content != "prompt 0" || older.More() {
+		t.Errorf("expected the three older prompts and nothing more, got %+v", older)
+	}
+}
+
+func TestReadPage_longLines(t *testing.T) {
+	long := strings.Repeat("x", 3*pageChunk)
+	path := writeJSONLFile(t, []string{
+		`{"type":"user","timestamp":"2025-11-24T09:00:00Z","message":{"role":"user","content":"` + long + `"}}`,
+		`{"type":"user","timestamp":"2025-11-24T09:01:00Z","message":{"role":"user","content":"short"}}`,
+	})
+	p, err := ReadPage(path, -1, 5, Filter{})
+	if err != nil {
+		t.Fatalf("ReadPage: %v", err)
+	}
+	if len(p.Messages) != 2 || p.Messages[0].Content != long || p.More() {
+		t.Errorf("expected both messages, got %d messages, more %v", len(p.Messages), p.More())
+	}
+}
@@ -0,0 +1,83 @@
+package conversation
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// PRPrompt asks claude for a pull request description of the conversation
+// it is given on stdin.
+const PRPrompt = `Below is the transcript of a coding session with an AI agent. Write a pull
+request description for the changes made in it, in GitHub markdown:
+a one-line title as a "# " heading, a short summary of what changed and why,
+a "## Changes" list, and a "## Testing" section saying what was run and
+what it showed. Describe only what the transcript shows was done; leave out
+abandoned attempts. Print only the description.`
+
+// prTextLimit is how much of a message or tool result PRTranscript keeps.
+const prTextLimit = 2000
+
+// PRTranscript renders entries as plain text for PRPrompt: prompts,
+// replies and the tools called, with long texts and tool results cut.
+// When it comes to more than limit bytes, the earliest entries are left
+// out, since the end of a session says most about what it did.
+func PRTranscript(entries []Entry, limit int) string {
+	var parts []string
+	size := 0
+	for i := len(entries) - 1; i >= 0; i-- {
+		part := prEntry(entries[i])
+		if part == "" {
+			continue
+		}
+		if limit > 0 && size+len(part) > limit {
+			parts = append(parts, "[earlier messages left out]\n")
+			break
+		}
+		size += len(part)
+		parts = append(parts, part)
+	}
+	var b strings.Builder
+	for i := len(parts) - 1; i >= 0; i-- {
+		b.WriteString(parts[i])
+	}
+	return b.String()
+}
+
+// prEntry renders one entry for PRTranscript.
+func prEntry(e Entry) string {
+	var b strings.Builder
+	if e.Text != "" {
+		role := "User"
+		if e.Role == "assistant" {
+			role = "Assistant"
+		}
+		fmt.Fprintf(&b, "%s: %s\n", role, cut(e.Text, prTextLimit))
+	}
+	for _, c := range e.ToolCalls {
+		fmt.Fprintf(&b, "Tool %s: %s\n", c.Name, cut(string(c.Input), prTextLimit))
+	}
+	for _, r := range e.ToolResults {
+		label := "Result"
+		if r.IsError {
+			label = "Error"
+		}
+		fmt.Fprintf(&b, "%s: %s\n", label, cut(r.Content, prTextLimit/4))
+	}
+	if b.Len() == 0 {
+		return ""
+	}
+	return b.String() + "\n"
+}
+
+// cut shortens s to at most n bytes, marking that it was cut.
+func cut(s string, n int) string {
+	s = strings.TrimSpace(s)
+	if len(s) <= n {
+		return s
+	}
+	for n > 0 && !utf8.RuneStart(s[n]) {
+		n--
+	}
+	return s[:n] + " […]"
+}
@@ -0,0 +1,47 @@
+package conversation
+
+import (
+	"strings"
+	"testing"
+)
+
+// ---------------------------------------------------------------------------
+// PRTranscript
+// ---------------------------------------------------------------------------
+
+func TestPRTranscript_rendersPromptsRepliesAndTools(t *testing.T) {
+	entries := []Entry{
+		{Role: "user", Text: "Add retries to the client"},
+		{Role: "assistant", Text: "Adding them.", ToolCalls: []ToolCall{{Name: "Edit", Input: []byte(`{"file_path":"client.go"}`)}}},
+		{Role: "user", ToolResults: []ToolResult{{Content: "exit status 1", IsError: true}}},
+	}
+	out := PRTranscript(entries, 0)
+	for _, want := range []string{"User: Add retries to the client", "Assistant: Adding them.", `Tool Edit: {"file_path":"client.go"}`, "Error: exit status 1"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %q in:\n%s", want, out)
+		}
+	}
+}
+
+func TestPRTranscript_keepsTheEndWithinTheLimit(t *testing.T) {
+	var entries []Entry
+	for _, text := range []string{"first prompt", "second prompt", "last prompt"} {
+		entries = append(entries, Entry{Role: "user", Text: text})
+	}
+	out := PRTranscript(entries, 45)
+	if strings.Contains(out, "first prompt") || !strings.Contains(out, "last prompt") {
+		t.Errorf("expected only the latest entries in:\n%s", out)
+	}
+	if !strings.HasPrefix(out, "[earlier messages left out]") {
+		t.Errorf("expected a note that entries were left out in:\n%s", out)
+	}
+}
+
+func TestCut_keepsWholeRunes(t *testing.T) {
+	if got := cut("héllo", 2); got != "h […]" {
+		t.Errorf("expected the cut before é, got %q", got)
+	}
+	if got := cut("  short  ", 10); got != "short" {
+		t.Errorf("expected short text unchanged, got %q", got)
+	}
+}
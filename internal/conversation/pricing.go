@@ -0,0 +1,120 @@
+package conversation
+
+import (
+	_ "embed"
+	"fmt"
+	"strings"
+	"sync"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Price is the list price of a model in US dollars per million tokens.
+type Price struct {
+	Input      float64 `yaml:"input"`
+	Output     float64 `yaml:"output"`
+	CacheWrite float64 `yaml:"cache_write"`
+	CacheRead  float64 `yaml:"cache_read"`
+}
+
+// PriceTable maps model ID prefixes to prices.
+type PriceTable struct {
+	Updated string           `yaml:"updated"` // when the prices were checked, YYYY-MM-DD
+	Models  map[string]Price `yaml:"models"`
+}
+
+//go:embed pricing.yaml
+var bundledPricing []byte
+
+// ParsePriceTable reads a price table in the layout of the bundled
+// pricing.yaml.
+func ParsePriceTable(data []byte) (PriceTable, error) {
+	var t PriceTable
+	if err := yaml.Unmarshal(data, &t); err != nil {
+		return PriceTable{}, fmt.Errorf("invalid price table: %w", err)
+	}
+	if len(t.Models) == 0 {
+		return PriceTable{}, fmt.Errorf("invalid price table: no models")
+	}
+	for model, p := range t.Models {
+		if p.Input < 0 || p.Output < 0 || p.CacheWrite < 0 || p.CacheRead < 0 {
+			return PriceTable{}, fmt.Errorf("invalid price table: negative price for %s", model)
+		}
+	}
+	return t, nil
+}
+
+// BundledPrices returns the price table built into the binary.
+func BundledPrices() PriceTable {
+	t, err := ParsePriceTable(bundledPricing)
+	if err != nil {
+		panic(err) // pricing.yaml is part of the source
+	}
+	return t
+}
+
+// prices is the table costs are worked out from; see SetPrices.
+var (
+	pricesMu sync.RWMutex
+	prices   = BundledPrices().Models
+)
+
+// SetPrices replaces the prices in use with the bundled table overlaid by
+// tables in order, model by model, so a later table (e.g. config overrides)
+// wins over an earlier one (e.g. a downloaded table).
+func SetPrices(tables ...PriceTable) {
+	merged := BundledPrices().Models
+	for _, t := range tables {
+		for model, p := range t.Models {
+			merged[model] = p
+		}
+	}
+	pricesMu.Lock()
+	prices = merged
+	pricesMu.Unlock()
+}
+
+// Prices returns a copy of the prices in use.
+func Prices() map[string]Price {
+	pricesMu.RLock()
+	defer pricesMu.RUnlock()
+	out := make(map[string]Price, len(prices))
+	for model, p := range prices {
+		out[model] = p
+	}
+	return out
+}
+
+// PriceFor returns the price of model, matched by longest known prefix.
+func PriceFor(model string) (Price, bool) {
+	pricesMu.RLock()
+	defer pricesMu.RUnlock()
+	best := ""
+	for prefix := range prices {
+		if strings.HasPrefix(model, prefix) && len(prefix) > len(best) {
+			best = prefix
+		}
+	}
+	if best == "" {
+		return Price{}, false
+	}
+	return prices[best], true
+}
+
+// MessageCost returns the dollar cost of an assistant message, or false when
+// the message has no usage or its model is not in the price table.
+func MessageCost(m Message) (float64, bool) {
+	if m.Usage.IsZero() {
+		return 0, false
+	}
+	p, ok := PriceFor(m.Model)
+	if !ok {
+		return 0, false
+	}
+	u := m.Usage
+	cost := float64(u.InputTokens)*p.Input +
+		float64(u.OutputTokens)*p.Output +
+		float64(u.CacheCreationInputTokens)*p.CacheWrite +
+		float64(u.CacheReadInputTokens)*p.CacheRead
+	return cost / 1_000_000, true
+}
@@ -1,12 +1,12 @@
 package conversation
 
 import (
-	"bufio"
+	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
-	"sort"
 	"strings"
 	"time"
 )
@@ -16,6 +16,13 @@ type Message struct {
 	Role      string // "user" or "assistant"
 	Content   string
 	Timestamp time.Time
+	Model     string // assistant messages only
+	Usage     Usage  // assistant messages only
+
+	// ContextDelta is how much the context window grew since the previous
+	// assistant turn, i.e. the cost of everything sent in between (user text,
+	// tool results, pasted files). Zero when unknown.
+	ContextDelta int
 }
 
 // ReadConversation reads the most recent conversation log for a given working directory.
@@ -33,56 +40,132 @@ func ReadConversation(workDir string, maxMessages int) ([]Message, error) {
 	return parseJSONL(jsonlFile, maxMessages)
 }
 
-// mapToProjectDir converts a working directory to the Claude project directory path.
-func mapToProjectDir(workDir string) string {
-	if workDir == "" {
-		return ""
+// ReadConversationFiltered is like ReadConversation but keeps only messages
+// matching f. The filter runs before maxMessages is applied, so the result
+// holds the last N matches rather than the matches among the last N.
+func ReadConversationFiltered(workDir string, maxMessages int, f Filter) ([]Message, Counts, error) {
+	projectDir := mapToProjectDir(workDir)
+	if projectDir == "" {
+		return nil, Counts{}, fmt.Errorf("could not map working directory")
 	}
-	// /Users/foo/bar -> -Users-foo-bar
-	projectName := strings.ReplaceAll(workDir, "/", "-")
-	homeDir, err := os.UserHomeDir()
+
+	jsonlFile, err := findLatestJSONL(projectDir)
 	if err != nil {
-		return ""
+		return nil, Counts{}, err
 	}
-	return filepath.Join(homeDir, ".claude", "projects", projectName)
+
+	return parseJSONLFiltered(jsonlFile, maxMessages, f)
 }
 
-// findLatestJSONL finds the most recently modified .jsonl file in the project directory.
-func findLatestJSONL(projectDir string) (string, error) {
-	entries, err := os.ReadDir(projectDir)
+// lastPromptTail is how much of the end of a log LastPrompt and LastReply
+// read. Tool results can be large, so text further back than this is not
+// found.
+const lastPromptTail = 512 * 1024
+
+// LastPrompt returns the last prompt the user typed in the latest
+// conversation log of workDir, or "" if none is found near the end of it.
+// Only the tail of the log is read, so it stays cheap for long sessions.
+func LastPrompt(workDir string) (string, error) {
+	path, err := LatestLog(workDir)
+	if err != nil {
+		return "", err
+	}
+	return LastPromptIn(path)
+}
+
+// LastPromptIn is like LastPrompt for the conversation log at path.
+func LastPromptIn(path string) (string, error) {
+	return lastTextIn(path, "user", lastPromptTail)
+}
+
+// LastReply returns the last text the assistant wrote in the latest
+// conversation log of workDir, or "" if none is found near the end of it.
+func LastReply(workDir string) (string, error) {
+	path, err := LatestLog(workDir)
 	if err != nil {
-		return "", fmt.Errorf("no conversation logs found")
+		return "", err
 	}
+	return LastReplyIn(path)
+}
+
+// LastReplyIn is like LastReply for the conversation log at path.
+func LastReplyIn(path string) (string, error) {
+	return lastTextIn(path, "assistant", lastPromptTail)
+}
 
-	type fileInfo struct {
-		path    string
-		modTime time.Time
+// lastTextIn scans the last tail bytes of the log at path for text of role.
+func lastTextIn(path, role string, tail int64) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
 	}
-	var jsonlFiles []fileInfo
+	defer f.Close()
 
-	for _, entry := range entries {
-		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".jsonl") {
-			continue
+	info, err := f.Stat()
+	if err != nil {
+		return "", err
+	}
+	partial := false
+	if info.Size() > tail {
+		if _, err := f.Seek(info.Size()-tail, io.SeekStart); err != nil {
+			return "", err
 		}
-		info, err := entry.Info()
-		if err != nil {
+		partial = true
+	}
+
+	scanner := newLogScanner(f)
+	text := ""
+	for scanner.Scan() {
+		if partial {
+			partial = false // the first line was cut by the seek
 			continue
 		}
-		jsonlFiles = append(jsonlFiles, fileInfo{
-			path:    filepath.Join(projectDir, entry.Name()),
-			modTime: info.ModTime(),
-		})
+		if msg, ok := scanLine(scanner.Bytes()); ok && msg.Role == role {
+			text = msg.Content
+		}
 	}
+	return text, scanner.Err()
+}
 
-	if len(jsonlFiles) == 0 {
-		return "", fmt.Errorf("no .jsonl files found")
+// ProjectsDir returns the directory where Claude Code keeps per-project
+// conversation logs (~/.claude/projects).
+func ProjectsDir() string {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return ""
 	}
+	return filepath.Join(homeDir, ".claude", "projects")
+}
 
-	sort.Slice(jsonlFiles, func(i, j int) bool {
-		return jsonlFiles[i].modTime.After(jsonlFiles[j].modTime)
-	})
+// mapToProjectDir converts a working directory to the Claude project directory path.
+func mapToProjectDir(workDir string) string {
+	if workDir == "" {
+		return ""
+	}
+	projectsDir := ProjectsDir()
+	if projectsDir == "" {
+		return ""
+	}
+	return filepath.Join(projectsDir, ProjectName(workDir))
+}
+
+// ProjectName returns the name of the directory under ProjectsDir holding
+// the conversation logs of workDir.
+func ProjectName(workDir string) string {
+	// /Users/foo/bar -> -Users-foo-bar, C:\Users\foo -> C--Users-foo
+	return strings.NewReplacer("/", "-", "\\", "-", ":", "-").Replace(workDir)
+}
 
-	return jsonlFiles[0].path, nil
+// findLatestJSONL finds the most recently modified .jsonl file in the project directory.
+func findLatestJSONL(projectDir string) (string, error) {
+	logs, err := listLogs(projectDir)
+	if err != nil {
+		return "", err
+	}
+	if len(logs) == 0 {
+		return "", fmt.Errorf("no .jsonl files found")
+	}
+	return logs[0].Path, nil
 }
 
 // jsonlEntry represents a raw .jsonl line.
@@ -94,54 +177,68 @@ type jsonlEntry struct {
 
 type msgEntry struct {
 	Role    string      `json:"role"`
+	Model   string      `json:"model,omitempty"`
 	Content interface{} `json:"content"`
+	Usage   *Usage      `json:"usage,omitempty"`
 }
 
 // parseJSONL reads a .jsonl file and extracts conversation messages.
 // When maxMessages > 0 it uses a ring buffer so only the last N messages
 // are kept in memory instead of reading everything then slicing.
 func parseJSONL(path string, maxMessages int) ([]Message, error) {
-	f, err := os.Open(path)
+	msgs, _, err := parseJSONLFiltered(path, maxMessages, Filter{})
+	return msgs, err
+}
+
+// parseJSONLFiltered is parseJSONL keeping only messages that match filter.
+// Counts.Total includes messages the filter dropped.
+func parseJSONLFiltered(path string, maxMessages int, filter Filter) ([]Message, Counts, error) {
+	entries, err := Stream(context.Background(), path)
 	if err != nil {
-		return nil, err
+		return nil, Counts{}, err
 	}
-	defer f.Close()
 
-	scanner := bufio.NewScanner(f)
-	scanner.Buffer(make([]byte, 0, 1024*1024), 10*1024*1024) // 10MB max line
+	var counts Counts
 
 	if maxMessages <= 0 {
 		// No limit: collect all messages.
 		var messages []Message
-		for scanner.Scan() {
-			if msg, ok := scanLine(scanner.Bytes()); ok {
+		for msg, err := range Messages(entries) {
+			if err != nil {
+				return messages, counts, err
+			}
+			counts.Total++
+			if filter.Match(msg) {
 				messages = append(messages, msg)
 			}
 		}
-		return messages, nil
+		counts.Matched = len(messages)
+		return messages, counts, nil
 	}
 
 	// Ring buffer: keep only the last maxMessages entries.
 	ring := make([]Message, maxMessages)
-	head := 0  // next write position
-	count := 0 // total messages seen
+	head := 0 // next write position
 
-	for scanner.Scan() {
-		msg, ok := scanLine(scanner.Bytes())
-		if !ok {
+	for msg, err := range Messages(entries) {
+		if err != nil {
+			return nil, counts, err
+		}
+		counts.Total++
+		if !filter.Match(msg) {
 			continue
 		}
+		counts.Matched++
 		ring[head] = msg
 		head = (head + 1) % maxMessages
-		count++
 	}
 
-	if count == 0 {
-		return nil, nil
+	if counts.Matched == 0 {
+		return nil, counts, nil
 	}
 
 	// Reconstruct ordered slice from ring buffer.
-	size := count
+	size := counts.Matched
 	if size > maxMessages {
 		size = maxMessages
 	}
@@ -150,11 +247,13 @@ func parseJSONL(path string, maxMessages int) ([]Message, error) {
 	for i := 0; i < size; i++ {
 		result[i] = ring[(start+i)%maxMessages]
 	}
-	return result, nil
+	return result, counts, nil
 }
 
 // scanLine parses a single JSONL scanner line and returns the Message and true
 // if it represents a user or assistant message with non-empty content.
+// Assistant entries without text (tool calls only) return false but still
+// carry their Usage so context growth can be tracked across them.
 func scanLine(b []byte) (Message, bool) {
 	var entry jsonlEntry
 	if err := json.Unmarshal(b, &entry); err != nil {
@@ -166,16 +265,17 @@ func scanLine(b []byte) (Message, bool) {
 	if entry.Message == nil {
 		return Message{}, false
 	}
-	content := extractContent(entry.Message)
-	if content == "" {
-		return Message{}, false
-	}
 	ts, _ := time.Parse(time.RFC3339Nano, entry.Timestamp)
-	return Message{
+	msg := Message{
 		Role:      entry.Message.Role,
-		Content:   content,
+		Content:   extractContent(entry.Message),
 		Timestamp: ts,
-	}, true
+		Model:     entry.Message.Model,
+	}
+	if entry.Message.Usage != nil {
+		msg.Usage = *entry.Message.Usage
+	}
+	return msg, msg.Content != ""
 }
 
 // extractContent extracts text content from a message.
@@ -211,20 +311,3 @@ func extractContent(msg *msgEntry) string {
 
 	return strings.Join(texts, "\n")
 }
-
-// FormatConversation formats messages for display in the log viewer.
-func FormatConversation(messages []Message) string {
-	var b strings.Builder
-	for _, msg := range messages {
-		ts := msg.Timestamp.Format("15:04:05")
-		switch msg.Role {
-		case "user":
-			b.WriteString(fmt.Sprintf("─── User [%s] ───\n", ts))
-		case "assistant":
-			b.WriteString(fmt.Sprintf("─── Assistant [%s] ───\n", ts))
-		}
-		b.WriteString(msg.Content)
-		b.WriteString("\n\n")
-	}
-	return b.String()
-}
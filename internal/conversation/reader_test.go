@@ -108,6 +108,18 @@ func TestMapToProjectDir_convertsSlashesToDashes(t *testing.T) {
 	}
 }
 
+func TestMapToProjectDir_convertsWindowsPath(t *testing.T) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		t.Skip("cannot determine home dir")
+	}
+	result := mapToProjectDir(`C:\Users\foo\bar`)
+	expected := filepath.Join(home, ".claude", "projects", "C--Users-foo-bar")
+	if result != expected {
+		t.Errorf("expected %q, got %q", expected, result)
+	}
+}
+
 func TestMapToProjectDir_singleComponentPath(t *testing.T) {
 	home, err := os.UserHomeDir()
 	if err != nil {
@@ -296,80 +308,6 @@ func TestParseJSONL_skipsMessagesWithEmptyContent(t *testing.T) {
 	}
 }
 
-// ---------------------------------------------------------------------------
-// FormatConversation
-// ---------------------------------------------------------------------------
-
-func TestFormatConversation_emptyInputReturnsEmptyString(t *testing.T) {
-	result := FormatConversation([]Message{})
-	if result != "" {
-		t.Errorf("expected empty string, got %q", result)
-	}
-}
-
-func TestFormatConversation_includesRoleAndContent(t *testing.T) {
-	msgs := []Message{
-		{Role: "user", Content: "Hello", Timestamp: time.Time{}},
-	}
-	result := FormatConversation(msgs)
-	if result == "" {
-		t.Fatal("expected non-empty result")
-	}
-	if len(result) == 0 {
-		t.Error("expected formatted output to contain content")
-	}
-}
-
-func TestFormatConversation_userRoleHeaderFormat(t *testing.T) {
-	ts, _ := time.Parse(time.RFC3339, "2024-01-01T15:04:05Z")
-	msgs := []Message{{Role: "user", Content: "Hello there", Timestamp: ts}}
-	result := FormatConversation(msgs)
-	if !containsSubstr(result, "User") {
-		t.Errorf("expected 'User' in output, got: %q", result)
-	}
-	if !containsSubstr(result, "Hello there") {
-		t.Errorf("expected content 'Hello there' in output, got: %q", result)
-	}
-}
-
-func TestFormatConversation_assistantRoleHeaderFormat(t *testing.T) {
-	ts, _ := time.Parse(time.RFC3339, "2024-01-01T09:00:00Z")
-	msgs := []Message{{Role: "assistant", Content: "Sure!", Timestamp: ts}}
-	result := FormatConversation(msgs)
-	if !containsSubstr(result, "Assistant") {
-		t.Errorf("expected 'Assistant' in output, got: %q", result)
-	}
-	if !containsSubstr(result, "Sure!") {
-		t.Errorf("expected content 'Sure!' in output, got: %q", result)
-	}
-}
-
-func TestFormatConversation_multipleMessagesAllPresent(t *testing.T) {
-	msgs := []Message{
-		{Role: "user", Content: "Question", Timestamp: time.Time{}},
-		{Role: "assistant", Content: "Answer", Timestamp: time.Time{}},
-	}
-	result := FormatConversation(msgs)
-	if !containsSubstr(result, "Question") {
-		t.Errorf("expected 'Question' in output")
-	}
-	if !containsSubstr(result, "Answer") {
-		t.Errorf("expected 'Answer' in output")
-	}
-}
-
-func containsSubstr(s, sub string) bool {
-	return len(s) >= len(sub) && (s == sub || len(sub) == 0 ||
-		func() bool {
-			for i := 0; i <= len(s)-len(sub); i++ {
-				if s[i:i+len(sub)] == sub {
-					return true
-				}
-			}
-			return false
-		}())
-}
-
 // ---------------------------------------------------------------------------
 // findLatestJSONL
 // ---------------------------------------------------------------------------
@@ -459,3 +397,109 @@ func TestReadConversation_nonexistentProjectDirReturnsError(t *testing.T) {
 		t.Error("expected error for nonexistent project dir, got nil")
 	}
 }
+
+// ---------------------------------------------------------------------------
+// parseJSONLFiltered
+// ---------------------------------------------------------------------------
+
+func filterFixture() []string {
+	return []string{
+		`{"type":"user","message":{"role":"user","content":"fix the panic"},"timestamp":"2024-01-01T09:00:00Z"}`,
+		`{"type":"assistant","message":{"role":"assistant","content":[{"type":"text","text":"Looking at the PANIC now"}]},"timestamp":"2024-01-01T09:01:00Z"}`,
+		`{"type":"user","message":{"role":"user","content":"thanks"},"timestamp":"2024-01-02T10:00:00Z"}`,
+		`{"type":"assistant","message":{"role":"assistant","content":[{"type":"text","text":"done"}]},"timestamp":"2024-01-02T10:01:00Z"}`,
+	}
+}
+
+func TestParseJSONLFiltered_roleKeepsOnlyThatRole(t *testing.T) {
+	path := writeJSONLFile(t, filterFixture())
+	msgs, counts, err := parseJSONLFiltered(path, 0, Filter{Role: "user"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(msgs) != 2 || msgs[0].Content != "fix the panic" || msgs[1].Content != "thanks" {
+		t.Errorf("expected the two user messages, got %+v", msgs)
+	}
+	if counts != (Counts{Matched: 2, Total: 4}) {
+		t.Errorf("expected counts 2/4, got %+v", counts)
+	}
+}
+
+func TestParseJSONLFiltered_termIsCaseInsensitiveAndCombinesWithSince(t *testing.T) {
+	path := writeJSONLFile(t, filterFixture())
+	msgs, _, err := parseJSONLFiltered(path, 0, Filter{Term: "panic"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(msgs) != 2 {
+		t.Errorf("expected 2 messages containing 'panic', got %d", len(msgs))
+	}
+
+	since := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+	msgs, counts, _ := parseJSONLFiltered(path, 0, Filter{Term: "panic", Since: since})
+	if len(msgs) != 0 || counts.Total != 4 {
+		t.Errorf("expected no matches out of 4, got %d (counts %+v)", len(msgs), counts)
+	}
+}
+
+func TestParseJSONLFiltered_limitAppliesAfterFilter(t *testing.T) {
+	path := writeJSONLFile(t, filterFixture())
+	msgs, counts, err := parseJSONLFiltered(path, 1, Filter{Role: "user"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(msgs) != 1 || msgs[0].Content != "thanks" {
+		t.Errorf("expected the last user message, got %+v", msgs)
+	}
+	if counts.Matched != 2 {
+		t.Errorf("expected 2 matches before the limit, got %d", counts.Matched)
+	}
+}
+
+func TestFilter_stringDescribesActiveFilters(t *testing.T) {
+	f := Filter{Role: "user", Since: StartOfDay(time.Now()), Term: "panic"}
+	if got, want := f.String(), `user · today · "panic"`; got != want {
+		t.Errorf("expected %q, got %q", want, got)
+	}
+	if !(Filter{}).IsZero() {
+		t.Error("expected zero filter to report IsZero")
+	}
+}
+
+// ---------------------------------------------------------------------------
+// lastTextIn
+// ---------------------------------------------------------------------------
+
+func TestLastTextIn_returnsLatestUserText(t *testing.T) {
+	path := writeJSONLFile(t, []string{
+		`{"type":"user","message":{"role":"user","content":"first"}}`,
+		`{"type":"user","message":{"role":"user","content":"second"}}`,
+		`{"type":"assistant","message":{"role":"assistant","content":[{"type":"text","text":"reply"}]}}`,
+		`{"type":"user","message":{"role":"user","content":[{"type":"tool_result","tool_use_id":"x","content":"out"}]}}`,
+	})
+	got, err := lastTextIn(path, "user", lastPromptTail)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got != "second" {
+		t.Errorf("expected %q, got %q", "second", got)
+	}
+	if got, _ := lastTextIn(path, "assistant", lastPromptTail); got != "reply" {
+		t.Errorf("expected the assistant's %q, got %q", "reply", got)
+	}
+}
+
+func TestLastTextIn_skipsLineCutByTail(t *testing.T) {
+	last := `{"type":"user","message":{"role":"user","content":"kept"}}`
+	path := writeJSONLFile(t, []string{
+		`{"type":"user","message":{"role":"user","content":"dropped"}}`,
+		last,
+	})
+	got, err := lastTextIn(path, "user", int64(len(last)+5))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got != "kept" {
+		t.Errorf("expected %q, got %q", "kept", got)
+	}
+}
@@ -0,0 +1,131 @@
+package conversation
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"os"
+
+	"github.com/seunggabi/claude-dashboard/internal/locale"
+)
+
+// Spend is what a conversation has cost so far.
+type Spend struct {
+	Cost     float64 `json:"cost"`     // US dollars, for messages of priced models
+	Tokens   int     `json:"tokens"`   // every token billed, cache reads included
+	Unpriced int     `json:"unpriced"` // assistant messages whose model has no price
+}
+
+// String describes the spend in the current locale and currency, e.g.
+// "$4.20, 1.2M tokens".
+func (s Spend) String() string {
+	return locale.Current().Money(s.Cost, locale.CurrentCurrency()) + ", " + FormatTokens(s.Tokens) + " tokens"
+}
+
+// Plus returns the spend of s and o together.
+func (s Spend) Plus(o Spend) Spend {
+	return Spend{Cost: s.Cost + o.Cost, Tokens: s.Tokens + o.Tokens, Unpriced: s.Unpriced + o.Unpriced}
+}
+
+// ContextUse is how full a conversation's context window is: the prompt
+// size of its latest main-thread assistant message, and that message's model.
+type ContextUse struct {
+	Tokens int    `json:"tokens"`
+	Model  string `json:"model,omitempty"`
+}
+
+// SpendMeter adds up the spend of a conversation log as it grows. Each Read
+// parses only the lines appended since the previous one, so it stays cheap
+// to call on every refresh.
+type SpendMeter struct {
+	path   string
+	offset int64  // end of the last complete line counted
+	lastID string // message ID of the last usage counted
+	spend  Spend
+	ctx    ContextUse
+}
+
+// usageLine is the part of a log line SpendMeter needs.
+type usageLine struct {
+	Type        string `json:"type"`
+	IsSidechain bool   `json:"isSidechain"` // a subagent's turn, with a context of its own
+	Message     *struct {
+		ID    string `json:"id"`
+		Model string `json:"model"`
+		Usage *Usage `json:"usage"`
+	} `json:"message"`
+}
+
+// Read returns the spend of the log at path. A different path than last
+// time, or a log that shrank, is counted from the start.
+func (s *SpendMeter) Read(path string) (Spend, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return s.spend, err
+	}
+	if path != s.path || info.Size() < s.offset {
+		*s = SpendMeter{path: path}
+	}
+	if info.Size() == s.offset {
+		return s.spend, nil
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return s.spend, err
+	}
+	defer f.Close()
+	if _, err := f.Seek(s.offset, io.SeekStart); err != nil {
+		return s.spend, err
+	}
+	r := bufio.NewReaderSize(f, 64*1024)
+	for {
+		line, err := r.ReadBytes('\n')
+		if err == io.EOF {
+			return s.spend, nil // a partial last line is read again next time
+		}
+		if err != nil {
+			return s.spend, err
+		}
+		s.offset += int64(len(line))
+		s.add(line)
+	}
+}
+
+// add counts the usage of one log line.
+func (s *SpendMeter) add(line []byte) {
+	var e usageLine
+	if err := json.Unmarshal(line, &e); err != nil {
+		return
+	}
+	if e.Type != "assistant" || e.Message == nil || e.Message.Usage == nil {
+		return
+	}
+	// A message is logged as one line per content block, each repeating
+	// the message's usage.
+	if e.Message.ID != "" && e.Message.ID == s.lastID {
+		return
+	}
+	s.lastID = e.Message.ID
+	s.spend.add(e.Message.Model, *e.Message.Usage)
+	if !e.IsSidechain {
+		if tokens := e.Message.Usage.ContextTokens(); tokens > 0 {
+			s.ctx = ContextUse{Tokens: tokens, Model: e.Message.Model}
+		}
+	}
+}
+
+// Context returns the context use as of the last Read.
+func (s *SpendMeter) Context() ContextUse {
+	return s.ctx
+}
+
+// add counts one assistant message.
+func (s *Spend) add(model string, u Usage) {
+	s.Tokens += u.InputTokens + u.OutputTokens + u.CacheCreationInputTokens + u.CacheReadInputTokens
+	if cost, ok := MessageCost(Message{Model: model, Usage: u}); ok {
+		s.Cost += cost
+	} else if !u.IsZero() {
+		s.Unpriced++
+	}
+}
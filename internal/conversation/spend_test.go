@@ -0,0 +1,75 @@
+package conversation
+
+import (
+	"math"
+	"os"
+	"testing"
+)
+
+// ---------------------------------------------------------------------------
+// SpendMeter
+// ---------------------------------------------------------------------------
+
+// spendLines are two assistant messages, the first logged as two lines.
+var spendLines = []string{
+	`{"type":"user","message":{"role":"user","content":"go"}}`,
+	`{"type":"assistant","message":{"id":"m1","model":"claude-sonnet-4-5","content":[{"type":"text","text":"a"}],"usage":{"input_tokens":1000000,"output_tokens":0}}}`,
+	`{"type":"assistant","message":{"id":"m1","model":"claude-sonnet-4-5","content":[{"type":"tool_use","id":"t","name":"Bash","input":{}}],"usage":{"input_tokens":1000000,"output_tokens":0}}}`,
+	`{"type":"assistant","message":{"id":"m2","model":"claude-sonnet-4-5","content":"b","usage":{"output_tokens":1000000}}}`,
+}
+
+func TestSpendMeter_countsEachMessageOnce(t *testing.T) {
+	var meter SpendMeter
+	spend, err := meter.Read(writeJSONLFile(t, spendLines))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if spend.Tokens != 2_000_000 || math.Abs(spend.Cost-18) > 1e-9 {
+		t.Errorf("expected 2M tokens costing $18, got %+v", spend)
+	}
+}
+
+func TestSpendMeter_readsOnlyCompleteAppendedLines(t *testing.T) {
+	path := writeJSONLFile(t, spendLines[:2])
+	var meter SpendMeter
+	if spend, _ := meter.Read(path); spend.Tokens != 1_000_000 {
+		t.Fatalf("expected 1M tokens, got %+v", spend)
+	}
+
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	last := spendLines[3]
+	f.WriteString(last[:20]) // a line still being written
+	if spend, _ := meter.Read(path); spend.Tokens != 1_000_000 {
+		t.Errorf("expected the partial line ignored, got %+v", spend)
+	}
+	f.WriteString(last[20:] + "\n")
+	f.Close()
+	if spend, _ := meter.Read(path); spend.Tokens != 2_000_000 {
+		t.Errorf("expected the completed line counted, got %+v", spend)
+	}
+}
+
+func TestSpendMeter_unknownModelIsUnpriced(t *testing.T) {
+	var meter SpendMeter
+	spend, _ := meter.Read(writeJSONLFile(t, []string{
+		`{"type":"assistant","message":{"id":"m1","model":"other-model","content":"x","usage":{"output_tokens":5}}}`,
+	}))
+	if spend.Cost != 0 || spend.Tokens != 5 || spend.Unpriced != 1 {
+		t.Errorf("expected 5 unpriced tokens, got %+v", spend)
+	}
+}
+
+func TestSpendMeter_contextIsTheLatestMainThreadPrompt(t *testing.T) {
+	var meter SpendMeter
+	meter.Read(writeJSONLFile(t, []string{
+		`{"type":"assistant","message":{"id":"m1","model":"claude-opus-4-1","content":"a","usage":{"input_tokens":10,"cache_read_input_tokens":90000,"cache_creation_input_tokens":2000,"output_tokens":50}}}`,
+		`{"type":"assistant","isSidechain":true,"message":{"id":"m2","model":"claude-haiku-4-5","content":"b","usage":{"input_tokens":500,"output_tokens":5}}}`,
+	}))
+	want := ContextUse{Tokens: 92010, Model: "claude-opus-4-1"}
+	if got := meter.Context(); got != want {
+		t.Errorf("expected %+v, got %+v", want, got)
+	}
+}
@@ -0,0 +1,73 @@
+package conversation
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"iter"
+	"os"
+)
+
+// Stream returns the user and assistant entries of the .jsonl log at path as
+// a sequence read one line at a time, so a long log is never held in memory.
+// Each range opens the file afresh and closes it when the loop ends,
+// including on break. When ctx is done, or reading fails, the error is
+// yielded as the last element. Stream itself only checks that path exists.
+func Stream(ctx context.Context, path string) (iter.Seq2[Entry, error], error) {
+	if _, err := os.Stat(path); err != nil {
+		return nil, err
+	}
+	return func(yield func(Entry, error) bool) {
+		f, err := os.Open(path)
+		if err != nil {
+			yield(Entry{}, err)
+			return
+		}
+		defer f.Close()
+
+		scanner := newLogScanner(f)
+		for scanner.Scan() {
+			if err := ctx.Err(); err != nil {
+				yield(Entry{}, err)
+				return
+			}
+			if e, ok := scanEntry(scanner.Bytes()); ok && !yield(e, nil) {
+				return
+			}
+		}
+		if err := scanner.Err(); err != nil {
+			yield(Entry{}, err)
+		}
+	}, nil
+}
+
+// Messages turns a Stream of entries into the messages the conversation
+// viewer shows: entries with text, with ContextDelta worked out from the
+// usage of every entry, including those without text.
+func Messages(entries iter.Seq2[Entry, error]) iter.Seq2[Message, error] {
+	return func(yield func(Message, error) bool) {
+		var ctxTracker contextTracker
+		for e, err := range entries {
+			if err != nil {
+				yield(Message{}, err)
+				return
+			}
+			msg := Message{Role: e.Role, Content: e.Text, Timestamp: e.Timestamp, Model: e.Model}
+			if e.Usage != nil {
+				msg.Usage = *e.Usage
+			}
+			ctxTracker.annotate(&msg)
+			if msg.Content != "" && !yield(msg, nil) {
+				return
+			}
+		}
+	}
+}
+
+// newLogScanner returns a line scanner for a .jsonl log, which can have very
+// long lines (e.g. pasted files in tool results).
+func newLogScanner(r io.Reader) *bufio.Scanner {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 1024*1024), 10*1024*1024) // 10MB max line
+	return scanner
+}
@@ -0,0 +1,80 @@
+package conversation
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+// ---------------------------------------------------------------------------
+// Stream
+// ---------------------------------------------------------------------------
+
+func TestStream_missingFileIsAnErrorUpFront(t *testing.T) {
+	if _, err := Stream(context.Background(), "/nonexistent/path/file.jsonl"); err == nil {
+		t.Error("expected an error for a missing file")
+	}
+}
+
+func TestStream_yieldsEntriesInOrder(t *testing.T) {
+	stream, err := Stream(context.Background(), writeJSONLFile(t, transcriptLines))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var roles []string
+	for e, err := range stream {
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		roles = append(roles, e.Role)
+	}
+	if len(roles) != 3 || roles[0] != "user" || roles[1] != "assistant" || roles[2] != "user" {
+		t.Errorf("expected user, assistant, user; got %v", roles)
+	}
+}
+
+func TestStream_breakStopsReading(t *testing.T) {
+	stream, _ := Stream(context.Background(), writeJSONLFile(t, transcriptLines))
+	n := 0
+	for range stream {
+		n++
+		break
+	}
+	if n != 1 {
+		t.Errorf("expected 1 entry before break, got %d", n)
+	}
+}
+
+func TestStream_cancelledContextEndsWithItsError(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	stream, _ := Stream(ctx, writeJSONLFile(t, transcriptLines))
+	n := 0
+	var last error
+	for _, err := range stream {
+		n++
+		last = err
+		cancel()
+	}
+	if n != 2 || !errors.Is(last, context.Canceled) {
+		t.Errorf("expected one entry then context.Canceled, got %d elements ending in %v", n, last)
+	}
+}
+
+// ---------------------------------------------------------------------------
+// Messages
+// ---------------------------------------------------------------------------
+
+func TestMessages_skipsEntriesWithoutText(t *testing.T) {
+	stream, _ := Stream(context.Background(), writeJSONLFile(t, transcriptLines))
+	var contents []string
+	for msg, err := range Messages(stream) {
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		contents = append(contents, msg.Content)
+	}
+	if len(contents) != 2 || contents[0] != "list files" || contents[1] != "Listing." {
+		t.Errorf("expected the two text messages, got %q", contents)
+	}
+}
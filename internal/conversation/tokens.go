@@ -0,0 +1,103 @@
+package conversation
+
+import (
+	"strings"
+	"unicode/utf8"
+
+	"github.com/seunggabi/claude-dashboard/internal/locale"
+)
+
+// Usage holds the token counts Claude reports for an assistant message.
+type Usage struct {
+	InputTokens              int `json:"input_tokens"`
+	OutputTokens             int `json:"output_tokens"`
+	CacheCreationInputTokens int `json:"cache_creation_input_tokens"`
+	CacheReadInputTokens     int `json:"cache_read_input_tokens"`
+}
+
+// IsZero reports whether no usage was recorded.
+func (u Usage) IsZero() bool {
+	return u == Usage{}
+}
+
+// ContextTokens is the size of the prompt the model saw: fresh, cache-written
+// and cache-read input combined.
+func (u Usage) ContextTokens() int {
+	return u.InputTokens + u.CacheCreationInputTokens + u.CacheReadInputTokens
+}
+
+// contextTracker derives Message.ContextDelta from consecutive usage records.
+type contextTracker struct {
+	prev int
+}
+
+// annotate sets msg.ContextDelta when msg carries usage.
+func (t *contextTracker) annotate(msg *Message) {
+	if msg.Usage.IsZero() {
+		return
+	}
+	ctx := msg.Usage.ContextTokens()
+	if t.prev > 0 {
+		msg.ContextDelta = ctx - t.prev
+	}
+	t.prev = ctx
+}
+
+// EstimateTokens gives a rough token count for text without usage data,
+// using the common ~4 characters per token rule of thumb.
+func EstimateTokens(text string) int {
+	return (utf8.RuneCountInString(text) + 3) / 4
+}
+
+// Weight is the number of tokens a message is responsible for: what the
+// assistant wrote plus the context growth that preceded it, or the estimated
+// size of a user message.
+func (m Message) Weight() int {
+	if m.Role == "assistant" && !m.Usage.IsZero() {
+		w := m.Usage.OutputTokens
+		if m.ContextDelta > 0 {
+			w += m.ContextDelta
+		}
+		return w
+	}
+	return EstimateTokens(m.Content)
+}
+
+// heatGlyphs render a message's weight relative to the heaviest message.
+var heatGlyphs = []rune("▁▂▃▄▅▆▇█")
+
+// heatGlyph returns the bar glyph for weight on a scale up to max.
+func heatGlyph(weight, max int) string {
+	if max <= 0 || weight <= 0 {
+		return string(heatGlyphs[0])
+	}
+	idx := weight * (len(heatGlyphs) - 1) / max
+	return string(heatGlyphs[idx])
+}
+
+// FormatTokens renders a token count compactly in the current locale, e.g.
+// 950, 12.3k, 1.2M.
+func FormatTokens(n int) string {
+	return locale.Current().Compact(n)
+}
+
+// tokenAnnotation renders the token summary shown in a message header.
+func tokenAnnotation(m Message, max int, showCost bool) string {
+	parts := []string{heatGlyph(m.Weight(), max)}
+	if m.Role == "assistant" && !m.Usage.IsZero() {
+		parts = append(parts, "out "+FormatTokens(m.Usage.OutputTokens))
+		ctx := "ctx " + FormatTokens(m.Usage.ContextTokens())
+		if m.ContextDelta > 0 {
+			ctx += " (+" + FormatTokens(m.ContextDelta) + ")"
+		}
+		parts = append(parts, ctx)
+		if showCost {
+			if cost, ok := MessageCost(m); ok {
+				parts = append(parts, locale.Current().SmallMoney(cost, locale.CurrentCurrency()))
+			}
+		}
+	} else {
+		parts = append(parts, "~"+FormatTokens(EstimateTokens(m.Content))+" tok")
+	}
+	return strings.Join(parts, " ")
+}
@@ -0,0 +1,168 @@
+package conversation
+
+import (
+	"math"
+	"strings"
+	"testing"
+)
+
+// ---------------------------------------------------------------------------
+// parseJSONL usage tracking
+// ---------------------------------------------------------------------------
+
+func TestParseJSONL_contextDeltaSpansToolOnlyTurns(t *testing.T) {
+	lines := []string{
+		`{"type":"assistant","message":{"role":"assistant","model":"claude-sonnet-4-5","content":[{"type":"text","text":"first"}],"usage":{"input_tokens":10,"cache_read_input_tokens":990,"output_tokens":50}}}`,
+		// Tool call without text: not shown, but its usage still counts.
+		`{"type":"assistant","message":{"role":"assistant","content":[{"type":"tool_use","id":"t1"}],"usage":{"input_tokens":10,"cache_read_input_tokens":1990,"output_tokens":20}}}`,
+		`{"type":"assistant","message":{"role":"assistant","content":[{"type":"text","text":"second"}],"usage":{"input_tokens":10,"cache_read_input_tokens":11990,"output_tokens":30}}}`,
+	}
+	msgs, err := parseJSONL(writeJSONLFile(t, lines), 0)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(msgs) != 2 {
+		t.Fatalf("expected 2 messages, got %d", len(msgs))
+	}
+	if msgs[0].Model != "claude-sonnet-4-5" || msgs[0].Usage.OutputTokens != 50 {
+		t.Errorf("unexpected first message: %+v", msgs[0])
+	}
+	if msgs[0].ContextDelta != 0 {
+		t.Errorf("expected no delta for first turn, got %d", msgs[0].ContextDelta)
+	}
+	if msgs[1].ContextDelta != 10000 {
+		t.Errorf("expected delta 10000 since tool-only turn, got %d", msgs[1].ContextDelta)
+	}
+}
+
+// ---------------------------------------------------------------------------
+// Weight / heatGlyph / FormatTokens
+// ---------------------------------------------------------------------------
+
+func TestWeight_userMessageUsesEstimate(t *testing.T) {
+	m := Message{Role: "user", Content: strings.Repeat("a", 400)}
+	if got := m.Weight(); got != 100 {
+		t.Errorf("expected 100, got %d", got)
+	}
+}
+
+func TestWeight_assistantAddsOutputAndDelta(t *testing.T) {
+	m := Message{Role: "assistant", Usage: Usage{OutputTokens: 30}, ContextDelta: 70}
+	if got := m.Weight(); got != 100 {
+		t.Errorf("expected 100, got %d", got)
+	}
+}
+
+func TestHeatGlyph_scalesToMax(t *testing.T) {
+	if got := heatGlyph(0, 100); got != "▁" {
+		t.Errorf("expected lowest glyph for zero, got %q", got)
+	}
+	if got := heatGlyph(100, 100); got != "█" {
+		t.Errorf("expected highest glyph for max, got %q", got)
+	}
+}
+
+func TestFormatTokens_tableTests(t *testing.T) {
+	cases := []struct {
+		n    int
+		want string
+	}{
+		{950, "950"},
+		{12345, "12.3k"},
+		{1_200_000, "1.2M"},
+	}
+	for _, tc := range cases {
+		if got := FormatTokens(tc.n); got != tc.want {
+			t.Errorf("FormatTokens(%d): expected %q, got %q", tc.n, tc.want, got)
+		}
+	}
+}
+
+// ---------------------------------------------------------------------------
+// Pricing
+// ---------------------------------------------------------------------------
+
+func TestPriceFor_longestPrefixWins(t *testing.T) {
+	p, ok := PriceFor("claude-opus-4-5-20251101")
+	if !ok || p.Input != 5 {
+		t.Errorf("expected opus 4.5 price, got %+v (ok=%v)", p, ok)
+	}
+	p, ok = PriceFor("claude-opus-4-1-20250805")
+	if !ok || p.Input != 15 {
+		t.Errorf("expected opus 4.1 price, got %+v (ok=%v)", p, ok)
+	}
+	p, ok = PriceFor("claude-opus-4-20250514")
+	if !ok || p.Input != 15 {
+		t.Errorf("expected opus 4 price, got %+v (ok=%v)", p, ok)
+	}
+}
+
+func TestPriceFor_laterOpus4VersionsDoNotTakeAnOlderRate(t *testing.T) {
+	if p, ok := PriceFor("claude-opus-4-6"); !ok || p.Input != 5 {
+		t.Errorf("expected opus 4.6 price, got %+v (ok=%v)", p, ok)
+	}
+	if p, ok := PriceFor("claude-opus-4-7-20261001"); ok && p.Input == 15 {
+		t.Errorf("expected an unknown opus 4.x not to be priced as opus 4, got %+v", p)
+	}
+}
+
+func TestParsePriceTable_rejectsEmptyAndNegativeTables(t *testing.T) {
+	if _, err := ParsePriceTable([]byte("updated: \"2025-01-01\"\n")); err == nil {
+		t.Error("expected an error for a table without models")
+	}
+	if _, err := ParsePriceTable([]byte("models:\n  claude-x: {input: -1}\n")); err == nil {
+		t.Error("expected an error for a negative price")
+	}
+	table, err := ParsePriceTable([]byte("models:\n  claude-x: {input: 2, output: 4}\n"))
+	if err != nil || table.Models["claude-x"].Output != 4 {
+		t.Errorf("expected claude-x output price 4, got %+v (err=%v)", table, err)
+	}
+}
+
+func TestSetPrices_laterTablesWinOverTheBundledOnes(t *testing.T) {
+	defer SetPrices()
+
+	downloaded := PriceTable{Models: map[string]Price{"claude-opus-4-5": {Input: 4}, "claude-new": {Input: 1}}}
+	override := PriceTable{Models: map[string]Price{"claude-opus-4-5": {Input: 2}}}
+	SetPrices(downloaded, override)
+
+	if p, _ := PriceFor("claude-opus-4-5-20251101"); p.Input != 2 {
+		t.Errorf("expected the override to win, got %+v", p)
+	}
+	if _, ok := PriceFor("claude-new-1"); !ok {
+		t.Error("expected the downloaded model to be priced")
+	}
+	if p, _ := PriceFor("claude-sonnet-4-5"); p.Input != 3 {
+		t.Errorf("expected bundled prices to remain, got %+v", p)
+	}
+}
+
+func TestMessageCost_unknownModelIsNotPriced(t *testing.T) {
+	m := Message{Model: "gpt-x", Usage: Usage{OutputTokens: 10}}
+	if _, ok := MessageCost(m); ok {
+		t.Error("expected unknown model to have no cost")
+	}
+}
+
+func TestMessageCost_sumsAllTokenKinds(t *testing.T) {
+	m := Message{Model: "claude-sonnet-4-5", Usage: Usage{
+		InputTokens:              1_000_000,
+		OutputTokens:             1_000_000,
+		CacheCreationInputTokens: 1_000_000,
+		CacheReadInputTokens:     1_000_000,
+	}}
+	cost, ok := MessageCost(m)
+	if !ok || math.Abs(cost-(3+15+3.75+0.3)) > 1e-9 {
+		t.Errorf("expected 22.05, got %v (ok=%v)", cost, ok)
+	}
+}
+
+func TestFormatHeader_showsCostOnlyWhenEnabled(t *testing.T) {
+	msg := Message{Role: "assistant", Content: "x", Model: "claude-sonnet-4", Usage: Usage{OutputTokens: 1000}}
+	if got := FormatHeader(msg, MaxWeight([]Message{msg}), FormatOptions{}); strings.Contains(got, "$") {
+		t.Errorf("expected no cost by default, got %q", got)
+	}
+	if got := FormatHeader(msg, MaxWeight([]Message{msg}), FormatOptions{ShowCost: true}); !strings.Contains(got, "$0.015") {
+		t.Errorf("expected cost in header, got %q", got)
+	}
+}
@@ -0,0 +1,101 @@
+package conversation
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"time"
+)
+
+// ToolEvent is one tool call paired with its result.
+type ToolEvent struct {
+	ID      string
+	Name    string
+	Summary string    // short description of the input, e.g. the command run
+	Start   time.Time // when the assistant requested the call
+	End     time.Time // when the result arrived; zero while running
+	IsError bool
+}
+
+// Done reports whether the result has arrived.
+func (e ToolEvent) Done() bool {
+	return !e.End.IsZero()
+}
+
+// Duration returns how long the call took, or has been running as of now.
+func (e ToolEvent) Duration(now time.Time) time.Duration {
+	if e.Done() {
+		return e.End.Sub(e.Start)
+	}
+	return now.Sub(e.Start)
+}
+
+// summaryKeys are the tool input fields that best describe a call, in order
+// of preference.
+var summaryKeys = []string{"command", "file_path", "path", "pattern", "url", "query", "description", "prompt"}
+
+// Summary returns a one-line description of the call's input.
+func (c ToolCall) Summary() string {
+	var input map[string]interface{}
+	if err := json.Unmarshal(c.Input, &input); err != nil {
+		return ""
+	}
+	for _, k := range summaryKeys {
+		if s, ok := input[k].(string); ok && s != "" {
+			line, _, _ := strings.Cut(strings.TrimSpace(s), "\n")
+			if r := []rune(line); len(r) > 120 {
+				line = string(r[:120])
+			}
+			return line
+		}
+	}
+	return ""
+}
+
+// ReadToolTimeline returns the last maxEvents tool calls in the latest
+// conversation log of workDir, oldest first. maxEvents <= 0 returns all.
+func ReadToolTimeline(workDir string, maxEvents int) ([]ToolEvent, error) {
+	path, err := LatestLog(workDir)
+	if err != nil {
+		return nil, err
+	}
+	return parseToolTimeline(path, maxEvents)
+}
+
+// ReadToolTimelineIn is like ReadToolTimeline for the conversation log at
+// path.
+func ReadToolTimelineIn(path string, maxEvents int) ([]ToolEvent, error) {
+	return parseToolTimeline(path, maxEvents)
+}
+
+// parseToolTimeline reads the tool calls of a .jsonl log, pairing each call
+// with its result by tool_use id.
+func parseToolTimeline(path string, maxEvents int) ([]ToolEvent, error) {
+	entries, err := Stream(context.Background(), path)
+	if err != nil {
+		return nil, err
+	}
+
+	var events []ToolEvent
+	pending := make(map[string]int) // tool_use id -> index in events
+	for e, err := range entries {
+		if err != nil {
+			return events, err
+		}
+		for _, c := range e.ToolCalls {
+			pending[c.ID] = len(events)
+			events = append(events, ToolEvent{ID: c.ID, Name: c.Name, Summary: c.Summary(), Start: e.Timestamp})
+		}
+		for _, r := range e.ToolResults {
+			if i, ok := pending[r.ToolUseID]; ok {
+				events[i].End = e.Timestamp
+				events[i].IsError = r.IsError
+				delete(pending, r.ToolUseID)
+			}
+		}
+	}
+	if maxEvents > 0 && len(events) > maxEvents {
+		events = events[len(events)-maxEvents:]
+	}
+	return events, nil
+}
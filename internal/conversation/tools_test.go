@@ -0,0 +1,54 @@
+package conversation
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+// ---------------------------------------------------------------------------
+// parseToolTimeline
+// ---------------------------------------------------------------------------
+
+func TestParseToolTimeline_pairsCallsWithResults(t *testing.T) {
+	lines := append(transcriptLines,
+		`{"type":"assistant","timestamp":"2025-01-01T10:00:05Z","message":{"role":"assistant","content":[{"type":"tool_use","id":"tu2","name":"Read","input":{"file_path":"/a.go"}}]}}`,
+	)
+	events, err := parseToolTimeline(writeJSONLFile(t, lines), 0)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(events) != 2 {
+		t.Fatalf("expected 2 events, got %d", len(events))
+	}
+	bash := events[0]
+	if bash.Name != "Bash" || bash.Summary != "ls" || !bash.Done() || !bash.IsError {
+		t.Errorf("unexpected first event: %+v", bash)
+	}
+	if got := bash.Duration(time.Time{}); got != time.Second {
+		t.Errorf("expected 1s duration, got %v", got)
+	}
+	if read := events[1]; read.Done() || read.Summary != "/a.go" {
+		t.Errorf("expected a running Read of /a.go, got %+v", read)
+	}
+}
+
+func TestParseToolTimeline_keepsLastEvents(t *testing.T) {
+	lines := []string{
+		`{"type":"assistant","timestamp":"2025-01-01T10:00:00Z","message":{"role":"assistant","content":[{"type":"tool_use","id":"a","name":"One","input":{}},{"type":"tool_use","id":"b","name":"Two","input":{}}]}}`,
+	}
+	events, err := parseToolTimeline(writeJSONLFile(t, lines), 1)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(events) != 1 || events[0].Name != "Two" {
+		t.Errorf("expected only the last event, got %+v", events)
+	}
+}
+
+func TestToolCallSummary_firstLineOfPreferredField(t *testing.T) {
+	c := ToolCall{Input: json.RawMessage(`{"description":"d","command":"go test\n./..."}`)}
+	if got := c.Summary(); got != "go test" {
+		t.Errorf("expected %q, got %q", "go test", got)
+	}
+}
@@ -0,0 +1,175 @@
+package conversation
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// ToolCall is a tool invocation requested by the assistant.
+type ToolCall struct {
+	ID    string          `json:"id"`
+	Name  string          `json:"name"`
+	Input json.RawMessage `json:"input,omitempty"`
+}
+
+// ToolResult is the output of a tool call, sent back in a user entry.
+type ToolResult struct {
+	ToolUseID string `json:"tool_use_id"`
+	Content   string `json:"content"`
+	IsError   bool   `json:"is_error,omitempty"`
+}
+
+// Entry is one user or assistant record of a conversation log. Unlike
+// Message it keeps the tool calls and results the log viewer leaves out.
+type Entry struct {
+	Role        string       `json:"role"`
+	Timestamp   time.Time    `json:"timestamp"`
+	Model       string       `json:"model,omitempty"`
+	Text        string       `json:"text,omitempty"`
+	ToolCalls   []ToolCall   `json:"tool_calls,omitempty"`
+	ToolResults []ToolResult `json:"tool_results,omitempty"`
+	Usage       *Usage       `json:"usage,omitempty"`
+}
+
+// LogDir returns the directory claude keeps the conversation logs of a
+// working directory in, or "" if it cannot be found.
+func LogDir(workDir string) string {
+	return mapToProjectDir(workDir)
+}
+
+// LatestLog returns the most recent conversation log for a working directory.
+func LatestLog(workDir string) (string, error) {
+	projectDir := mapToProjectDir(workDir)
+	if projectDir == "" {
+		return "", fmt.Errorf("could not map working directory")
+	}
+	return findLatestJSONL(projectDir)
+}
+
+// SessionLog returns the conversation log of the claude session id, looked
+// for under the project directory of workDir first and then under every
+// project, since claude may have been started elsewhere. Without an id, or
+// when its log is not found (e.g. nothing has been said yet), it returns
+// LatestLog(workDir).
+func SessionLog(workDir, id string) (string, error) {
+	if path := findSessionLog(ProjectsDir(), mapToProjectDir(workDir), id); path != "" {
+		return path, nil
+	}
+	return LatestLog(workDir)
+}
+
+// findSessionLog returns the log of the session id in projectDir or another
+// directory of projectsDir, or "" if there is none.
+func findSessionLog(projectsDir, projectDir, id string) string {
+	if id == "" || strings.ContainsAny(id, `/\*?[`) {
+		return ""
+	}
+	name := id + ".jsonl"
+	if projectDir != "" {
+		if path := filepath.Join(projectDir, name); fileExists(path) {
+			return path
+		}
+	}
+	if projectsDir == "" {
+		return ""
+	}
+	matches, _ := filepath.Glob(filepath.Join(projectsDir, "*", name))
+	if len(matches) == 0 {
+		return ""
+	}
+	return matches[0]
+}
+
+// fileExists reports whether path is a regular file.
+func fileExists(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && info.Mode().IsRegular()
+}
+
+// ReadEntries reads every user and assistant entry of a .jsonl log, in order.
+// Use Stream to go through a log without holding it in memory.
+func ReadEntries(path string) ([]Entry, error) {
+	stream, err := Stream(context.Background(), path)
+	if err != nil {
+		return nil, err
+	}
+	var entries []Entry
+	for e, err := range stream {
+		if err != nil {
+			return entries, err
+		}
+		entries = append(entries, e)
+	}
+	return entries, nil
+}
+
+// scanEntry parses a JSONL line into an Entry. It returns false for lines
+// that are not user or assistant records or carry nothing to show.
+func scanEntry(b []byte) (Entry, bool) {
+	var raw jsonlEntry
+	if err := json.Unmarshal(b, &raw); err != nil {
+		return Entry{}, false
+	}
+	if (raw.Type != "user" && raw.Type != "assistant") || raw.Message == nil {
+		return Entry{}, false
+	}
+	ts, _ := time.Parse(time.RFC3339Nano, raw.Timestamp)
+	e := Entry{
+		Role:      raw.Message.Role,
+		Timestamp: ts,
+		Model:     raw.Message.Model,
+		Text:      extractContent(raw.Message),
+		Usage:     raw.Message.Usage,
+	}
+	blocks, _ := raw.Message.Content.([]interface{})
+	for _, block := range blocks {
+		m, ok := block.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		switch m["type"] {
+		case "tool_use":
+			call := ToolCall{}
+			call.ID, _ = m["id"].(string)
+			call.Name, _ = m["name"].(string)
+			if in, ok := m["input"]; ok {
+				call.Input, _ = json.Marshal(in)
+			}
+			e.ToolCalls = append(e.ToolCalls, call)
+		case "tool_result":
+			res := ToolResult{Content: toolResultText(m["content"])}
+			res.ToolUseID, _ = m["tool_use_id"].(string)
+			res.IsError, _ = m["is_error"].(bool)
+			e.ToolResults = append(e.ToolResults, res)
+		}
+	}
+	if e.Text == "" && len(e.ToolCalls) == 0 && len(e.ToolResults) == 0 {
+		return Entry{}, false
+	}
+	return e, true
+}
+
+// toolResultText flattens tool result content, which is either a string or
+// a list of text blocks.
+func toolResultText(content interface{}) string {
+	if s, ok := content.(string); ok {
+		return s
+	}
+	blocks, _ := content.([]interface{})
+	var texts []string
+	for _, block := range blocks {
+		m, ok := block.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if text, ok := m["text"].(string); ok && text != "" {
+			texts = append(texts, text)
+		}
+	}
+	return strings.Join(texts, "\n")
+}
@@ -0,0 +1,131 @@
+package conversation
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+)
+
+// ReadLog is ReadConversationFiltered for the .jsonl log at path rather
+// than the latest log of a working directory.
+func ReadLog(path string, maxMessages int, f Filter) ([]Message, Counts, error) {
+	return parseJSONLFiltered(path, maxMessages, f)
+}
+
+// LogInfo is where a conversation log was recorded, as far as it says.
+type LogInfo struct {
+	SessionID string // what claude --resume takes
+	Cwd       string // working directory of the conversation
+}
+
+// ReadLogInfo returns the session ID and working directory of the first
+// lines of the .jsonl log at path that have them.
+func ReadLogInfo(path string) (LogInfo, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return LogInfo{}, err
+	}
+	defer f.Close()
+
+	var info LogInfo
+	scanner := newLogScanner(f)
+	for scanner.Scan() && (info.SessionID == "" || info.Cwd == "") {
+		var line struct {
+			SessionID string `json:"sessionId"`
+			Cwd       string `json:"cwd"`
+		}
+		if json.Unmarshal(scanner.Bytes(), &line) != nil {
+			continue
+		}
+		if info.SessionID == "" {
+			info.SessionID = line.SessionID
+		}
+		if info.Cwd == "" {
+			info.Cwd = line.Cwd
+		}
+	}
+	return info, scanner.Err()
+}
+
+// ParseTranscript reads a conversation exported as JSON, either as written
+// by Export (the json format) or as Anthropic Messages API JSON (the
+// messages format).
+func ParseTranscript(data []byte) ([]Entry, error) {
+	var t struct {
+		Entries  []Entry      `json:"entries"`
+		Model    string       `json:"model"`
+		Messages []APIMessage `json:"messages"`
+	}
+	if err := json.Unmarshal(data, &t); err != nil {
+		return nil, fmt.Errorf("not a JSON transcript: %w", err)
+	}
+	if len(t.Entries) > 0 {
+		return t.Entries, nil
+	}
+	var entries []Entry
+	for _, m := range t.Messages {
+		e := Entry{Role: m.Role}
+		if m.Role == "assistant" {
+			e.Model = t.Model
+		}
+		var texts []string
+		for _, b := range m.Content {
+			switch b.Type {
+			case "text":
+				texts = append(texts, b.Text)
+			case "tool_use":
+				e.ToolCalls = append(e.ToolCalls, ToolCall{ID: b.ID, Name: b.Name, Input: b.Input})
+			case "tool_result":
+				e.ToolResults = append(e.ToolResults, ToolResult{ToolUseID: b.ToolUseID, Content: b.Content, IsError: b.IsError})
+			}
+		}
+		e.Text = strings.Join(texts, "\n")
+		entries = append(entries, e)
+	}
+	if len(entries) == 0 {
+		return nil, fmt.Errorf("no entries or messages in the transcript")
+	}
+	return entries, nil
+}
+
+// WriteLog writes entries as a .jsonl log in the shape claude writes, so
+// the readers of this package take it like any other log.
+func WriteLog(w io.Writer, entries []Entry) error {
+	bw := bufio.NewWriter(w)
+	enc := json.NewEncoder(bw)
+	for _, e := range entries {
+		var blocks []map[string]any
+		for _, r := range e.ToolResults {
+			blocks = append(blocks, map[string]any{"type": "tool_result", "tool_use_id": r.ToolUseID, "content": r.Content, "is_error": r.IsError})
+		}
+		if e.Text != "" {
+			blocks = append(blocks, map[string]any{"type": "text", "text": e.Text})
+		}
+		for _, c := range e.ToolCalls {
+			input := c.Input
+			if len(input) == 0 {
+				input = json.RawMessage("{}")
+			}
+			blocks = append(blocks, map[string]any{"type": "tool_use", "id": c.ID, "name": c.Name, "input": input})
+		}
+		message := map[string]any{"role": e.Role, "content": blocks}
+		if e.Model != "" {
+			message["model"] = e.Model
+		}
+		if e.Usage != nil {
+			message["usage"] = e.Usage
+		}
+		line := map[string]any{"type": e.Role, "message": message}
+		if !e.Timestamp.IsZero() {
+			line["timestamp"] = e.Timestamp.Format(time.RFC3339Nano)
+		}
+		if err := enc.Encode(line); err != nil {
+			return err
+		}
+	}
+	return bw.Flush()
+}
@@ -0,0 +1,97 @@
+package conversation
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// ---------------------------------------------------------------------------
+// ReadLogInfo
+// ---------------------------------------------------------------------------
+
+func TestReadLogInfo_findsSessionAndCwd(t *testing.T) {
+	path := writeJSONLFile(t, []string{
+		`{"type":"summary","summary":"x"}`,
+		`{"type":"user","sessionId":"0b6c","cwd":"/src/api","message":{"role":"user","content":"hi"}}`,
+	})
+	info, err := ReadLogInfo(path)
+	if err != nil || info.SessionID != "0b6c" || info.Cwd != "/src/api" {
+		t.Errorf("unexpected info %+v, %v", info, err)
+	}
+}
+
+// ---------------------------------------------------------------------------
+// ParseTranscript and WriteLog
+// ---------------------------------------------------------------------------
+
+// rewrite writes entries as a log and reads them back.
+func rewrite(t *testing.T, entries []Entry) []Entry {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := WriteLog(&buf, entries); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	path := filepath.Join(t.TempDir(), "log.jsonl")
+	os.WriteFile(path, buf.Bytes(), 0644)
+	back, err := ReadEntries(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return back
+}
+
+func TestParseTranscript_jsonExportRoundTrips(t *testing.T) {
+	entries, _ := ReadEntries(writeJSONLFile(t, transcriptLines))
+	var buf bytes.Buffer
+	if err := (Export{Title: "t", Entries: entries}).Write(&buf, ExportJSON); err != nil {
+		t.Fatal(err)
+	}
+
+	parsed, err := ParseTranscript(buf.Bytes())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := rewrite(t, parsed); !reflect.DeepEqual(got, entries) {
+		t.Errorf("expected the entries back\n got %+v\nwant %+v", got, entries)
+	}
+}
+
+func TestParseTranscript_messagesExport(t *testing.T) {
+	entries, _ := ReadEntries(writeJSONLFile(t, transcriptLines))
+	var buf bytes.Buffer
+	if err := (Export{Entries: entries, Tools: true}).Write(&buf, ExportMessages); err != nil {
+		t.Fatal(err)
+	}
+
+	parsed, err := ParseTranscript(buf.Bytes())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	got := rewrite(t, parsed)
+	if len(got) != 3 || got[0].Text != "list files" || got[1].Model != "claude-opus" || len(got[1].ToolCalls) != 1 || len(got[2].ToolResults) != 1 {
+		t.Errorf("unexpected entries %+v", got)
+	}
+	if !got[0].Timestamp.Equal(time.Time{}) {
+		t.Errorf("expected no timestamps, got %v", got[0].Timestamp)
+	}
+}
+
+func TestParseTranscript_rejectsOtherJSON(t *testing.T) {
+	for _, data := range []string{`{"sessions": []}`, `not json`} {
+		if _, err := ParseTranscript([]byte(data)); err == nil {
+			t.Errorf("expected an error for %s", data)
+		}
+	}
+}
+
+func TestReadLog_readsAFile(t *testing.T) {
+	messages, counts, err := ReadLog(writeJSONLFile(t, transcriptLines), 10, Filter{Term: "listing"})
+	if err != nil || counts.Matched != 1 || !strings.Contains(messages[0].Content, "Listing.") {
+		t.Errorf("unexpected result %+v, %+v, %v", messages, counts, err)
+	}
+}
@@ -0,0 +1,153 @@
+// Package demo makes up sessions and conversations for the dashboard's
+// --demo mode: screenshots, docs, UI work, and trying the dashboard before
+// installing tmux or claude.
+package demo
+
+import (
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/seunggabi/claude-dashboard/internal/git"
+	"github.com/seunggabi/claude-dashboard/internal/session"
+	"github.com/seunggabi/claude-dashboard/internal/testrun"
+	"github.com/seunggabi/claude-dashboard/internal/tmux"
+)
+
+// statusPeriod is how long a session keeps each status of its cycle, so the
+// demo changes while it is watched.
+const statusPeriod = 45 * time.Second
+
+// Fleet is a made-up set of sessions. It implements session.Source.
+type Fleet struct {
+	home    string
+	started time.Time
+	specs   []spec
+}
+
+// spec describes one made-up session.
+type spec struct {
+	name     string
+	project  string
+	dir      string           // relative to ~/src
+	statuses []session.Status // cycled through every statusPeriod
+	cpu      float64          // CPU percent while active
+	mem      float64          // memory percent
+	age      time.Duration    // how long before the demo the session started
+	quiet    time.Duration    // how long before the demo its conversation ended
+	managed  bool
+	git      git.Status
+	test     testrun.Result
+	tags     []string
+	windows  []session.Window
+	conflict session.Conflict
+	turns    []turn
+}
+
+// New returns the demo fleet as of now, with a working directory under
+// home/src and a conversation log under home/.claude/projects for each
+// session. home should be a temporary directory the dashboard runs in.
+func New(home string, now time.Time) (*Fleet, error) {
+	f := &Fleet{home: home, started: now, specs: specs(now)}
+	for _, s := range f.specs {
+		dir := f.path(s)
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return nil, err
+		}
+		if err := writeLog(home, dir, s, now); err != nil {
+			return nil, fmt.Errorf("demo conversation of %s: %w", s.name, err)
+		}
+	}
+	return f, nil
+}
+
+// path returns the working directory of s.
+func (f *Fleet) path(s spec) string {
+	return filepath.Join(f.home, "src", filepath.FromSlash(s.dir))
+}
+
+// Sessions returns the sessions as of now: their statuses move through
+// their cycles and CPU use wanders while active.
+func (f *Fleet) Sessions(now time.Time) []session.Session {
+	elapsed := now.Sub(f.started)
+	step := int(elapsed / statusPeriod)
+	sessions := make([]session.Session, 0, len(f.specs))
+	for i, s := range f.specs {
+		status := s.statuses[step%len(s.statuses)]
+		cpu := 0.2
+		switch status {
+		case session.StatusActive:
+			// A slow wave, out of step between sessions.
+			cpu = s.cpu * (0.7 + 0.3*math.Sin(elapsed.Seconds()/7+float64(i)))
+		case session.StatusExited:
+			cpu = 0
+		}
+		activity := now
+		if status != session.StatusActive {
+			activity = f.started.Add(-s.quiet)
+		}
+		sessions = append(sessions, session.Session{
+			Name:      s.name,
+			Project:   s.project,
+			Status:    status,
+			StartedAt: f.started.Add(-s.age),
+			Activity:  activity,
+			CPU:       math.Round(cpu*10) / 10,
+			Memory:    s.mem,
+			Path:      f.path(s),
+			Managed:   s.managed,
+			Git:       s.git,
+			Test:      s.test,
+			Conflict:  s.conflict,
+			Windows:   s.windows,
+			Tags:      s.tags,
+		})
+	}
+	return sessions
+}
+
+// Server returns the made-up tmux server the sessions run on.
+func (f *Fleet) Server() tmux.ServerInfo {
+	return tmux.ServerInfo{PID: "4242", Started: f.started.Add(-26 * time.Hour), Version: "3.5a"}
+}
+
+// Pane returns what the pane of the session called name shows: its latest
+// turn, as claude would draw it.
+func (f *Fleet) Pane(name string) string {
+	for i, s := range f.specs {
+		if s.name == name {
+			status := f.Sessions(time.Now())[i].Status
+			return pane(s, status)
+		}
+	}
+	return ""
+}
+
+// pane draws the latest turn of s, ending in what claude shows in status.
+func pane(s spec, status session.Status) string {
+	var b strings.Builder
+	last := s.turns[len(s.turns)-1]
+	fmt.Fprintf(&b, "> %s\n\n", last.prompt)
+	for _, t := range last.tools {
+		fmt.Fprintf(&b, "● %s(%s)\n", t.name, t.summary())
+		if t.result != "" {
+			line, _, _ := strings.Cut(t.result, "\n")
+			fmt.Fprintf(&b, "  ⎿  %s\n", line)
+		}
+		b.WriteString("\n")
+	}
+	switch status {
+	case session.StatusActive:
+		b.WriteString("✻ Working… (esc to interrupt)\n")
+	case session.StatusWaiting:
+		b.WriteString("Do you want to proceed?\n❯ 1. Yes\n  2. No, and tell Claude what to do differently (esc)\n")
+	case session.StatusExited:
+		fmt.Fprintf(&b, "%s\n\n$ ", last.reply)
+	default:
+		fmt.Fprintf(&b, "● %s\n\n> ", last.reply)
+	}
+	return b.String()
+}
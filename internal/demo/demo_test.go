@@ -0,0 +1,75 @@
+package demo
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/seunggabi/claude-dashboard/internal/conversation"
+	"github.com/seunggabi/claude-dashboard/internal/session"
+)
+
+// ---------------------------------------------------------------------------
+// New
+// ---------------------------------------------------------------------------
+
+func TestNew_writesAConversationForEverySession(t *testing.T) {
+	home := t.TempDir()
+	now := time.Now()
+	f, err := New(home, now)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, s := range f.specs {
+		entries, err := conversation.ReadEntries(logPath(home, f.path(s), s))
+		if err != nil || len(entries) == 0 {
+			t.Errorf("%s: expected a conversation, got %d entries, %v", s.name, len(entries), err)
+			continue
+		}
+		if last := entries[len(entries)-1].Timestamp; last.After(now) || now.Sub(last) > s.quiet+replyTime {
+			t.Errorf("%s: expected the conversation to end %s before now, ended at %s", s.name, s.quiet, last)
+		}
+	}
+}
+
+// ---------------------------------------------------------------------------
+// Sessions
+// ---------------------------------------------------------------------------
+
+func TestSessions_statusesCycle(t *testing.T) {
+	now := time.Now()
+	f, err := New(t.TempDir(), now)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	first := f.Sessions(now)
+	later := f.Sessions(now.Add(2 * statusPeriod))
+	if len(first) != len(f.specs) {
+		t.Fatalf("expected %d sessions, got %d", len(f.specs), len(first))
+	}
+	changed := false
+	for i := range first {
+		if first[i].Status != later[i].Status {
+			changed = true
+		}
+		if first[i].Status == session.StatusActive && first[i].CPU == 0 {
+			t.Errorf("%s: expected CPU use while active", first[i].Name)
+		}
+	}
+	if !changed {
+		t.Error("expected some statuses to change over time")
+	}
+}
+
+func TestPane_endsInTheStatusPrompt(t *testing.T) {
+	f, err := New(t.TempDir(), time.Now())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := pane(f.specs[0], session.StatusWaiting); !strings.Contains(got, "Do you want to proceed?") {
+		t.Errorf("expected a permission prompt, got %q", got)
+	}
+	if f.Pane("cd-nope") != "" {
+		t.Error("expected no pane for an unknown session")
+	}
+}
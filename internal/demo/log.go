@@ -0,0 +1,147 @@
+package demo
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/seunggabi/claude-dashboard/internal/conversation"
+)
+
+const (
+	model = "claude-sonnet-4-5"
+
+	// turnGap is the pause between the end of a turn and the next prompt;
+	// replyTime is how long claude takes to write each reply.
+	turnGap   = 90 * time.Second
+	replyTime = 8 * time.Second
+
+	// baseContext is the context of the first reply; each tool result adds
+	// contextStep to it.
+	baseContext = 14000
+	contextStep = 2600
+)
+
+// turn is one prompt and what claude did with it.
+type turn struct {
+	prompt string
+	tools  []tool
+	reply  string // empty when the turn is still being worked on
+}
+
+// tool is one tool call and its result.
+type tool struct {
+	name   string
+	input  map[string]string
+	result string // empty for a call still running
+	failed bool
+	took   time.Duration
+}
+
+// summary returns the input of t that claude shows after its name.
+func (t tool) summary() string {
+	for _, k := range []string{"command", "file_path", "pattern", "url"} {
+		if v, ok := t.input[k]; ok {
+			return v
+		}
+	}
+	return ""
+}
+
+// duration returns how long the turn takes.
+func (tn turn) duration() time.Duration {
+	d := replyTime
+	for _, t := range tn.tools {
+		d += replyTime + t.took
+	}
+	return d
+}
+
+// writeLog writes the conversation of s, run in dir and ending at now minus
+// s.quiet, to home/.claude/projects as claude would.
+func writeLog(home, dir string, s spec, now time.Time) error {
+	path := logPath(home, dir, s)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	enc := json.NewEncoder(f)
+	for _, line := range entries(s, dir, now.Add(-s.quiet)) {
+		if err := enc.Encode(line); err != nil {
+			f.Close()
+			return err
+		}
+	}
+	return f.Close()
+}
+
+// logPath returns where the conversation of s, run in dir, is kept.
+func logPath(home, dir string, s spec) string {
+	return filepath.Join(home, ".claude", "projects", conversation.ProjectName(dir), "demo-"+s.project+".jsonl")
+}
+
+// entries returns the log lines of the conversation of s, timed so that the
+// last one is written at end.
+func entries(s spec, dir string, end time.Time) []map[string]any {
+	var total time.Duration
+	for _, tn := range s.turns {
+		total += turnGap + tn.duration()
+	}
+	at := end.Add(-total)
+	var lines []map[string]any
+	n := 0 // for message and tool ids
+	ctx := baseContext
+	add := func(typ string, msg map[string]any) {
+		lines = append(lines, map[string]any{
+			"type":      typ,
+			"cwd":       dir,
+			"sessionId": "demo-" + s.project,
+			"timestamp": at.UTC().Format(time.RFC3339Nano),
+			"message":   msg,
+		})
+	}
+	reply := func(content []map[string]any, output int) {
+		n++
+		at = at.Add(replyTime)
+		add("assistant", map[string]any{
+			"id":      fmt.Sprintf("msg_demo_%s_%d", s.project, n),
+			"role":    "assistant",
+			"model":   model,
+			"content": content,
+			"usage": conversation.Usage{
+				InputTokens:          4,
+				OutputTokens:         output,
+				CacheReadInputTokens: ctx,
+			},
+		})
+	}
+
+	for _, tn := range s.turns {
+		at = at.Add(turnGap)
+		add("user", map[string]any{"role": "user", "content": tn.prompt})
+		for _, t := range tn.tools {
+			id := fmt.Sprintf("toolu_demo_%s_%d", s.project, n)
+			reply([]map[string]any{{"type": "tool_use", "id": id, "name": t.name, "input": t.input}}, 150+4*len(t.summary()))
+			if t.result == "" {
+				return lines // still running
+			}
+			at = at.Add(t.took)
+			ctx += contextStep
+			add("user", map[string]any{"role": "user", "content": []map[string]any{{
+				"type":        "tool_result",
+				"tool_use_id": id,
+				"content":     t.result,
+				"is_error":    t.failed,
+			}}})
+		}
+		if tn.reply != "" {
+			reply([]map[string]any{{"type": "text", "text": tn.reply}}, 200+2*len(tn.reply))
+		}
+	}
+	return lines
+}
@@ -0,0 +1,196 @@
+package demo
+
+import (
+	"time"
+
+	"github.com/seunggabi/claude-dashboard/internal/git"
+	"github.com/seunggabi/claude-dashboard/internal/session"
+	"github.com/seunggabi/claude-dashboard/internal/testrun"
+)
+
+const (
+	active  = session.StatusActive
+	waiting = session.StatusWaiting
+	idle    = session.StatusIdle
+	exited  = session.StatusExited
+)
+
+// specs returns the made-up sessions, as of now.
+func specs(now time.Time) []spec {
+	return []spec{
+		{
+			name:     "cd-shop-api",
+			project:  "shop-api",
+			dir:      "shop-api",
+			statuses: []session.Status{active, active, waiting, active},
+			cpu:      38,
+			mem:      1.8,
+			age:      3*time.Hour + 12*time.Minute,
+			managed:  true,
+			git:      git.Status{Branch: "feat/checkout-idempotency", Head: "4e1c9a2", Upstream: "origin/feat/checkout-idempotency", Ahead: 2, Changed: 5},
+			test:     testrun.Result{Passed: true, Summary: "ok  shop-api/internal/checkout  1.204s", Duration: 1204 * time.Millisecond, Source: testrun.SourcePane, At: now.Add(-4 * time.Minute)},
+			tags:     []string{"backend", "payments"},
+			windows:  []session.Window{{Index: 0, Name: "claude"}, {Index: 1, Name: "server", Titles: []string{"go run ./cmd/api"}}},
+			conflict: session.Conflict{With: []string{"cd-shop-worktree"}, Files: []string{"internal/checkout/handler.go"}},
+			turns: []turn{
+				{
+					prompt: "Add idempotency keys to POST /checkout so retried requests don't charge twice",
+					tools: []tool{
+						{name: "Grep", input: map[string]string{"pattern": "func.*Checkout"}, result: "internal/checkout/handler.go:41\ninternal/checkout/service.go:18", took: 2 * time.Second},
+						{name: "Read", input: map[string]string{"file_path": "internal/checkout/handler.go"}, result: "     1\tpackage checkout", took: time.Second},
+						{name: "Edit", input: map[string]string{"file_path": "internal/checkout/handler.go"}, result: "The file internal/checkout/handler.go has been updated.", took: time.Second},
+						{name: "Write", input: map[string]string{"file_path": "internal/checkout/idempotency.go"}, result: "File created successfully at: internal/checkout/idempotency.go", took: time.Second},
+					},
+					reply: "I added an `Idempotency-Key` header check to the checkout handler. Keys are stored for 24 hours with the response, so a retry returns the first response instead of charging again.",
+				},
+				{
+					prompt: "Now cover it with tests, including two concurrent requests with the same key",
+					tools: []tool{
+						{name: "Write", input: map[string]string{"file_path": "internal/checkout/idempotency_test.go"}, result: "File created successfully at: internal/checkout/idempotency_test.go", took: time.Second},
+						{name: "Bash", input: map[string]string{"command": "go test ./internal/checkout/..."}, result: "--- FAIL: TestIdempotency_concurrent (0.01s)\n    idempotency_test.go:58: charged 2 times, want 1\nFAIL", failed: true, took: 6 * time.Second},
+						{name: "Edit", input: map[string]string{"file_path": "internal/checkout/idempotency.go"}, result: "The file internal/checkout/idempotency.go has been updated.", took: time.Second},
+						{name: "Bash", input: map[string]string{"command": "go test -race ./internal/checkout/..."}},
+					},
+				},
+			},
+		},
+		{
+			name:     "cd-web",
+			project:  "web",
+			dir:      "web",
+			statuses: []session.Status{waiting, waiting, active, idle},
+			cpu:      22,
+			mem:      2.6,
+			age:      52 * time.Minute,
+			quiet:    2 * time.Minute,
+			managed:  true,
+			git:      git.Status{Branch: "redesign-cart", Head: "b7d03f1", Upstream: "origin/redesign-cart", Behind: 3, Changed: 2},
+			tags:     []string{"frontend"},
+			turns: []turn{
+				{
+					prompt: "The cart drawer flickers when an item is removed. Find out why and fix it",
+					tools: []tool{
+						{name: "Read", input: map[string]string{"file_path": "src/components/CartDrawer.tsx"}, result: "     1\timport { useState } from 'react'", took: time.Second},
+						{name: "Edit", input: map[string]string{"file_path": "src/components/CartDrawer.tsx"}, result: "The file src/components/CartDrawer.tsx has been updated.", took: time.Second},
+					},
+					reply: "The drawer re-mounted on every change because its `key` was the item count. It now uses a stable key, so removing an item only animates that row.",
+				},
+				{
+					prompt: "Run the e2e suite for the cart",
+					tools: []tool{
+						{name: "Bash", input: map[string]string{"command": "npx playwright test tests/cart.spec.ts"}, result: "Running 9 tests using 3 workers\n  9 passed (21.4s)", took: 24 * time.Second},
+						{name: "Bash", input: map[string]string{"command": "git push origin redesign-cart"}},
+					},
+				},
+			},
+		},
+		{
+			name:     "cd-docs",
+			project:  "docs",
+			dir:      "docs",
+			statuses: []session.Status{idle},
+			mem:      0.9,
+			age:      5*time.Hour + 40*time.Minute,
+			quiet:    38 * time.Minute,
+			managed:  true,
+			git:      git.Status{Branch: "main", Head: "09a7c55", Upstream: "origin/main"},
+			tags:     []string{"docs"},
+			turns: []turn{
+				{
+					prompt: "Write a quickstart page for the CLI, with install steps for brew and go install",
+					tools: []tool{
+						{name: "Glob", input: map[string]string{"pattern": "content/**/*.md"}, result: "content/index.md\ncontent/reference/cli.md", took: time.Second},
+						{name: "Write", input: map[string]string{"file_path": "content/quickstart.md"}, result: "File created successfully at: content/quickstart.md", took: time.Second},
+					},
+					reply: "## Quickstart\n\nI wrote `content/quickstart.md`:\n\n- install with `brew install` or `go install`\n- run the first session\n- where the config lives\n\nIt is linked from the index page.",
+				},
+			},
+		},
+		{
+			name:     "cd-infra",
+			project:  "infra",
+			dir:      "infra",
+			statuses: []session.Status{active, active, active, idle},
+			cpu:      64,
+			mem:      3.4,
+			age:      28 * time.Minute,
+			managed:  true,
+			git:      git.Status{Branch: "bump-postgres-16", Head: "e52b8d0", Changed: 3},
+			test:     testrun.Result{Summary: "FAIL: 2 of 14 checks failed", Duration: 41 * time.Second, Source: testrun.SourcePane, At: now.Add(-90 * time.Second)},
+			tags:     []string{"ops", "urgent"},
+			turns: []turn{
+				{
+					prompt: "Upgrade the staging database module to Postgres 16 and plan it",
+					tools: []tool{
+						{name: "Edit", input: map[string]string{"file_path": "modules/db/main.tf"}, result: "The file modules/db/main.tf has been updated.", took: time.Second},
+						{name: "Bash", input: map[string]string{"command": "terraform plan -var-file=staging.tfvars"}, result: "Error: Unsupported parameter group family \"postgres15\"", failed: true, took: 38 * time.Second},
+						{name: "Edit", input: map[string]string{"file_path": "modules/db/params.tf"}, result: "The file modules/db/params.tf has been updated.", took: time.Second},
+						{name: "Bash", input: map[string]string{"command": "terraform plan -var-file=staging.tfvars"}},
+					},
+				},
+			},
+		},
+		{
+			name:     "cd-shop-worktree",
+			project:  "shop-worktree",
+			dir:      "shop-api-wt/refunds",
+			statuses: []session.Status{idle, idle, active, active},
+			cpu:      17,
+			mem:      1.5,
+			age:      74 * time.Minute,
+			quiet:    6 * time.Minute,
+			managed:  true,
+			git:      git.Status{Branch: "refunds", Head: "71aa3e4", Upstream: "origin/refunds", Ahead: 1, Changed: 2},
+			tags:     []string{"backend", "payments"},
+			conflict: session.Conflict{With: []string{"cd-shop-api"}, Files: []string{"internal/checkout/handler.go"}},
+			turns: []turn{
+				{
+					prompt: "Add partial refunds to the checkout handler",
+					tools: []tool{
+						{name: "Edit", input: map[string]string{"file_path": "internal/checkout/handler.go"}, result: "The file internal/checkout/handler.go has been updated.", took: time.Second},
+						{name: "Bash", input: map[string]string{"command": "go build ./..."}, result: "(No content)", took: 4 * time.Second},
+					},
+					reply: "Partial refunds are in: `POST /checkout/{id}/refund` takes an amount up to what is left to refund.",
+				},
+			},
+		},
+		{
+			name:     "cd-ml-eval",
+			project:  "ml-eval",
+			dir:      "ml-eval",
+			statuses: []session.Status{exited},
+			age:      9 * time.Hour,
+			quiet:    2 * time.Hour,
+			managed:  true,
+			git:      git.Status{Branch: "main", Head: "c3f10b9"},
+			turns: []turn{
+				{
+					prompt: "Summarize the results of last night's eval run",
+					tools: []tool{
+						{name: "Read", input: map[string]string{"file_path": "runs/nightly/results.json"}, result: "{\"accuracy\": 0.874, \"baseline\": 0.861}", took: time.Second},
+					},
+					reply: "Accuracy went from 86.1% to 87.4%; the gain is all in the long-context split.",
+				},
+			},
+		},
+		{
+			name:     "terminal/ttys004",
+			project:  "dotfiles",
+			dir:      "dotfiles",
+			statuses: []session.Status{session.StatusTerminal},
+			cpu:      3,
+			mem:      0.7,
+			age:      20 * time.Minute,
+			quiet:    12 * time.Minute,
+			turns: []turn{
+				{
+					prompt: "Why is my zsh startup slow?",
+					tools: []tool{
+						{name: "Bash", input: map[string]string{"command": "zsh -i -c 'zprof' | head -20"}, result: "nvm_auto  61.2%", took: 3 * time.Second},
+					},
+					reply: "Most of the time goes to loading nvm. Lazy-loading it on the first `node` call takes startup from 900ms to 120ms.",
+				},
+			},
+		},
+	}
+}
@@ -0,0 +1,304 @@
+// Package filefeed watches the working directories of sessions and keeps
+// the files recently created, modified or deleted in each, leaving out what
+// git ignores.
+package filefeed
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// Op is what happened to a file.
+type Op string
+
+const (
+	Created  Op = "created"
+	Modified Op = "modified"
+	Deleted  Op = "deleted"
+	Renamed  Op = "renamed" // moved away; the new name shows up as created
+)
+
+// Change is the latest change to a file.
+type Change struct {
+	Path string // relative to the watched directory, with / separators
+	Op   Op
+	At   time.Time
+}
+
+const (
+	// maxChanges is how many changed files are kept per directory.
+	maxChanges = 100
+
+	// maxDirs bounds the directories watched under one working directory,
+	// so a session started in a home directory does not exhaust the
+	// system's watches.
+	maxDirs = 1000
+
+	// debounceDelay coalesces bursts of writes into one event.
+	debounceDelay = 250 * time.Millisecond
+)
+
+// Feed watches directories and records the changes below each. Changes
+// are only seen from when a directory starts being watched.
+type Feed struct {
+	fs     *fsnotify.Watcher
+	events chan struct{}
+	wg     sync.WaitGroup
+
+	mu      sync.Mutex
+	roots   map[string]*root // by directory
+	watched map[string]int   // watched directories, by how many roots hold them
+	closed  bool
+}
+
+// root is a watched working directory.
+type root struct {
+	dir     string
+	ignore  *ignore
+	dirs    []string // directories watched for it
+	changes []Change // oldest first
+}
+
+// New starts a feed watching nothing.
+func New() (*Feed, error) {
+	fsw, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, err
+	}
+	f := &Feed{
+		fs:      fsw,
+		events:  make(chan struct{}, 1),
+		roots:   make(map[string]*root),
+		watched: make(map[string]int),
+	}
+	f.wg.Add(1)
+	go f.loop()
+	return f, nil
+}
+
+// Events returns the channel that receives a value per coalesced burst of
+// recorded changes. It is closed by Close.
+func (f *Feed) Events() <-chan struct{} {
+	return f.events
+}
+
+// Close stops watching.
+func (f *Feed) Close() error {
+	f.mu.Lock()
+	f.closed = true
+	f.mu.Unlock()
+	err := f.fs.Close()
+	f.wg.Wait()
+	close(f.events)
+	return err
+}
+
+// Sync makes dirs the watched directories: new ones are walked and watched
+// in the background, and the changes of those left out are dropped.
+func (f *Feed) Sync(dirs []string) {
+	want := make(map[string]bool, len(dirs))
+	for _, d := range dirs {
+		if d != "" {
+			want[filepath.Clean(d)] = true
+		}
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.closed {
+		return
+	}
+	for dir, r := range f.roots {
+		if !want[dir] {
+			f.unwatch(r)
+			delete(f.roots, dir)
+		}
+	}
+	for dir := range want {
+		if _, ok := f.roots[dir]; !ok {
+			r := &root{dir: dir, ignore: repoIgnore(dir)}
+			f.roots[dir] = r
+			f.wg.Add(1)
+			go func() {
+				defer f.wg.Done()
+				f.walk(r, dir)
+			}()
+		}
+	}
+}
+
+// Recent returns the latest changes under dir, newest first, at most n;
+// nil if dir is not watched.
+func (f *Feed) Recent(dir string, n int) []Change {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	r, ok := f.roots[filepath.Clean(dir)]
+	if !ok {
+		return nil
+	}
+	out := []Change{}
+	for i := len(r.changes) - 1; i >= 0 && len(out) < n; i-- {
+		out = append(out, r.changes[i])
+	}
+	return out
+}
+
+// walk watches dir and the directories below it for r, skipping ignored
+// ones and loading their .gitignore files on the way.
+func (f *Feed) walk(r *root, dir string) {
+	_ = filepath.WalkDir(dir, func(path string, d os.DirEntry, err error) error {
+		if err != nil || !d.IsDir() {
+			return nil
+		}
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		if f.closed || f.roots[r.dir] != r {
+			return filepath.SkipAll
+		}
+		if path != r.dir && r.ignore.ignored(path, true) {
+			return filepath.SkipDir
+		}
+		if len(r.dirs) >= maxDirs {
+			return filepath.SkipAll
+		}
+		r.ignore.addFile(path, filepath.Join(path, ".gitignore"))
+		if f.watched[path] == 0 {
+			if err := f.fs.Add(path); err != nil {
+				return filepath.SkipDir
+			}
+		}
+		f.watched[path]++
+		r.dirs = append(r.dirs, path)
+		return nil
+	})
+}
+
+// unwatch stops watching the directories of r that no other root holds.
+func (f *Feed) unwatch(r *root) {
+	for _, d := range r.dirs {
+		if f.watched[d]--; f.watched[d] <= 0 {
+			delete(f.watched, d)
+			_ = f.fs.Remove(d)
+		}
+	}
+	r.dirs = nil
+}
+
+// loop records filesystem events and sends debounced Events.
+func (f *Feed) loop() {
+	defer f.wg.Done()
+	debounce := time.NewTimer(time.Hour)
+	debounce.Stop()
+	for {
+		select {
+		case ev, ok := <-f.fs.Events:
+			if !ok {
+				return
+			}
+			if f.record(ev, time.Now()) {
+				debounce.Reset(debounceDelay)
+			}
+		case _, ok := <-f.fs.Errors:
+			if !ok {
+				return
+			}
+		case <-debounce.C:
+			select {
+			case f.events <- struct{}{}:
+			default:
+			}
+		}
+	}
+}
+
+// record adds ev to the roots it is under, watching directories created
+// in them, and reports whether a change was recorded.
+func (f *Feed) record(ev fsnotify.Event, now time.Time) bool {
+	op := eventOp(ev)
+	if op == "" {
+		return false
+	}
+	isDir := false
+	if op == Created {
+		if info, err := os.Stat(ev.Name); err == nil && info.IsDir() {
+			isDir = true
+		}
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	recorded := false
+	for _, r := range f.roots {
+		if !within(r.dir, ev.Name) || r.ignore.ignored(ev.Name, isDir) {
+			continue
+		}
+		if (op == Deleted || op == Renamed) && r.ignore.ignored(ev.Name, true) {
+			continue // what is gone may have been an ignored directory
+		}
+		if isDir {
+			f.wg.Add(1)
+			go func() {
+				defer f.wg.Done()
+				f.walk(r, ev.Name)
+			}()
+			continue
+		}
+		if _, watched := f.watched[ev.Name]; watched {
+			continue // a watched directory went away
+		}
+		rel, _ := filepath.Rel(r.dir, ev.Name)
+		r.add(Change{Path: filepath.ToSlash(rel), Op: op, At: now})
+		recorded = true
+	}
+	return recorded
+}
+
+// add records c, replacing the earlier change of the same file. A file
+// written right after it was created stays created; one created where it
+// was just deleted or moved away, as editors save, was modified.
+func (r *root) add(c Change) {
+	for i, old := range r.changes {
+		if old.Path != c.Path {
+			continue
+		}
+		switch {
+		case old.Op == Created && c.Op == Modified:
+			c.Op = Created
+		case (old.Op == Deleted || old.Op == Renamed) && c.Op == Created:
+			c.Op = Modified
+		}
+		r.changes = append(r.changes[:i], r.changes[i+1:]...)
+		break
+	}
+	r.changes = append(r.changes, c)
+	if len(r.changes) > maxChanges {
+		r.changes = r.changes[len(r.changes)-maxChanges:]
+	}
+}
+
+// eventOp is the Op of a filesystem event, or "" for one that changes no
+// content, such as a permission change.
+func eventOp(ev fsnotify.Event) Op {
+	switch {
+	case ev.Has(fsnotify.Create):
+		return Created
+	case ev.Has(fsnotify.Write):
+		return Modified
+	case ev.Has(fsnotify.Remove):
+		return Deleted
+	case ev.Has(fsnotify.Rename):
+		return Renamed
+	}
+	return ""
+}
+
+// within reports whether path is below dir.
+func within(dir, path string) bool {
+	rel, err := filepath.Rel(dir, path)
+	return err == nil && rel != "." && !strings.HasPrefix(rel, "..")
+}
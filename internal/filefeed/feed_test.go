@@ -0,0 +1,131 @@
+package filefeed
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// ---------------------------------------------------------------------------
+// Feed
+// ---------------------------------------------------------------------------
+
+// waitFor waits until the latest change under dir is to path.
+func waitFor(t *testing.T, f *Feed, dir, path string, op Op) []Change {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		if got := f.Recent(dir, 10); len(got) > 0 && got[0].Path == path && got[0].Op == op {
+			return got
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+	t.Fatalf("expected %s %s, got %+v", op, path, f.Recent(dir, 10))
+	return nil
+}
+
+// waitWatched waits until dir is watched.
+func waitWatched(t *testing.T, f *Feed, dir string) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		f.mu.Lock()
+		n := f.watched[dir]
+		f.mu.Unlock()
+		if n > 0 {
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatalf("expected %s to be watched", dir)
+}
+
+func TestFeed_recordsChangesLeavingOutIgnoredFiles(t *testing.T) {
+	dir := t.TempDir()
+	os.WriteFile(filepath.Join(dir, ".gitignore"), []byte("*.log\nbuild/\n"), 0644)
+	os.MkdirAll(filepath.Join(dir, "internal", "app"), 0755)
+	os.MkdirAll(filepath.Join(dir, "build"), 0755)
+	os.WriteFile(filepath.Join(dir, "internal", "app", "app.go"), []byte("package app\n"), 0644)
+
+	f, err := New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	f.Sync([]string{dir})
+	waitWatched(t, f, filepath.Join(dir, "internal", "app"))
+
+	os.WriteFile(filepath.Join(dir, "debug.log"), []byte("x"), 0644)
+	os.WriteFile(filepath.Join(dir, "build", "out.bin"), []byte("x"), 0644)
+	os.WriteFile(filepath.Join(dir, "internal", "app", "app.go"), []byte("package app // edited\n"), 0644)
+	waitFor(t, f, dir, "internal/app/app.go", Modified)
+
+	os.Remove(filepath.Join(dir, "internal", "app", "app.go"))
+	if got := waitFor(t, f, dir, "internal/app/app.go", Deleted); len(got) != 1 {
+		t.Errorf("expected only app.go, got %+v", got)
+	}
+
+	select {
+	case <-f.Events():
+	case <-time.After(5 * time.Second):
+		t.Error("expected an event")
+	}
+
+	f.Sync(nil)
+	if got := f.Recent(dir, 10); got != nil {
+		t.Errorf("expected an unwatched directory to have no changes, got %+v", got)
+	}
+}
+
+func TestFeed_watchesNewDirectories(t *testing.T) {
+	dir := t.TempDir()
+	f, err := New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	f.Sync([]string{dir})
+	waitWatched(t, f, dir)
+
+	sub := filepath.Join(dir, "pkg")
+	os.Mkdir(sub, 0755)
+	waitWatched(t, f, sub)
+	os.WriteFile(filepath.Join(sub, "new.go"), []byte("package pkg\n"), 0644)
+	waitFor(t, f, dir, "pkg/new.go", Created)
+}
+
+func TestRootAdd_keepsTheLatestChangePerFile(t *testing.T) {
+	r := &root{}
+	at := time.Now()
+	r.add(Change{Path: "a.go", Op: Created, At: at})
+	r.add(Change{Path: "b.go", Op: Modified, At: at})
+	r.add(Change{Path: "a.go", Op: Modified, At: at.Add(time.Second)})
+	if len(r.changes) != 2 || r.changes[1].Path != "a.go" || r.changes[1].Op != Created {
+		t.Errorf("expected a.go created, last, got %+v", r.changes)
+	}
+
+	r.add(Change{Path: "b.go", Op: Renamed, At: at})
+	r.add(Change{Path: "b.go", Op: Created, At: at})
+	if c := r.changes[len(r.changes)-1]; c.Path != "b.go" || c.Op != Modified {
+		t.Errorf("expected a save through a rename to be a modification, got %+v", c)
+	}
+
+	for i := 0; i < maxChanges+5; i++ {
+		r.add(Change{Path: filepath.Join("gen", string(rune('a'+i%26)), string(rune('a'+i/26))), Op: Created})
+	}
+	if len(r.changes) != maxChanges {
+		t.Errorf("expected %d changes kept, got %d", maxChanges, len(r.changes))
+	}
+}
+
+func TestEventOp_skipsPermissionChanges(t *testing.T) {
+	if op := eventOp(fsnotify.Event{Op: fsnotify.Chmod}); op != "" {
+		t.Errorf("expected no op, got %q", op)
+	}
+	if op := eventOp(fsnotify.Event{Op: fsnotify.Create | fsnotify.Write}); op != Created {
+		t.Errorf("expected created, got %q", op)
+	}
+}
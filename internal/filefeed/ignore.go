@@ -0,0 +1,161 @@
+package filefeed
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// ignore holds gitignore rules, each applying below the directory of the
+// file it came from. Later rules win, as in git.
+type ignore struct {
+	rules []ignoreRule
+}
+
+// ignoreRule is one gitignore pattern.
+type ignoreRule struct {
+	base    string // directory the pattern is relative to
+	re      *regexp.Regexp
+	negate  bool // !pattern: not ignored after all
+	dirOnly bool // pattern/: only matches directories
+}
+
+// addFile adds the patterns of the gitignore file at path, relative to base.
+// A missing file adds nothing.
+func (ig *ignore) addFile(base, path string) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return
+	}
+	ig.add(base, string(data))
+}
+
+// add adds the gitignore patterns in text, one per line, relative to base.
+func (ig *ignore) add(base, text string) {
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.TrimRight(line, "\r")
+		if !strings.HasSuffix(line, `\ `) {
+			line = strings.TrimRight(line, " ")
+		}
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		r := ignoreRule{base: base}
+		if strings.HasPrefix(line, "!") {
+			r.negate = true
+			line = line[1:]
+		}
+		if strings.HasSuffix(line, "/") {
+			r.dirOnly = true
+			line = strings.TrimRight(line, "/")
+		}
+		if line == "" {
+			continue
+		}
+		// A slash anywhere but at the end anchors the pattern to base.
+		anchored := strings.Contains(line, "/")
+		line = strings.TrimPrefix(line, "/")
+		expr := globExpr(line)
+		if anchored {
+			expr = "^" + expr + "$"
+		} else {
+			expr = "(^|/)" + expr + "$"
+		}
+		re, err := regexp.Compile(expr)
+		if err != nil {
+			continue
+		}
+		r.re = re
+		ig.rules = append(ig.rules, r)
+	}
+}
+
+// globExpr translates a gitignore glob to a regular expression: * and ?
+// stay within a path segment, ** crosses them.
+func globExpr(glob string) string {
+	var b strings.Builder
+	for i := 0; i < len(glob); i++ {
+		c := glob[i]
+		switch {
+		case strings.HasPrefix(glob[i:], "**/"):
+			b.WriteString("(.*/)?")
+			i += 2
+		case strings.HasPrefix(glob[i:], "**"):
+			b.WriteString(".*")
+			i++
+		case c == '*':
+			b.WriteString("[^/]*")
+		case c == '?':
+			b.WriteString("[^/]")
+		case c == '[':
+			end := strings.IndexByte(glob[i+1:], ']')
+			if end < 0 {
+				b.WriteString(`\[`)
+				continue
+			}
+			class := glob[i+1 : i+1+end]
+			if strings.HasPrefix(class, "!") {
+				class = "^" + class[1:]
+			}
+			b.WriteString("[" + strings.ReplaceAll(class, `\`, `\\`) + "]")
+			i += end + 1
+		case c == '\\' && i+1 < len(glob):
+			i++
+			b.WriteString(regexp.QuoteMeta(glob[i : i+1]))
+		default:
+			b.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	return b.String()
+}
+
+// ignored reports whether path is ignored by the rules. Its parent
+// directories are assumed not to be.
+func (ig *ignore) ignored(path string, isDir bool) bool {
+	if filepath.Base(path) == ".git" {
+		return true
+	}
+	ignored := false
+	for _, r := range ig.rules {
+		if r.dirOnly && !isDir {
+			continue
+		}
+		rel, err := filepath.Rel(r.base, path)
+		if err != nil || rel == "." || strings.HasPrefix(rel, "..") {
+			continue
+		}
+		if r.re.MatchString(filepath.ToSlash(rel)) {
+			ignored = !r.negate
+		}
+	}
+	return ignored
+}
+
+// repoIgnore returns the rules that apply in dir from outside it: the
+// repository's info/exclude and the .gitignore files of the parents of dir
+// up to the repository root. Outside a repository there are none.
+func repoIgnore(dir string) *ignore {
+	ig := &ignore{}
+	var parents []string // from dir's parent up to the repository root
+	top := dir
+	for !exists(filepath.Join(top, ".git")) {
+		parent := filepath.Dir(top)
+		if parent == top {
+			return ig
+		}
+		top = parent
+		parents = append(parents, top)
+	}
+	ig.addFile(top, filepath.Join(top, ".git", "info", "exclude"))
+	for i := len(parents) - 1; i >= 0; i-- {
+		ig.addFile(parents[i], filepath.Join(parents[i], ".gitignore"))
+	}
+	return ig
+}
+
+// exists reports whether path exists.
+func exists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
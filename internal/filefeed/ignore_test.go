@@ -0,0 +1,87 @@
+package filefeed
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// ---------------------------------------------------------------------------
+// ignore
+// ---------------------------------------------------------------------------
+
+func TestIgnore_gitignorePatterns(t *testing.T) {
+	ig := &ignore{}
+	ig.add("/repo", `
+# build output
+node_modules/
+/dist
+*.log
+!keep.log
+docs/**/*.tmp
+sub/generated.go
+`)
+	ig.add("/repo/web", "cache\n")
+	cases := []struct {
+		path  string
+		isDir bool
+		want  bool
+	}{
+		{"/repo/node_modules", true, true},
+		{"/repo/web/node_modules", true, true},
+		{"/repo/node_modules", false, false}, // dir-only pattern
+		{"/repo/dist", true, true},
+		{"/repo/web/dist", true, false}, // anchored to /repo
+		{"/repo/debug.log", false, true},
+		{"/repo/web/debug.log", false, true},
+		{"/repo/keep.log", false, false},
+		{"/repo/docs/a/b/x.tmp", false, true},
+		{"/repo/docs/x.tmp", false, true},
+		{"/repo/sub/generated.go", false, true},
+		{"/repo/other/sub/generated.go", false, false},
+		{"/repo/web/cache", false, true},
+		{"/repo/cache", false, false}, // the rule is web's
+		{"/repo/main.go", false, false},
+		{"/repo/.git", true, true},
+	}
+	for _, tc := range cases {
+		path := filepath.FromSlash(tc.path)
+		if got := ig.ignored(path, tc.isDir); got != tc.want {
+			t.Errorf("ignored(%s, dir=%v): expected %v, got %v", tc.path, tc.isDir, tc.want, got)
+		}
+	}
+}
+
+func TestGlobExpr(t *testing.T) {
+	cases := map[string]string{
+		"*.go":    `[^/]*\.go`,
+		"a?c":     `a[^/]c`,
+		"[!x]y":   `[^x]y`,
+		"**/foo":  `(.*/)?foo`,
+		"foo/**":  `foo/.*`,
+		`\#note`:  `#note`,
+		"[broken": `\[broken`,
+	}
+	for glob, want := range cases {
+		if got := globExpr(glob); got != want {
+			t.Errorf("globExpr(%q): expected %q, got %q", glob, want, got)
+		}
+	}
+}
+
+func TestRepoIgnore_readsParentsUpToTheRepositoryRoot(t *testing.T) {
+	top := t.TempDir()
+	os.MkdirAll(filepath.Join(top, ".git", "info"), 0755)
+	os.WriteFile(filepath.Join(top, ".git", "info", "exclude"), []byte("*.swp\n"), 0644)
+	os.WriteFile(filepath.Join(top, ".gitignore"), []byte("vendor/\n"), 0644)
+	dir := filepath.Join(top, "services", "api")
+	os.MkdirAll(dir, 0755)
+
+	ig := repoIgnore(dir)
+	if !ig.ignored(filepath.Join(dir, "vendor"), true) || !ig.ignored(filepath.Join(dir, "x.swp"), false) {
+		t.Errorf("expected the repository's rules to apply, got %+v", ig.rules)
+	}
+	if rules := repoIgnore(t.TempDir()).rules; len(rules) != 0 {
+		t.Errorf("expected no rules outside a repository, got %+v", rules)
+	}
+}
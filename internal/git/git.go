@@ -0,0 +1,210 @@
+// Package git reads the branch and working tree state of the repositories
+// sessions work in.
+package git
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// timeout bounds one git status call; a huge repository should not stall
+// a refresh.
+const timeout = 3 * time.Second
+
+// ErrNotRepo is returned for a directory outside any git repository.
+var ErrNotRepo = errors.New("not a git repository")
+
+// Status is the state of a repository's working tree.
+type Status struct {
+	Branch   string // empty when HEAD is detached
+	Head     string // abbreviated commit of HEAD; empty before the first commit
+	Upstream string // e.g. "origin/main"; empty without one
+	Ahead    int    // commits not on the upstream
+	Behind   int    // upstream commits not merged
+	Changed  int    // changed, staged, conflicted and untracked paths
+}
+
+// IsZero reports whether no status was read.
+func (s Status) IsZero() bool {
+	return s == Status{}
+}
+
+// Dirty reports whether the working tree has uncommitted changes.
+func (s Status) Dirty() bool {
+	return s.Changed > 0
+}
+
+// Ref returns the branch name, or the commit when HEAD is detached.
+func (s Status) Ref() string {
+	if s.Branch != "" {
+		return s.Branch
+	}
+	if s.Head != "" {
+		return "(" + s.Head + ")"
+	}
+	return ""
+}
+
+// Short describes the status in a few columns, e.g. "main* ↑2↓1".
+func (s Status) Short() string {
+	out := s.Ref()
+	if s.Dirty() {
+		out += "*"
+	}
+	if ab := s.aheadBehind(); ab != "" {
+		out += " " + ab
+	}
+	return out
+}
+
+// String describes the status in full, e.g. "main ↑2 ↓1, 3 changed".
+func (s Status) String() string {
+	out := s.Ref()
+	if s.Upstream != "" {
+		out += " → " + s.Upstream
+		if s.Ahead > 0 {
+			out += fmt.Sprintf(" ↑%d", s.Ahead)
+		}
+		if s.Behind > 0 {
+			out += fmt.Sprintf(" ↓%d", s.Behind)
+		}
+	}
+	if s.Dirty() {
+		return out + fmt.Sprintf(", %d changed", s.Changed)
+	}
+	return out + ", clean"
+}
+
+func (s Status) aheadBehind() string {
+	var out string
+	if s.Ahead > 0 {
+		out += fmt.Sprintf("↑%d", s.Ahead)
+	}
+	if s.Behind > 0 {
+		out += fmt.Sprintf("↓%d", s.Behind)
+	}
+	return out
+}
+
+// Read returns the status of the repository containing dir.
+func Read(ctx context.Context, dir string) (Status, error) {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	out, err := gitOutput(ctx, dir, "status", "--porcelain=v2", "--branch")
+	if err != nil {
+		return Status{}, err
+	}
+	return parseStatus(out), nil
+}
+
+// gitOutput runs git in dir, telling a directory outside any repository
+// apart by ErrNotRepo.
+func gitOutput(ctx context.Context, dir string, args ...string) (string, error) {
+	cmd := exec.CommandContext(ctx, "git", append([]string{"-C", dir}, args...)...)
+	cmd.Env = append(os.Environ(), "LC_ALL=C")
+	out, err := cmd.Output()
+	if err != nil {
+		var exit *exec.ExitError
+		if errors.As(err, &exit) && strings.Contains(string(exit.Stderr), "not a git repository") {
+			return "", ErrNotRepo
+		}
+		return "", fmt.Errorf("git %s in %s: %w", args[0], dir, err)
+	}
+	return string(out), nil
+}
+
+// HooksDir returns the directory git runs the hooks of the repository
+// containing dir from, core.hooksPath included.
+func HooksDir(ctx context.Context, dir string) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	out, err := gitOutput(ctx, dir, "rev-parse", "--git-path", "hooks")
+	if err != nil {
+		return "", err
+	}
+	hooks := strings.TrimSpace(out)
+	if !filepath.IsAbs(hooks) {
+		hooks = filepath.Join(dir, hooks)
+	}
+	return hooks, nil
+}
+
+// parseStatus reads the output of git status --porcelain=v2 --branch.
+func parseStatus(out string) Status {
+	var s Status
+	for _, line := range strings.Split(out, "\n") {
+		switch {
+		case strings.HasPrefix(line, "# branch.oid "):
+			if oid := strings.TrimPrefix(line, "# branch.oid "); oid != "(initial)" && len(oid) >= 7 {
+				s.Head = oid[:7]
+			}
+		case strings.HasPrefix(line, "# branch.head "):
+			if head := strings.TrimPrefix(line, "# branch.head "); head != "(detached)" {
+				s.Branch = head
+			}
+		case strings.HasPrefix(line, "# branch.upstream "):
+			s.Upstream = strings.TrimPrefix(line, "# branch.upstream ")
+		case strings.HasPrefix(line, "# branch.ab "):
+			for _, f := range strings.Fields(strings.TrimPrefix(line, "# branch.ab ")) {
+				n, _ := strconv.Atoi(f[1:])
+				if f[0] == '+' {
+					s.Ahead = n
+				} else {
+					s.Behind = n
+				}
+			}
+		case line != "" && !strings.HasPrefix(line, "#") && !strings.HasPrefix(line, "!"):
+			s.Changed++ // "1", "2", "u" and "?" entries
+		}
+	}
+	return s
+}
+
+// Cache remembers statuses for a while, so sessions sharing a directory and
+// frequent refreshes do not run git each time.
+type Cache struct {
+	ttl  time.Duration
+	read func(ctx context.Context, dir string) (Status, error)
+	now  func() time.Time
+
+	mu      sync.Mutex
+	entries map[string]cacheEntry
+}
+
+type cacheEntry struct {
+	status Status
+	err    error
+	at     time.Time
+}
+
+// NewCache returns a cache keeping each status for ttl.
+func NewCache(ttl time.Duration) *Cache {
+	return &Cache{ttl: ttl, read: Read, now: time.Now, entries: make(map[string]cacheEntry)}
+}
+
+// Get returns the status of dir, reading it if the cached one is older than
+// the cache's ttl. Errors are cached too, so a directory outside any
+// repository is not retried on every call.
+func (c *Cache) Get(ctx context.Context, dir string) (Status, error) {
+	now := c.now()
+	c.mu.Lock()
+	e, ok := c.entries[dir]
+	c.mu.Unlock()
+	if ok && now.Sub(e.at) < c.ttl {
+		return e.status, e.err
+	}
+
+	status, err := c.read(ctx, dir)
+	c.mu.Lock()
+	c.entries[dir] = cacheEntry{status: status, err: err, at: now}
+	c.mu.Unlock()
+	return status, err
+}
@@ -0,0 +1,307 @@
+package git
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// ---------------------------------------------------------------------------
+// parseStatus
+// ---------------------------------------------------------------------------
+
+func TestParseStatus_branchUpstreamAndChanges(t *testing.T) {
+	out := "# branch.oid 1234567890abcdef\n" +
+		"# branch.head feature/x\n" +
+		"# branch.upstream origin/feature/x\n" +
+		"# branch.ab +2 -1\n" +
+		"1 .M N... 100644 100644 100644 abc abc main.go\n" +
+		"? notes.txt\n"
+	s := parseStatus(out)
+	want := Status{Branch: "feature/x", Head: "1234567", Upstream: "origin/feature/x", Ahead: 2, Behind: 1, Changed: 2}
+	if s != want {
+		t.Errorf("expected %+v, got %+v", want, s)
+	}
+	if got := s.Short(); got != "feature/x* ↑2↓1" {
+		t.Errorf("unexpected short form %q", got)
+	}
+	if got := s.String(); got != "feature/x → origin/feature/x ↑2 ↓1, 2 changed" {
+		t.Errorf("unexpected long form %q", got)
+	}
+}
+
+func TestParseStatus_detachedHead(t *testing.T) {
+	s := parseStatus("# branch.oid abcdef0123\n# branch.head (detached)\n")
+	if s.Branch != "" || s.Ref() != "(abcdef0)" || s.Dirty() {
+		t.Errorf("expected a clean detached head at abcdef0, got %+v", s)
+	}
+	if got := s.String(); got != "(abcdef0), clean" {
+		t.Errorf("unexpected long form %q", got)
+	}
+}
+
+func TestParseStatus_initialCommit(t *testing.T) {
+	s := parseStatus("# branch.oid (initial)\n# branch.head main\n")
+	if s.Branch != "main" || s.Head != "" {
+		t.Errorf("expected main with no commit, got %+v", s)
+	}
+}
+
+// ---------------------------------------------------------------------------
+// Read
+// ---------------------------------------------------------------------------
+
+func TestRead_repositoryAndPlainDirectory(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	dir := t.TempDir()
+	if _, err := Read(context.Background(), dir); !errors.Is(err, ErrNotRepo) {
+		t.Errorf("expected ErrNotRepo, got %v", err)
+	}
+
+	if err := exec.Command("git", "init", "-q", "-b", "trunk", dir).Run(); err != nil {
+		t.Skipf("git init failed: %v", err)
+	}
+	if err := os.WriteFile(dir+"/a.txt", []byte("a"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	s, err := Read(context.Background(), dir)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if s.Branch != "trunk" || s.Changed != 1 {
+		t.Errorf("expected trunk with 1 change, got %+v", s)
+	}
+}
+
+// ---------------------------------------------------------------------------
+// Cache
+// ---------------------------------------------------------------------------
+
+func TestCache_rereadsOnlyAfterTTL(t *testing.T) {
+	now := time.Unix(1000, 0)
+	reads := 0
+	c := NewCache(10 * time.Second)
+	c.now = func() time.Time { return now }
+	c.read = func(context.Context, string) (Status, error) {
+		reads++
+		return Status{Branch: "main", Changed: reads}, nil
+	}
+
+	c.Get(context.Background(), "/repo")
+	now = now.Add(5 * time.Second)
+	if s, _ := c.Get(context.Background(), "/repo"); s.Changed != 1 || reads != 1 {
+		t.Errorf("expected the cached status, got %+v after %d reads", s, reads)
+	}
+	now = now.Add(6 * time.Second)
+	if s, _ := c.Get(context.Background(), "/repo"); s.Changed != 2 || reads != 2 {
+		t.Errorf("expected a fresh status, got %+v after %d reads", s, reads)
+	}
+}
+
+// ---------------------------------------------------------------------------
+// Log
+// ---------------------------------------------------------------------------
+
+func TestParseLog_fields(t *testing.T) {
+	out := "abc1234\x1fAda\x1f2025-11-24T10:00:00+01:00\x1fFix: a | b\x1fcd-api\n"
+	commits := parseLog(out)
+	if len(commits) != 1 {
+		t.Fatalf("expected 1 commit, got %d", len(commits))
+	}
+	c := commits[0]
+	if c.Hash != "abc1234" || c.Author != "Ada" || c.Subject != "Fix: a | b" || c.Session != "cd-api" {
+		t.Errorf("unexpected commit %+v", c)
+	}
+	if want := time.Date(2025, 11, 24, 9, 0, 0, 0, time.UTC); !c.Time.Equal(want) {
+		t.Errorf("expected %v, got %v", want, c.Time)
+	}
+}
+
+func TestLog_commitsInWindow(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	dir := t.TempDir()
+	if err := exec.Command("git", "init", "-q", dir).Run(); err != nil {
+		t.Skipf("git init failed: %v", err)
+	}
+	now := time.Now()
+	if commits, err := Log(context.Background(), dir, now.Add(-time.Hour), now.Add(time.Hour)); err != nil || len(commits) != 0 {
+		t.Fatalf("expected no commits in a new repository, got %v, %v", commits, err)
+	}
+
+	commit := exec.Command("git", "-C", dir, "-c", "user.name=Ada", "-c", "user.email=ada@example.com",
+		"commit", "-q", "--allow-empty", "-m", "first")
+	if err := commit.Run(); err != nil {
+		t.Skipf("git commit failed: %v", err)
+	}
+	commits, err := Log(context.Background(), dir, now.Add(-time.Hour), now.Add(time.Hour))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(commits) != 1 || commits[0].Subject != "first" || commits[0].Author != "Ada" {
+		t.Errorf("expected the first commit, got %+v", commits)
+	}
+	if commits, _ := Log(context.Background(), dir, now.Add(-2*time.Hour), now.Add(-time.Hour)); len(commits) != 0 {
+		t.Errorf("expected no commits before the window, got %+v", commits)
+	}
+}
+
+func TestSessionCommits_byTrailer(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	dir := t.TempDir()
+	if err := exec.Command("git", "init", "-q", dir).Run(); err != nil {
+		t.Skipf("git init failed: %v", err)
+	}
+	for _, msg := range []string{
+		"first\n\nClaude-Session: cd-api",
+		"second\n\nClaude-Session: cd-api-2",
+		"third: mentions Claude-Session: cd-api in passing",
+	} {
+		commit := exec.Command("git", "-C", dir, "-c", "user.name=Ada", "-c", "user.email=ada@example.com",
+			"commit", "-q", "--allow-empty", "-m", msg)
+		if err := commit.Run(); err != nil {
+			t.Skipf("git commit failed: %v", err)
+		}
+	}
+	commits, err := SessionCommits(context.Background(), dir, "cd-api")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(commits) != 1 || commits[0].Subject != "first" || commits[0].Session != "cd-api" {
+		t.Errorf("expected only the commit with the trailer, got %+v", commits)
+	}
+}
+
+func TestHooksDir_followsHooksPath(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	dir := t.TempDir()
+	if err := exec.Command("git", "init", "-q", dir).Run(); err != nil {
+		t.Skipf("git init failed: %v", err)
+	}
+	if got, err := HooksDir(context.Background(), dir); err != nil || got != filepath.Join(dir, ".git", "hooks") {
+		t.Errorf("expected .git/hooks, got %q, %v", got, err)
+	}
+	custom := filepath.Join(t.TempDir(), "hooks")
+	if err := exec.Command("git", "-C", dir, "config", "core.hooksPath", custom).Run(); err != nil {
+		t.Fatalf("git config: %v", err)
+	}
+	if got, _ := HooksDir(context.Background(), dir); got != custom {
+		t.Errorf("expected %s, got %q", custom, got)
+	}
+}
+
+// ---------------------------------------------------------------------------
+// Snapshot
+// ---------------------------------------------------------------------------
+
+func TestParseChanged_skipsIgnoredAndRenameSources(t *testing.T) {
+	out := " M b.go\x00?? new/c.go\x00R  a2.go\x00a.go\x00!! build/out\x00"
+	got := parseChanged(out)
+	want := []string{"a2.go", "b.go", "new/c.go"}
+	if len(got) != len(want) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("expected %v, got %v", want, got)
+		}
+	}
+}
+
+func TestTakeSnapshot_worktreesShareTheRepository(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	dir := t.TempDir()
+	main, wt := filepath.Join(dir, "main"), filepath.Join(dir, "wt")
+	if err := exec.Command("git", "init", "-q", main).Run(); err != nil {
+		t.Skipf("git init failed: %v", err)
+	}
+	commit := exec.Command("git", "-C", main, "-c", "user.name=Ada", "-c", "user.email=ada@example.com",
+		"commit", "-q", "--allow-empty", "-m", "first")
+	if err := commit.Run(); err != nil {
+		t.Skipf("git commit failed: %v", err)
+	}
+	if err := exec.Command("git", "-C", main, "worktree", "add", "-q", wt).Run(); err != nil {
+		t.Skipf("git worktree failed: %v", err)
+	}
+	if err := os.MkdirAll(filepath.Join(wt, "pkg"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(wt, "pkg", "a.go"), []byte("a"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	a, err := TakeSnapshot(context.Background(), main)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	b, err := TakeSnapshot(context.Background(), filepath.Join(wt, "pkg"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if a.Repo != b.Repo {
+		t.Errorf("expected worktrees to share the repository, got %q and %q", a.Repo, b.Repo)
+	}
+	if len(a.Changed) != 0 || !b.Has("pkg/a.go") {
+		t.Errorf("expected only the worktree to have pkg/a.go, got %v and %v", a.Changed, b.Changed)
+	}
+}
+
+// ---------------------------------------------------------------------------
+// Worktrees
+// ---------------------------------------------------------------------------
+
+func TestRemoveWorktree_refusesChangesThenRemoves(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	dir := t.TempDir()
+	main, wt := filepath.Join(dir, "main"), filepath.Join(dir, "wt")
+	if err := exec.Command("git", "init", "-q", main).Run(); err != nil {
+		t.Skipf("git init failed: %v", err)
+	}
+	commit := exec.Command("git", "-C", main, "-c", "user.name=Ada", "-c", "user.email=ada@example.com",
+		"commit", "-q", "--allow-empty", "-m", "first")
+	if err := commit.Run(); err != nil {
+		t.Skipf("git commit failed: %v", err)
+	}
+	if err := exec.Command("git", "-C", main, "worktree", "add", "-q", wt).Run(); err != nil {
+		t.Skipf("git worktree failed: %v", err)
+	}
+	if IsLinkedWorktree(main) || !IsLinkedWorktree(wt) {
+		t.Fatalf("expected only %s to be a linked worktree", wt)
+	}
+	if err := RemoveWorktree(context.Background(), main); err == nil {
+		t.Error("expected the main worktree to be refused")
+	}
+
+	untracked := filepath.Join(wt, "notes.txt")
+	if err := os.WriteFile(untracked, []byte("x"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := RemoveWorktree(context.Background(), wt); err == nil || !strings.Contains(err.Error(), "untracked") {
+		t.Errorf("expected a worktree with untracked files to be refused, got %v", err)
+	}
+	if err := os.Remove(untracked); err != nil {
+		t.Fatal(err)
+	}
+	if err := RemoveWorktree(context.Background(), wt); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := os.Stat(wt); !os.IsNotExist(err) {
+		t.Errorf("expected %s to be removed, got %v", wt, err)
+	}
+}
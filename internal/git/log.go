@@ -0,0 +1,101 @@
+package git
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// Commit is one commit of a repository's history.
+type Commit struct {
+	Hash    string    `json:"hash"` // abbreviated
+	Author  string    `json:"author"`
+	Subject string    `json:"subject"`
+	Time    time.Time `json:"time"`              // committer date
+	Session string    `json:"session,omitempty"` // from its SessionTrailer
+}
+
+// SessionTrailer is the trailer naming the dashboard session a commit was
+// made in, added by the commit-msg hook setup installs.
+const SessionTrailer = "Claude-Session"
+
+// logFormat separates the fields of a commit with the unit separator and
+// commits with newlines; subjects are single lines.
+const logFormat = "%h%x1f%an%x1f%cI%x1f%s%x1f%(trailers:key=" + SessionTrailer + ",valueonly,separator=%x2C)"
+
+// Log returns the commits on any branch of the repository containing dir
+// that were committed between since and until, newest first.
+func Log(ctx context.Context, dir string, since, until time.Time) ([]Commit, error) {
+	return logCommits(ctx, dir, "--since="+since.Format(time.RFC3339), "--until="+until.Format(time.RFC3339))
+}
+
+// SessionCommits returns the commits on any branch of the repository
+// containing dir whose SessionTrailer names the session called name,
+// newest first.
+func SessionCommits(ctx context.Context, dir, name string) ([]Commit, error) {
+	commits, err := logCommits(ctx, dir, "--fixed-strings", "--grep="+SessionTrailer+": "+name)
+	var found []Commit
+	for _, c := range commits {
+		for _, s := range strings.Split(c.Session, ",") {
+			if strings.TrimSpace(s) == name {
+				found = append(found, c)
+				break
+			}
+		}
+	}
+	return found, err
+}
+
+// logCommits runs git log --all in dir with args.
+func logCommits(ctx context.Context, dir string, args ...string) ([]Commit, error) {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	cmd := exec.CommandContext(ctx, "git", append([]string{"-C", dir, "log", "--all", "--format=" + logFormat}, args...)...)
+	cmd.Env = append(os.Environ(), "LC_ALL=C")
+	out, err := cmd.Output()
+	if err != nil {
+		var exit *exec.ExitError
+		if errors.As(err, &exit) {
+			stderr := string(exit.Stderr)
+			if strings.Contains(stderr, "not a git repository") {
+				return nil, ErrNotRepo
+			}
+			if strings.Contains(stderr, "does not have any commits") {
+				return nil, nil
+			}
+		}
+		return nil, fmt.Errorf("git log in %s: %w", dir, err)
+	}
+	return parseLog(string(out)), nil
+}
+
+// parseLog reads the output of git log --format=logFormat.
+func parseLog(out string) []Commit {
+	var commits []Commit
+	for _, line := range strings.Split(out, "\n") {
+		f := strings.Split(line, "\x1f")
+		if len(f) != 5 {
+			continue
+		}
+		t, _ := time.Parse(time.RFC3339, f[2])
+		commits = append(commits, Commit{Hash: f[0], Author: f[1], Time: t, Subject: f[3], Session: f[4]})
+	}
+	return commits
+}
+
+// TopLevel returns the root of the repository containing dir, so that
+// several directories of one repository can be told apart from different
+// repositories.
+func TopLevel(ctx context.Context, dir string) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	out, err := gitOutput(ctx, dir, "rev-parse", "--show-toplevel")
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(out), nil
+}
@@ -0,0 +1,72 @@
+package git
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Snapshot is the set of uncommitted paths of a working tree at one moment.
+type Snapshot struct {
+	Root    string   // top level of the working tree
+	Repo    string   // common git directory, shared by the worktrees of a repository
+	Changed []string // changed, staged and untracked paths relative to Root, sorted
+}
+
+// Has reports whether path, relative to Root, is uncommitted.
+func (s Snapshot) Has(path string) bool {
+	i := sort.SearchStrings(s.Changed, path)
+	return i < len(s.Changed) && s.Changed[i] == path
+}
+
+// TakeSnapshot returns the uncommitted paths of the working tree containing
+// dir.
+func TakeSnapshot(ctx context.Context, dir string) (Snapshot, error) {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	out, err := gitOutput(ctx, dir, "rev-parse", "--show-toplevel", "--git-common-dir")
+	if err != nil {
+		return Snapshot{}, err
+	}
+	lines := strings.Split(strings.TrimSpace(out), "\n")
+	if len(lines) != 2 {
+		return Snapshot{}, fmt.Errorf("git rev-parse in %s: unexpected output %q", dir, out)
+	}
+	s := Snapshot{Root: lines[0], Repo: lines[1]}
+	if !filepath.IsAbs(s.Repo) {
+		s.Repo = filepath.Join(dir, s.Repo)
+	}
+	s.Repo = filepath.Clean(s.Repo)
+
+	out, err = gitOutput(ctx, s.Root, "status", "--porcelain=v1", "-z", "--untracked-files=all")
+	if err != nil {
+		return Snapshot{}, err
+	}
+	s.Changed = parseChanged(out)
+	return s, nil
+}
+
+// parseChanged reads the paths of git status --porcelain=v1 -z, leaving out
+// ignored ones and the sources of renames and copies.
+func parseChanged(out string) []string {
+	var paths []string
+	fields := strings.Split(out, "\x00")
+	for i := 0; i < len(fields); i++ {
+		entry := fields[i]
+		if len(entry) < 4 {
+			continue
+		}
+		xy := entry[:2]
+		if xy == "!!" {
+			continue
+		}
+		paths = append(paths, entry[3:])
+		if strings.ContainsAny(xy, "RC") {
+			i++ // the next field is the source path
+		}
+	}
+	sort.Strings(paths)
+	return paths
+}
@@ -0,0 +1,49 @@
+package git
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// IsLinkedWorktree reports whether dir is the top of a linked worktree, one
+// added with git worktree add. Its .git is a file pointing into the
+// worktrees of the main repository; a submodule's points into modules.
+func IsLinkedWorktree(dir string) bool {
+	if dir == "" {
+		return false
+	}
+	data, err := os.ReadFile(filepath.Join(dir, ".git"))
+	if err != nil {
+		return false
+	}
+	gitdir, ok := strings.CutPrefix(strings.TrimSpace(string(data)), "gitdir: ")
+	return ok && filepath.Base(filepath.Dir(filepath.Clean(gitdir))) == "worktrees"
+}
+
+// RemoveWorktree removes the linked worktree at dir. Like git worktree
+// remove without --force, it refuses a worktree with changed or untracked
+// files; its branch is kept.
+func RemoveWorktree(ctx context.Context, dir string) error {
+	if !IsLinkedWorktree(dir) {
+		return fmt.Errorf("%s is not a linked git worktree", dir)
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	cmd := exec.CommandContext(ctx, "git", "-C", dir, "worktree", "remove", dir)
+	cmd.Env = append(os.Environ(), "LC_ALL=C")
+	if _, err := cmd.Output(); err != nil {
+		var exit *exec.ExitError
+		if errors.As(err, &exit) {
+			if msg, _, _ := strings.Cut(strings.TrimSpace(string(exit.Stderr)), "\n"); msg != "" {
+				return fmt.Errorf("git worktree remove %s: %s", dir, strings.TrimPrefix(msg, "fatal: "))
+			}
+		}
+		return fmt.Errorf("git worktree remove %s: %w", dir, err)
+	}
+	return nil
+}
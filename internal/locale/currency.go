@@ -0,0 +1,83 @@
+package locale
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+// Currency is what costs, computed in US dollars, are shown in.
+type Currency struct {
+	Code   string  // ISO 4217, e.g. "EUR"
+	Symbol string  // e.g. "€"; the code when empty
+	Rate   float64 // units of the currency per US dollar
+}
+
+// USD is the currency costs are computed in.
+var USD = Currency{Code: "USD", Symbol: "$", Rate: 1}
+
+// symbols of common currencies; others are written with their code.
+var symbols = map[string]string{
+	"USD": "$", "EUR": "€", "GBP": "£", "JPY": "¥", "CNY": "¥", "KRW": "₩",
+	"INR": "₹", "CHF": "CHF", "CAD": "CA$", "AUD": "A$", "BRL": "R$",
+	"SEK": "kr", "NOK": "kr", "DKK": "kr", "PLN": "zł", "RUB": "₽", "TRY": "₺",
+}
+
+// noMinor are currencies without a minor unit, written without decimals.
+var noMinor = map[string]bool{"JPY": true, "KRW": true, "HUF": true, "ISK": true}
+
+var currencyCode = regexp.MustCompile(`^[A-Z]{3}$`)
+
+// NewCurrency returns the currency with code at rate units per US dollar,
+// with its usual symbol unless symbol is given. USD needs no rate.
+func NewCurrency(code, symbol string, rate float64) (Currency, error) {
+	if !currencyCode.MatchString(code) {
+		return USD, fmt.Errorf("currency code %q is not three capital letters (e.g. EUR)", code)
+	}
+	if code == "USD" && rate == 0 {
+		rate = 1
+	}
+	if rate <= 0 {
+		return USD, fmt.Errorf("currency %s needs a rate, in %s per US dollar, above 0", code, code)
+	}
+	if symbol == "" {
+		symbol = symbols[code]
+	}
+	if symbol == "" {
+		symbol = code
+	}
+	return Currency{Code: code, Symbol: symbol, Rate: rate}, nil
+}
+
+// decimals is how many digits the currency is written with.
+func (c Currency) decimals() int {
+	if noMinor[c.Code] {
+		return 0
+	}
+	return 2
+}
+
+// Money writes an amount of US dollars in c, e.g. "$4.20" or "3,86 €".
+func (l Locale) Money(usd float64, c Currency) string {
+	return l.money(usd, c, c.decimals())
+}
+
+// SmallMoney is Money with one more digit, for the cost of a single message.
+func (l Locale) SmallMoney(usd float64, c Currency) string {
+	return l.money(usd, c, c.decimals()+1)
+}
+
+func (l Locale) money(usd float64, c Currency, decimals int) string {
+	if c.Rate == 0 {
+		c = USD
+	}
+	amount := l.Number(usd*c.Rate, decimals)
+	switch {
+	case l.after:
+		return amount + nbsp + c.Symbol
+	case utf8.RuneCountInString(c.Symbol) > 1 && !strings.HasSuffix(c.Symbol, "$"):
+		return c.Symbol + nbsp + amount // a code or word, e.g. "CHF 4.20"
+	}
+	return c.Symbol + amount
+}
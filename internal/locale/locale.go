@@ -0,0 +1,187 @@
+// Package locale formats numbers, token counts, money and dates the way a
+// locale writes them: "1,234.5" in English, "1.234,5" in German, "1 234,5"
+// in French. It knows the separators of common locales rather than the
+// full CLDR data, and converts costs, kept in US dollars, to a currency.
+package locale
+
+import (
+	"fmt"
+	"math"
+	"os"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// Locale is how numbers and dates are written.
+type Locale struct {
+	Tag      string // e.g. "de-DE"; empty for the default
+	decimal  string
+	group    string
+	after    bool   // currency symbol after the amount
+	dateTime string // time layout of a date and time
+}
+
+// Default writes numbers as in English and dates as ISO 8601, as the
+// dashboard did before it was localized.
+var Default = Locale{decimal: ".", group: ",", dateTime: "2006-01-02 15:04:05"}
+
+// nbsp keeps numbers grouped with spaces, and amounts and their symbols,
+// on one line.
+const nbsp = "\u00a0"
+
+// styles are the separators of each language; regions below override them.
+var styles = map[string]Locale{
+	"en": Default,
+	"ja": {decimal: ".", group: ",", dateTime: "2006/01/02 15:04:05"},
+	"zh": {decimal: ".", group: ",", dateTime: "2006/01/02 15:04:05"},
+	"ko": {decimal: ".", group: ",", dateTime: "2006. 01. 02. 15:04:05"},
+	"de": {decimal: ",", group: ".", after: true, dateTime: "02.01.2006 15:04:05"},
+	"nl": {decimal: ",", group: ".", dateTime: "02-01-2006 15:04:05"},
+	"da": {decimal: ",", group: ".", after: true, dateTime: "02.01.2006 15.04.05"},
+	"es": {decimal: ",", group: ".", after: true, dateTime: "02/01/2006 15:04:05"},
+	"it": {decimal: ",", group: ".", after: true, dateTime: "02/01/2006 15:04:05"},
+	"pt": {decimal: ",", group: ".", after: true, dateTime: "02/01/2006 15:04:05"},
+	"tr": {decimal: ",", group: ".", dateTime: "02.01.2006 15:04:05"},
+	"id": {decimal: ",", group: ".", dateTime: "02/01/2006 15.04.05"},
+	"fr": {decimal: ",", group: nbsp, after: true, dateTime: "02/01/2006 15:04:05"},
+	"ru": {decimal: ",", group: nbsp, after: true, dateTime: "02.01.2006 15:04:05"},
+	"uk": {decimal: ",", group: nbsp, after: true, dateTime: "02.01.2006 15:04:05"},
+	"pl": {decimal: ",", group: nbsp, after: true, dateTime: "02.01.2006 15:04:05"},
+	"cs": {decimal: ",", group: nbsp, after: true, dateTime: "02.01.2006 15:04:05"},
+	"sv": {decimal: ",", group: nbsp, after: true, dateTime: "2006-01-02 15:04:05"},
+	"nb": {decimal: ",", group: nbsp, after: true, dateTime: "02.01.2006 15:04:05"},
+	"fi": {decimal: ",", group: nbsp, after: true, dateTime: "02.01.2006 15.04.05"},
+	"hu": {decimal: ",", group: nbsp, after: true, dateTime: "2006. 01. 02. 15:04:05"},
+}
+
+// regions are locales whose region writes numbers or dates differently
+// from the rest of its language.
+var regions = map[string]Locale{
+	"en-US": {decimal: ".", group: ",", dateTime: "01/02/2006 3:04:05 PM"},
+	"en-GB": {decimal: ".", group: ",", dateTime: "02/01/2006 15:04:05"},
+	"en-IN": {decimal: ".", group: ",", dateTime: "02/01/2006 15:04:05"},
+	"de-CH": {decimal: ".", group: "’", dateTime: "02.01.2006 15:04:05"},
+	"fr-CH": {decimal: ".", group: "’", after: true, dateTime: "02.01.2006 15:04:05"},
+	"de-AT": {decimal: ",", group: nbsp, dateTime: "02.01.2006 15:04:05"},
+	"pt-BR": {decimal: ",", group: ".", dateTime: "02/01/2006 15:04:05"},
+	"es-MX": {decimal: ".", group: ",", dateTime: "02/01/2006 15:04:05"},
+}
+
+// Parse returns the locale of a tag such as "de", "de-DE" or, as in LANG,
+// "de_DE.UTF-8". ok is false for a language it does not know, which gets
+// Default; "C" and "POSIX" are known and get Default too.
+func Parse(tag string) (l Locale, ok bool) {
+	tag, _, _ = strings.Cut(tag, ".") // encoding
+	tag, _, _ = strings.Cut(tag, "@") // modifier
+	tag = strings.ReplaceAll(tag, "_", "-")
+	lang, region, _ := strings.Cut(tag, "-")
+	lang = strings.ToLower(lang)
+	if lang == "" || lang == "c" || lang == "posix" {
+		return Default, lang != ""
+	}
+	tag = lang
+	if region != "" {
+		tag += "-" + strings.ToUpper(region)
+	}
+	if l, ok := regions[tag]; ok {
+		l.Tag = tag
+		return l, true
+	}
+	l, ok = styles[lang]
+	if !ok {
+		return Default, false
+	}
+	l.Tag = tag
+	return l, true
+}
+
+// FromEnv returns the locale numbers are written in according to the
+// environment: LC_ALL, LC_NUMERIC, then LANG.
+func FromEnv() Locale {
+	for _, name := range []string{"LC_ALL", "LC_NUMERIC", "LANG"} {
+		if v := os.Getenv(name); v != "" {
+			l, _ := Parse(v)
+			return l
+		}
+	}
+	return Default
+}
+
+// Number writes v with decimals digits after the decimal separator and
+// grouped thousands.
+func (l Locale) Number(v float64, decimals int) string {
+	s := fmt.Sprintf("%.*f", decimals, math.Abs(v))
+	whole, frac, _ := strings.Cut(s, ".")
+	var b strings.Builder
+	if v < 0 && strings.Trim(s, "0.") != "" {
+		b.WriteString("-")
+	}
+	for i, d := range whole {
+		if i > 0 && (len(whole)-i)%3 == 0 {
+			b.WriteString(l.group)
+		}
+		b.WriteRune(d)
+	}
+	if frac != "" {
+		b.WriteString(l.decimal + frac)
+	}
+	return b.String()
+}
+
+// Int writes n with grouped thousands.
+func (l Locale) Int(n int) string {
+	return l.Number(float64(n), 0)
+}
+
+// Compact writes a count in at most a few characters, e.g. 950, 12.3k or
+// 1.2M (12,3k and 1,2M in German).
+func (l Locale) Compact(n int) string {
+	switch {
+	case n >= 1_000_000:
+		return l.Number(float64(n)/1_000_000, 1) + "M"
+	case n >= 1000:
+		return l.Number(float64(n)/1000, 1) + "k"
+	default:
+		return l.Int(n)
+	}
+}
+
+// DateTime writes t as a date and time.
+func (l Locale) DateTime(t time.Time) string {
+	return t.Format(l.dateTime)
+}
+
+// current is the locale and currency set by Set.
+var current atomic.Pointer[settings]
+
+type settings struct {
+	locale   Locale
+	currency Currency
+}
+
+// Set makes l and c what Current and CurrentCurrency return.
+func Set(l Locale, c Currency) {
+	current.Store(&settings{locale: l, currency: c})
+}
+
+// Current returns the locale set with Set, or Default.
+func Current() Locale {
+	if s := current.Load(); s != nil {
+		return s.locale
+	}
+	return Default
+}
+
+// CurrentCurrency returns the currency set with Set, or USD.
+func CurrentCurrency() Currency {
+	if s := current.Load(); s != nil {
+		return s.currency
+	}
+	return USD
+}
+
+// Percent writes v, a percentage, with one decimal, e.g. "12.5%".
+func (l Locale) Percent(v float64) string {
+	return l.Number(v, 1) + "%"
+}
@@ -0,0 +1,180 @@
+package locale
+
+import (
+	"testing"
+	"time"
+)
+
+// ---------------------------------------------------------------------------
+// Parse
+// ---------------------------------------------------------------------------
+
+func TestParse_acceptsTagsAndLANGValues(t *testing.T) {
+	tests := []struct {
+		tag, want string
+		ok        bool
+	}{
+		{"de", "de", true},
+		{"de-DE", "de-DE", true},
+		{"de_CH.UTF-8", "de-CH", true},
+		{"fr_FR@euro", "fr-FR", true},
+		{"C.UTF-8", "", true},
+		{"POSIX", "", true},
+		{"xx-YY", "", false},
+		{"", "", false},
+	}
+	for _, tt := range tests {
+		l, ok := Parse(tt.tag)
+		if l.Tag != tt.want || ok != tt.ok {
+			t.Errorf("Parse(%q) = %q, %v; expected %q, %v", tt.tag, l.Tag, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestFromEnv_prefersLCAll(t *testing.T) {
+	t.Setenv("LC_ALL", "de_DE.UTF-8")
+	t.Setenv("LC_NUMERIC", "fr_FR.UTF-8")
+	t.Setenv("LANG", "en_US.UTF-8")
+	if got := FromEnv().Tag; got != "de-DE" {
+		t.Errorf("expected de-DE, got %q", got)
+	}
+	t.Setenv("LC_ALL", "")
+	if got := FromEnv().Tag; got != "fr-FR" {
+		t.Errorf("expected fr-FR, got %q", got)
+	}
+}
+
+// ---------------------------------------------------------------------------
+// Number
+// ---------------------------------------------------------------------------
+
+func TestNumber_usesTheSeparatorsOfTheLocale(t *testing.T) {
+	tests := []struct {
+		tag  string
+		want string
+	}{
+		{"en-US", "1,234,567.89"},
+		{"de-DE", "1.234.567,89"},
+		{"fr-FR", "1\u00a0234\u00a0567,89"},
+		{"de-CH", "1’234’567.89"},
+	}
+	for _, tt := range tests {
+		l, _ := Parse(tt.tag)
+		if got := l.Number(1234567.891, 2); got != tt.want {
+			t.Errorf("%s: expected %q, got %q", tt.tag, tt.want, got)
+		}
+	}
+}
+
+func TestNumber_smallAndNegative(t *testing.T) {
+	if got := Default.Number(-1234.5, 1); got != "-1,234.5" {
+		t.Errorf("expected -1,234.5, got %q", got)
+	}
+	if got := Default.Number(-0.001, 2); got != "0.00" {
+		t.Errorf("expected no sign on a value that rounds to zero, got %q", got)
+	}
+	if got := Default.Int(999); got != "999" {
+		t.Errorf("expected 999, got %q", got)
+	}
+}
+
+func TestCompact_tokenCounts(t *testing.T) {
+	de, _ := Parse("de")
+	tests := []struct {
+		n      int
+		en, de string
+	}{
+		{950, "950", "950"},
+		{12_345, "12.3k", "12,3k"},
+		{3_400_000, "3.4M", "3,4M"},
+		{1_250_000_000, "1,250.0M", "1.250,0M"},
+	}
+	for _, tt := range tests {
+		if got := Default.Compact(tt.n); got != tt.en {
+			t.Errorf("en %d: expected %q, got %q", tt.n, tt.en, got)
+		}
+		if got := de.Compact(tt.n); got != tt.de {
+			t.Errorf("de %d: expected %q, got %q", tt.n, tt.de, got)
+		}
+	}
+}
+
+func TestDateTime_layoutOfTheLocale(t *testing.T) {
+	at := time.Date(2026, 3, 7, 14, 5, 9, 0, time.UTC)
+	tests := map[string]string{
+		"":      "2026-03-07 14:05:09",
+		"en-US": "03/07/2026 2:05:09 PM",
+		"de":    "07.03.2026 14:05:09",
+		"ja":    "2026/03/07 14:05:09",
+	}
+	for tag, want := range tests {
+		l, _ := Parse(tag)
+		if got := l.DateTime(at); got != want {
+			t.Errorf("%q: expected %q, got %q", tag, want, got)
+		}
+	}
+}
+
+// ---------------------------------------------------------------------------
+// Money
+// ---------------------------------------------------------------------------
+
+func TestMoney_convertsAndPlacesTheSymbol(t *testing.T) {
+	eur, _ := NewCurrency("EUR", "", 0.5)
+	jpy, _ := NewCurrency("JPY", "", 150)
+	chf, _ := NewCurrency("CHF", "", 1)
+	de, _ := Parse("de-DE")
+	tests := []struct {
+		l    Locale
+		c    Currency
+		usd  float64
+		want string
+	}{
+		{Default, USD, 4.2, "$4.20"},
+		{Default, Currency{}, 4.2, "$4.20"},
+		{de, eur, 4.2, "2,10\u00a0€"},
+		{Default, eur, 4.2, "€2.10"},
+		{Default, jpy, 4.2, "¥630"},
+		{Default, chf, 1234.5, "CHF\u00a01,234.50"},
+	}
+	for _, tt := range tests {
+		if got := tt.l.Money(tt.usd, tt.c); got != tt.want {
+			t.Errorf("Money(%v, %s) in %q: expected %q, got %q", tt.usd, tt.c.Code, tt.l.Tag, tt.want, got)
+		}
+	}
+	if got := Default.SmallMoney(0.0123, USD); got != "$0.012" {
+		t.Errorf("expected one more digit, got %q", got)
+	}
+}
+
+func TestNewCurrency_validates(t *testing.T) {
+	if _, err := NewCurrency("eur", "", 1); err == nil {
+		t.Error("expected an error for a lower case code")
+	}
+	if _, err := NewCurrency("EUR", "", 0); err == nil {
+		t.Error("expected an error for a missing rate")
+	}
+	if c, err := NewCurrency("USD", "", 0); err != nil || c.Rate != 1 {
+		t.Errorf("expected USD without a rate to be 1:1, got %+v, %v", c, err)
+	}
+	if c, _ := NewCurrency("XYZ", "", 2); c.Symbol != "XYZ" {
+		t.Errorf("expected the code as symbol of an unknown currency, got %q", c.Symbol)
+	}
+}
+
+// ---------------------------------------------------------------------------
+// Set
+// ---------------------------------------------------------------------------
+
+func TestSet_changesCurrent(t *testing.T) {
+	defer current.Store(nil)
+	if Current().Tag != "" || CurrentCurrency() != USD {
+		t.Fatal("expected the defaults before Set")
+	}
+	fr, _ := Parse("fr")
+	eur, _ := NewCurrency("EUR", "", 0.9)
+	Set(fr, eur)
+	if Current().Tag != "fr" || CurrentCurrency().Code != "EUR" {
+		t.Errorf("expected fr and EUR, got %q and %q", Current().Tag, CurrentCurrency().Code)
+	}
+}
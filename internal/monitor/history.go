@@ -0,0 +1,150 @@
+package monitor
+
+import (
+	"strings"
+	"time"
+)
+
+// HistorySize is how many samples are kept per session: an hour at the
+// default refresh interval.
+const HistorySize = int(time.Hour / DefaultInterval)
+
+// Sample is one reading of a session. CPU and Memory are only meaningful
+// for local sessions; Status and Attached are recorded for every session.
+type Sample struct {
+	Time     time.Time
+	CPU      float64
+	Memory   float64
+	Status   string // session.Status at the time of the sample
+	Attached bool
+
+	Activity time.Time // last output in the pane
+	LogSize  int64     // size of the conversation log; 0 when not read
+	Output   int64     // what was written since the previous sample, set by History.Record
+}
+
+// Ring is a fixed-size buffer of samples that overwrites the oldest entry
+// when full.
+type Ring struct {
+	buf  []Sample
+	head int // next write position
+	n    int
+}
+
+// NewRing creates a ring holding up to size samples.
+func NewRing(size int) *Ring {
+	if size < 1 {
+		size = 1
+	}
+	return &Ring{buf: make([]Sample, size)}
+}
+
+// Add appends a sample, dropping the oldest one if the ring is full.
+func (r *Ring) Add(s Sample) {
+	r.buf[r.head] = s
+	r.head = (r.head + 1) % len(r.buf)
+	if r.n < len(r.buf) {
+		r.n++
+	}
+}
+
+// Len returns the number of samples held.
+func (r *Ring) Len() int {
+	return r.n
+}
+
+// Last returns the latest n samples, oldest first.
+func (r *Ring) Last(n int) []Sample {
+	n = min(n, r.n)
+	out := make([]Sample, n)
+	for i := range out {
+		out[i] = r.buf[(r.head-n+i+len(r.buf))%len(r.buf)]
+	}
+	return out
+}
+
+// Since returns the samples taken at or after t, oldest first.
+func (r *Ring) Since(t time.Time) []Sample {
+	out := make([]Sample, 0, r.n)
+	start := (r.head - r.n + len(r.buf)) % len(r.buf)
+	for i := 0; i < r.n; i++ {
+		s := r.buf[(start+i)%len(r.buf)]
+		if !s.Time.Before(t) {
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
+// History keeps a sample ring per session key.
+type History struct {
+	size  int
+	rings map[string]*Ring
+}
+
+// NewHistory creates a history keeping size samples per session.
+func NewHistory(size int) *History {
+	return &History{size: size, rings: make(map[string]*Ring)}
+}
+
+// Record adds a sample for key, with its Output measured against the
+// previous one.
+func (h *History) Record(key string, s Sample) {
+	r, ok := h.rings[key]
+	if !ok {
+		r = NewRing(h.size)
+		h.rings[key] = r
+	}
+	if prev := r.Last(1); len(prev) == 1 {
+		s.Output = output(prev[0], s)
+	}
+	r.Add(s)
+}
+
+// output returns what a session wrote between samples prev and s: the
+// bytes its conversation log grew by or, when the log was not read, 1 if
+// the pane had output. A new, shorter log counts as fresh output.
+func output(prev, s Sample) int64 {
+	switch {
+	case s.LogSize > 0 && prev.LogSize > 0 && s.LogSize >= prev.LogSize:
+		return s.LogSize - prev.LogSize
+	case s.LogSize > 0 && prev.LogSize > 0:
+		return s.LogSize
+	case s.Activity.After(prev.Activity) && !prev.Activity.IsZero():
+		return 1
+	}
+	return 0
+}
+
+// Last returns the latest n samples recorded for key, oldest first.
+func (h *History) Last(key string, n int) []Sample {
+	r, ok := h.rings[key]
+	if !ok {
+		return nil
+	}
+	return r.Last(n)
+}
+
+// Since returns the samples recorded for key at or after t, oldest first.
+func (h *History) Since(key string, t time.Time) []Sample {
+	r, ok := h.rings[key]
+	if !ok {
+		return nil
+	}
+	return r.Since(t)
+}
+
+// Forget drops the samples of key, e.g. when its session is gone.
+func (h *History) Forget(key string) {
+	delete(h.rings, key)
+}
+
+// Prune drops the rings of the keys starting with prefix that are not in
+// keep, e.g. of sessions of a host that no longer exist.
+func (h *History) Prune(prefix string, keep map[string]bool) {
+	for k := range h.rings {
+		if strings.HasPrefix(k, prefix) && !keep[k] {
+			delete(h.rings, k)
+		}
+	}
+}
@@ -0,0 +1,81 @@
+package monitor
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+// ---------------------------------------------------------------------------
+// Ring / History
+// ---------------------------------------------------------------------------
+
+func TestRing_overwritesOldestWhenFull(t *testing.T) {
+	base := time.Unix(1700000000, 0)
+	r := NewRing(3)
+	for i := 0; i < 5; i++ {
+		r.Add(Sample{Time: base.Add(time.Duration(i) * time.Second), CPU: float64(i)})
+	}
+	got := r.Since(time.Time{})
+	if len(got) != 3 || got[0].CPU != 2 || got[2].CPU != 4 {
+		t.Errorf("expected samples 2..4 oldest first, got %+v", got)
+	}
+	if got := r.Since(base.Add(4 * time.Second)); len(got) != 1 {
+		t.Errorf("expected 1 sample since the last timestamp, got %d", len(got))
+	}
+}
+
+func TestHistory_forgetDropsOnlyThatSession(t *testing.T) {
+	h := NewHistory(10)
+	h.Record("/cd-a", Sample{Time: time.Now()})
+	h.Record("/cd-b", Sample{Time: time.Now()})
+	h.Forget("/cd-b")
+	if len(h.Since("/cd-a", time.Time{})) != 1 {
+		t.Error("expected other session to retain its samples")
+	}
+	if h.Since("/cd-b", time.Time{}) != nil {
+		t.Error("expected forgotten session to have no samples")
+	}
+}
+
+func TestHistory_pruneDropsOnlyGoneSessionsOfTheHost(t *testing.T) {
+	h := NewHistory(10)
+	for _, key := range []string{"/cd-a", "/cd-b", "devbox/cd-b"} {
+		h.Record(key, Sample{Time: time.Now()})
+	}
+	h.Prune("/", map[string]bool{"/cd-a": true})
+	if h.Last("/cd-a", 1) == nil || h.Last("devbox/cd-b", 1) == nil {
+		t.Error("expected live sessions and those of other hosts to keep their samples")
+	}
+	if h.Last("/cd-b", 1) != nil {
+		t.Error("expected the gone session to be pruned")
+	}
+}
+
+func TestHistory_recordMeasuresOutputAgainstPreviousSample(t *testing.T) {
+	base := time.Unix(1700000000, 0)
+	h := NewHistory(10)
+	h.Record("/cd-a", Sample{Time: base, LogSize: 1000, Activity: base})
+	h.Record("/cd-a", Sample{Time: base.Add(2 * time.Second), LogSize: 1500, Activity: base})
+	h.Record("/cd-a", Sample{Time: base.Add(4 * time.Second), LogSize: 1500, Activity: base})
+	h.Record("/cd-a", Sample{Time: base.Add(6 * time.Second), LogSize: 200, Activity: base}) // a new conversation
+	var got []int64
+	for _, s := range h.Last("/cd-a", 10) {
+		got = append(got, s.Output)
+	}
+	if want := []int64{0, 500, 0, 200}; !reflect.DeepEqual(got, want) {
+		t.Errorf("expected log growth %v, got %v", want, got)
+	}
+
+	// Without a log, pane output counts.
+	h.Record("/cd-b", Sample{Time: base, Activity: base})
+	h.Record("/cd-b", Sample{Time: base.Add(2 * time.Second), Activity: base.Add(time.Second)})
+	h.Record("/cd-b", Sample{Time: base.Add(4 * time.Second), Activity: base.Add(time.Second)})
+	got = nil
+	for _, s := range h.Last("/cd-b", 2) {
+		got = append(got, s.Output)
+	}
+	if want := []int64{1, 0}; !reflect.DeepEqual(got, want) {
+		t.Errorf("expected pane output %v, got %v", want, got)
+	}
+}
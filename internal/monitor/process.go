@@ -1,11 +1,5 @@
 package monitor
 
-import (
-	"os/exec"
-	"strconv"
-	"strings"
-)
-
 // ProcessInfo holds CPU and memory usage for a process.
 type ProcessInfo struct {
 	PID    string
@@ -17,6 +11,7 @@ type ProcessInfo struct {
 type ProcessTableEntry struct {
 	PID  string
 	PPID string
+	TTY  string // controlling terminal, e.g. "pts/3"; "?" when detached
 	CPU  float64
 	Mem  float64
 	Args string
@@ -25,33 +20,27 @@ type ProcessTableEntry struct {
 // ProcessTable is a map from PID to ProcessTableEntry.
 type ProcessTable map[string]ProcessTableEntry
 
-// GetProcessTable runs ps once and returns a full process table.
+// ProcessProvider collects process information from the operating system.
+// Linux reads /proc directly, Windows uses gopsutil, and other platforms
+// shell out to ps and lsof.
+type ProcessProvider interface {
+	// ProcessTable returns a snapshot of every process.
+	ProcessTable() ProcessTable
+	// ProcessCWD returns the working directory of pid, or "" if unknown.
+	ProcessCWD(pid string) string
+}
+
+// provider is the platform ProcessProvider, set by the build-tagged files.
+var provider ProcessProvider = newProvider()
+
+// GetProcessTable returns a full process table from the platform provider.
 func GetProcessTable() ProcessTable {
-	cmd := exec.Command("ps", "-eo", "pid,ppid,%cpu,%mem,args")
-	out, err := cmd.Output()
-	if err != nil {
-		return ProcessTable{}
-	}
+	return provider.ProcessTable()
+}
 
-	table := make(ProcessTable)
-	lines := strings.Split(string(out), "\n")
-	for _, line := range lines[1:] { // skip header
-		fields := strings.Fields(line)
-		if len(fields) < 5 {
-			continue
-		}
-		cpu, _ := strconv.ParseFloat(fields[2], 64)
-		mem, _ := strconv.ParseFloat(fields[3], 64)
-		entry := ProcessTableEntry{
-			PID:  fields[0],
-			PPID: fields[1],
-			CPU:  cpu,
-			Mem:  mem,
-			Args: strings.Join(fields[4:], " "),
-		}
-		table[entry.PID] = entry
-	}
-	return table
+// GetProcessCWD returns the working directory of a process.
+func GetProcessCWD(pid string) string {
+	return provider.ProcessCWD(pid)
 }
 
 // GetChildProcessInfo returns aggregated CPU/memory for a PID and all children
@@ -0,0 +1,177 @@
+//go:build linux
+
+package monitor
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// clockTicks is USER_HZ, the unit of the CPU times in /proc/<pid>/stat.
+// It is 100 on every mainstream Linux architecture.
+const clockTicks = 100
+
+// procProvider reads process information straight from /proc, avoiding a
+// ps/lsof fork on every refresh.
+type procProvider struct {
+	root string
+}
+
+func newProvider() ProcessProvider {
+	return procProvider{root: "/proc"}
+}
+
+// procStat holds the fields of /proc/<pid>/stat that the dashboard uses.
+type procStat struct {
+	comm      string
+	ppid      string
+	ttyNr     int64
+	utime     float64 // clock ticks
+	stime     float64 // clock ticks
+	starttime float64 // clock ticks since boot
+	rss       int64   // pages
+}
+
+// ProcessTable reads every /proc/<pid> entry into a table. CPU is averaged
+// over the process lifetime and memory is RSS over MemTotal, matching ps.
+func (p procProvider) ProcessTable() ProcessTable {
+	entries, err := os.ReadDir(p.root)
+	if err != nil {
+		return ProcessTable{}
+	}
+
+	uptime := p.uptime()
+	memTotal := p.memTotalBytes()
+	pageSize := int64(os.Getpagesize())
+
+	table := make(ProcessTable)
+	for _, e := range entries {
+		pid := e.Name()
+		if _, err := strconv.Atoi(pid); err != nil {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(p.root, pid, "stat"))
+		if err != nil {
+			continue // process exited while scanning
+		}
+		st, err := parseProcStat(data)
+		if err != nil {
+			continue
+		}
+
+		entry := ProcessTableEntry{
+			PID:  pid,
+			PPID: st.ppid,
+			TTY:  ttyName(st.ttyNr),
+			Args: p.cmdline(pid, st.comm),
+		}
+		if elapsed := uptime - st.starttime/clockTicks; elapsed > 0 {
+			entry.CPU = (st.utime + st.stime) / clockTicks / elapsed * 100
+		}
+		if memTotal > 0 {
+			entry.Mem = float64(st.rss*pageSize) / float64(memTotal) * 100
+		}
+		table[pid] = entry
+	}
+	return table
+}
+
+// ProcessCWD resolves the /proc/<pid>/cwd symlink.
+func (p procProvider) ProcessCWD(pid string) string {
+	cwd, err := os.Readlink(filepath.Join(p.root, pid, "cwd"))
+	if err != nil {
+		return ""
+	}
+	return cwd
+}
+
+// cmdline returns the NUL-separated argv as a space-joined string. Kernel
+// threads have an empty cmdline and are shown as [comm], like ps does.
+func (p procProvider) cmdline(pid, comm string) string {
+	data, err := os.ReadFile(filepath.Join(p.root, pid, "cmdline"))
+	if err != nil || len(data) == 0 {
+		return "[" + comm + "]"
+	}
+	data = bytes.TrimRight(data, "\x00")
+	return string(bytes.ReplaceAll(data, []byte{0}, []byte{' '}))
+}
+
+// uptime returns seconds since boot from /proc/uptime.
+func (p procProvider) uptime() float64 {
+	data, err := os.ReadFile(filepath.Join(p.root, "uptime"))
+	if err != nil {
+		return 0
+	}
+	fields := strings.Fields(string(data))
+	if len(fields) == 0 {
+		return 0
+	}
+	up, _ := strconv.ParseFloat(fields[0], 64)
+	return up
+}
+
+// memTotalBytes returns MemTotal from /proc/meminfo.
+func (p procProvider) memTotalBytes() int64 {
+	f, err := os.Open(filepath.Join(p.root, "meminfo"))
+	if err != nil {
+		return 0
+	}
+	defer f.Close()
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) >= 2 && fields[0] == "MemTotal:" {
+			kb, _ := strconv.ParseInt(fields[1], 10, 64)
+			return kb * 1024
+		}
+	}
+	return 0
+}
+
+// parseProcStat parses /proc/<pid>/stat. The command name is wrapped in
+// parentheses and may itself contain spaces or parentheses, so fields are
+// counted from the last ')'.
+func parseProcStat(data []byte) (procStat, error) {
+	open := bytes.IndexByte(data, '(')
+	closeIdx := bytes.LastIndexByte(data, ')')
+	if open < 0 || closeIdx < open {
+		return procStat{}, fmt.Errorf("malformed stat")
+	}
+	// fields[0] is field 3 (state) in proc(5) numbering.
+	fields := strings.Fields(string(data[closeIdx+1:]))
+	if len(fields) < 22 {
+		return procStat{}, fmt.Errorf("short stat: %d fields", len(fields))
+	}
+	st := procStat{
+		comm: string(data[open+1 : closeIdx]),
+		ppid: fields[1],
+	}
+	st.ttyNr, _ = strconv.ParseInt(fields[4], 10, 64)
+	st.utime, _ = strconv.ParseFloat(fields[11], 64)
+	st.stime, _ = strconv.ParseFloat(fields[12], 64)
+	st.starttime, _ = strconv.ParseFloat(fields[19], 64)
+	st.rss, _ = strconv.ParseInt(fields[21], 10, 64)
+	return st, nil
+}
+
+// ttyName converts a tty_nr device number to the name ps prints.
+func ttyName(ttyNr int64) string {
+	if ttyNr == 0 {
+		return "?"
+	}
+	major := (ttyNr >> 8) & 0xfff
+	minor := (ttyNr & 0xff) | ((ttyNr >> 12) & 0xfff00)
+	switch {
+	case major == 4 && minor < 64:
+		return fmt.Sprintf("tty%d", minor)
+	case major >= 136 && major <= 143:
+		return fmt.Sprintf("pts/%d", minor+(major-136)*256)
+	default:
+		return fmt.Sprintf("%d,%d", major, minor)
+	}
+}
@@ -0,0 +1,82 @@
+//go:build linux
+
+package monitor
+
+import (
+	"os"
+	"strconv"
+	"testing"
+)
+
+// ---------------------------------------------------------------------------
+// parseProcStat
+// ---------------------------------------------------------------------------
+
+func TestParseProcStat_commWithSpacesAndParens(t *testing.T) {
+	line := "4242 (my (weird) cmd) S 1 4242 4242 34819 4242 4194304 100 0 0 0 250 50 0 0 20 0 1 0 12345 1000000 512 18446744073709551615\n"
+	st, err := parseProcStat([]byte(line))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if st.comm != "my (weird) cmd" {
+		t.Errorf("comm: expected %q, got %q", "my (weird) cmd", st.comm)
+	}
+	if st.ppid != "1" {
+		t.Errorf("ppid: expected %q, got %q", "1", st.ppid)
+	}
+	if st.ttyNr != 34819 || st.utime != 250 || st.stime != 50 || st.starttime != 12345 || st.rss != 512 {
+		t.Errorf("unexpected stat: %+v", st)
+	}
+}
+
+func TestParseProcStat_malformedReturnsError(t *testing.T) {
+	if _, err := parseProcStat([]byte("garbage")); err == nil {
+		t.Error("expected error for malformed stat")
+	}
+	if _, err := parseProcStat([]byte("1 (x) S 0")); err == nil {
+		t.Error("expected error for short stat")
+	}
+}
+
+// ---------------------------------------------------------------------------
+// ttyName
+// ---------------------------------------------------------------------------
+
+func TestTTYName_tableTests(t *testing.T) {
+	cases := []struct {
+		nr   int64
+		want string
+	}{
+		{0, "?"},
+		{34819, "pts/3"}, // major 136, minor 3
+		{1025, "tty1"},   // major 4, minor 1
+	}
+	for _, tc := range cases {
+		if got := ttyName(tc.nr); got != tc.want {
+			t.Errorf("ttyName(%d): expected %q, got %q", tc.nr, tc.want, got)
+		}
+	}
+}
+
+// ---------------------------------------------------------------------------
+// procProvider
+// ---------------------------------------------------------------------------
+
+func TestProcProvider_tableContainsCurrentProcess(t *testing.T) {
+	table := newProvider().ProcessTable()
+	pid := strconv.Itoa(os.Getpid())
+	entry, ok := table[pid]
+	if !ok {
+		t.Fatalf("expected own pid %s in process table", pid)
+	}
+	if entry.PPID != strconv.Itoa(os.Getppid()) {
+		t.Errorf("PPID: expected %d, got %s", os.Getppid(), entry.PPID)
+	}
+}
+
+func TestProcProvider_cwdOfCurrentProcess(t *testing.T) {
+	wd, _ := os.Getwd()
+	if got := newProvider().ProcessCWD(strconv.Itoa(os.Getpid())); got != wd {
+		t.Errorf("expected %q, got %q", wd, got)
+	}
+}
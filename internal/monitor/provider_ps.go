@@ -0,0 +1,61 @@
+//go:build !linux && !windows
+
+package monitor
+
+import (
+	"os/exec"
+	"strconv"
+	"strings"
+)
+
+// psProvider collects process information by running ps and lsof.
+type psProvider struct{}
+
+func newProvider() ProcessProvider {
+	return psProvider{}
+}
+
+// ProcessTable runs ps once and returns a full process table.
+func (psProvider) ProcessTable() ProcessTable {
+	cmd := exec.Command("ps", "-eo", "pid,ppid,tty,%cpu,%mem,args")
+	out, err := cmd.Output()
+	if err != nil {
+		return ProcessTable{}
+	}
+
+	table := make(ProcessTable)
+	lines := strings.Split(string(out), "\n")
+	for _, line := range lines[1:] { // skip header
+		fields := strings.Fields(line)
+		if len(fields) < 6 {
+			continue
+		}
+		cpu, _ := strconv.ParseFloat(fields[3], 64)
+		mem, _ := strconv.ParseFloat(fields[4], 64)
+		entry := ProcessTableEntry{
+			PID:  fields[0],
+			PPID: fields[1],
+			TTY:  fields[2],
+			CPU:  cpu,
+			Mem:  mem,
+			Args: strings.Join(fields[5:], " "),
+		}
+		table[entry.PID] = entry
+	}
+	return table
+}
+
+// ProcessCWD gets the working directory of a process via lsof.
+func (psProvider) ProcessCWD(pid string) string {
+	cmd := exec.Command("lsof", "-a", "-p", pid, "-d", "cwd", "-Fn")
+	out, err := cmd.Output()
+	if err != nil {
+		return ""
+	}
+	for _, line := range strings.Split(string(out), "\n") {
+		if strings.HasPrefix(line, "n/") {
+			return line[1:]
+		}
+	}
+	return ""
+}
@@ -0,0 +1,178 @@
+package monitor
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/fsnotify/fsnotify"
+)
+
+// ChangeMsg is sent when the watcher sees a change worth refreshing for.
+type ChangeMsg struct{}
+
+const (
+	// debounceDelay coalesces bursts of writes (a streaming reply appends to
+	// the JSONL file many times a second) into one refresh.
+	debounceDelay = 250 * time.Millisecond
+
+	// settleDelay triggers one more refresh after the last event so sessions
+	// move from active to idle without any further writes.
+	settleDelay = 3 * time.Second
+
+	// waitRetryDelay is how long to back off when tmux wait-for fails,
+	// e.g. because no tmux server is running yet.
+	waitRetryDelay = 5 * time.Second
+)
+
+// WatchFallbackInterval is the slow safety refresh used in watch mode, so
+// uptime and CPU columns still move while nothing else happens.
+const WatchFallbackInterval = 30 * time.Second
+
+// Watcher turns conversation log writes and tmux hook signals into refresh
+// events. Events are coalesced: a slow consumer sees at most one pending event.
+type Watcher struct {
+	events  chan struct{}
+	signals chan struct{} // tmux hook signals, debounced like writes
+	fs      *fsnotify.Watcher
+	cancel  context.CancelFunc
+	wg      sync.WaitGroup
+}
+
+// NewWatcher watches root and its immediate subdirectories (one per Claude
+// project) for writes. If waitFor is non-nil it is called in a loop and every
+// successful return counts as an event; it should block until tmux signals.
+func NewWatcher(root string, waitFor func(context.Context) error) (*Watcher, error) {
+	fsw, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, err
+	}
+	if err := fsw.Add(root); err != nil {
+		fsw.Close()
+		return nil, err
+	}
+	if entries, err := os.ReadDir(root); err == nil {
+		for _, e := range entries {
+			if e.IsDir() {
+				_ = fsw.Add(filepath.Join(root, e.Name()))
+			}
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	w := &Watcher{
+		events:  make(chan struct{}, 1),
+		signals: make(chan struct{}, 1),
+		fs:      fsw,
+		cancel:  cancel,
+	}
+
+	w.wg.Add(1)
+	go w.loop(ctx, root)
+	if waitFor != nil {
+		w.wg.Add(1)
+		go w.waitLoop(ctx, waitFor)
+	}
+	return w, nil
+}
+
+// Events returns the channel that receives a value per coalesced change.
+func (w *Watcher) Events() <-chan struct{} {
+	return w.events
+}
+
+// Close stops watching and waits for background goroutines to exit. The
+// events channel is closed so a pending WaitCmd returns.
+func (w *Watcher) Close() error {
+	w.cancel()
+	err := w.fs.Close()
+	w.wg.Wait()
+	close(w.events)
+	return err
+}
+
+// notify queues an event unless one is already pending.
+func (w *Watcher) notify() {
+	select {
+	case w.events <- struct{}{}:
+	default:
+	}
+}
+
+// loop debounces filesystem events and tmux signals, and schedules the
+// settle refresh.
+func (w *Watcher) loop(ctx context.Context, root string) {
+	defer w.wg.Done()
+
+	debounce := time.NewTimer(time.Hour)
+	debounce.Stop()
+	settle := time.NewTimer(time.Hour)
+	settle.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case ev, ok := <-w.fs.Events:
+			if !ok {
+				return
+			}
+			// New project directory: start watching it too.
+			if ev.Has(fsnotify.Create) && filepath.Dir(ev.Name) == root {
+				if info, err := os.Stat(ev.Name); err == nil && info.IsDir() {
+					_ = w.fs.Add(ev.Name)
+				}
+			}
+			debounce.Reset(debounceDelay)
+		case _, ok := <-w.fs.Errors:
+			if !ok {
+				return
+			}
+		case <-w.signals:
+			debounce.Reset(debounceDelay)
+		case <-debounce.C:
+			w.notify()
+			settle.Reset(settleDelay)
+		case <-settle.C:
+			w.notify()
+		}
+	}
+}
+
+// waitLoop passes each return of waitFor on to loop, which debounces it
+// like a write: tmux can signal many times a second.
+func (w *Watcher) waitLoop(ctx context.Context, waitFor func(context.Context) error) {
+	defer w.wg.Done()
+	for {
+		err := waitFor(ctx)
+		if ctx.Err() != nil {
+			return
+		}
+		if err != nil {
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(waitRetryDelay):
+			}
+			continue
+		}
+		select {
+		case w.signals <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// WaitCmd returns a command that blocks until the watcher reports a change.
+// Re-issue it after handling each ChangeMsg.
+func WaitCmd(w *Watcher) tea.Cmd {
+	return func() tea.Msg {
+		if _, ok := <-w.Events(); !ok {
+			return nil
+		}
+		return ChangeMsg{}
+	}
+}
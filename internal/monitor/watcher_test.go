@@ -0,0 +1,34 @@
+package monitor
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestWatcher_debouncesTmuxSignals(t *testing.T) {
+	signalled := false
+	w, err := NewWatcher(t.TempDir(), func(ctx context.Context) error {
+		if !signalled {
+			signalled = true
+			return nil
+		}
+		<-ctx.Done()
+		return ctx.Err()
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w.Close()
+
+	select {
+	case <-w.Events():
+		t.Fatal("expected the signal to wait out the debounce")
+	case <-time.After(debounceDelay / 2):
+	}
+	select {
+	case <-w.Events():
+	case <-time.After(2 * time.Second):
+		t.Fatal("expected an event once the debounce passed")
+	}
+}
@@ -0,0 +1,100 @@
+package projects
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// Match returns the dirs matching query, best first, at most limit of them
+// (limit <= 0 means all). A dir matches when the characters of query appear
+// in it in order, ignoring case. Runs of consecutive characters and matches
+// at the start of a path component or in the last component rank higher;
+// ties keep the order of dirs. A query starting with home is matched as
+// ~/..., like the dirs under home, so either spelling works. An empty query
+// matches every dir.
+func Match(query string, dirs []string, home string, limit int) []string {
+	query = strings.ToLower(tildePath(strings.TrimSpace(query), home))
+	type scored struct {
+		dir   string
+		score int
+	}
+	var matches []scored
+	for _, dir := range dirs {
+		if score, ok := matchScore(query, strings.ToLower(tildePath(dir, home))); ok {
+			matches = append(matches, scored{dir, score})
+		}
+	}
+	sort.SliceStable(matches, func(i, j int) bool { return matches[i].score > matches[j].score })
+	if limit > 0 && len(matches) > limit {
+		matches = matches[:limit]
+	}
+	out := make([]string, len(matches))
+	for i, m := range matches {
+		out[i] = m.dir
+	}
+	return out
+}
+
+// matchScore reports whether query is a subsequence of target, and how well
+// it matches. Both are lower case. Each place the first character occurs is
+// tried as the start of the match, and the best is kept.
+func matchScore(query, target string) (int, bool) {
+	if query == "" {
+		return 0, true
+	}
+	q := []rune(query)
+	t := []rune(target)
+	base := len([]rune(target[:strings.LastIndexAny(target, `/\`)+1])) // start of the last component
+
+	best, found := 0, false
+	for start := range t {
+		if t[start] != q[0] {
+			continue
+		}
+		if score, ok := scoreFrom(q, t, start, base); ok && (!found || score > best) {
+			best, found = score, true
+		}
+	}
+	return best, found
+}
+
+// scoreFrom matches q in t greedily from start.
+func scoreFrom(q, t []rune, start, base int) (int, bool) {
+	score, qi := 0, 0
+	prev := -2
+	for ti := start; ti < len(t) && qi < len(q); ti++ {
+		if t[ti] != q[qi] {
+			continue
+		}
+		score++
+		if prev == ti-1 {
+			score += 4 // consecutive
+		}
+		if ti == 0 || isSeparator(t[ti-1]) {
+			score += 3 // start of a component or word
+		}
+		if ti >= base {
+			score += 2 // in the last component
+		}
+		prev = ti
+		qi++
+	}
+	return score, qi == len(q)
+}
+
+func isSeparator(r rune) bool {
+	return r == '/' || r == '\\' || r == '-' || r == '_' || r == '.' || unicode.IsSpace(r)
+}
+
+// tildePath replaces a leading home with ~.
+func tildePath(path, home string) string {
+	if home == "" || !strings.HasPrefix(path, home) {
+		return path
+	}
+	rest := path[len(home):]
+	if rest != "" && rest[0] != '/' && rest[0] != '\\' {
+		return path
+	}
+	return "~" + rest
+}
@@ -0,0 +1,97 @@
+// Package projects finds the directories sessions are likely to be created
+// in: git repositories under the configured project roots and recently used
+// session directories. It also ranks them against what has been typed.
+package projects
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// maxDepth is how far below a root repositories are looked for, e.g.
+// ~/src/org/repo is two levels below ~/src.
+const maxDepth = 2
+
+// skipDirs are directories never worth descending into.
+var skipDirs = map[string]bool{"node_modules": true, "vendor": true}
+
+// Discover returns recent (most recent first) followed by the git
+// repositories under roots, without duplicates and without directories
+// that no longer exist. Roots may start with ~/; ones that cannot be read
+// are skipped.
+func Discover(roots, recent []string) []string {
+	var out []string
+	seen := make(map[string]bool)
+	add := func(dir string) {
+		dir = filepath.Clean(dir)
+		if seen[dir] {
+			return
+		}
+		seen[dir] = true
+		out = append(out, dir)
+	}
+	for _, dir := range recent {
+		if dir != "" && isDir(dir) {
+			add(dir)
+		}
+	}
+	var repos []string
+	for _, root := range roots {
+		repos = append(repos, findRepos(ExpandHome(root), maxDepth)...)
+	}
+	sort.Strings(repos)
+	for _, dir := range repos {
+		add(dir)
+	}
+	return out
+}
+
+// findRepos returns dir if it is a git repository, otherwise the
+// repositories up to depth levels below it. Hidden directories are skipped
+// and repositories are not searched for nested ones.
+func findRepos(dir string, depth int) []string {
+	if isRepo(dir) {
+		return []string{dir}
+	}
+	if depth == 0 {
+		return nil
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil
+	}
+	var repos []string
+	for _, e := range entries {
+		if !e.IsDir() || strings.HasPrefix(e.Name(), ".") || skipDirs[e.Name()] {
+			continue
+		}
+		repos = append(repos, findRepos(filepath.Join(dir, e.Name()), depth-1)...)
+	}
+	return repos
+}
+
+// isRepo reports whether dir is the top of a git working tree; .git is a
+// file in worktrees and submodules.
+func isRepo(dir string) bool {
+	_, err := os.Stat(filepath.Join(dir, ".git"))
+	return err == nil
+}
+
+func isDir(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && info.IsDir()
+}
+
+// ExpandHome replaces a leading ~ with the home directory.
+func ExpandHome(path string) string {
+	if path != "~" && !strings.HasPrefix(path, "~/") {
+		return path
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return path
+	}
+	return filepath.Join(home, path[1:])
+}
@@ -0,0 +1,67 @@
+package projects
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// ---------------------------------------------------------------------------
+// Discover
+// ---------------------------------------------------------------------------
+
+func TestDiscover_reposUnderRootsAfterRecent(t *testing.T) {
+	root := t.TempDir()
+	mkdir := func(parts ...string) string {
+		dir := filepath.Join(append([]string{root}, parts...)...)
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatal(err)
+		}
+		return dir
+	}
+	api := filepath.Dir(mkdir("api", ".git"))
+	web := filepath.Dir(mkdir("org", "web", ".git"))
+	mkdir("api", "nested", ".git")            // inside a repository
+	mkdir("org", "team", "deep", "x", ".git") // too deep
+	mkdir(".cache", "repo", ".git")           // hidden
+	notes := mkdir("notes")
+
+	got := Discover([]string{root, filepath.Join(root, "missing")}, []string{notes, web, filepath.Join(root, "gone")})
+	want := []string{notes, web, api}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+}
+
+// ---------------------------------------------------------------------------
+// Match
+// ---------------------------------------------------------------------------
+
+func TestMatch_ranksLastComponentAndRuns(t *testing.T) {
+	dirs := []string{"/home/me/apps/pilot", "/home/me/work/api", "/home/me/work/web"}
+	got := Match("api", dirs, "/home/me", 0)
+	want := []string{"/home/me/work/api", "/home/me/apps/pilot"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+}
+
+func TestMatch_homeSpellingsAndCase(t *testing.T) {
+	dirs := []string{"/home/me/work/API", "/srv/api"}
+	for _, q := range []string{"~/work/api", "/home/me/work/api", "~/WoRk"} {
+		if got := Match(q, dirs, "/home/me", 0); len(got) != 1 || got[0] != "/home/me/work/API" {
+			t.Errorf("query %q: expected only ~/work/API, got %v", q, got)
+		}
+	}
+}
+
+func TestMatch_emptyQueryKeepsOrderAndLimit(t *testing.T) {
+	dirs := []string{"/c", "/a", "/b"}
+	if got := Match("", dirs, "", 2); !reflect.DeepEqual(got, []string{"/c", "/a"}) {
+		t.Errorf("expected the first two in order, got %v", got)
+	}
+	if got := Match("zzz", dirs, "", 0); len(got) != 0 {
+		t.Errorf("expected no matches, got %v", got)
+	}
+}
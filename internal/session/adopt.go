@@ -0,0 +1,119 @@
+package session
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/seunggabi/claude-dashboard/internal/monitor"
+)
+
+// adoptGrace is how long claude in a terminal gets to exit when its session
+// is adopted.
+const adoptGrace = 10 * time.Second
+
+// adoptFlags are the claude flags an adopted session keeps, and whether
+// each takes a value. Others, such as a prompt or --continue, would not
+// mean the same when resuming.
+var adoptFlags = map[string]bool{
+	"--model":                        true,
+	"--permission-mode":              true,
+	"--add-dir":                      true,
+	"--dangerously-skip-permissions": false,
+	"--verbose":                      false,
+}
+
+// Adopt moves claude running in the terminal session s into a new tmux
+// session called name (without the prefix). claude in the terminal is
+// stopped first, so two never write the conversation at once, and the
+// conversation is then resumed in tmux in the same directory, with the
+// model and permission flags claude was started with.
+func (m *Manager) Adopt(ctx context.Context, s Session, name string) error {
+	switch {
+	case s.Managed:
+		return fmt.Errorf("%s is already a tmux session", s.Name)
+	case s.Host != "":
+		return fmt.Errorf("only terminal sessions on this machine can be adopted")
+	case s.PID == "" || s.Path == "":
+		return fmt.Errorf("no claude process or directory found for %s", s.Name)
+	}
+	if err := m.noTmux(); err != nil {
+		return err
+	}
+	if m.client.HasSession(ctx, SessionPrefix+name) {
+		return fmt.Errorf("session %s already exists", SessionPrefix+name)
+	}
+	id := s.Conversation
+	if id == "" {
+		log, err := s.Log()
+		if err != nil {
+			return fmt.Errorf("no conversation of %s to resume: %w", s.Name, err)
+		}
+		id = strings.TrimSuffix(filepath.Base(log), ".jsonl")
+	}
+	if !conversationID.MatchString(id) {
+		return fmt.Errorf("no conversation of %s to resume", s.Name)
+	}
+	args := strings.TrimSpace(adoptArgs(monitor.GetProcessTable()[s.PID].Args) + " --resume " + id)
+	if validateClaudeArgs(args) != nil {
+		args = "--resume " + id
+	}
+	if err := stopProcess(ctx, s.PID); err != nil {
+		return err
+	}
+	return m.Create(ctx, name, s.Path, args)
+}
+
+// adoptArgs returns the adoptFlags of a claude command line.
+func adoptArgs(cmdline string) string {
+	fields := strings.Fields(cmdline)
+	var kept []string
+	for i := 1; i < len(fields); i++ {
+		flag, _, inline := strings.Cut(fields[i], "=")
+		takesValue, ok := adoptFlags[flag]
+		switch {
+		case !ok:
+		case inline || !takesValue:
+			kept = append(kept, fields[i])
+		case i+1 < len(fields):
+			kept = append(kept, fields[i], fields[i+1])
+			i++
+		}
+	}
+	return strings.Join(kept, " ")
+}
+
+// stopProcess asks the process pid to terminate and waits up to adoptGrace
+// for it to be gone.
+func stopProcess(ctx context.Context, pid string) error {
+	n, err := strconv.Atoi(pid)
+	if err != nil {
+		return fmt.Errorf("invalid pid %q", pid)
+	}
+	p, err := os.FindProcess(n)
+	if err == nil {
+		err = p.Signal(syscall.SIGTERM)
+	}
+	if err != nil {
+		return fmt.Errorf("cannot stop claude (pid %s): %w", pid, err)
+	}
+	deadline := time.Now().Add(adoptGrace)
+	for {
+		if _, running := monitor.GetProcessTable()[pid]; !running {
+			return nil
+		}
+		if time.Now().After(deadline) {
+			return fmt.Errorf("claude (pid %s) did not exit within %s", pid, adoptGrace)
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(killPoll):
+		}
+	}
+}
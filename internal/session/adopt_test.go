@@ -0,0 +1,38 @@
+package session
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestAdoptArgs_keepsModelAndPermissionFlags(t *testing.T) {
+	tests := map[string]string{
+		"claude":                            "",
+		"claude --model opus fix the build": "--model opus",
+		"/usr/bin/claude -c --permission-mode=plan --verbose": "--permission-mode=plan --verbose",
+		"claude --resume 0b1c --dangerously-skip-permissions": "--dangerously-skip-permissions",
+		"claude --add-dir ../shared --model":                  "--add-dir ../shared",
+	}
+	for cmdline, want := range tests {
+		if got := adoptArgs(cmdline); got != want {
+			t.Errorf("%s: expected %q, got %q", cmdline, want, got)
+		}
+	}
+}
+
+func TestAdopt_onlyTerminalSessions(t *testing.T) {
+	m := NewManager(nil)
+	ctx := context.Background()
+	if err := m.Adopt(ctx, Session{Name: "cd-api", Managed: true}, "api"); err == nil || !strings.Contains(err.Error(), "already a tmux session") {
+		t.Errorf("expected a tmux session to be refused, got %v", err)
+	}
+	if err := m.Adopt(ctx, Session{Name: "terminal/pts/3", Path: "/src/api"}, "api"); err == nil {
+		t.Error("expected a session without a process to be refused")
+	}
+	s := Session{Name: "terminal/pts/3", PID: "42", Path: "/src/api"}
+	if err := m.Adopt(ctx, s, "api"); !errors.Is(err, ErrNoTmux) {
+		t.Errorf("expected ErrNoTmux, got %v", err)
+	}
+}
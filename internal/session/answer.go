@@ -0,0 +1,142 @@
+package session
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Prompt is a yes/no question a session is waiting on: claude asking
+// permission to use a tool, with a numbered menu, or a program in the pane
+// asking (y/n).
+type Prompt struct {
+	Question string // e.g. "Do you want to make this edit to app.go?"
+	Yes, No  Choice
+}
+
+// Choice is an answer to a Prompt and how it is given.
+type Choice struct {
+	Label string // as shown, e.g. "1. Yes" or "y"
+	key   string // the menu number pressed, or the text typed
+	enter bool   // typed and followed by Enter
+}
+
+// promptLines is how many non-empty lines at the bottom of a pane are
+// looked at for a prompt.
+const promptLines = 20
+
+var (
+	// menuOption matches a choice of claude's permission menu, e.g.
+	// "❯ 1. Yes", once box borders are trimmed.
+	menuOption = regexp.MustCompile(`^(?:❯\s*)?(\d)\.\s+(.+)$`)
+	// yesNo matches the answers a y/n prompt offers.
+	yesNo = regexp.MustCompile(`[(\[]\s*[yY]\s*/\s*[nN]\s*[)\]]`)
+)
+
+// ParsePrompt finds the yes/no prompt at the bottom of pane content, or
+// returns false when there is none.
+func ParsePrompt(content string) (Prompt, bool) {
+	var lines []string
+	raw := strings.Split(content, "\n")
+	for i := len(raw) - 1; i >= 0 && len(lines) < promptLines; i-- {
+		if line := trimBox(raw[i]); line != "" {
+			lines = append(lines, line) // bottom first
+		}
+	}
+
+	// claude's menu: the Yes and No choices, of which long ones may wrap,
+	// up to the question above them.
+	var p Prompt
+	options := false
+	for _, line := range lines {
+		m := menuOption.FindStringSubmatch(line)
+		if m == nil {
+			if options && strings.HasSuffix(line, "?") {
+				p.Question = line
+				break
+			}
+			continue
+		}
+		options = true
+		choice := Choice{Label: m[1] + ". " + m[2], key: m[1]}
+		switch text := strings.ToLower(m[2]); {
+		case strings.HasPrefix(text, "yes"):
+			p.Yes = choice // the topmost Yes, read last, is plain yes
+		case strings.HasPrefix(text, "no"):
+			p.No = choice
+		}
+	}
+	if p.Yes.key != "" && p.No.key != "" {
+		return p, true
+	}
+
+	// A program asking (y/n) on the last line.
+	if len(lines) > 0 && yesNo.MatchString(lines[0]) {
+		return Prompt{
+			Question: lines[0],
+			Yes:      Choice{Label: "y", key: "y", enter: true},
+			No:       Choice{Label: "n", key: "n", enter: true},
+		}, true
+	}
+	return Prompt{}, false
+}
+
+// PendingQuestion returns the question a waiting session asks at the
+// bottom of pane content: that of its prompt, or else the last line asking
+// one; "" when there is none.
+func PendingQuestion(content string) string {
+	if p, ok := ParsePrompt(content); ok && p.Question != "" {
+		return p.Question
+	}
+	lines := strings.Split(content, "\n")
+	for i := len(lines) - 1; i >= 0 && i >= len(lines)-promptLines; i-- {
+		if line := trimBox(lines[i]); strings.HasSuffix(line, "?") || yesNo.MatchString(line) {
+			return line
+		}
+	}
+	return ""
+}
+
+// trimBox trims the spaces and box borders around a pane line.
+func trimBox(line string) string {
+	return strings.TrimSpace(strings.Trim(strings.TrimSpace(line), "│┃|"))
+}
+
+// Answer approves or denies the prompt s is waiting on, without attaching:
+// it reads the prompt from the pane again and presses the choice only if
+// the pane still asks question (as PendingQuestion reads it), so a prompt
+// answered or replaced meanwhile is not answered blindly. It returns the
+// choice made.
+func (m *Manager) Answer(ctx context.Context, s Session, question string, approve bool) (Choice, error) {
+	if err := m.noTmux(); err != nil {
+		return Choice{}, err
+	}
+	if !s.Managed {
+		return Choice{}, fmt.Errorf("%s is not a tmux session; only tmux sessions can be answered", s.Name)
+	}
+	content, err := m.client.CapturePaneContent(ctx, s.Name, 0)
+	if err != nil {
+		return Choice{}, err
+	}
+	p, ok := ParsePrompt(content)
+	if !ok {
+		return Choice{}, fmt.Errorf("%s is not waiting on a yes/no prompt", s.Name)
+	}
+	if asked := PendingQuestion(content); asked != question {
+		return Choice{}, fmt.Errorf("%s now asks %q, not %q; not answered", s.Name, asked, question)
+	}
+	c := p.No
+	if approve {
+		c = p.Yes
+	}
+	if c.enter {
+		err = m.client.SendKeys(ctx, s.Name, c.key)
+	} else {
+		err = m.client.SendKey(ctx, s.Name, c.key)
+	}
+	if err != nil {
+		return Choice{}, fmt.Errorf("failed to answer %s: %w", s.Name, err)
+	}
+	return c, nil
+}
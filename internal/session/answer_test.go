@@ -0,0 +1,103 @@
+package session
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/seunggabi/claude-dashboard/internal/tmux"
+)
+
+func TestParsePrompt_claudePermissionMenu(t *testing.T) {
+	pane := `● Bash(rm -rf build)
+
+╭──────────────────────────────────────────────────────╮
+│ Bash command                                         │
+│                                                      │
+│   rm -rf build                                       │
+│                                                      │
+│ Do you want to proceed?                              │
+│ ❯ 1. Yes                                             │
+│   2. Yes, and don't ask again for rm commands in     │
+│   /src/api                                           │
+│   3. No, and tell Claude what to do differently (esc)│
+╰──────────────────────────────────────────────────────╯
+`
+	p, ok := ParsePrompt(pane)
+	if !ok {
+		t.Fatal("expected a prompt")
+	}
+	if p.Question != "Do you want to proceed?" || p.Yes.Label != "1. Yes" || p.Yes.key != "1" || p.No.key != "3" || p.Yes.enter {
+		t.Errorf("unexpected prompt %+v", p)
+	}
+}
+
+func TestParsePrompt_yesNoLine(t *testing.T) {
+	p, ok := ParsePrompt("Installing hooks\nOverwrite settings.json? (y/N) \n\n")
+	if !ok || p.Question != "Overwrite settings.json? (y/N)" || p.Yes.key != "y" || !p.No.enter {
+		t.Errorf("expected a y/n prompt, got %+v, %v", p, ok)
+	}
+}
+
+func TestParsePrompt_none(t *testing.T) {
+	for _, pane := range []string{
+		"",
+		"> \n  ? for shortcuts\n",
+		"Steps:\n1. Yes, build it\n2. Run the tests\n> \n",
+	} {
+		if p, ok := ParsePrompt(pane); ok {
+			t.Errorf("expected no prompt in %q, got %+v", pane, p)
+		}
+	}
+}
+
+func TestPendingQuestion(t *testing.T) {
+	for pane, want := range map[string]string{
+		"● Edit(app.go)\nDo you want to make this edit to app.go?\n❯ 1. Yes\n  2. No, and tell Claude what to do differently (esc)\n": "Do you want to make this edit to app.go?",
+		"Which database should the migration target?\n": "Which database should the migration target?",
+		"Continue? [Y/n]\n":                             "Continue? [Y/n]",
+		"> \n":                                          "",
+	} {
+		if got := PendingQuestion(pane); got != want {
+			t.Errorf("PendingQuestion(%q) = %q, want %q", pane, got, want)
+		}
+	}
+}
+
+func TestAnswer_refusesAChangedQuestion(t *testing.T) {
+	client, err := tmux.NewSocketClient(fmt.Sprintf("cd-answer-test-%d", os.Getpid()))
+	if err != nil {
+		t.Skip("tmux not installed")
+	}
+	t.Setenv("HOME", t.TempDir())
+	ctx := context.Background()
+	// The prompt confirmed was for a.go; by the time it is answered, claude
+	// asks about b.go.
+	pane := `printf 'Do you want to make this edit to b.go?\n  1. Yes\n  2. No\n'; exec cat`
+	if err := client.NewSession(ctx, "cd-api", t.TempDir(), pane); err != nil {
+		t.Skipf("cannot start a tmux server: %v", err)
+	}
+	defer func() { _ = client.KillServer(ctx) }()
+	for deadline := time.Now().Add(5 * time.Second); time.Now().Before(deadline); time.Sleep(20 * time.Millisecond) {
+		if content, _ := client.CapturePaneContent(ctx, "cd-api", 0); strings.Contains(content, "2. No") {
+			break
+		}
+	}
+
+	m := NewManager(client)
+	s := Session{Name: "cd-api", Managed: true}
+	if _, err := m.Answer(ctx, s, "Do you want to make this edit to a.go?", true); err == nil {
+		t.Fatal("expected a changed question not to be answered")
+	}
+	time.Sleep(100 * time.Millisecond)
+	content, _ := client.CapturePaneContent(ctx, "cd-api", 0)
+	if !strings.HasSuffix(strings.TrimSpace(content), "2. No") {
+		t.Errorf("expected nothing typed into the pane, got %q", content)
+	}
+	if _, err := m.Answer(ctx, s, "Do you want to make this edit to b.go?", true); err != nil {
+		t.Errorf("expected the question still asked to be answered, got %v", err)
+	}
+}
@@ -0,0 +1,87 @@
+package session
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/seunggabi/claude-dashboard/internal/archive"
+	"github.com/seunggabi/claude-dashboard/internal/conversation"
+)
+
+// archivePaneLines is how much pane history an archive keeps.
+const archivePaneLines = 10000
+
+// DueForArchive returns the local tmux sessions in sessions that have been
+// idle for longer than after at now. Sessions someone is attached to are
+// left alone.
+func DueForArchive(sessions []Session, after time.Duration, now time.Time) []Session {
+	var due []Session
+	for _, s := range sessions {
+		if s.Host == "" && s.Managed && !s.Attached && s.Status == StatusIdle && !s.Activity.IsZero() && now.Sub(s.Activity) > after {
+			due = append(due, s)
+		}
+	}
+	return due
+}
+
+// Archive saves the pane history and current conversation log of s under
+// root (see archive.Save) and then kills it.
+func (m *Manager) Archive(ctx context.Context, s Session, root string, now time.Time) (archive.Entry, error) {
+	if err := m.noTmux(); err != nil {
+		return archive.Entry{}, err
+	}
+	pane, err := m.GetLogs(ctx, s.Name, archivePaneLines)
+	if err != nil {
+		return archive.Entry{}, fmt.Errorf("failed to capture session %s: %w", s.Name, err)
+	}
+	e := archive.Entry{Name: s.Name, Project: s.Project, Path: s.Path, Args: m.definitionArgs(s.Name), Archived: now}
+	if !s.Activity.IsZero() {
+		e.Idle = now.Sub(s.Activity).Round(time.Minute)
+	}
+	var logPath string
+	if s.Path != "" {
+		logPath, _ = s.Log()
+	}
+	e, err = archive.Save(root, e, pane, logPath)
+	if err != nil {
+		return e, fmt.Errorf("failed to archive session %s: %w", s.Name, err)
+	}
+	return e, m.Kill(ctx, s.Name)
+}
+
+// ArchiveMany archives each session, carrying on past failures.
+func (m *Manager) ArchiveMany(ctx context.Context, sessions []Session, root string, now time.Time) BulkResult {
+	r := BulkResult{Op: "Archived"}
+	for _, s := range sessions {
+		_, err := m.Archive(ctx, s, root, now)
+		r.Add(s.Name, err)
+	}
+	return r
+}
+
+// Unarchive recreates an archived session in its directory with its
+// arguments, resuming the saved conversation. The log is put back where
+// claude looks for it if it has gone from there.
+func (m *Manager) Unarchive(ctx context.Context, e archive.Entry) error {
+	args := e.Args
+	if e.Conversation != "" && !resumes(args) {
+		if err := e.PutBackConversation(conversation.LogDir(e.Path)); err != nil {
+			return fmt.Errorf("failed to restore the conversation of %s: %w", e.Name, err)
+		}
+		args = strings.TrimSpace(args + " --resume " + e.Conversation)
+	}
+	return m.Create(ctx, strings.TrimPrefix(e.Name, SessionPrefix), e.Path, args)
+}
+
+// resumes reports whether claude args already pick a conversation.
+func resumes(args string) bool {
+	for _, f := range strings.Fields(args) {
+		switch f {
+		case "-r", "--resume", "-c", "--continue":
+			return true
+		}
+	}
+	return false
+}
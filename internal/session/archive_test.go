@@ -0,0 +1,43 @@
+package session
+
+import (
+	"testing"
+	"time"
+)
+
+// ---------------------------------------------------------------------------
+// Archive
+// ---------------------------------------------------------------------------
+
+func TestDueForArchive_onlyLocalTmuxSessionsIdleLongEnough(t *testing.T) {
+	now := time.Now()
+	long := now.Add(-9 * time.Hour)
+	sessions := []Session{
+		{Name: "cd-old", Managed: true, Status: StatusIdle, Activity: long},
+		{Name: "cd-attached", Managed: true, Status: StatusIdle, Activity: long, Attached: true},
+		{Name: "cd-recent", Managed: true, Status: StatusIdle, Activity: now.Add(-time.Hour)},
+		{Name: "cd-waiting", Managed: true, Status: StatusWaiting, Activity: long},
+		{Name: "cd-remote", Managed: true, Status: StatusIdle, Activity: long, Host: "devbox"},
+		{Name: "terminal", Status: StatusIdle, Activity: long},
+		{Name: "cd-unknown", Managed: true, Status: StatusIdle},
+	}
+	due := DueForArchive(sessions, 8*time.Hour, now)
+	if len(due) != 1 || due[0].Name != "cd-old" {
+		t.Errorf("expected only cd-old, got %+v", due)
+	}
+}
+
+func TestResumes(t *testing.T) {
+	cases := map[string]bool{
+		"":                   false,
+		"--model opus":       false,
+		"-c":                 true,
+		"--resume abc":       true,
+		"--model opus -r 12": true,
+	}
+	for args, want := range cases {
+		if got := resumes(args); got != want {
+			t.Errorf("resumes(%q) = %v, want %v", args, got, want)
+		}
+	}
+}
@@ -0,0 +1,94 @@
+package session
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// BulkItem is the outcome of a bulk operation for one session.
+type BulkItem struct {
+	Name string
+	Err  error
+}
+
+// BulkResult collects the per-session outcomes of an operation applied to
+// several sessions, so partial failures can be reported item by item.
+type BulkResult struct {
+	Op    string // past-tense verb for summaries, e.g. "Killed"
+	Items []BulkItem
+}
+
+// Add records the outcome for name.
+func (r *BulkResult) Add(name string, err error) {
+	r.Items = append(r.Items, BulkItem{Name: name, Err: err})
+}
+
+// Succeeded returns the names of the sessions the operation succeeded for.
+func (r BulkResult) Succeeded() []string {
+	var names []string
+	for _, it := range r.Items {
+		if it.Err == nil {
+			names = append(names, it.Name)
+		}
+	}
+	return names
+}
+
+// Failed returns the items the operation failed for.
+func (r BulkResult) Failed() []BulkItem {
+	var failed []BulkItem
+	for _, it := range r.Items {
+		if it.Err != nil {
+			failed = append(failed, it)
+		}
+	}
+	return failed
+}
+
+// Err joins the errors of the failed items; nil when all succeeded.
+func (r BulkResult) Err() error {
+	var errs []error
+	for _, it := range r.Failed() {
+		errs = append(errs, it.Err)
+	}
+	return errors.Join(errs...)
+}
+
+// Summary returns a one-line account, e.g. "Killed 2 of 3 sessions".
+func (r BulkResult) Summary() string {
+	return fmt.Sprintf("%s %d of %d session(s)", r.Op, len(r.Succeeded()), len(r.Items))
+}
+
+// KillMany kills each named session, carrying on past failures: with
+// force right away (ForceKill), otherwise letting claude exit first (Kill).
+func (m *Manager) KillMany(ctx context.Context, names []string, force bool) BulkResult {
+	kill := m.Kill
+	if force {
+		kill = m.ForceKill
+	}
+	r := BulkResult{Op: "Killed"}
+	for _, name := range names {
+		r.Add(name, kill(ctx, name))
+	}
+	return r
+}
+
+// SendMany sends the same prompt to each named session.
+func (m *Manager) SendMany(ctx context.Context, names []string, text string) BulkResult {
+	r := BulkResult{Op: "Sent to"}
+	for _, name := range names {
+		r.Add(name, m.SendCommand(ctx, name, text))
+	}
+	return r
+}
+
+// CreateMany creates a session for each definition. Items are named with
+// SessionPrefix, like the tmux sessions they create.
+func (m *Manager) CreateMany(ctx context.Context, defs []Definition) BulkResult {
+	r := BulkResult{Op: "Created"}
+	for _, d := range defs {
+		r.Add(SessionPrefix+d.Name, m.Create(ctx, d.Name, d.Path, d.Args))
+	}
+	return r
+}
@@ -0,0 +1,63 @@
+package session
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+// ---------------------------------------------------------------------------
+// BulkResult
+// ---------------------------------------------------------------------------
+
+func TestBulkResult_splitsSucceededAndFailed(t *testing.T) {
+	r := BulkResult{Op: "Killed"}
+	r.Add("cd-a", nil)
+	r.Add("cd-b", errors.New("boom"))
+	r.Add("cd-c", nil)
+
+	if got := r.Succeeded(); len(got) != 2 || got[0] != "cd-a" || got[1] != "cd-c" {
+		t.Errorf("unexpected succeeded: %v", got)
+	}
+	if got := r.Failed(); len(got) != 1 || got[0].Name != "cd-b" {
+		t.Errorf("unexpected failed: %+v", got)
+	}
+	if r.Err() == nil || !strings.Contains(r.Err().Error(), "boom") {
+		t.Errorf("expected joined error, got %v", r.Err())
+	}
+	if got := r.Summary(); got != "Killed 2 of 3 session(s)" {
+		t.Errorf("unexpected summary %q", got)
+	}
+}
+
+func TestBulkResult_errIsNilWhenAllSucceed(t *testing.T) {
+	r := BulkResult{}
+	r.Add("cd-a", nil)
+	if err := r.Err(); err != nil {
+		t.Errorf("expected nil error, got %v", err)
+	}
+}
+
+func TestManager_bulkOperationsReportEveryItem(t *testing.T) {
+	m := NewManager(nil)
+	ctx := context.Background()
+
+	kill := m.KillMany(ctx, []string{"cd-a", "cd-b"}, false)
+	force := m.KillMany(ctx, []string{"cd-a", "cd-b"}, true)
+	send := m.SendMany(ctx, []string{"cd-a", "cd-b"}, "hi")
+	create := m.CreateMany(ctx, []Definition{{Name: "a"}, {Name: "b"}})
+	for _, r := range []BulkResult{kill, force, send, create} {
+		if len(r.Items) != 2 || len(r.Failed()) != 2 {
+			t.Errorf("%s: expected two failed items, got %+v", r.Op, r.Items)
+		}
+		for _, it := range r.Items {
+			if !errors.Is(it.Err, ErrNoTmux) {
+				t.Errorf("%s %s: expected ErrNoTmux, got %v", r.Op, it.Name, it.Err)
+			}
+		}
+	}
+	if create.Items[0].Name != "cd-a" {
+		t.Errorf("expected created items named with the prefix, got %q", create.Items[0].Name)
+	}
+}
@@ -0,0 +1,68 @@
+package session
+
+import "sort"
+
+// Edits are the uncommitted files one session wrote in a repository.
+type Edits struct {
+	Session string   // session name
+	Repo    string   // common git directory; the worktrees of a repository share it
+	Files   []string // paths relative to the working tree's top level
+}
+
+// Conflict lists files a session wrote that other sessions of the same
+// repository also have uncommitted edits to.
+type Conflict struct {
+	With  []string // the other sessions, sorted
+	Files []string // sorted
+}
+
+// IsZero reports whether there is no conflict.
+func (c Conflict) IsZero() bool {
+	return len(c.Files) == 0
+}
+
+// Conflicts finds the files each session wrote that another session of the
+// same repository wrote too, by session name. Sessions sharing a working
+// tree overwrite each other's edits; sessions in different worktrees will
+// collide when their branches merge.
+func Conflicts(edits []Edits) map[string]Conflict {
+	// writers[repo][file] are the sessions that wrote file.
+	writers := make(map[string]map[string][]string)
+	for _, e := range edits {
+		files := writers[e.Repo]
+		if files == nil {
+			files = make(map[string][]string)
+			writers[e.Repo] = files
+		}
+		for _, f := range e.Files {
+			files[f] = append(files[f], e.Session)
+		}
+	}
+
+	conflicts := make(map[string]Conflict)
+	for _, e := range edits {
+		with := make(map[string]bool)
+		var c Conflict
+		for _, f := range e.Files {
+			shared := false
+			for _, other := range writers[e.Repo][f] {
+				if other != e.Session {
+					with[other], shared = true, true
+				}
+			}
+			if shared {
+				c.Files = append(c.Files, f)
+			}
+		}
+		if c.IsZero() {
+			continue
+		}
+		for name := range with {
+			c.With = append(c.With, name)
+		}
+		sort.Strings(c.With)
+		sort.Strings(c.Files)
+		conflicts[e.Session] = c
+	}
+	return conflicts
+}
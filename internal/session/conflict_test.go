@@ -0,0 +1,42 @@
+package session
+
+import (
+	"strings"
+	"testing"
+)
+
+// ---------------------------------------------------------------------------
+// Conflicts
+// ---------------------------------------------------------------------------
+
+func TestConflicts_sharedFilesOfTheSameRepository(t *testing.T) {
+	conflicts := Conflicts([]Edits{
+		{Session: "cd-a", Repo: "/r/.git", Files: []string{"main.go", "a.go"}},
+		{Session: "cd-b", Repo: "/r/.git", Files: []string{"main.go", "b.go"}},
+		{Session: "cd-c", Repo: "/r/.git", Files: []string{"c.go", "main.go", "a.go"}},
+		{Session: "cd-other", Repo: "/other/.git", Files: []string{"main.go"}},
+	})
+	a := conflicts["cd-a"]
+	if got := strings.Join(a.With, ","); got != "cd-b,cd-c" {
+		t.Errorf("expected cd-a to conflict with cd-b and cd-c, got %s", got)
+	}
+	if got := strings.Join(a.Files, ","); got != "a.go,main.go" {
+		t.Errorf("expected a.go and main.go, got %s", got)
+	}
+	if got := strings.Join(conflicts["cd-b"].Files, ","); got != "main.go" {
+		t.Errorf("expected cd-b to conflict on main.go, got %s", got)
+	}
+	if _, ok := conflicts["cd-other"]; ok {
+		t.Errorf("expected no conflict for another repository, got %+v", conflicts["cd-other"])
+	}
+}
+
+func TestConflicts_noneForDisjointFiles(t *testing.T) {
+	conflicts := Conflicts([]Edits{
+		{Session: "cd-a", Repo: "/r/.git", Files: []string{"a.go"}},
+		{Session: "cd-b", Repo: "/r/.git", Files: []string{"b.go"}},
+	})
+	if len(conflicts) != 0 {
+		t.Errorf("expected no conflicts, got %+v", conflicts)
+	}
+}
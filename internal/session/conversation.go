@@ -0,0 +1,70 @@
+package session
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/seunggabi/claude-dashboard/internal/conversation"
+	"github.com/seunggabi/claude-dashboard/internal/monitor"
+	"github.com/seunggabi/claude-dashboard/internal/tmux"
+)
+
+// Log returns the conversation log of the session: the log of its claude
+// session ID when that is known and has been written, otherwise the latest
+// log of its directory.
+func (s *Session) Log() (string, error) {
+	return conversation.SessionLog(s.Path, s.Conversation)
+}
+
+// conversationID matches the UUIDs claude names conversations by.
+var conversationID = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+
+// conversationFromArgs returns the conversation ID a claude command line
+// names with --resume, -r or --session-id, or "" if it names none. A
+// --resume search term is not an ID.
+func conversationFromArgs(args string) string {
+	fields := strings.Fields(args)
+	for i, f := range fields {
+		value := ""
+		switch {
+		case f == "--resume" || f == "-r" || f == "--session-id":
+			if i+1 < len(fields) {
+				value = fields[i+1]
+			}
+		case strings.HasPrefix(f, "--resume="):
+			value = strings.TrimPrefix(f, "--resume=")
+		case strings.HasPrefix(f, "--session-id="):
+			value = strings.TrimPrefix(f, "--session-id=")
+		}
+		if conversationID.MatchString(value) {
+			return value
+		}
+	}
+	return ""
+}
+
+// claudeConversation returns the conversation ID on the command line of the
+// claude process pid runs, pid itself or a descendant, or "" if none has
+// one.
+func claudeConversation(pid string, table monitor.ProcessTable, procChildren map[string][]tmux.ProcEntry) string {
+	if pid == "" {
+		return ""
+	}
+	queue := []tmux.ProcEntry{{PID: pid, Args: table[pid].Args}}
+	visited := make(map[string]bool)
+	for len(queue) > 0 {
+		p := queue[0]
+		queue = queue[1:]
+		if visited[p.PID] {
+			continue
+		}
+		visited[p.PID] = true
+		if strings.Contains(strings.ToLower(p.Args), "claude") {
+			if id := conversationFromArgs(p.Args); id != "" {
+				return id
+			}
+		}
+		queue = append(queue, procChildren[p.PID]...)
+	}
+	return ""
+}
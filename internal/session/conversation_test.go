@@ -0,0 +1,58 @@
+package session
+
+import (
+	"testing"
+
+	"github.com/seunggabi/claude-dashboard/internal/monitor"
+	"github.com/seunggabi/claude-dashboard/internal/tmux"
+)
+
+const testConversation = "6f1c2a9e-0d4b-4e8a-9c3f-2b7d5e1a8c40"
+
+// ---------------------------------------------------------------------------
+// conversationFromArgs
+// ---------------------------------------------------------------------------
+
+func TestConversationFromArgs(t *testing.T) {
+	cases := map[string]string{
+		"claude --resume " + testConversation:                      testConversation,
+		"claude -r " + testConversation + " --model opus":          testConversation,
+		"claude --session-id=" + testConversation:                  testConversation,
+		"node /usr/lib/claude/cli.js --resume=" + testConversation: testConversation,
+		"claude --resume fix the login bug":                        "",
+		"claude -r":                                                "",
+		"claude --model opus":                                      "",
+	}
+	for args, want := range cases {
+		if got := conversationFromArgs(args); got != want {
+			t.Errorf("%q: expected %q, got %q", args, want, got)
+		}
+	}
+}
+
+// ---------------------------------------------------------------------------
+// claudeConversation
+// ---------------------------------------------------------------------------
+
+func TestClaudeConversation_findsClaudeBelowTheShell(t *testing.T) {
+	table := monitor.ProcessTable{
+		"10": {PID: "10", PPID: "1", Args: "-zsh"},
+		"11": {PID: "11", PPID: "10", Args: "claude --resume " + testConversation},
+	}
+	children := map[string][]tmux.ProcEntry{"10": {{PID: "11", Args: table["11"].Args}}}
+	if got := claudeConversation("10", table, children); got != testConversation {
+		t.Errorf("expected the ID claude was resumed with, got %q", got)
+	}
+	if got := claudeConversation("", table, children); got != "" {
+		t.Errorf("expected nothing without a pid, got %q", got)
+	}
+}
+
+func TestClaudeConversation_ignoresOtherProcesses(t *testing.T) {
+	table := monitor.ProcessTable{
+		"10": {PID: "10", PPID: "1", Args: "vim --session-id " + testConversation},
+	}
+	if got := claudeConversation("10", table, nil); got != "" {
+		t.Errorf("expected no ID from a process that is not claude, got %q", got)
+	}
+}
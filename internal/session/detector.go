@@ -2,8 +2,9 @@ package session
 
 import (
 	"context"
+	"errors"
 	"fmt"
-	"os/exec"
+	"sort"
 	"strings"
 	"sync"
 	"time"
@@ -26,20 +27,36 @@ var (
 
 // Detector discovers Claude Code sessions from tmux.
 type Detector struct {
-	client *tmux.Client
+	client   *tmux.Client
+	stateDir string // hook state files; empty disables hook-based status
+
+	mu      sync.Mutex
+	checked map[string]time.Time // sessions recoverLabels looked at, by name, with their creation time
 }
 
 // NewDetector creates a new session detector.
 func NewDetector(client *tmux.Client) *Detector {
-	return &Detector{client: client}
+	return &Detector{client: client, stateDir: StateDir()}
 }
 
 // Detect finds all Claude-related tmux sessions.
 func (d *Detector) Detect(ctx context.Context) ([]Session, error) {
+	if d.client == nil {
+		return d.detectTerminalOnly()
+	}
+	if d.client.IsRemote() {
+		return d.detectRemote(ctx)
+	}
+
 	output, err := d.client.ListSessions(ctx, tmux.SessionFormat)
 	if err != nil {
-		// Even if tmux fails, still detect terminal sessions
-		return d.detectTerminalOnly()
+		// Even if tmux fails, still detect terminal sessions, telling the
+		// caller only when the server was lost so it can recover.
+		sessions, _ := d.detectTerminalOnly()
+		if errors.Is(err, tmux.ErrServerLost) {
+			return sessions, err
+		}
+		return sessions, nil
 	}
 	if output == "" {
 		return d.detectTerminalOnly()
@@ -47,6 +64,9 @@ func (d *Detector) Detect(ctx context.Context) ([]Session, error) {
 
 	rawSessions := tmux.ParseSessions(output)
 	sessions := make([]Session, 0, len(rawSessions))
+	windows := listWindows(ctx, d.client)
+	tags := listTags(ctx, d.client)
+	issues := listIssues(ctx, d.client)
 
 	// Build process table and children map once for all sessions.
 	procTable := monitor.GetProcessTable()
@@ -68,10 +88,18 @@ func (d *Detector) Detect(ctx context.Context) ([]Session, error) {
 			Attached:  raw.Attached,
 			Path:      raw.Path,
 			Managed:   true,
+			Windows:   windows[raw.Name],
+			Tags:      tags[raw.Name],
+			Issue:     issues[raw.Name],
 		}
+		d.recoverLabels(ctx, &s, raw.Created)
 
-		// Detect status from pane content and activity timestamp
-		s.Status = d.detectStatus(ctx, raw.Name, raw.Activity)
+		// Detect status from Claude Code hooks if they reported for this
+		// session, otherwise from pane content and activity timestamp
+		hook := currentHookState(d.stateDir, raw.Name, raw.Created)
+		if hook != nil && hook.Time.After(s.Activity) {
+			s.Activity = hook.Time
+		}
 
 		// Get PID
 		pid, err := d.client.GetSessionPID(ctx, raw.Name)
@@ -79,6 +107,23 @@ func (d *Detector) Detect(ctx context.Context) ([]Session, error) {
 			s.PID = pid
 		}
 
+		// The hooks follow claude to a new conversation (e.g. after /clear);
+		// its command line only names the one it was started with.
+		if hook != nil && hook.Conversation != "" {
+			s.Conversation = hook.Conversation
+		} else {
+			s.Conversation = claudeConversation(s.PID, procTable, procChildren)
+		}
+		if args, ok := claudeArgs(s.PID, procTable, procChildren); ok {
+			s.Mode = ModeFromArgs(args)
+		}
+
+		if status, exited := exitStatus(raw, claudeInTree(s.PID, procTable, procChildren)); exited {
+			s.Status = status
+		} else {
+			s.Status, s.Question = d.detectStatus(ctx, raw.Name, raw.Activity, hook)
+		}
+
 		sessions = append(sessions, s)
 	}
 
@@ -97,6 +142,49 @@ func (d *Detector) Detect(ctx context.Context) ([]Session, error) {
 	return sessions, nil
 }
 
+// detectRemote lists Claude sessions on a remote host. The local process
+// table says nothing about remote PIDs, so only tmux itself is consulted and
+// CPU/memory stay empty.
+func (d *Detector) detectRemote(ctx context.Context) ([]Session, error) {
+	output, err := d.client.ListSessions(ctx, tmux.SessionFormat)
+	if err != nil {
+		return nil, err
+	}
+
+	noProcs := map[string][]tmux.ProcEntry{}
+	windows := listWindows(ctx, d.client)
+	tags := listTags(ctx, d.client)
+	issues := listIssues(ctx, d.client)
+	var sessions []Session
+	for _, raw := range tmux.ParseSessions(output) {
+		isNameMatch := strings.HasPrefix(raw.Name, SessionPrefix) || strings.Contains(strings.ToLower(raw.Name), "claude")
+		if !isNameMatch && !d.client.HasClaudeProcess(ctx, raw.Name, noProcs) {
+			continue
+		}
+		running := !shells[executableName(strings.TrimPrefix(raw.Command, "-"))]
+		status, exited := exitStatus(raw, running)
+		var question string
+		if !exited {
+			status, question = d.detectStatus(ctx, raw.Name, raw.Activity, nil)
+		}
+		sessions = append(sessions, Session{
+			Name:      raw.Name,
+			Project:   extractProject(raw.Name, raw.Path),
+			Status:    status,
+			Question:  question,
+			StartedAt: raw.Created,
+			Activity:  raw.Activity,
+			Attached:  raw.Attached,
+			Path:      raw.Path,
+			Managed:   true,
+			Windows:   windows[raw.Name],
+			Tags:      tags[raw.Name],
+			Issue:     issues[raw.Name],
+		})
+	}
+	return sessions, nil
+}
+
 // detectTerminalOnly returns only terminal sessions (when tmux is unavailable).
 func (d *Detector) detectTerminalOnly() ([]Session, error) {
 	sessions := d.DetectTerminalSessions(make(map[string]bool))
@@ -105,27 +193,17 @@ func (d *Detector) detectTerminalOnly() ([]Session, error) {
 
 // DetectTerminalSessions finds Claude processes running outside tmux.
 func (d *Detector) DetectTerminalSessions(tmuxPIDs map[string]bool) []Session {
-	cmd := exec.Command("ps", "-eo", "pid,ppid,tty,args")
-	out, err := cmd.Output()
-	if err != nil {
-		return nil
-	}
-
 	var sessions []Session
-	lines := strings.Split(string(out), "\n")
-	for _, line := range lines[1:] { // skip header
-		fields := strings.Fields(line)
-		if len(fields) < 4 {
-			continue
-		}
-		pid := fields[0]
-		tty := fields[2]
+	for _, entry := range monitor.GetProcessTable() {
+		pid := entry.PID
+		tty := entry.TTY
 
 		// Only match processes where the executable base name is "claude"
-		execName := fields[3]
-		parts := strings.Split(execName, "/")
-		baseName := parts[len(parts)-1]
-		if baseName != "claude" {
+		argv := strings.Fields(entry.Args)
+		if len(argv) == 0 {
+			continue
+		}
+		if executableName(argv[0]) != "claude" {
 			continue
 		}
 
@@ -135,16 +213,15 @@ func (d *Detector) DetectTerminalSessions(tmuxPIDs map[string]bool) []Session {
 		}
 
 		// Skip background/detached processes (no TTY)
-		if tty == "??" || tty == "?" {
+		if tty == "??" || tty == "?" || tty == "" {
 			continue
 		}
 
-		// Get working directory via lsof
 		path := getProcessCWD(pid)
 
 		project := ""
 		if path != "" {
-			pathParts := strings.Split(strings.TrimRight(path, "/"), "/")
+			pathParts := strings.FieldsFunc(path, func(r rune) bool { return r == '/' || r == '\\' })
 			if len(pathParts) > 0 {
 				project = pathParts[len(pathParts)-1]
 			}
@@ -157,15 +234,20 @@ func (d *Detector) DetectTerminalSessions(tmuxPIDs map[string]bool) []Session {
 			PID:     pid,
 			Path:    path,
 			Managed: false,
+
+			Conversation: conversationFromArgs(entry.Args),
+			Mode:         ModeFromArgs(entry.Args),
 		}
 		sessions = append(sessions, s)
 	}
 
+	// The process table is a map; keep the list stable between refreshes.
+	sort.Slice(sessions, func(i, j int) bool { return sessions[i].Name < sessions[j].Name })
 	return sessions
 }
 
 // getProcessCWD gets the current working directory of a process.
-// Results are cached with a 10-second TTL to avoid repeated lsof calls.
+// Results are cached with a 10-second TTL since the lookup may fork lsof.
 func getProcessCWD(pid string) string {
 	cwdCacheMu.Lock()
 	if entry, ok := cwdCache[pid]; ok && time.Now().Before(entry.expires) {
@@ -174,17 +256,7 @@ func getProcessCWD(pid string) string {
 	}
 	cwdCacheMu.Unlock()
 
-	cmd := exec.Command("lsof", "-a", "-p", pid, "-d", "cwd", "-Fn")
-	out, err := cmd.Output()
-	result := ""
-	if err == nil {
-		for _, line := range strings.Split(string(out), "\n") {
-			if strings.HasPrefix(line, "n/") {
-				result = line[1:]
-				break
-			}
-		}
-	}
+	result := monitor.GetProcessCWD(pid)
 
 	cwdCacheMu.Lock()
 	cwdCache[pid] = cwdCacheEntry{path: result, expires: time.Now().Add(cwdCacheTTL)}
@@ -193,19 +265,81 @@ func getProcessCWD(pid string) string {
 	return result
 }
 
+// executableName returns the base name of a command path without a Windows
+// .exe suffix, accepting either path separator.
+func executableName(path string) string {
+	if i := strings.LastIndexAny(path, `/\`); i >= 0 {
+		path = path[i+1:]
+	}
+	if n := len(path) - len(".exe"); n > 0 && strings.EqualFold(path[n:], ".exe") {
+		path = path[:n]
+	}
+	return path
+}
+
+// shells are the pane commands that mean a session is back at a shell prompt.
+var shells = map[string]bool{
+	"sh": true, "bash": true, "zsh": true, "fish": true, "dash": true, "ksh": true,
+	"tcsh": true, "csh": true, "nu": true, "pwsh": true, "powershell": true,
+}
+
+// exitStatus reports whether claude has exited in a dashboard session, and
+// if so whether it crashed, its pane dead with an error, or simply exited:
+// its pane dead without one, or claude no longer running in it. Other
+// sessions may be shells that only sometimes run claude, so they never
+// count as exited.
+func exitStatus(raw tmux.RawSession, running bool) (Status, bool) {
+	if !strings.HasPrefix(raw.Name, SessionPrefix) {
+		return "", false
+	}
+	switch {
+	case raw.Dead && raw.Failed:
+		return StatusCrashed, true
+	case raw.Dead, !running:
+		return StatusExited, true
+	}
+	return "", false
+}
+
+// claudeInTree reports whether claude is the process pid or one of its
+// descendants. A pid missing from the table, as when the table could not be
+// read, is assumed to still run claude.
+func claudeInTree(pid string, table monitor.ProcessTable, procChildren map[string][]tmux.ProcEntry) bool {
+	entry, ok := table[pid]
+	if pid == "" || !ok {
+		return true
+	}
+	return strings.Contains(strings.ToLower(entry.Args), "claude") || tmux.HasClaudeDescendant(pid, procChildren)
+}
+
 // detectStatus determines session status by examining activity timestamp and pane content.
-func (d *Detector) detectStatus(ctx context.Context, name string, lastActivity time.Time) Status {
+// A non-nil hook state replaces the pane content heuristics, unless it is
+// stale. For a waiting session it also returns the question asked (see
+// PendingQuestion).
+func (d *Detector) detectStatus(ctx context.Context, name string, lastActivity time.Time, hook *HookState) (Status, string) {
 	// If activity is very recent (within 2 seconds), consider it active
 	// This handles cases where output is streaming but prompt is not visible yet
 	idleThreshold := 2 * time.Second
 	if !lastActivity.IsZero() && time.Since(lastActivity) < idleThreshold {
-		return StatusActive
+		return StatusActive, ""
+	}
+
+	if hook != nil && hook.Stale(lastActivity) {
+		hook = nil
+	}
+	if hook != nil {
+		if hook.Status != StatusWaiting {
+			return hook.Status, ""
+		}
+		// The hooks say claude waits, not on what; the pane does.
+		content, _ := d.client.CapturePaneContent(ctx, name, 20)
+		return hook.Status, PendingQuestion(content)
 	}
 
 	// If no recent activity, check pane content to distinguish idle vs waiting
 	content, err := d.client.CapturePaneContent(ctx, name, 20)
 	if err != nil {
-		return StatusIdle
+		return StatusIdle, ""
 	}
 
 	lines := strings.Split(content, "\n")
@@ -224,7 +358,10 @@ func (d *Detector) detectStatus(ctx context.Context, name string, lastActivity t
 		hasConfirmPattern := strings.Contains(line, "(y/n)") || strings.Contains(line, "(Y/n)") ||
 			strings.Contains(line, "(y/N)") || strings.Contains(line, "Y/n") || strings.Contains(line, "y/N")
 		if endsWithQuestion || hasConfirmPattern {
-			return StatusWaiting
+			if q := PendingQuestion(content); q != "" {
+				return StatusWaiting, q
+			}
+			return StatusWaiting, line
 		}
 
 		// Prompt visible = idle
@@ -237,10 +374,10 @@ func (d *Detector) detectStatus(ctx context.Context, name string, lastActivity t
 
 	// If prompt found, it's idle. Otherwise, unknown.
 	if hasPrompt {
-		return StatusIdle
+		return StatusIdle, ""
 	}
 
-	return StatusIdle // Default to idle when no recent activity
+	return StatusIdle, "" // Default to idle when no recent activity
 }
 
 // buildProcChildren converts a monitor.ProcessTable into the children map
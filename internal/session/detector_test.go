@@ -2,6 +2,9 @@ package session
 
 import (
 	"testing"
+
+	"github.com/seunggabi/claude-dashboard/internal/monitor"
+	"github.com/seunggabi/claude-dashboard/internal/tmux"
 )
 
 // ---------------------------------------------------------------------------
@@ -98,3 +101,64 @@ func TestExtractProject_tableTestCases(t *testing.T) {
 		})
 	}
 }
+
+// ---------------------------------------------------------------------------
+// executableName
+// ---------------------------------------------------------------------------
+
+func TestExecutableName_tableTests(t *testing.T) {
+	cases := []struct{ in, want string }{
+		{"claude", "claude"},
+		{"/usr/local/bin/claude", "claude"},
+		{`C:\Users\dev\.local\bin\claude.exe`, "claude"},
+		{`C:\tools\CLAUDE.EXE`, "CLAUDE"},
+		{"/usr/bin/claude-dashboard", "claude-dashboard"},
+		{".exe", ".exe"},
+	}
+	for _, tc := range cases {
+		if got := executableName(tc.in); got != tc.want {
+			t.Errorf("executableName(%q): expected %q, got %q", tc.in, tc.want, got)
+		}
+	}
+}
+
+// ---------------------------------------------------------------------------
+// exitStatus and claudeInTree
+// ---------------------------------------------------------------------------
+
+func TestExitStatus_deadPaneOrNoClaudeInADashboardSession(t *testing.T) {
+	cases := []struct {
+		raw     tmux.RawSession
+		running bool
+		want    Status
+	}{
+		{tmux.RawSession{Name: "cd-api", Command: "claude"}, true, ""},
+		{tmux.RawSession{Name: "cd-api", Command: "zsh"}, false, StatusExited},
+		{tmux.RawSession{Name: "cd-api", Command: "claude", Dead: true}, true, StatusExited},
+		{tmux.RawSession{Name: "cd-api", Command: "claude", Dead: true, Failed: true}, true, StatusCrashed},
+		{tmux.RawSession{Name: "claude-scratch", Command: "zsh"}, false, ""},
+	}
+	for _, tc := range cases {
+		got, exited := exitStatus(tc.raw, tc.running)
+		if got != tc.want || exited != (tc.want != "") {
+			t.Errorf("exitStatus(%+v, %v): expected %q, got %q, %v", tc.raw, tc.running, tc.want, got, exited)
+		}
+	}
+}
+
+func TestClaudeInTree_paneProcessOrDescendant(t *testing.T) {
+	table := monitor.ProcessTable{
+		"10": {PID: "10", PPID: "1", Args: "/usr/local/bin/claude --resume"},
+		"20": {PID: "20", PPID: "1", Args: "-zsh"},
+		"21": {PID: "21", PPID: "20", Args: "node /opt/claude/cli.js"},
+		"30": {PID: "30", PPID: "1", Args: "-zsh"},
+		"31": {PID: "31", PPID: "30", Args: "vim notes.md"},
+	}
+	children := buildProcChildren(table)
+	cases := map[string]bool{"10": true, "20": true, "30": false, "99": true, "": true}
+	for pid, want := range cases {
+		if got := claudeInTree(pid, table, children); got != want {
+			t.Errorf("claudeInTree(%q): expected %v, got %v", pid, want, got)
+		}
+	}
+}
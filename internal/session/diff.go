@@ -0,0 +1,110 @@
+package session
+
+import "time"
+
+// Change is a session present in both listings whose state differs.
+type Change struct {
+	Old, New Session
+	Fields   []string // names of the differing fields, e.g. "status"
+}
+
+// Has reports whether field is among the changed fields.
+func (c Change) Has(field string) bool {
+	for _, f := range c.Fields {
+		if f == field {
+			return true
+		}
+	}
+	return false
+}
+
+// ChangeSet is what changed between two listings of sessions.
+type ChangeSet struct {
+	Added   []Session // in the order of the new listing
+	Removed []Session // in the order of the old listing
+	Changed []Change  // in the order of the new listing
+}
+
+// Empty reports whether the listings are the same.
+func (c ChangeSet) Empty() bool {
+	return len(c.Added) == 0 && len(c.Removed) == 0 && len(c.Changed) == 0
+}
+
+// sessionKey identifies a session across listings; names are only unique
+// per host.
+func sessionKey(s Session) string {
+	return s.Host + "/" + s.Name
+}
+
+// changedFields returns the fields that differ between two states of one
+// session. Readings that move on every refresh (CPU, memory, activity time)
+// are not compared.
+func changedFields(old, new Session) []string {
+	var fields []string
+	if old.Status != new.Status {
+		fields = append(fields, "status")
+	}
+	if old.Attached != new.Attached {
+		fields = append(fields, "attached")
+	}
+	if old.Path != new.Path {
+		fields = append(fields, "path")
+	}
+	if old.Project != new.Project {
+		fields = append(fields, "project")
+	}
+	if old.PID != new.PID {
+		fields = append(fields, "pid")
+	}
+	if old.Managed != new.Managed {
+		fields = append(fields, "managed")
+	}
+	return fields
+}
+
+// Diff compares two listings of sessions, matching sessions by host and
+// name.
+func Diff(old, new []Session) ChangeSet {
+	var c ChangeSet
+	before := make(map[string]Session, len(old))
+	for _, s := range old {
+		before[sessionKey(s)] = s
+	}
+	after := make(map[string]bool, len(new))
+	for _, s := range new {
+		key := sessionKey(s)
+		after[key] = true
+		prev, ok := before[key]
+		if !ok {
+			c.Added = append(c.Added, s)
+			continue
+		}
+		if fields := changedFields(prev, s); len(fields) > 0 {
+			c.Changed = append(c.Changed, Change{Old: prev, New: s, Fields: fields})
+		}
+	}
+	for _, s := range old {
+		if !after[sessionKey(s)] {
+			c.Removed = append(c.Removed, s)
+		}
+	}
+	return c
+}
+
+// Events returns the events the change set amounts to: status changes,
+// additions, then removals.
+func (c ChangeSet) Events(at time.Time) []Event {
+	var events []Event
+	for _, ch := range c.Changed {
+		if ch.Has("status") {
+			events = append(events, Event{Kind: StatusChanged, Session: ch.New, From: ch.Old.Status, At: at})
+		}
+	}
+	for _, s := range c.Added {
+		events = append(events, Event{Kind: SessionAdded, Session: s, At: at})
+	}
+	for _, s := range c.Removed {
+		events = append(events, Event{Kind: SessionRemoved, Session: s, At: at})
+	}
+	return events
+}
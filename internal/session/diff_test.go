@@ -0,0 +1,84 @@
+package session
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+// ---------------------------------------------------------------------------
+// Diff
+// ---------------------------------------------------------------------------
+
+func TestDiff_identicalListingsAreEmpty(t *testing.T) {
+	list := []Session{{Name: "cd-a", Status: StatusIdle, CPU: 1}}
+	moved := []Session{{Name: "cd-a", Status: StatusIdle, CPU: 50, Activity: time.Now()}}
+	if c := Diff(list, moved); !c.Empty() {
+		t.Errorf("expected CPU and activity changes to be ignored, got %+v", c)
+	}
+	if c := Diff(nil, nil); !c.Empty() {
+		t.Errorf("expected empty change set, got %+v", c)
+	}
+}
+
+func TestDiff_addedRemovedAndChangedInListingOrder(t *testing.T) {
+	old := []Session{
+		{Name: "cd-b", Status: StatusIdle},
+		{Name: "cd-a", Status: StatusIdle},
+		{Name: "cd-z"},
+		{Name: "cd-y"},
+	}
+	new := []Session{
+		{Name: "cd-d"},
+		{Name: "cd-a", Status: StatusWaiting, Attached: true},
+		{Name: "cd-c"},
+		{Name: "cd-b", Status: StatusIdle},
+	}
+	c := Diff(old, new)
+
+	if got := names(c.Added); !reflect.DeepEqual(got, []string{"cd-d", "cd-c"}) {
+		t.Errorf("unexpected added %v", got)
+	}
+	if got := names(c.Removed); !reflect.DeepEqual(got, []string{"cd-z", "cd-y"}) {
+		t.Errorf("unexpected removed %v", got)
+	}
+	if len(c.Changed) != 1 {
+		t.Fatalf("expected one change, got %+v", c.Changed)
+	}
+	ch := c.Changed[0]
+	if ch.Old.Status != StatusIdle || ch.New.Status != StatusWaiting || !reflect.DeepEqual(ch.Fields, []string{"status", "attached"}) {
+		t.Errorf("unexpected change %+v", ch)
+	}
+	if !ch.Has("attached") || ch.Has("path") {
+		t.Errorf("unexpected Has results for fields %v", ch.Fields)
+	}
+}
+
+func TestDiff_matchesByHostAndName(t *testing.T) {
+	old := []Session{{Name: "cd-a"}}
+	new := []Session{{Name: "cd-a", Host: "devbox"}}
+	c := Diff(old, new)
+	if len(c.Added) != 1 || len(c.Removed) != 1 || len(c.Changed) != 0 {
+		t.Errorf("expected same-named sessions on different hosts to be distinct, got %+v", c)
+	}
+}
+
+func TestChangeSet_eventsOnlyForStatusChanges(t *testing.T) {
+	at := time.Now()
+	c := Diff(
+		[]Session{{Name: "cd-a", Status: StatusIdle}, {Name: "cd-b", Path: "/x"}},
+		[]Session{{Name: "cd-a", Status: StatusActive}, {Name: "cd-b", Path: "/y"}},
+	)
+	events := c.Events(at)
+	if len(events) != 1 || events[0].Kind != StatusChanged || events[0].From != StatusIdle || !events[0].At.Equal(at) {
+		t.Errorf("expected one status event, got %+v", events)
+	}
+}
+
+func names(sessions []Session) []string {
+	var out []string
+	for _, s := range sessions {
+		out = append(out, s.Name)
+	}
+	return out
+}
@@ -0,0 +1,149 @@
+package session
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// EventKind says what changed about a session between two listings.
+type EventKind int
+
+const (
+	SessionAdded EventKind = iota
+	StatusChanged
+	SessionRemoved
+	AllQuiet // no session is working any more; see Tracker
+)
+
+func (k EventKind) String() string {
+	switch k {
+	case SessionAdded:
+		return "added"
+	case StatusChanged:
+		return "status"
+	case SessionRemoved:
+		return "removed"
+	case AllQuiet:
+		return "all quiet"
+	default:
+		return "unknown"
+	}
+}
+
+// Event is one change seen between two listings of a host's sessions.
+type Event struct {
+	Kind    EventKind
+	Session Session   // state after the change; for SessionRemoved, the last state seen
+	From    Status    // status before a StatusChanged
+	Quiet   []Session // for AllQuiet, the sessions idle or waiting
+	At      time.Time
+}
+
+// minQuiet is how many sessions have to be idle or waiting for AllQuiet; a
+// single session is covered by its own events.
+const minQuiet = 2
+
+// eventBuffer is how many undelivered events a subscriber can fall behind
+// by before further events are dropped for it.
+const eventBuffer = 64
+
+// Tracker turns successive session listings into events (see Diff) and
+// delivers them to subscribers, so consumers need not diff snapshots
+// themselves. Listings are tracked per host, so the managers of several
+// hosts can share one.
+//
+// Once no session on any host is working after some were, the tracker also
+// publishes a single AllQuiet event naming the sessions left idle or
+// waiting; it is armed again when a session starts working.
+type Tracker struct {
+	mu    sync.Mutex
+	hosts map[string][]Session // last listing per host
+	subs  map[chan Event]struct{}
+	busy  bool // a session has worked since the last AllQuiet
+}
+
+// NewTracker creates a tracker with no listings yet.
+func NewTracker() *Tracker {
+	return &Tracker{
+		hosts: make(map[string][]Session),
+		subs:  make(map[chan Event]struct{}),
+	}
+}
+
+// Subscribe returns a channel receiving every event from now on, and a
+// function that ends the subscription and closes the channel. A subscriber
+// that falls eventBuffer events behind misses events rather than blocking
+// the listing.
+func (t *Tracker) Subscribe() (<-chan Event, func()) {
+	ch := make(chan Event, eventBuffer)
+	t.mu.Lock()
+	t.subs[ch] = struct{}{}
+	t.mu.Unlock()
+
+	var once sync.Once
+	return ch, func() {
+		once.Do(func() {
+			t.mu.Lock()
+			delete(t.subs, ch)
+			t.mu.Unlock()
+			close(ch)
+		})
+	}
+}
+
+// Observe records the current sessions of host (empty for the local
+// machine), publishes the changes since its previous listing and returns
+// them. The first listing of a host is the baseline and yields no events.
+func (t *Tracker) Observe(host string, sessions []Session, now time.Time) []Event {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	prev, seen := t.hosts[host]
+	t.hosts[host] = append([]Session(nil), sessions...)
+	if !seen {
+		t.quiet(now)
+		return nil
+	}
+
+	events := Diff(prev, sessions).Events(now)
+	if e, ok := t.quiet(now); ok {
+		events = append(events, e)
+	}
+	for _, e := range events {
+		for ch := range t.subs {
+			select {
+			case ch <- e:
+			default: // subscriber is behind; drop rather than block
+			}
+		}
+	}
+	return events
+}
+
+// quiet updates whether sessions are working across all hosts and returns
+// an AllQuiet event when the last of them has stopped, leaving at least
+// minQuiet sessions idle or waiting. The caller holds t.mu.
+func (t *Tracker) quiet(now time.Time) (Event, bool) {
+	var quiet []Session
+	for _, sessions := range t.hosts {
+		for _, s := range sessions {
+			switch s.Status {
+			case StatusActive:
+				t.busy = true
+				return Event{}, false
+			case StatusIdle, StatusWaiting:
+				quiet = append(quiet, s)
+			}
+		}
+	}
+	if !t.busy {
+		return Event{}, false
+	}
+	t.busy = false
+	if len(quiet) < minQuiet {
+		return Event{}, false
+	}
+	sort.Slice(quiet, func(i, j int) bool { return sessionKey(quiet[i]) < sessionKey(quiet[j]) })
+	return Event{Kind: AllQuiet, Quiet: quiet, At: now}, true
+}
@@ -0,0 +1,142 @@
+package session
+
+import (
+	"testing"
+	"time"
+)
+
+// ---------------------------------------------------------------------------
+// Tracker
+// ---------------------------------------------------------------------------
+
+func TestTracker_firstListingIsBaseline(t *testing.T) {
+	tr := NewTracker()
+	if events := tr.Observe("", []Session{{Name: "cd-a", Status: StatusIdle}}, time.Now()); events != nil {
+		t.Errorf("expected no events for the first listing, got %+v", events)
+	}
+}
+
+func TestTracker_reportsAddedChangedAndRemoved(t *testing.T) {
+	tr := NewTracker()
+	now := time.Now()
+	tr.Observe("", []Session{
+		{Name: "cd-a", Status: StatusIdle},
+		{Name: "cd-b", Status: StatusActive},
+		{Name: "cd-c", Status: StatusIdle},
+	}, now)
+
+	events := tr.Observe("", []Session{
+		{Name: "cd-a", Status: StatusWaiting},
+		{Name: "cd-c", Status: StatusIdle},
+		{Name: "cd-d", Status: StatusActive},
+	}, now)
+	if len(events) != 3 {
+		t.Fatalf("expected 3 events, got %+v", events)
+	}
+	if e := events[0]; e.Kind != StatusChanged || e.Session.Name != "cd-a" || e.From != StatusIdle || e.Session.Status != StatusWaiting {
+		t.Errorf("unexpected status event %+v", e)
+	}
+	if e := events[1]; e.Kind != SessionAdded || e.Session.Name != "cd-d" {
+		t.Errorf("unexpected added event %+v", e)
+	}
+	if e := events[2]; e.Kind != SessionRemoved || e.Session.Name != "cd-b" || e.Session.Status != StatusActive {
+		t.Errorf("unexpected removed event %+v", e)
+	}
+}
+
+func TestTracker_hostsAreTrackedSeparately(t *testing.T) {
+	tr := NewTracker()
+	now := time.Now()
+	tr.Observe("", []Session{{Name: "cd-a"}}, now)
+	tr.Observe("devbox", []Session{{Name: "cd-a", Host: "devbox"}}, now)
+	if events := tr.Observe("devbox", nil, now); len(events) != 1 || events[0].Session.Host != "devbox" {
+		t.Errorf("expected only the devbox session removed, got %+v", events)
+	}
+	if events := tr.Observe("", []Session{{Name: "cd-a"}}, now); events != nil {
+		t.Errorf("expected the local listing unaffected, got %+v", events)
+	}
+}
+
+func TestTracker_allQuietOnceTheLastSessionStops(t *testing.T) {
+	tr := NewTracker()
+	now := time.Now()
+	tr.Observe("", []Session{
+		{Name: "cd-a", Status: StatusActive},
+		{Name: "cd-b", Status: StatusIdle},
+	}, now)
+	tr.Observe("devbox", []Session{{Name: "cd-c", Host: "devbox", Status: StatusActive}}, now)
+
+	if events := tr.Observe("", []Session{
+		{Name: "cd-a", Status: StatusWaiting},
+		{Name: "cd-b", Status: StatusIdle},
+	}, now); len(events) != 1 {
+		t.Fatalf("expected only the status event while devbox works, got %+v", events)
+	}
+	events := tr.Observe("devbox", []Session{{Name: "cd-c", Host: "devbox", Status: StatusIdle}}, now)
+	if len(events) != 2 || events[1].Kind != AllQuiet {
+		t.Fatalf("expected a status and an all quiet event, got %+v", events)
+	}
+	if q := events[1].Quiet; len(q) != 3 || q[0].Name != "cd-a" || q[2].Name != "cd-c" {
+		t.Errorf("expected the three quiet sessions in host and name order, got %+v", q)
+	}
+
+	if events := tr.Observe("", []Session{{Name: "cd-b", Status: StatusIdle}}, now); len(events) != 1 {
+		t.Errorf("expected no second all quiet before a session works again, got %+v", events)
+	}
+	tr.Observe("", []Session{{Name: "cd-b", Status: StatusActive}}, now)
+	if events := tr.Observe("", []Session{{Name: "cd-b", Status: StatusWaiting}}, now); len(events) != 2 || events[1].Kind != AllQuiet {
+		t.Errorf("expected all quiet again after cd-b worked, got %+v", events)
+	}
+}
+
+func TestTracker_noAllQuietForASingleSession(t *testing.T) {
+	tr := NewTracker()
+	now := time.Now()
+	tr.Observe("", []Session{{Name: "cd-a", Status: StatusActive}, {Name: "cd-b", Status: StatusExited}}, now)
+	events := tr.Observe("", []Session{{Name: "cd-a", Status: StatusIdle}, {Name: "cd-b", Status: StatusExited}}, now)
+	if len(events) != 1 || events[0].Kind != StatusChanged {
+		t.Errorf("expected only the status event, got %+v", events)
+	}
+}
+
+func TestTracker_subscribersReceiveEventsUntilUnsubscribed(t *testing.T) {
+	tr := NewTracker()
+	ch, stop := tr.Subscribe()
+	now := time.Now()
+	tr.Observe("", nil, now)
+	tr.Observe("", []Session{{Name: "cd-a"}}, now)
+
+	select {
+	case e := <-ch:
+		if e.Kind != SessionAdded || e.Session.Name != "cd-a" {
+			t.Errorf("unexpected event %+v", e)
+		}
+	default:
+		t.Fatal("expected an event to be delivered")
+	}
+
+	stop()
+	stop() // idempotent
+	if _, ok := <-ch; ok {
+		t.Error("expected the channel to be closed")
+	}
+	tr.Observe("", nil, now) // must not send on the closed channel
+}
+
+func TestTracker_slowSubscriberDoesNotBlock(t *testing.T) {
+	tr := NewTracker()
+	ch, stop := tr.Subscribe()
+	defer stop()
+	now := time.Now()
+	tr.Observe("", nil, now)
+	for i := 0; i < eventBuffer+10; i++ {
+		status := StatusIdle
+		if i%2 == 0 {
+			status = StatusActive
+		}
+		tr.Observe("", []Session{{Name: "cd-a", Status: status}}, now)
+	}
+	if len(ch) != eventBuffer {
+		t.Errorf("expected the buffer full with %d events, got %d", eventBuffer, len(ch))
+	}
+}
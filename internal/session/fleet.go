@@ -0,0 +1,30 @@
+package session
+
+// Fleet sums up a session listing for the dashboard header.
+type Fleet struct {
+	Sessions int
+	ByStatus map[Status]int
+	CPU      float64 // percent, summed over the sessions' process trees
+	Memory   float64 // percent of physical memory, summed likewise
+	Hosts    int     // hosts with at least one session, the local machine included
+}
+
+// Summarize totals sessions. CPU and memory are only known for local
+// sessions, so remote ones add nothing to them.
+func Summarize(sessions []Session) Fleet {
+	f := Fleet{Sessions: len(sessions), ByStatus: make(map[Status]int)}
+	hosts := make(map[string]bool)
+	for _, s := range sessions {
+		f.ByStatus[s.Status]++
+		f.CPU += s.CPU
+		f.Memory += s.Memory
+		hosts[s.HostName()] = true
+	}
+	f.Hosts = len(hosts)
+	return f
+}
+
+// Count returns how many sessions have status.
+func (f Fleet) Count(status Status) int {
+	return f.ByStatus[status]
+}
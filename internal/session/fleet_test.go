@@ -0,0 +1,25 @@
+package session
+
+import "testing"
+
+// ---------------------------------------------------------------------------
+// Summarize
+// ---------------------------------------------------------------------------
+
+func TestSummarize_countsStatusesAndSumsResources(t *testing.T) {
+	f := Summarize([]Session{
+		{Name: "cd-api", Status: StatusActive, CPU: 40.5, Memory: 2},
+		{Name: "cd-web", Status: StatusIdle, CPU: 1.5, Memory: 1.5},
+		{Name: "cd-gpu", Host: "devbox", Status: StatusActive},
+		{Name: "claude", Status: StatusTerminal, CPU: 3},
+	})
+	if f.Sessions != 4 || f.Count(StatusActive) != 2 || f.Count(StatusIdle) != 1 || f.Count(StatusWaiting) != 0 {
+		t.Errorf("unexpected counts %+v", f)
+	}
+	if f.CPU != 45 || f.Memory != 3.5 || f.Hosts != 2 {
+		t.Errorf("unexpected totals %+v", f)
+	}
+	if empty := Summarize(nil); empty.Sessions != 0 || empty.Hosts != 0 || empty.Count(StatusIdle) != 0 {
+		t.Errorf("unexpected empty fleet %+v", empty)
+	}
+}
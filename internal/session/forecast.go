@@ -0,0 +1,106 @@
+package session
+
+import (
+	"time"
+
+	"github.com/seunggabi/claude-dashboard/internal/conversation"
+	"github.com/seunggabi/claude-dashboard/internal/monitor"
+)
+
+const (
+	// ForecastWindow is how far back a forecast looks: the output token
+	// rate of the last forecastRecent is compared with the rest of it.
+	ForecastWindow = 15 * time.Minute
+	forecastRecent = 5 * time.Minute
+
+	// busyCPU is the average CPU percent above which a session producing
+	// no output is taken to be running something, e.g. a long build.
+	busyCPU = 5.0
+)
+
+// Trend is where the work of a session seems to be heading.
+type Trend string
+
+const (
+	TrendUnknown   Trend = ""          // too little activity to tell
+	TrendRising    Trend = "rising"    // writing more than before
+	TrendSteady    Trend = "steady"    // writing about as much as before
+	TrendDeclining Trend = "declining" // writing less than before, likely finishing
+	TrendQuiet     Trend = "quiet"     // wrote before, nothing recently
+	TrendBusy      Trend = "busy"      // no output but CPU in use, e.g. a long tool run
+	TrendBlocked   Trend = "blocked"   // waiting for input
+	TrendDone      Trend = "done"      // idle or exited
+)
+
+// Forecast is a rough outlook on a session from its recent history; a
+// heuristic to help decide whether to wait or interrupt, not a prediction.
+type Forecast struct {
+	Trend  Trend
+	Recent float64       // output tokens per minute over the last forecastRecent
+	Before float64       // output tokens per minute over the rest of ForecastWindow
+	CPU    float64       // average CPU percent over the last forecastRecent
+	ETA    time.Duration // when declining, until the rate would reach zero; else zero
+}
+
+// Predict reads the trend of a session with status from its samples and
+// assistant messages of the last ForecastWindow.
+func Predict(status Status, samples []monitor.Sample, msgs []conversation.Message, now time.Time) Forecast {
+	var f Forecast
+	split := now.Add(-forecastRecent)
+	start := now.Add(-ForecastWindow)
+	var recent, before int
+	for _, msg := range msgs {
+		switch {
+		case msg.Timestamp.After(split):
+			recent += msg.Usage.OutputTokens
+		case msg.Timestamp.After(start):
+			before += msg.Usage.OutputTokens
+		}
+	}
+	f.Recent = float64(recent) / forecastRecent.Minutes()
+	f.Before = float64(before) / (ForecastWindow - forecastRecent).Minutes()
+
+	var cpu float64
+	n := 0
+	for _, s := range samples {
+		if s.Time.After(split) {
+			cpu += s.CPU
+			n++
+		}
+	}
+	if n > 0 {
+		f.CPU = cpu / float64(n)
+	}
+
+	switch status {
+	case StatusWaiting:
+		f.Trend = TrendBlocked
+		return f
+	case StatusIdle, StatusExited, StatusCrashed:
+		f.Trend = TrendDone
+		return f
+	case StatusActive:
+	default:
+		return f
+	}
+
+	switch {
+	case f.Recent == 0 && f.CPU >= busyCPU:
+		f.Trend = TrendBusy
+	case f.Recent == 0 && f.Before > 0:
+		f.Trend = TrendQuiet
+	case f.Recent == 0:
+		// nothing written in the whole window
+	case f.Before == 0 || f.Recent > 1.5*f.Before:
+		f.Trend = TrendRising
+	case f.Recent < 0.5*f.Before:
+		f.Trend = TrendDeclining
+		// The rates are averages over windows whose middles lie
+		// ForecastWindow/2 apart; continue the line between them.
+		slope := (f.Before - f.Recent) / (ForecastWindow / 2).Minutes()
+		f.ETA = time.Duration(f.Recent / slope * float64(time.Minute))
+	default:
+		f.Trend = TrendSteady
+	}
+	return f
+}
@@ -0,0 +1,76 @@
+package session
+
+import (
+	"testing"
+	"time"
+
+	"github.com/seunggabi/claude-dashboard/internal/conversation"
+	"github.com/seunggabi/claude-dashboard/internal/monitor"
+)
+
+// ---------------------------------------------------------------------------
+// Predict
+// ---------------------------------------------------------------------------
+
+// outputAt returns assistant messages writing tokens the given minutes
+// before now.
+func outputAt(now time.Time, tokens int, minutesAgo ...int) []conversation.Message {
+	var msgs []conversation.Message
+	for _, m := range minutesAgo {
+		msgs = append(msgs, conversation.Message{
+			Role:      "assistant",
+			Timestamp: now.Add(-time.Duration(m) * time.Minute),
+			Usage:     conversation.Usage{OutputTokens: tokens},
+		})
+	}
+	return msgs
+}
+
+func TestPredict_decliningRateIsLikelyFinishing(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	// 1000 tokens/min over the ten minutes before, 200/min recently.
+	msgs := append(outputAt(now, 1000, 6, 7, 8, 9, 10, 11, 12, 13, 14, 14), outputAt(now, 1000, 1)...)
+	f := Predict(StatusActive, nil, msgs, now)
+	if f.Trend != TrendDeclining || f.Before != 1000 || f.Recent != 200 {
+		t.Fatalf("expected a declining rate from 1000 to 200/min, got %+v", f)
+	}
+	if f.ETA != 112500*time.Millisecond {
+		t.Errorf("expected the rate to reach zero in 1m52.5s, got %v", f.ETA)
+	}
+}
+
+func TestPredict_risingAndSteady(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	if f := Predict(StatusActive, nil, outputAt(now, 500, 1, 2), now); f.Trend != TrendRising {
+		t.Errorf("expected output after none to be rising, got %+v", f)
+	}
+	msgs := append(outputAt(now, 1000, 6, 8), outputAt(now, 1000, 2)...)
+	if f := Predict(StatusActive, nil, msgs, now); f.Trend != TrendSteady {
+		t.Errorf("expected 200/min both times to be steady, got %+v", f)
+	}
+}
+
+func TestPredict_noRecentOutput(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	busy := []monitor.Sample{{Time: now.Add(-time.Minute), CPU: 80}, {Time: now.Add(-2 * time.Minute), CPU: 40}}
+	if f := Predict(StatusActive, busy, outputAt(now, 100, 10), now); f.Trend != TrendBusy || f.CPU != 60 {
+		t.Errorf("expected a busy session, got %+v", f)
+	}
+	if f := Predict(StatusActive, nil, outputAt(now, 100, 10), now); f.Trend != TrendQuiet {
+		t.Errorf("expected a quiet session, got %+v", f)
+	}
+	if f := Predict(StatusActive, nil, nil, now); f.Trend != TrendUnknown {
+		t.Errorf("expected no trend without activity, got %+v", f)
+	}
+}
+
+func TestPredict_statusDecidesOutsideActiveWork(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	msgs := outputAt(now, 1000, 1)
+	if f := Predict(StatusWaiting, nil, msgs, now); f.Trend != TrendBlocked {
+		t.Errorf("expected a waiting session to be blocked, got %+v", f)
+	}
+	if f := Predict(StatusIdle, nil, msgs, now); f.Trend != TrendDone {
+		t.Errorf("expected an idle session to be done, got %+v", f)
+	}
+}
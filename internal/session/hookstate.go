@@ -0,0 +1,83 @@
+package session
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/seunggabi/claude-dashboard/internal/config"
+)
+
+// HookState is the last status reported by the Claude Code hooks installed
+// by setup (see the claude-dashboard-hook script).
+type HookState struct {
+	Status       Status
+	Event        string
+	Time         time.Time
+	Conversation string // claude's session ID; empty from older hook scripts
+}
+
+// hookStateFile mirrors the JSON written by the hook script.
+type hookStateFile struct {
+	State     string `json:"state"`
+	Event     string `json:"event"`
+	Time      int64  `json:"time"`
+	SessionID string `json:"session_id,omitempty"`
+}
+
+// StateDir returns the directory hook state files are written to.
+func StateDir() string {
+	return filepath.Join(config.ConfigDir(), "state")
+}
+
+// stateFilePath returns the state file for a tmux session name.
+func stateFilePath(dir, name string) string {
+	return filepath.Join(dir, strings.ReplaceAll(name, "/", "_")+".json")
+}
+
+// ReadHookState returns the hook-reported state of a session. ok is false
+// when no hooks have fired for it or the file is unreadable.
+func ReadHookState(dir, name string) (HookState, bool) {
+	data, err := os.ReadFile(stateFilePath(dir, name))
+	if err != nil {
+		return HookState{}, false
+	}
+	var f hookStateFile
+	if err := json.Unmarshal(data, &f); err != nil || f.Time == 0 {
+		return HookState{}, false
+	}
+	status := Status(f.State)
+	switch status {
+	case StatusActive, StatusIdle, StatusWaiting:
+	default:
+		return HookState{}, false
+	}
+	return HookState{Status: status, Event: f.Event, Time: time.Unix(f.Time, 0), Conversation: f.SessionID}, true
+}
+
+// hookStaleAfter is how long after a hook state the pane may go on
+// writing, e.g. redrawing claude's prompt, before the state is outdated.
+const hookStaleAfter = 5 * time.Second
+
+// Stale reports whether the pane wrote well after hs, with paneActivity:
+// claude moved on without a hook telling, as when it was interrupted or
+// crashed, so hs no longer says what it does.
+func (hs HookState) Stale(paneActivity time.Time) bool {
+	return !paneActivity.IsZero() && paneActivity.Sub(hs.Time) > hookStaleAfter
+}
+
+// currentHookState returns the hook state for a session, ignoring a state
+// file written before the session was created: it belongs to an earlier
+// session of the same name.
+func currentHookState(dir, name string, created time.Time) *HookState {
+	if dir == "" {
+		return nil
+	}
+	hs, ok := ReadHookState(dir, name)
+	if !ok || (!created.IsZero() && hs.Time.Before(created.Truncate(time.Second))) {
+		return nil
+	}
+	return &hs
+}
@@ -0,0 +1,137 @@
+package session
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/seunggabi/claude-dashboard/internal/tmux"
+)
+
+func writeStateFile(t *testing.T, dir, name, body string) {
+	t.Helper()
+	if err := os.WriteFile(filepath.Join(dir, name), []byte(body), 0644); err != nil {
+		t.Fatalf("failed to write state file: %v", err)
+	}
+}
+
+// ---------------------------------------------------------------------------
+// ReadHookState
+// ---------------------------------------------------------------------------
+
+func TestReadHookState_parsesScriptOutput(t *testing.T) {
+	dir := t.TempDir()
+	writeStateFile(t, dir, "cd-api.json", `{"state":"waiting","event":"Notification","time":1700000000}`)
+
+	hs, ok := ReadHookState(dir, "cd-api")
+	if !ok {
+		t.Fatal("expected state to be read")
+	}
+	if hs.Status != StatusWaiting || hs.Event != "Notification" || hs.Time.Unix() != 1700000000 {
+		t.Errorf("unexpected state %+v", hs)
+	}
+}
+
+func TestReadHookState_sanitizesSlashesInName(t *testing.T) {
+	dir := t.TempDir()
+	writeStateFile(t, dir, "team_api.json", `{"state":"idle","event":"Stop","time":1700000000}`)
+	if _, ok := ReadHookState(dir, "team/api"); !ok {
+		t.Error("expected slash in session name to map to underscore")
+	}
+}
+
+func TestReadHookState_rejectsMissingOrInvalid(t *testing.T) {
+	dir := t.TempDir()
+	writeStateFile(t, dir, "bad.json", `not json`)
+	writeStateFile(t, dir, "odd.json", `{"state":"terminal","event":"Stop","time":1700000000}`)
+	for _, name := range []string{"missing", "bad", "odd"} {
+		if _, ok := ReadHookState(dir, name); ok {
+			t.Errorf("%s: expected no state", name)
+		}
+	}
+}
+
+// ---------------------------------------------------------------------------
+// currentHookState / detectStatus
+// ---------------------------------------------------------------------------
+
+func TestCurrentHookState_ignoresStateFromEarlierSession(t *testing.T) {
+	dir := t.TempDir()
+	writeStateFile(t, dir, "cd-api.json", `{"state":"waiting","event":"Notification","time":1700000000}`)
+
+	if hs := currentHookState(dir, "cd-api", time.Unix(1700000100, 0)); hs != nil {
+		t.Errorf("expected stale state to be ignored, got %+v", hs)
+	}
+	if hs := currentHookState(dir, "cd-api", time.Unix(1699999000, 0)); hs == nil {
+		t.Error("expected state written after creation to be used")
+	}
+	if hs := currentHookState("", "cd-api", time.Time{}); hs != nil {
+		t.Error("expected empty state dir to disable hook state")
+	}
+}
+
+func TestDetectStatus_hookStateSkipsPaneScraping(t *testing.T) {
+	// A nil client would panic if capture-pane were attempted; only a
+	// waiting session's pane is read, for its question.
+	d := &Detector{}
+	hook := &HookState{Status: StatusIdle, Time: time.Now().Add(-time.Minute)}
+	got, question := d.detectStatus(context.Background(), "cd-api", time.Now().Add(-time.Minute), hook)
+	if got != StatusIdle || question != "" {
+		t.Errorf("expected %q, got %q, %q", StatusIdle, got, question)
+	}
+}
+
+func TestHookState_staleOncePaneMovesOn(t *testing.T) {
+	hs := HookState{Status: StatusActive, Time: time.Unix(1700000000, 0)}
+	if hs.Stale(time.Unix(1700000003, 0)) {
+		t.Error("expected output right after the hook, e.g. a redraw, to keep it current")
+	}
+	if !hs.Stale(time.Unix(1700000060, 0)) {
+		t.Error("expected output a minute after the hook to make it stale")
+	}
+	if hs.Stale(time.Time{}) {
+		t.Error("expected unknown pane activity to keep the hook state")
+	}
+}
+
+func TestDetectStatus_staleStateFileFallsBackToPane(t *testing.T) {
+	dir := t.TempDir()
+	// claude was interrupted while active: no hook fired afterwards, but its
+	// pane printed more half a minute later.
+	writeStateFile(t, dir, "cd-api.json", `{"state":"active","event":"UserPromptSubmit","time":1700000000}`)
+	hook := currentHookState(dir, "cd-api", time.Time{})
+	if hook == nil {
+		t.Fatal("expected the state file to be read")
+	}
+	client, err := tmux.NewSocketClient(fmt.Sprintf("cd-stale-test-%d", os.Getpid()))
+	if err != nil {
+		t.Skip("tmux not installed")
+	}
+	// No server runs on the socket, so the pane reads as empty: idle.
+	d := &Detector{client: client}
+	got, _ := d.detectStatus(context.Background(), "cd-api", time.Unix(1700000030, 0), hook)
+	if got != StatusIdle {
+		t.Errorf("expected the stale %q to give way to the pane, got %q", hook.Status, got)
+	}
+}
+
+func TestDetectStatus_recentOutputOverridesHookState(t *testing.T) {
+	d := &Detector{}
+	hook := &HookState{Status: StatusWaiting, Time: time.Now().Add(-time.Minute)}
+	got, _ := d.detectStatus(context.Background(), "cd-api", time.Now(), hook)
+	if got != StatusActive {
+		t.Errorf("expected %q, got %q", StatusActive, got)
+	}
+}
+
+func TestReadHookState_readsTheConversation(t *testing.T) {
+	dir := t.TempDir()
+	writeStateFile(t, dir, "cd-api.json", `{"state":"idle","event":"Stop","time":1700000000,"session_id":"6f1c2a9e-0d4b-4e8a-9c3f-2b7d5e1a8c40"}`)
+	hs, ok := ReadHookState(dir, "cd-api")
+	if !ok || hs.Conversation != "6f1c2a9e-0d4b-4e8a-9c3f-2b7d5e1a8c40" {
+		t.Errorf("expected the session ID, got %+v", hs)
+	}
+}
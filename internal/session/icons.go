@@ -0,0 +1,52 @@
+package session
+
+import "github.com/seunggabi/claude-dashboard/internal/config"
+
+// IconSet holds the glyph shown before each status.
+type IconSet struct {
+	Active   string
+	Idle     string
+	Waiting  string
+	Terminal string
+	Exited   string
+	Crashed  string
+	Unknown  string
+}
+
+// iconSets are the presets selectable with the status_icons config option.
+// Some fonts draw the unicode glyphs double-width, which shifts the columns
+// after STATUS; the ASCII set is always one column wide.
+var iconSets = map[string]IconSet{
+	config.IconsUnicode: {Active: "●", Idle: "○", Waiting: "◎", Terminal: "⊘", Exited: "■", Crashed: "✗", Unknown: "?"},
+	// nf-fa-circle, nf-fa-circle_o, nf-fa-hourglass_half, nf-fa-terminal, nf-fa-stop, nf-fa-times, nf-fa-question
+	config.IconsNerd:  {Active: "\uf111", Idle: "\uf10c", Waiting: "\uf252", Terminal: "\uf120", Exited: "\uf04d", Crashed: "\uf00d", Unknown: "\uf128"},
+	config.IconsASCII: {Active: "*", Idle: "o", Waiting: "!", Terminal: "#", Exited: "-", Crashed: "x", Unknown: "?"},
+}
+
+// Icons returns the named icon set, or the unicode set for an unknown name.
+func Icons(name string) IconSet {
+	if set, ok := iconSets[name]; ok {
+		return set
+	}
+	return iconSets[config.IconsUnicode]
+}
+
+// StatusLabel returns the status with its glyph from icons.
+func (s *Session) StatusLabel(icons IconSet) string {
+	switch s.Status {
+	case StatusActive:
+		return icons.Active + " active"
+	case StatusIdle:
+		return icons.Idle + " idle"
+	case StatusWaiting:
+		return icons.Waiting + " waiting"
+	case StatusTerminal:
+		return icons.Terminal + " terminal"
+	case StatusExited:
+		return icons.Exited + " exited"
+	case StatusCrashed:
+		return icons.Crashed + " crashed"
+	default:
+		return icons.Unknown + " unknown"
+	}
+}
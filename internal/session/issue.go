@@ -0,0 +1,89 @@
+package session
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/seunggabi/claude-dashboard/internal/tmux"
+)
+
+// issueKey is what an issue key such as ENG-123 looks like.
+var issueKey = regexp.MustCompile(`^[A-Z][A-Z0-9]+-[0-9]+$`)
+
+// leadingIssueKey finds upper-case issue keys starting the text or a part of
+// it after /, [ or (, as in feature/ENG-123-retry or "[ENG-123] Fix it".
+var leadingIssueKey = regexp.MustCompile(`(?:^|[/\[(])([A-Z][A-Z0-9]+-[0-9]+)\b`)
+
+// ParseIssue reads an issue key typed by the user, upper-cased. An empty
+// text is no issue.
+func ParseIssue(text string) (string, error) {
+	key := strings.ToUpper(strings.TrimSpace(text))
+	if key != "" && !issueKey.MatchString(key) {
+		return "", fmt.Errorf("invalid issue key %q: use a key such as ENG-123", text)
+	}
+	return key, nil
+}
+
+// FindIssues returns the issue keys in text, such as a branch name or a
+// commit subject, in order and without repeats. With projects (key
+// prefixes such as ENG), their keys are found anywhere and in any case, as
+// in eng-123-retry; without, only upper-case keys leading the text or a
+// part of it, since text like UTF-8 looks like a key too.
+func FindIssues(text string, projects []string) []string {
+	var found []string
+	if len(projects) == 0 {
+		for _, m := range leadingIssueKey.FindAllStringSubmatch(text, -1) {
+			found = append(found, m[1])
+		}
+	} else {
+		quoted := make([]string, len(projects))
+		for i, p := range projects {
+			quoted[i] = regexp.QuoteMeta(p)
+		}
+		re := regexp.MustCompile(`(?i)(?:^|[^a-z0-9])((?:` + strings.Join(quoted, "|") + `)-[0-9]+)\b`)
+		for _, m := range re.FindAllStringSubmatch(text, -1) {
+			found = append(found, strings.ToUpper(m[1]))
+		}
+	}
+	var keys []string
+	seen := make(map[string]bool)
+	for _, k := range found {
+		if !seen[k] {
+			seen[k] = true
+			keys = append(keys, k)
+		}
+	}
+	return keys
+}
+
+// FindIssue returns the first issue key in branch, or "".
+func FindIssue(branch string, projects []string) string {
+	if keys := FindIssues(branch, projects); len(keys) > 0 {
+		return keys[0]
+	}
+	return ""
+}
+
+// SetIssue sets the issue key of a session, kept in a tmux session option
+// like its tags; an empty key goes back to the one in its branch name.
+func (m *Manager) SetIssue(ctx context.Context, name, key string) error {
+	if err := m.noTmux(); err != nil {
+		return err
+	}
+	if err := m.client.SetIssue(ctx, name, key); err != nil {
+		return fmt.Errorf("failed to set the issue of session %s: %w", name, err)
+	}
+	return nil
+}
+
+// listIssues returns the issue keys set on sessions of client's server; nil
+// when they cannot be listed.
+func listIssues(ctx context.Context, client *tmux.Client) map[string]string {
+	out, err := client.ListSessions(ctx, tmux.IssueFormat)
+	if err != nil {
+		return nil
+	}
+	return tmux.ParseIssues(out)
+}
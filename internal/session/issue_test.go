@@ -0,0 +1,51 @@
+package session
+
+import (
+	"reflect"
+	"testing"
+)
+
+// ---------------------------------------------------------------------------
+// ParseIssue
+// ---------------------------------------------------------------------------
+
+func TestParseIssue_upperCasesAndRejectsOthers(t *testing.T) {
+	if key, err := ParseIssue(" eng-42 "); err != nil || key != "ENG-42" {
+		t.Errorf("expected ENG-42, got %q, %v", key, err)
+	}
+	if key, err := ParseIssue(""); err != nil || key != "" {
+		t.Errorf("expected no issue, got %q, %v", key, err)
+	}
+	if _, err := ParseIssue("fix the login"); err == nil {
+		t.Error("expected text that is not a key to be rejected")
+	}
+}
+
+// ---------------------------------------------------------------------------
+// FindIssues
+// ---------------------------------------------------------------------------
+
+func TestFindIssues_leadingUpperCaseKeysWithoutProjects(t *testing.T) {
+	cases := map[string]string{
+		"feature/ENG-123-retry": "ENG-123",
+		"OPS-7":                 "OPS-7",
+		"feat/utf-8-names":      "",
+		"add-UTF-8-support":     "",
+		"eng-123-retry":         "",
+	}
+	for branch, want := range cases {
+		if got := FindIssue(branch, nil); got != want {
+			t.Errorf("%s: expected %q, got %q", branch, want, got)
+		}
+	}
+}
+
+func TestFindIssues_projectKeysAnywhere(t *testing.T) {
+	got := FindIssues("jane/eng-123-retry, see ENG-9 and OPS-1; ENG-123 again, and SHA-256", []string{"ENG", "OPS"})
+	if want := []string{"ENG-123", "ENG-9", "OPS-1"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+	if got := FindIssue("bump-postgres-16", []string{"ENG"}); got != "" {
+		t.Errorf("expected no key of another project, got %q", got)
+	}
+}
@@ -0,0 +1,59 @@
+package session
+
+import (
+	"context"
+	"time"
+
+	"github.com/seunggabi/claude-dashboard/internal/tmux"
+)
+
+// recoverLabels gives s the tags and issue kept in its tmux environment
+// when its session options have lost them, e.g. to a dashboard that did not
+// know them, and sets the options again. Each session is looked at once per
+// detector, so sessions without labels cost one tmux call, not one a poll.
+func (d *Detector) recoverLabels(ctx context.Context, s *Session, created time.Time) {
+	if len(s.Tags) > 0 && s.Issue != "" {
+		return
+	}
+	d.mu.Lock()
+	if d.checked == nil {
+		d.checked = make(map[string]time.Time)
+	}
+	seen, ok := d.checked[s.Name]
+	d.checked[s.Name] = created
+	d.mu.Unlock()
+	if ok && seen.Equal(created) {
+		return
+	}
+
+	env, err := d.client.Environment(ctx, s.Name)
+	if err != nil {
+		return
+	}
+	tags, issue := envLabels(env)
+	if len(s.Tags) == 0 && len(tags) > 0 {
+		if d.client.SetTags(ctx, s.Name, tags) == nil {
+			s.Tags = tags
+		}
+	}
+	if s.Issue == "" && issue != "" {
+		if d.client.SetIssue(ctx, s.Name, issue) == nil {
+			s.Issue = issue
+		}
+	}
+	if project := env[tmux.ProjectEnv]; project != "" {
+		_ = d.client.Mark(ctx, s.Name, project)
+	}
+}
+
+// envLabels reads the tags and issue key of a session environment. Values
+// that do not parse, e.g. edited by hand, are ignored.
+func envLabels(env map[string]string) (tags []string, issue string) {
+	if v := env[tmux.TagsEnv]; v != "" {
+		tags, _ = ParseTags(v)
+	}
+	if v := env[tmux.IssueEnv]; v != "" {
+		issue, _ = ParseIssue(v)
+	}
+	return tags, issue
+}
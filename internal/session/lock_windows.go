@@ -0,0 +1,39 @@
+//go:build windows
+
+package session
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"time"
+)
+
+// staleLock is how old a lock file is taken to be left by a process that
+// died holding it; writers hold it for milliseconds.
+const staleLock = 30 * time.Second
+
+// lockFile takes an exclusive lock on path by creating it, waiting for
+// other processes holding it. Windows has no flock, so the file itself is
+// the lock and one left by a crash is taken over once stale.
+func lockFile(path string) (unlock func(), err error) {
+	deadline := time.Now().Add(2 * staleLock)
+	for {
+		f, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0644)
+		if err == nil {
+			f.Close()
+			return func() { os.Remove(path) }, nil
+		}
+		if !errors.Is(err, os.ErrExist) {
+			return nil, err
+		}
+		if info, err := os.Stat(path); err == nil && time.Since(info.ModTime()) > staleLock {
+			os.Remove(path)
+			continue
+		}
+		if time.Now().After(deadline) {
+			return nil, fmt.Errorf("%s is held by another process", path)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
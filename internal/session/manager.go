@@ -2,10 +2,12 @@ package session
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/seunggabi/claude-dashboard/internal/conversation"
 	"github.com/seunggabi/claude-dashboard/internal/tmux"
@@ -41,23 +43,85 @@ func validateClaudeArgs(args string) error {
 	return nil
 }
 
+// ErrNoTmux is returned by operations that need tmux when the manager was
+// created without a client (tmux is not installed).
+var ErrNoTmux = errors.New("tmux is not available; only terminal sessions can be viewed")
+
 // Manager handles session CRUD operations.
+// A nil client runs in terminal-only mode: List reports Claude processes
+// running in terminals, and tmux operations return ErrNoTmux.
 type Manager struct {
 	client   *tmux.Client
 	detector *Detector
+	defsPath string // where created sessions are saved for Restore; empty for remote hosts
+	host     string // configured host name of a remote client; empty for local
+	events   *Tracker
+	source   Source // lists sessions in place of tmux; nil for real ones
 }
 
 // NewManager creates a new session manager.
 func NewManager(client *tmux.Client) *Manager {
-	return &Manager{
+	m := &Manager{
 		client:   client,
 		detector: NewDetector(client),
+		events:   NewTracker(),
+	}
+	if !client.IsRemote() {
+		m.defsPath = DefinitionsPath()
 	}
+	return m
+}
+
+// SetHost names the remote host the manager's client runs on. Listed
+// sessions are tagged with it.
+func (m *Manager) SetHost(name string) {
+	m.host = name
+}
+
+// SetDefinitionsPath sets the file created sessions are remembered in for
+// restore; empty to remember none, e.g. for sessions on a scratch server.
+func (m *Manager) SetDefinitionsPath(path string) {
+	m.defsPath = path
+}
+
+// SetTracker makes the manager report changes to t instead of its own
+// tracker, so one subscriber can follow several hosts.
+func (m *Manager) SetTracker(t *Tracker) {
+	m.events = t
+}
+
+// Events returns the tracker that every successful List reports changes to.
+func (m *Manager) Events() *Tracker {
+	return m.events
 }
 
 // List returns all Claude sessions.
 func (m *Manager) List(ctx context.Context) ([]Session, error) {
-	return m.detector.Detect(ctx)
+	var sessions []Session
+	if m.source != nil {
+		sessions = m.source.Sessions(time.Now())
+	} else {
+		var err error
+		if sessions, err = m.detector.Detect(ctx); err != nil {
+			return sessions, err
+		}
+	}
+	for i := range sessions {
+		sessions[i].Host = m.host
+	}
+	m.events.Observe(m.host, sessions, time.Now())
+	return sessions, nil
+}
+
+// noTmux returns why the manager cannot run tmux commands, or nil if it can.
+func (m *Manager) noTmux() error {
+	switch {
+	case m.source != nil:
+		return ErrReadOnly
+	case m.client == nil:
+		return ErrNoTmux
+	}
+	return nil
 }
 
 // Create creates a new Claude session with optional claude arguments.
@@ -68,8 +132,9 @@ func (m *Manager) Create(ctx context.Context, name, projectDir, claudeArgs strin
 		}
 	}
 
-	// Resolve and validate project directory
-	if projectDir != "" {
+	// Resolve and validate project directory. Remote directories cannot be
+	// checked from here; tmux falls back to the remote home if it is missing.
+	if projectDir != "" && !m.client.IsRemote() {
 		resolved, err := resolvePath(projectDir)
 		if err != nil {
 			return err
@@ -84,63 +149,222 @@ func (m *Manager) Create(ctx context.Context, name, projectDir, claudeArgs strin
 		projectDir = resolved
 	}
 
+	if err := m.noTmux(); err != nil {
+		return err
+	}
+
 	sessionName := SessionPrefix + name
 	command := "claude"
 	if claudeArgs != "" {
 		command = "claude " + claudeArgs
 	}
 
-	err := m.client.NewSession(ctx, sessionName, projectDir, command)
+	// The pane outlives claude, so a crash shows as crashed (and can be
+	// restarted) instead of taking the session with it.
+	err := m.client.NewLastingSession(ctx, sessionName, projectDir, command)
 	if err != nil {
 		return fmt.Errorf("failed to create session %s: %w", sessionName, err)
 	}
+	// Only labels for tmux's own lists; the session works without them.
+	project := extractProject(sessionName, projectDir)
+	_ = m.client.SetTitle(ctx, sessionName, project)
+	_ = m.client.Mark(ctx, sessionName, project)
+	if m.defsPath != "" {
+		// The session is running either way; failing to remember it only
+		// means it will not be restored.
+		_ = putDefinition(m.defsPath, Definition{Name: name, Path: projectDir, Args: claudeArgs, Created: time.Now()})
+	}
 	return nil
 }
 
-// Kill terminates a session.
+// Graceful kills give claude killGrace to exit, checking every killPoll.
+const (
+	killGrace = 10 * time.Second
+	killPoll  = 250 * time.Millisecond
+)
+
+// Kill ends a session gracefully: claude is interrupted and told to /exit,
+// so it is not cut off in the middle of a tool call, and the session is
+// killed once claude has quit or killGrace has passed.
 func (m *Manager) Kill(ctx context.Context, name string) error {
+	if err := m.noTmux(); err != nil {
+		return err
+	}
+	m.askToExit(ctx, name)
+	return m.ForceKill(ctx, name)
+}
+
+// askToExit interrupts claude in the session and types /exit, then waits
+// up to killGrace for it to quit. Sessions not running claude are left
+// alone.
+func (m *Manager) askToExit(ctx context.Context, name string) {
+	if !m.client.HasClaudeProcess(ctx, name, nil) {
+		return
+	}
+	_ = m.client.SendKey(ctx, name, "Escape")
+	time.Sleep(killPoll) // let claude leave the turn before typing
+	if err := m.client.SendKeys(ctx, name, "/exit"); err != nil {
+		return
+	}
+	deadline := time.Now().Add(killGrace)
+	for time.Now().Before(deadline) {
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(killPoll):
+		}
+		if !m.client.HasSession(ctx, name) || !m.client.HasClaudeProcess(ctx, name, nil) {
+			return
+		}
+	}
+}
+
+// ForceKill kills a session right away, whatever claude is doing. A session
+// already gone, e.g. having ended with claude, counts as killed.
+func (m *Manager) ForceKill(ctx context.Context, name string) error {
+	if err := m.noTmux(); err != nil {
+		return err
+	}
 	err := m.client.KillSession(ctx, name)
-	if err != nil {
+	if err != nil && m.client.HasSession(ctx, name) {
 		return fmt.Errorf("failed to kill session %s: %w", name, err)
 	}
+	// A session killed on purpose should not come back on restore.
+	if m.defsPath != "" && strings.HasPrefix(name, SessionPrefix) {
+		_ = removeDefinition(m.defsPath, strings.TrimPrefix(name, SessionPrefix))
+	}
+	return nil
+}
+
+// SendCommand types text into a session's pane and submits it, as if the
+// user had typed it at the Claude prompt.
+func (m *Manager) SendCommand(ctx context.Context, name, text string) error {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return fmt.Errorf("prompt is empty")
+	}
+	if strings.ContainsAny(text, "\r\n") {
+		return fmt.Errorf("prompt must be a single line")
+	}
+	if err := m.noTmux(); err != nil {
+		return err
+	}
+	if err := m.client.SendKeys(ctx, name, text); err != nil {
+		return fmt.Errorf("failed to send to session %s: %w", name, err)
+	}
+	return nil
+}
+
+// Interrupt presses Escape in a session, which stops Claude's current turn.
+func (m *Manager) Interrupt(ctx context.Context, name string) error {
+	if err := m.noTmux(); err != nil {
+		return err
+	}
+	if err := m.client.SendKey(ctx, name, "Escape"); err != nil {
+		return fmt.Errorf("failed to interrupt session %s: %w", name, err)
+	}
 	return nil
 }
 
 // GetLogs returns the captured pane content for a session.
 func (m *Manager) GetLogs(ctx context.Context, name string, lines int) (string, error) {
+	return m.logs(ctx, name, lines, false)
+}
+
+// GetColoredLogs is GetLogs keeping the pane's colors as escape sequences.
+func (m *Manager) GetColoredLogs(ctx context.Context, name string, lines int) (string, error) {
+	return m.logs(ctx, name, lines, true)
+}
+
+func (m *Manager) logs(ctx context.Context, name string, lines int, colors bool) (string, error) {
+	if m.source != nil {
+		return m.source.Pane(name), nil
+	}
+	if err := m.noTmux(); err != nil {
+		return "", err
+	}
 	if lines <= 0 {
 		lines = 1000
 	}
+	if colors {
+		return m.client.CapturePaneColors(ctx, name, lines)
+	}
 	return m.client.CapturePaneContent(ctx, name, lines)
 }
 
-// GetConversation returns the formatted conversation log for a session.
-func (m *Manager) GetConversation(path string, maxMessages int) (string, error) {
-	if path == "" {
-		return "", fmt.Errorf("no working directory for session")
+// GetConversationMessages returns the parsed conversation messages for a
+// session that match filter, with counts before and after filtering.
+func (m *Manager) GetConversationMessages(s Session, maxMessages int, filter conversation.Filter) ([]conversation.Message, conversation.Counts, error) {
+	if s.Path == "" {
+		return nil, conversation.Counts{}, fmt.Errorf("no working directory for session")
 	}
-	messages, err := conversation.ReadConversation(path, maxMessages)
+	path, err := s.Log()
 	if err != nil {
-		return "", err
+		return nil, conversation.Counts{}, err
 	}
-	if len(messages) == 0 {
-		return "No conversation messages found.", nil
+	return conversation.ReadLog(path, maxMessages, filter)
+}
+
+// GetToolTimeline returns the last maxEvents tool calls of a session's
+// conversation, oldest first.
+func (m *Manager) GetToolTimeline(s Session, maxEvents int) ([]conversation.ToolEvent, error) {
+	if s.Path == "" {
+		return nil, fmt.Errorf("no working directory for session")
+	}
+	path, err := s.Log()
+	if err != nil {
+		return nil, err
 	}
-	return conversation.FormatConversation(messages), nil
+	return conversation.ReadToolTimelineIn(path, maxEvents)
 }
 
-// FilterSessions filters sessions by query string.
+// Retitle sets the window name, pane title and tmux.MarkOption of a managed
+// session to its project name again, as Create does, e.g. after it was
+// renamed in tmux.
+func (m *Manager) Retitle(ctx context.Context, s Session) error {
+	if !s.Managed {
+		return fmt.Errorf("%s is not a tmux session", s.Name)
+	}
+	if err := m.noTmux(); err != nil {
+		return err
+	}
+	if err := m.client.SetTitle(ctx, s.Name, s.Project); err != nil {
+		return err
+	}
+	return m.client.Mark(ctx, s.Name, s.Project)
+}
+
+// TreeFilter is a tmux format true for the sessions the dashboard manages:
+// those marked with tmux.MarkOption and, from older versions, those named
+// with SessionPrefix. choose-tree -f takes it.
+var TreeFilter = fmt.Sprintf("#{||:#{%s},#{m:%s*,#{session_name}}}", tmux.MarkOption, SessionPrefix)
+
+// FilterSessions filters sessions by query, parsed with ParseQuery: terms
+// such as tag:frontend or status:waiting, and text matched against names,
+// hosts, projects, statuses, paths, window names and pane titles.
 func FilterSessions(sessions []Session, query string) []Session {
 	if query == "" {
 		return sessions
 	}
-	query = strings.ToLower(query)
+	q := ParseQuery(query)
 	filtered := make([]Session, 0)
 	for _, s := range sessions {
-		if strings.Contains(strings.ToLower(s.Name), query) ||
-			strings.Contains(strings.ToLower(s.Project), query) ||
-			strings.Contains(strings.ToLower(string(s.Status)), query) ||
-			strings.Contains(strings.ToLower(s.Path), query) {
+		if q.Match(s) {
+			filtered = append(filtered, s)
+		}
+	}
+	return filtered
+}
+
+// FilterByHost returns only the sessions on the named host. An empty host
+// matches every session; LocalHost matches sessions on this machine.
+func FilterByHost(sessions []Session, host string) []Session {
+	if host == "" {
+		return sessions
+	}
+	filtered := make([]Session, 0, len(sessions))
+	for _, s := range sessions {
+		if s.HostName() == host {
 			filtered = append(filtered, s)
 		}
 	}
@@ -257,3 +257,117 @@ func TestFilterSessions_partialMatchWorks(t *testing.T) {
 		t.Errorf("expected 3 matches for 'cd-' prefix, got %d", len(result))
 	}
 }
+
+func TestFilterSessions_matchesByWindowNameAndPaneTitle(t *testing.T) {
+	sessions := makeSessions()
+	sessions[1].Windows = []Window{{Index: 0, Name: "claude"}, {Index: 1, Name: "frontend"}}
+	sessions[2].Windows = []Window{{Index: 0, Name: "zsh", Titles: []string{"Deploy staging"}}}
+
+	if result := FilterSessions(sessions, "front"); len(result) != 1 || result[0].Name != "cd-beta" {
+		t.Errorf("expected cd-beta for a window name, got %v", result)
+	}
+	if result := FilterSessions(sessions, "staging"); len(result) != 1 || result[0].Name != "cd-gamma" {
+		t.Errorf("expected cd-gamma for a pane title, got %v", result)
+	}
+}
+
+// ---------------------------------------------------------------------------
+// Retitle
+// ---------------------------------------------------------------------------
+
+func TestRetitle_rejectsTerminalSessions(t *testing.T) {
+	m := NewManager(nil)
+	if err := m.Retitle(context.Background(), Session{Name: "claude-123", Project: "api"}); err == nil {
+		t.Error("expected an error for a terminal session")
+	}
+	if err := m.Retitle(context.Background(), Session{Name: "cd-api", Project: "api", Managed: true}); err != ErrNoTmux {
+		t.Errorf("expected ErrNoTmux without tmux, got %v", err)
+	}
+}
+
+// ---------------------------------------------------------------------------
+// FilterByHost
+// ---------------------------------------------------------------------------
+
+func TestFilterByHost_emptyHostReturnsAll(t *testing.T) {
+	sessions := makeSessions()
+	if got := FilterByHost(sessions, ""); len(got) != len(sessions) {
+		t.Errorf("expected %d sessions, got %d", len(sessions), len(got))
+	}
+}
+
+func TestFilterByHost_localMatchesSessionsWithoutHost(t *testing.T) {
+	sessions := append(makeSessions(), Session{Name: "cd-remote", Host: "devbox"})
+	got := FilterByHost(sessions, LocalHost)
+	if len(got) != 3 {
+		t.Fatalf("expected 3 local sessions, got %d", len(got))
+	}
+	for _, s := range got {
+		if s.Host != "" {
+			t.Errorf("expected only local sessions, got host %q", s.Host)
+		}
+	}
+}
+
+func TestFilterByHost_namedHostMatchesOnlyThatHost(t *testing.T) {
+	sessions := append(makeSessions(), Session{Name: "cd-remote", Host: "devbox"})
+	got := FilterByHost(sessions, "devbox")
+	if len(got) != 1 || got[0].Name != "cd-remote" {
+		t.Errorf("expected only cd-remote, got %+v", got)
+	}
+}
+
+// ---------------------------------------------------------------------------
+// SendCommand — validation
+// ---------------------------------------------------------------------------
+
+func TestSendCommand_emptyPromptReturnsError(t *testing.T) {
+	mgr := &Manager{client: nil} // nil client: validation fires before any tmux call
+	if err := mgr.SendCommand(context.Background(), "cd-test", "   "); err == nil {
+		t.Error("expected error for empty prompt, got nil")
+	}
+}
+
+func TestSendCommand_multiLinePromptReturnsError(t *testing.T) {
+	mgr := &Manager{client: nil}
+	err := mgr.SendCommand(context.Background(), "cd-test", "first\nsecond")
+	if err == nil || !strings.Contains(err.Error(), "single line") {
+		t.Errorf("expected single-line error, got %v", err)
+	}
+}
+
+// ---------------------------------------------------------------------------
+// Terminal-only mode (no tmux)
+// ---------------------------------------------------------------------------
+
+func TestManager_tmuxOperationsWithoutClientReturnErrNoTmux(t *testing.T) {
+	mgr := NewManager(nil)
+	ctx := context.Background()
+	if err := mgr.Create(ctx, "test", "", ""); err != ErrNoTmux {
+		t.Errorf("Create: expected ErrNoTmux, got %v", err)
+	}
+	if err := mgr.Kill(ctx, "cd-test"); err != ErrNoTmux {
+		t.Errorf("Kill: expected ErrNoTmux, got %v", err)
+	}
+	if err := mgr.ForceKill(ctx, "cd-test"); err != ErrNoTmux {
+		t.Errorf("ForceKill: expected ErrNoTmux, got %v", err)
+	}
+	if err := mgr.SendCommand(ctx, "cd-test", "hello"); err != ErrNoTmux {
+		t.Errorf("SendCommand: expected ErrNoTmux, got %v", err)
+	}
+	if _, err := mgr.GetLogs(ctx, "cd-test", 10); err != ErrNoTmux {
+		t.Errorf("GetLogs: expected ErrNoTmux, got %v", err)
+	}
+}
+
+func TestManager_listWithoutClientReturnsOnlyTerminalSessions(t *testing.T) {
+	sessions, err := NewManager(nil).List(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, s := range sessions {
+		if s.Managed {
+			t.Errorf("expected only terminal sessions, got managed %q", s.Name)
+		}
+	}
+}
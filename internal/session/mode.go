@@ -0,0 +1,141 @@
+package session
+
+import (
+	"context"
+	"strings"
+
+	"github.com/seunggabi/claude-dashboard/internal/monitor"
+	"github.com/seunggabi/claude-dashboard/internal/tmux"
+)
+
+// PermissionMode is how freely claude in a session may act: asking before
+// using tools, accepting file edits, only planning, or skipping permission
+// prompts altogether. The values are those of claude's --permission-mode.
+type PermissionMode string
+
+const (
+	ModeUnknown     PermissionMode = ""
+	ModeDefault     PermissionMode = "default"
+	ModeAcceptEdits PermissionMode = "acceptEdits"
+	ModePlan        PermissionMode = "plan"
+	ModeBypass      PermissionMode = "bypassPermissions"
+)
+
+// Label is the mode as shown in the MODE column.
+func (p PermissionMode) Label() string {
+	switch p {
+	case ModeDefault:
+		return "default"
+	case ModeAcceptEdits:
+		return "accept edits"
+	case ModePlan:
+		return "plan"
+	case ModeBypass:
+		return "skip perms"
+	}
+	return "-"
+}
+
+// ModeFromArgs returns the mode a claude command line starts claude in:
+// --dangerously-skip-permissions, --permission-mode, or ModeDefault.
+func ModeFromArgs(cmdline string) PermissionMode {
+	fields := strings.Fields(cmdline)
+	mode := ModeDefault
+	for i, f := range fields {
+		flag, value, inline := strings.Cut(f, "=")
+		switch flag {
+		case "--dangerously-skip-permissions":
+			return ModeBypass
+		case "--permission-mode":
+			if !inline && i+1 < len(fields) {
+				value = fields[i+1]
+			}
+			switch m := PermissionMode(value); m {
+			case ModeDefault, ModeAcceptEdits, ModePlan, ModeBypass:
+				mode = m
+			}
+		}
+	}
+	return mode
+}
+
+// paneModes are what claude's footer shows in each mode but the default,
+// which shows none.
+var paneModes = []struct {
+	marker string
+	mode   PermissionMode
+}{
+	{"bypass permissions on", ModeBypass},
+	{"plan mode on", ModePlan},
+	{"accept edits on", ModeAcceptEdits},
+}
+
+// paneModeLines is how many non-empty lines at the bottom of a pane are
+// looked at for the footer, so the conversation above is not mistaken
+// for it.
+const paneModeLines = 3
+
+// ModeFromPane returns the mode claude's footer shows at the bottom of pane
+// content, or false when it shows none.
+func ModeFromPane(content string) (PermissionMode, bool) {
+	lines := strings.Split(content, "\n")
+	seen := 0
+	for i := len(lines) - 1; i >= 0 && seen < paneModeLines; i-- {
+		line := strings.TrimSpace(lines[i])
+		if line == "" {
+			continue
+		}
+		seen++
+		for _, pm := range paneModes {
+			if strings.Contains(line, pm.marker) {
+				return pm.mode, true
+			}
+		}
+	}
+	return ModeUnknown, false
+}
+
+// claudeArgs returns the command line of the claude process pid runs, pid
+// itself or a descendant, or false if none does.
+func claudeArgs(pid string, table monitor.ProcessTable, procChildren map[string][]tmux.ProcEntry) (string, bool) {
+	if pid == "" {
+		return "", false
+	}
+	queue := []tmux.ProcEntry{{PID: pid, Args: table[pid].Args}}
+	visited := make(map[string]bool)
+	for len(queue) > 0 {
+		p := queue[0]
+		queue = queue[1:]
+		if visited[p.PID] {
+			continue
+		}
+		visited[p.PID] = true
+		if strings.Contains(strings.ToLower(p.Args), "claude") {
+			return p.Args, true
+		}
+		queue = append(queue, procChildren[p.PID]...)
+	}
+	return "", false
+}
+
+// ReadMode sets the mode of s, a tmux session, from its pane, which shows
+// the mode claude is in now (shift+tab cycles it). Without the footer it
+// keeps the mode of claude's command line, or when that was not read, as
+// when claude runs on another host, takes the arguments the session was
+// created with.
+func (m *Manager) ReadMode(ctx context.Context, s *Session) {
+	if !s.Managed || m.noTmux() != nil {
+		return
+	}
+	if content, err := m.client.CapturePaneContent(ctx, s.Name, 0); err == nil {
+		if mode, ok := ModeFromPane(content); ok {
+			s.Mode = mode
+			return
+		}
+	}
+	if s.Mode == ModeUnknown {
+		if d, ok := m.definition(s.Name); ok {
+			s.Mode = ModeFromArgs(d.Args)
+		}
+	}
+}
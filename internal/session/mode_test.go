@@ -0,0 +1,32 @@
+package session
+
+import "testing"
+
+func TestModeFromArgs(t *testing.T) {
+	tests := []struct {
+		cmdline string
+		want    PermissionMode
+	}{
+		{"claude", ModeDefault},
+		{"claude --model opus --dangerously-skip-permissions", ModeBypass},
+		{"node /usr/lib/claude --permission-mode plan", ModePlan},
+		{"claude --permission-mode=acceptEdits --resume abc", ModeAcceptEdits},
+		{"claude --permission-mode sometimes", ModeDefault},
+	}
+	for _, tt := range tests {
+		if got := ModeFromArgs(tt.cmdline); got != tt.want {
+			t.Errorf("ModeFromArgs(%q) = %q, want %q", tt.cmdline, got, tt.want)
+		}
+	}
+}
+
+func TestModeFromPane_onlyTheFooter(t *testing.T) {
+	pane := "● Switched to plan mode on request\n\n> \n  ⏸ plan mode on (shift+tab to cycle)\n\n"
+	if mode, ok := ModeFromPane(pane); !ok || mode != ModePlan {
+		t.Errorf("expected plan mode from the footer, got %q, %v", mode, ok)
+	}
+	pane = "  ⏵⏵ accept edits on (shift+tab to cycle)\nline\nline\nline\n> \n  ? for shortcuts\n"
+	if mode, ok := ModeFromPane(pane); ok {
+		t.Errorf("expected no mode from text above the footer, got %q", mode)
+	}
+}
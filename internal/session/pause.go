@@ -0,0 +1,17 @@
+package session
+
+import "github.com/seunggabi/claude-dashboard/internal/monitor"
+
+// processTree returns root and every process below it in table, parents
+// before their children.
+func processTree(root string, table monitor.ProcessTable) []string {
+	children := make(map[string][]string)
+	for _, e := range table {
+		children[e.PPID] = append(children[e.PPID], e.PID)
+	}
+	tree := []string{root}
+	for i := 0; i < len(tree); i++ {
+		tree = append(tree, children[tree[i]]...)
+	}
+	return tree
+}
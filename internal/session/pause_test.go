@@ -0,0 +1,21 @@
+package session
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/seunggabi/claude-dashboard/internal/monitor"
+)
+
+func TestProcessTree_parentsBeforeChildren(t *testing.T) {
+	table := monitor.ProcessTable{
+		"10": {PID: "10", PPID: "1", Args: "claude"},
+		"11": {PID: "11", PPID: "10", Args: "bash -c go test ./..."},
+		"12": {PID: "12", PPID: "11", Args: "go test ./..."},
+		"20": {PID: "20", PPID: "1", Args: "claude"},
+	}
+	got := strings.Join(processTree("10", table), " ")
+	if got != "10 11 12" {
+		t.Errorf("processTree = %s, want 10 11 12", got)
+	}
+}
@@ -0,0 +1,42 @@
+//go:build !windows
+
+package session
+
+import (
+	"fmt"
+	"strconv"
+	"syscall"
+
+	"github.com/seunggabi/claude-dashboard/internal/monitor"
+)
+
+// Pause stops the processes of the local session s, claude and whatever
+// its tools run, with SIGSTOP, so they use no CPU until Resume. The session
+// stays as it was, only frozen.
+func Pause(s Session) error {
+	return signalTree(s, syscall.SIGSTOP)
+}
+
+// Resume lets the processes of a session stopped by Pause run again.
+func Resume(s Session) error {
+	return signalTree(s, syscall.SIGCONT)
+}
+
+// signalTree sends sig to the process of s and every process below it.
+// Processes gone in the meantime are skipped; only failing to signal the
+// session's own process is an error.
+func signalTree(s Session, sig syscall.Signal) error {
+	if s.Host != "" || s.PID == "" {
+		return fmt.Errorf("only sessions on this machine with a known process can be paused")
+	}
+	for _, pid := range processTree(s.PID, monitor.GetProcessTable()) {
+		n, err := strconv.Atoi(pid)
+		if err != nil {
+			continue
+		}
+		if err := syscall.Kill(n, sig); err != nil && pid == s.PID {
+			return fmt.Errorf("cannot signal %s (pid %s): %w", s.Name, pid, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,19 @@
+//go:build windows
+
+package session
+
+import "errors"
+
+// errNoPause is returned on Windows, which cannot stop processes as
+// SIGSTOP does.
+var errNoPause = errors.New("pausing sessions is not supported on Windows")
+
+// Pause is not supported on Windows.
+func Pause(s Session) error {
+	return errNoPause
+}
+
+// Resume is not supported on Windows.
+func Resume(s Session) error {
+	return errNoPause
+}
@@ -0,0 +1,93 @@
+package session
+
+import "strings"
+
+// queryKeys are the fields a filter term can name, as in tag:frontend.
+var queryKeys = map[string]bool{"tag": true, "status": true, "host": true, "project": true, "name": true}
+
+// Query is a parsed filter. Terms naming a field, like tag:frontend or
+// status:waiting,idle, must each match, any of their comma-separated values
+// doing; the rest of the words are Text, matched as a whole against names,
+// hosts, projects, statuses, paths, window names and pane titles.
+type Query struct {
+	Fields map[string][]string // values by key, lower case
+	Text   string
+}
+
+// ParseQuery parses a filter typed in the filter bar. Words with a key that
+// is not a field, like a URL, are text.
+func ParseQuery(query string) Query {
+	var q Query
+	var text []string
+	for _, word := range strings.Fields(strings.ToLower(query)) {
+		key, value, ok := strings.Cut(word, ":")
+		if !ok || !queryKeys[key] {
+			text = append(text, word)
+			continue
+		}
+		if q.Fields == nil {
+			q.Fields = make(map[string][]string)
+		}
+		for _, v := range strings.Split(value, ",") {
+			if v != "" {
+				q.Fields[key] = append(q.Fields[key], v)
+			}
+		}
+		if _, ok := q.Fields[key]; !ok {
+			q.Fields[key] = nil // still being typed; matches everything
+		}
+	}
+	q.Text = strings.Join(text, " ")
+	return q
+}
+
+// Match reports whether s matches every term of q.
+func (q Query) Match(s Session) bool {
+	for key, values := range q.Fields {
+		if len(values) > 0 && !matchField(s, key, values) {
+			return false
+		}
+	}
+	if q.Text == "" {
+		return true
+	}
+	if strings.Contains(strings.ToLower(s.Name), q.Text) ||
+		strings.Contains(strings.ToLower(s.Host), q.Text) ||
+		strings.Contains(strings.ToLower(s.Project), q.Text) ||
+		strings.Contains(strings.ToLower(string(s.Status)), q.Text) ||
+		strings.Contains(strings.ToLower(s.Path), q.Text) {
+		return true
+	}
+	_, ok := MatchedWindow(s, q.Text)
+	return ok
+}
+
+// matchField reports whether the field key of s matches any of values:
+// tags exactly, statuses by prefix and the rest by substring.
+func matchField(s Session, key string, values []string) bool {
+	for _, v := range values {
+		switch key {
+		case "tag":
+			if s.HasTag(v) {
+				return true
+			}
+		case "status":
+			if strings.HasPrefix(string(s.Status), v) {
+				return true
+			}
+		case "host":
+			if strings.Contains(strings.ToLower(s.HostName()), v) {
+				return true
+			}
+		case "project":
+			if strings.Contains(strings.ToLower(s.Project), v) {
+				return true
+			}
+		case "name":
+			if strings.Contains(strings.ToLower(s.Name), v) {
+				return true
+			}
+		}
+	}
+	return false
+}
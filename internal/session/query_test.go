@@ -0,0 +1,52 @@
+package session
+
+import (
+	"reflect"
+	"testing"
+)
+
+// ---------------------------------------------------------------------------
+// ParseQuery
+// ---------------------------------------------------------------------------
+
+func TestParseQuery_fieldsAndText(t *testing.T) {
+	q := ParseQuery("Tag:Frontend status:waiting,idle fix login http://x")
+	want := map[string][]string{"tag": {"frontend"}, "status": {"waiting", "idle"}}
+	if !reflect.DeepEqual(q.Fields, want) {
+		t.Errorf("expected %v, got %v", want, q.Fields)
+	}
+	if q.Text != "fix login http://x" {
+		t.Errorf("expected the other words as text, got %q", q.Text)
+	}
+}
+
+// ---------------------------------------------------------------------------
+// Query.Match
+// ---------------------------------------------------------------------------
+
+func TestQueryMatch_everyTermMustMatch(t *testing.T) {
+	web := Session{Name: "cd-web", Project: "web", Status: StatusWaiting, Tags: []string{"frontend"}}
+	api := Session{Name: "cd-api", Project: "api", Status: StatusWaiting, Tags: []string{"backend"}}
+	ui := Session{Name: "cd-ui", Project: "ui", Status: StatusActive, Tags: []string{"frontend"}}
+
+	cases := []struct {
+		query string
+		want  []string
+	}{
+		{"tag:frontend status:wait", []string{"cd-web"}},
+		{"tag:frontend,backend", []string{"cd-web", "cd-api", "cd-ui"}},
+		{"status:waiting api", []string{"cd-api"}},
+		{"tag:", []string{"cd-web", "cd-api", "cd-ui"}},
+		{"host:local project:u", []string{"cd-ui"}},
+		{"tag:front", nil},
+	}
+	for _, tc := range cases {
+		var got []string
+		for _, s := range FilterSessions([]Session{web, api, ui}, tc.query) {
+			got = append(got, s.Name)
+		}
+		if !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("%q: expected %v, got %v", tc.query, tc.want, got)
+		}
+	}
+}
@@ -4,6 +4,11 @@ import (
 	"fmt"
 	"strings"
 	"time"
+
+	"github.com/seunggabi/claude-dashboard/internal/config"
+	"github.com/seunggabi/claude-dashboard/internal/conversation"
+	"github.com/seunggabi/claude-dashboard/internal/git"
+	"github.com/seunggabi/claude-dashboard/internal/testrun"
 )
 
 // Status represents the session state.
@@ -13,6 +18,8 @@ const (
 	StatusActive  Status = "active"
 	StatusIdle    Status = "idle"
 	StatusWaiting Status = "waiting"
+	StatusExited  Status = "exited"  // claude quit; its pane is done or back at a shell
+	StatusCrashed Status = "crashed" // claude exited with an error and its pane died
 	StatusUnknown  Status = "unknown"
 	StatusTerminal Status = "terminal"
 )
@@ -29,7 +36,81 @@ type Session struct {
 	CPU       float64
 	Memory    float64
 	Path      string
-	Managed   bool // true = tmux session (can attach/detach), false = terminal process (read-only)
+	Managed   bool   // true = tmux session (can attach/detach), false = terminal process (read-only)
+	Host      string // remote host name from config; empty for the local machine
+
+	// Conversation is the ID of claude's current conversation, reported by
+	// the hooks or given on claude's command line; "" when unknown, and
+	// Log falls back to the latest log of Path.
+	Conversation string
+
+	// Mode is claude's permission mode, from its command line, refined from
+	// the pane only while the mode column is shown (see Manager.ReadMode).
+	Mode PermissionMode
+
+	// Question is what a waiting session asks, read from its pane, e.g.
+	// "Do you want to proceed?"; "" otherwise.
+	Question string
+
+	// LastPrompt is the user's latest prompt, read from the conversation
+	// log only when the dashboard shows detailed rows.
+	LastPrompt string
+
+	// Spend of the current conversation, read only when a spend cap is set,
+	// and how full its context window is, read with it.
+	Spend   conversation.Spend
+	Context conversation.ContextUse
+
+	// LogSize is the size of the current conversation log, read only while
+	// the activity column is shown, and Output what the session wrote at
+	// each of the latest refreshes, oldest first, for that column.
+	LogSize int64
+	Output  []int64
+
+	// Git state of Path; zero outside a repository and for remote sessions.
+	Git git.Status
+
+	// Test is the latest test result of the session, from its configured
+	// test command or its pane; zero if none was seen.
+	Test testrun.Result
+
+	// Conflict lists the uncommitted files this session and others working
+	// in the same repository both wrote; zero when there are none.
+	Conflict Conflict
+
+	// Windows of the tmux session, with their pane titles.
+	Windows []Window
+
+	// Tags the user gave the session, lower case; see ParseTags.
+	Tags []string
+
+	// Issue is the key of the issue the session works on, e.g. ENG-123:
+	// set by the user, or else found in the branch name.
+	Issue string
+}
+
+// LocalHost is the host name used for sessions on the local machine.
+const LocalHost = "local"
+
+// HostName returns the configured host name, or LocalHost for local sessions.
+func (s *Session) HostName() string {
+	if s.Host == "" {
+		return LocalHost
+	}
+	return s.Host
+}
+
+// HostStatus summarises one host's state for the multi-host rollup.
+type HostStatus struct {
+	Name     string
+	Sessions int
+	Err      error // non-nil when the host could not be queried
+	Pending  bool  // not answered yet
+}
+
+// Reachable reports whether the host answered the last refresh.
+func (h HostStatus) Reachable() bool {
+	return h.Err == nil
 }
 
 // Uptime returns the human-readable uptime string.
@@ -49,20 +130,15 @@ func (s *Session) Uptime() string {
 	return fmt.Sprintf("%dd%dh", days, hours)
 }
 
-// StatusString returns a colored status string.
+// StatusString returns the status with its default (unicode) glyph.
 func (s *Session) StatusString() string {
-	switch s.Status {
-	case StatusActive:
-		return "● active"
-	case StatusIdle:
-		return "○ idle"
-	case StatusWaiting:
-		return "◎ waiting"
-	case StatusTerminal:
-		return "⊘ terminal"
-	default:
-		return "? unknown"
-	}
+	return s.StatusLabel(Icons(config.IconsUnicode))
+}
+
+// Exited reports whether claude is no longer running in the session, having
+// exited or crashed.
+func (s *Session) Exited() bool {
+	return s.Status == StatusExited || s.Status == StatusCrashed
 }
 
 // DisplayName returns the display name without the cd- prefix.
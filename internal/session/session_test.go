@@ -3,6 +3,8 @@ package session
 import (
 	"testing"
 	"time"
+
+	"github.com/seunggabi/claude-dashboard/internal/config"
 )
 
 // newSessionWithAge creates a Session whose StartedAt is age ago from now.
@@ -134,3 +136,34 @@ func TestSessionPrefix_value(t *testing.T) {
 		t.Errorf("expected SessionPrefix to be %q, got %q", "cd-", SessionPrefix)
 	}
 }
+
+// ---------------------------------------------------------------------------
+// HostName
+// ---------------------------------------------------------------------------
+
+func TestHostName_emptyHostIsLocal(t *testing.T) {
+	s := &Session{}
+	if got := s.HostName(); got != LocalHost {
+		t.Errorf("expected %q, got %q", LocalHost, got)
+	}
+}
+
+func TestHostName_remoteHostIsReturned(t *testing.T) {
+	s := &Session{Host: "devbox"}
+	if got := s.HostName(); got != "devbox" {
+		t.Errorf("expected %q, got %q", "devbox", got)
+	}
+}
+
+func TestStatusLabel_asciiIcons(t *testing.T) {
+	s := &Session{Status: StatusWaiting}
+	if got := s.StatusLabel(Icons(config.IconsASCII)); got != "! waiting" {
+		t.Errorf("expected %q, got %q", "! waiting", got)
+	}
+}
+
+func TestIcons_unknownNameFallsBackToUnicode(t *testing.T) {
+	if got := Icons("emoji"); got != Icons(config.IconsUnicode) {
+		t.Errorf("expected the unicode set, got %+v", got)
+	}
+}
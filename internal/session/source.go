@@ -0,0 +1,25 @@
+package session
+
+import (
+	"errors"
+	"time"
+)
+
+// Source stands in for tmux and the process table, e.g. for the demo: a
+// manager created with NewSourceManager lists its sessions and reads their
+// panes from it.
+type Source interface {
+	Sessions(now time.Time) []Session
+	Pane(name string) string
+}
+
+// ErrReadOnly is returned by operations that would change the sessions of
+// a Source.
+var ErrReadOnly = errors.New("demo sessions cannot be changed")
+
+// NewSourceManager creates a manager whose sessions come from src. It
+// reports changes like any other, but nothing can be created, killed or
+// typed into.
+func NewSourceManager(src Source) *Manager {
+	return &Manager{source: src, events: NewTracker()}
+}
@@ -0,0 +1,45 @@
+package session
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// fakeSource lists fixed sessions.
+type fakeSource []Session
+
+func (f fakeSource) Sessions(time.Time) []Session { return f }
+func (f fakeSource) Pane(name string) string      { return "pane of " + name }
+
+// ---------------------------------------------------------------------------
+// NewSourceManager
+// ---------------------------------------------------------------------------
+
+func TestSourceManager_listsAndReadsTheSource(t *testing.T) {
+	mgr := NewSourceManager(fakeSource{{Name: "cd-api", Status: StatusActive, Managed: true}})
+	sessions, err := mgr.List(context.Background())
+	if err != nil || len(sessions) != 1 || sessions[0].Name != "cd-api" {
+		t.Fatalf("expected the source's session, got %v, %v", sessions, err)
+	}
+	if pane, err := mgr.GetLogs(context.Background(), "cd-api", 10); err != nil || pane != "pane of cd-api" {
+		t.Errorf("expected the source's pane, got %q, %v", pane, err)
+	}
+}
+
+func TestSourceManager_changesAreRefused(t *testing.T) {
+	mgr := NewSourceManager(fakeSource{{Name: "cd-api", Managed: true}})
+	ctx := context.Background()
+	if err := mgr.Create(ctx, "web", "", ""); err != ErrReadOnly {
+		t.Errorf("Create: expected ErrReadOnly, got %v", err)
+	}
+	if err := mgr.Kill(ctx, "cd-api"); err != ErrReadOnly {
+		t.Errorf("Kill: expected ErrReadOnly, got %v", err)
+	}
+	if err := mgr.SendCommand(ctx, "cd-api", "hello"); err != ErrReadOnly {
+		t.Errorf("SendCommand: expected ErrReadOnly, got %v", err)
+	}
+	if err := mgr.SetTags(ctx, "cd-api", []string{"x"}); err != ErrReadOnly {
+		t.Errorf("SetTags: expected ErrReadOnly, got %v", err)
+	}
+}
@@ -0,0 +1,207 @@
+package session
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/seunggabi/claude-dashboard/internal/config"
+	"gopkg.in/yaml.v3"
+)
+
+// Definition is what is needed to recreate a managed session, saved when
+// the session is created so it can be restored after a reboot.
+type Definition struct {
+	Name    string    `yaml:"name"` // without SessionPrefix
+	Path    string    `yaml:"path"`
+	Args    string    `yaml:"args,omitempty"`
+	Created time.Time `yaml:"created"`
+}
+
+// definitionsFile is the YAML layout of the definitions file.
+type definitionsFile struct {
+	Sessions []Definition `yaml:"sessions"`
+}
+
+// DefinitionsPath returns the file session definitions are kept in.
+func DefinitionsPath() string {
+	return filepath.Join(config.ConfigDir(), "sessions.yaml")
+}
+
+// LoadDefinitions reads the session definitions at path. A missing file
+// means no definitions. A file that does not parse, e.g. edited by hand,
+// gives way to the backup kept of the last good one.
+func LoadDefinitions(path string) ([]Definition, error) {
+	defs, err := readDefinitions(path)
+	if err == nil || errors.Is(err, os.ErrNotExist) {
+		return defs, nil
+	}
+	if backup, berr := readDefinitions(backupPath(path)); berr == nil {
+		return backup, nil
+	}
+	return nil, err
+}
+
+// readDefinitions reads the definitions file at path.
+func readDefinitions(path string) ([]Definition, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var f definitionsFile
+	if err := yaml.Unmarshal(data, &f); err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", path, err)
+	}
+	return f.Sessions, nil
+}
+
+// backupPath returns where the last good definitions file is kept.
+func backupPath(path string) string {
+	return path + ".bak"
+}
+
+// saveDefinitions writes defs to path, keeping the file it replaces as the
+// backup if that one was good. Each file is written whole and renamed into
+// place, so a crash cannot leave either half written.
+func saveDefinitions(path string, defs []Definition) error {
+	data, err := yaml.Marshal(definitionsFile{Sessions: defs})
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	if old, err := os.ReadFile(path); err == nil {
+		if yaml.Unmarshal(old, &definitionsFile{}) != nil {
+			// Kept aside for a look; the definitions come from the backup.
+			_ = os.Rename(path, path+".corrupt")
+		} else if err := writeAtomic(backupPath(path), old); err != nil {
+			return err
+		}
+	}
+	return writeAtomic(path, data)
+}
+
+// writeAtomic replaces path with data through a temporary file in the same
+// directory, synced before it is renamed over path.
+func writeAtomic(path string, data []byte) error {
+	f, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*")
+	if err != nil {
+		return err
+	}
+	tmp := f.Name()
+	_, err = f.Write(data)
+	if err == nil {
+		err = f.Sync()
+	}
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err == nil {
+		err = os.Chmod(tmp, 0644)
+	}
+	if err == nil {
+		err = os.Rename(tmp, path)
+	}
+	if err != nil {
+		os.Remove(tmp)
+	}
+	return err
+}
+
+// updateDefinitions applies change to the definitions at path under a lock,
+// so the dashboard, serve and CLI commands changing them at once do not
+// lose each other's changes. The file is only written when change reports
+// a change.
+func updateDefinitions(path string, change func([]Definition) ([]Definition, bool)) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	unlock, err := lockFile(path + ".lock")
+	if err != nil {
+		return fmt.Errorf("cannot lock %s: %w", path, err)
+	}
+	defer unlock()
+	defs, err := LoadDefinitions(path)
+	if err != nil {
+		return err
+	}
+	out, changed := change(defs)
+	if !changed {
+		return nil
+	}
+	return saveDefinitions(path, out)
+}
+
+// putDefinition adds d to the definitions at path, replacing any with the
+// same name.
+func putDefinition(path string, d Definition) error {
+	return updateDefinitions(path, func(defs []Definition) ([]Definition, bool) {
+		out := defs[:0]
+		for _, old := range defs {
+			if old.Name != d.Name {
+				out = append(out, old)
+			} else if !old.Created.IsZero() {
+				d.Created = old.Created // recreated, not new
+			}
+		}
+		return append(out, d), true
+	})
+}
+
+// removeDefinition drops the definition called name, if there is one.
+func removeDefinition(path, name string) error {
+	return updateDefinitions(path, func(defs []Definition) ([]Definition, bool) {
+		out := defs[:0]
+		for _, d := range defs {
+			if d.Name != name {
+				out = append(out, d)
+			}
+		}
+		return out, len(out) != len(defs)
+	})
+}
+
+// MissingDefinitions returns the saved definitions whose tmux session does
+// not exist.
+func (m *Manager) MissingDefinitions(ctx context.Context) ([]Definition, error) {
+	if err := m.noTmux(); err != nil {
+		return nil, err
+	}
+	defs, err := LoadDefinitions(m.defsPath)
+	if err != nil || len(defs) == 0 {
+		return nil, err
+	}
+	out, err := m.client.ListSessions(ctx, "#{session_name}")
+	if err != nil {
+		return nil, err
+	}
+	running := make(map[string]bool)
+	for _, name := range strings.Split(out, "\n") {
+		running[name] = true
+	}
+	var missing []Definition
+	for _, d := range defs {
+		if !running[SessionPrefix+d.Name] {
+			missing = append(missing, d)
+		}
+	}
+	return missing, nil
+}
+
+// Restore recreates every defined session that is missing from tmux. The
+// result has one item per missing session; the error is only for failing
+// to find out which sessions are missing.
+func (m *Manager) Restore(ctx context.Context) (BulkResult, error) {
+	missing, err := m.MissingDefinitions(ctx)
+	if err != nil {
+		return BulkResult{Op: "Restored"}, err
+	}
+	r := m.CreateMany(ctx, missing)
+	r.Op = "Restored"
+	return r, nil
+}
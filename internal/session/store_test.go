@@ -0,0 +1,128 @@
+package session
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+// ---------------------------------------------------------------------------
+// Definitions store
+// ---------------------------------------------------------------------------
+
+func TestLoadDefinitions_missingFileIsEmpty(t *testing.T) {
+	defs, err := LoadDefinitions(filepath.Join(t.TempDir(), "sessions.yaml"))
+	if err != nil || defs != nil {
+		t.Errorf("expected no definitions and no error, got %v, %v", defs, err)
+	}
+}
+
+func TestPutDefinition_replacesByNameAndKeepsCreated(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "sessions.yaml")
+	first := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	if err := putDefinition(path, Definition{Name: "api", Path: "/old", Created: first}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := putDefinition(path, Definition{Name: "web", Path: "/web"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := putDefinition(path, Definition{Name: "api", Path: "/new", Args: "--model opus", Created: time.Now()}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	defs, err := LoadDefinitions(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(defs) != 2 {
+		t.Fatalf("expected 2 definitions, got %+v", defs)
+	}
+	api := defs[1]
+	if api.Name != "api" || api.Path != "/new" || api.Args != "--model opus" || !api.Created.Equal(first) {
+		t.Errorf("unexpected definition: %+v", api)
+	}
+}
+
+func TestRemoveDefinition_dropsOnlyThatName(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "sessions.yaml")
+	for _, name := range []string{"api", "web"} {
+		if err := putDefinition(path, Definition{Name: name}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if err := removeDefinition(path, "api"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defs, _ := LoadDefinitions(path)
+	if len(defs) != 1 || defs[0].Name != "web" {
+		t.Errorf("expected only web left, got %+v", defs)
+	}
+}
+
+func TestPutDefinition_concurrentWritersLoseNothing(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "sessions.yaml")
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			if err := putDefinition(path, Definition{Name: fmt.Sprintf("s%d", i)}); err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+		}(i)
+	}
+	wg.Wait()
+	defs, err := LoadDefinitions(path)
+	if err != nil || len(defs) != 20 {
+		t.Errorf("expected all 20 definitions, got %d, %v", len(defs), err)
+	}
+	if tmps, _ := filepath.Glob(filepath.Join(filepath.Dir(path), ".sessions.yaml.*")); len(tmps) != 0 {
+		t.Errorf("expected no temporary files left, got %v", tmps)
+	}
+}
+
+func TestLoadDefinitions_corruptFileFallsBackToBackup(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "sessions.yaml")
+	for _, name := range []string{"api", "web"} {
+		if err := putDefinition(path, Definition{Name: name}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	os.WriteFile(path, []byte("sessions: [{name: api\n"), 0644)
+
+	defs, err := LoadDefinitions(path)
+	if err != nil || len(defs) != 1 || defs[0].Name != "api" {
+		t.Fatalf("expected the backup's api, got %+v, %v", defs, err)
+	}
+	if err := putDefinition(path, Definition{Name: "ui"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defs, _ = readDefinitions(path)
+	if len(defs) != 2 || defs[0].Name != "api" || defs[1].Name != "ui" {
+		t.Errorf("expected the backup carried on with ui, got %+v", defs)
+	}
+	if _, err := os.Stat(path + ".corrupt"); err != nil {
+		t.Errorf("expected the corrupt file kept aside: %v", err)
+	}
+	if backup, _ := readDefinitions(backupPath(path)); len(backup) != 1 {
+		t.Errorf("expected the good backup kept rather than the corrupt file, got %+v", backup)
+	}
+}
+
+func TestLoadDefinitions_corruptWithoutBackupIsAnError(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "sessions.yaml")
+	os.WriteFile(path, []byte("sessions: {"), 0644)
+	if _, err := LoadDefinitions(path); err == nil {
+		t.Error("expected an error")
+	}
+}
+
+func TestManager_restoreWithoutClientReturnsErrNoTmux(t *testing.T) {
+	if _, err := NewManager(nil).Restore(context.Background()); err != ErrNoTmux {
+		t.Errorf("expected ErrNoTmux, got %v", err)
+	}
+}
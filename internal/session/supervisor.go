@@ -0,0 +1,96 @@
+package session
+
+import (
+	"context"
+	"fmt"
+)
+
+// Supervisor picks crashed sessions to restart claude in, each at most
+// limit times. Sessions past the limit are left crashed.
+type Supervisor struct {
+	limit    int
+	restarts map[string]int // by session name
+}
+
+// NewSupervisor returns a supervisor restarting each session at most limit
+// times.
+func NewSupervisor(limit int) *Supervisor {
+	return &Supervisor{limit: limit, restarts: make(map[string]int)}
+}
+
+// Due returns the local crashed sessions in sessions to restart now and
+// counts a restart for each. Counts of sessions that are gone are dropped,
+// so a new session of the same name starts afresh.
+func (sv *Supervisor) Due(sessions []Session) []Session {
+	seen := make(map[string]bool, len(sessions))
+	var due []Session
+	for _, s := range sessions {
+		if s.Host != "" {
+			continue
+		}
+		seen[s.Name] = true
+		if s.Status == StatusCrashed && sv.restarts[s.Name] < sv.limit {
+			sv.restarts[s.Name]++
+			due = append(due, s)
+		}
+	}
+	for name := range sv.restarts {
+		if !seen[name] {
+			delete(sv.restarts, name)
+		}
+	}
+	return due
+}
+
+// Restarts returns how many times claude was restarted in the session name.
+func (sv *Supervisor) Restarts(name string) int {
+	return sv.restarts[name]
+}
+
+// Restart runs claude again in the session s it exited or crashed in, with
+// the arguments the session was created with, replacing its dead pane or
+// shell.
+func (m *Manager) Restart(ctx context.Context, s Session) error {
+	if err := m.noTmux(); err != nil {
+		return err
+	}
+	command := "claude"
+	if args := m.definitionArgs(s.Name); args != "" {
+		command += " " + args
+	}
+	if err := m.client.RespawnPane(ctx, s.Name, s.Path, command); err != nil {
+		return fmt.Errorf("failed to restart claude in %s: %w", s.Name, err)
+	}
+	return nil
+}
+
+// RestartMany restarts claude in each session, carrying on past failures.
+func (m *Manager) RestartMany(ctx context.Context, sessions []Session) BulkResult {
+	r := BulkResult{Op: "Restarted"}
+	for _, s := range sessions {
+		r.Add(s.Name, m.Restart(ctx, s))
+	}
+	return r
+}
+
+// definitionArgs returns the claude arguments the session name was created
+// with, or "" if it was not created by the dashboard.
+func (m *Manager) definitionArgs(name string) string {
+	d, _ := m.definition(name)
+	return d.Args
+}
+
+// definition returns the saved definition of the session name, or false if
+// it was not created by the dashboard.
+func (m *Manager) definition(name string) (Definition, bool) {
+	if m.defsPath == "" {
+		return Definition{}, false
+	}
+	defs, _ := LoadDefinitions(m.defsPath)
+	for _, d := range defs {
+		if SessionPrefix+d.Name == name {
+			return d, true
+		}
+	}
+	return Definition{}, false
+}
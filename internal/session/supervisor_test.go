@@ -0,0 +1,99 @@
+package session
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/seunggabi/claude-dashboard/internal/tmux"
+)
+
+// ---------------------------------------------------------------------------
+// Supervisor
+// ---------------------------------------------------------------------------
+
+func TestSupervisor_restartsLocalCrashedSessionsUpToTheLimit(t *testing.T) {
+	sv := NewSupervisor(2)
+	sessions := []Session{
+		{Name: "cd-api", Status: StatusCrashed},
+		{Name: "cd-web", Status: StatusIdle},
+		{Name: "cd-gpu", Host: "devbox", Status: StatusCrashed},
+	}
+	for i := 0; i < 2; i++ {
+		if got := names(sv.Due(sessions)); len(got) != 1 || got[0] != "cd-api" {
+			t.Fatalf("round %d: expected cd-api, got %v", i, got)
+		}
+	}
+	if got := sv.Due(sessions); len(got) != 0 {
+		t.Errorf("expected nothing past the limit, got %v", names(got))
+	}
+	if sv.Restarts("cd-api") != 2 {
+		t.Errorf("expected 2 restarts, got %d", sv.Restarts("cd-api"))
+	}
+
+	// A session that is gone is forgotten.
+	sv.Due(nil)
+	if got := names(sv.Due(sessions)); len(got) != 1 {
+		t.Errorf("expected a new cd-api to be restarted, got %v", got)
+	}
+}
+
+func TestRestart_withoutClientReturnsErrNoTmux(t *testing.T) {
+	if err := NewManager(nil).Restart(context.Background(), Session{Name: "cd-api"}); err != ErrNoTmux {
+		t.Errorf("expected ErrNoTmux, got %v", err)
+	}
+}
+
+func TestCreate_killedClaudeShowsCrashed(t *testing.T) {
+	client, err := tmux.NewSocketClient(fmt.Sprintf("cd-crash-test-%d", os.Getpid()))
+	if err != nil {
+		t.Skip("tmux not installed")
+	}
+	home, bin := t.TempDir(), t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("PATH", bin+string(os.PathListSeparator)+os.Getenv("PATH"))
+	if err := os.WriteFile(filepath.Join(bin, "claude"), []byte("#!/bin/sh\nexec sleep 30\n"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	m := NewManager(client)
+	if err := m.Create(ctx, "api", home, ""); err != nil {
+		t.Skipf("cannot start a tmux server: %v", err)
+	}
+	defer func() { _ = client.KillServer(ctx) }()
+
+	pid, err := client.GetSessionPID(ctx, "cd-api")
+	if err != nil {
+		t.Fatalf("GetSessionPID: %v", err)
+	}
+	n, _ := strconv.Atoi(pid)
+	p, err := os.FindProcess(n)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := p.Kill(); err != nil {
+		t.Fatalf("killing claude: %v", err)
+	}
+
+	var got Status
+	for deadline := time.Now().Add(5 * time.Second); time.Now().Before(deadline); time.Sleep(50 * time.Millisecond) {
+		sessions, err := m.List(ctx)
+		if err != nil {
+			t.Fatalf("List: %v", err)
+		}
+		got = ""
+		for _, s := range sessions {
+			if s.Name == "cd-api" {
+				got = s.Status
+			}
+		}
+		if got == StatusCrashed {
+			return
+		}
+	}
+	t.Errorf("expected the session to stay and show %q, got %q", StatusCrashed, got)
+}
@@ -0,0 +1,65 @@
+package session
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/seunggabi/claude-dashboard/internal/tmux"
+)
+
+// tagPattern is what a tag may look like; tags are kept lower case.
+var tagPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_.-]*$`)
+
+// ParseTags reads tags separated by commas or spaces, lower-cased and
+// without repeats. A tag that is not letters, digits, _ . or - is an error.
+func ParseTags(text string) ([]string, error) {
+	var tags []string
+	seen := make(map[string]bool)
+	for _, tag := range strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return r == ',' || r == ' ' || r == '\t'
+	}) {
+		tag = strings.TrimPrefix(tag, "#")
+		if !tagPattern.MatchString(tag) {
+			return nil, fmt.Errorf("invalid tag %q: use letters, digits, _ . or -", tag)
+		}
+		if !seen[tag] {
+			seen[tag] = true
+			tags = append(tags, tag)
+		}
+	}
+	return tags, nil
+}
+
+// HasTag reports whether s is tagged with tag.
+func (s *Session) HasTag(tag string) bool {
+	for _, t := range s.Tags {
+		if t == tag {
+			return true
+		}
+	}
+	return false
+}
+
+// SetTags replaces the tags of a session, kept in a tmux session option so
+// they last as long as the session.
+func (m *Manager) SetTags(ctx context.Context, name string, tags []string) error {
+	if err := m.noTmux(); err != nil {
+		return err
+	}
+	if err := m.client.SetTags(ctx, name, tags); err != nil {
+		return fmt.Errorf("failed to tag session %s: %w", name, err)
+	}
+	return nil
+}
+
+// listTags returns the tags of every session on client's server; nil when
+// they cannot be listed, which only costs filtering by them.
+func listTags(ctx context.Context, client *tmux.Client) map[string][]string {
+	out, err := client.ListSessions(ctx, tmux.TagsFormat)
+	if err != nil {
+		return nil
+	}
+	return tmux.ParseTags(out)
+}
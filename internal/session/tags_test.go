@@ -0,0 +1,50 @@
+package session
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/seunggabi/claude-dashboard/internal/tmux"
+)
+
+// ---------------------------------------------------------------------------
+// ParseTags
+// ---------------------------------------------------------------------------
+
+func TestParseTags_commasOrSpacesLowerCasedOnce(t *testing.T) {
+	tags, err := ParseTags("Frontend, #urgent  frontend,v1.2")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := []string{"frontend", "urgent", "v1.2"}; !reflect.DeepEqual(tags, want) {
+		t.Errorf("expected %v, got %v", want, tags)
+	}
+	if tags, err := ParseTags("  "); err != nil || tags != nil {
+		t.Errorf("expected no tags, got %v (%v)", tags, err)
+	}
+}
+
+func TestParseTags_rejectsOddCharacters(t *testing.T) {
+	for _, text := range []string{"a|b", "ui;rm", "-x"} {
+		if _, err := ParseTags(text); err == nil {
+			t.Errorf("%q: expected an error", text)
+		}
+	}
+}
+
+// ---------------------------------------------------------------------------
+// envLabels
+// ---------------------------------------------------------------------------
+
+func TestEnvLabels_tagsAndIssueOfEnvironment(t *testing.T) {
+	tags, issue := envLabels(map[string]string{
+		tmux.TagsEnv:  "backend,urgent",
+		tmux.IssueEnv: "eng-12",
+	})
+	if want := []string{"backend", "urgent"}; !reflect.DeepEqual(tags, want) || issue != "ENG-12" {
+		t.Errorf("expected %v and ENG-12, got %v and %q", want, tags, issue)
+	}
+	if tags, issue := envLabels(map[string]string{tmux.TagsEnv: "a b!", tmux.IssueEnv: "nope"}); tags != nil || issue != "" {
+		t.Errorf("expected values that do not parse ignored, got %v and %q", tags, issue)
+	}
+}
@@ -0,0 +1,108 @@
+package session
+
+import (
+	"sort"
+	"time"
+
+	"github.com/seunggabi/claude-dashboard/internal/config"
+)
+
+// Transition is a change in a session's life that webhooks are sent for.
+type Transition struct {
+	Kind    string  // one of config.WebhookEvents
+	Session Session // state at the transition; for crashed and finished, the last state seen
+	Idle    time.Duration
+	Worked  time.Duration // for done, how long the session worked
+	Quiet   []Session     // for all_quiet, the sessions idle or waiting
+	At      time.Time
+}
+
+// Transitions follows a tracker's events to find transitions: a session
+// starting to wait, going idle after working for longTask (done), staying
+// idle for idleAfter, claude exiting in it or the session going away while
+// working (crashed), or going away otherwise (finished), and the tracker
+// finding every session idle or waiting (all_quiet). Idle time counts from
+// the change to idle, so Tick has to be called now and then to report it.
+type Transitions struct {
+	idleAfter time.Duration
+	longTask  time.Duration
+	idle      map[string]idleState // by sessionKey
+	active    map[string]time.Time // when sessions started working, by sessionKey
+}
+
+// idleState is a session seen going idle.
+type idleState struct {
+	session  Session
+	since    time.Time
+	reported bool
+}
+
+// NewTransitions returns transitions reporting sessions idle for idleAfter
+// and tasks that took longTask.
+func NewTransitions(idleAfter, longTask time.Duration) *Transitions {
+	return &Transitions{
+		idleAfter: idleAfter,
+		longTask:  longTask,
+		idle:      make(map[string]idleState),
+		active:    make(map[string]time.Time),
+	}
+}
+
+// Feed returns the transitions e amounts to.
+func (t *Transitions) Feed(e Event) []Transition {
+	if e.Kind == AllQuiet {
+		return []Transition{{Kind: config.WebhookAllQuiet, Quiet: e.Quiet, At: e.At}}
+	}
+	key := sessionKey(e.Session)
+	started, working := t.active[key]
+	delete(t.active, key)
+	delete(t.idle, key)
+	switch e.Kind {
+	case SessionRemoved:
+		kind := config.WebhookFinished
+		switch e.Session.Status {
+		case StatusActive:
+			kind = config.WebhookCrashed
+		case StatusExited, StatusCrashed:
+			return nil // reported when claude exited
+		}
+		return []Transition{{Kind: kind, Session: e.Session, At: e.At}}
+	case SessionAdded, StatusChanged:
+		switch e.Session.Status {
+		case StatusActive:
+			t.active[key] = e.At
+		case StatusIdle:
+			t.idle[key] = idleState{session: e.Session, since: e.At}
+			if worked := e.At.Sub(started); working && worked >= t.longTask {
+				return []Transition{{Kind: config.WebhookDone, Session: e.Session, Worked: worked, At: e.At}}
+			}
+		case StatusWaiting:
+			return []Transition{{Kind: config.WebhookWaiting, Session: e.Session, At: e.At}}
+		case StatusExited:
+			return []Transition{{Kind: config.WebhookFinished, Session: e.Session, At: e.At}}
+		case StatusCrashed:
+			return []Transition{{Kind: config.WebhookCrashed, Session: e.Session, At: e.At}}
+		}
+	}
+	return nil
+}
+
+// Tick returns an idle transition for each session idle for idleAfter at
+// now, once per stretch of idleness, in host and name order.
+func (t *Transitions) Tick(now time.Time) []Transition {
+	var keys []string
+	for key, st := range t.idle {
+		if !st.reported && now.Sub(st.since) >= t.idleAfter {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	out := make([]Transition, 0, len(keys))
+	for _, key := range keys {
+		st := t.idle[key]
+		st.reported = true
+		t.idle[key] = st
+		out = append(out, Transition{Kind: config.WebhookIdle, Session: st.session, Idle: now.Sub(st.since), At: now})
+	}
+	return out
+}
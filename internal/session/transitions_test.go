@@ -0,0 +1,121 @@
+package session
+
+import (
+	"testing"
+	"time"
+
+	"github.com/seunggabi/claude-dashboard/internal/config"
+)
+
+// ---------------------------------------------------------------------------
+// Transitions
+// ---------------------------------------------------------------------------
+
+func kinds(ts []Transition) []string {
+	out := make([]string, len(ts))
+	for i, t := range ts {
+		out[i] = t.Kind + " " + t.Session.Name
+	}
+	return out
+}
+
+func TestTransitions_waitingCrashedAndFinished(t *testing.T) {
+	tr := NewTransitions(time.Minute, time.Hour)
+	now := time.Now()
+	tracker := NewTracker()
+	tracker.Observe("", []Session{
+		{Name: "cd-a", Status: StatusActive},
+		{Name: "cd-b", Status: StatusActive},
+		{Name: "cd-c", Status: StatusIdle},
+	}, now)
+
+	var got []Transition
+	for _, e := range tracker.Observe("", []Session{{Name: "cd-a", Status: StatusWaiting}}, now) {
+		got = append(got, tr.Feed(e)...)
+	}
+	want := []string{"waiting cd-a", "crashed cd-b", "finished cd-c"}
+	if g := kinds(got); len(g) != len(want) || g[0] != want[0] || g[1] != want[1] || g[2] != want[2] {
+		t.Errorf("expected %v, got %v", want, g)
+	}
+}
+
+func TestTransitions_claudeExitingIsReportedOnce(t *testing.T) {
+	tr := NewTransitions(time.Minute, time.Hour)
+	now := time.Now()
+	got := tr.Feed(Event{Kind: StatusChanged, Session: Session{Name: "cd-a", Status: StatusCrashed}, From: StatusActive, At: now})
+	got = append(got, tr.Feed(Event{Kind: StatusChanged, Session: Session{Name: "cd-b", Status: StatusExited}, From: StatusIdle, At: now})...)
+	if g := kinds(got); len(g) != 2 || g[0] != "crashed cd-a" || g[1] != "finished cd-b" {
+		t.Errorf("expected crashed cd-a and finished cd-b, got %v", g)
+	}
+	for _, s := range []Session{{Name: "cd-a", Status: StatusCrashed}, {Name: "cd-b", Status: StatusExited}} {
+		if got := tr.Feed(Event{Kind: SessionRemoved, Session: s, At: now}); len(got) != 0 {
+			t.Errorf("expected killing %s not to be reported, got %v", s.Name, kinds(got))
+		}
+	}
+}
+
+func TestTransitions_idleOnceAfterTheWait(t *testing.T) {
+	tr := NewTransitions(10*time.Minute, time.Hour)
+	start := time.Now()
+	tr.Feed(Event{Kind: StatusChanged, Session: Session{Name: "cd-a", Status: StatusIdle}, From: StatusActive, At: start})
+
+	if got := tr.Tick(start.Add(9 * time.Minute)); len(got) != 0 {
+		t.Errorf("expected nothing before idle_after, got %v", kinds(got))
+	}
+	got := tr.Tick(start.Add(11 * time.Minute))
+	if len(got) != 1 || got[0].Kind != config.WebhookIdle || got[0].Idle != 11*time.Minute {
+		t.Fatalf("expected one idle transition after 11m, got %+v", got)
+	}
+	if got := tr.Tick(start.Add(20 * time.Minute)); len(got) != 0 {
+		t.Errorf("expected idle to be reported once, got %v", kinds(got))
+	}
+
+	// Working again starts a new stretch.
+	tr.Feed(Event{Kind: StatusChanged, Session: Session{Name: "cd-a", Status: StatusActive}, At: start.Add(21 * time.Minute)})
+	tr.Feed(Event{Kind: StatusChanged, Session: Session{Name: "cd-a", Status: StatusIdle}, At: start.Add(22 * time.Minute)})
+	if got := tr.Tick(start.Add(33 * time.Minute)); len(got) != 1 {
+		t.Errorf("expected a second idle transition, got %v", kinds(got))
+	}
+}
+
+func TestTransitions_removedSessionIsNotReportedIdle(t *testing.T) {
+	tr := NewTransitions(time.Minute, time.Hour)
+	start := time.Now()
+	s := Session{Name: "cd-a", Status: StatusIdle}
+	tr.Feed(Event{Kind: SessionAdded, Session: s, At: start})
+	tr.Feed(Event{Kind: SessionRemoved, Session: s, At: start})
+	if got := tr.Tick(start.Add(time.Hour)); len(got) != 0 {
+		t.Errorf("expected nothing for a removed session, got %v", kinds(got))
+	}
+}
+
+func TestTransitions_doneAfterALongTask(t *testing.T) {
+	tr := NewTransitions(time.Hour, 5*time.Minute)
+	start := time.Now()
+	working := Session{Name: "cd-a", Status: StatusActive}
+	stopped := Session{Name: "cd-a", Status: StatusIdle}
+
+	tr.Feed(Event{Kind: StatusChanged, Session: working, At: start})
+	if got := tr.Feed(Event{Kind: StatusChanged, Session: stopped, At: start.Add(time.Minute)}); len(got) != 0 {
+		t.Errorf("expected nothing after a short task, got %v", kinds(got))
+	}
+	tr.Feed(Event{Kind: StatusChanged, Session: working, At: start.Add(2 * time.Minute)})
+	got := tr.Feed(Event{Kind: StatusChanged, Session: stopped, At: start.Add(10 * time.Minute)})
+	if len(got) != 1 || got[0].Kind != config.WebhookDone || got[0].Worked != 8*time.Minute {
+		t.Errorf("expected done after 8m of work, got %+v", got)
+	}
+}
+
+func TestTransitions_allQuiet(t *testing.T) {
+	tr := NewTransitions(time.Minute, time.Hour)
+	now := time.Now()
+	tr.Feed(Event{Kind: StatusChanged, Session: Session{Name: "cd-a", Status: StatusActive}, At: now})
+	quiet := []Session{{Name: "cd-a", Status: StatusIdle}, {Name: "cd-b", Status: StatusWaiting}}
+	got := tr.Feed(Event{Kind: AllQuiet, Quiet: quiet, At: now})
+	if len(got) != 1 || got[0].Kind != config.WebhookAllQuiet || len(got[0].Quiet) != 2 || !got[0].At.Equal(now) {
+		t.Fatalf("expected one all_quiet transition, got %+v", got)
+	}
+	if _, working := tr.active["/cd-a"]; !working {
+		t.Error("expected all quiet to leave the sessions it names alone")
+	}
+}
@@ -0,0 +1,71 @@
+package session
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/seunggabi/claude-dashboard/internal/tmux"
+)
+
+// Window is a tmux window of a session. Users often name windows ("api",
+// "frontend") more meaningfully than sessions, so they are searched too.
+type Window struct {
+	Index  int
+	Name   string
+	Titles []string // pane titles other than tmux's default
+}
+
+// String returns the window as tmux shows it, e.g. "2:api".
+func (w Window) String() string {
+	return fmt.Sprintf("%d:%s", w.Index, w.Name)
+}
+
+// windowsBySession groups panes into the windows of each session, in the
+// order tmux lists them.
+func windowsBySession(panes []tmux.RawPane) map[string][]Window {
+	out := make(map[string][]Window)
+	for _, p := range panes {
+		windows := out[p.Session]
+		if n := len(windows); n == 0 || windows[n-1].Index != p.WindowIndex {
+			windows = append(windows, Window{Index: p.WindowIndex, Name: p.WindowName})
+		}
+		if p.Title != "" {
+			w := &windows[len(windows)-1]
+			w.Titles = append(w.Titles, p.Title)
+		}
+		out[p.Session] = windows
+	}
+	return out
+}
+
+// listWindows returns the windows of every session on client's server; nil
+// when they cannot be listed, which only costs searching by them.
+func listWindows(ctx context.Context, client *tmux.Client) map[string][]Window {
+	out, err := client.ListPanes(ctx, tmux.PaneFormat)
+	if err != nil {
+		return nil
+	}
+	return windowsBySession(tmux.ParsePanes(out))
+}
+
+// MatchedWindow returns how the first window of s matching query, ignoring
+// case, is labelled in filter results: "2:api", or "2:api · title" when it
+// matched by a pane title. ok is false when no window matches.
+func MatchedWindow(s Session, query string) (label string, ok bool) {
+	if query == "" {
+		return "", false
+	}
+	query = strings.ToLower(query)
+	for _, w := range s.Windows {
+		if strings.Contains(strings.ToLower(w.Name), query) {
+			return w.String(), true
+		}
+		for _, title := range w.Titles {
+			if strings.Contains(strings.ToLower(title), query) {
+				return w.String() + " · " + title, true
+			}
+		}
+	}
+	return "", false
+}
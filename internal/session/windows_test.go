@@ -0,0 +1,60 @@
+package session
+
+import (
+	"testing"
+
+	"github.com/seunggabi/claude-dashboard/internal/tmux"
+)
+
+// ---------------------------------------------------------------------------
+// windowsBySession
+// ---------------------------------------------------------------------------
+
+func TestWindowsBySession_groupsPanesIntoWindows(t *testing.T) {
+	got := windowsBySession([]tmux.RawPane{
+		{Session: "cd-api", WindowIndex: 0, WindowName: "claude", Title: "Fix login"},
+		{Session: "cd-api", WindowIndex: 1, WindowName: "server"},
+		{Session: "cd-api", WindowIndex: 1, WindowName: "server", Title: "tail logs"},
+		{Session: "cd-web", WindowIndex: 0, WindowName: "claude"},
+	})
+	api := got["cd-api"]
+	if len(api) != 2 {
+		t.Fatalf("expected 2 windows for cd-api, got %+v", api)
+	}
+	if api[0].String() != "0:claude" || len(api[0].Titles) != 1 || api[0].Titles[0] != "Fix login" {
+		t.Errorf("unexpected first window %+v", api[0])
+	}
+	if api[1].String() != "1:server" || len(api[1].Titles) != 1 {
+		t.Errorf("expected one title on the second window, got %+v", api[1])
+	}
+	if len(got["cd-web"]) != 1 {
+		t.Errorf("expected 1 window for cd-web, got %+v", got["cd-web"])
+	}
+}
+
+// ---------------------------------------------------------------------------
+// MatchedWindow
+// ---------------------------------------------------------------------------
+
+func TestMatchedWindow_labelsTheMatchingWindow(t *testing.T) {
+	s := Session{Windows: []Window{
+		{Index: 0, Name: "claude"},
+		{Index: 1, Name: "api"},
+		{Index: 2, Name: "zsh", Titles: []string{"Frontend build"}},
+	}}
+	tests := []struct {
+		query, label string
+		ok           bool
+	}{
+		{"API", "1:api", true},
+		{"frontend", "2:zsh · Frontend build", true},
+		{"nothing", "", false},
+		{"", "", false},
+	}
+	for _, tt := range tests {
+		label, ok := MatchedWindow(s, tt.query)
+		if label != tt.label || ok != tt.ok {
+			t.Errorf("MatchedWindow(%q) = %q, %v; expected %q, %v", tt.query, label, ok, tt.label, tt.ok)
+		}
+	}
+}
@@ -0,0 +1,146 @@
+package setup
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/seunggabi/claude-dashboard/internal/config"
+)
+
+// Bindings are the tmux keys setup binds to the helper scripts, as tmux
+// key names; config.KeyNone leaves one unbound.
+type Bindings struct {
+	MouseToggle string
+	SaveHistory string
+}
+
+// ConfiguredBindings returns the keys set in config.yaml, or the defaults.
+func ConfiguredBindings() Bindings {
+	cfg := config.Load()
+	return Bindings{MouseToggle: cfg.TmuxMouseToggleKey, SaveHistory: cfg.TmuxSaveHistoryKey}
+}
+
+// installedBindings returns the keys a ~/.tmux.conf binds to the helper
+// scripts; config.KeyNone for a script it binds no key to.
+func installedBindings(conf string) Bindings {
+	b := Bindings{MouseToggle: config.KeyNone, SaveHistory: config.KeyNone}
+	for _, line := range strings.Split(conf, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 4 || fields[0] != "bind-key" || fields[1] != "-n" {
+			continue
+		}
+		switch {
+		case strings.Contains(line, "claude-dashboard-mouse-toggle"):
+			b.MouseToggle = fields[2]
+		case strings.Contains(line, "claude-dashboard-save-history"):
+			b.SaveHistory = fields[2]
+		}
+	}
+	return b
+}
+
+// bindingPrompts are the questions UpdateBindings asks, and the config
+// setting each answer goes to.
+var bindingPrompts = []struct {
+	question string
+	setting  string
+	key      func(cfg *config.Config) *string
+}{
+	{"tmux key to toggle mouse mode", "tmux_mouse_toggle_key", func(cfg *config.Config) *string { return &cfg.TmuxMouseToggleKey }},
+	{"tmux key to save the pane history", "tmux_save_history_key", func(cfg *config.Config) *string { return &cfg.TmuxSaveHistoryKey }},
+}
+
+// UpdateBindings rebinds the helper scripts to the keys set in config.yaml
+// (setup --update-bindings): it rewrites the claude-dashboard block of
+// ~/.tmux.conf, unbinds the keys it replaces in a running tmux server and
+// reloads the configuration there. With ask, it first asks for each key,
+// offering the one set, and saves the answers to config.yaml, changing
+// only those settings (and not a config.yaml that does not parse); ask
+// returns "" to keep it.
+func UpdateBindings(w io.Writer, ask func(question, current string) string) error {
+	if ask != nil {
+		cfg := config.Load()
+		changed := map[string]string{}
+		for _, p := range bindingPrompts {
+			key := p.key(cfg)
+			answer := strings.TrimSpace(ask(fmt.Sprintf("%s (a key such as F12 or C-s, or %s)", p.question, config.KeyNone), *key))
+			if answer == "" || answer == *key {
+				continue
+			}
+			if err := config.ValidateTmuxKey(answer); err != nil {
+				return err
+			}
+			*key, changed[p.setting] = answer, answer
+		}
+		if cfg.TmuxMouseToggleKey != config.KeyNone && cfg.TmuxMouseToggleKey == cfg.TmuxSaveHistoryKey {
+			return fmt.Errorf("%s cannot both toggle mouse mode and save the pane history", cfg.TmuxMouseToggleKey)
+		}
+		if len(changed) > 0 {
+			for _, p := range bindingPrompts {
+				key, ok := changed[p.setting]
+				if !ok {
+					continue
+				}
+				if err := config.Set(p.setting, key); err != nil {
+					return fmt.Errorf("failed to save %s: %w", config.ConfigPath(), err)
+				}
+			}
+			fmt.Fprintf(w, "✅ Keys saved to %s\n", config.ConfigPath())
+		}
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return fmt.Errorf("failed to get home directory: %w", err)
+	}
+	tmuxConf := filepath.Join(home, ".tmux.conf")
+	old := Bindings{MouseToggle: config.KeyNone, SaveHistory: config.KeyNone}
+	if data, err := os.ReadFile(tmuxConf); err == nil {
+		old = installedBindings(string(data))
+	}
+	changed, err := SetupTmuxConfig()
+	if err != nil {
+		return err
+	}
+	b := ConfiguredBindings()
+	if !changed {
+		fmt.Fprintf(w, "✅ Key bindings already up to date in %s\n", tmuxConf)
+	} else {
+		fmt.Fprintf(w, "✅ Key bindings updated in %s\n", tmuxConf)
+		for _, key := range replacedKeys(old, b) {
+			// Reloading only adds bindings; the old key stays bound to the
+			// script in a running server until unbound.
+			_ = exec.Command("tmux", "unbind-key", "-n", key).Run()
+		}
+		if err := ReloadTmuxConfig(); err != nil {
+			fmt.Fprintf(w, "⚠️  Could not reload tmux (%s). Configuration will apply on next tmux start.\n", err)
+		}
+	}
+	writeBindings(w, b)
+	return nil
+}
+
+// replacedKeys returns the keys of old that new no longer binds.
+func replacedKeys(old, new Bindings) []string {
+	var keys []string
+	for _, key := range []string{old.MouseToggle, old.SaveHistory} {
+		if key != config.KeyNone && key != new.MouseToggle && key != new.SaveHistory {
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
+
+// writeBindings tells which key does what in tmux.
+func writeBindings(w io.Writer, b Bindings) {
+	if b.MouseToggle != config.KeyNone {
+		fmt.Fprintf(w, "  Press %s in tmux to toggle mouse mode\n", b.MouseToggle)
+	}
+	if b.SaveHistory != config.KeyNone {
+		fmt.Fprintf(w, "  Press %s in tmux to save entire pane history to file\n", b.SaveHistory)
+	}
+}
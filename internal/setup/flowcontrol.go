@@ -0,0 +1,94 @@
+package setup
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"slices"
+	"strings"
+
+	"github.com/seunggabi/claude-dashboard/internal/config"
+)
+
+// flowControlKeys are the keys terminal flow control (ixon) takes: Ctrl+S
+// stops the output (XOFF), which looks like a frozen terminal, until Ctrl+Q
+// resumes it (XON).
+var flowControlKeys = []string{"C-s", "C-q"}
+
+// FlowControlSaveHistoryKey is the key setup binds to saving the pane
+// history instead of Ctrl+S when the terminal has flow control on.
+const FlowControlSaveHistoryKey = "M-s"
+
+// FlowControl reports whether the terminal has flow control (ixon) on, as
+// stty tells; an error when there is no terminal to ask.
+func FlowControl() (bool, error) {
+	tty, err := os.Open("/dev/tty")
+	if err != nil {
+		return false, fmt.Errorf("no terminal: %w", err)
+	}
+	defer tty.Close()
+	cmd := exec.Command("stty", "-a")
+	cmd.Stdin = tty
+	out, err := cmd.Output()
+	if err != nil {
+		return false, fmt.Errorf("stty: %w", err)
+	}
+	return ixonEnabled(string(out)), nil
+}
+
+// ixonEnabled reports whether stty -a output shows ixon on; off it shows
+// -ixon.
+func ixonEnabled(stty string) bool {
+	return slices.Contains(strings.Fields(strings.ReplaceAll(stty, ";", " ")), "ixon")
+}
+
+// FlowControlConflicts returns the keys of b that flow control takes.
+func FlowControlConflicts(b Bindings) []string {
+	var keys []string
+	for _, key := range []string{b.MouseToggle, b.SaveHistory} {
+		if slices.Contains(flowControlKeys, key) {
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
+
+// flowControlBindings returns b with the save-history key moved off Ctrl+S
+// to FlowControlSaveHistoryKey, for a terminal with flow control on, when
+// Ctrl+S is only the default (explicit is false) and the mouse toggle does
+// not use the other key, and the keys still taken by flow control.
+func flowControlBindings(b Bindings, explicit bool) (Bindings, []string) {
+	if !explicit && b.SaveHistory == config.DefaultSaveHistoryKey && b.MouseToggle != FlowControlSaveHistoryKey {
+		b.SaveHistory = FlowControlSaveHistoryKey
+	}
+	return b, FlowControlConflicts(b)
+}
+
+// avoidFlowControl keeps the tmux bindings off the keys flow control takes
+// in this terminal: Ctrl+S, as the default save-history key, is replaced
+// in config.yaml, so the bindings written next use the other key; the
+// rest of config.yaml is left as it is, and one that does not parse is not
+// changed but reported. Keys set explicitly are kept and returned as an error, with how to turn flow
+// control off instead. It reports whether config.yaml changed, and
+// returns errNoTerminal without a terminal to check.
+func avoidFlowControl() (bool, error) {
+	on, err := FlowControl()
+	if err != nil {
+		return false, errNoTerminal
+	}
+	if !on {
+		return false, nil
+	}
+	current := ConfiguredBindings()
+	b, conflicts := flowControlBindings(current, config.SetsSaveHistoryKey())
+	changed := b.SaveHistory != current.SaveHistory
+	if changed {
+		if err := config.Set("tmux_save_history_key", b.SaveHistory); err != nil {
+			return false, fmt.Errorf("failed to save %s: %w", config.ConfigPath(), err)
+		}
+	}
+	if len(conflicts) > 0 {
+		return changed, fmt.Errorf("%s is taken by flow control in this terminal and can freeze it; add 'stty -ixon' to your shell profile, or bind another key (setup --update-bindings)", strings.Join(conflicts, ", "))
+	}
+	return changed, nil
+}
@@ -0,0 +1,66 @@
+package setup
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// commitMsgScript is the installed name of the helper that adds the session
+// trailer to a commit message.
+const commitMsgScript = "claude-dashboard-commit-msg"
+
+// gitHookMarker identifies commit-msg hooks written by InstallGitHook.
+const gitHookMarker = "# claude-dashboard: session trailer"
+
+// gitHook returns the commit-msg hook calling the helper for sessions named
+// with prefix. Without the helper, commits go through unchanged.
+func gitHook(prefix string) string {
+	return `#!/bin/sh
+` + gitHookMarker + `
+# Adds a Claude-Session trailer to commits made in a claude-dashboard session.
+# Remove with: claude-dashboard git-hook uninstall
+helper="$HOME/.local/bin/` + commitMsgScript + `"
+[ -x "$helper" ] && "$helper" "$1" ` + prefix + `
+exit 0
+`
+}
+
+// ErrForeignGitHook is returned when a repository already has a commit-msg
+// hook that claude-dashboard did not write.
+var ErrForeignGitHook = errors.New("a commit-msg hook not written by claude-dashboard is already installed")
+
+// InstallGitHook writes the commit-msg hook to hooksDir, replacing one from
+// a previous install. It returns the path of the hook.
+func InstallGitHook(hooksDir, prefix string) (string, error) {
+	path := filepath.Join(hooksDir, "commit-msg")
+	if data, err := os.ReadFile(path); err == nil && !strings.Contains(string(data), gitHookMarker) {
+		return path, fmt.Errorf("%s: %w", path, ErrForeignGitHook)
+	}
+	if err := os.MkdirAll(hooksDir, 0755); err != nil {
+		return path, fmt.Errorf("failed to create %s: %w", hooksDir, err)
+	}
+	if err := os.WriteFile(path, []byte(gitHook(prefix)), 0755); err != nil {
+		return path, fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return path, nil
+}
+
+// RemoveGitHook deletes the commit-msg hook InstallGitHook wrote to
+// hooksDir, reporting whether there was one. Other hooks are left alone.
+func RemoveGitHook(hooksDir string) (bool, error) {
+	path := filepath.Join(hooksDir, "commit-msg")
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	if !strings.Contains(string(data), gitHookMarker) {
+		return false, fmt.Errorf("%s: %w", path, ErrForeignGitHook)
+	}
+	return true, os.Remove(path)
+}
@@ -0,0 +1,117 @@
+package setup
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// hookScript is the installed name of the Claude Code hook helper.
+const hookScript = "claude-dashboard-hook"
+
+// hookEvents are the Claude Code hook events that report session state.
+var hookEvents = []string{"UserPromptSubmit", "PreToolUse", "PostToolUse", "Notification", "Stop"}
+
+// ClaudeSettingsPath returns the user-level Claude Code settings file.
+func ClaudeSettingsPath() string {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(homeDir, ".claude", "settings.json")
+}
+
+// InstallClaudeHooks registers the state hook in ~/.claude/settings.json for
+// every event in hookEvents, reporting whether the file had to change.
+// Other settings and hooks are left untouched; entries from a previous
+// install are replaced.
+func InstallClaudeHooks() (bool, error) {
+	path := ClaudeSettingsPath()
+	if path == "" {
+		return false, fmt.Errorf("failed to get home directory")
+	}
+
+	settings := map[string]interface{}{}
+	if data, err := os.ReadFile(path); err == nil && len(strings.TrimSpace(string(data))) > 0 {
+		if err := json.Unmarshal(data, &settings); err != nil {
+			return false, fmt.Errorf("failed to parse %s: %w", path, err)
+		}
+	}
+
+	settings["hooks"] = mergeHooks(settings["hooks"])
+
+	data, err := json.MarshalIndent(settings, "", "  ")
+	if err != nil {
+		return false, err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return false, fmt.Errorf("failed to create %s: %w", filepath.Dir(path), err)
+	}
+	changed, err := writeIfChanged(path, append(data, '\n'), 0644)
+	if err != nil {
+		return changed, fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return changed, nil
+}
+
+// mergeHooks returns the settings "hooks" object with the dashboard hook
+// registered once per event.
+func mergeHooks(existing interface{}) map[string]interface{} {
+	hooks, _ := existing.(map[string]interface{})
+	if hooks == nil {
+		hooks = map[string]interface{}{}
+	}
+
+	for _, event := range hookEvents {
+		groups, _ := hooks[event].([]interface{})
+		groups = removeDashboardHooks(groups)
+
+		group := map[string]interface{}{
+			"hooks": []interface{}{
+				map[string]interface{}{
+					"type":    "command",
+					"command": "~/.local/bin/" + hookScript + " " + event,
+				},
+			},
+		}
+		if event == "PreToolUse" || event == "PostToolUse" {
+			group["matcher"] = "*"
+		}
+		hooks[event] = append(groups, group)
+	}
+	return hooks
+}
+
+// removeDashboardHooks drops hook commands installed by claude-dashboard,
+// and any matcher group left empty by that.
+func removeDashboardHooks(groups []interface{}) []interface{} {
+	var kept []interface{}
+	for _, g := range groups {
+		group, ok := g.(map[string]interface{})
+		if !ok {
+			kept = append(kept, g)
+			continue
+		}
+		list, _ := group["hooks"].([]interface{})
+		if len(list) == 0 {
+			kept = append(kept, g)
+			continue
+		}
+		var others []interface{}
+		for _, h := range list {
+			hook, _ := h.(map[string]interface{})
+			if cmd, _ := hook["command"].(string); strings.Contains(cmd, hookScript) {
+				continue
+			}
+			others = append(others, h)
+		}
+		if len(others) == 0 {
+			continue
+		}
+		group["hooks"] = others
+		kept = append(kept, group)
+	}
+	return kept
+}
@@ -0,0 +1,267 @@
+package setup
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/seunggabi/claude-dashboard/internal/config"
+)
+
+// Options of a setup run. The zero value installs everything, as the
+// first start does.
+type Options struct {
+	Version    string
+	ConfigFrom string // config file to install as config.yaml; empty to leave it alone
+	AssumeYes  bool   // replace a different config.yaml without asking
+	NoTmuxConf bool   // leave ~/.tmux.conf alone, e.g. when it is managed elsewhere
+
+	// Confirm asks a yes/no question; nil when no one can answer, so
+	// anything needing an answer fails unless AssumeYes is set.
+	Confirm func(question string) bool
+}
+
+// Step statuses of a Report.
+const (
+	StepChanged   = "changed"
+	StepUnchanged = "unchanged"
+	StepSkipped   = "skipped"
+	StepWarning   = "warning" // failed, but setup carried on
+	StepFailed    = "failed"
+)
+
+// Step is one thing a setup run did.
+type Step struct {
+	Name   string `json:"name"`
+	Status string `json:"status"`
+	Path   string `json:"path,omitempty"`
+	Error  string `json:"error,omitempty"`
+}
+
+// Report lists the steps of a setup run. Changed is false when everything
+// was already in place: running setup again with the same options is
+// always such a no-op.
+type Report struct {
+	Changed bool   `json:"changed"`
+	Steps   []Step `json:"steps"`
+	Error   string `json:"error,omitempty"`
+}
+
+// add records a step, named name on path, that wrote if changed or
+// failed with err.
+func (r *Report) add(name, path string, changed bool, err error, fatal bool) {
+	st := Step{Name: name, Path: path, Status: StepUnchanged}
+	switch {
+	case err != nil && fatal:
+		st.Status, st.Error = StepFailed, err.Error()
+	case err != nil:
+		st.Status, st.Error = StepWarning, err.Error()
+	case changed:
+		st.Status = StepChanged
+	}
+	r.Changed = r.Changed || changed
+	r.Steps = append(r.Steps, st)
+}
+
+// skip records a step that was not run.
+func (r *Report) skip(name, path string) {
+	r.Steps = append(r.Steps, Step{Name: name, Path: path, Status: StepSkipped})
+}
+
+// errNeedsYes is returned when a change needs an answer no one can give.
+var errNeedsYes = errors.New("run with --assume-yes to replace it")
+
+// errNoTerminal is returned by a check of the terminal when setup runs
+// without one, as when provisioning.
+var errNoTerminal = errors.New("no terminal")
+
+// Run installs the helper scripts, the tmux configuration and the Claude
+// Code hooks, and config.yaml when opts say so, writing only what differs
+// from what is there. The report lists every step, also when a step fails
+// and ends the run with the error.
+func Run(opts Options) (Report, error) {
+	var r Report
+	home, _ := os.UserHomeDir()
+
+	if opts.ConfigFrom == "" {
+		r.skip("config", config.ConfigPath())
+	} else {
+		changed, err := installConfig(opts)
+		r.add("config", config.ConfigPath(), changed, err, true)
+		if err != nil {
+			return r, err
+		}
+	}
+
+	changed, err := InstallScripts()
+	r.add("scripts", filepath.Join(home, ".local", "bin"), changed, err, true)
+	if err != nil {
+		return r, fmt.Errorf("failed to install scripts: %w", err)
+	}
+	_ = markSetup() // without it the next start checks the scripts again
+
+	tmuxConf := filepath.Join(home, ".tmux.conf")
+	tmuxChanged := false
+	if opts.NoTmuxConf {
+		r.skip("flow_control", config.ConfigPath())
+		r.skip("tmux_conf", tmuxConf)
+	} else {
+		// Before the tmux configuration, which binds the keys it picks.
+		changed, err = avoidFlowControl()
+		if errors.Is(err, errNoTerminal) {
+			r.skip("flow_control", config.ConfigPath())
+		} else {
+			r.add("flow_control", config.ConfigPath(), changed, err, false)
+		}
+		tmuxChanged, err = SetupTmuxConfig()
+		r.add("tmux_conf", tmuxConf, tmuxChanged, err, true)
+		if err != nil {
+			return r, fmt.Errorf("failed to setup tmux config: %w", err)
+		}
+	}
+
+	// Hooks are an optional improvement to status detection; pane scraping
+	// still works without them, so a failure here does not abort setup.
+	changed, err = InstallClaudeHooks()
+	r.add("claude_hooks", ClaudeSettingsPath(), changed, err, false)
+
+	// Reloading changes nothing on disk, so it is only done, and counted,
+	// along with a new ~/.tmux.conf.
+	if tmuxChanged {
+		r.add("tmux_reload", "", true, ReloadTmuxConfig(), false)
+	} else {
+		r.skip("tmux_reload", "")
+	}
+
+	if opts.Version != "" && opts.Version != "dev" {
+		dir, _ := cacheDir()
+		changed, err = updateVersionCache(opts.Version)
+		r.add("version_cache", dir, changed, err, false)
+	}
+	return r, nil
+}
+
+// installConfig makes opts.ConfigFrom config.yaml, once it validates. A
+// different config.yaml is only replaced with AssumeYes or when Confirm
+// says so.
+func installConfig(opts Options) (bool, error) {
+	data, err := os.ReadFile(opts.ConfigFrom)
+	if err != nil {
+		return false, err
+	}
+	if errs := config.Validate(data); len(errs) > 0 {
+		msgs := make([]string, len(errs))
+		for i, e := range errs {
+			msgs[i] = e.Error()
+		}
+		return false, fmt.Errorf("%s: %s", opts.ConfigFrom, strings.Join(msgs, "; "))
+	}
+	path := config.ConfigPath()
+	old, err := os.ReadFile(path)
+	switch {
+	case err == nil && string(old) == string(data):
+		return false, nil
+	case err == nil && !opts.AssumeYes:
+		if opts.Confirm == nil || !opts.Confirm(fmt.Sprintf("Replace %s with %s?", path, opts.ConfigFrom)) {
+			return false, fmt.Errorf("%s differs from %s; %w", path, opts.ConfigFrom, errNeedsYes)
+		}
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return false, err
+	}
+	return writeIfChanged(path, data, 0644)
+}
+
+// SetupJSON runs setup and writes its report to w as JSON, for
+// configuration management tools. The report is written also when setup
+// fails.
+func SetupJSON(w io.Writer, opts Options) error {
+	r, err := Run(opts)
+	if err != nil {
+		r.Error = err.Error()
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	if encErr := enc.Encode(r); encErr != nil {
+		return encErr
+	}
+	return err
+}
+
+// stepMessages say what each step did, by status, for Setup.
+var stepMessages = map[string]map[string]string{
+	"config": {
+		StepChanged:   "✅ Config installed to %s",
+		StepUnchanged: "✅ Config already up to date in %s",
+	},
+	"scripts": {
+		StepChanged:   "✅ Helper scripts installed to %s/",
+		StepUnchanged: "✅ Helper scripts already up to date in %s/",
+	},
+	"flow_control": {
+		StepChanged: "✅ Ctrl+S can freeze this terminal (flow control is on), so pane history is saved with " + FlowControlSaveHistoryKey + " instead; set in %s",
+		StepWarning: "⚠️  Warning: %s",
+	},
+	"tmux_conf": {
+		StepChanged:   "✅ Tmux configuration added to %s",
+		StepUnchanged: "✅ Tmux configuration already in %s",
+		StepSkipped:   "⏭️  Left %s alone",
+	},
+	"claude_hooks": {
+		StepChanged:   "✅ Status hooks added to %s",
+		StepUnchanged: "✅ Status hooks already in %s",
+		StepWarning:   "⚠️  Warning: Could not install Claude Code hooks: %s",
+	},
+	"tmux_reload": {
+		StepChanged: "✅ Tmux configuration reloaded",
+		StepWarning: "⚠️  Could not reload tmux (%s). Configuration will apply on next tmux start.",
+	},
+	"version_cache": {
+		StepChanged: "✅ Version cache updated",
+		StepWarning: "⚠️  Warning: Could not update version cache: %s",
+	},
+}
+
+// Setup runs setup and describes each step on w.
+func Setup(w io.Writer, opts Options) error {
+	fmt.Fprintln(w, "🔧 Setting up claude-dashboard...")
+	fmt.Fprintln(w)
+	r, err := Run(opts)
+	for _, st := range r.Steps {
+		msg := stepMessages[st.Name][st.Status]
+		arg := st.Path
+		if st.Error != "" {
+			arg = st.Error
+		}
+		switch {
+		case msg == "": // failures are returned
+		case strings.Contains(msg, "%s"):
+			fmt.Fprintf(w, msg+"\n", arg)
+		default:
+			fmt.Fprintln(w, msg)
+		}
+	}
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
+	if r.Changed {
+		fmt.Fprintln(w, "  🎉 Setup complete!")
+	} else {
+		fmt.Fprintln(w, "  🎉 Setup complete; nothing needed changing")
+	}
+	fmt.Fprintln(w, "━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
+	fmt.Fprintln(w)
+	if !opts.NoTmuxConf {
+		writeBindings(w, ConfiguredBindings())
+		fmt.Fprintln(w, "  Check the status bar for version and mouse status")
+		fmt.Fprintln(w)
+	}
+	return nil
+}
@@ -0,0 +1,33 @@
+#!/usr/bin/env bash
+# Add a Claude-Session trailer naming the claude-dashboard session a commit
+# was made in, so the archive view can link commits to their conversation.
+# Called by the commit-msg hook `claude-dashboard git-hook install` writes:
+#   claude-dashboard-commit-msg <message-file> <session-prefix>
+
+MSG="${1:-}"
+PREFIX="${2:-cd-}"
+[ -f "$MSG" ] || exit 0
+command -v tmux >/dev/null 2>&1 || exit 0
+
+# A commit made in a tmux pane belongs to that pane's session.
+SESSION=""
+if [ -n "${TMUX:-}" ]; then
+    SESSION=$(tmux display-message -p -t "${TMUX_PANE:-}" '#S' 2>/dev/null)
+    case "$SESSION" in
+        "$PREFIX"*) ;;
+        *) SESSION="" ;;
+    esac
+fi
+
+# Otherwise, to a running session with a pane in this worktree.
+if [ -z "$SESSION" ]; then
+    TOP=$(git rev-parse --show-toplevel 2>/dev/null) || exit 0
+    SESSION=$(tmux list-panes -a -F "#{session_name}"$'\t'"#{pane_current_path}" 2>/dev/null |
+        awk -F '\t' -v top="$TOP" -v prefix="$PREFIX" \
+            'index($1, prefix) == 1 && ($2 == top || index($2, top "/") == 1) { print $1; exit }')
+fi
+[ -n "$SESSION" ] || exit 0
+
+git interpret-trailers --in-place --if-exists addIfDifferent \
+    --trailer "Claude-Session: $SESSION" "$MSG" 2>/dev/null
+exit 0
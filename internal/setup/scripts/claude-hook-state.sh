@@ -0,0 +1,33 @@
+#!/usr/bin/env bash
+# Record Claude Code hook events for claude-dashboard status detection.
+# Installed as a Claude Code hook: claude-dashboard-hook <EventName>
+# Writes ~/.claude-dashboard/state/<tmux-session>.json
+
+# Claude Code passes the event payload on stdin; read all of it so the hook
+# never blocks, keeping the session ID that names the conversation log.
+PAYLOAD=$(cat 2>/dev/null | tr -d '\n') || true
+SESSION_ID=$(printf '%s' "$PAYLOAD" |
+    sed -n 's/.*"session_id"[[:space:]]*:[[:space:]]*"\([0-9A-Za-z-]*\)".*/\1/p')
+
+# Only sessions running inside tmux can be matched to a dashboard row.
+[ -n "${TMUX:-}" ] || exit 0
+
+EVENT="${1:-}"
+case "$EVENT" in
+    UserPromptSubmit|PreToolUse|PostToolUse) STATE="active" ;;
+    Notification) STATE="waiting" ;;
+    Stop) STATE="idle" ;;
+    *) exit 0 ;;
+esac
+
+SESSION_NAME=$(tmux display-message -p -t "${TMUX_PANE:-}" '#S' 2>/dev/null) || exit 0
+[ -n "$SESSION_NAME" ] || exit 0
+
+STATE_DIR="$HOME/.claude-dashboard/state"
+mkdir -p "$STATE_DIR" || exit 0
+
+FILE="$STATE_DIR/${SESSION_NAME//\//_}.json"
+TMP="$FILE.$$"
+printf '{"state":"%s","event":"%s","time":%s,"session_id":"%s"}\n' "$STATE" "$EVENT" "$(date +%s)" "$SESSION_ID" > "$TMP" && mv -f "$TMP" "$FILE"
+
+exit 0
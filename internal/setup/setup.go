@@ -1,12 +1,17 @@
 package setup
 
 import (
+	"bytes"
+	"crypto/sha256"
 	_ "embed"
+	"encoding/hex"
 	"fmt"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"strings"
+
+	"github.com/seunggabi/claude-dashboard/internal/config"
 )
 
 //go:embed scripts/tmux-mouse-toggle.sh
@@ -18,6 +23,12 @@ var statusBarScript []byte
 //go:embed scripts/tmux-save-history.sh
 var saveHistoryScript []byte
 
+//go:embed scripts/claude-hook-state.sh
+var hookStateScript []byte
+
+//go:embed scripts/claude-commit-msg.sh
+var commitMsgHelper []byte
+
 // scriptInfo holds information about a helper script
 type scriptInfo struct {
 	name    string
@@ -28,36 +39,90 @@ var helperScripts = []scriptInfo{
 	{"claude-dashboard-mouse-toggle", mouseToggleScript},
 	{"claude-dashboard-status-bar", statusBarScript},
 	{"claude-dashboard-save-history", saveHistoryScript},
+	{hookScript, hookStateScript},
+	{commitMsgScript, commitMsgHelper},
 }
 
-// InstallScripts installs the helper scripts to ~/.local/bin
-func InstallScripts() error {
+// InstallScripts installs the helper scripts to ~/.local/bin, reporting
+// whether any was missing or out of date.
+func InstallScripts() (bool, error) {
 	homeDir, err := os.UserHomeDir()
 	if err != nil {
-		return fmt.Errorf("failed to get home directory: %w", err)
+		return false, fmt.Errorf("failed to get home directory: %w", err)
 	}
 
 	binDir := filepath.Join(homeDir, ".local", "bin")
 	if err := os.MkdirAll(binDir, 0755); err != nil {
-		return fmt.Errorf("failed to create bin directory: %w", err)
+		return false, fmt.Errorf("failed to create bin directory: %w", err)
 	}
 
 	// Install all helper scripts
+	changed := false
 	for _, script := range helperScripts {
 		scriptPath := filepath.Join(binDir, script.name)
-		if err := os.WriteFile(scriptPath, script.content, 0755); err != nil {
-			return fmt.Errorf("failed to write %s: %w", script.name, err)
+		wrote, err := writeIfChanged(scriptPath, script.content, 0755)
+		if err != nil {
+			return changed, fmt.Errorf("failed to write %s: %w", script.name, err)
 		}
+		changed = changed || wrote
 	}
 
-	return nil
+	return changed, nil
+}
+
+// writeIfChanged writes data to path, created with perm, unless it already
+// holds exactly that, so that running setup again changes nothing. It
+// reports whether it wrote.
+func writeIfChanged(path string, data []byte, perm os.FileMode) (bool, error) {
+	if old, err := os.ReadFile(path); err == nil && bytes.Equal(old, data) {
+		return false, nil
+	}
+	return true, os.WriteFile(path, data, perm)
 }
 
-// SetupTmuxConfig adds the required tmux configuration
-func SetupTmuxConfig() error {
+// tmuxConfig returns the block SetupTmuxConfig appends to ~/.tmux.conf,
+// binding the keys of b.
+func tmuxConfig(b Bindings) string {
+	var bind strings.Builder
+	if b.MouseToggle != config.KeyNone {
+		fmt.Fprintf(&bind, `
+# claude-dashboard: %s key binding for mouse mode toggle
+bind-key -n %s run-shell "~/.local/bin/claude-dashboard-mouse-toggle"
+`, b.MouseToggle, b.MouseToggle)
+	}
+	if b.SaveHistory != config.KeyNone {
+		fmt.Fprintf(&bind, `
+# claude-dashboard: %s key binding for saving pane history
+bind-key -n %s run-shell "~/.local/bin/claude-dashboard-save-history"
+`, b.SaveHistory, b.SaveHistory)
+	}
+	mouseLabel := ""
+	if b.MouseToggle != config.KeyNone {
+		mouseLabel = "[" + b.MouseToggle + "] "
+	}
+	return `
+# claude-dashboard: Increase scrollback buffer for full history capture
+set -g history-limit 50000
+` + bind.String() + `
+# claude-dashboard: Status bar with version check and mouse status
+set -g status-right-length 80
+set -g status-right "#(~/.local/bin/claude-dashboard-status-bar) | ` + mouseLabel + `#[fg=#{?mouse,green,red}]Mouse:#{?mouse,ON,OFF}#[default] | %H:%M"
+set -g status-interval 5
+
+# claude-dashboard: Enable mouse mode by default
+set -g mouse on
+
+# claude-dashboard: Terminal overrides for better mouse support
+set -g terminal-overrides 'xterm*:smcup@:rmcup@'
+`
+}
+
+// SetupTmuxConfig adds the required tmux configuration, with the keys
+// configured in config.yaml, reporting whether ~/.tmux.conf had to change.
+func SetupTmuxConfig() (bool, error) {
 	homeDir, err := os.UserHomeDir()
 	if err != nil {
-		return fmt.Errorf("failed to get home directory: %w", err)
+		return false, fmt.Errorf("failed to get home directory: %w", err)
 	}
 
 	tmuxConfPath := filepath.Join(homeDir, ".tmux.conf")
@@ -107,37 +172,15 @@ func SetupTmuxConfig() error {
 		cleanedLines = cleanedLines[:len(cleanedLines)-1]
 	}
 
-	// Configuration to add
-	config := `
-# claude-dashboard: Increase scrollback buffer for full history capture
-set -g history-limit 50000
-
-# claude-dashboard: F12 key binding for mouse mode toggle
-bind-key -n F12 run-shell "~/.local/bin/claude-dashboard-mouse-toggle"
-
-# claude-dashboard: Ctrl+S key binding for saving pane history
-bind-key -n C-s run-shell "~/.local/bin/claude-dashboard-save-history"
-
-# claude-dashboard: Status bar with version check and mouse status
-set -g status-right-length 80
-set -g status-right "#(~/.local/bin/claude-dashboard-status-bar) | [F12] #[fg=#{?mouse,green,red}]Mouse:#{?mouse,ON,OFF}#[default] | %H:%M"
-set -g status-interval 5
-
-# claude-dashboard: Enable mouse mode by default
-set -g mouse on
-
-# claude-dashboard: Terminal overrides for better mouse support
-set -g terminal-overrides 'xterm*:smcup@:rmcup@'
-`
-
 	// Write cleaned config with new configuration
-	newConfig := strings.Join(cleanedLines, "\n") + config
+	newConfig := strings.Join(cleanedLines, "\n") + tmuxConfig(ConfiguredBindings())
 
-	if err := os.WriteFile(tmuxConfPath, []byte(newConfig), 0644); err != nil {
-		return fmt.Errorf("failed to write tmux config: %w", err)
+	changed, err := writeIfChanged(tmuxConfPath, []byte(newConfig), 0644)
+	if err != nil {
+		return changed, fmt.Errorf("failed to write tmux config: %w", err)
 	}
 
-	return nil
+	return changed, nil
 }
 
 // ReloadTmuxConfig reloads the tmux configuration
@@ -158,16 +201,29 @@ func ReloadTmuxConfig() error {
 	return nil
 }
 
-// UpdateVersionCache updates the cached version information
-func UpdateVersionCache(version string) error {
+// cacheDir returns ~/.cache/claude-dashboard, where the version cache and
+// the setup marker are kept.
+func cacheDir() (string, error) {
 	homeDir, err := os.UserHomeDir()
 	if err != nil {
-		return fmt.Errorf("failed to get home directory: %w", err)
+		return "", fmt.Errorf("failed to get home directory: %w", err)
 	}
+	return filepath.Join(homeDir, ".cache", "claude-dashboard"), nil
+}
 
-	cacheDir := filepath.Join(homeDir, ".cache", "claude-dashboard")
-	if err := os.MkdirAll(cacheDir, 0755); err != nil {
-		return fmt.Errorf("failed to create cache directory: %w", err)
+// UpdateVersionCache updates the cached version information. It runs on
+// every start, so an unchanged cache is only read.
+func UpdateVersionCache(version string) error {
+	_, err := updateVersionCache(version)
+	return err
+}
+
+// updateVersionCache updates the version cache, reporting whether it had
+// to change.
+func updateVersionCache(version string) (bool, error) {
+	dir, err := cacheDir()
+	if err != nil {
+		return false, err
 	}
 
 	// Normalize version format (ensure it starts with 'v')
@@ -175,100 +231,114 @@ func UpdateVersionCache(version string) error {
 		version = "v" + version
 	}
 
-	// Update current version cache
-	currentVersionFile := filepath.Join(cacheDir, "current-version")
+	currentVersionFile := filepath.Join(dir, "current-version")
+	if data, err := os.ReadFile(currentVersionFile); err == nil && string(data) == version {
+		return false, nil
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return false, fmt.Errorf("failed to create cache directory: %w", err)
+	}
 	if err := os.WriteFile(currentVersionFile, []byte(version), 0644); err != nil {
-		return fmt.Errorf("failed to write current version cache: %w", err)
+		return false, fmt.Errorf("failed to write current version cache: %w", err)
 	}
 
-	return nil
+	return true, nil
 }
 
-// Setup performs the complete setup
-func Setup(silent bool, version string) error {
-	if !silent {
-		fmt.Println("🔧 Installing claude-dashboard helper scripts...")
-	}
-
-	if err := InstallScripts(); err != nil {
-		return fmt.Errorf("failed to install scripts: %w", err)
-	}
+// setupMarker is the file in cacheDir recording that setup installed the
+// current helper scripts, so startup reads one file instead of checking
+// every script.
+const setupMarker = "setup-done"
 
-	if !silent {
-		fmt.Println("✅ Helper scripts installed to ~/.local/bin/")
-		fmt.Println()
-		fmt.Println("🔧 Configuring tmux...")
+// scriptsDigest identifies the embedded helper scripts; a release that
+// changes them no longer matches the marker of the previous one.
+func scriptsDigest() []byte {
+	h := sha256.New()
+	for _, script := range helperScripts {
+		h.Write([]byte(script.name))
+		h.Write(script.content)
 	}
+	return []byte(hex.EncodeToString(h.Sum(nil)))
+}
 
-	if err := SetupTmuxConfig(); err != nil {
-		return fmt.Errorf("failed to setup tmux config: %w", err)
+// markSetup writes the setup marker.
+func markSetup() error {
+	dir, err := cacheDir()
+	if err != nil {
+		return err
 	}
-
-	if !silent {
-		fmt.Println("✅ Tmux configuration added to ~/.tmux.conf")
-		fmt.Println()
-		fmt.Println("🔄 Reloading tmux configuration...")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("failed to create cache directory: %w", err)
 	}
+	return os.WriteFile(filepath.Join(dir, setupMarker), scriptsDigest(), 0644)
+}
 
-	if err := ReloadTmuxConfig(); err != nil {
-		if !silent {
-			fmt.Println("⚠️  Could not reload tmux (not running). Configuration will apply on next tmux start.")
-		}
-	} else {
-		if !silent {
-			fmt.Println("✅ Tmux configuration reloaded")
-		}
+// setupMarked reports whether the setup marker matches the current helper
+// scripts.
+func setupMarked() bool {
+	dir, err := cacheDir()
+	if err != nil {
+		return false
 	}
+	data, err := os.ReadFile(filepath.Join(dir, setupMarker))
+	return err == nil && bytes.Equal(data, scriptsDigest())
+}
 
-	// Update version cache
-	if version != "" && version != "dev" {
-		if !silent {
-			fmt.Println()
-			fmt.Println("🔄 Updating version cache...")
-		}
-
-		if err := UpdateVersionCache(version); err != nil {
-			if !silent {
-				fmt.Printf("⚠️  Warning: Could not update version cache: %v\n", err)
-			}
-		} else {
-			if !silent {
-				fmt.Println("✅ Version cache updated")
-			}
-		}
+// CheckSetup checks if setup has been completed. The marker written by
+// Setup answers with one read; without it the scripts are checked, and
+// the marker is written if they are all there. Scripts deleted after
+// setup are only noticed by doctor, which checks them directly.
+func CheckSetup() bool {
+	if setupMarked() {
+		return true
 	}
-
-	if !silent {
-		fmt.Println()
-		fmt.Println("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
-		fmt.Println("  🎉 Setup complete!")
-		fmt.Println("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
-		fmt.Println()
-		fmt.Println("  Press F12 in tmux to toggle mouse mode")
-		fmt.Println("  Press Ctrl+S in tmux to save entire pane history to file")
-		fmt.Println("  Check the status bar for version and mouse status")
-		fmt.Println()
+	if len(MissingScripts()) > 0 {
+		return false
 	}
-
-	return nil
+	_ = markSetup()
+	return true
 }
 
-// CheckSetup checks if setup has been completed
-func CheckSetup() bool {
+// MissingScripts returns the helper scripts not installed in ~/.local/bin.
+func MissingScripts() []string {
 	homeDir, err := os.UserHomeDir()
 	if err != nil {
-		return false
+		return []string{"(home directory unknown)"}
 	}
 
 	binDir := filepath.Join(homeDir, ".local", "bin")
 
-	// Check if all helper scripts exist
+	var missing []string
 	for _, script := range helperScripts {
 		scriptPath := filepath.Join(binDir, script.name)
 		if _, err := os.Stat(scriptPath); err != nil {
-			return false
+			missing = append(missing, script.name)
 		}
 	}
+	return missing
+}
 
-	return true
+// MissingTmuxConfig returns the settings of tmuxConfig, with the
+// configured keys, that ~/.tmux.conf lacks.
+func MissingTmuxConfig() ([]string, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get home directory: %w", err)
+	}
+	data, err := os.ReadFile(filepath.Join(homeDir, ".tmux.conf"))
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	have := make(map[string]bool)
+	for _, line := range strings.Split(string(data), "\n") {
+		have[strings.TrimSpace(line)] = true
+	}
+	var missing []string
+	for _, line := range strings.Split(tmuxConfig(ConfiguredBindings()), "\n") {
+		line = strings.TrimSpace(line)
+		if line != "" && !strings.HasPrefix(line, "#") && !have[line] {
+			missing = append(missing, line)
+		}
+	}
+	return missing, nil
 }
@@ -0,0 +1,129 @@
+package setup
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRun_secondRunChangesNothing(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	cfg := filepath.Join(t.TempDir(), "fleet.yaml")
+	if err := os.WriteFile(cfg, []byte("refresh_interval: 3s\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	opts := Options{ConfigFrom: cfg, NoTmuxConf: true}
+
+	r, err := Run(opts)
+	if err != nil || !r.Changed {
+		t.Fatalf("expected the first run to change things, got %+v, %v", r, err)
+	}
+	r, err = Run(opts)
+	if err != nil || r.Changed {
+		t.Fatalf("expected the second run to change nothing, got %+v, %v", r, err)
+	}
+	for _, st := range r.Steps {
+		if st.Name == "tmux_conf" && st.Status != StepSkipped {
+			t.Errorf("expected ~/.tmux.conf to be skipped, got %+v", st)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(home, ".tmux.conf")); !os.IsNotExist(err) {
+		t.Errorf("expected no ~/.tmux.conf, got %v", err)
+	}
+}
+
+func TestRun_differentConfigNeedsAssumeYes(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	cfg := filepath.Join(t.TempDir(), "fleet.yaml")
+	write := func(content string) {
+		if err := os.WriteFile(cfg, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("refresh_interval: 3s\n")
+	if _, err := Run(Options{ConfigFrom: cfg, NoTmuxConf: true}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	write("refresh_interval: 5s\n")
+	r, err := Run(Options{ConfigFrom: cfg, NoTmuxConf: true})
+	if err == nil || !strings.Contains(err.Error(), "--assume-yes") || r.Steps[0].Status != StepFailed {
+		t.Fatalf("expected a different config to be refused, got %+v, %v", r, err)
+	}
+	if r, err := Run(Options{ConfigFrom: cfg, NoTmuxConf: true, AssumeYes: true}); err != nil || r.Steps[0].Status != StepChanged {
+		t.Errorf("expected --assume-yes to replace it, got %+v, %v", r, err)
+	}
+
+	write("refresh_interval: soon\n")
+	if _, err := Run(Options{ConfigFrom: cfg, NoTmuxConf: true, AssumeYes: true}); err == nil {
+		t.Error("expected an invalid config to be refused")
+	}
+}
+
+func TestSetupTmuxConfig_bindsConfiguredKeys(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	conf := filepath.Join(home, ".tmux.conf")
+	if err := os.WriteFile(conf, []byte("set -g base-index 1\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := SetupTmuxConfig(); err != nil {
+		t.Fatal(err)
+	}
+	data, _ := os.ReadFile(conf)
+	if b := installedBindings(string(data)); b != (Bindings{MouseToggle: "F12", SaveHistory: "C-s"}) {
+		t.Fatalf("expected the default keys, got %+v", b)
+	}
+
+	cfgDir := filepath.Join(home, ".claude-dashboard")
+	os.MkdirAll(cfgDir, 0755)
+	if err := os.WriteFile(filepath.Join(cfgDir, "config.yaml"), []byte("tmux_mouse_toggle_key: F11\ntmux_save_history_key: none\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := SetupTmuxConfig(); err != nil {
+		t.Fatal(err)
+	}
+	data, _ = os.ReadFile(conf)
+	if b := installedBindings(string(data)); b != (Bindings{MouseToggle: "F11", SaveHistory: "none"}) {
+		t.Errorf("expected F11 and no save key, got %+v", b)
+	}
+	if !strings.HasPrefix(string(data), "set -g base-index 1\n") || strings.Count(string(data), "history-limit") != 1 {
+		t.Errorf("expected the user's settings kept and the block replaced, got:\n%s", data)
+	}
+	if !strings.Contains(string(data), "[F11] #[fg=") {
+		t.Errorf("expected the status bar to show F11, got:\n%s", data)
+	}
+}
+
+func TestReplacedKeys(t *testing.T) {
+	old := Bindings{MouseToggle: "F12", SaveHistory: "C-s"}
+	if got := replacedKeys(old, Bindings{MouseToggle: "C-s", SaveHistory: "none"}); len(got) != 1 || got[0] != "F12" {
+		t.Errorf("expected only F12 unbound, got %v", got)
+	}
+}
+
+func TestIxonEnabled(t *testing.T) {
+	linux := "speed 38400 baud; rows 50; columns 200; line = 0;\n-ignbrk brkint -ignpar -parmrk -inpck -istrip -inlcr -igncr icrnl ixon -ixoff\n"
+	macOS := "iflags: -istrip icrnl -inlcr -igncr -ixon ixoff ixany imaxbel iutf8\n"
+	if !ixonEnabled(linux) {
+		t.Error("expected ixon on")
+	}
+	if ixonEnabled(macOS) {
+		t.Error("expected -ixon to be off")
+	}
+}
+
+func TestFlowControlBindings(t *testing.T) {
+	defaults := Bindings{MouseToggle: "F12", SaveHistory: "C-s"}
+	if b, conflicts := flowControlBindings(defaults, false); b.SaveHistory != FlowControlSaveHistoryKey || len(conflicts) != 0 {
+		t.Errorf("expected the default C-s moved to %s, got %+v, %v", FlowControlSaveHistoryKey, b, conflicts)
+	}
+	if b, conflicts := flowControlBindings(defaults, true); b.SaveHistory != "C-s" || len(conflicts) != 1 {
+		t.Errorf("expected an explicit C-s kept and reported, got %+v, %v", b, conflicts)
+	}
+	if _, conflicts := flowControlBindings(Bindings{MouseToggle: "C-q", SaveHistory: "none"}, false); len(conflicts) != 1 || conflicts[0] != "C-q" {
+		t.Errorf("expected C-q reported, got %v", conflicts)
+	}
+}
@@ -2,39 +2,64 @@ package styles
 
 import "github.com/charmbracelet/lipgloss"
 
-// Colors
+// Colors of the current theme (see Apply).
 var (
-	ColorPrimary   = lipgloss.Color("#7C3AED") // Purple
-	ColorSecondary = lipgloss.Color("#06B6D4") // Cyan
-	ColorSuccess   = lipgloss.Color("#10B981") // Green
-	ColorWarning   = lipgloss.Color("#F59E0B") // Amber
-	ColorDanger    = lipgloss.Color("#EF4444") // Red
-	ColorMuted     = lipgloss.Color("#6B7280") // Gray
-	ColorBg        = lipgloss.Color("#1F2937") // Dark bg
-	ColorBgLight   = lipgloss.Color("#374151") // Light bg
-	ColorText      = lipgloss.Color("#F9FAFB") // White
-	ColorTextDim   = lipgloss.Color("#9CA3AF") // Dim text
+	ColorPrimary      lipgloss.Color
+	ColorSecondary    lipgloss.Color
+	ColorSuccess      lipgloss.Color
+	ColorWarning      lipgloss.Color
+	ColorDanger       lipgloss.Color
+	ColorMuted        lipgloss.Color
+	ColorBg           lipgloss.Color
+	ColorBgLight      lipgloss.Color
+	ColorText         lipgloss.Color
+	ColorTextDim      lipgloss.Color
+	ColorSelectedText lipgloss.Color
 )
 
-// Styles
+// Styles, built from the current theme.
 var (
+	Title, StatusBar, StatusKey, StatusVal, Active, Waiting, Selected, Help,
+	Error, Header, Confirm, LogViewer, DetailLabel, DetailValue, Muted,
+	Fresh, FreshDim, Match, CurrentMatch lipgloss.Style
+)
+
+func init() {
+	Apply(Themes[DefaultTheme])
+}
+
+// Apply makes t the current theme, rebuilding every style from it.
+func Apply(t Theme) {
+	Current = t
+	ColorPrimary = t.Primary
+	ColorSecondary = t.Secondary
+	ColorSuccess = t.Success
+	ColorWarning = t.Warning
+	ColorDanger = t.Danger
+	ColorMuted = t.Muted
+	ColorBg = t.Bg
+	ColorBgLight = t.BgLight
+	ColorText = t.Text
+	ColorTextDim = t.TextDim
+	ColorSelectedText = t.SelectedText
+
 	Title = lipgloss.NewStyle().
 		Bold(true).
 		Foreground(ColorPrimary).
 		PaddingLeft(1)
 
 	StatusBar = lipgloss.NewStyle().
-			Background(ColorBgLight).
-			Foreground(ColorText).
-			PaddingLeft(1).
-			PaddingRight(1)
+		Background(ColorBgLight).
+		Foreground(ColorText).
+		PaddingLeft(1).
+		PaddingRight(1)
 
 	StatusKey = lipgloss.NewStyle().
-			Foreground(ColorSecondary).
-			Bold(true)
+		Foreground(ColorSecondary).
+		Bold(true)
 
 	StatusVal = lipgloss.NewStyle().
-			Foreground(ColorTextDim)
+		Foreground(ColorTextDim)
 
 	Active = lipgloss.NewStyle().
 		Foreground(ColorSuccess).
@@ -44,9 +69,9 @@ var (
 		Foreground(ColorWarning)
 
 	Selected = lipgloss.NewStyle().
-			Background(ColorPrimary).
-			Foreground(ColorText).
-			Bold(true)
+		Background(ColorPrimary).
+		Foreground(ColorSelectedText).
+		Bold(true)
 
 	Help = lipgloss.NewStyle().
 		Foreground(ColorTextDim)
@@ -65,16 +90,32 @@ var (
 		Bold(true)
 
 	LogViewer = lipgloss.NewStyle().
-			Padding(0, 1)
+		Padding(0, 1)
 
 	DetailLabel = lipgloss.NewStyle().
-			Foreground(ColorSecondary).
-			Bold(true).
-			Width(14)
+		Foreground(ColorSecondary).
+		Bold(true).
+		Width(14)
 
 	DetailValue = lipgloss.NewStyle().
-			Foreground(ColorText)
+		Foreground(ColorText)
 
 	Muted = lipgloss.NewStyle().
 		Foreground(ColorMuted)
-)
+
+	Fresh = lipgloss.NewStyle().
+		Foreground(ColorSuccess).
+		Bold(true)
+
+	FreshDim = lipgloss.NewStyle().
+		Foreground(ColorTextDim)
+
+	Match = lipgloss.NewStyle().
+		Foreground(ColorBg).
+		Background(ColorWarning)
+
+	CurrentMatch = lipgloss.NewStyle().
+		Foreground(ColorSelectedText).
+		Background(ColorPrimary).
+		Bold(true)
+}
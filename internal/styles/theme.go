@@ -0,0 +1,133 @@
+package styles
+
+import (
+	"github.com/charmbracelet/lipgloss"
+	"github.com/seunggabi/claude-dashboard/internal/config"
+)
+
+// Theme is a palette the styles are built from.
+type Theme struct {
+	Primary      lipgloss.Color // titles, selection background
+	Secondary    lipgloss.Color // headers and labels
+	Success      lipgloss.Color
+	Warning      lipgloss.Color
+	Danger       lipgloss.Color
+	Muted        lipgloss.Color
+	Bg           lipgloss.Color
+	BgLight      lipgloss.Color // status bar background
+	Text         lipgloss.Color
+	TextDim      lipgloss.Color
+	SelectedText lipgloss.Color // text on the Primary background
+	Dark         bool           // meant for a dark terminal background
+}
+
+// DefaultTheme is the theme used when none is configured.
+const DefaultTheme = config.ThemeDark
+
+// Current is the theme last applied.
+var Current Theme
+
+// Themes are the built-in themes by config name.
+var Themes = map[string]Theme{
+	config.ThemeDark: {
+		Primary:      "#7C3AED", // Purple
+		Secondary:    "#06B6D4", // Cyan
+		Success:      "#10B981", // Green
+		Warning:      "#F59E0B", // Amber
+		Danger:       "#EF4444", // Red
+		Muted:        "#6B7280", // Gray
+		Bg:           "#1F2937", // Dark bg
+		BgLight:      "#374151", // Light bg
+		Text:         "#F9FAFB", // White
+		TextDim:      "#9CA3AF", // Dim text
+		SelectedText: "#F9FAFB",
+		Dark:         true,
+	},
+	config.ThemeLight: {
+		Primary:      "#6D28D9",
+		Secondary:    "#0E7490",
+		Success:      "#047857",
+		Warning:      "#B45309",
+		Danger:       "#B91C1C",
+		Muted:        "#6B7280",
+		Bg:           "#F9FAFB",
+		BgLight:      "#E5E7EB",
+		Text:         "#111827",
+		TextDim:      "#4B5563",
+		SelectedText: "#FFFFFF",
+	},
+	config.ThemeSolarized: {
+		Primary:      "#6C71C4", // violet
+		Secondary:    "#2AA198", // cyan
+		Success:      "#859900", // green
+		Warning:      "#B58900", // yellow
+		Danger:       "#DC322F", // red
+		Muted:        "#586E75", // base01
+		Bg:           "#002B36", // base03
+		BgLight:      "#073642", // base02
+		Text:         "#EEE8D5", // base2
+		TextDim:      "#839496", // base0
+		SelectedText: "#FDF6E3", // base3
+		Dark:         true,
+	},
+	// Pure black and white with saturated colors, all at least 7:1 against
+	// the background (WCAG AAA); the selection is black on yellow.
+	config.ThemeHighContrast: {
+		Primary:      "#FFFF00",
+		Secondary:    "#00FFFF",
+		Success:      "#00FF00",
+		Warning:      "#FFB000",
+		Danger:       "#FF6060",
+		Muted:        "#C0C0C0",
+		Bg:           "#000000",
+		BgLight:      "#303030",
+		Text:         "#FFFFFF",
+		TextDim:      "#E0E0E0",
+		SelectedText: "#000000",
+		Dark:         true,
+	},
+	// The Okabe-Ito palette, told apart with deuteranopia and protanopia:
+	// active is blue and waiting yellow rather than green and amber.
+	config.ThemeColorblind: {
+		Primary:      "#0072B2", // blue
+		Secondary:    "#CC79A7", // reddish purple
+		Success:      "#56B4E9", // sky blue
+		Warning:      "#F0E442", // yellow
+		Danger:       "#D55E00", // vermillion
+		Muted:        "#8C8C8C",
+		Bg:           "#1F2937",
+		BgLight:      "#374151",
+		Text:         "#F9FAFB",
+		TextDim:      "#BDBDBD",
+		SelectedText: "#FFFFFF",
+		Dark:         true,
+	},
+}
+
+// ThemeFor returns the named built-in theme (DefaultTheme if unknown) with
+// colors overridden by hex value, keyed as config.ThemeColorKeys.
+func ThemeFor(name string, colors map[string]string) Theme {
+	t, ok := Themes[name]
+	if !ok {
+		t = Themes[DefaultTheme]
+	}
+	fields := map[string]*lipgloss.Color{
+		"primary":       &t.Primary,
+		"secondary":     &t.Secondary,
+		"success":       &t.Success,
+		"warning":       &t.Warning,
+		"danger":        &t.Danger,
+		"muted":         &t.Muted,
+		"bg":            &t.Bg,
+		"bg_light":      &t.BgLight,
+		"text":          &t.Text,
+		"text_dim":      &t.TextDim,
+		"selected_text": &t.SelectedText,
+	}
+	for key, hex := range colors {
+		if f, ok := fields[key]; ok {
+			*f = lipgloss.Color(hex)
+		}
+	}
+	return t
+}
@@ -0,0 +1,154 @@
+package summary
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/seunggabi/claude-dashboard/internal/conversation"
+	"github.com/seunggabi/claude-dashboard/internal/locale"
+)
+
+// Format is an output format for Report.Write.
+type Format string
+
+const (
+	FormatMarkdown Format = "md"
+	FormatJSON     Format = "json"
+	FormatSlack    Format = "slack" // Slack mrkdwn, as posted to a webhook
+)
+
+// ParseFormat validates a format name given on the command line.
+func ParseFormat(s string) (Format, error) {
+	switch f := Format(strings.ToLower(s)); f {
+	case FormatMarkdown, FormatJSON, FormatSlack:
+		return f, nil
+	case "markdown":
+		return FormatMarkdown, nil
+	}
+	return "", fmt.Errorf("unknown summary format %q (want md, json or slack)", s)
+}
+
+// topicWidth is how many characters of a conversation's first prompt are
+// shown.
+const topicWidth = 100
+
+// markup is how a text format spells headings and emphasis.
+type markup struct {
+	heading    func(string) string
+	subheading func(string) string
+	bold       func(string) string
+	bullet     string
+}
+
+var (
+	markdownMarkup = markup{
+		heading:    func(s string) string { return "# " + s },
+		subheading: func(s string) string { return "## " + s },
+		bold:       func(s string) string { return "**" + s + "**" },
+		bullet:     "- ",
+	}
+	slackMarkup = markup{
+		heading:    func(s string) string { return "*" + s + "*" },
+		subheading: func(s string) string { return "*" + s + "*" },
+		bold:       func(s string) string { return "*" + s + "*" },
+		bullet:     "• ",
+	}
+)
+
+// Write writes the report in the given format.
+func (r Report) Write(w io.Writer, format Format) error {
+	switch format {
+	case FormatMarkdown:
+		_, err := io.WriteString(w, r.text(markdownMarkup))
+		return err
+	case FormatSlack:
+		_, err := io.WriteString(w, r.text(slackMarkup))
+		return err
+	case FormatJSON:
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(r)
+	}
+	return fmt.Errorf("unknown summary format %q", format)
+}
+
+// Title names the period, e.g. "Mon 24 Nov 2025" for a whole day.
+func (r Report) Title() string {
+	day := conversation.StartOfDay(r.From)
+	switch {
+	case r.From.Equal(day) && r.To.Equal(day.AddDate(0, 0, 1)):
+		return r.From.Format("Mon 2 Jan 2006")
+	case r.From.Equal(day) && r.To.Before(day.AddDate(0, 0, 1)):
+		return r.From.Format("Mon 2 Jan 2006") + " until " + r.To.Format("15:04")
+	}
+	return r.From.Format("2 Jan 15:04") + " – " + r.To.Format("2 Jan 15:04")
+}
+
+// text renders the report as a digest, one section per project.
+func (r Report) text(m markup) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s\n\n", m.heading("Claude summary: "+r.Title()))
+	if len(r.Projects) == 0 {
+		b.WriteString("No activity.\n")
+		return b.String()
+	}
+	fmt.Fprintf(&b, "%s · %s · %s · %s",
+		plural(len(r.Projects), "project"), plural(r.Conversations(), "conversation"),
+		plural(r.Commits(), "commit"), r.Spend)
+	if !r.Time.IsZero() {
+		fmt.Fprintf(&b, " · %s", r.Time)
+	}
+	b.WriteString("\n")
+
+	for _, p := range r.Projects {
+		fmt.Fprintf(&b, "\n%s\n", m.subheading(p.Dir))
+		var stats []string
+		if len(p.Conversations) > 0 {
+			stats = append(stats,
+				plural(len(p.Conversations), "conversation"), plural(p.Prompts, "prompt"),
+				plural(p.ToolCalls, "tool call"), p.Spend.String())
+		}
+		if len(p.Commits) > 0 {
+			stats = append(stats, plural(len(p.Commits), "commit"))
+		}
+		if !p.Time.IsZero() {
+			stats = append(stats, p.Time.String())
+		}
+		fmt.Fprintf(&b, "%s\n", strings.Join(stats, " · "))
+		if len(p.Issues) > 0 {
+			fmt.Fprintf(&b, "Issues: %s\n", strings.Join(p.Issues, ", "))
+		}
+
+		if key := p.Key(); len(key) > 0 {
+			b.WriteString("\nKey conversations:\n")
+			for _, a := range key {
+				topic := a.Topic
+				if topic == "" {
+					topic = "(no prompt)"
+				} else if runes := []rune(topic); len(runes) > topicWidth {
+					topic = string(runes[:topicWidth-1]) + "…"
+				}
+				fmt.Fprintf(&b, "%s%s–%s %s (%s)\n", m.bullet,
+					a.Start.In(r.From.Location()).Format("15:04"), a.End.In(r.From.Location()).Format("15:04"),
+					m.bold(topic), plural(a.Prompts, "prompt"))
+			}
+		}
+		if len(p.Commits) > 0 {
+			b.WriteString("\nCommits:\n")
+			for _, c := range p.Commits {
+				fmt.Fprintf(&b, "%s`%s` %s (%s)\n", m.bullet, c.Hash, c.Subject, c.Author)
+			}
+		}
+	}
+	return b.String()
+}
+
+// plural returns "1 commit" or "3 commits".
+func plural(n int, noun string) string {
+	if n == 1 {
+		return "1 " + noun
+	}
+	return locale.Current().Int(n) + " " + noun + "s"
+}
@@ -0,0 +1,211 @@
+// Package summary compiles a digest of what the sessions did over a period:
+// activity per project, the conversations that mattered most, commits made
+// in the projects' repositories, the time spent on them and what it all
+// cost.
+package summary
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/seunggabi/claude-dashboard/internal/conversation"
+	"github.com/seunggabi/claude-dashboard/internal/git"
+	"github.com/seunggabi/claude-dashboard/internal/session"
+	"github.com/seunggabi/claude-dashboard/internal/timesheet"
+)
+
+// keyConversations is how many conversations each project lists.
+const keyConversations = 3
+
+// Project is the activity in one working directory.
+type Project struct {
+	Dir           string                  `json:"dir"`
+	Conversations []conversation.Activity `json:"conversations"` // busiest first
+	Commits       []git.Commit            `json:"commits"`       // newest first
+	Prompts       int                     `json:"prompts"`
+	ToolCalls     int                     `json:"tool_calls"`
+	Spend         conversation.Spend      `json:"spend"`
+	Time          timesheet.Totals        `json:"time"`             // sessions attached to and working in Dir
+	Issues        []string                `json:"issues,omitempty"` // keys of the sessions' and commits' issues
+}
+
+// Key returns the conversations worth mentioning: the busiest few.
+func (p Project) Key() []conversation.Activity {
+	if len(p.Conversations) > keyConversations {
+		return p.Conversations[:keyConversations]
+	}
+	return p.Conversations
+}
+
+// Report is the digest of a period.
+type Report struct {
+	From     time.Time          `json:"from"`
+	To       time.Time          `json:"to"`
+	Projects []Project          `json:"projects"` // busiest first
+	Spend    conversation.Spend `json:"spend"`
+	Time     timesheet.Totals   `json:"time"`
+}
+
+// Day returns the period from midnight of t's day to the next midnight.
+func Day(t time.Time) (from, to time.Time) {
+	from = conversation.StartOfDay(t)
+	return from, from.AddDate(0, 0, 1)
+}
+
+// Conversations returns how many conversations the report covers.
+func (r Report) Conversations() int {
+	n := 0
+	for _, p := range r.Projects {
+		n += len(p.Conversations)
+	}
+	return n
+}
+
+// Commits returns how many commits the report covers.
+func (r Report) Commits() int {
+	n := 0
+	for _, p := range r.Projects {
+		n += len(p.Commits)
+	}
+	return n
+}
+
+// Issues says which issues the projects' work is linked to.
+type Issues struct {
+	ByDir    map[string][]string // issue keys of the sessions in each directory
+	Projects []string            // key prefixes looked for in commit subjects; none to skip them
+}
+
+// source supplies what a report is built from, so tests can replace the
+// conversation logs, git and the timesheet.
+type source struct {
+	activities func(ctx context.Context, from, to time.Time) ([]conversation.Activity, error)
+	log        func(ctx context.Context, dir string, since, until time.Time) ([]git.Commit, error)
+	topLevel   func(ctx context.Context, dir string) (string, error)
+	times      func(from, to time.Time) ([]timesheet.Entry, error)
+}
+
+var defaultSource = source{
+	activities: conversation.Activities,
+	log:        git.Log,
+	topLevel:   git.TopLevel,
+	times: func(from, to time.Time) ([]timesheet.Entry, error) {
+		return timesheet.Read(timesheet.Dir(), from, to)
+	},
+}
+
+// Build compiles the report for the period from..to. Besides the directories
+// conversations ran in, dirs (e.g. those of the dashboard's sessions) are
+// checked for commits. A repository's commits are listed once, under the
+// first of its directories seen. Each project lists the issues of its
+// sessions and those mentioned in its commits.
+func Build(ctx context.Context, from, to time.Time, dirs []string, issues Issues) (Report, error) {
+	return defaultSource.build(ctx, from, to, dirs, issues)
+}
+
+func (s source) build(ctx context.Context, from, to time.Time, dirs []string, issues Issues) (Report, error) {
+	activities, err := s.activities(ctx, from, to)
+	if err != nil {
+		return Report{}, err
+	}
+
+	r := Report{From: from, To: to}
+	byDir := make(map[string]*Project)
+	var order []string
+	project := func(dir string) *Project {
+		p, ok := byDir[dir]
+		if !ok {
+			p = &Project{Dir: dir}
+			byDir[dir] = p
+			order = append(order, dir)
+		}
+		return p
+	}
+	for _, a := range activities {
+		p := project(a.Dir)
+		p.Conversations = append(p.Conversations, a)
+		p.Prompts += a.Prompts
+		p.ToolCalls += a.ToolCalls
+		p.Spend = p.Spend.Plus(a.Spend)
+		r.Spend = r.Spend.Plus(a.Spend)
+	}
+	for _, dir := range dirs {
+		if dir != "" {
+			project(dir)
+		}
+	}
+	entries, err := s.times(from, to)
+	if err != nil {
+		return Report{}, err
+	}
+	for _, e := range entries {
+		if e.Path != "" {
+			project(e.Path).Time.Add(e, from, to)
+			r.Time.Add(e, from, to)
+		}
+	}
+
+	seen := make(map[string]bool) // repository roots already logged
+	for _, dir := range order {
+		root, err := s.topLevel(ctx, dir)
+		if err != nil {
+			if ctx.Err() != nil {
+				return r, ctx.Err()
+			}
+			continue // not a repository, or gone
+		}
+		if seen[root] {
+			continue
+		}
+		seen[root] = true
+		commits, err := s.log(ctx, root, from, to)
+		if err != nil {
+			if ctx.Err() != nil {
+				return r, ctx.Err()
+			}
+			continue
+		}
+		byDir[dir].Commits = commits
+	}
+
+	for _, dir := range order {
+		p := byDir[dir]
+		if len(p.Conversations) == 0 && len(p.Commits) == 0 && p.Time.IsZero() {
+			continue
+		}
+		p.Issues = projectIssues(*p, issues)
+		sort.SliceStable(p.Conversations, func(i, j int) bool {
+			return p.Conversations[i].Prompts > p.Conversations[j].Prompts
+		})
+		r.Projects = append(r.Projects, *p)
+	}
+	sort.SliceStable(r.Projects, func(i, j int) bool {
+		a, b := r.Projects[i], r.Projects[j]
+		if a.Prompts != b.Prompts {
+			return a.Prompts > b.Prompts
+		}
+		return len(a.Commits) > len(b.Commits)
+	})
+	return r, nil
+}
+
+// projectIssues returns the keys of the issues p's sessions are linked to,
+// then those its commits mention, without repeats.
+func projectIssues(p Project, issues Issues) []string {
+	keys := issues.ByDir[p.Dir]
+	if len(issues.Projects) > 0 {
+		for _, c := range p.Commits {
+			keys = append(keys, session.FindIssues(c.Subject, issues.Projects)...)
+		}
+	}
+	var out []string
+	seen := make(map[string]bool)
+	for _, k := range keys {
+		if k != "" && !seen[k] {
+			seen[k] = true
+			out = append(out, k)
+		}
+	}
+	return out
+}
@@ -0,0 +1,193 @@
+package summary
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/seunggabi/claude-dashboard/internal/conversation"
+	"github.com/seunggabi/claude-dashboard/internal/git"
+	"github.com/seunggabi/claude-dashboard/internal/timesheet"
+)
+
+var day = time.Date(2025, 11, 24, 0, 0, 0, 0, time.UTC)
+
+// fakeSource has conversations in /work/api (twice) and /work/web, with
+// /work/api/sub in the same repository as /work/api and /tmp outside any,
+// and time recorded in /work/api and /work/docs.
+func fakeSource() source {
+	at := func(h int) time.Time { return day.Add(time.Duration(h) * time.Hour) }
+	return source{
+		activities: func(context.Context, time.Time, time.Time) ([]conversation.Activity, error) {
+			return []conversation.Activity{
+				{Dir: "/work/web", Start: at(8), End: at(9), Prompts: 2, Topic: "restyle", Spend: conversation.Spend{Cost: 1}},
+				{Dir: "/work/api", Start: at(9), End: at(10), Prompts: 1, Topic: "small fix", Spend: conversation.Spend{Cost: 2}},
+				{Dir: "/work/api", Start: at(11), End: at(12), Prompts: 5, Topic: "big refactor", Spend: conversation.Spend{Cost: 3}},
+			}, nil
+		},
+		topLevel: func(_ context.Context, dir string) (string, error) {
+			switch dir {
+			case "/work/api", "/work/api/sub":
+				return "/work/api", nil
+			case "/work/web":
+				return "/work/web", nil
+			}
+			return "", git.ErrNotRepo
+		},
+		log: func(_ context.Context, dir string, _, _ time.Time) ([]git.Commit, error) {
+			if dir == "/work/api" {
+				return []git.Commit{{Hash: "abc1234", Author: "Ada", Subject: "[ENG-7] Refactor client"}}, nil
+			}
+			return nil, nil
+		},
+		times: func(time.Time, time.Time) ([]timesheet.Entry, error) {
+			return []timesheet.Entry{
+				{Kind: timesheet.KindActive, Path: "/work/api", Start: at(9), End: at(11)},
+				{Kind: timesheet.KindAttached, Path: "/work/api", Start: at(23), End: at(25)},
+				{Kind: timesheet.KindAttached, Path: "/work/docs", Start: at(14), End: at(14).Add(20 * time.Minute)},
+			}, nil
+		},
+	}
+}
+
+// ---------------------------------------------------------------------------
+// build
+// ---------------------------------------------------------------------------
+
+func TestBuild_groupsByProjectBusiestFirst(t *testing.T) {
+	r, err := fakeSource().build(context.Background(), day, day.AddDate(0, 0, 1), []string{"/work/api/sub", "/tmp"}, Issues{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(r.Projects) != 3 {
+		t.Fatalf("expected the three projects with activity, got %+v", r.Projects)
+	}
+	api := r.Projects[0]
+	if api.Dir != "/work/api" || api.Prompts != 6 || api.Spend.Cost != 5 {
+		t.Errorf("expected /work/api first with 6 prompts and $5, got %+v", api)
+	}
+	if api.Conversations[0].Topic != "big refactor" {
+		t.Errorf("expected the busiest conversation first, got %q", api.Conversations[0].Topic)
+	}
+	if len(api.Commits) != 1 || r.Commits() != 1 {
+		t.Errorf("expected the repository's commit listed once, got %d", r.Commits())
+	}
+	if api.Time.Active != 2*time.Hour || api.Time.Attached != time.Hour {
+		t.Errorf("expected 2h active and the hour attached before midnight, got %+v", api.Time)
+	}
+	if docs := r.Projects[2]; docs.Dir != "/work/docs" || docs.Time.Attached != 20*time.Minute {
+		t.Errorf("expected /work/docs listed for its time alone, got %+v", docs)
+	}
+	if r.Spend.Cost != 6 || r.Conversations() != 3 {
+		t.Errorf("unexpected totals: %+v, %d conversations", r.Spend, r.Conversations())
+	}
+}
+
+func TestBuild_sessionDirWithOnlyCommitsIsListed(t *testing.T) {
+	src := fakeSource()
+	src.activities = func(context.Context, time.Time, time.Time) ([]conversation.Activity, error) { return nil, nil }
+	r, _ := src.build(context.Background(), day, day.AddDate(0, 0, 1), []string{"/work/api", "/work/web"}, Issues{})
+	if len(r.Projects) != 2 || r.Projects[0].Dir != "/work/api" {
+		t.Errorf("expected /work/api, which has commits, and /work/docs, which has time, got %+v", r.Projects)
+	}
+}
+
+func TestBuild_listsIssuesOfSessionsAndCommits(t *testing.T) {
+	issues := Issues{ByDir: map[string][]string{"/work/api": {"ENG-12", "ENG-7"}}}
+	r, _ := fakeSource().build(context.Background(), day, day.AddDate(0, 0, 1), nil, issues)
+	if got := r.Projects[0].Issues; !reflect.DeepEqual(got, []string{"ENG-12", "ENG-7"}) {
+		t.Errorf("expected the sessions' issues, got %v", got)
+	}
+
+	r, _ = fakeSource().build(context.Background(), day, day.AddDate(0, 0, 1), nil, Issues{Projects: []string{"ENG"}})
+	if got := r.Projects[0].Issues; !reflect.DeepEqual(got, []string{"ENG-7"}) {
+		t.Errorf("expected the issue of the commit, got %v", got)
+	}
+	var b bytes.Buffer
+	r.Write(&b, FormatMarkdown)
+	if !strings.Contains(b.String(), "Issues: ENG-7\n") {
+		t.Errorf("expected the issues listed:\n%s", b.String())
+	}
+}
+
+func TestBuild_activityErrorFails(t *testing.T) {
+	src := fakeSource()
+	src.activities = func(context.Context, time.Time, time.Time) ([]conversation.Activity, error) {
+		return nil, errors.New("boom")
+	}
+	if _, err := src.build(context.Background(), day, day.AddDate(0, 0, 1), nil, Issues{}); err == nil {
+		t.Error("expected an error")
+	}
+}
+
+// ---------------------------------------------------------------------------
+// Write
+// ---------------------------------------------------------------------------
+
+func TestWrite_markdownDigest(t *testing.T) {
+	r, _ := fakeSource().build(context.Background(), day, day.AddDate(0, 0, 1), nil, Issues{})
+	var b bytes.Buffer
+	if err := r.Write(&b, FormatMarkdown); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	out := b.String()
+	for _, want := range []string{
+		"# Claude summary: Mon 24 Nov 2025",
+		"3 projects · 3 conversations · 1 commit · $6.00",
+		"tokens · attached 1h20m · active 2h\n",
+		"## /work/api",
+		"1 commit · attached 1h · active 2h",
+		"- 11:00–12:00 **big refactor** (5 prompts)",
+		"- `abc1234` [ENG-7] Refactor client (Ada)",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %q in:\n%s", want, out)
+		}
+	}
+}
+
+func TestWrite_slackUsesMrkdwn(t *testing.T) {
+	r, _ := fakeSource().build(context.Background(), day, day.AddDate(0, 0, 1), nil, Issues{})
+	var b bytes.Buffer
+	r.Write(&b, FormatSlack)
+	if out := b.String(); !strings.HasPrefix(out, "*Claude summary: Mon 24 Nov 2025*") || strings.Contains(out, "**") {
+		t.Errorf("expected Slack bold rather than markdown, got:\n%s", out)
+	}
+}
+
+func TestWrite_jsonRoundTrips(t *testing.T) {
+	r, _ := fakeSource().build(context.Background(), day, day.AddDate(0, 0, 1), nil, Issues{})
+	var b bytes.Buffer
+	if err := r.Write(&b, FormatJSON); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var back Report
+	if err := json.Unmarshal(b.Bytes(), &back); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if len(back.Projects) != 3 || back.Projects[0].Commits[0].Hash != "abc1234" || back.Time != r.Time {
+		t.Errorf("unexpected report %+v", back)
+	}
+}
+
+func TestWrite_emptyReport(t *testing.T) {
+	var b bytes.Buffer
+	Report{From: day, To: day.Add(15 * time.Hour)}.Write(&b, FormatMarkdown)
+	if got := b.String(); got != "# Claude summary: Mon 24 Nov 2025 until 15:00\n\nNo activity.\n" {
+		t.Errorf("unexpected output %q", got)
+	}
+}
+
+func TestParseFormat_rejectsUnknown(t *testing.T) {
+	if f, err := ParseFormat("markdown"); err != nil || f != FormatMarkdown {
+		t.Errorf("expected markdown to mean md, got %q, %v", f, err)
+	}
+	if _, err := ParseFormat("pdf"); err == nil {
+		t.Error("expected an error for pdf")
+	}
+}
@@ -0,0 +1,171 @@
+// Package testrun runs the test command configured for a project and reads
+// test results from pane output, so the dashboard can show whether the
+// latest change of a session broke its tests.
+package testrun
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"regexp"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// timeout bounds one test run, so a hanging suite is reported as failed
+// instead of showing as running forever.
+const timeout = 30 * time.Minute
+
+// Sources of a result.
+const (
+	SourceRun  = "run"  // the configured test command, run by the dashboard
+	SourcePane = "pane" // a test summary seen in the session's pane
+)
+
+// Result is the outcome of the latest test run of a session.
+type Result struct {
+	Running  bool          // the configured command is being run
+	Passed   bool          // meaningless while Running
+	Summary  string        // the line that gave the result, e.g. "5 passed in 1.20s"
+	Duration time.Duration // how long the tests took; 0 if unknown
+	Source   string        // SourceRun or SourcePane
+	At       time.Time     // when the run finished, or started while Running
+}
+
+// IsZero reports whether no result was seen.
+func (r Result) IsZero() bool {
+	return r == Result{}
+}
+
+// Short describes the result in a few columns, e.g. "✓ 1.2s".
+func (r Result) Short() string {
+	switch {
+	case r.IsZero():
+		return ""
+	case r.Running:
+		return "… running"
+	}
+	mark, word := "✗", "fail"
+	if r.Passed {
+		mark, word = "✓", "pass"
+	}
+	if r.Duration > 0 {
+		word = duration(r.Duration)
+	}
+	return mark + " " + word
+}
+
+// String describes the result in full, e.g. "passed in 1.2s: ok ./... (pane)".
+func (r Result) String() string {
+	switch {
+	case r.IsZero():
+		return ""
+	case r.Running:
+		return "running"
+	}
+	out := "failed"
+	if r.Passed {
+		out = "passed"
+	}
+	if r.Duration > 0 {
+		out += " in " + duration(r.Duration)
+	}
+	if r.Summary != "" {
+		out += ": " + r.Summary
+	}
+	return out + " (" + r.Source + ")"
+}
+
+// duration writes d as e.g. "850ms", "1.2s" or "3m5s".
+func duration(d time.Duration) string {
+	switch {
+	case d < time.Second:
+		return d.Round(time.Millisecond).String()
+	case d < time.Minute:
+		return strconv.FormatFloat(d.Seconds(), 'f', 1, 64) + "s"
+	}
+	return strings.TrimSuffix(d.Round(time.Second).String(), "0s")
+}
+
+// Run runs command with the shell in dir and reports whether it exited
+// successfully, summarised by its last output line, or its test summary
+// when the output has one.
+func Run(ctx context.Context, dir, command string) Result {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	cmd := exec.CommandContext(ctx, "sh", "-c", command)
+	if runtime.GOOS == "windows" {
+		cmd = exec.CommandContext(ctx, "cmd", "/C", command)
+	}
+	cmd.Dir = dir
+	var out bytes.Buffer
+	cmd.Stdout = &out
+	cmd.Stderr = &out
+	start := time.Now()
+	err := cmd.Run()
+	r := Result{Passed: err == nil, Duration: time.Since(start), Source: SourceRun, At: time.Now()}
+	r.Summary = lastLine(out.String())
+	if parsed, ok := Parse(out.String()); ok {
+		r.Summary = parsed.Summary
+	}
+	var exitErr *exec.ExitError
+	switch {
+	case ctx.Err() != nil:
+		r.Summary = fmt.Sprintf("timed out after %s", duration(timeout))
+	case err != nil && !errors.As(err, &exitErr):
+		r.Summary = err.Error() // the shell could not be started
+	}
+	return r
+}
+
+// lastLine returns the last non-blank line of s, trimmed.
+func lastLine(s string) string {
+	lines := strings.Split(strings.TrimSpace(s), "\n")
+	return strings.TrimSpace(lines[len(lines)-1])
+}
+
+// summaries are the result lines of common test runners, with whether each
+// says the tests failed and how long they took.
+var summaries = []struct {
+	re     *regexp.Regexp
+	failed func(m []string) bool
+}{
+	// go test: "ok  	example.com/pkg	0.012s", "FAIL	example.com/pkg	0.3s",
+	// and a bare "FAIL" closing a run with a failed package
+	{regexp.MustCompile(`^(ok|FAIL)\s+\S+\s+(?:(\d+(?:\.\d+)?s)|\(cached\)|\[(?:build|setup) failed\])`), func(m []string) bool { return m[1] == "FAIL" }},
+	{regexp.MustCompile(`^(FAIL)$`), func([]string) bool { return true }},
+	// pytest: "==== 2 failed, 5 passed in 1.20s ===="
+	{regexp.MustCompile(`^=+ (.*\b(?:passed|failed|errors?)\b.*) in (\d+(?:\.\d+)?s)(?: \([^)]*\))? =+$`), func(m []string) bool {
+		return strings.Contains(m[1], "failed") || strings.Contains(m[1], "error")
+	}},
+	// cargo test: "test result: ok. 3 passed; 0 failed; ...; finished in 0.01s"
+	{regexp.MustCompile(`^test result: (ok|FAILED)\..*finished in (\d+(?:\.\d+)?s)`), func(m []string) bool { return m[1] == "FAILED" }},
+	// jest and vitest: "Tests:       1 failed, 4 passed, 5 total", "Tests  4 passed (4)"
+	{regexp.MustCompile(`^Tests:?\s+(.*\b(?:passed|failed)\b.*)$`), func(m []string) bool { return strings.Contains(m[1], "failed") }},
+}
+
+// Parse finds the last test summary in content, such as pane output, and
+// returns its result. Go, pytest, cargo, jest and vitest summaries are
+// recognised.
+func Parse(content string) (Result, bool) {
+	lines := strings.Split(content, "\n")
+	for i := len(lines) - 1; i >= 0; i-- {
+		line := strings.TrimSpace(lines[i])
+		for _, s := range summaries {
+			m := s.re.FindStringSubmatch(line)
+			if m == nil {
+				continue
+			}
+			r := Result{Passed: !s.failed(m), Summary: line, Source: SourcePane}
+			if len(m) > 2 {
+				r.Duration, _ = time.ParseDuration(m[2])
+			}
+			return r, true
+		}
+	}
+	return Result{}, false
+}
@@ -0,0 +1,94 @@
+package testrun
+
+import (
+	"context"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+)
+
+// ---------------------------------------------------------------------------
+// Parse
+// ---------------------------------------------------------------------------
+
+func TestParse_summariesOfCommonRunners(t *testing.T) {
+	cases := []struct {
+		content  string
+		passed   bool
+		duration time.Duration
+	}{
+		{"=== RUN   TestA\n--- PASS: TestA\nPASS\nok  \texample.com/api\t0.012s\n", true, 12 * time.Millisecond},
+		{"ok  \texample.com/api\t0.5s\nFAIL\texample.com/web\t1.5s\nFAIL\n", false, 0},
+		{"ok  \texample.com/api\t(cached)\n", true, 0},
+		{"tests/test_api.py ..F\n===== 1 failed, 2 passed in 1.20s =====\n", false, 1200 * time.Millisecond},
+		{"===== 3 passed, 1 warning in 0.50s =====", true, 500 * time.Millisecond},
+		{"test result: ok. 3 passed; 0 failed; 0 ignored; 0 measured; 0 filtered out; finished in 0.01s", true, 10 * time.Millisecond},
+		{"Test Suites: 1 failed, 1 total\nTests:       1 failed, 4 passed, 5 total\nTime:        2.1 s\n", false, 0},
+		{" Tests  4 passed (4)\n", true, 0},
+	}
+	for _, tc := range cases {
+		r, ok := Parse(tc.content)
+		if !ok || r.Passed != tc.passed || r.Duration != tc.duration || r.Source != SourcePane {
+			t.Errorf("Parse(%q): expected passed=%v in %s, got %+v, %v", tc.content, tc.passed, tc.duration, r, ok)
+		}
+	}
+}
+
+func TestParse_latestSummaryWinsAndProseIsIgnored(t *testing.T) {
+	content := "FAIL\texample.com/api\t0.3s\nFAIL\n> fixed the nil check\nok  \texample.com/api\t0.2s\n> ok I will also add a test\n"
+	r, ok := Parse(content)
+	if !ok || !r.Passed || r.Summary != "ok  \texample.com/api\t0.2s" {
+		t.Errorf("expected the later passing run, got %+v, %v", r, ok)
+	}
+	if _, ok := Parse("> ok, running the tests now\nAll good"); ok {
+		t.Error("expected no summary in prose")
+	}
+}
+
+// ---------------------------------------------------------------------------
+// Result
+// ---------------------------------------------------------------------------
+
+func TestResult_shortAndLongForms(t *testing.T) {
+	cases := []struct {
+		r           Result
+		short, long string
+	}{
+		{Result{}, "", ""},
+		{Result{Running: true, At: time.Now()}, "… running", "running"},
+		{Result{Passed: true, Duration: 1200 * time.Millisecond, Summary: "5 passed", Source: SourceRun}, "✓ 1.2s", "passed in 1.2s: 5 passed (run)"},
+		{Result{Summary: "FAIL", Source: SourcePane}, "✗ fail", "failed: FAIL (pane)"},
+		{Result{Passed: true, Duration: 185 * time.Second, Source: SourceRun}, "✓ 3m5s", "passed in 3m5s (run)"},
+	}
+	for _, tc := range cases {
+		if got := tc.r.Short(); got != tc.short {
+			t.Errorf("Short(%+v): expected %q, got %q", tc.r, tc.short, got)
+		}
+		if got := tc.r.String(); got != tc.long {
+			t.Errorf("String(%+v): expected %q, got %q", tc.r, tc.long, got)
+		}
+	}
+}
+
+// ---------------------------------------------------------------------------
+// Run
+// ---------------------------------------------------------------------------
+
+func TestRun_exitStatusAndSummary(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses sh")
+	}
+	dir := t.TempDir()
+	r := Run(context.Background(), dir, "echo '===== 2 passed in 0.10s ====='; echo done")
+	if !r.Passed || r.Summary != "===== 2 passed in 0.10s =====" || r.Source != SourceRun || r.At.IsZero() {
+		t.Errorf("unexpected passing result %+v", r)
+	}
+	r = Run(context.Background(), dir, "pwd; echo boom >&2; exit 3")
+	if r.Passed || r.Summary != "boom" {
+		t.Errorf("unexpected failing result %+v", r)
+	}
+	if r := Run(context.Background(), dir, "pwd"); !strings.HasSuffix(r.Summary, dir[strings.LastIndex(dir, "/"):]) {
+		t.Errorf("expected the command to run in %s, got %+v", dir, r)
+	}
+}
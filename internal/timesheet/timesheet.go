@@ -0,0 +1,337 @@
+// Package timesheet records how long sessions were attached to and how
+// long claude worked in them, so the time spent supervising each project's
+// sessions can be billed. Time is recorded while the dashboard runs; time
+// recorded by several dashboards at once is counted once.
+package timesheet
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/seunggabi/claude-dashboard/internal/config"
+	"github.com/seunggabi/claude-dashboard/internal/conversation"
+	"github.com/seunggabi/claude-dashboard/internal/session"
+)
+
+// Kind is what a stretch of time was spent on.
+type Kind string
+
+const (
+	KindAttached Kind = "attached" // someone was attached to the session
+	KindActive   Kind = "active"   // claude was working in it
+)
+
+var kinds = []Kind{KindAttached, KindActive}
+
+// Entry is one stretch of a session being attached to or working.
+type Entry struct {
+	Kind    Kind      `json:"kind"`
+	Session string    `json:"session"`
+	Host    string    `json:"host,omitempty"`
+	Project string    `json:"project,omitempty"`
+	Path    string    `json:"path,omitempty"`
+	Start   time.Time `json:"start"`
+	End     time.Time `json:"end"`
+}
+
+// newEntry starts an entry of kind for s at start.
+func newEntry(kind Kind, s session.Session, start time.Time) Entry {
+	return Entry{Kind: kind, Session: s.Name, Host: s.Host, Project: s.Project, Path: s.Path, Start: start, End: start}
+}
+
+// Totals is the time spent on a session or project.
+type Totals struct {
+	Attached time.Duration
+	Active   time.Duration
+}
+
+// Add counts the part of e between from and to.
+func (t *Totals) Add(e Entry, from, to time.Time) {
+	start, end := e.Start, e.End
+	if start.Before(from) {
+		start = from
+	}
+	if end.After(to) {
+		end = to
+	}
+	if !end.After(start) {
+		return
+	}
+	switch e.Kind {
+	case KindAttached:
+		t.Attached += end.Sub(start)
+	case KindActive:
+		t.Active += end.Sub(start)
+	}
+}
+
+// Plus returns the sum of t and o.
+func (t Totals) Plus(o Totals) Totals {
+	return Totals{Attached: t.Attached + o.Attached, Active: t.Active + o.Active}
+}
+
+// IsZero reports whether no time was recorded.
+func (t Totals) IsZero() bool {
+	return t.Attached == 0 && t.Active == 0
+}
+
+// String writes the totals as e.g. "attached 1h12m · active 3h4m".
+func (t Totals) String() string {
+	return "attached " + duration(t.Attached) + " · active " + duration(t.Active)
+}
+
+// totalsJSON is Totals as JSON, in seconds.
+type totalsJSON struct {
+	Attached int `json:"attached_seconds"`
+	Active   int `json:"active_seconds"`
+}
+
+// MarshalJSON writes the totals in seconds.
+func (t Totals) MarshalJSON() ([]byte, error) {
+	return json.Marshal(totalsJSON{Attached: int(t.Attached.Seconds()), Active: int(t.Active.Seconds())})
+}
+
+// UnmarshalJSON reads totals written by MarshalJSON.
+func (t *Totals) UnmarshalJSON(data []byte) error {
+	var secs totalsJSON
+	if err := json.Unmarshal(data, &secs); err != nil {
+		return err
+	}
+	t.Attached = time.Duration(secs.Attached) * time.Second
+	t.Active = time.Duration(secs.Active) * time.Second
+	return nil
+}
+
+// duration writes d to the minute, e.g. "12m", "1h5m" or "2h".
+func duration(d time.Duration) string {
+	d = d.Round(time.Minute)
+	if d == 0 {
+		return "0m"
+	}
+	s := strings.TrimSuffix(d.String(), "0s")
+	if strings.HasSuffix(s, "h0m") {
+		s = strings.TrimSuffix(s, "0m")
+	}
+	return s
+}
+
+// Dir returns the directory entries are kept in, one file per day.
+func Dir() string {
+	return filepath.Join(config.ConfigDir(), "timesheet")
+}
+
+// dayFile returns the file of the day of t.
+func dayFile(dir string, t time.Time) string {
+	return filepath.Join(dir, t.Format("2006-01-02")+".jsonl")
+}
+
+// Append adds e to the files in dir, split at midnight so each day's file
+// holds that day's part.
+func Append(dir string, e Entry) error {
+	if !e.End.After(e.Start) {
+		return nil
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	for e.Start.Before(e.End) {
+		part := e
+		if midnight := conversation.StartOfDay(e.Start).AddDate(0, 0, 1); part.End.After(midnight) {
+			part.End = midnight
+		}
+		if err := appendLine(dayFile(dir, part.Start), part); err != nil {
+			return err
+		}
+		e.Start = part.End
+	}
+	return nil
+}
+
+func appendLine(path string, e Entry) error {
+	line, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0644)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(append(line, '\n')); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// Read returns the entries in dir overlapping from..to, merged (see
+// Merge). Lines that cannot be read are skipped.
+func Read(dir string, from, to time.Time) ([]Entry, error) {
+	var entries []Entry
+	for day := conversation.StartOfDay(from); day.Before(to); day = day.AddDate(0, 0, 1) {
+		f, err := os.Open(dayFile(dir, day))
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return entries, err
+		}
+		sc := bufio.NewScanner(f)
+		for sc.Scan() {
+			var e Entry
+			if json.Unmarshal(sc.Bytes(), &e) != nil {
+				continue
+			}
+			if e.End.After(from) && e.Start.Before(to) {
+				entries = append(entries, e)
+			}
+		}
+		err = sc.Err()
+		f.Close()
+		if err != nil {
+			return Merge(entries), fmt.Errorf("reading the timesheet of %s: %w", day.Format("2006-01-02"), err)
+		}
+	}
+	return Merge(entries), nil
+}
+
+// Merge joins the overlapping entries of each session and kind into one,
+// so time is counted once however many dashboards recorded it: each
+// running dashboard writes its own entries for the sessions it sees. The
+// entries are returned by start.
+func Merge(entries []Entry) []Entry {
+	sorted := slices.Clone(entries)
+	slices.SortStableFunc(sorted, func(a, b Entry) int { return a.Start.Compare(b.Start) })
+	var merged []Entry
+	last := make(map[string]int) // by key, the index in merged of its latest entry
+	for _, e := range sorted {
+		k := key(e.Kind, e.Host, e.Session)
+		if i, ok := last[k]; ok && !e.Start.After(merged[i].End) {
+			if e.End.After(merged[i].End) {
+				merged[i].End = e.End
+			}
+			continue
+		}
+		last[k] = len(merged)
+		merged = append(merged, e)
+	}
+	return merged
+}
+
+// Sheet turns listings of sessions into entries written to a directory.
+type Sheet struct {
+	mu   sync.Mutex
+	dir  string
+	open map[string]Entry // by key
+}
+
+// New returns a sheet writing to dir.
+func New(dir string) *Sheet {
+	return &Sheet{dir: dir, open: make(map[string]Entry)}
+}
+
+// key identifies the stretch of kind of the session called name on host.
+func key(kind Kind, host, name string) string {
+	return string(kind) + " " + host + "/" + name
+}
+
+// Observe records the sessions of host (empty for the local machine)
+// listed at now: a stretch starts when a session is first seen attached or
+// working, and is written when it no longer is or goes away. A working
+// stretch that ended in a gap between listings, as while the dashboard was
+// attached, ends at the session's last activity.
+func (s *Sheet) Observe(host string, sessions []session.Session, now time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var errs []error
+	listed := make(map[string]bool)
+	for _, sess := range sessions {
+		for _, kind := range kinds {
+			k := key(kind, host, sess.Name)
+			listed[k] = true
+			on := sess.Attached
+			end := now
+			if kind == KindActive {
+				on = sess.Status == session.StatusActive
+				if !sess.Activity.IsZero() && sess.Activity.Before(now) {
+					end = sess.Activity
+				}
+			}
+			e, open := s.open[k]
+			switch {
+			case on && !open:
+				e = newEntry(kind, sess, now)
+				e.Host = host
+				s.open[k] = e
+			case !on && open:
+				if end.Before(e.Start) {
+					end = e.Start
+				}
+				errs = append(errs, s.close(k, end))
+			}
+		}
+	}
+	for k, e := range s.open {
+		if e.Host == host && !listed[k] {
+			errs = append(errs, s.close(k, now))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// close writes the open stretch k, ending at end. The caller holds s.mu.
+func (s *Sheet) close(k string, end time.Time) error {
+	e := s.open[k]
+	delete(s.open, k)
+	e.End = end
+	return Append(s.dir, e)
+}
+
+// Attached records the dashboard attaching to sess from start to end; the
+// sheet sees no listings meanwhile.
+func (s *Sheet) Attached(sess session.Session, start, end time.Time) error {
+	e := newEntry(KindAttached, sess, start)
+	e.End = end
+	return Append(s.dir, e)
+}
+
+// Close writes every open stretch, ending at now.
+func (s *Sheet) Close(now time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var errs []error
+	for k := range s.open {
+		errs = append(errs, s.close(k, now))
+	}
+	return errors.Join(errs...)
+}
+
+// Today returns the time recorded for sess on the day of now, including
+// stretches still open.
+func (s *Sheet) Today(sess session.Session, now time.Time) (Totals, error) {
+	from := conversation.StartOfDay(now)
+	entries, err := Read(s.dir, from, now)
+	s.mu.Lock()
+	for _, kind := range kinds {
+		if e, ok := s.open[key(kind, sess.Host, sess.Name)]; ok {
+			e.End = now
+			entries = append(entries, e)
+		}
+	}
+	s.mu.Unlock()
+	var t Totals
+	for _, e := range Merge(entries) {
+		if e.Host == sess.Host && e.Session == sess.Name {
+			t.Add(e, from, now)
+		}
+	}
+	return t, err
+}
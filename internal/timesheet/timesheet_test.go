@@ -0,0 +1,163 @@
+package timesheet
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/seunggabi/claude-dashboard/internal/session"
+)
+
+var day = time.Date(2025, 11, 24, 0, 0, 0, 0, time.Local)
+
+func at(h, m int) time.Time {
+	return day.Add(time.Duration(h)*time.Hour + time.Duration(m)*time.Minute)
+}
+
+// ---------------------------------------------------------------------------
+// Append and Read
+// ---------------------------------------------------------------------------
+
+func TestAppend_splitsAtMidnight(t *testing.T) {
+	dir := t.TempDir()
+	e := Entry{Kind: KindAttached, Session: "cd-api", Start: at(23, 0), End: at(25, 30)}
+	if err := Append(dir, e); err != nil {
+		t.Fatalf("Append: %v", err)
+	}
+	for _, name := range []string{"2025-11-24.jsonl", "2025-11-25.jsonl"} {
+		if _, err := os.Stat(filepath.Join(dir, name)); err != nil {
+			t.Errorf("expected %s: %v", name, err)
+		}
+	}
+
+	next, err := Read(dir, at(24, 0), at(48, 0))
+	if err != nil {
+		t.Fatalf("Read: %v", err)
+	}
+	if len(next) != 1 || !next[0].Start.Equal(at(24, 0)) || !next[0].End.Equal(at(25, 30)) {
+		t.Errorf("expected the part after midnight, got %+v", next)
+	}
+}
+
+func TestRead_missingDirIsEmpty(t *testing.T) {
+	entries, err := Read(filepath.Join(t.TempDir(), "none"), day, day.AddDate(0, 0, 7))
+	if err != nil || len(entries) != 0 {
+		t.Errorf("expected nothing, got %v, %v", entries, err)
+	}
+}
+
+// ---------------------------------------------------------------------------
+// Totals
+// ---------------------------------------------------------------------------
+
+func TestTotals_addClipsToThePeriod(t *testing.T) {
+	var tot Totals
+	tot.Add(Entry{Kind: KindActive, Start: at(8, 0), End: at(10, 0)}, at(9, 0), at(12, 0))
+	tot.Add(Entry{Kind: KindAttached, Start: at(9, 30), End: at(9, 42)}, at(9, 0), at(12, 0))
+	tot.Add(Entry{Kind: KindAttached, Start: at(13, 0), End: at(14, 0)}, at(9, 0), at(12, 0))
+	if tot.Active != time.Hour || tot.Attached != 12*time.Minute {
+		t.Errorf("unexpected totals %+v", tot)
+	}
+	if got := tot.String(); got != "attached 12m · active 1h" {
+		t.Errorf("unexpected text %q", got)
+	}
+}
+
+// ---------------------------------------------------------------------------
+// Sheet
+// ---------------------------------------------------------------------------
+
+func TestSheet_recordsStretchesFromListings(t *testing.T) {
+	dir := t.TempDir()
+	sh := New(dir)
+	api := session.Session{Name: "cd-api", Path: "/src/api", Status: session.StatusActive, Attached: true}
+	sh.Observe("", []session.Session{api}, at(9, 0))
+
+	api.Attached = false
+	sh.Observe("", []session.Session{api}, at(9, 10))
+
+	// Idle since 9:40, seen only at 10:00 after a gap in the listings.
+	api.Status, api.Activity = session.StatusIdle, at(9, 40)
+	sh.Observe("", []session.Session{api}, at(10, 0))
+
+	got, err := sh.Today(api, at(12, 0))
+	if err != nil {
+		t.Fatalf("Today: %v", err)
+	}
+	if got.Attached != 10*time.Minute || got.Active != 40*time.Minute {
+		t.Errorf("expected 10m attached and 40m active, got %+v", got)
+	}
+}
+
+func TestSheet_goneSessionsAndCloseEndOpenStretches(t *testing.T) {
+	dir := t.TempDir()
+	sh := New(dir)
+	web := session.Session{Name: "cd-web", Host: "devbox", Status: session.StatusActive}
+	api := session.Session{Name: "cd-api", Status: session.StatusActive}
+	sh.Observe("devbox", []session.Session{web}, at(9, 0))
+	sh.Observe("", []session.Session{api}, at(9, 0))
+
+	sh.Observe("devbox", nil, at(9, 30))
+	if err := sh.Close(at(11, 0)); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	if got, _ := sh.Today(web, at(12, 0)); got.Active != 30*time.Minute {
+		t.Errorf("expected the devbox session to stop when it went away, got %+v", got)
+	}
+	if got, _ := sh.Today(api, at(12, 0)); got.Active != 2*time.Hour {
+		t.Errorf("expected the local session to run until Close, got %+v", got)
+	}
+}
+
+func TestSheet_openStretchCountsToday(t *testing.T) {
+	sh := New(t.TempDir())
+	api := session.Session{Name: "cd-api", Attached: true}
+	sh.Observe("", []session.Session{api}, at(9, 0))
+	if got, _ := sh.Today(api, at(9, 25)); got.Attached != 25*time.Minute {
+		t.Errorf("expected the open stretch counted, got %+v", got)
+	}
+}
+
+func TestSheet_attachedFromTheDashboard(t *testing.T) {
+	sh := New(t.TempDir())
+	api := session.Session{Name: "cd-api", Path: "/src/api"}
+	if err := sh.Attached(api, at(9, 0), at(9, 45)); err != nil {
+		t.Fatalf("Attached: %v", err)
+	}
+	if got, _ := sh.Today(api, at(12, 0)); got.Attached != 45*time.Minute {
+		t.Errorf("expected 45m attached, got %+v", got)
+	}
+}
+
+func TestSheet_twoDashboardsCountTimeOnce(t *testing.T) {
+	dir := t.TempDir()
+	first, second := New(dir), New(dir)
+	api := session.Session{Name: "cd-api", Status: session.StatusActive, Attached: true}
+	first.Observe("", []session.Session{api}, at(9, 0))
+	second.Observe("", []session.Session{api}, at(9, 20)) // started later
+
+	api.Status, api.Attached = session.StatusIdle, false
+	first.Observe("", []session.Session{api}, at(10, 0))
+	second.Observe("", []session.Session{api}, at(10, 0))
+
+	got, err := first.Today(api, at(12, 0))
+	if err != nil {
+		t.Fatalf("Today: %v", err)
+	}
+	if got.Attached != time.Hour || got.Active != time.Hour {
+		t.Errorf("expected one hour of each, got %+v", got)
+	}
+	if entries, _ := Read(dir, day, at(12, 0)); len(entries) != 2 {
+		t.Errorf("expected the overlapping entries merged into one per kind, got %+v", entries)
+	}
+}
+
+func TestMerge_keepsSeparateStretchesAndSessions(t *testing.T) {
+	api := Entry{Kind: KindActive, Session: "cd-api", Start: at(9, 0), End: at(9, 30)}
+	later := Entry{Kind: KindActive, Session: "cd-api", Start: at(10, 0), End: at(10, 30)}
+	web := Entry{Kind: KindActive, Session: "cd-web", Start: at(9, 10), End: at(9, 20)}
+	if got := Merge([]Entry{later, web, api}); len(got) != 3 || got[0] != api || got[1] != web || got[2] != later {
+		t.Errorf("expected the three entries kept, by start, got %+v", got)
+	}
+}
@@ -0,0 +1,89 @@
+// Package timing records how long the phases of startup take, for the
+// --timings report.
+package timing
+
+import (
+	"fmt"
+	"io"
+	"text/tabwriter"
+	"time"
+)
+
+// Phase is one measured step of startup.
+type Phase struct {
+	Name string
+	Took time.Duration
+}
+
+// Recorder measures consecutive phases: each Mark ends the phase begun by
+// the previous one, or by New. A nil Recorder records nothing, so code on
+// the startup path can mark phases unconditionally.
+type Recorder struct {
+	now    func() time.Time
+	start  time.Time
+	last   time.Time
+	phases []Phase
+}
+
+// New returns a recorder whose first phase starts now.
+func New() *Recorder {
+	return newRecorder(time.Now)
+}
+
+func newRecorder(now func() time.Time) *Recorder {
+	t := now()
+	return &Recorder{now: now, start: t, last: t}
+}
+
+// Mark ends the current phase, naming it.
+func (r *Recorder) Mark(name string) {
+	if r == nil {
+		return
+	}
+	t := r.now()
+	r.phases = append(r.phases, Phase{Name: name, Took: t.Sub(r.last)})
+	r.last = t
+}
+
+// Phases returns the phases marked so far, in order.
+func (r *Recorder) Phases() []Phase {
+	if r == nil {
+		return nil
+	}
+	return r.phases
+}
+
+// Total returns the time from New to the last Mark.
+func (r *Recorder) Total() time.Duration {
+	if r == nil {
+		return 0
+	}
+	return r.last.Sub(r.start)
+}
+
+// Write prints each phase with its share of the total, then the total.
+func (r *Recorder) Write(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', tabwriter.AlignRight)
+	total := r.Total()
+	for _, p := range r.Phases() {
+		share := 0.0
+		if total > 0 {
+			share = float64(p.Took) / float64(total) * 100
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%.0f%%\t\n", p.Name, format(p.Took), share)
+	}
+	fmt.Fprintf(tw, "total\t%s\t\t\n", format(total))
+	return tw.Flush()
+}
+
+// format rounds d to a readable precision, e.g. "1.24ms" or "312µs".
+func format(d time.Duration) string {
+	switch {
+	case d >= time.Second:
+		return d.Round(time.Millisecond).String()
+	case d >= time.Millisecond:
+		return d.Round(10 * time.Microsecond).String()
+	default:
+		return d.Round(time.Microsecond).String()
+	}
+}
@@ -0,0 +1,56 @@
+package timing
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+// ---------------------------------------------------------------------------
+// Recorder
+// ---------------------------------------------------------------------------
+
+// fakeClock returns times advancing by the given steps, one per call.
+func fakeClock(steps ...time.Duration) func() time.Time {
+	t := time.Unix(1700000000, 0)
+	return func() time.Time {
+		if len(steps) > 0 {
+			t = t.Add(steps[0])
+			steps = steps[1:]
+		}
+		return t
+	}
+}
+
+func TestRecorder_phasesFollowEachOther(t *testing.T) {
+	r := newRecorder(fakeClock(0, 2*time.Millisecond, 6*time.Millisecond))
+	r.Mark("config load")
+	r.Mark("first detection")
+
+	phases := r.Phases()
+	if len(phases) != 2 || phases[0].Took != 2*time.Millisecond || phases[1].Took != 6*time.Millisecond {
+		t.Fatalf("unexpected phases %+v", phases)
+	}
+	if r.Total() != 8*time.Millisecond {
+		t.Errorf("expected a total of 8ms, got %v", r.Total())
+	}
+
+	var buf bytes.Buffer
+	if err := r.Write(&buf); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"config load", "2ms", "25%", "first detection", "75%", "total", "8ms"} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("expected %q in:\n%s", want, buf.String())
+		}
+	}
+}
+
+func TestRecorder_nilRecordsNothing(t *testing.T) {
+	var r *Recorder
+	r.Mark("setup check")
+	if r.Phases() != nil || r.Total() != 0 {
+		t.Errorf("expected nothing recorded, got %+v", r.Phases())
+	}
+}
@@ -2,9 +2,12 @@ package tmux
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os/exec"
 	"regexp"
+	"slices"
+	"strconv"
 	"strings"
 	"time"
 )
@@ -29,8 +32,12 @@ func withTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
 }
 
 // Client wraps tmux commands.
+// A Client with a non-empty ssh field runs tmux on a remote host instead of
+// the local machine.
 type Client struct {
-	tmuxPath string
+	tmuxPath   string
+	socketName string
+	ssh        []string // ssh argv prefix (binary, options, destination)
 }
 
 // NewClient creates a new tmux client.
@@ -42,14 +49,117 @@ func NewClient() (*Client, error) {
 	return &Client{tmuxPath: path}, nil
 }
 
-// ListSessions returns raw tmux session list with format.
+// NewSocketClient creates a client of the tmux server named socket (tmux
+// -L), separate from the user's own, e.g. to try things out in.
+func NewSocketClient(socket string) (*Client, error) {
+	c, err := NewClient()
+	if err != nil {
+		return nil, err
+	}
+	c.socketName = socket
+	return c, nil
+}
+
+// command builds a tmux invocation, routing it through ssh for remote clients.
+func (c *Client) command(ctx context.Context, args ...string) *exec.Cmd {
+	if c.socketName != "" {
+		args = append([]string{"-L", c.socketName}, args...)
+	}
+	if len(c.ssh) == 0 {
+		return exec.CommandContext(ctx, c.tmuxPath, args...)
+	}
+	// ssh joins the remote argv with spaces and hands it to the remote shell,
+	// so every argument has to be quoted individually.
+	remote := make([]string, 0, len(args)+1)
+	remote = append(remote, remotePath(c.tmuxPath))
+	for _, a := range args {
+		remote = append(remote, shellQuote(a))
+	}
+	argv := append(append([]string{}, c.ssh[1:]...), strings.Join(remote, " "))
+	return exec.CommandContext(ctx, c.ssh[0], argv...)
+}
+
+// IsRemote reports whether the client runs tmux over ssh. A nil client is local.
+func (c *Client) IsRemote() bool {
+	return c != nil && len(c.ssh) > 0
+}
+
+// Version returns the output of `tmux -V`, e.g. "tmux 3.4".
+func (c *Client) Version(ctx context.Context) (string, error) {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+	out, err := c.command(ctx, "-V").CombinedOutput()
+	if err != nil {
+		msg := strings.TrimSpace(string(out))
+		if msg != "" {
+			return "", fmt.Errorf("%w: %s", err, msg)
+		}
+		return "", err
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// ServerInfo describes the tmux server; it fails when no server is
+// running.
+func (c *Client) ServerInfo(ctx context.Context) (ServerInfo, error) {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+	out, err := c.command(ctx, "display-message", "-p", ServerFormat).CombinedOutput()
+	if err != nil {
+		msg := strings.TrimSpace(string(out))
+		if msg != "" {
+			return ServerInfo{}, fmt.Errorf("%w: %s", err, msg)
+		}
+		return ServerInfo{}, err
+	}
+	return ParseServerInfo(string(out))
+}
+
+// ErrServerLost is returned when the tmux server went away during a command
+// or its socket refuses connections, as when the server crashed, rather
+// than there being no server at all.
+var ErrServerLost = errors.New("lost the tmux server")
+
+// serverLostMessages are what tmux prints when it loses its server.
+var serverLostMessages = []string{"server exited unexpectedly", "lost server", "error connecting to"}
+
+// noServerReasons are why tmux fails to connect when there simply is no
+// server: no socket, or one left behind by a server that is gone.
+var noServerReasons = []string{"No such file or directory", "Connection refused"}
+
+// serverLost reports whether tmux output says the server was lost.
+func serverLost(output string) bool {
+	for _, reason := range noServerReasons {
+		if strings.Contains(output, reason) {
+			return false
+		}
+	}
+	for _, m := range serverLostMessages {
+		if strings.Contains(output, m) {
+			return true
+		}
+	}
+	return false
+}
+
+// ListSessions returns raw tmux session list with format. No server means
+// no sessions; a server lost is ErrServerLost.
 func (c *Client) ListSessions(ctx context.Context, format string) (string, error) {
 	ctx, cancel := withTimeout(ctx)
 	defer cancel()
-	cmd := exec.CommandContext(ctx, c.tmuxPath, "list-sessions", "-F", format)
+	cmd := c.command(ctx, "list-sessions", "-F", format)
 	out, err := cmd.CombinedOutput()
 	if err != nil {
+		// ssh exits with 255 when the connection itself fails; that must not be
+		// mistaken for "no tmux server running" on the remote side.
+		var exitErr *exec.ExitError
+		if c.IsRemote() && errors.As(err, &exitErr) && exitErr.ExitCode() == 255 {
+			return "", fmt.Errorf("ssh: %s", strings.TrimSpace(string(out)))
+		}
 		combined := string(out)
+		if serverLost(combined) {
+			return "", fmt.Errorf("%w: %s", ErrServerLost, strings.TrimSpace(combined))
+		}
 		if strings.Contains(combined, "no server running") ||
 			strings.Contains(combined, "no current client") ||
 			strings.Contains(err.Error(), "exit status") {
@@ -74,10 +184,173 @@ func (c *Client) NewSession(ctx context.Context, name, startDir, command string)
 	if command != "" {
 		args = append(args, command)
 	}
-	cmd := exec.CommandContext(ctx, c.tmuxPath, args...)
+	cmd := c.command(ctx, args...)
 	return cmd.Run()
 }
 
+// NewLastingSession creates a new tmux session like NewSession, but keeps its
+// pane once command exits (remain-on-exit), dead and showing how it exited,
+// instead of closing the session. The option is set in the same tmux call,
+// so a command that exits at once is kept too.
+func (c *Client) NewLastingSession(ctx context.Context, name, startDir, command string) error {
+	if err := validateSessionName(name); err != nil {
+		return err
+	}
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+	args := []string{"new-session", "-d", "-s", name}
+	if startDir != "" {
+		args = append(args, "-c", startDir)
+	}
+	if command != "" {
+		args = append(args, command)
+	}
+	args = append(args, ";", "set-option", "-w", "-t", name, "remain-on-exit", "on")
+	return c.command(ctx, args...).Run()
+}
+
+// RespawnPane kills whatever runs in the active pane of a session, dead or
+// not, and runs command there instead, in startDir when set.
+func (c *Client) RespawnPane(ctx context.Context, name, startDir, command string) error {
+	if err := validateSessionName(name); err != nil {
+		return err
+	}
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+	args := []string{"respawn-pane", "-k", "-t", name}
+	if startDir != "" {
+		args = append(args, "-c", startDir)
+	}
+	return c.command(ctx, append(args, command)...).Run()
+}
+
+// SetTitle names the current window of a session and titles its active
+// pane, and stops programs in the pane from renaming either with escape
+// sequences, so choose-tree and the status line keep showing title. Pane
+// titles can only be locked from tmux 3.4; older servers keep the window
+// name but let the program retitle the pane.
+func (c *Client) SetTitle(ctx context.Context, name, title string) error {
+	if err := validateSessionName(name); err != nil {
+		return err
+	}
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+	// rename-window also turns automatic-rename off for the window.
+	if err := c.command(ctx, "rename-window", "-t", name, title).Run(); err != nil {
+		return fmt.Errorf("rename-window failed: %w", err)
+	}
+	if err := c.command(ctx, "set-option", "-w", "-t", name, "allow-rename", "off").Run(); err != nil {
+		return fmt.Errorf("set-option failed: %w", err)
+	}
+	if err := c.command(ctx, "select-pane", "-t", name, "-T", title).Run(); err != nil {
+		return fmt.Errorf("select-pane failed: %w", err)
+	}
+	_ = c.command(ctx, "set-option", "-p", "-t", name, "allow-set-title", "off").Run() // tmux 3.4+
+	return nil
+}
+
+// MarkOption is the session user option set on sessions the dashboard
+// created, to their project name, so tmux formats can tell them apart.
+const MarkOption = "@claude_dashboard"
+
+// The session environment variables mirroring the dashboard's session
+// options, so scripts and Claude Code hooks in a session can read them with
+// tmux show-environment, and so they survive the options being lost.
+const (
+	ProjectEnv = "CLAUDE_DASHBOARD_PROJECT"
+	TagsEnv    = "CLAUDE_DASHBOARD_TAGS"
+	IssueEnv   = "CLAUDE_DASHBOARD_ISSUE"
+)
+
+// Mark sets MarkOption of a session, and ProjectEnv, to project.
+func (c *Client) Mark(ctx context.Context, name, project string) error {
+	if err := validateSessionName(name); err != nil {
+		return err
+	}
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+	if err := c.command(ctx, "set-option", "-t", name, MarkOption, project).Run(); err != nil {
+		return err
+	}
+	return c.setEnvironment(ctx, name, ProjectEnv, project)
+}
+
+// TagsOption is the session user option holding the tags of a session,
+// separated by commas.
+const TagsOption = "@claude_dashboard_tags"
+
+// SetTags sets TagsOption of a session, and TagsEnv, to tags; no tags
+// unset them.
+func (c *Client) SetTags(ctx context.Context, name string, tags []string) error {
+	if err := validateSessionName(name); err != nil {
+		return err
+	}
+	if err := c.setOption(ctx, name, TagsOption, strings.Join(tags, ",")); err != nil {
+		return err
+	}
+	return c.setEnvironment(ctx, name, TagsEnv, strings.Join(tags, ","))
+}
+
+// IssueOption is the session user option holding the key of the issue a
+// session works on, e.g. ENG-123.
+const IssueOption = "@claude_dashboard_issue"
+
+// SetIssue sets IssueOption of a session, and IssueEnv, to key; an empty
+// key unsets them.
+func (c *Client) SetIssue(ctx context.Context, name, key string) error {
+	if err := validateSessionName(name); err != nil {
+		return err
+	}
+	if err := c.setOption(ctx, name, IssueOption, key); err != nil {
+		return err
+	}
+	return c.setEnvironment(ctx, name, IssueEnv, key)
+}
+
+// setEnvironment sets a session environment variable, or unsets it when
+// value is empty. Only processes started afterwards inherit it.
+func (c *Client) setEnvironment(ctx context.Context, name, variable, value string) error {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+	args := []string{"set-environment", "-t", name, variable, value}
+	if value == "" {
+		args = []string{"set-environment", "-u", "-t", name, variable}
+	}
+	if err := c.command(ctx, args...).Run(); err != nil {
+		return fmt.Errorf("set-environment failed: %w", err)
+	}
+	return nil
+}
+
+// Environment returns the session environment of a session, without the
+// global one.
+func (c *Client) Environment(ctx context.Context, name string) (map[string]string, error) {
+	if err := validateSessionName(name); err != nil {
+		return nil, err
+	}
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+	out, err := c.command(ctx, "show-environment", "-t", name).Output()
+	if err != nil {
+		return nil, fmt.Errorf("show-environment failed: %w", err)
+	}
+	return ParseEnvironment(string(out)), nil
+}
+
+// setOption sets a session option, or unsets it when value is empty.
+func (c *Client) setOption(ctx context.Context, name, option, value string) error {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+	args := []string{"set-option", "-t", name, option, value}
+	if value == "" {
+		args = []string{"set-option", "-u", "-t", name, option}
+	}
+	if err := c.command(ctx, args...).Run(); err != nil {
+		return fmt.Errorf("set-option failed: %w", err)
+	}
+	return nil
+}
+
 // KillSession kills a tmux session by name.
 func (c *Client) KillSession(ctx context.Context, name string) error {
 	if err := validateSessionName(name); err != nil {
@@ -85,19 +358,46 @@ func (c *Client) KillSession(ctx context.Context, name string) error {
 	}
 	ctx, cancel := withTimeout(ctx)
 	defer cancel()
-	cmd := exec.CommandContext(ctx, c.tmuxPath, "kill-session", "-t", name)
+	cmd := c.command(ctx, "kill-session", "-t", name)
 	return cmd.Run()
 }
 
+// KillServer kills the tmux server of the client with all its sessions.
+func (c *Client) KillServer(ctx context.Context) error {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+	return c.command(ctx, "kill-server").Run()
+}
+
+// HasSession reports whether a session of that exact name exists.
+func (c *Client) HasSession(ctx context.Context, name string) bool {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+	return c.command(ctx, "has-session", "-t", "="+name).Run() == nil
+}
+
 // CapturePaneContent captures the visible pane content of a session.
 func (c *Client) CapturePaneContent(ctx context.Context, name string, historyLines int) (string, error) {
+	return c.capturePane(ctx, name, historyLines, false)
+}
+
+// CapturePaneColors is CapturePaneContent keeping the colors and text
+// attributes of the pane as SGR escape sequences.
+func (c *Client) CapturePaneColors(ctx context.Context, name string, historyLines int) (string, error) {
+	return c.capturePane(ctx, name, historyLines, true)
+}
+
+func (c *Client) capturePane(ctx context.Context, name string, historyLines int, escapes bool) (string, error) {
 	ctx, cancel := withTimeout(ctx)
 	defer cancel()
 	args := []string{"capture-pane", "-t", name, "-p"}
+	if escapes {
+		args = append(args, "-e")
+	}
 	if historyLines > 0 {
 		args = append(args, "-S", fmt.Sprintf("-%d", historyLines))
 	}
-	cmd := exec.CommandContext(ctx, c.tmuxPath, args...)
+	cmd := c.command(ctx, args...)
 	out, err := cmd.Output()
 	if err != nil {
 		return "", fmt.Errorf("capture-pane failed: %w", err)
@@ -109,7 +409,7 @@ func (c *Client) CapturePaneContent(ctx context.Context, name string, historyLin
 func (c *Client) GetSessionPID(ctx context.Context, name string) (string, error) {
 	ctx, cancel := withTimeout(ctx)
 	defer cancel()
-	cmd := exec.CommandContext(ctx, c.tmuxPath, "list-panes", "-t", name, "-F", "#{pane_pid}")
+	cmd := c.command(ctx, "list-panes", "-t", name, "-F", "#{pane_pid}")
 	out, err := cmd.Output()
 	if err != nil {
 		return "", err
@@ -121,22 +421,48 @@ func (c *Client) GetSessionPID(ctx context.Context, name string) (string, error)
 	return "", fmt.Errorf("no pane found for session %s", name)
 }
 
-// SendKeys sends keys to a tmux session.
+// ListPanes returns the panes of every session, formatted with format
+// (e.g. PaneFormat).
+func (c *Client) ListPanes(ctx context.Context, format string) (string, error) {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+	out, err := c.command(ctx, "list-panes", "-a", "-F", format).Output()
+	if err != nil {
+		return "", fmt.Errorf("list-panes failed: %w", err)
+	}
+	return strings.TrimRight(string(out), "\n"), nil // keep a trailing empty field
+}
+
+// SendKeys types keys into a tmux session and presses Enter. The text is
+// sent literally (-l) so words like "Enter" or "C-c" are not read as key
+// names; Enter is sent separately.
 func (c *Client) SendKeys(ctx context.Context, name, keys string) error {
 	if err := validateSessionName(name); err != nil {
 		return err
 	}
 	ctx, cancel := withTimeout(ctx)
 	defer cancel()
-	cmd := exec.CommandContext(ctx, c.tmuxPath, "send-keys", "-t", name, keys, "Enter")
-	return cmd.Run()
+	if err := c.command(ctx, "send-keys", "-t", name, "-l", keys).Run(); err != nil {
+		return err
+	}
+	return c.command(ctx, "send-keys", "-t", name, "Enter").Run()
+}
+
+// SendKey presses a named key (e.g. "Escape") in a tmux session.
+func (c *Client) SendKey(ctx context.Context, name, key string) error {
+	if err := validateSessionName(name); err != nil {
+		return err
+	}
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+	return c.command(ctx, "send-keys", "-t", name, key).Run()
 }
 
 // GetSessionInfo returns detailed session info with custom format.
 func (c *Client) GetSessionInfo(ctx context.Context, name, format string) (string, error) {
 	ctx, cancel := withTimeout(ctx)
 	defer cancel()
-	cmd := exec.CommandContext(ctx, c.tmuxPath, "display-message", "-t", name, "-p", format)
+	cmd := c.command(ctx, "display-message", "-t", name, "-p", format)
 	out, err := cmd.Output()
 	if err != nil {
 		return "", err
@@ -157,7 +483,7 @@ func (c *Client) HasClaudeProcess(ctx context.Context, name string, procChildren
 	// Check pane current command first (fast path).
 	tctx, cancel := withTimeout(ctx)
 	defer cancel()
-	cmd := exec.CommandContext(tctx, c.tmuxPath, "list-panes", "-t", name, "-F", "#{pane_current_command}")
+	cmd := c.command(tctx, "list-panes", "-t", name, "-F", "#{pane_current_command}")
 	out, err := cmd.Output()
 	if err == nil {
 		for _, line := range strings.Split(strings.TrimSpace(string(out)), "\n") {
@@ -173,7 +499,7 @@ func (c *Client) HasClaudeProcess(ctx context.Context, name string, procChildren
 		return false
 	}
 
-	return hasClaudeDescendant(pid, procChildren)
+	return HasClaudeDescendant(pid, procChildren)
 }
 
 // BuildProcChildren converts a flat pid->ppid map into a children lookup.
@@ -188,9 +514,10 @@ func BuildProcChildren(entries []struct{ PID, PPID, Args string }) map[string][]
 	return m
 }
 
-// hasClaudeDescendant checks if any descendant process of the given PID has
-// "claude" in its command. If procChildren is nil it falls back to spawning ps.
-func hasClaudeDescendant(rootPID string, procChildren map[string][]ProcEntry) bool {
+// HasClaudeDescendant checks if any descendant process of the given PID has
+// "claude" in its command. procChildren is built by BuildProcChildren; if it
+// is nil it falls back to spawning ps.
+func HasClaudeDescendant(rootPID string, procChildren map[string][]ProcEntry) bool {
 	if procChildren == nil {
 		// Legacy fallback: spawn ps once.
 		cmd := exec.Command("ps", "-eo", "pid,ppid,args")
@@ -232,3 +559,138 @@ func hasClaudeDescendant(rootPID string, procChildren map[string][]ProcEntry) bo
 
 	return false
 }
+
+// refreshHooks are the tmux hooks that signal a session list change.
+var refreshHooks = []string{
+	"session-created",
+	"session-closed",
+	"session-renamed",
+	"client-attached",
+	"client-detached",
+	"pane-exited",
+}
+
+// InstallRefreshHooks makes tmux signal the wait-for channel whenever a
+// session is created, closed, renamed, attached, detached or its pane exits.
+// The hooks go in slot index of each hook array; every dashboard on a
+// server uses its own (see app.refreshHookIndex), so they neither clobber
+// each other's nor hooks the user set with plain set-hook, which use 0.
+// Hooks unknown to older tmux versions are skipped.
+func (c *Client) InstallRefreshHooks(ctx context.Context, channel string, index int) error {
+	if err := validateSessionName(channel); err != nil {
+		return err
+	}
+	if index <= 0 {
+		return fmt.Errorf("invalid hook index: %d", index)
+	}
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+	tmuxCmd := c.tmuxPath
+	if c.socketName != "" {
+		tmuxCmd += " -L " + c.socketName
+	}
+	signal := fmt.Sprintf("run-shell -b '%s wait-for -S %s'", tmuxCmd, channel)
+	installed := 0
+	for _, hook := range refreshHooks {
+		name := fmt.Sprintf("%s[%d]", hook, index)
+		if err := c.command(ctx, "set-hook", "-g", name, signal).Run(); err == nil {
+			installed++
+		}
+	}
+	if installed == 0 {
+		return fmt.Errorf("could not install any tmux hooks")
+	}
+	return nil
+}
+
+// RemoveRefreshHooks undoes InstallRefreshHooks of slot index, leaving
+// the hooks of other dashboards in place.
+func (c *Client) RemoveRefreshHooks(ctx context.Context, index int) {
+	if index <= 0 {
+		return
+	}
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+	for _, hook := range refreshHooks {
+		name := fmt.Sprintf("%s[%d]", hook, index)
+		_ = c.command(ctx, "set-hook", "-gu", name).Run()
+	}
+}
+
+// hookSlot matches a hook of show-hooks output set in a numbered slot, e.g.
+// `session-created[4242] run-shell -b "tmux wait-for -S channel"`.
+var hookSlot = regexp.MustCompile(`^[a-z-]+\[(\d+)\] (.*)$`)
+
+// RefreshHookSlots returns the slots holding refresh hooks that signal a
+// wait-for channel whose name starts with prefix, in order, e.g. those left
+// by dashboards that were killed before removing theirs.
+func (c *Client) RefreshHookSlots(ctx context.Context, prefix string) []int {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+	out, err := c.command(ctx, "show-hooks", "-g").Output()
+	if err != nil {
+		return nil
+	}
+	var slots []int
+	for _, line := range strings.Split(string(out), "\n") {
+		m := hookSlot.FindStringSubmatch(strings.TrimSpace(line))
+		if m == nil || !strings.Contains(m[2], "wait-for -S "+prefix) {
+			continue
+		}
+		if n, err := strconv.Atoi(m[1]); err == nil && !slices.Contains(slots, n) {
+			slots = append(slots, n)
+		}
+	}
+	slices.Sort(slots)
+	return slots
+}
+
+// WaitFor blocks until the wait-for channel is signalled or ctx is done.
+// Unlike other client methods it has no default timeout.
+func (c *Client) WaitFor(ctx context.Context, channel string) error {
+	if err := validateSessionName(channel); err != nil {
+		return err
+	}
+	return c.command(ctx, "wait-for", channel).Run()
+}
+
+// AttachCommand returns an interactive `tmux attach-session` command for name.
+// Remote clients request a tty from ssh so the session is usable.
+func (c *Client) AttachCommand(name string) *exec.Cmd {
+	if len(c.ssh) == 0 {
+		return c.command(context.Background(), "attach-session", "-t", name)
+	}
+	remote := *c
+	remote.ssh = append([]string{c.ssh[0], "-t"}, c.ssh[1:]...)
+	return remote.command(context.Background(), "attach-session", "-t", name)
+}
+
+// AttachLine returns a shell one-liner attaching to name, for a person to
+// paste into another terminal: tmux attach, or for remote clients ssh -t
+// to the host running it there. The ssh options meant for the dashboard
+// itself (-o BatchMode and the like) are left out so ssh may prompt.
+func (c *Client) AttachLine(name string) string {
+	tmuxPath := c.tmuxPath
+	if len(c.ssh) == 0 {
+		tmuxPath = "tmux" // on PATH, as NewClient found it there
+	}
+	words := []string{remotePath(tmuxPath)}
+	if c.socketName != "" {
+		words = append(words, "-L", shellQuote(c.socketName))
+	}
+	words = append(words, "attach", "-t", shellQuote(name))
+	if len(c.ssh) == 0 {
+		return strings.Join(words, " ")
+	}
+	line := []string{"ssh", "-t"}
+	for i := 1; i < len(c.ssh); i++ {
+		switch c.ssh[i] {
+		case "-o":
+			i++
+		case "--":
+		default:
+			line = append(line, shellQuote(c.ssh[i]))
+		}
+	}
+	return strings.Join(append(line, shellQuote(strings.Join(words, " "))), " ")
+}
@@ -1,7 +1,14 @@
 package tmux
 
 import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"reflect"
+	"strings"
 	"testing"
+	"time"
 )
 
 // ---------------------------------------------------------------------------
@@ -214,14 +221,14 @@ func TestBuildProcChildren_singleEntry(t *testing.T) {
 }
 
 // ---------------------------------------------------------------------------
-// hasClaudeDescendant — using pre-built procChildren map
+// HasClaudeDescendant — using pre-built procChildren map
 // ---------------------------------------------------------------------------
 
 func TestHasClaudeDescendant_directChildWithClaudeInArgs(t *testing.T) {
 	children := map[string][]ProcEntry{
 		"100": {{PID: "200", Args: "/usr/local/bin/claude --verbose"}},
 	}
-	if !hasClaudeDescendant("100", children) {
+	if !HasClaudeDescendant("100", children) {
 		t.Error("expected true when direct child contains 'claude' in args")
 	}
 }
@@ -232,7 +239,7 @@ func TestHasClaudeDescendant_deepDescendantWithClaude(t *testing.T) {
 		"10":  {{PID: "20", Args: "node"}},
 		"20":  {{PID: "30", Args: "claude-code"}},
 	}
-	if !hasClaudeDescendant("1", children) {
+	if !HasClaudeDescendant("1", children) {
 		t.Error("expected true when deep descendant has 'claude' in args")
 	}
 }
@@ -242,13 +249,13 @@ func TestHasClaudeDescendant_noClaudeInTree(t *testing.T) {
 		"1":  {{PID: "10", Args: "bash"}},
 		"10": {{PID: "20", Args: "vim"}},
 	}
-	if hasClaudeDescendant("1", children) {
+	if HasClaudeDescendant("1", children) {
 		t.Error("expected false when no descendant has 'claude'")
 	}
 }
 
 func TestHasClaudeDescendant_emptyTreeReturnsFalse(t *testing.T) {
-	if hasClaudeDescendant("999", map[string][]ProcEntry{}) {
+	if HasClaudeDescendant("999", map[string][]ProcEntry{}) {
 		t.Error("expected false for empty process tree")
 	}
 }
@@ -257,7 +264,7 @@ func TestHasClaudeDescendant_claudeInArgsCaseInsensitive(t *testing.T) {
 	children := map[string][]ProcEntry{
 		"1": {{PID: "2", Args: "/path/to/CLAUDE"}},
 	}
-	if !hasClaudeDescendant("1", children) {
+	if !HasClaudeDescendant("1", children) {
 		t.Error("expected true for case-insensitive match of 'claude' in args")
 	}
 }
@@ -269,8 +276,338 @@ func TestHasClaudeDescendant_cycleInTreeDoesNotInfiniteLoop(t *testing.T) {
 		"2": {{PID: "1", Args: "bash"}}, // back-edge cycle
 	}
 	// Should terminate and return false (no 'claude' in args)
-	result := hasClaudeDescendant("1", children)
+	result := HasClaudeDescendant("1", children)
 	if result {
 		t.Error("expected false when no claude in cyclic tree")
 	}
 }
+
+// ---------------------------------------------------------------------------
+// ListPanes
+// ---------------------------------------------------------------------------
+
+func TestListPanes_windowNamesOfEverySession(t *testing.T) {
+	path, err := exec.LookPath("tmux")
+	if err != nil {
+		t.Skip("tmux not installed")
+	}
+	c := &Client{tmuxPath: path, socketName: "cd-test-panes"}
+	ctx := context.Background()
+	if err := c.NewSession(ctx, "cd-one", t.TempDir(), "sleep 30"); err != nil {
+		t.Skipf("cannot start a tmux server: %v", err)
+	}
+	defer c.command(ctx, "kill-server").Run()
+	c.NewSession(ctx, "cd-two", t.TempDir(), "sleep 30")
+	c.command(ctx, "rename-window", "-t", "cd-two", "api").Run()
+
+	out, err := c.ListPanes(ctx, PaneFormat)
+	if err != nil {
+		t.Fatalf("ListPanes: %v", err)
+	}
+	names := map[string]string{}
+	for _, p := range ParsePanes(out) {
+		names[p.Session] = p.WindowName
+	}
+	if len(names) != 2 || names["cd-two"] != "api" {
+		t.Errorf("expected both sessions with cd-two's window named api, got %v", names)
+	}
+}
+
+// ---------------------------------------------------------------------------
+// CapturePaneColors
+// ---------------------------------------------------------------------------
+
+func TestCapturePaneColors_keepsEscapeSequences(t *testing.T) {
+	path, err := exec.LookPath("tmux")
+	if err != nil {
+		t.Skip("tmux not installed")
+	}
+	c := &Client{tmuxPath: path, socketName: "cd-test-colors"}
+	ctx := context.Background()
+	if err := c.NewSession(ctx, "cd-diff", t.TempDir(), `printf '\033[31m-old\033[0m\n'; sleep 30`); err != nil {
+		t.Skipf("cannot start a tmux server: %v", err)
+	}
+	defer c.command(ctx, "kill-server").Run()
+
+	var colored string
+	for i := 0; i < 50 && !strings.Contains(colored, "-old"); i++ {
+		time.Sleep(20 * time.Millisecond)
+		colored, _ = c.CapturePaneColors(ctx, "cd-diff", 0)
+	}
+	if !strings.Contains(colored, "\x1b[31m-old") {
+		t.Errorf("expected the red line with its color, got %q", colored)
+	}
+	plain, _ := c.CapturePaneContent(ctx, "cd-diff", 0)
+	if strings.Contains(plain, "\x1b") || !strings.Contains(plain, "-old") {
+		t.Errorf("expected the line without colors, got %q", plain)
+	}
+}
+
+// ---------------------------------------------------------------------------
+// SetTitle
+// ---------------------------------------------------------------------------
+
+func TestSetTitle_namesWindowAndPane(t *testing.T) {
+	path, err := exec.LookPath("tmux")
+	if err != nil {
+		t.Skip("tmux not installed")
+	}
+	c := &Client{tmuxPath: path, socketName: "cd-test-title"}
+	ctx := context.Background()
+	if err := c.NewSession(ctx, "cd-shop", t.TempDir(), "sleep 30"); err != nil {
+		t.Skipf("cannot start a tmux server: %v", err)
+	}
+	defer c.command(ctx, "kill-server").Run()
+
+	if err := c.SetTitle(ctx, "cd-shop", "shop"); err != nil {
+		t.Fatalf("SetTitle: %v", err)
+	}
+	out, err := c.ListPanes(ctx, PaneFormat)
+	if err != nil {
+		t.Fatalf("ListPanes: %v", err)
+	}
+	panes := ParsePanes(out)
+	if len(panes) != 1 || panes[0].WindowName != "shop" || panes[0].Title != "shop" {
+		t.Errorf("expected window and pane titled shop, got %+v", panes)
+	}
+	rename, _ := c.command(ctx, "show-options", "-w", "-v", "-t", "cd-shop", "automatic-rename").Output()
+	if string(rename) != "off\n" {
+		t.Errorf("expected automatic-rename off, got %q", rename)
+	}
+}
+
+// ---------------------------------------------------------------------------
+// Mark
+// ---------------------------------------------------------------------------
+
+func TestMark_setsTheOptionFormatsFilterOn(t *testing.T) {
+	path, err := exec.LookPath("tmux")
+	if err != nil {
+		t.Skip("tmux not installed")
+	}
+	c := &Client{tmuxPath: path, socketName: "cd-test-mark"}
+	ctx := context.Background()
+	if err := c.NewSession(ctx, "shop", t.TempDir(), "sleep 30"); err != nil {
+		t.Skipf("cannot start a tmux server: %v", err)
+	}
+	defer c.command(ctx, "kill-server").Run()
+	c.NewSession(ctx, "scratch", t.TempDir(), "sleep 30")
+
+	if err := c.Mark(ctx, "shop", "shop-api"); err != nil {
+		t.Fatalf("Mark: %v", err)
+	}
+	out, err := c.command(ctx, "list-sessions", "-f", "#{"+MarkOption+"}", "-F", "#{session_name} #{"+MarkOption+"}").Output()
+	if err != nil {
+		t.Fatalf("list-sessions: %v", err)
+	}
+	if got := string(out); got != "shop shop-api\n" {
+		t.Errorf("expected only the marked session, got %q", got)
+	}
+}
+
+func TestSetTags_listedAndUnset(t *testing.T) {
+	path, err := exec.LookPath("tmux")
+	if err != nil {
+		t.Skip("tmux not installed")
+	}
+	c := &Client{tmuxPath: path, socketName: "cd-test-tags"}
+	ctx := context.Background()
+	if err := c.NewSession(ctx, "cd-shop", t.TempDir(), "sleep 30"); err != nil {
+		t.Skipf("cannot start a tmux server: %v", err)
+	}
+	defer c.command(ctx, "kill-server").Run()
+
+	if err := c.SetTags(ctx, "cd-shop", []string{"backend", "urgent"}); err != nil {
+		t.Fatalf("SetTags: %v", err)
+	}
+	out, err := c.ListSessions(ctx, TagsFormat)
+	if err != nil {
+		t.Fatalf("ListSessions: %v", err)
+	}
+	if tags := ParseTags(out); !reflect.DeepEqual(tags["cd-shop"], []string{"backend", "urgent"}) {
+		t.Errorf("expected both tags, got %v", tags)
+	}
+	if env, err := c.Environment(ctx, "cd-shop"); err != nil || env[TagsEnv] != "backend,urgent" {
+		t.Errorf("expected the tags in the environment, got %v, %v", env, err)
+	}
+
+	if err := c.SetTags(ctx, "cd-shop", nil); err != nil {
+		t.Fatalf("SetTags: %v", err)
+	}
+	out, _ = c.ListSessions(ctx, TagsFormat)
+	if tags := ParseTags(out); len(tags) != 0 {
+		t.Errorf("expected the tags unset, got %v", tags)
+	}
+	if env, _ := c.Environment(ctx, "cd-shop"); env[TagsEnv] != "" {
+		t.Errorf("expected the tags gone from the environment, got %v", env)
+	}
+}
+
+func TestSetIssue_listedAndUnset(t *testing.T) {
+	path, err := exec.LookPath("tmux")
+	if err != nil {
+		t.Skip("tmux not installed")
+	}
+	c := &Client{tmuxPath: path, socketName: "cd-test-issue"}
+	ctx := context.Background()
+	if err := c.NewSession(ctx, "cd-shop", t.TempDir(), "sleep 30"); err != nil {
+		t.Skipf("cannot start a tmux server: %v", err)
+	}
+	defer c.command(ctx, "kill-server").Run()
+
+	if err := c.SetIssue(ctx, "cd-shop", "ENG-123"); err != nil {
+		t.Fatalf("SetIssue: %v", err)
+	}
+	out, err := c.ListSessions(ctx, IssueFormat)
+	if err != nil {
+		t.Fatalf("ListSessions: %v", err)
+	}
+	if issues := ParseIssues(out); issues["cd-shop"] != "ENG-123" {
+		t.Errorf("expected the issue, got %v", issues)
+	}
+	if env, _ := c.Environment(ctx, "cd-shop"); env[IssueEnv] != "ENG-123" {
+		t.Errorf("expected the issue in the environment, got %v", env)
+	}
+
+	if err := c.SetIssue(ctx, "cd-shop", ""); err != nil {
+		t.Fatalf("SetIssue: %v", err)
+	}
+	out, _ = c.ListSessions(ctx, IssueFormat)
+	if issues := ParseIssues(out); len(issues) != 0 {
+		t.Errorf("expected the issue unset, got %v", issues)
+	}
+}
+
+// ---------------------------------------------------------------------------
+// HasSession
+// ---------------------------------------------------------------------------
+
+func TestHasSession_exactNameOnly(t *testing.T) {
+	path, err := exec.LookPath("tmux")
+	if err != nil {
+		t.Skip("tmux not installed")
+	}
+	c := &Client{tmuxPath: path, socketName: "cd-test-has"}
+	ctx := context.Background()
+	if err := c.NewSession(ctx, "cd-api-2", t.TempDir(), "sleep 30"); err != nil {
+		t.Skipf("cannot start a tmux server: %v", err)
+	}
+	defer c.command(ctx, "kill-server").Run()
+
+	if !c.HasSession(ctx, "cd-api-2") {
+		t.Error("expected the session to exist")
+	}
+	if c.HasSession(ctx, "cd-api") {
+		t.Error("expected no match on a prefix of the name")
+	}
+	c.KillSession(ctx, "cd-api-2")
+	if c.HasSession(ctx, "cd-api-2") {
+		t.Error("expected the killed session to be gone")
+	}
+}
+
+func TestKillServer_endsAllSessions(t *testing.T) {
+	c, err := NewSocketClient("cd-test-kill-server")
+	if err != nil {
+		t.Skip("tmux not installed")
+	}
+	ctx := context.Background()
+	for _, name := range []string{"cd-a", "cd-b"} {
+		if err := c.NewSession(ctx, name, t.TempDir(), "sleep 30"); err != nil {
+			t.Skipf("cannot start a tmux server: %v", err)
+		}
+	}
+	if err := c.KillServer(ctx); err != nil {
+		t.Fatalf("KillServer: %v", err)
+	}
+	if c.HasSession(ctx, "cd-a") || c.HasSession(ctx, "cd-b") {
+		t.Error("expected no sessions left")
+	}
+}
+
+// ---------------------------------------------------------------------------
+// ListSessions
+// ---------------------------------------------------------------------------
+
+func TestListSessions_noServerIsNoSessions(t *testing.T) {
+	c, err := NewSocketClient("cd-test-no-server")
+	if err != nil {
+		t.Skip("tmux not installed")
+	}
+	out, err := c.ListSessions(context.Background(), "#{session_name}")
+	if out != "" || err != nil {
+		t.Errorf("expected no sessions and no error, got %q, %v", out, err)
+	}
+}
+
+func TestServerLost(t *testing.T) {
+	lost := []string{
+		"server exited unexpectedly",
+		"lost server",
+		"error connecting to /tmp/tmux-1000/default (Permission denied)",
+	}
+	for _, output := range lost {
+		if !serverLost(output) {
+			t.Errorf("expected %q to be a lost server", output)
+		}
+	}
+	noServer := []string{
+		"no server running on /tmp/tmux-1000/default",
+		"error connecting to /tmp/tmux-1000/default (No such file or directory)",
+		"can't find session: cd-api",
+	}
+	for _, output := range noServer {
+		if serverLost(output) {
+			t.Errorf("expected %q not to be a lost server", output)
+		}
+	}
+}
+
+func TestRefreshHooks_removeOnlyTheirOwnSlot(t *testing.T) {
+	c, err := NewSocketClient(fmt.Sprintf("cd-hooks-test-%d", os.Getpid()))
+	if err != nil {
+		t.Skip("tmux not installed")
+	}
+	ctx := context.Background()
+	if err := c.command(ctx, "new-session", "-d", "-s", "hooks", "sleep 30").Run(); err != nil {
+		t.Skipf("cannot start a tmux server: %v", err)
+	}
+	defer c.command(ctx, "kill-server").Run()
+
+	if err := c.InstallRefreshHooks(ctx, "refresh-a", 101); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.InstallRefreshHooks(ctx, "refresh-b", 102); err != nil {
+		t.Fatal(err)
+	}
+	c.RemoveRefreshHooks(ctx, 101)
+
+	out, err := c.command(ctx, "show-hooks", "-g").Output()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(out), "[101]") || !strings.Contains(string(out), "session-created[102]") {
+		t.Errorf("expected only the hooks of slot 102 left, got:\n%s", out)
+	}
+}
+
+func TestRefreshHookSlots_listsTheSlotsOfTheChannels(t *testing.T) {
+	c, err := NewSocketClient(fmt.Sprintf("cd-slots-test-%d", os.Getpid()))
+	if err != nil {
+		t.Skip("tmux not installed")
+	}
+	ctx := context.Background()
+	if err := c.command(ctx, "new-session", "-d", "-s", "hooks", "sleep 30").Run(); err != nil {
+		t.Skipf("cannot start a tmux server: %v", err)
+	}
+	defer c.command(ctx, "kill-server").Run()
+
+	for slot, channel := range map[int]string{4242: "refresh-4242", 77: "refresh-77", 5: "other-5"} {
+		if err := c.InstallRefreshHooks(ctx, channel, slot); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if got := c.RefreshHookSlots(ctx, "refresh-"); fmt.Sprint(got) != "[77 4242]" {
+		t.Errorf("expected slots 77 and 4242, got %v", got)
+	}
+}
@@ -0,0 +1,75 @@
+package tmux
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// Inside reports whether this process runs in a tmux pane, where attaching
+// would nest tmux in itself.
+func Inside() bool {
+	return os.Getenv("TMUX") != ""
+}
+
+// CurrentSession returns the session of the pane this process runs in, or ""
+// outside tmux. It asks about $TMUX_PANE rather than the active client, so
+// the answer holds after the client switches to another session.
+func (c *Client) CurrentSession(ctx context.Context) (string, error) {
+	if !Inside() || c.IsRemote() {
+		return "", nil
+	}
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+	args := []string{"display-message", "-p"}
+	if pane := os.Getenv("TMUX_PANE"); pane != "" {
+		args = append(args, "-t", pane)
+	}
+	out, err := c.command(ctx, append(args, "#{session_name}")...).Output()
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// SwitchClient moves the tmux client this process runs in to session name,
+// the way to reach another session from inside tmux without nesting.
+func (c *Client) SwitchClient(ctx context.Context, name string) error {
+	if err := validateSessionName(name); err != nil {
+		return err
+	}
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+	out, err := c.command(ctx, "switch-client", "-t", name).CombinedOutput()
+	if err != nil {
+		if msg := strings.TrimSpace(string(out)); msg != "" {
+			return fmt.Errorf("%w: %s", err, msg)
+		}
+		return err
+	}
+	return nil
+}
+
+// ChooseTree opens tmux's own session picker in the pane this process runs
+// in, listing only sessions for which filter, a tmux format, is true.
+// Picking one switches the client to it.
+func (c *Client) ChooseTree(ctx context.Context, filter string) error {
+	if !Inside() {
+		return fmt.Errorf("choose-tree needs a tmux client; run it inside tmux")
+	}
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+	args := []string{"choose-tree", "-s", "-Z", "-f", filter}
+	if pane := os.Getenv("TMUX_PANE"); pane != "" {
+		args = append(args, "-t", pane)
+	}
+	out, err := c.command(ctx, args...).CombinedOutput()
+	if err != nil {
+		if msg := strings.TrimSpace(string(out)); msg != "" {
+			return fmt.Errorf("%w: %s", err, msg)
+		}
+		return err
+	}
+	return nil
+}
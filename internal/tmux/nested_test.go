@@ -0,0 +1,56 @@
+package tmux
+
+import (
+	"context"
+	"os/exec"
+	"strings"
+	"testing"
+)
+
+// ---------------------------------------------------------------------------
+// CurrentSession
+// ---------------------------------------------------------------------------
+
+func TestCurrentSession_emptyOutsideTmux(t *testing.T) {
+	t.Setenv("TMUX", "")
+	c := &Client{tmuxPath: "/nonexistent/tmux"}
+	if name, err := c.CurrentSession(context.Background()); name != "" || err != nil {
+		t.Errorf("expected no session outside tmux, got %q, %v", name, err)
+	}
+}
+
+func TestCurrentSession_sessionOfOwnPane(t *testing.T) {
+	path, err := exec.LookPath("tmux")
+	if err != nil {
+		t.Skip("tmux not installed")
+	}
+	c := &Client{tmuxPath: path, socketName: "cd-test-nested"}
+	ctx := context.Background()
+	if err := c.NewSession(ctx, "cd-own", t.TempDir(), "sleep 30"); err != nil {
+		t.Skipf("cannot start a tmux server: %v", err)
+	}
+	defer c.command(ctx, "kill-server").Run()
+	c.NewSession(ctx, "cd-other", t.TempDir(), "sleep 30")
+
+	out, err := c.command(ctx, "list-panes", "-t", "cd-own", "-F", "#{pane_id}").Output()
+	if err != nil {
+		t.Fatalf("list-panes: %v", err)
+	}
+	t.Setenv("TMUX", "/tmp/cd-test-nested,1,0")
+	t.Setenv("TMUX_PANE", strings.TrimSpace(string(out)))
+	if name, err := c.CurrentSession(ctx); name != "cd-own" || err != nil {
+		t.Errorf("expected cd-own, got %q, %v", name, err)
+	}
+}
+
+// ---------------------------------------------------------------------------
+// ChooseTree
+// ---------------------------------------------------------------------------
+
+func TestChooseTree_needsTmux(t *testing.T) {
+	t.Setenv("TMUX", "")
+	c := &Client{tmuxPath: "/nonexistent/tmux"}
+	if err := c.ChooseTree(context.Background(), "1"); err == nil {
+		t.Error("expected an error outside tmux")
+	}
+}
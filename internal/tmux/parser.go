@@ -1,6 +1,7 @@
 package tmux
 
 import (
+	"fmt"
 	"strconv"
 	"strings"
 	"time"
@@ -14,10 +15,15 @@ type RawSession struct {
 	Windows  int
 	Activity time.Time
 	Path     string
+	Command  string // command running in the active pane, e.g. claude or zsh
+	Dead     bool   // the active pane's program exited and tmux kept the pane
+	Failed   bool   // for a dead pane, its program exited non-zero or by a signal
 }
 
-// SessionFormat is the tmux format string for listing sessions.
-const SessionFormat = "#{session_name}|#{session_created}|#{session_attached}|#{session_windows}|#{session_activity}|#{session_path}"
+// SessionFormat is the tmux format string for listing sessions. The active
+// pane's command, dead flag and exit status come last; lines without them
+// still parse.
+const SessionFormat = "#{session_name}|#{session_created}|#{session_attached}|#{session_windows}|#{session_activity}|#{session_path}|#{pane_current_command}|#{pane_dead}|#{pane_dead_status}"
 
 // ParseSessions parses tmux list-sessions output.
 func ParseSessions(output string) []RawSession {
@@ -44,19 +50,97 @@ func ParseSessions(output string) []RawSession {
 		windows, _ := strconv.Atoi(parts[3])
 		activity := parseUnixTimestamp(parts[4])
 
-		sessions = append(sessions, RawSession{
+		raw := RawSession{
 			Name:     parts[0],
 			Created:  created,
 			Attached: attached,
 			Windows:  windows,
 			Activity: activity,
 			Path:     parts[5],
-		})
+		}
+		if n := len(parts); n >= 9 {
+			// A path with | in it spans several fields.
+			raw.Path = strings.Join(parts[5:n-3], "|")
+			raw.Command = parts[n-3]
+			raw.Dead = parts[n-2] == "1"
+			raw.Failed = raw.Dead && parts[n-1] != "0"
+		}
+		sessions = append(sessions, raw)
 	}
 
 	return sessions
 }
 
+// TagsFormat is the tmux format string for listing the tags of sessions.
+const TagsFormat = "#{session_name}\t#{" + TagsOption + "}"
+
+// ParseTags parses tmux list-sessions output in TagsFormat into the tags of
+// each session that has any.
+func ParseTags(output string) map[string][]string {
+	tags := make(map[string][]string)
+	for _, line := range strings.Split(output, "\n") {
+		name, value, ok := strings.Cut(strings.TrimRight(line, "\r"), "\t")
+		if !ok || value == "" {
+			continue
+		}
+		for _, tag := range strings.Split(value, ",") {
+			if tag != "" {
+				tags[name] = append(tags[name], tag)
+			}
+		}
+	}
+	return tags
+}
+
+// IssueFormat is the tmux format string for listing the issue keys of
+// sessions.
+const IssueFormat = "#{session_name}\t#{" + IssueOption + "}"
+
+// ParseIssues parses tmux list-sessions output in IssueFormat into the
+// issue key of each session that has one.
+func ParseIssues(output string) map[string]string {
+	issues := make(map[string]string)
+	for _, line := range strings.Split(output, "\n") {
+		name, key, ok := strings.Cut(strings.TrimRight(line, "\r"), "\t")
+		if ok && key != "" {
+			issues[name] = key
+		}
+	}
+	return issues
+}
+
+// ParseEnvironment parses tmux show-environment output into its variables.
+// Variables marked as removed (-NAME) are left out.
+func ParseEnvironment(output string) map[string]string {
+	env := make(map[string]string)
+	for _, line := range strings.Split(output, "\n") {
+		name, value, ok := strings.Cut(strings.TrimRight(line, "\r"), "=")
+		if ok && name != "" && !strings.HasPrefix(name, "-") {
+			env[name] = value
+		}
+	}
+	return env
+}
+
+// ServerInfo describes the running tmux server.
+type ServerInfo struct {
+	PID     string
+	Started time.Time
+	Version string // e.g. "3.4"; empty before tmux 2.4
+}
+
+// ServerFormat is the tmux format string for ServerInfo.
+const ServerFormat = "#{pid}|#{start_time}|#{version}"
+
+// ParseServerInfo parses display-message output in ServerFormat.
+func ParseServerInfo(output string) (ServerInfo, error) {
+	parts := strings.Split(strings.TrimSpace(output), "|")
+	if len(parts) != 3 || parts[0] == "" {
+		return ServerInfo{}, fmt.Errorf("unexpected server info %q", output)
+	}
+	return ServerInfo{PID: parts[0], Started: parseUnixTimestamp(parts[1]), Version: parts[2]}, nil
+}
+
 func parseUnixTimestamp(s string) time.Time {
 	ts, err := strconv.ParseInt(strings.TrimSpace(s), 10, 64)
 	if err != nil {
@@ -64,3 +148,42 @@ func parseUnixTimestamp(s string) time.Time {
 	}
 	return time.Unix(ts, 0)
 }
+
+// RawPane holds parsed tmux pane data.
+type RawPane struct {
+	Session     string
+	WindowIndex int
+	WindowName  string
+	Title       string // empty when tmux's default, the host name
+}
+
+// PaneFormat is the tmux format string for listing panes. Fields are split
+// by tabs, which tmux keeps out of names and titles; the title comes last.
+const PaneFormat = "#{session_name}\t#{window_index}\t#{host}\t#{window_name}\t#{pane_title}"
+
+// ParsePanes parses tmux list-panes output. Pane titles that are just the
+// host name, which tmux starts every pane with, are dropped.
+func ParsePanes(output string) []RawPane {
+	var panes []RawPane
+	for _, line := range strings.Split(output, "\n") {
+		parts := strings.SplitN(strings.TrimRight(line, "\r"), "\t", 5)
+		if len(parts) < 5 {
+			continue
+		}
+		index, err := strconv.Atoi(parts[1])
+		if err != nil {
+			continue
+		}
+		host, title := parts[2], parts[4]
+		if short, _, _ := strings.Cut(host, "."); title == host || title == short {
+			title = ""
+		}
+		panes = append(panes, RawPane{
+			Session:     parts[0],
+			WindowIndex: index,
+			WindowName:  parts[3],
+			Title:       title,
+		})
+	}
+	return panes
+}
@@ -1,6 +1,7 @@
 package tmux
 
 import (
+	"reflect"
 	"testing"
 	"time"
 )
@@ -101,6 +102,25 @@ func TestParseSessions_mixedValidAndInvalidLines(t *testing.T) {
 	}
 }
 
+func TestParseSessions_paneCommandAndExit(t *testing.T) {
+	input := "cd-api|1700000000|0|1|1700000000|/src/a|b|zsh|0|\n" +
+		"cd-web|1700000000|0|1|1700000000|/src/web|claude|1|0\n" +
+		"cd-gpu|1700000000|0|1|1700000000|/src/gpu|claude|1|137"
+	sessions := ParseSessions(input)
+	if len(sessions) != 3 {
+		t.Fatalf("expected 3 sessions, got %d", len(sessions))
+	}
+	if s := sessions[0]; s.Path != "/src/a|b" || s.Command != "zsh" || s.Dead || s.Failed {
+		t.Errorf("unexpected first session %+v", s)
+	}
+	if s := sessions[1]; s.Path != "/src/web" || s.Command != "claude" || !s.Dead || s.Failed {
+		t.Errorf("unexpected second session %+v", s)
+	}
+	if s := sessions[2]; !s.Dead || !s.Failed {
+		t.Errorf("expected the third pane to have failed, got %+v", s)
+	}
+}
+
 func TestParseUnixTimestamp_validTimestamp(t *testing.T) {
 	ts := parseUnixTimestamp("1700000000")
 	expected := time.Unix(1700000000, 0)
@@ -138,3 +158,88 @@ func TestParseUnixTimestamp_zeroTimestamp(t *testing.T) {
 		t.Errorf("expected Unix epoch for '0', got %v", ts)
 	}
 }
+
+// ---------------------------------------------------------------------------
+// ParsePanes
+// ---------------------------------------------------------------------------
+
+func TestParsePanes_dropsDefaultTitles(t *testing.T) {
+	input := "cd-api\t0\tbox.local\tclaude\t✳ Fix login\n" +
+		"cd-api\t1\tbox.local\ta|b c\tbox\n" +
+		"cd-web\t0\tbox.local\tzsh\tbox.local\n" +
+		"cd-web\t1\tbox.local\tlogs\t"
+	panes := ParsePanes(input)
+	if len(panes) != 4 {
+		t.Fatalf("expected 4 panes, got %d: %+v", len(panes), panes)
+	}
+	want := []RawPane{
+		{Session: "cd-api", WindowIndex: 0, WindowName: "claude", Title: "✳ Fix login"},
+		{Session: "cd-api", WindowIndex: 1, WindowName: "a|b c"},
+		{Session: "cd-web", WindowIndex: 0, WindowName: "zsh"},
+		{Session: "cd-web", WindowIndex: 1, WindowName: "logs"},
+	}
+	for i := range want {
+		if panes[i] != want[i] {
+			t.Errorf("pane %d: expected %+v, got %+v", i, want[i], panes[i])
+		}
+	}
+}
+
+func TestParsePanes_skipsMalformedLines(t *testing.T) {
+	if panes := ParsePanes("only|pipes|here\ncd-x\tNaN\th\tw\tt"); len(panes) != 0 {
+		t.Errorf("expected no panes, got %+v", panes)
+	}
+}
+
+// ---------------------------------------------------------------------------
+// ParseTags
+// ---------------------------------------------------------------------------
+
+func TestParseTags_bySessionSkippingUntagged(t *testing.T) {
+	tags := ParseTags("cd-api\tbackend,urgent\ncd-web\t\ncd-ui\tfrontend\n")
+	want := map[string][]string{"cd-api": {"backend", "urgent"}, "cd-ui": {"frontend"}}
+	if !reflect.DeepEqual(tags, want) {
+		t.Errorf("expected %v, got %v", want, tags)
+	}
+}
+
+// ---------------------------------------------------------------------------
+// ParseIssues
+// ---------------------------------------------------------------------------
+
+func TestParseIssues_bySessionSkippingUnset(t *testing.T) {
+	issues := ParseIssues("cd-api\tENG-12\ncd-web\t\r\ncd-ui\tOPS-7\n")
+	want := map[string]string{"cd-api": "ENG-12", "cd-ui": "OPS-7"}
+	if !reflect.DeepEqual(issues, want) {
+		t.Errorf("expected %v, got %v", want, issues)
+	}
+}
+
+// ---------------------------------------------------------------------------
+// ParseEnvironment
+// ---------------------------------------------------------------------------
+
+func TestParseEnvironment_skipsRemovedVariables(t *testing.T) {
+	env := ParseEnvironment("CLAUDE_DASHBOARD_TAGS=backend,urgent\n-DISPLAY\nA=b=c\r\n")
+	want := map[string]string{"CLAUDE_DASHBOARD_TAGS": "backend,urgent", "A": "b=c"}
+	if !reflect.DeepEqual(env, want) {
+		t.Errorf("expected %v, got %v", want, env)
+	}
+}
+
+// ---------------------------------------------------------------------------
+// ParseServerInfo
+// ---------------------------------------------------------------------------
+
+func TestParseServerInfo_pidStartAndVersion(t *testing.T) {
+	info, err := ParseServerInfo("3943|1700000000|3.4\n")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if info.PID != "3943" || info.Version != "3.4" || !info.Started.Equal(time.Unix(1700000000, 0)) {
+		t.Errorf("unexpected info %+v", info)
+	}
+	if _, err := ParseServerInfo("no server running"); err == nil {
+		t.Error("expected an error for unexpected output")
+	}
+}
@@ -0,0 +1,76 @@
+package tmux
+
+import (
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+)
+
+// RemoteOptions describes how to reach tmux on another machine over SSH.
+type RemoteOptions struct {
+	Address      string // ssh destination, e.g. "dev@devbox"
+	Port         int    // optional ssh port
+	ProxyJump    string // optional jump host(s), passed as -J
+	IdentityFile string // optional private key, passed as -i
+	TmuxPath     string // tmux binary on the remote host (default "tmux")
+	SocketName   string // optional tmux socket name, passed as -L
+}
+
+// NewRemoteClient creates a tmux client that runs every command through ssh.
+// The connection is not checked here; use Version to probe reachability.
+func NewRemoteClient(opts RemoteOptions) (*Client, error) {
+	if opts.Address == "" {
+		return nil, fmt.Errorf("remote host address is required")
+	}
+	sshPath, err := exec.LookPath("ssh")
+	if err != nil {
+		return nil, fmt.Errorf("ssh not found: %w", err)
+	}
+	tmuxPath := opts.TmuxPath
+	if tmuxPath == "" {
+		tmuxPath = "tmux"
+	}
+	return &Client{
+		tmuxPath:   tmuxPath,
+		socketName: opts.SocketName,
+		ssh:        append([]string{sshPath}, sshArgs(opts)...),
+	}, nil
+}
+
+// sshArgs builds the ssh options and destination for opts.
+// BatchMode keeps ssh from prompting for passwords inside the TUI.
+func sshArgs(opts RemoteOptions) []string {
+	args := []string{"-o", "BatchMode=yes", "-o", "ConnectTimeout=5"}
+	if opts.Port > 0 {
+		args = append(args, "-p", strconv.Itoa(opts.Port))
+	}
+	if opts.ProxyJump != "" {
+		args = append(args, "-J", opts.ProxyJump)
+	}
+	if opts.IdentityFile != "" {
+		args = append(args, "-i", opts.IdentityFile)
+	}
+	return append(args, "--", opts.Address)
+}
+
+// remotePath quotes path for the remote shell like shellQuote, except that
+// a leading ~/, as in a tmux_path of ~/bin/tmux, is left for the shell to
+// expand to the remote home directory, which quoting would prevent.
+func remotePath(path string) string {
+	if rest, ok := strings.CutPrefix(path, "~/"); ok {
+		return `"$HOME"/` + shellQuote(rest)
+	}
+	return shellQuote(path)
+}
+
+// shellQuote quotes s for safe use as a single POSIX shell word.
+func shellQuote(s string) string {
+	if s != "" && strings.IndexFunc(s, func(r rune) bool {
+		return !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' ||
+			strings.ContainsRune("-_./:=@%+,", r))
+	}) < 0 {
+		return s
+	}
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
@@ -0,0 +1,134 @@
+package tmux
+
+import (
+	"context"
+	"os/exec"
+	"strings"
+	"testing"
+)
+
+// ---------------------------------------------------------------------------
+// shellQuote
+// ---------------------------------------------------------------------------
+
+func TestShellQuote_tableTests(t *testing.T) {
+	cases := []struct {
+		in, want string
+	}{
+		{"list-sessions", "list-sessions"},
+		{"/usr/local/bin/tmux", "/usr/local/bin/tmux"},
+		{"", "''"},
+		{"#{session_name}|#{session_path}", "'#{session_name}|#{session_path}'"},
+		{"it's", `'it'\''s'`},
+		{"a b", "'a b'"},
+	}
+	for _, tc := range cases {
+		if got := shellQuote(tc.in); got != tc.want {
+			t.Errorf("shellQuote(%q): expected %q, got %q", tc.in, tc.want, got)
+		}
+	}
+}
+
+// ---------------------------------------------------------------------------
+// sshArgs
+// ---------------------------------------------------------------------------
+
+func TestSSHArgs_minimalHasBatchModeAndDestination(t *testing.T) {
+	args := sshArgs(RemoteOptions{Address: "dev@box"})
+	joined := strings.Join(args, " ")
+	if !strings.Contains(joined, "BatchMode=yes") {
+		t.Errorf("expected BatchMode option, got %q", joined)
+	}
+	if args[len(args)-1] != "dev@box" || args[len(args)-2] != "--" {
+		t.Errorf("expected args to end with '-- dev@box', got %q", joined)
+	}
+}
+
+func TestSSHArgs_includesJumpIdentityAndPort(t *testing.T) {
+	args := sshArgs(RemoteOptions{
+		Address:      "box",
+		Port:         2222,
+		ProxyJump:    "bastion",
+		IdentityFile: "~/.ssh/id_dev",
+	})
+	joined := strings.Join(args, " ")
+	for _, want := range []string{"-p 2222", "-J bastion", "-i ~/.ssh/id_dev"} {
+		if !strings.Contains(joined, want) {
+			t.Errorf("expected %q in %q", want, joined)
+		}
+	}
+}
+
+// ---------------------------------------------------------------------------
+// Client.command
+// ---------------------------------------------------------------------------
+
+func TestCommand_localClientRunsTmuxDirectly(t *testing.T) {
+	c := &Client{tmuxPath: "/usr/bin/tmux", socketName: "work"}
+	cmd := c.command(context.Background(), "list-sessions")
+	want := []string{"/usr/bin/tmux", "-L", "work", "list-sessions"}
+	if strings.Join(cmd.Args, " ") != strings.Join(want, " ") {
+		t.Errorf("expected %v, got %v", want, cmd.Args)
+	}
+	if c.IsRemote() {
+		t.Error("expected local client not to be remote")
+	}
+}
+
+func TestCommand_remoteClientQuotesRemoteArgv(t *testing.T) {
+	c := &Client{
+		tmuxPath: "/opt/tmux",
+		ssh:      []string{"/usr/bin/ssh", "--", "box"},
+	}
+	cmd := c.command(context.Background(), "list-sessions", "-F", SessionFormat)
+	if cmd.Args[0] != "/usr/bin/ssh" {
+		t.Fatalf("expected ssh as argv[0], got %q", cmd.Args[0])
+	}
+	remote := cmd.Args[len(cmd.Args)-1]
+	if !strings.HasPrefix(remote, "/opt/tmux list-sessions -F '") {
+		t.Errorf("unexpected remote command %q", remote)
+	}
+	if !c.IsRemote() {
+		t.Error("expected remote client to report IsRemote")
+	}
+}
+
+func TestAttachCommand_remoteClientRequestsTTY(t *testing.T) {
+	c := &Client{tmuxPath: "tmux", ssh: []string{"/usr/bin/ssh", "--", "box"}}
+	cmd := c.AttachCommand("cd-api")
+	if len(cmd.Args) < 2 || cmd.Args[1] != "-t" {
+		t.Errorf("expected ssh -t, got %v", cmd.Args)
+	}
+	if got := cmd.Args[len(cmd.Args)-1]; got != "tmux attach-session -t cd-api" {
+		t.Errorf("unexpected remote command %q", got)
+	}
+	if len(c.ssh) != 3 {
+		t.Errorf("expected client ssh args to be left untouched, got %v", c.ssh)
+	}
+}
+
+func TestAttachLine(t *testing.T) {
+	local := &Client{tmuxPath: "/usr/bin/tmux", socketName: "work"}
+	if got := local.AttachLine("cd-api"); got != "tmux -L work attach -t cd-api" {
+		t.Errorf("unexpected local line %q", got)
+	}
+	remote := &Client{tmuxPath: "tmux", ssh: append([]string{"/usr/bin/ssh"}, sshArgs(RemoteOptions{Address: "dev@box", Port: 2222, ProxyJump: "bastion"})...)}
+	if got := remote.AttachLine("cd-api"); got != "ssh -t -p 2222 -J bastion dev@box 'tmux attach -t cd-api'" {
+		t.Errorf("unexpected remote line %q", got)
+	}
+}
+
+func TestCommand_remoteTmuxPathInHomeIsExpanded(t *testing.T) {
+	c := &Client{tmuxPath: "~/bin/tmux", ssh: []string{"/usr/bin/ssh", "--", "box"}}
+	cmd := c.command(context.Background(), "list-sessions")
+	if got := cmd.Args[len(cmd.Args)-1]; got != `"$HOME"/bin/tmux list-sessions` {
+		t.Errorf("unexpected remote command %q", got)
+	}
+	out, err := exec.Command("sh", "-c", "HOME=/home/dev; echo "+remotePath("~/my tools/tmux")).Output()
+	if err != nil {
+		t.Skip("no sh")
+	}
+	if got := strings.TrimSpace(string(out)); got != "/home/dev/my tools/tmux" {
+		t.Errorf("expected the shell to expand the home directory, got %q", got)
+	}
+}
@@ -0,0 +1,119 @@
+package ui
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/seunggabi/claude-dashboard/internal/archive"
+	"github.com/seunggabi/claude-dashboard/internal/config"
+	"github.com/seunggabi/claude-dashboard/internal/git"
+	"github.com/seunggabi/claude-dashboard/internal/locale"
+	"github.com/seunggabi/claude-dashboard/internal/styles"
+)
+
+// archiveChromeRows is the height of the archive view outside its rows:
+// title, rules, header, blank lines, the path of the highlighted entry and
+// the help line.
+const archiveChromeRows = 8
+
+// RenderArchive renders the archived sessions, most recent first, with the
+// entry at cursor highlighted. query is the search they were found by.
+func RenderArchive(entries []archive.Entry, cursor int, query string, width, height int) string {
+	var b strings.Builder
+
+	title := fmt.Sprintf(" Archive: %d session(s) ", len(entries))
+	if query != "" {
+		title = fmt.Sprintf(" Archive: %d session(s) matching %q ", len(entries), query)
+	}
+	b.WriteString(styles.Title.Render(title))
+	b.WriteString("\n")
+	b.WriteString(strings.Repeat("─", width))
+	b.WriteString("\n")
+
+	nameWidth := width - 2 - 24 - 18 - 8 - 14
+	if nameWidth < 12 {
+		nameWidth = 12
+	}
+	row := func(name, project, archived, idle, conv string) string {
+		return fmt.Sprintf("  %-*s%-24s%-18s%-8s%-14s", nameWidth, truncate(name, nameWidth-2), truncate(project, 22), archived, idle, conv)
+	}
+	b.WriteString(styles.Header.Render(row("NAME", "PROJECT", "ARCHIVED", "IDLE", "CONVERSATION")))
+	b.WriteString("\n")
+
+	if len(entries) == 0 {
+		b.WriteString("\n")
+		if query != "" {
+			b.WriteString(styles.Muted.Render("  Nothing archived matches. Press 'backspace' to clear the search."))
+		} else {
+			b.WriteString(styles.Muted.Render("  Nothing archived. Set archive_after to archive sessions left idle, or import transcripts."))
+		}
+		b.WriteString("\n")
+	}
+
+	limit := height - archiveChromeRows
+	if limit < 1 {
+		limit = 1
+	}
+	start := 0
+	if cursor >= limit {
+		start = cursor - limit + 1
+	}
+	for i := start; i < len(entries) && i < start+limit; i++ {
+		e := entries[i]
+		conv := "-"
+		switch {
+		case e.Imported():
+			conv = "imported"
+		case e.Conversation != "":
+			conv = "saved"
+		}
+		line := row(e.Name, e.Project, locale.Current().DateTime(e.Archived), idleLabel(e), conv)
+		if i == cursor {
+			b.WriteString(styles.Selected.Width(width).Render(line))
+		} else {
+			b.WriteString(line)
+		}
+		b.WriteString("\n")
+	}
+
+	b.WriteString("\n")
+	if cursor < len(entries) {
+		home, _ := os.UserHomeDir()
+		e := entries[cursor]
+		where := FormatPath(e.Path, home, config.PathStyleHome, width-4)
+		if e.Imported() {
+			where = truncate("from "+e.Source, width-4)
+		}
+		b.WriteString(styles.Muted.Render("  " + where))
+		b.WriteString("\n")
+	}
+	b.WriteString(strings.Repeat("─", width))
+	b.WriteString("\n")
+	b.WriteString(styles.Help.Render("  Press 'enter' to restore, 'c' for the conversation, 'l' for the pane history, 'g' for its commits, '/' to search, 'esc' to go back"))
+	b.WriteString("\n")
+
+	return b.String()
+}
+
+// idleLabel is how long an entry had been idle when archived, in hours.
+func idleLabel(e archive.Entry) string {
+	if e.Idle <= 0 {
+		return "-"
+	}
+	return fmt.Sprintf("%.0fh", e.Idle.Hours())
+}
+
+// RenderCommits lists the commits made in the session called name, newest
+// first, for the log viewer.
+func RenderCommits(name string, commits []git.Commit) string {
+	if len(commits) == 0 {
+		return fmt.Sprintf("No commits carry a %s: %s trailer.\nRun 'claude-dashboard git-hook install' in a repository to add it to new commits.", git.SessionTrailer, name)
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "%d commit(s) made in %s\n\n", len(commits), name)
+	for _, c := range commits {
+		fmt.Fprintf(&b, "%s  %s  %-16s %s\n", c.Hash, locale.Current().DateTime(c.Time), truncate(c.Author, 16), c.Subject)
+	}
+	return b.String()
+}
@@ -0,0 +1,86 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/charmbracelet/x/ansi"
+	"github.com/seunggabi/claude-dashboard/internal/archive"
+	"github.com/seunggabi/claude-dashboard/internal/git"
+)
+
+// ---------------------------------------------------------------------------
+// RenderArchive
+// ---------------------------------------------------------------------------
+
+func TestRenderArchive_listsEntriesWithTheHighlightedPath(t *testing.T) {
+	at := time.Date(2025, 11, 24, 9, 0, 0, 0, time.UTC)
+	entries := []archive.Entry{
+		{Name: "cd-api", Project: "api", Path: "/src/api", Archived: at, Idle: 9 * time.Hour, Conversation: "abc"},
+		{Name: "cd-web", Project: "web", Path: "/src/web", Archived: at.Add(-time.Hour)},
+	}
+	out := ansi.Strip(RenderArchive(entries, 1, "", 120, 20))
+	for _, want := range []string{"Archive: 2 session(s)", "cd-api", "9h", "saved", "cd-web", "/src/web"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %q in:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "/src/api") {
+		t.Errorf("expected only the highlighted path in:\n%s", out)
+	}
+}
+
+func TestRenderArchive_scrollsToTheCursor(t *testing.T) {
+	var entries []archive.Entry
+	for _, name := range []string{"cd-a", "cd-b", "cd-c", "cd-d", "cd-e"} {
+		entries = append(entries, archive.Entry{Name: name})
+	}
+	out := ansi.Strip(RenderArchive(entries, 4, "", 100, archiveChromeRows+2))
+	if strings.Contains(out, "cd-c") || !strings.Contains(out, "cd-d") || !strings.Contains(out, "cd-e") {
+		t.Errorf("expected the last two entries shown in:\n%s", out)
+	}
+}
+
+func TestRenderArchive_empty(t *testing.T) {
+	out := ansi.Strip(RenderArchive(nil, 0, "", 100, 20))
+	if !strings.Contains(out, "Nothing archived") {
+		t.Errorf("expected an empty notice in:\n%s", out)
+	}
+}
+
+func TestRenderArchive_showsTheSearchAndImportedEntries(t *testing.T) {
+	entries := []archive.Entry{{Name: "handoff", Project: "ci", Source: "/tmp/handoff.json", Archived: time.Now()}}
+	out := ansi.Strip(RenderArchive(entries, 0, "flaky", 140, 20))
+	for _, want := range []string{`matching "flaky"`, "imported", "from /tmp/handoff.json"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %q in:\n%s", want, out)
+		}
+	}
+	if out := ansi.Strip(RenderArchive(nil, 0, "flaky", 100, 20)); !strings.Contains(out, "Nothing archived matches") {
+		t.Errorf("expected a no-match notice in:\n%s", out)
+	}
+}
+
+// ---------------------------------------------------------------------------
+// RenderCommits
+// ---------------------------------------------------------------------------
+
+func TestRenderCommits_listsCommits(t *testing.T) {
+	at := time.Date(2025, 11, 24, 9, 0, 0, 0, time.UTC)
+	out := RenderCommits("cd-api", []git.Commit{
+		{Hash: "abc1234", Author: "Ada", Subject: "Fix the retry", Time: at},
+		{Hash: "def5678", Author: "Ada", Subject: "Add the retry", Time: at.Add(-time.Hour)},
+	})
+	for _, want := range []string{"2 commit(s) made in cd-api", "abc1234", "Fix the retry", "def5678"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %q in:\n%s", want, out)
+		}
+	}
+}
+
+func TestRenderCommits_explainsTheHookWhenNone(t *testing.T) {
+	if out := RenderCommits("cd-api", nil); !strings.Contains(out, "git-hook install") {
+		t.Errorf("expected a pointer to the hook, got:\n%s", out)
+	}
+}
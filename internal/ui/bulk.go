@@ -0,0 +1,61 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/seunggabi/claude-dashboard/internal/session"
+	"github.com/seunggabi/claude-dashboard/internal/styles"
+)
+
+// bulkChromeRows is the height of the bulk summary outside its item list:
+// title, rules, blank lines and the help line.
+const bulkChromeRows = 6
+
+// RenderBulkResult renders the summary of a bulk operation: one line per
+// session, failures first, with the error of each failed item.
+func RenderBulkResult(r session.BulkResult, width, height int) string {
+	var b strings.Builder
+
+	b.WriteString(styles.Title.Render(" " + r.Summary() + " "))
+	b.WriteString("\n")
+	b.WriteString(strings.Repeat("─", width))
+	b.WriteString("\n\n")
+
+	items := append(r.Failed(), succeededItems(r)...)
+	limit := height - bulkChromeRows
+	if limit < 1 {
+		limit = 1
+	}
+	for i, it := range items {
+		if i == limit-1 && len(items) > limit {
+			b.WriteString(styles.Muted.Render(fmt.Sprintf("  … %d more", len(items)-i)))
+			b.WriteString("\n")
+			break
+		}
+		if it.Err != nil {
+			line := fmt.Sprintf("✗ %s  %v", it.Name, it.Err)
+			b.WriteString("  " + styles.Error.Render(truncate(line, width-2)))
+		} else {
+			b.WriteString("  " + styles.Active.Render("✓") + " " + truncate(it.Name, width-4))
+		}
+		b.WriteString("\n")
+	}
+
+	b.WriteString("\n")
+	b.WriteString(strings.Repeat("─", width))
+	b.WriteString("\n")
+	b.WriteString(styles.Help.Render("  Press any key to close"))
+	b.WriteString("\n")
+
+	return b.String()
+}
+
+// succeededItems returns the items of r that succeeded.
+func succeededItems(r session.BulkResult) []session.BulkItem {
+	var items []session.BulkItem
+	for _, name := range r.Succeeded() {
+		items = append(items, session.BulkItem{Name: name})
+	}
+	return items
+}
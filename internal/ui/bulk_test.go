@@ -0,0 +1,44 @@
+package ui
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/x/ansi"
+	"github.com/seunggabi/claude-dashboard/internal/session"
+)
+
+// ---------------------------------------------------------------------------
+// RenderBulkResult
+// ---------------------------------------------------------------------------
+
+func TestRenderBulkResult_listsFailuresFirstWithErrors(t *testing.T) {
+	r := session.BulkResult{Op: "Killed"}
+	r.Add("cd-a", nil)
+	r.Add("cd-b", errors.New("no such session"))
+
+	out := ansi.Strip(RenderBulkResult(r, 80, 20))
+	if !strings.Contains(out, "Killed 1 of 2 session(s)") {
+		t.Errorf("expected summary title in:\n%s", out)
+	}
+	failed := strings.Index(out, "✗ cd-b  no such session")
+	ok := strings.Index(out, "✓ cd-a")
+	if failed < 0 || ok < 0 || failed > ok {
+		t.Errorf("expected the failure listed before the success in:\n%s", out)
+	}
+}
+
+func TestRenderBulkResult_collapsesItemsBeyondHeight(t *testing.T) {
+	r := session.BulkResult{Op: "Sent to"}
+	for i := 0; i < 10; i++ {
+		r.Add("cd-x", errors.New("boom"))
+	}
+	out := ansi.Strip(RenderBulkResult(r, 80, bulkChromeRows+4))
+	if got := strings.Count(out, "✗"); got != 3 {
+		t.Errorf("expected 3 items shown, got %d in:\n%s", got, out)
+	}
+	if !strings.Contains(out, "… 7 more") {
+		t.Errorf("expected a count of hidden items in:\n%s", out)
+	}
+}
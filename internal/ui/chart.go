@@ -0,0 +1,179 @@
+package ui
+
+import (
+	"math"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/seunggabi/claude-dashboard/internal/styles"
+)
+
+// chartBlocks are the partial cell fills, in eighths, used for chart bars.
+var chartBlocks = []rune{' ', '▁', '▂', '▃', '▄', '▅', '▆', '▇', '█'}
+
+// Sparkline draws the latest width of values, oldest first, as a line of
+// blocks scaled to the largest: nothing written is the lowest block, so a
+// stuck session shows flat, and refreshes not seen yet are blank.
+func Sparkline(values []int64, width int) string {
+	if width <= 0 {
+		return ""
+	}
+	if len(values) > width {
+		values = values[len(values)-width:]
+	}
+	var top int64
+	for _, v := range values {
+		top = max(top, v)
+	}
+	var b strings.Builder
+	b.WriteString(strings.Repeat(" ", width-len(values)))
+	for _, v := range values {
+		level := 1
+		if top > 0 && v > 0 {
+			level = 1 + int((v*int64(len(chartBlocks)-2)+top-1)/top)
+		}
+		b.WriteRune(chartBlocks[level])
+	}
+	return b.String()
+}
+
+// chartLabelWidth is the width of the y-axis label column.
+const chartLabelWidth = 8
+
+// ChartPoint is a value observed at a point in time.
+type ChartPoint struct {
+	Time  time.Time
+	Value float64
+}
+
+// bucketMax spreads points over n equal time buckets in [start, end) and
+// keeps the largest value per bucket. Buckets before the first point are
+// NaN (no data); later empty buckets repeat the previous value, since a
+// gauge keeps its reading between samples.
+func bucketMax(points []ChartPoint, start, end time.Time, n int) []float64 {
+	out := make([]float64, n)
+	for i := range out {
+		out[i] = math.NaN()
+	}
+	span := end.Sub(start)
+	if n == 0 || span <= 0 {
+		return out
+	}
+	for _, p := range points {
+		i := bucketIndex(p.Time, start, span, n)
+		if i < 0 {
+			continue
+		}
+		if math.IsNaN(out[i]) || p.Value > out[i] {
+			out[i] = p.Value
+		}
+	}
+	last := math.NaN()
+	for i, v := range out {
+		if math.IsNaN(v) {
+			out[i] = last
+		} else {
+			last = v
+		}
+	}
+	return out
+}
+
+// rollingRate samples n evenly spaced instants in (start, end] and returns,
+// for each, the per-minute rate of the values summed over the preceding
+// minute. Bursty series such as tokens per reply read better smoothed this
+// way than bucketed into columns a few seconds wide.
+func rollingRate(points []ChartPoint, start, end time.Time, n int) []float64 {
+	out := make([]float64, n)
+	span := end.Sub(start)
+	if n == 0 || span <= 0 {
+		return out
+	}
+	for i := range out {
+		to := start.Add(span * time.Duration(i+1) / time.Duration(n))
+		from := to.Add(-time.Minute)
+		for _, p := range points {
+			if !p.Time.Before(from) && p.Time.Before(to) {
+				out[i] += p.Value
+			}
+		}
+	}
+	return out
+}
+
+// bucketIndex returns the bucket t falls in, or -1 outside [start, start+span).
+func bucketIndex(t, start time.Time, span time.Duration, n int) int {
+	off := t.Sub(start)
+	if off < 0 || off >= span {
+		return -1
+	}
+	return int(int64(off) * int64(n) / int64(span))
+}
+
+// RenderChart draws values as a bar chart height rows tall, one column per
+// value, with the scale on the left. NaN values are left blank. The scale
+// tops out at the largest value, but never below floor.
+func RenderChart(values []float64, height int, floor float64, color lipgloss.Color, format func(float64) string) string {
+	if height < 1 {
+		height = 1
+	}
+	top := floor
+	for _, v := range values {
+		if !math.IsNaN(v) && v > top {
+			top = v
+		}
+	}
+	if top <= 0 {
+		top = 1
+	}
+
+	bar := lipgloss.NewStyle().Foreground(color)
+	var b strings.Builder
+	for row := 0; row < height; row++ {
+		label := ""
+		switch row {
+		case 0:
+			label = format(top)
+		case height - 1:
+			label = format(0)
+		}
+		b.WriteString(styles.Muted.Render(padLeft(label, chartLabelWidth-1) + "│"))
+
+		// Fill of this row in eighths: rows are counted from the bottom.
+		base := float64(height - 1 - row)
+		var cells strings.Builder
+		for _, v := range values {
+			if math.IsNaN(v) {
+				cells.WriteRune(' ')
+				continue
+			}
+			level := v/top*float64(height) - base
+			idx := int(math.Round(level * 8))
+			if idx < 0 {
+				idx = 0
+			}
+			if idx > 8 {
+				idx = 8
+			}
+			// Keep a visible baseline for small non-zero readings.
+			if row == height-1 && idx == 0 && v > 0 {
+				idx = 1
+			}
+			cells.WriteRune(chartBlocks[idx])
+		}
+		b.WriteString(bar.Render(cells.String()))
+		if row < height-1 {
+			b.WriteString("\n")
+		}
+	}
+	return b.String()
+}
+
+// padLeft right-aligns s in a field of width n.
+func padLeft(s string, n int) string {
+	if len(s) >= n {
+		return s
+	}
+	return strings.Repeat(" ", n-len(s)) + s
+}
@@ -0,0 +1,81 @@
+package ui
+
+import (
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// ---------------------------------------------------------------------------
+// Sparkline
+// ---------------------------------------------------------------------------
+
+func TestSparkline_scaledToLargestAndPaddedToWidth(t *testing.T) {
+	if got := Sparkline([]int64{0, 100, 50, 1}, 6); got != "  ▁█▅▂" {
+		t.Errorf("expected quiet low, busiest full, got %q", got)
+	}
+	if got := Sparkline([]int64{5, 0, 0, 0}, 3); got != "▁▁▁" {
+		t.Errorf("expected only the latest values, all quiet, got %q", got)
+	}
+	if got := Sparkline(nil, 0); got != "" {
+		t.Errorf("expected nothing at width 0, got %q", got)
+	}
+}
+
+// ---------------------------------------------------------------------------
+// bucketMax / rollingRate
+// ---------------------------------------------------------------------------
+
+func TestBucketMax_leadingGapIsNaNAndLaterGapsCarryForward(t *testing.T) {
+	start := time.Unix(1700000000, 0)
+	end := start.Add(4 * time.Minute)
+	points := []ChartPoint{
+		{Time: start.Add(70 * time.Second), Value: 5},
+		{Time: start.Add(80 * time.Second), Value: 9},
+	}
+	got := bucketMax(points, start, end, 4)
+	if !math.IsNaN(got[0]) {
+		t.Errorf("expected no data before the first sample, got %v", got[0])
+	}
+	if got[1] != 9 || got[2] != 9 || got[3] != 9 {
+		t.Errorf("expected peak 9 carried forward, got %v", got)
+	}
+}
+
+func TestRollingRate_sumsThePrecedingMinute(t *testing.T) {
+	start := time.Unix(1700000000, 0)
+	end := start.Add(4 * time.Minute)
+	points := []ChartPoint{
+		{Time: start.Add(10 * time.Second), Value: 300},
+		{Time: start.Add(100 * time.Second), Value: 600},
+		{Time: end, Value: 1000}, // outside the window
+	}
+	got := rollingRate(points, start, end, 4) // instants at 1m, 2m, 3m, 4m
+	want := []float64{300, 600, 0, 0}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("expected %v, got %v", want, got)
+		}
+	}
+}
+
+// ---------------------------------------------------------------------------
+// RenderChart
+// ---------------------------------------------------------------------------
+
+func TestRenderChart_fullValueFillsEveryRow(t *testing.T) {
+	format := func(v float64) string { return "" }
+	out := RenderChart([]float64{10, 0, math.NaN()}, 3, 10, lipgloss.Color("#fff"), format)
+	lines := strings.Split(out, "\n")
+	if len(lines) != 3 {
+		t.Fatalf("expected 3 rows, got %d", len(lines))
+	}
+	for i, line := range lines {
+		if strings.Count(line, "█") != 1 {
+			t.Errorf("row %d: expected exactly one full block, got %q", i, line)
+		}
+	}
+}
@@ -0,0 +1,276 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/seunggabi/claude-dashboard/internal/config"
+	"github.com/seunggabi/claude-dashboard/internal/conversation"
+	"github.com/seunggabi/claude-dashboard/internal/locale"
+	"github.com/seunggabi/claude-dashboard/internal/session"
+	"github.com/seunggabi/claude-dashboard/internal/styles"
+	"github.com/seunggabi/claude-dashboard/internal/usage"
+)
+
+// indexWidth is the width of the row number column, which always comes
+// first.
+const indexWidth = 4
+
+// Column is a column of the session table. Widths include the two spaces
+// that separate it from the next column.
+type Column struct {
+	Name  string // as listed in the columns setting
+	Title string
+	Width int // preferred width; a flexible column may grow past it
+	Flex  int // share of the left over width a flexible column gets; 0 for a fixed column
+	Min   int // least width the column is narrowed to when space is short
+	cell  func(s session.Session, c cellContext) string
+
+	// tone, if set, picks a style of its own for the cell of s in an
+	// unselected row, such as a warning color; false leaves the row's.
+	tone func(s session.Session) (lipgloss.Style, bool)
+}
+
+// cellContext is what cells need besides their session.
+type cellContext struct {
+	home  string // local home directory, for paths
+	opts  DashboardOptions
+	icons session.IconSet
+	width int // of the cell's content
+}
+
+// TableColumns are the columns the session table can show, by name; see
+// config.Columns.
+var TableColumns = map[string]Column{
+	"name":     {Name: "name", Title: "NAME", Width: 24, Flex: 1, Min: 14, cell: nameCell},
+	"host":     {Name: "host", Title: "HOST", Width: 12, Min: 8, cell: func(s session.Session, _ cellContext) string { return s.HostName() }},
+	"project":  {Name: "project", Title: "PROJECT", Width: 35, Min: 12, cell: func(s session.Session, _ cellContext) string { return s.Project }},
+	"branch":   {Name: "branch", Title: "BRANCH", Width: 22, Min: 10, cell: func(s session.Session, _ cellContext) string { return s.Git.Short() }},
+	"issue":    {Name: "issue", Title: "ISSUE", Width: 11, Min: 8, cell: func(s session.Session, _ cellContext) string { return s.Issue }},
+	"status":   {Name: "status", Title: "STATUS", Width: 12, Min: 12, cell: func(s session.Session, c cellContext) string { return s.StatusLabel(c.icons) }},
+	"mode":     {Name: "mode", Title: "MODE", Width: 13, Min: 8, cell: func(s session.Session, _ cellContext) string { return s.Mode.Label() }, tone: modeTone},
+	"waiting":  {Name: "waiting", Title: "WAITING ON", Width: 30, Flex: 1, Min: 12, cell: func(s session.Session, _ cellContext) string { return s.Question }},
+	"test":     {Name: "test", Title: "TEST", Width: 12, Min: 9, cell: func(s session.Session, _ cellContext) string { return s.Test.Short() }},
+	"uptime":   {Name: "uptime", Title: "UPTIME", Width: 10, Min: 8, cell: func(s session.Session, _ cellContext) string { return s.Uptime() }},
+	"cpu":      {Name: "cpu", Title: "CPU", Width: 8, Min: 7, cell: func(s session.Session, _ cellContext) string { return locale.Current().Percent(s.CPU) }},
+	"mem":      {Name: "mem", Title: "MEM", Width: 8, Min: 7, cell: func(s session.Session, _ cellContext) string { return locale.Current().Percent(s.Memory) }},
+	"tokens":   {Name: "tokens", Title: "TOKENS", Width: 9, Min: 8, cell: tokensCell},
+	"context":  {Name: "context", Title: "CONTEXT", Width: 12, Min: 9, cell: contextCell, tone: contextTone},
+	"activity": {Name: "activity", Title: "ACTIVITY", Width: 14, Min: 8, cell: func(s session.Session, c cellContext) string { return Sparkline(s.Output, c.width) }},
+	"path":     {Name: "path", Title: "PATH", Width: 30, Flex: 2, Min: 12, cell: pathCell},
+}
+
+// nameCell is the session name, marked when it edits the same files as
+// another session and followed by the window the filter matched.
+func nameCell(s session.Session, c cellContext) string {
+	name := s.Name
+	if !s.Conflict.IsZero() {
+		name = "⚠ " + name
+	}
+	if label, ok := session.MatchedWindow(s, session.ParseQuery(c.opts.Query).Text); ok {
+		name += " ▸ " + label
+	}
+	return name
+}
+
+// tokensCell is the tokens of the session's conversation, read only while
+// the column is shown or a spend cap is set.
+func tokensCell(s session.Session, _ cellContext) string {
+	if s.Spend.Tokens == 0 {
+		return "-"
+	}
+	return conversation.FormatTokens(s.Spend.Tokens)
+}
+
+func pathCell(s session.Session, c cellContext) string {
+	home := c.home
+	if s.Host != "" {
+		home = "" // the local home says nothing about remote paths
+	}
+	return FormatPath(s.Path, home, c.opts.PathStyle, c.width)
+}
+
+// tableColumn is a column laid out at a width.
+type tableColumn struct {
+	Column
+	width int
+}
+
+// defaultColumns returns the columns shown without a columns setting; the
+// host, branch, issue and test columns only as opts asks.
+func defaultColumns(opts DashboardOptions) []config.ColumnSpec {
+	var specs []config.ColumnSpec
+	for _, name := range []string{"name", "host", "project", "branch", "issue", "status", "test", "uptime", "cpu", "mem", "path"} {
+		switch {
+		case name == "host" && !opts.ShowHost,
+			name == "branch" && !opts.ShowBranch,
+			name == "issue" && !opts.ShowIssues,
+			name == "test" && !opts.ShowTests:
+			continue
+		}
+		specs = append(specs, config.ColumnSpec{Name: name})
+	}
+	return specs
+}
+
+// layoutColumns fits the columns of opts into width. Columns get their
+// preferred width while there is room, and flexible ones share what is left
+// by their Flex. When space is short, every column is narrowed toward its
+// Min, each by its share of the slack; columns that do not fit even at
+// their Min are dropped from the right.
+func layoutColumns(opts DashboardOptions, width int) []tableColumn {
+	specs := defaultColumns(opts)
+	if len(opts.Columns) > 0 {
+		specs = nil
+		for _, c := range opts.Columns {
+			if spec, err := config.ParseColumn(c); err == nil {
+				specs = append(specs, spec)
+			}
+		}
+	}
+	cols := make([]tableColumn, 0, len(specs))
+	for _, spec := range specs {
+		col := TableColumns[spec.Name]
+		if spec.Width > 0 {
+			col.Width, col.Flex = spec.Width, 0
+			col.Min = min(col.Min, spec.Width)
+		}
+		cols = append(cols, tableColumn{Column: col, width: col.Width})
+	}
+
+	available := width - 2 - indexWidth // left margin and row number
+	least := 0
+	for _, c := range cols {
+		least += c.Min
+	}
+	for len(cols) > 1 && least > available {
+		least -= cols[len(cols)-1].Min
+		cols = cols[:len(cols)-1]
+	}
+
+	over, slack := -available, 0
+	for _, c := range cols {
+		over += c.width
+		slack += c.width - c.Min
+	}
+	for i := range cols {
+		c := &cols[i]
+		if over <= 0 || slack == 0 {
+			break
+		}
+		share := c.width - c.Min
+		cut := min((over*share+slack-1)/slack, share, over)
+		c.width -= cut
+		over -= cut
+		slack -= share
+	}
+
+	left, flex := available, 0
+	for _, c := range cols {
+		left -= c.width
+		flex += c.Flex
+	}
+	for i := range cols {
+		c := &cols[i]
+		if left <= 0 || c.Flex == 0 {
+			continue
+		}
+		grow := left * c.Flex / flex
+		c.width += grow
+		left -= grow
+		flex -= c.Flex
+	}
+	return cols
+}
+
+// modeTone warns of sessions skipping permission prompts, free to run any
+// command and edit any file.
+func modeTone(s session.Session) (lipgloss.Style, bool) {
+	if s.Mode == session.ModeBypass {
+		return styles.Error, true
+	}
+	return lipgloss.Style{}, false
+}
+
+// contextCell is how full the context window of the session's conversation
+// is, e.g. "142k/200k", read along with its tokens.
+func contextCell(s session.Session, _ cellContext) string {
+	if s.Context.Tokens == 0 {
+		return "-"
+	}
+	window := usage.ContextWindow(s.Context.Model, s.Context.Tokens)
+	return contextTokens(s.Context.Tokens) + "/" + contextTokens(window)
+}
+
+// contextTokens formats a token count in whole thousands below a million,
+// so the used and window sizes line up, and compactly from there.
+func contextTokens(n int) string {
+	if n < 1_000_000 {
+		return fmt.Sprintf("%dk", (n+500)/1000)
+	}
+	return conversation.FormatTokens(n)
+}
+
+// contextWarn and contextAlarm are how full a context window is, as a
+// fraction, when the context cell is colored as waiting and as an error:
+// the conversation is getting close to being compacted.
+const (
+	contextWarn  = 0.75
+	contextAlarm = 0.9
+)
+
+// contextTone colors the context cell of a conversation near its limit.
+func contextTone(s session.Session) (lipgloss.Style, bool) {
+	if s.Context.Tokens == 0 {
+		return lipgloss.Style{}, false
+	}
+	full := float64(s.Context.Tokens) / float64(usage.ContextWindow(s.Context.Model, s.Context.Tokens))
+	switch {
+	case full >= contextAlarm:
+		return styles.Error, true
+	case full >= contextWarn:
+		return styles.Waiting, true
+	}
+	return lipgloss.Style{}, false
+}
+
+// renderCells formats a row from one value per column, each cut to fit.
+func renderCells(idx string, cols []tableColumn, values []string) string {
+	var b strings.Builder
+	b.WriteString(indexCell(idx))
+	for i, c := range cols {
+		b.WriteString(padCell(values[i], c))
+	}
+	return b.String()
+}
+
+// renderTonedRow renders an unselected row of s in base, except for the
+// cells whose column tones them, which get their own style. It returns
+// false when no cell is toned, leaving the row to be rendered whole.
+func renderTonedRow(s session.Session, idx string, cols []tableColumn, values []string, base lipgloss.Style) (string, bool) {
+	var b strings.Builder
+	b.WriteString(base.Render(indexCell(idx)))
+	toned := false
+	for i, c := range cols {
+		style := base
+		if c.tone != nil {
+			if st, ok := c.tone(s); ok {
+				style, toned = st, true
+			}
+		}
+		b.WriteString(style.Render(padCell(values[i], c)))
+	}
+	return b.String(), toned
+}
+
+// indexCell is the row number, after the left margin.
+func indexCell(idx string) string {
+	return fmt.Sprintf("  %-*s", indexWidth, idx)
+}
+
+// padCell cuts v to fit column c and pads it to the column's width.
+func padCell(v string, c tableColumn) string {
+	v = truncate(v, c.width-2)
+	return v + strings.Repeat(" ", max(c.width-lipgloss.Width(v), 0))
+}
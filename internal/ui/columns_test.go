@@ -0,0 +1,144 @@
+package ui
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/x/ansi"
+	"github.com/seunggabi/claude-dashboard/internal/config"
+	"github.com/seunggabi/claude-dashboard/internal/conversation"
+	"github.com/seunggabi/claude-dashboard/internal/session"
+)
+
+// names returns the names of cols in order.
+func names(cols []tableColumn) []string {
+	out := make([]string, len(cols))
+	for i, c := range cols {
+		out[i] = c.Name
+	}
+	return out
+}
+
+// ---------------------------------------------------------------------------
+// layoutColumns
+// ---------------------------------------------------------------------------
+
+func TestLayoutColumns_everyConfigColumnIsDefined(t *testing.T) {
+	for _, name := range config.Columns {
+		if c, ok := TableColumns[name]; !ok || c.Name != name || c.cell == nil {
+			t.Errorf("column %q is not defined", name)
+		}
+	}
+}
+
+func TestLayoutColumns_configuredOrderAndWidthFillTheRow(t *testing.T) {
+	cols := layoutColumns(DashboardOptions{Columns: []string{"status", "name", "branch:30", "uptime"}}, 120)
+	if got := names(cols); !reflect.DeepEqual(got, []string{"status", "name", "branch", "uptime"}) {
+		t.Fatalf("expected the configured order, got %v", got)
+	}
+	if cols[2].width != 30 {
+		t.Errorf("expected branch at 30, got %d", cols[2].width)
+	}
+	total := 2 + indexWidth
+	for _, c := range cols {
+		total += c.width
+	}
+	if total != 120 {
+		t.Errorf("expected the name to take the rest of the row, got %d columns", total)
+	}
+}
+
+func TestLayoutColumns_narrowsBeforeDropping(t *testing.T) {
+	cols := layoutColumns(DashboardOptions{ShowBranch: true}, 100)
+	if got := names(cols); !reflect.DeepEqual(got, []string{"name", "project", "branch", "status", "uptime", "cpu", "mem", "path"}) {
+		t.Fatalf("expected every column, got %v", got)
+	}
+	for _, c := range cols {
+		if c.width < c.Min {
+			t.Errorf("%s: expected at least %d, got %d", c.Name, c.Min, c.width)
+		}
+	}
+
+	narrow := names(layoutColumns(DashboardOptions{ShowBranch: true}, 60))
+	if narrow[len(narrow)-1] == "path" || !reflect.DeepEqual(narrow[:4], []string{"name", "project", "branch", "status"}) {
+		t.Errorf("expected columns dropped from the right, got %v", narrow)
+	}
+}
+
+func TestLayoutColumns_issueOnlyWhenLinked(t *testing.T) {
+	if got := names(layoutColumns(DashboardOptions{ShowBranch: true}, 160)); reflect.DeepEqual(got[:4], []string{"name", "project", "branch", "issue"}) {
+		t.Errorf("expected no issue column without issue linking, got %v", got)
+	}
+	if got := names(layoutColumns(DashboardOptions{ShowBranch: true, ShowIssues: true}, 160)); !reflect.DeepEqual(got[:4], []string{"name", "project", "branch", "issue"}) {
+		t.Errorf("expected the issue column after the branch, got %v", got)
+	}
+}
+
+// ---------------------------------------------------------------------------
+// RenderDashboard columns
+// ---------------------------------------------------------------------------
+
+func TestRenderDashboard_configuredColumnsWithTokens(t *testing.T) {
+	sessions := []session.Session{{Name: "cd-api", Status: session.StatusIdle, Spend: conversation.Spend{Tokens: 12000}}}
+	lines := strings.Split(ansi.Strip(RenderDashboard(sessions, 0, 120, 0, 10, DashboardOptions{Columns: []string{"name", "status", "tokens"}})), "\n")
+	if !strings.Contains(lines[0], "TOKENS") || strings.Contains(lines[0], "PROJECT") {
+		t.Errorf("expected only the configured headers, got %q", lines[0])
+	}
+	if !strings.Contains(lines[1], conversation.FormatTokens(12000)) {
+		t.Errorf("expected the token count, got %q", lines[1])
+	}
+}
+
+// ---------------------------------------------------------------------------
+// context column
+// ---------------------------------------------------------------------------
+
+func TestContextCell(t *testing.T) {
+	tests := []struct {
+		use  conversation.ContextUse
+		want string
+	}{
+		{conversation.ContextUse{}, "-"},
+		{conversation.ContextUse{Tokens: 142_300, Model: "claude-sonnet-4-5"}, "142k/200k"},
+		{conversation.ContextUse{Tokens: 420_000, Model: "claude-sonnet-4-5"}, "420k/" + conversation.FormatTokens(1_000_000)},
+	}
+	for _, tt := range tests {
+		if got := contextCell(session.Session{Context: tt.use}, cellContext{}); got != tt.want {
+			t.Errorf("contextCell(%+v) = %q, want %q", tt.use, got, tt.want)
+		}
+	}
+}
+
+func TestContextTone_onlyNearTheLimit(t *testing.T) {
+	for tokens, want := range map[int]bool{0: false, 100_000: false, 160_000: true, 190_000: true} {
+		s := session.Session{Context: conversation.ContextUse{Tokens: tokens, Model: "claude-opus-4-1"}}
+		if _, toned := contextTone(s); toned != want {
+			t.Errorf("%d tokens: expected toned %v, got %v", tokens, want, toned)
+		}
+	}
+}
+
+func TestRenderDashboard_contextColumnKeepsTheRowText(t *testing.T) {
+	sessions := []session.Session{
+		{Name: "cd-api", Status: session.StatusActive},
+		{Name: "cd-web", Status: session.StatusActive, Context: conversation.ContextUse{Tokens: 185_000, Model: "claude-sonnet-4-5"}},
+	}
+	lines := strings.Split(ansi.Strip(RenderDashboard(sessions, 0, 120, 0, 10, DashboardOptions{Columns: []string{"name", "context", "status"}})), "\n")
+	if !strings.Contains(lines[2], "185k/200k") || !strings.Contains(lines[2], "cd-web") {
+		t.Errorf("expected the toned row intact, got %q", lines[2])
+	}
+	if len(lines[2]) != len(lines[1]) {
+		t.Errorf("expected toned and plain rows aligned, got %q and %q", lines[1], lines[2])
+	}
+}
+
+func TestModeTone_onlySkippedPermissions(t *testing.T) {
+	for mode, want := range map[session.PermissionMode]bool{
+		session.ModeUnknown: false, session.ModeDefault: false, session.ModePlan: false, session.ModeBypass: true,
+	} {
+		if _, toned := modeTone(session.Session{Mode: mode}); toned != want {
+			t.Errorf("%q: expected toned %v, got %v", mode, want, toned)
+		}
+	}
+}
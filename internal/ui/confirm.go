@@ -0,0 +1,94 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/seunggabi/claude-dashboard/internal/styles"
+)
+
+// ConfirmChoice is one action a Confirm menu offers.
+type ConfirmChoice struct {
+	Key   string // picks the choice straight away, e.g. "a"
+	Label string
+}
+
+// Confirm is a modal question shown under the current view: a y/n prompt,
+// or a small menu of actions when it has choices. The zero value is closed.
+type Confirm struct {
+	Prompt  string
+	Choices []ConfirmChoice // nil for a y/n prompt
+	Cursor  int
+}
+
+// NewYesNo returns a prompt answered with y or n.
+func NewYesNo(prompt string) Confirm {
+	return Confirm{Prompt: prompt}
+}
+
+// NewConfirmMenu returns a menu of choices, the first one selected. No
+// choice may use n, which cancels like esc.
+func NewConfirmMenu(prompt string, choices []ConfirmChoice) Confirm {
+	return Confirm{Prompt: prompt, Choices: choices}
+}
+
+// Open reports whether the modal is shown.
+func (c Confirm) Open() bool {
+	return c.Prompt != ""
+}
+
+// HandleKey applies a key to the modal. done is true once it is answered:
+// picked is then the key of the chosen action ("y" for yes), or empty when
+// it was cancelled. Other keys are ignored.
+func (c *Confirm) HandleKey(key string) (picked string, done bool) {
+	switch key {
+	case "n", "N", "esc":
+		return "", true
+	}
+	if c.Choices == nil {
+		if key == "y" || key == "Y" {
+			return "y", true
+		}
+		return "", false
+	}
+	switch key {
+	case "up", "shift+tab":
+		c.Cursor = (c.Cursor + len(c.Choices) - 1) % len(c.Choices)
+	case "down", "tab":
+		c.Cursor = (c.Cursor + 1) % len(c.Choices)
+	case "enter":
+		return c.Choices[c.Cursor].Key, true
+	default:
+		for _, ch := range c.Choices {
+			if strings.EqualFold(ch.Key, key) {
+				return ch.Key, true
+			}
+		}
+	}
+	return "", false
+}
+
+// RenderConfirm renders the modal: one line for a y/n prompt, otherwise
+// the prompt over its choices and a help line.
+func RenderConfirm(c Confirm, width int) string {
+	if c.Choices == nil {
+		return styles.Confirm.Render("  " + c.Prompt + " (y/n)")
+	}
+	var b strings.Builder
+	b.WriteString(styles.Confirm.Render("  " + c.Prompt))
+	for i, ch := range c.Choices {
+		b.WriteString("\n")
+		line := fmt.Sprintf("[%s] %s", ch.Key, ch.Label)
+		if width > 6 {
+			line = truncate(line, width-6)
+		}
+		if i == c.Cursor {
+			b.WriteString("  ▸ " + styles.Selected.Render(line))
+		} else {
+			b.WriteString("    " + line)
+		}
+	}
+	b.WriteString("\n")
+	b.WriteString(styles.Help.Render("  ↑/↓ move · enter or key: pick · n/esc: cancel"))
+	return b.String()
+}
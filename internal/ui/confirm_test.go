@@ -0,0 +1,52 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+// ---------------------------------------------------------------------------
+// Confirm
+// ---------------------------------------------------------------------------
+
+func TestConfirm_yesNo(t *testing.T) {
+	c := NewYesNo("Kill session 'cd-api'?")
+	if picked, done := c.HandleKey("x"); done {
+		t.Errorf("expected other keys to be ignored, got %q", picked)
+	}
+	if picked, done := c.HandleKey("Y"); !done || picked != "y" {
+		t.Errorf("expected Y to answer yes, got %q, %v", picked, done)
+	}
+	if picked, done := c.HandleKey("esc"); !done || picked != "" {
+		t.Errorf("expected esc to cancel, got %q, %v", picked, done)
+	}
+	if out := ansi.Strip(RenderConfirm(c, 80)); out != "  Kill session 'cd-api'? (y/n)" {
+		t.Errorf("unexpected prompt %q", out)
+	}
+}
+
+func TestConfirm_menuPicksByKeyOrCursor(t *testing.T) {
+	c := NewConfirmMenu("Kill session 'cd-api'?", []ConfirmChoice{
+		{Key: "y", Label: "Kill"},
+		{Key: "a", Label: "Archive, then kill"},
+	})
+	if picked, done := c.HandleKey("A"); !done || picked != "a" {
+		t.Errorf("expected A to pick archive, got %q, %v", picked, done)
+	}
+	c.HandleKey("up")
+	if c.Cursor != 1 {
+		t.Errorf("expected up to wrap to the last choice, got %d", c.Cursor)
+	}
+	out := ansi.Strip(RenderConfirm(c, 80))
+	if !strings.Contains(out, "▸ [a] Archive, then kill") || !strings.Contains(out, "    [y] Kill") {
+		t.Errorf("expected the selected choice marked in:\n%s", out)
+	}
+	if picked, done := c.HandleKey("enter"); !done || picked != "a" {
+		t.Errorf("expected enter to pick the selected choice, got %q, %v", picked, done)
+	}
+	if picked, done := c.HandleKey("n"); !done || picked != "" {
+		t.Errorf("expected n to cancel, got %q, %v", picked, done)
+	}
+}
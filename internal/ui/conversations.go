@@ -0,0 +1,87 @@
+package ui
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/seunggabi/claude-dashboard/internal/conversation"
+	"github.com/seunggabi/claude-dashboard/internal/locale"
+	"github.com/seunggabi/claude-dashboard/internal/styles"
+)
+
+// conversationsChromeRows is the height of the conversation picker outside
+// its rows: title, rules, header, blank line, the file of the highlighted
+// log and the help line.
+const conversationsChromeRows = 7
+
+// RenderConversations renders the conversation logs of the session called
+// name, most recently written first, with the log at cursor highlighted.
+// The first is the conversation the log viewer shows by default.
+func RenderConversations(name string, logs []conversation.LogFile, cursor, width, height int) string {
+	var b strings.Builder
+
+	b.WriteString(styles.Title.Render(fmt.Sprintf(" Conversations: %s, %d log(s) ", name, len(logs))))
+	b.WriteString("\n")
+	b.WriteString(strings.Repeat("─", width))
+	b.WriteString("\n")
+
+	promptWidth := width - 2 - 21 - 21 - 8
+	if promptWidth < 12 {
+		promptWidth = 12
+	}
+	row := func(started, written, mark, prompt string) string {
+		return fmt.Sprintf("  %-21s%-21s%-8s%s", started, written, mark, truncate(prompt, promptWidth))
+	}
+	b.WriteString(styles.Header.Render(row("STARTED", "LAST WRITTEN", "", "FIRST PROMPT")))
+	b.WriteString("\n")
+
+	if len(logs) == 0 {
+		b.WriteString("\n")
+		b.WriteString(styles.Muted.Render("  No conversation logs for this session's directory yet."))
+		b.WriteString("\n")
+	}
+
+	limit := height - conversationsChromeRows
+	if limit < 1 {
+		limit = 1
+	}
+	start := 0
+	if cursor >= limit {
+		start = cursor - limit + 1
+	}
+	for i := start; i < len(logs) && i < start+limit; i++ {
+		l := logs[i]
+		started := "-"
+		if !l.Started.IsZero() {
+			started = locale.Current().DateTime(l.Started.Local())
+		}
+		mark := ""
+		if i == 0 {
+			mark = "latest"
+		}
+		prompt := l.Preview
+		if prompt == "" {
+			prompt = "(no prompt)"
+		}
+		line := row(started, locale.Current().DateTime(l.Modified), mark, prompt)
+		if i == cursor {
+			b.WriteString(styles.Selected.Width(width).Render(line))
+		} else {
+			b.WriteString(line)
+		}
+		b.WriteString("\n")
+	}
+
+	b.WriteString("\n")
+	if cursor < len(logs) {
+		b.WriteString(styles.Muted.Render("  " + truncate(filepath.Base(logs[cursor].Path), width-4)))
+		b.WriteString("\n")
+	}
+	b.WriteString(strings.Repeat("─", width))
+	b.WriteString("\n")
+	b.WriteString(styles.Help.Render("  Press 'enter' to read the conversation, 'esc' to go back"))
+	b.WriteString("\n")
+
+	return b.String()
+}
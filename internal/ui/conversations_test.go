@@ -0,0 +1,48 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/charmbracelet/x/ansi"
+	"github.com/seunggabi/claude-dashboard/internal/conversation"
+)
+
+// ---------------------------------------------------------------------------
+// RenderConversations
+// ---------------------------------------------------------------------------
+
+func TestRenderConversations_listsLogsWithTheHighlightedFile(t *testing.T) {
+	at := time.Date(2025, 11, 24, 9, 0, 0, 0, time.Local)
+	logs := []conversation.LogFile{
+		{Path: "/p/new.jsonl", Modified: at, Started: at.Add(-time.Hour), Preview: "fix the flaky test"},
+		{Path: "/p/old.jsonl", Modified: at.Add(-24 * time.Hour)},
+	}
+	out := ansi.Strip(RenderConversations("api", logs, 1, 120, 20))
+	for _, want := range []string{"Conversations: api, 2 log(s)", "latest", "fix the flaky test", "(no prompt)", "old.jsonl"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %q in:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "new.jsonl") {
+		t.Errorf("expected only the highlighted file in:\n%s", out)
+	}
+}
+
+func TestRenderConversations_scrollsToTheCursor(t *testing.T) {
+	var logs []conversation.LogFile
+	for _, p := range []string{"a", "b", "c", "d", "e"} {
+		logs = append(logs, conversation.LogFile{Path: p + ".jsonl", Preview: "prompt " + p})
+	}
+	out := ansi.Strip(RenderConversations("api", logs, 4, 100, conversationsChromeRows+2))
+	if strings.Contains(out, "prompt c") || !strings.Contains(out, "prompt d") || !strings.Contains(out, "prompt e") {
+		t.Errorf("expected the last two logs shown in:\n%s", out)
+	}
+}
+
+func TestRenderConversations_empty(t *testing.T) {
+	if out := ansi.Strip(RenderConversations("api", nil, 0, 100, 20)); !strings.Contains(out, "No conversation logs") {
+		t.Errorf("expected an empty notice in:\n%s", out)
+	}
+}
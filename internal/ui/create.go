@@ -2,18 +2,49 @@ package ui
 
 import (
 	"fmt"
+	"os"
 	"strings"
 
 	"github.com/charmbracelet/bubbles/textinput"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/seunggabi/claude-dashboard/internal/config"
+	"github.com/seunggabi/claude-dashboard/internal/conversation"
+	"github.com/seunggabi/claude-dashboard/internal/locale"
+	"github.com/seunggabi/claude-dashboard/internal/projects"
 	"github.com/seunggabi/claude-dashboard/internal/styles"
 )
 
+// MaxDirSuggestions is how many directory suggestions the form shows.
+const MaxDirSuggestions = 6
+
 // CreateForm holds the new session form state.
 type CreateForm struct {
 	NameInput textinput.Model
 	DirInput  textinput.Model
 	FocusIdx  int
 	Err       string
+	Host      string // remote host the session is created on; empty for local
+	Naming    string // naming policy names must follow, e.g. "{team}-{project}"; optional
+
+	// Dirs are the directories to suggest (see projects.Discover), Matches
+	// those matching the directory typed, and Selected the highlighted
+	// match, or -1.
+	Dirs      []string
+	Matches   []string
+	Selected  int
+	dirEdited bool // until the directory is edited, every suggestion matches
+
+	// Models are the models to choose from and Model the chosen one, as
+	// an index into them; -1 leaves the model to claude.
+	Models []string
+	Model  int
+
+	// Resumable are the conversations of ResumeDir, most recent first, and
+	// Resume the one to resume, as an index into them; -1 starts a new
+	// conversation. Only local sessions can resume.
+	Resumable []conversation.LogFile
+	ResumeDir string
+	Resume    int
 }
 
 // NewCreateForm creates a new session creation form.
@@ -36,20 +67,141 @@ func NewCreateForm(defaultDir string) CreateForm {
 		NameInput: nameInput,
 		DirInput:  dirInput,
 		FocusIdx:  0,
+		Selected:  -1,
+		Model:     -1,
+		Resume:    -1,
+	}
+}
+
+// SetDirs sets the directories to suggest.
+func (f *CreateForm) SetDirs(dirs []string) {
+	f.Dirs = dirs
+	f.updateMatches()
+}
+
+// DirEdited records that the directory input changed and matches the
+// suggestions against it.
+func (f *CreateForm) DirEdited() {
+	f.dirEdited = true
+	f.updateMatches()
+}
+
+func (f *CreateForm) updateMatches() {
+	query := ""
+	if f.dirEdited {
+		query = f.DirInput.Value()
+	}
+	home, _ := os.UserHomeDir()
+	f.Matches = projects.Match(query, f.Dirs, home, MaxDirSuggestions)
+	if f.Selected >= len(f.Matches) {
+		f.Selected = len(f.Matches) - 1
 	}
 }
 
-// FocusNext moves focus to the next input field.
+// MoveSelection highlights the next (delta 1) or previous (-1) suggestion,
+// wrapping through none.
+func (f *CreateForm) MoveSelection(delta int) {
+	n := len(f.Matches) + 1 // the matches and none
+	f.Selected = (f.Selected+1+delta+n)%n - 1
+}
+
+// AcceptSuggestion puts the highlighted suggestion in the directory input.
+// It reports false when none is highlighted.
+func (f *CreateForm) AcceptSuggestion() bool {
+	if f.Selected < 0 || f.Selected >= len(f.Matches) {
+		return false
+	}
+	f.DirInput.SetValue(f.Matches[f.Selected])
+	f.DirInput.CursorEnd()
+	f.Selected = -1
+	f.DirEdited()
+	return true
+}
+
+// FocusNext moves focus to the next field: name, directory, model when
+// there are models to choose from, then the conversation to resume for
+// local sessions.
 func (f *CreateForm) FocusNext() {
-	if f.FocusIdx == 0 {
-		f.FocusIdx = 1
-		f.NameInput.Blur()
-		f.DirInput.Focus()
-	} else {
-		f.FocusIdx = 0
-		f.DirInput.Blur()
+	fields := 2
+	if len(f.Models) > 0 {
+		fields++
+	}
+	if f.Host == "" {
+		fields++
+	}
+	f.FocusIdx = (f.FocusIdx + 1) % fields
+	f.NameInput.Blur()
+	f.DirInput.Blur()
+	switch f.FocusIdx {
+	case 0:
 		f.NameInput.Focus()
+	case 1:
+		f.DirInput.Focus()
+	}
+}
+
+// ModelFocused reports whether the model field has focus.
+func (f *CreateForm) ModelFocused() bool {
+	return len(f.Models) > 0 && f.FocusIdx == 2
+}
+
+// ResumeFocused reports whether the conversation field has focus.
+func (f *CreateForm) ResumeFocused() bool {
+	field := 2
+	if len(f.Models) > 0 {
+		field = 3
+	}
+	return f.Host == "" && f.FocusIdx == field
+}
+
+// SetResumable sets the conversations of dir to choose from, keeping the
+// chosen one if it is still there.
+func (f *CreateForm) SetResumable(dir string, logs []conversation.LogFile) {
+	chosen := f.resumeID()
+	f.Resumable, f.ResumeDir, f.Resume = logs, dir, -1
+	for i, l := range logs {
+		if l.ID() == chosen {
+			f.Resume = i
+		}
+	}
+}
+
+// MoveResume chooses the next (delta 1) or previous (-1) conversation,
+// wrapping through a new one.
+func (f *CreateForm) MoveResume(delta int) {
+	n := len(f.Resumable) + 1 // the conversations and a new one
+	f.Resume = (f.Resume+1+delta+n)%n - 1
+}
+
+// resumeID returns the id of the conversation to resume, or "" for a new
+// one. Conversations listed for another directory than the one typed are
+// not resumed.
+func (f *CreateForm) resumeID() string {
+	_, dir := f.Values()
+	if f.Resume < 0 || f.Resume >= len(f.Resumable) || dir != f.ResumeDir {
+		return ""
+	}
+	return f.Resumable[f.Resume].ID()
+}
+
+// CycleModel chooses the next (delta 1) or previous (-1) model, wrapping
+// through claude's default.
+func (f *CreateForm) CycleModel(delta int) {
+	n := len(f.Models) + 1 // the models and the default
+	f.Model = (f.Model+1+delta+n)%n - 1
+}
+
+// ClaudeArgs returns the arguments claude is started with for the chosen
+// model and conversation; "" for claude's defaults.
+func (f *CreateForm) ClaudeArgs() string {
+	var args []string
+	if f.Model >= 0 && f.Model < len(f.Models) {
+		args = append(args, "--model", f.Models[f.Model])
 	}
+	if id := f.resumeID(); id != "" {
+		args = append(args, "--resume", id)
+	}
+	return strings.Join(args, " ")
 }
 
 // Values returns the form values.
@@ -66,7 +218,7 @@ func (f *CreateForm) Validate() error {
 	if strings.Contains(name, " ") {
 		return fmt.Errorf("session name cannot contain spaces")
 	}
-	if dir == "" {
+	if dir == "" && f.Host == "" {
 		return fmt.Errorf("project directory is required")
 	}
 	return nil
@@ -76,7 +228,11 @@ func (f *CreateForm) Validate() error {
 func RenderCreateForm(form CreateForm, width int) string {
 	var b strings.Builder
 
-	title := styles.Title.Render(" New Session ")
+	titleText := " New Session "
+	if form.Host != "" {
+		titleText = fmt.Sprintf(" New Session on %s ", form.Host)
+	}
+	title := styles.Title.Render(titleText)
 	b.WriteString(title)
 	b.WriteString("\n")
 	b.WriteString(strings.Repeat("─", width))
@@ -96,6 +252,36 @@ func RenderCreateForm(form CreateForm, width int) string {
 		dirLabel = styles.StatusKey.Render("▸ Directory:")
 	}
 	b.WriteString(fmt.Sprintf("  %s  %s\n", dirLabel, form.DirInput.View()))
+	if form.FocusIdx == 1 && len(form.Matches) > 0 {
+		home, _ := os.UserHomeDir()
+		indent := strings.Repeat(" ", lipgloss.Width(dirLabel)+4)
+		for i, dir := range form.Matches {
+			line := FormatPath(dir, home, config.PathStyleHome, width-len(indent)-2)
+			if i == form.Selected {
+				b.WriteString(indent + styles.Selected.Render("› "+line) + "\n")
+			} else {
+				b.WriteString(indent + styles.Help.Render("  "+line) + "\n")
+			}
+		}
+	}
+	if len(form.Models) > 0 {
+		modelLabel := styles.DetailLabel.Render("Model:")
+		if form.ModelFocused() {
+			modelLabel = styles.StatusKey.Render("▸ Model:")
+		}
+		b.WriteString(fmt.Sprintf("  %s  %s\n", modelLabel, renderModels(form)))
+	}
+	if form.Host == "" {
+		resumeLabel := styles.DetailLabel.Render("Resume:")
+		if form.ResumeFocused() {
+			resumeLabel = styles.StatusKey.Render("▸ Resume:")
+		}
+		b.WriteString(fmt.Sprintf("  %s  %s\n", resumeLabel, renderResume(form)))
+		if form.ResumeFocused() && form.ResumeDir != "" {
+			indent := strings.Repeat(" ", lipgloss.Width(resumeLabel)+4)
+			b.WriteString(renderResumable(form, indent, width-len(indent)-2))
+		}
+	}
 	b.WriteString("\n")
 
 	if form.Err != "" {
@@ -105,10 +291,82 @@ func RenderCreateForm(form CreateForm, width int) string {
 
 	b.WriteString(strings.Repeat("─", width))
 	b.WriteString("\n")
-	b.WriteString(styles.Help.Render("  Session will run: claude in the specified directory"))
+	run := "claude"
+	if args := form.ClaudeArgs(); args != "" {
+		run += " " + args
+	}
+	b.WriteString(styles.Help.Render(fmt.Sprintf("  Session will run: %s in the specified directory", run)))
 	b.WriteString("\n")
 	b.WriteString(styles.Help.Render(fmt.Sprintf("  tmux session name: cd-%s", form.NameInput.Value())))
 	b.WriteString("\n")
+	if form.Naming != "" {
+		b.WriteString(styles.Help.Render("  naming policy: " + form.Naming))
+		b.WriteString("\n")
+	}
 
 	return b.String()
 }
+
+// renderModels renders the model choices with the chosen one highlighted,
+// and how to change it while the field has focus.
+func renderModels(form CreateForm) string {
+	choices := append([]string{"default"}, form.Models...)
+	parts := make([]string, len(choices))
+	for i, c := range choices {
+		if i == form.Model+1 {
+			parts[i] = styles.Selected.Render(" " + c + " ")
+		} else {
+			parts[i] = styles.Help.Render(" " + c + " ")
+		}
+	}
+	out := strings.Join(parts, " ")
+	if form.ModelFocused() {
+		out += styles.Help.Render("  ←/→ to change")
+	}
+	return out
+}
+
+// renderResume renders the conversation chosen to resume.
+func renderResume(form CreateForm) string {
+	id := form.resumeID()
+	if id == "" {
+		out := "new conversation"
+		if form.ResumeFocused() && len(form.Resumable) > 0 {
+			out += styles.Help.Render("  ↑/↓ to resume one")
+		}
+		return out
+	}
+	return id
+}
+
+// renderResumable lists the conversations to resume around the chosen one,
+// at most MaxDirSuggestions, each with when it was last written and its
+// first prompt.
+func renderResumable(form CreateForm, indent string, width int) string {
+	var b strings.Builder
+	if len(form.Resumable) == 0 {
+		b.WriteString(indent + styles.Help.Render("  no conversations in this directory yet") + "\n")
+		return b.String()
+	}
+	if width < 12 {
+		width = 12
+	}
+	start := 0
+	if form.Resume >= MaxDirSuggestions {
+		start = form.Resume - MaxDirSuggestions + 1
+	}
+	for i := start; i < len(form.Resumable) && i < start+MaxDirSuggestions; i++ {
+		l := form.Resumable[i]
+		prompt := l.Preview
+		if prompt == "" {
+			prompt = "(no prompt)"
+		}
+		line := truncate(locale.Current().DateTime(l.Modified)+"  "+prompt, width)
+		if i == form.Resume {
+			b.WriteString(indent + styles.Selected.Render("› "+line) + "\n")
+		} else {
+			b.WriteString(indent + styles.Help.Render("  "+line) + "\n")
+		}
+	}
+	return b.String()
+}
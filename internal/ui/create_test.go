@@ -0,0 +1,177 @@
+package ui
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/seunggabi/claude-dashboard/internal/conversation"
+)
+
+// ---------------------------------------------------------------------------
+// CreateForm suggestions
+// ---------------------------------------------------------------------------
+
+func TestCreateForm_suggestionsFollowTypedDirectory(t *testing.T) {
+	f := NewCreateForm("/somewhere/else")
+	f.SetDirs([]string{"/src/web", "/src/api", "/src/apple"})
+	if len(f.Matches) != 3 {
+		t.Fatalf("expected every dir before editing, got %v", f.Matches)
+	}
+
+	f.DirInput.SetValue("api")
+	f.DirEdited()
+	if !reflect.DeepEqual(f.Matches, []string{"/src/api"}) {
+		t.Errorf("expected only /src/api, got %v", f.Matches)
+	}
+}
+
+func TestCreateForm_acceptHighlightedSuggestion(t *testing.T) {
+	f := NewCreateForm("")
+	f.SetDirs([]string{"/src/web", "/src/api"})
+	if f.AcceptSuggestion() {
+		t.Fatal("expected nothing accepted without a highlight")
+	}
+	f.MoveSelection(1)
+	f.MoveSelection(1)
+	if f.Selected != 1 {
+		t.Fatalf("expected the second suggestion highlighted, got %d", f.Selected)
+	}
+	f.MoveSelection(1) // wraps to none
+	f.MoveSelection(-1)
+	if !f.AcceptSuggestion() || f.DirInput.Value() != "/src/api" || f.Selected != -1 {
+		t.Errorf("expected /src/api accepted, got %q (selected %d)", f.DirInput.Value(), f.Selected)
+	}
+}
+
+func TestRenderCreateForm_showsSuggestionsForDirectoryField(t *testing.T) {
+	f := NewCreateForm("")
+	f.SetDirs([]string{"/src/api"})
+	if strings.Contains(RenderCreateForm(f, 100), "/src/api") {
+		t.Error("expected no suggestions while the name field has focus")
+	}
+	f.FocusNext()
+	if !strings.Contains(RenderCreateForm(f, 100), "/src/api") {
+		t.Error("expected suggestions under the directory field")
+	}
+}
+
+// ---------------------------------------------------------------------------
+// CreateForm model
+// ---------------------------------------------------------------------------
+
+func TestCreateForm_cycleModelThroughDefault(t *testing.T) {
+	f := NewCreateForm("")
+	f.Models = []string{"opus", "sonnet"}
+	if got := f.ClaudeArgs(); got != "" {
+		t.Fatalf("expected claude's default model, got %q", got)
+	}
+	f.CycleModel(1)
+	f.CycleModel(1)
+	if got := f.ClaudeArgs(); got != "--model sonnet" {
+		t.Errorf("expected sonnet, got %q", got)
+	}
+	f.CycleModel(1) // wraps to the default
+	if got := f.ClaudeArgs(); got != "" {
+		t.Errorf("expected the default again, got %q", got)
+	}
+	f.CycleModel(-1)
+	if got := f.ClaudeArgs(); got != "--model sonnet" {
+		t.Errorf("expected sonnet going back, got %q", got)
+	}
+}
+
+func TestCreateForm_focusReachesModelOnlyWithModels(t *testing.T) {
+	f := NewCreateForm("")
+	f.FocusNext()
+	f.FocusNext()
+	if f.ModelFocused() || !f.ResumeFocused() {
+		t.Errorf("expected the resume field after the directory without models, got %d", f.FocusIdx)
+	}
+	f.FocusNext()
+	if f.FocusIdx != 0 {
+		t.Errorf("expected focus back on the name, got %d", f.FocusIdx)
+	}
+
+	f.Models = []string{"opus"}
+	f.FocusNext()
+	f.FocusNext()
+	if !f.ModelFocused() || f.NameInput.Focused() || f.DirInput.Focused() {
+		t.Errorf("expected the model field focused, got %d", f.FocusIdx)
+	}
+	f.FocusNext()
+	if !f.ResumeFocused() {
+		t.Errorf("expected the resume field after the model, got %d", f.FocusIdx)
+	}
+}
+
+func TestRenderCreateForm_showsChosenModelInCommand(t *testing.T) {
+	f := NewCreateForm("")
+	f.Models = []string{"opus"}
+	f.CycleModel(1)
+	out := RenderCreateForm(f, 100)
+	if !strings.Contains(out, "Model:") || !strings.Contains(out, "claude --model opus") {
+		t.Errorf("expected the model row and command, got:\n%s", out)
+	}
+}
+
+// ---------------------------------------------------------------------------
+// CreateForm resume
+// ---------------------------------------------------------------------------
+
+func TestCreateForm_resumeChosenConversation(t *testing.T) {
+	f := NewCreateForm("/src/api")
+	f.Models = []string{"opus"}
+	f.CycleModel(1)
+	f.SetResumable("/src/api", []conversation.LogFile{
+		{Path: "/p/1111.jsonl", Preview: "fix the login bug"},
+		{Path: "/p/2222.jsonl", Preview: "add tests"},
+	})
+	if got := f.ClaudeArgs(); got != "--model opus" {
+		t.Fatalf("expected a new conversation by default, got %q", got)
+	}
+	f.MoveResume(1)
+	f.MoveResume(1)
+	if got := f.ClaudeArgs(); got != "--model opus --resume 2222" {
+		t.Errorf("expected the second conversation resumed, got %q", got)
+	}
+	f.MoveResume(1) // wraps to a new conversation
+	if got := f.ClaudeArgs(); got != "--model opus" {
+		t.Errorf("expected a new conversation again, got %q", got)
+	}
+}
+
+func TestCreateForm_resumeNotCarriedToAnotherDirectory(t *testing.T) {
+	f := NewCreateForm("/src/api")
+	f.SetResumable("/src/api", []conversation.LogFile{{Path: "/p/1111.jsonl"}})
+	f.MoveResume(1)
+	f.DirInput.SetValue("/src/web")
+	if got := f.ClaudeArgs(); got != "" {
+		t.Errorf("expected nothing resumed in another directory, got %q", got)
+	}
+	f.DirInput.SetValue("/src/api")
+	f.SetResumable("/src/api", []conversation.LogFile{{Path: "/p/0000.jsonl"}, {Path: "/p/1111.jsonl"}})
+	if got := f.ClaudeArgs(); got != "--resume 1111" {
+		t.Errorf("expected the chosen conversation kept when listed again, got %q", got)
+	}
+}
+
+func TestRenderCreateForm_listsResumableWhenFocused(t *testing.T) {
+	f := NewCreateForm("/src/api")
+	f.SetResumable("/src/api", []conversation.LogFile{{Path: "/p/1111.jsonl", Preview: "fix the login bug"}})
+	if strings.Contains(RenderCreateForm(f, 100), "fix the login bug") {
+		t.Error("expected no conversations while the name field has focus")
+	}
+	f.FocusNext()
+	f.FocusNext()
+	f.MoveResume(1)
+	out := RenderCreateForm(f, 100)
+	if !strings.Contains(out, "fix the login bug") || !strings.Contains(out, "claude --resume 1111") {
+		t.Errorf("expected the conversation listed and resumed, got:\n%s", out)
+	}
+
+	f.Host = "devbox"
+	if strings.Contains(RenderCreateForm(f, 100), "Resume:") {
+		t.Error("expected no resume field for remote sessions")
+	}
+}
@@ -2,62 +2,60 @@ package ui
 
 import (
 	"fmt"
+	"os"
 	"strings"
 
 	"github.com/charmbracelet/lipgloss"
+	"github.com/seunggabi/claude-dashboard/internal/config"
 	"github.com/seunggabi/claude-dashboard/internal/session"
 	"github.com/seunggabi/claude-dashboard/internal/styles"
 )
 
-// DashboardColumns defines the table column widths.
-// Width 0 means flexible (calculated dynamically).
-var DashboardColumns = []struct {
-	Title string
-	Width int
-}{
-	{"#", 4},
-	{"NAME", 0},    // flexible width
-	{"PROJECT", 35},
-	{"STATUS", 12},
-	{"UPTIME", 10},
-	{"CPU", 8},
-	{"MEM", 8},
-	{"PATH", 0}, // flexible width
+// DashboardOptions controls configurable parts of the session table.
+type DashboardOptions struct {
+	ShowHost   bool            // add a HOST column after NAME
+	ShowBranch bool            // add a BRANCH column after PROJECT
+	ShowIssues bool            // add an ISSUE column after BRANCH
+	ShowTests  bool            // add a TEST column after STATUS
+	PathStyle  string          // a config.PathStyle* value
+	Icons      session.IconSet // status glyphs; zero for the default set
+	Density    string          // a config.Density* value; empty for compact
+	Query      string          // active filter; a window it matched is shown by the name
+	Loading    bool            // sessions are not listed yet; draw placeholder rows
+	Columns    []string        // the columns setting; empty for the default columns
+}
+
+// RowHeight returns how many lines one session takes at a row density.
+func RowHeight(density string) int {
+	switch density {
+	case config.DensityComfortable, config.DensityDetailed:
+		return 2
+	}
+	return 1
 }
 
 // RenderDashboard renders the session table with scroll support.
-func RenderDashboard(sessions []session.Session, cursor int, width int, scrollOffset int, visibleRows int) string {
+func RenderDashboard(sessions []session.Session, cursor int, width int, scrollOffset int, visibleRows int, opts DashboardOptions) string {
 	var b strings.Builder
-
-	// Calculate flexible column widths
-	fixedWidth := 2 // left margin
-	for _, col := range DashboardColumns {
-		if col.Width > 0 {
-			fixedWidth += col.Width + 2
-		}
-	}
-	flexWidth := width - fixedWidth
-	if flexWidth < 30 {
-		flexWidth = 30
+	home, _ := os.UserHomeDir()
+	icons := opts.Icons
+	if icons == (session.IconSet{}) {
+		icons = session.Icons("")
 	}
-	nameWidth := flexWidth / 3
-	pathWidth := flexWidth - nameWidth
+	cols := layoutColumns(opts, width)
 
 	// Header
-	header := renderRow(
-		DashboardColumns[0].Title,
-		DashboardColumns[1].Title,
-		DashboardColumns[2].Title,
-		DashboardColumns[3].Title,
-		DashboardColumns[4].Title,
-		DashboardColumns[5].Title,
-		DashboardColumns[6].Title,
-		DashboardColumns[7].Title,
-		nameWidth, pathWidth,
-	)
-	b.WriteString(styles.Header.Render(header))
+	titles := make([]string, len(cols))
+	for i, c := range cols {
+		titles[i] = c.Title
+	}
+	b.WriteString(styles.Header.Render(renderCells("#", cols, titles)))
 	b.WriteString("\n")
 
+	if len(sessions) == 0 && opts.Loading {
+		b.WriteString(renderSkeleton(min(visibleRows, skeletonRows), cols))
+		return b.String()
+	}
 	if len(sessions) == 0 {
 		b.WriteString("\n")
 		b.WriteString(styles.Muted.Render("  No sessions found. Press 'n' to create a new session."))
@@ -81,31 +79,45 @@ func RenderDashboard(sessions []session.Session, cursor int, width int, scrollOf
 	// Rows (only visible range)
 	for i := scrollOffset; i < end; i++ {
 		s := sessions[i]
-		row := renderRow(
-			fmt.Sprintf("%d", i+1),
-			truncate(s.Name, nameWidth),
-			truncate(s.Project, DashboardColumns[2].Width),
-			s.StatusString(),
-			s.Uptime(),
-			fmt.Sprintf("%.1f%%", s.CPU),
-			fmt.Sprintf("%.1f%%", s.Memory),
-			truncatePath(s.Path, pathWidth),
-			nameWidth, pathWidth,
-		)
+		values := make([]string, len(cols))
+		for j, c := range cols {
+			values[j] = c.cell(s, cellContext{home: home, opts: opts, icons: icons, width: c.width - 2})
+		}
+		row := renderCells(fmt.Sprintf("%d", i+1), cols, values)
 
 		if i == cursor {
 			b.WriteString(styles.Selected.Width(width).Render(row))
 		} else {
+			base, styled := lipgloss.NewStyle(), true
 			switch s.Status {
 			case session.StatusActive:
-				b.WriteString(styles.Active.Render(row))
+				base = styles.Active
 			case session.StatusWaiting:
-				b.WriteString(styles.Waiting.Render(row))
+				base = styles.Waiting
+			case session.StatusExited:
+				base = styles.Muted
+			case session.StatusCrashed:
+				base = styles.Error
 			default:
+				styled = false
+			}
+			if toned, ok := renderTonedRow(s, fmt.Sprintf("%d", i+1), cols, values, base); ok {
+				b.WriteString(toned)
+			} else if styled {
+				b.WriteString(base.Render(row))
+			} else {
 				b.WriteString(row)
 			}
 		}
 		b.WriteString("\n")
+
+		switch opts.Density {
+		case config.DensityComfortable:
+			b.WriteString("\n")
+		case config.DensityDetailed:
+			b.WriteString(renderPromptLine(s, i == cursor, width))
+			b.WriteString("\n")
+		}
 	}
 
 	// Scroll indicator (bottom)
@@ -118,9 +130,42 @@ func RenderDashboard(sessions []session.Session, cursor int, width int, scrollOf
 	return b.String()
 }
 
-func renderRow(idx, name, project, status, uptime, cpu, mem, path string, nameWidth, pathWidth int) string {
-	return fmt.Sprintf("  %-4s%-*s  %-35s%-12s%-10s%-8s%-8s%-*s",
-		idx, nameWidth, name, project, status, uptime, cpu, mem, pathWidth, path)
+// skeletonRows is how many placeholder rows stand in for the sessions
+// while they are detected.
+const skeletonRows = 3
+
+// renderSkeleton renders placeholder rows shaped like the table, so the
+// first frame shows the layout before any session is listed.
+func renderSkeleton(rows int, cols []tableColumn) string {
+	bars := make([]string, len(cols))
+	for i, c := range cols {
+		bars[i] = strings.Repeat("░", max(c.width-2, 1))
+	}
+	var b strings.Builder
+	for i := 0; i < rows; i++ {
+		b.WriteString(styles.Muted.Render(renderCells("░", cols, bars)))
+		b.WriteString("\n")
+	}
+	b.WriteString(styles.Muted.Render("  Detecting sessions…"))
+	b.WriteString("\n")
+	return b.String()
+}
+
+// renderPromptLine renders the second line of a detailed row: the last
+// prompt, indented under the NAME column.
+func renderPromptLine(s session.Session, selected bool, width int) string {
+	prompt := strings.Join(strings.Fields(s.LastPrompt), " ")
+	switch {
+	case s.Host != "":
+		prompt = "(prompts of remote sessions are not read)"
+	case prompt == "":
+		prompt = "(no prompt yet)"
+	}
+	line := "      └ " + truncate(prompt, width-8)
+	if selected {
+		return styles.Selected.Width(width).Render(line)
+	}
+	return styles.Muted.Render(line)
 }
 
 func truncate(s string, maxLen int) string {
@@ -140,16 +185,3 @@ func truncate(s string, maxLen int) string {
 	}
 	return string(runes[:maxLen-3]) + "..."
 }
-
-func truncatePath(s string, maxLen int) string {
-	if maxLen <= 0 || lipgloss.Width(s) <= maxLen {
-		return s
-	}
-	if maxLen <= 3 {
-		return "..."
-	}
-	if len(s) > maxLen {
-		return "..." + s[len(s)-(maxLen-3):]
-	}
-	return s
-}
@@ -3,6 +3,15 @@ package ui
 import (
 	"strings"
 	"testing"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/seunggabi/claude-dashboard/internal/config"
+	"github.com/seunggabi/claude-dashboard/internal/git"
+	"github.com/seunggabi/claude-dashboard/internal/session"
+	"github.com/seunggabi/claude-dashboard/internal/styles"
+	"github.com/seunggabi/claude-dashboard/internal/testrun"
 )
 
 // ---------------------------------------------------------------------------
@@ -64,78 +73,78 @@ func TestTruncate_ellipsisIsAddedForMaxLenFour(t *testing.T) {
 }
 
 // ---------------------------------------------------------------------------
-// truncatePath
+// FormatPath
 // ---------------------------------------------------------------------------
 
-func TestTruncatePath_shortPathIsUnchanged(t *testing.T) {
-	got := truncatePath("/a/b", 20)
+func TestFormatPath_shortPathIsUnchanged(t *testing.T) {
+	got := FormatPath("/a/b", "", config.PathStyleFull, 20)
 	if got != "/a/b" {
 		t.Errorf("expected %q, got %q", "/a/b", got)
 	}
 }
 
-func TestTruncatePath_exactLengthIsUnchanged(t *testing.T) {
-	s := "/a/b/c"
-	got := truncatePath(s, len(s))
-	if got != s {
-		t.Errorf("expected %q, got %q", s, got)
+func TestFormatPath_maxLenZeroReturnsOriginal(t *testing.T) {
+	got := FormatPath("/a/b/c", "", config.PathStyleFull, 0)
+	if got != "/a/b/c" {
+		t.Errorf("expected original path when maxLen=0, got %q", got)
 	}
 }
 
-func TestTruncatePath_longPathTruncatesFromLeft(t *testing.T) {
-	got := truncatePath("/very/long/path/to/some/directory", 15)
-	if !strings.HasPrefix(got, "...") {
-		t.Errorf("expected truncated path to start with '...', got %q", got)
+func TestFormatPath_emptyStringIsUnchanged(t *testing.T) {
+	if got := FormatPath("", "/home/me", config.PathStyleHome, 10); got != "" {
+		t.Errorf("expected empty string, got %q", got)
 	}
 }
 
-func TestTruncatePath_maxLenZeroReturnsOriginal(t *testing.T) {
-	got := truncatePath("/a/b/c", 0)
-	if got != "/a/b/c" {
-		t.Errorf("expected original path when maxLen=0, got %q", got)
+func TestFormatPath_homeStyleReplacesHomeDir(t *testing.T) {
+	got := FormatPath("/home/me/work/api", "/home/me", config.PathStyleHome, 40)
+	if got != "~/work/api" {
+		t.Errorf("expected %q, got %q", "~/work/api", got)
 	}
 }
 
-func TestTruncatePath_maxLenNegativeReturnsOriginal(t *testing.T) {
-	got := truncatePath("/a/b/c", -1)
-	if got != "/a/b/c" {
-		t.Errorf("expected original path when maxLen<0, got %q", got)
+func TestFormatPath_homeStyleIgnoresSiblingWithSamePrefix(t *testing.T) {
+	got := FormatPath("/home/meg/api", "/home/me", config.PathStyleHome, 40)
+	if got != "/home/meg/api" {
+		t.Errorf("expected path unchanged, got %q", got)
 	}
 }
 
-func TestTruncatePath_maxLenThreeReturnsEllipsis(t *testing.T) {
-	got := truncatePath("/some/very/long/path", 3)
-	if got != "..." {
-		t.Errorf("expected %q, got %q", "...", got)
+func TestFormatPath_basenameStyleShowsLastComponent(t *testing.T) {
+	if got := FormatPath("/home/me/work/api/", "/home/me", config.PathStyleBase, 40); got != "api" {
+		t.Errorf("expected %q, got %q", "api", got)
+	}
+	if got := FormatPath(`C:\Users\me\api`, "", config.PathStyleBase, 40); got != "api" {
+		t.Errorf("expected %q for a Windows path, got %q", "api", got)
 	}
 }
 
-func TestTruncatePath_maxLenOneReturnsEllipsis(t *testing.T) {
-	got := truncatePath("/some/path", 1)
-	// maxLen <= 3, so return "..."
-	if got != "..." {
-		t.Errorf("expected %q, got %q", "...", got)
+func TestFormatPath_longPathKeepsFirstAndLastComponents(t *testing.T) {
+	got := FormatPath("/home/me/work/company/platform/services/api", "/home/me", config.PathStyleHome, 24)
+	if got != "~/work/…/services/api" {
+		t.Errorf("expected %q, got %q", "~/work/…/services/api", got)
 	}
 }
 
-func TestTruncatePath_emptyStringIsUnchanged(t *testing.T) {
-	got := truncatePath("", 10)
-	if got != "" {
-		t.Errorf("expected empty string, got %q", got)
+func TestFormatPath_truncatedLengthRespectsBound(t *testing.T) {
+	s := "/home/user/projects/myapp/src/components/button.go"
+	for _, maxLen := range []int{1, 5, 12, 20} {
+		got := FormatPath(s, "", config.PathStyleFull, maxLen)
+		if w := lipgloss.Width(got); w > maxLen {
+			t.Errorf("maxLen %d: got width %d (%q)", maxLen, w, got)
+		}
 	}
 }
 
-func TestTruncatePath_truncatedLengthRespectsBound(t *testing.T) {
-	s := "/home/user/projects/myapp/src/components/button.go"
-	maxLen := 20
-	got := truncatePath(s, maxLen)
-	if len(got) > maxLen {
-		t.Errorf("expected length <= %d, got %d (%q)", maxLen, len(got), got)
+func TestFormatPath_lastComponentTooLongIsCutFromLeft(t *testing.T) {
+	got := FormatPath("/a/very-long-directory-name", "", config.PathStyleFull, 10)
+	if got != "…tory-name" {
+		t.Errorf("expected %q, got %q", "…tory-name", got)
 	}
 }
 
 // ---------------------------------------------------------------------------
-// Table-driven tests combining truncate and truncatePath
+// Table-driven truncate tests
 // ---------------------------------------------------------------------------
 
 func TestTruncateTableDriven(t *testing.T) {
@@ -162,26 +171,140 @@ func TestTruncateTableDriven(t *testing.T) {
 	}
 }
 
-func TestTruncatePathTableDriven(t *testing.T) {
-	cases := []struct {
-		name   string
-		input  string
-		maxLen int
-		check  func(string) bool
-		desc   string
-	}{
-		{"zero maxLen returns original", "/a/b", 0, func(s string) bool { return s == "/a/b" }, "should equal original"},
-		{"short path unchanged", "/a/b", 10, func(s string) bool { return s == "/a/b" }, "should be unchanged"},
-		{"long path starts with ...", "/a/b/c/d/e/f/g", 8, func(s string) bool { return strings.HasPrefix(s, "...") }, "should start with ..."},
-		{"max 3 returns ...", "/long/path", 3, func(s string) bool { return s == "..." }, "should be exactly ..."},
+// ---------------------------------------------------------------------------
+// RenderDashboard host column
+// ---------------------------------------------------------------------------
+
+func TestRenderDashboard_hostColumnOnlyWhenEnabled(t *testing.T) {
+	sessions := []session.Session{{Name: "cd-api", Host: "devbox"}}
+
+	without := RenderDashboard(sessions, 0, 160, 0, 10, DashboardOptions{})
+	if strings.Contains(without, "HOST") {
+		t.Error("expected no HOST header when showHost is false")
 	}
-	for _, tc := range cases {
-		tc := tc
-		t.Run(tc.name, func(t *testing.T) {
-			got := truncatePath(tc.input, tc.maxLen)
-			if !tc.check(got) {
-				t.Errorf("truncatePath(%q, %d): %s, got %q", tc.input, tc.maxLen, tc.desc, got)
+
+	with := RenderDashboard(sessions, 0, 160, 0, 10, DashboardOptions{ShowHost: true})
+	if !strings.Contains(with, "HOST") || !strings.Contains(with, "devbox") {
+		t.Errorf("expected HOST header and host name, got %q", with)
+	}
+}
+
+func TestRenderDashboard_branchColumnOnlyWhenEnabled(t *testing.T) {
+	sessions := []session.Session{{Name: "cd-api", Git: git.Status{Branch: "feature", Changed: 2}}}
+
+	without := RenderDashboard(sessions, 0, 160, 0, 10, DashboardOptions{})
+	if strings.Contains(without, "BRANCH") {
+		t.Error("expected no BRANCH header when showBranch is false")
+	}
+
+	with := RenderDashboard(sessions, 0, 160, 0, 10, DashboardOptions{ShowBranch: true})
+	if !strings.Contains(with, "BRANCH") || !strings.Contains(with, "feature*") {
+		t.Errorf("expected BRANCH header and dirty branch, got %q", with)
+	}
+}
+
+func TestRenderDashboard_testColumnOnlyWhenEnabled(t *testing.T) {
+	sessions := []session.Session{
+		{Name: "cd-api", Test: testrun.Result{Passed: true, Duration: 1200 * time.Millisecond, Source: testrun.SourceRun}},
+		{Name: "cd-web", Test: testrun.Result{Source: testrun.SourcePane}},
+		{Name: "cd-gpu"},
+	}
+
+	without := RenderDashboard(sessions, 0, 160, 0, 10, DashboardOptions{})
+	if strings.Contains(without, "TEST") {
+		t.Error("expected no TEST header when showTests is false")
+	}
+
+	lines := strings.Split(RenderDashboard(sessions, 0, 160, 0, 10, DashboardOptions{ShowTests: true}), "\n")
+	if !strings.Contains(lines[0], "STATUS      TEST") || !strings.Contains(lines[1], "✓ 1.2s") || !strings.Contains(lines[2], "✗ fail") {
+		t.Errorf("expected TEST header and badges, got %q", lines)
+	}
+	if strings.ContainsAny(lines[3], "✓✗") {
+		t.Errorf("expected an empty badge without a result, got %q", lines[3])
+	}
+}
+
+// ---------------------------------------------------------------------------
+// RenderDashboard status
+// ---------------------------------------------------------------------------
+
+func TestRenderDashboard_statusIsNotColorOnly(t *testing.T) {
+	defer styles.Apply(styles.Themes[styles.DefaultTheme])
+	statuses := []session.Status{session.StatusActive, session.StatusIdle, session.StatusWaiting, session.StatusExited, session.StatusCrashed, session.StatusTerminal, session.StatusUnknown}
+	var sessions []session.Session
+	for _, st := range statuses {
+		sessions = append(sessions, session.Session{Name: "cd-" + string(st), Status: st})
+	}
+	for name := range styles.Themes {
+		styles.Apply(styles.Themes[name])
+		for _, icons := range []string{config.IconsUnicode, config.IconsNerd, config.IconsASCII} {
+			set := session.Icons(icons)
+			lines := strings.Split(ansi.Strip(RenderDashboard(sessions, 0, 160, 0, 10, DashboardOptions{Icons: set})), "\n")
+			glyphs := map[string]bool{}
+			for i, st := range statuses {
+				row := lines[i+1] // after the header
+				label := sessions[i].StatusLabel(set)
+				if !strings.Contains(row, label) || !strings.HasSuffix(label, " "+string(st)) {
+					t.Errorf("%s/%s: expected %q in the row, got %q", name, icons, label, row)
+				}
+				glyphs[strings.TrimSuffix(label, " "+string(st))] = true
 			}
-		})
+			if len(glyphs) != len(statuses) {
+				t.Errorf("%s: expected a glyph per status, got %v", icons, glyphs)
+			}
+		}
+	}
+}
+
+// ---------------------------------------------------------------------------
+// RenderDashboard density
+// ---------------------------------------------------------------------------
+
+func TestRenderDashboard_showsTheWindowTheFilterMatched(t *testing.T) {
+	sessions := []session.Session{{Name: "cd-api", Windows: []session.Window{{Index: 1, Name: "frontend"}}}}
+
+	if out := RenderDashboard(sessions, 0, 160, 0, 10, DashboardOptions{Query: "front"}); !strings.Contains(out, "cd-api ▸ 1:frontend") {
+		t.Errorf("expected the matched window by the name, got %q", out)
+	}
+	if out := RenderDashboard(sessions, 0, 160, 0, 10, DashboardOptions{}); strings.Contains(out, "frontend") {
+		t.Errorf("expected no window without a filter, got %q", out)
+	}
+}
+
+func TestRenderDashboard_skeletonWhileLoading(t *testing.T) {
+	out := RenderDashboard(nil, 0, 160, 0, 10, DashboardOptions{Loading: true})
+	if !strings.Contains(out, "Detecting sessions") || strings.Count(out, "\n") != 1+skeletonRows+1 {
+		t.Errorf("expected header, placeholder rows and a note, got %q", out)
+	}
+	if strings.Contains(out, "No sessions found") {
+		t.Errorf("expected no empty-state message while loading, got %q", out)
+	}
+}
+
+func TestRenderDashboard_marksConflictingSessions(t *testing.T) {
+	sessions := []session.Session{
+		{Name: "cd-api", Conflict: session.Conflict{With: []string{"cd-web"}, Files: []string{"go.mod"}}},
+		{Name: "cd-web"},
+	}
+	lines := strings.Split(RenderDashboard(sessions, 0, 160, 0, 10, DashboardOptions{}), "\n")
+	if !strings.Contains(lines[1], "⚠ cd-api") || strings.Contains(lines[2], "⚠") {
+		t.Errorf("expected only cd-api marked, got %q", lines)
+	}
+}
+
+func TestRenderDashboard_detailedRowsShowLastPrompt(t *testing.T) {
+	sessions := []session.Session{{Name: "cd-api", LastPrompt: "fix the\nflaky test"}}
+	out := RenderDashboard(sessions, 1, 160, 0, 10, DashboardOptions{Density: config.DensityDetailed})
+	if !strings.Contains(out, "└ fix the flaky test") {
+		t.Errorf("expected the prompt on one line under the row, got:\n%s", out)
+	}
+}
+
+func TestRenderDashboard_comfortableRowsAreSpaced(t *testing.T) {
+	sessions := []session.Session{{Name: "cd-a"}, {Name: "cd-b"}}
+	compact := RenderDashboard(sessions, 0, 160, 0, 10, DashboardOptions{})
+	comfortable := RenderDashboard(sessions, 0, 160, 0, 10, DashboardOptions{Density: config.DensityComfortable})
+	if got, want := strings.Count(comfortable, "\n"), strings.Count(compact, "\n")+2; got != want {
+		t.Errorf("expected %d lines, got %d", want, got)
 	}
 }
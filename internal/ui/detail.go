@@ -2,14 +2,30 @@ package ui
 
 import (
 	"fmt"
+	"math"
 	"strings"
+	"time"
 
+	"github.com/charmbracelet/lipgloss"
+	"github.com/seunggabi/claude-dashboard/internal/conversation"
+	"github.com/seunggabi/claude-dashboard/internal/filefeed"
+	"github.com/seunggabi/claude-dashboard/internal/git"
+	"github.com/seunggabi/claude-dashboard/internal/locale"
+	"github.com/seunggabi/claude-dashboard/internal/monitor"
 	"github.com/seunggabi/claude-dashboard/internal/session"
 	"github.com/seunggabi/claude-dashboard/internal/styles"
+	"github.com/seunggabi/claude-dashboard/internal/testrun"
+	"github.com/seunggabi/claude-dashboard/internal/timesheet"
 )
 
-// RenderDetail renders the session detail view.
-func RenderDetail(s *session.Session, width int) string {
+// DetailWindows are the time spans the detail view's CPU and memory graphs
+// can show, cycled with w.
+var DetailWindows = []time.Duration{5 * time.Minute, 15 * time.Minute, 30 * time.Minute}
+
+// RenderDetail renders the session detail view: metadata, CPU and memory
+// graphs of the samples over the last window, and the session's recent
+// tool calls. tools is nil when the conversation log is unavailable.
+func RenderDetail(s *session.Session, icons session.IconSet, tools []conversation.ToolEvent, files []filefeed.Change, forecast session.Forecast, times timesheet.Totals, samples []monitor.Sample, window time.Duration, now time.Time, width, height int) string {
 	if s == nil {
 		return styles.Error.Render("  No session selected")
 	}
@@ -27,15 +43,26 @@ func RenderDetail(s *session.Session, width int) string {
 		value string
 	}{
 		{"Name", s.Name},
+		{"Host", s.HostName()},
 		{"Project", s.Project},
-		{"Status", s.StatusString()},
+		{"Tags", tagsLabel(s.Tags)},
+		{"Issue", issueLabel(s.Issue)},
+		{"Status", s.StatusLabel(icons)},
+		{"Waiting on", questionLabel(s.Question)},
+		{"Forecast", forecastLabel(forecast)},
 		{"Uptime", s.Uptime()},
 		{"PID", s.PID},
-		{"CPU", fmt.Sprintf("%.1f%%", s.CPU)},
-		{"Memory", fmt.Sprintf("%.1f%%", s.Memory)},
+		{"CPU", locale.Current().Percent(s.CPU)},
+		{"Memory", locale.Current().Percent(s.Memory)},
 		{"Path", s.Path},
+		{"Branch", gitLabel(s.Git)},
+		{"Tests", testLabel(s.Test)},
+		{"Conflicts", conflictLabel(s.Conflict)},
+		{"Windows", windowsLabel(s.Windows)},
 		{"Attached", fmt.Sprintf("%v", s.Attached)},
-		{"Started", s.StartedAt.Format("2006-01-02 15:04:05")},
+		{"Time today", timesLabel(times)},
+		{"Started", locale.Current().DateTime(s.StartedAt)},
+		{"Spend", formatSpend(s.Spend)},
 	}
 
 	for _, row := range rows {
@@ -44,11 +71,280 @@ func RenderDetail(s *session.Session, width int) string {
 		b.WriteString(fmt.Sprintf("  %s %s\n", label, value))
 	}
 
+	b.WriteString("\n")
+	writeResources(&b, s, samples, window, now, width)
+	b.WriteString("\n")
+	if len(files) > detailFileLimit {
+		files = files[:detailFileLimit]
+	}
+	writeFileChanges(&b, files, now, width)
+	b.WriteString("\n")
+	writeToolTimeline(&b, tools, now, width, height-detailToolRows-len(files))
+
 	b.WriteString("\n")
 	b.WriteString(strings.Repeat("─", width))
 	b.WriteString("\n")
-	b.WriteString(styles.Help.Render("  Press 'l' for logs, 'K' to kill, 'esc' to go back"))
+	b.WriteString(styles.Help.Render("  Press 'l' for logs, 'w' to change the graph span, 'K' to kill, 'esc' to go back"))
 	b.WriteString("\n")
 
 	return b.String()
 }
+
+// detailToolRows is how many lines the detail view uses besides the tool
+// timeline and file change entries: title, rules, metadata rows, graphs,
+// section headers and help.
+const detailToolRows = 3 + 20 + 2 + detailGraphRows + 2 + 3
+
+// detailGraphHeight is how many rows tall the CPU and memory graphs are.
+const detailGraphHeight = 3
+
+// detailGraphRows is how many lines the graphs take with their headers and
+// the blank line after them.
+const detailGraphRows = 1 + 2*(1+detailGraphHeight) + 1
+
+// writeResources graphs the CPU and memory of s over the last window from
+// samples, so a runaway process shows as a climb rather than one reading.
+// Remote sessions are not sampled.
+func writeResources(b *strings.Builder, s *session.Session, samples []monitor.Sample, window time.Duration, now time.Time, width int) {
+	b.WriteString("  " + styles.Header.Render("RESOURCES"))
+	if s.Host != "" {
+		b.WriteString("  " + styles.Muted.Render("not sampled for remote sessions") + "\n")
+		return
+	}
+	b.WriteString("  " + styles.Muted.Render("last "+formatWindow(window)) + "\n")
+
+	start := now.Add(-window)
+	cpu := make([]ChartPoint, len(samples))
+	mem := make([]ChartPoint, len(samples))
+	for i, sample := range samples {
+		cpu[i] = ChartPoint{Time: sample.Time, Value: sample.CPU}
+		mem[i] = ChartPoint{Time: sample.Time, Value: sample.Memory}
+	}
+	cols := max(width-chartLabelWidth-2, 10)
+	percent := locale.Current().Percent
+	b.WriteString("  ")
+	writePanel(b, "CPU", bucketMax(cpu, start, now, cols), detailGraphHeight, 10, styles.ColorSecondary, percent)
+	b.WriteString("\n  ")
+	writePanel(b, "MEM", bucketMax(mem, start, now, cols), detailGraphHeight, 1, styles.ColorPrimary, percent)
+	b.WriteString("\n")
+}
+
+// detailFileLimit is how many recent file changes the detail view shows.
+const detailFileLimit = 5
+
+// writeFileChanges writes the latest file changes, newest first. files is
+// nil when the directory is not watched.
+func writeFileChanges(b *strings.Builder, files []filefeed.Change, now time.Time, width int) {
+	b.WriteString("  " + styles.Header.Render("FILE CHANGES"))
+	switch {
+	case files == nil:
+		b.WriteString("  " + styles.Muted.Render("not watched (remote or no directory)"))
+	case len(files) == 0:
+		b.WriteString("  " + styles.Muted.Render("none since the dashboard started"))
+	}
+	b.WriteString("\n")
+
+	pathWidth := width - 2 - 10 - 10
+	for _, c := range files {
+		op := string(c.Op)
+		if c.Op == filefeed.Deleted || c.Op == filefeed.Renamed {
+			op = styles.Error.Render(fmt.Sprintf("%-8s", op))
+		} else {
+			op = fmt.Sprintf("%-8s", op)
+		}
+		b.WriteString(fmt.Sprintf("  %s  %-*s%s\n",
+			op,
+			pathWidth, truncate(c.Path, pathWidth-2),
+			styles.Muted.Render(agoLabel(now.Sub(c.At)))))
+	}
+}
+
+// agoLabel says how long ago something happened, e.g. "2m ago".
+func agoLabel(d time.Duration) string {
+	switch {
+	case d < time.Minute:
+		return fmt.Sprintf("%ds ago", max(int(d.Seconds()), 0))
+	case d < time.Hour:
+		return fmt.Sprintf("%dm ago", int(d.Minutes()))
+	case d < 24*time.Hour:
+		return fmt.Sprintf("%dh ago", int(d.Hours()))
+	}
+	return fmt.Sprintf("%dd ago", int(d.Hours())/24)
+}
+
+// writeToolTimeline writes the most recent tool calls that fit in rows
+// lines, oldest first.
+func writeToolTimeline(b *strings.Builder, tools []conversation.ToolEvent, now time.Time, width, rows int) {
+	b.WriteString("  " + styles.Header.Render("TOOL TIMELINE"))
+	switch {
+	case tools == nil:
+		b.WriteString("  " + styles.Muted.Render("no conversation log for this session"))
+	case len(tools) == 0:
+		b.WriteString("  " + styles.Muted.Render("no tool calls yet"))
+	}
+	b.WriteString("\n")
+	if rows < 1 {
+		rows = 1
+	}
+	if len(tools) > rows {
+		tools = tools[len(tools)-rows:]
+	}
+
+	ok := lipgloss.NewStyle().Foreground(styles.ColorSuccess)
+	// What the columns before the summary leave; nothing on narrow screens.
+	summaryWidth := max(width-2-10-14-9-4, 0)
+	for _, t := range tools {
+		var mark string
+		switch {
+		case !t.Done():
+			mark = styles.Waiting.Render("…")
+		case t.IsError:
+			mark = styles.Error.Render("✗")
+		default:
+			mark = ok.Render("✓")
+		}
+		b.WriteString(fmt.Sprintf("  %s  %-14s%8s  %s  %s\n",
+			styles.Muted.Render(t.Start.Local().Format("15:04:05")),
+			truncate(t.Name, 13),
+			formatToolDuration(t.Duration(now)),
+			mark,
+			styles.Muted.Render(truncate(t.Summary, summaryWidth))))
+	}
+}
+
+// gitLabel describes the git state of a session's directory.
+func gitLabel(s git.Status) string {
+	if s.IsZero() {
+		return "-"
+	}
+	return s.String()
+}
+
+// testLabel describes the latest test result with when it was seen.
+func testLabel(r testrun.Result) string {
+	if r.IsZero() {
+		return "-"
+	}
+	return r.String() + ", " + locale.Current().DateTime(r.At)
+}
+
+// forecastLabel turns a forecast into a hint on whether to wait, e.g.
+// "↘ declining (1.0k → 200 tokens/min), likely finishing in ~2m".
+func forecastLabel(f session.Forecast) string {
+	rates := func() string {
+		return fmt.Sprintf("(%s → %s tokens/min)", conversation.FormatTokens(int(f.Before)), conversation.FormatTokens(int(f.Recent)))
+	}
+	switch f.Trend {
+	case session.TrendRising:
+		return "↗ rising " + rates() + ", likely far from done"
+	case session.TrendSteady:
+		return fmt.Sprintf("→ steady at %s tokens/min, still working", conversation.FormatTokens(int(f.Recent)))
+	case session.TrendDeclining:
+		return "↘ declining " + rates() + ", likely finishing " + etaLabel(f.ETA)
+	case session.TrendQuiet:
+		return "· no output lately " + rates() + ", wrapping up or thinking"
+	case session.TrendBusy:
+		return fmt.Sprintf("⚙ no output but CPU at %s, likely a long tool run", locale.Current().Percent(f.CPU))
+	case session.TrendBlocked:
+		return "waiting for input; nothing moves until it is answered"
+	case session.TrendDone:
+		return "finished; nothing to wait for"
+	}
+	return "-"
+}
+
+// etaLabel rounds a rough time left up to whole minutes.
+func etaLabel(d time.Duration) string {
+	if d < time.Minute {
+		return "within a minute"
+	}
+	return fmt.Sprintf("in ~%dm", int(math.Ceil(d.Minutes())))
+}
+
+// conflictLabel names the sessions that wrote the same uncommitted files
+// and the first few of those files.
+func conflictLabel(c session.Conflict) string {
+	if c.IsZero() {
+		return "-"
+	}
+	files := c.Files
+	more := ""
+	if len(files) > conflictFileLimit {
+		files, more = files[:conflictFileLimit], fmt.Sprintf(" +%d more", len(c.Files)-conflictFileLimit)
+	}
+	return fmt.Sprintf("⚠ with %s: %s%s", strings.Join(c.With, ", "), strings.Join(files, ", "), more)
+}
+
+// conflictFileLimit is how many conflicting files the detail view names.
+const conflictFileLimit = 5
+
+// timesLabel describes the time recorded for a session, e.g. "attached
+// 12m · active 1h5m".
+func timesLabel(t timesheet.Totals) string {
+	if t.IsZero() {
+		return "-"
+	}
+	return t.String()
+}
+
+// tagsLabel lists tags, e.g. "api, urgent".
+func tagsLabel(tags []string) string {
+	if len(tags) == 0 {
+		return "-"
+	}
+	return strings.Join(tags, ", ")
+}
+
+// issueLabel is the issue key, or "-" without one.
+func issueLabel(key string) string {
+	if key == "" {
+		return "-"
+	}
+	return key
+}
+
+// questionLabel is what a waiting session asks, or "-".
+func questionLabel(question string) string {
+	if question == "" {
+		return "-"
+	}
+	return question
+}
+
+// windowsLabel lists windows as tmux shows them, e.g. "0:claude, 1:api".
+func windowsLabel(windows []session.Window) string {
+	if len(windows) == 0 {
+		return "-"
+	}
+	names := make([]string, len(windows))
+	for i, w := range windows {
+		names[i] = w.String()
+	}
+	return strings.Join(names, ", ")
+}
+
+// formatSpend describes the spend of a session's conversation, which is
+// only measured while a spend cap is set.
+func formatSpend(s conversation.Spend) string {
+	if s == (conversation.Spend{}) {
+		return "-"
+	}
+	if s.Unpriced > 0 {
+		return fmt.Sprintf("%s (%d messages of unpriced models)", s, s.Unpriced)
+	}
+	return s.String()
+}
+
+// formatToolDuration formats a tool call duration, e.g. "0.4s", "12s" or
+// "3m05s".
+func formatToolDuration(d time.Duration) string {
+	switch {
+	case d < 0:
+		return ""
+	case d < 10*time.Second:
+		return locale.Current().Number(d.Seconds(), 1) + "s"
+	case d < time.Minute:
+		return fmt.Sprintf("%ds", int(d.Seconds()))
+	}
+	return fmt.Sprintf("%dm%02ds", int(d.Minutes()), int(d.Seconds())%60)
+}
@@ -0,0 +1,140 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/charmbracelet/x/ansi"
+	"github.com/seunggabi/claude-dashboard/internal/conversation"
+	"github.com/seunggabi/claude-dashboard/internal/filefeed"
+	"github.com/seunggabi/claude-dashboard/internal/monitor"
+	"github.com/seunggabi/claude-dashboard/internal/session"
+	"github.com/seunggabi/claude-dashboard/internal/timesheet"
+)
+
+// ---------------------------------------------------------------------------
+// RenderDetail tool timeline
+// ---------------------------------------------------------------------------
+
+func TestRenderDetail_showsMostRecentToolCallsThatFit(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	var tools []conversation.ToolEvent
+	for i, name := range []string{"Old", "Bash", "Read"} {
+		start := now.Add(time.Duration(i-3) * time.Minute)
+		tools = append(tools, conversation.ToolEvent{Name: name, Start: start, End: start.Add(2 * time.Second)})
+	}
+	tools[2].End = time.Time{} // still running
+
+	out := ansi.Strip(RenderDetail(&session.Session{Name: "cd-x"}, session.Icons(""), tools, nil, session.Forecast{}, timesheet.Totals{}, nil, DetailWindows[0], now, 100, detailToolRows+2))
+	if strings.Contains(out, "Old") {
+		t.Errorf("expected the oldest call to be dropped, got:\n%s", out)
+	}
+	if !strings.Contains(out, "Bash") || !strings.Contains(out, "2.0s  ✓") {
+		t.Errorf("expected the finished Bash call, got:\n%s", out)
+	}
+	if !strings.Contains(out, "1m00s  …") {
+		t.Errorf("expected the running Read call with its elapsed time, got:\n%s", out)
+	}
+}
+
+func TestWriteToolTimeline_narrowWidthDoesNotPanic(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	tools := []conversation.ToolEvent{{Name: "Bash", Summary: "go test ./...", Start: now.Add(-time.Second), End: now}}
+	var b strings.Builder
+	writeToolTimeline(&b, tools, now, 20, 3)
+	if !strings.Contains(ansi.Strip(b.String()), "Bash") {
+		t.Errorf("expected the call listed without its summary, got:\n%s", b.String())
+	}
+}
+
+func TestRenderDetail_notesMissingLog(t *testing.T) {
+	out := ansi.Strip(RenderDetail(&session.Session{Name: "cd-x"}, session.Icons(""), nil, nil, session.Forecast{}, timesheet.Totals{}, nil, DetailWindows[0], time.Now(), 100, 40))
+	if !strings.Contains(out, "no conversation log") {
+		t.Errorf("expected a note about the missing log, got:\n%s", out)
+	}
+}
+
+// ---------------------------------------------------------------------------
+// RenderDetail file changes
+// ---------------------------------------------------------------------------
+
+func TestRenderDetail_showsRecentFileChanges(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	files := []filefeed.Change{
+		{Path: "internal/app/app.go", Op: filefeed.Modified, At: now.Add(-2 * time.Minute)},
+		{Path: "old.go", Op: filefeed.Deleted, At: now.Add(-3 * time.Hour)},
+	}
+	out := ansi.Strip(RenderDetail(&session.Session{Name: "cd-x"}, session.Icons(""), []conversation.ToolEvent{}, files, session.Forecast{}, timesheet.Totals{}, nil, DetailWindows[0], now, 100, 40))
+	for _, want := range []string{"modified  internal/app/app.go", "2m ago", "deleted   old.go", "3h ago"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %q in:\n%s", want, out)
+		}
+	}
+
+	out = ansi.Strip(RenderDetail(&session.Session{Name: "cd-x"}, session.Icons(""), nil, []filefeed.Change{}, session.Forecast{}, timesheet.Totals{}, nil, DetailWindows[0], now, 100, 40))
+	if !strings.Contains(out, "none since the dashboard started") {
+		t.Errorf("expected a note about no changes, got:\n%s", out)
+	}
+}
+
+// ---------------------------------------------------------------------------
+// RenderDetail conflicts
+// ---------------------------------------------------------------------------
+
+func TestRenderDetail_namesConflictingSessionsAndFiles(t *testing.T) {
+	s := &session.Session{Name: "cd-x", Conflict: session.Conflict{
+		With:  []string{"cd-y"},
+		Files: []string{"a.go", "b.go", "c.go", "d.go", "e.go", "f.go", "g.go"},
+	}}
+	out := ansi.Strip(RenderDetail(s, session.Icons(""), nil, nil, session.Forecast{}, timesheet.Totals{}, nil, DetailWindows[0], time.Now(), 200, 40))
+	if !strings.Contains(out, "⚠ with cd-y: a.go, b.go, c.go, d.go, e.go +2 more") {
+		t.Errorf("expected the conflict row, got:\n%s", out)
+	}
+}
+
+func TestRenderDetail_timeToday(t *testing.T) {
+	times := timesheet.Totals{Attached: 12 * time.Minute, Active: 65 * time.Minute}
+	out := ansi.Strip(RenderDetail(&session.Session{Name: "cd-x"}, session.Icons(""), nil, nil, session.Forecast{}, times, nil, DetailWindows[0], time.Now(), 200, 40))
+	if !strings.Contains(out, "attached 12m · active 1h5m") {
+		t.Errorf("expected the time row, got:\n%s", out)
+	}
+}
+
+// ---------------------------------------------------------------------------
+// RenderDetail resources
+// ---------------------------------------------------------------------------
+
+func TestRenderDetail_graphsCPUAndMemoryOverTheWindow(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	samples := []monitor.Sample{
+		{Time: now.Add(-4 * time.Minute), CPU: 5, Memory: 1.5},
+		{Time: now.Add(-2 * time.Minute), CPU: 85, Memory: 2},
+		{Time: now.Add(-time.Minute), CPU: 40, Memory: 2.5},
+	}
+	out := ansi.Strip(RenderDetail(&session.Session{Name: "cd-x"}, session.Icons(""), nil, nil, session.Forecast{}, timesheet.Totals{}, samples, 5*time.Minute, now, 100, 60))
+	for _, want := range []string{"RESOURCES  last 5m", "CPU  now 40.0%  peak 85.0%", "MEM  now 2.5%  peak 2.5%", "█"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %q, got:\n%s", want, out)
+		}
+	}
+
+	out = ansi.Strip(RenderDetail(&session.Session{Name: "cd-x", Host: "box"}, session.Icons(""), nil, nil, session.Forecast{}, timesheet.Totals{}, nil, 5*time.Minute, now, 100, 60))
+	if !strings.Contains(out, "not sampled for remote sessions") || strings.Contains(out, "peak") {
+		t.Errorf("expected no graphs for a remote session, got:\n%s", out)
+	}
+}
+
+// ---------------------------------------------------------------------------
+// forecastLabel
+// ---------------------------------------------------------------------------
+
+func TestForecastLabel_declineNamesRatesAndETA(t *testing.T) {
+	f := session.Forecast{Trend: session.TrendDeclining, Before: 1500, Recent: 200, ETA: 90 * time.Second}
+	if got := forecastLabel(f); !strings.Contains(got, "likely finishing in ~2m") || !strings.Contains(got, "→ 200 tokens/min") {
+		t.Errorf("unexpected label %q", got)
+	}
+	if got := forecastLabel(session.Forecast{}); got != "-" {
+		t.Errorf("expected - without a trend, got %q", got)
+	}
+}
@@ -0,0 +1,126 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/x/ansi"
+	"github.com/seunggabi/claude-dashboard/internal/config"
+	"github.com/seunggabi/claude-dashboard/internal/locale"
+	"github.com/seunggabi/claude-dashboard/internal/session"
+	"github.com/seunggabi/claude-dashboard/internal/styles"
+	"github.com/seunggabi/claude-dashboard/internal/tmux"
+	"github.com/seunggabi/claude-dashboard/internal/usage"
+)
+
+// FleetHeaderRows is how many lines RenderFleetHeader takes.
+const FleetHeaderRows = 2
+
+// fleetStatuses are the statuses the header counts, in order.
+var fleetStatuses = []session.Status{
+	session.StatusActive, session.StatusWaiting, session.StatusIdle,
+	session.StatusExited, session.StatusCrashed, session.StatusTerminal, session.StatusUnknown,
+}
+
+// FleetStats is what the header above the session table totals.
+type FleetStats struct {
+	Fleet session.Fleet
+	Icons session.IconSet      // status glyphs; zero for the default set
+	Guard config.ResourceGuard // CPU and memory over its thresholds are highlighted
+
+	// Today's use across every conversation; UsageErr is set if the logs
+	// could not be read, and neither before the first read.
+	Usage    *usage.Totals
+	UsageErr error
+
+	// The tmux server: NoTmux when tmux is not installed, otherwise its
+	// Server info or the error reading it, e.g. when no server runs.
+	NoTmux    bool
+	Server    tmux.ServerInfo
+	ServerErr error
+}
+
+// RenderFleetHeader renders the totals above the session table: sessions
+// by status with their CPU and memory, then today's tokens and spend and
+// the health of the tmux server.
+func RenderFleetHeader(st FleetStats, now time.Time, width int) string {
+	icons := st.Icons
+	if icons == (session.IconSet{}) {
+		icons = session.Icons("")
+	}
+
+	counts := []string{fleetLabel("SESSIONS") + styles.StatusVal.Render(fmt.Sprintf("%d", st.Fleet.Sessions))}
+	for _, status := range fleetStatuses {
+		if n := st.Fleet.Count(status); n > 0 {
+			s := session.Session{Status: status}
+			counts = append(counts, fmt.Sprintf("%s %d", s.StatusLabel(icons), n))
+		}
+	}
+	resources := fleetLabel("CPU") + guarded(st.Fleet.CPU, st.Guard.CPU) + "  " + fleetLabel("MEM") + guarded(st.Fleet.Memory, st.Guard.Memory)
+	if st.Fleet.Hosts > 1 {
+		resources += styles.Muted.Render(" (local)")
+	}
+
+	today := fleetLabel("TODAY")
+	switch {
+	case st.UsageErr != nil:
+		today += styles.Error.Render("✗ " + st.UsageErr.Error())
+	case st.Usage == nil:
+		today += styles.Muted.Render("reading logs…")
+	default:
+		today += st.Usage.Spend.String()
+		if st.Usage.Spend.Unpriced > 0 {
+			today += styles.Muted.Render(fmt.Sprintf(" (%d unpriced)", st.Usage.Spend.Unpriced))
+		}
+		today += styles.Muted.Render(fmt.Sprintf(" in %d conversation(s)", st.Usage.Conversations))
+	}
+
+	server := fleetLabel("TMUX")
+	switch {
+	case st.NoTmux:
+		server += styles.Muted.Render("not found; terminal sessions only")
+	case st.ServerErr != nil:
+		server += styles.Error.Render("✗ " + st.ServerErr.Error())
+	case st.Server.PID == "":
+		server += styles.Muted.Render("checking…")
+	default:
+		server += styles.Active.Render("● ") + fleetServer(st.Server, now)
+	}
+
+	lines := []string{
+		"  " + strings.Join(counts, "  ") + "  │  " + resources,
+		"  " + today + "  │  " + server,
+	}
+	for i, line := range lines {
+		lines[i] = ansi.Truncate(line, width, "…")
+	}
+	return strings.Join(lines, "\n") + "\n"
+}
+
+// guarded renders a CPU or memory total, marked when it is over the
+// resource guard's limit (0 for none), e.g. "⚠ 412% > 400%".
+func guarded(total, limit float64) string {
+	loc := locale.Current()
+	if limit > 0 && total > limit {
+		return styles.Error.Render("⚠ " + loc.Percent(total) + " > " + loc.Percent(limit))
+	}
+	return loc.Percent(total)
+}
+
+// fleetLabel renders a header field name with the space after it.
+func fleetLabel(name string) string {
+	return styles.StatusKey.Render(name) + " "
+}
+
+// fleetServer describes a running tmux server, e.g. "3.4 · pid 3943 · up 2d".
+func fleetServer(info tmux.ServerInfo, now time.Time) string {
+	parts := []string{"pid " + info.PID}
+	if info.Version != "" {
+		parts = append([]string{info.Version}, parts...)
+	}
+	if !info.Started.IsZero() {
+		parts = append(parts, "up "+strings.TrimSuffix(agoLabel(now.Sub(info.Started)), " ago"))
+	}
+	return strings.Join(parts, " · ")
+}
@@ -0,0 +1,81 @@
+package ui
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/charmbracelet/x/ansi"
+	"github.com/seunggabi/claude-dashboard/internal/config"
+	"github.com/seunggabi/claude-dashboard/internal/conversation"
+	"github.com/seunggabi/claude-dashboard/internal/session"
+	"github.com/seunggabi/claude-dashboard/internal/tmux"
+	"github.com/seunggabi/claude-dashboard/internal/usage"
+)
+
+// ---------------------------------------------------------------------------
+// RenderFleetHeader
+// ---------------------------------------------------------------------------
+
+func TestRenderFleetHeader_countsUsageAndServer(t *testing.T) {
+	now := time.Date(2025, 11, 24, 12, 0, 0, 0, time.UTC)
+	st := FleetStats{
+		Fleet: session.Summarize([]session.Session{
+			{Name: "cd-api", Status: session.StatusActive, CPU: 40},
+			{Name: "cd-web", Status: session.StatusIdle, CPU: 2.5},
+			{Name: "cd-gpu", Status: session.StatusIdle},
+		}),
+		Usage:  &usage.Totals{Conversations: 3, Spend: conversation.Spend{Cost: 4.2, Tokens: 1200000, Unpriced: 1}},
+		Server: tmux.ServerInfo{PID: "3943", Version: "3.4", Started: now.Add(-50 * time.Hour)},
+	}
+	out := ansi.Strip(RenderFleetHeader(st, now, 200))
+	lines := strings.Split(strings.TrimSuffix(out, "\n"), "\n")
+	if len(lines) != FleetHeaderRows {
+		t.Fatalf("expected %d lines, got %q", FleetHeaderRows, lines)
+	}
+	for _, want := range []string{"SESSIONS 3", "● active 1", "○ idle 2", "CPU 42.5%"} {
+		if !strings.Contains(lines[0], want) {
+			t.Errorf("expected %q in %q", want, lines[0])
+		}
+	}
+	if strings.Contains(lines[0], "waiting") {
+		t.Errorf("expected statuses without sessions left out, got %q", lines[0])
+	}
+	for _, want := range []string{"TODAY $4.20, 1.2M tokens (1 unpriced) in 3 conversation(s)", "TMUX ● 3.4 · pid 3943 · up 2d"} {
+		if !strings.Contains(lines[1], want) {
+			t.Errorf("expected %q in %q", want, lines[1])
+		}
+	}
+}
+
+func TestRenderFleetHeader_marksTotalsOverTheResourceGuard(t *testing.T) {
+	st := FleetStats{
+		Fleet: session.Summarize([]session.Session{{Name: "cd-a", CPU: 300, Memory: 10}, {Name: "cd-b", CPU: 150, Memory: 5}}),
+		Guard: config.ResourceGuard{CPU: 400, Memory: 50},
+	}
+	out := ansi.Strip(RenderFleetHeader(st, time.Now(), 200))
+	if !strings.Contains(out, "CPU ⚠ 450.0% > 400.0%") || !strings.Contains(out, "MEM 15.0%") {
+		t.Errorf("expected only CPU marked, got %q", out)
+	}
+}
+
+func TestRenderFleetHeader_pendingAndFailedReads(t *testing.T) {
+	now := time.Now()
+	out := ansi.Strip(RenderFleetHeader(FleetStats{}, now, 200))
+	if !strings.Contains(out, "reading logs…") || !strings.Contains(out, "TMUX checking…") {
+		t.Errorf("expected pending reads, got %q", out)
+	}
+	out = ansi.Strip(RenderFleetHeader(FleetStats{UsageErr: errors.New("permission denied"), ServerErr: errors.New("no server running")}, now, 200))
+	if !strings.Contains(out, "✗ permission denied") || !strings.Contains(out, "✗ no server running") {
+		t.Errorf("expected read errors, got %q", out)
+	}
+	if out := ansi.Strip(RenderFleetHeader(FleetStats{NoTmux: true}, now, 200)); !strings.Contains(out, "TMUX not found") {
+		t.Errorf("expected tmux to be missing, got %q", out)
+	}
+	for _, line := range strings.Split(RenderFleetHeader(FleetStats{NoTmux: true}, now, 30), "\n") {
+		if ansi.StringWidth(line) > 30 {
+			t.Errorf("expected lines cut to 30 columns, got %q", line)
+		}
+	}
+}
@@ -33,20 +33,54 @@ func RenderHelp(width int) string {
 			title: "Actions",
 			keys: []struct{ key, desc string }{
 				{"n", "Create new session"},
-				{"K", "Kill session (with confirm)"},
-				{"ctrl+k", "Kill all idle sessions"},
+				{"K", "Kill session (archive or worktree options)"},
+				{"ctrl+k", "Kill all idle and exited sessions"},
+				{"ctrl+r", "Restart claude where it exited or crashed"},
+				{"R", "Restore saved sessions missing from tmux"},
+				{"a", "Adopt a terminal session: resume its conversation in tmux"},
 				{"l", "View session logs"},
+				{"c", "Copy the attach command (ssh + tmux attach for remote hosts)"},
+				{"C", "Pick an earlier conversation of the session to read"},
+				{"D", "Draft a PR description of the conversation (claude -p) to the clipboard"},
+				{"p", "Send a prompt to session"},
+				{"y / N", "Approve / deny the prompt a waiting session is on"},
+				{"#", "Tag session (filter with / tag:name)"},
+				{"I", "Link session to an issue, e.g. ENG-123"},
+				{"o", "Open the session's issue in the browser (issues.url)"},
 				{"ctrl+s", "Save pane history (when attached to session)"},
-				{"d", "View session detail"},
+				{"d", "View session detail, CPU / memory graphs, file changes and tool timeline"},
+				{"t", "Run the project's test command (test_commands)"},
+				{"tab", "Toggle preview pane of the highlighted session"},
+				{"m", "Monitor CPU / memory / token rate charts"},
+				{"P", "Pulse: activity of all sessions over time"},
+				{"A", "Archive: browse, search and restore archived and imported sessions"},
+				{"v", "Cycle row density (compact / comfortable / detailed)"},
+				{"1-9 / 0", "Quick views: waiting, active, mine, then config views / all"},
+				{"u / U", "Undo / redo a filter, host, density or preview change"},
+				{"z", "Resume a session paused by the resource guard"},
+				{"S", "Switch config profile (work / home, ...)"},
 				{"r", "Refresh session list"},
 			},
 		},
 		{
 			title: "Logs Viewer",
 			keys: []struct{ key, desc string }{
-				{"↑/k", "Scroll up"},
+				{"↑/k", "Scroll up (conversations: past the top, load older messages)"},
 				{"↓/j", "Scroll down"},
 				{"pgup/pgdn", "Page up / down"},
+				{"[ / ]", "Previous / next message"},
+				{"u / a", "Previous user / assistant message"},
+				{"R", "Show all / user / assistant messages"},
+				{"T", "Show only today's messages"},
+				{"/", "Search pane output (conversations: show only messages containing a term)"},
+				{"n / N", "Next / previous search match"},
+				{"backspace", "Clear message filters"},
+				{"r", "Toggle raw text / rendered markdown"},
+				{"e", "Export full conversation as markdown"},
+				{"F", "Toggle follow (refresh and mark new lines)"},
+				{"enter", "Expand / collapse long message"},
+				{"E", "Expand / collapse all messages"},
+				{"y", "Copy message to clipboard"},
 				{"esc", "Back to dashboard"},
 			},
 		},
@@ -54,6 +88,7 @@ func RenderHelp(width int) string {
 			title: "Search & Other",
 			keys: []struct{ key, desc string }{
 				{"/", "Filter sessions"},
+				{"H", "Cycle host filter (remote hosts)"},
 				{"?", "Show this help"},
 				{"q", "Quit"},
 				{"ctrl+c", "Force quit"},
@@ -0,0 +1,115 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/seunggabi/claude-dashboard/internal/styles"
+)
+
+// MinSplitWidth is the narrowest terminal a side pane is shown in; below it
+// the main pane keeps the full width.
+const MinSplitWidth = 100
+
+// paneDivider separates side-by-side panes.
+const paneDivider = " │ "
+
+// Pane is rendered content placed by JoinPanes.
+type Pane struct {
+	Content string
+	Width   int
+}
+
+// SplitWidth divides width between a main pane and a side pane taking about
+// ratio of it, leaving room for the divider. side is 0 when width is below
+// MinSplitWidth.
+func SplitWidth(width int, ratio float64) (main, side int) {
+	if width < MinSplitWidth {
+		return width, 0
+	}
+	side = int(float64(width) * ratio)
+	main = width - side - lipgloss.Width(paneDivider)
+	return main, side
+}
+
+// JoinPanes lays panes out side by side, height lines tall. Each pane is cut
+// or padded to its width and height, so a long line or an overfull pane
+// never pushes its neighbours out of place.
+func JoinPanes(height int, panes ...Pane) string {
+	cols := make([][]string, len(panes))
+	for i, p := range panes {
+		cols[i] = fitLines(p.Content, p.Width, height)
+	}
+	divider := styles.Muted.Render(paneDivider)
+	var b strings.Builder
+	for row := 0; row < height; row++ {
+		for i := range cols {
+			if i > 0 {
+				b.WriteString(divider)
+			}
+			b.WriteString(cols[i][row])
+		}
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+// fitLines splits content into exactly height lines of exactly width columns.
+func fitLines(content string, width, height int) []string {
+	lines := strings.Split(strings.TrimSuffix(content, "\n"), "\n")
+	if len(lines) > height {
+		lines = lines[:height]
+	}
+	out := make([]string, height)
+	for i := range out {
+		line := ""
+		if i < len(lines) {
+			line = ansi.Truncate(lines[i], width, "")
+		}
+		if pad := width - lipgloss.Width(line); pad > 0 {
+			line += strings.Repeat(" ", pad)
+		}
+		out[i] = line
+	}
+	return out
+}
+
+// MinWidth and MinHeight are the smallest terminal the dashboard is drawn
+// in; below either, RenderTooSmall is shown instead.
+const (
+	MinWidth  = 60
+	MinHeight = 10
+)
+
+// TooSmall reports whether a width x height terminal is below MinWidth or
+// MinHeight.
+func TooSmall(width, height int) bool {
+	return width < MinWidth || height < MinHeight
+}
+
+// RenderTooSmall renders a notice, centred in a width x height terminal,
+// with its size and the size needed. The notice is cut to fit, so nothing
+// wraps or scrolls.
+func RenderTooSmall(width, height int) string {
+	dim := func(have, need int) string {
+		s := fmt.Sprintf("%d", have)
+		if have < need {
+			return styles.Error.Render(s)
+		}
+		return styles.Active.Render(s)
+	}
+	lines := []string{
+		styles.Header.Render("Terminal too small"),
+		fmt.Sprintf("%s x %s", dim(width, MinWidth), dim(height, MinHeight)),
+		styles.Muted.Render(fmt.Sprintf("needs at least %d x %d", MinWidth, MinHeight)),
+	}
+	if len(lines) > height {
+		lines = lines[:max(height, 1)]
+	}
+	for i, line := range lines {
+		lines[i] = ansi.Truncate(line, width, "")
+	}
+	return lipgloss.Place(width, height, lipgloss.Center, lipgloss.Center, strings.Join(lines, "\n"))
+}
@@ -0,0 +1,93 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// ---------------------------------------------------------------------------
+// SplitWidth
+// ---------------------------------------------------------------------------
+
+func TestSplitWidth_narrowTerminalHasNoSidePane(t *testing.T) {
+	main, side := SplitWidth(MinSplitWidth-1, 0.5)
+	if main != MinSplitWidth-1 || side != 0 {
+		t.Errorf("expected (%d, 0), got (%d, %d)", MinSplitWidth-1, main, side)
+	}
+}
+
+func TestSplitWidth_leavesRoomForTheDivider(t *testing.T) {
+	main, side := SplitWidth(200, 0.4)
+	if side != 80 || main+side+lipgloss.Width(paneDivider) != 200 {
+		t.Errorf("expected panes filling 200 columns with an 80 column side, got (%d, %d)", main, side)
+	}
+}
+
+// ---------------------------------------------------------------------------
+// JoinPanes
+// ---------------------------------------------------------------------------
+
+func TestJoinPanes_fitsEachPaneToItsBox(t *testing.T) {
+	left := "short\na line much longer than the pane\nthird\nfourth"
+	right := "r1"
+	out := JoinPanes(3, Pane{Content: left, Width: 10}, Pane{Content: right, Width: 4})
+
+	lines := strings.Split(strings.TrimSuffix(out, "\n"), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("expected 3 lines, got %d:\n%s", len(lines), out)
+	}
+	want := []string{"short      │ r1  ", "a line muc │     ", "third      │     "}
+	for i, line := range lines {
+		if got := ansi.Strip(line); got != want[i] {
+			t.Errorf("line %d: expected %q, got %q", i, want[i], got)
+		}
+	}
+}
+
+// ---------------------------------------------------------------------------
+// RenderTooSmall
+// ---------------------------------------------------------------------------
+
+func TestTooSmall_belowEitherMinimum(t *testing.T) {
+	if TooSmall(MinWidth, MinHeight) {
+		t.Error("expected the minimum size to be large enough")
+	}
+	if !TooSmall(MinWidth-1, MinHeight) || !TooSmall(MinWidth, MinHeight-1) {
+		t.Error("expected a size below either minimum to be too small")
+	}
+}
+
+func TestRenderTooSmall_fillsTheTerminalWithTheSizes(t *testing.T) {
+	out := RenderTooSmall(40, 6)
+	lines := strings.Split(out, "\n")
+	if len(lines) != 6 {
+		t.Fatalf("expected 6 lines, got %d:\n%s", len(lines), out)
+	}
+	for i, line := range lines {
+		if w := lipgloss.Width(line); w > 40 {
+			t.Errorf("line %d is %d columns wide, expected at most 40", i, w)
+		}
+	}
+	plain := ansi.Strip(out)
+	for _, want := range []string{"Terminal too small", "40 x 6", fmt.Sprintf("needs at least %d x %d", MinWidth, MinHeight)} {
+		if !strings.Contains(plain, want) {
+			t.Errorf("expected %q in:\n%s", want, plain)
+		}
+	}
+}
+
+func TestRenderTooSmall_cutsLinesInATinyTerminal(t *testing.T) {
+	lines := strings.Split(RenderTooSmall(8, 2), "\n")
+	if len(lines) != 2 {
+		t.Errorf("expected 2 lines, got %d", len(lines))
+	}
+	for i, line := range lines {
+		if w := lipgloss.Width(line); w > 8 {
+			t.Errorf("line %d is %d columns wide, expected at most 8", i, w)
+		}
+	}
+}
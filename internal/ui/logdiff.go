@@ -0,0 +1,67 @@
+package ui
+
+import "strings"
+
+// freshRange marks where newly appended output starts in a log: line is the
+// first line that differs from the previous content and col the byte offset
+// within it where new text begins (non-zero when a line was extended, e.g.
+// a reply streaming into the last line).
+type freshRange struct {
+	line, col int
+}
+
+// diffAppended compares two snapshots of a log that only grows at the end,
+// possibly losing lines at the top (history limit), and returns where the
+// appended output starts in next. ok is false when nothing was appended or
+// the snapshots do not overlap (e.g. the screen was cleared).
+func diffAppended(prev, next []string) (r freshRange, ok bool) {
+	prev = trimTrailingBlank(prev)
+	next = trimTrailingBlank(next)
+	if len(prev) == 0 || len(next) == 0 {
+		return freshRange{}, false
+	}
+
+	// Find the smallest shift s such that prev[s:] lines up with the start of
+	// next; the last line of the overlap may have grown in place.
+	for s := 0; s < len(prev); s++ {
+		overlap := len(prev) - s
+		if overlap > len(next) {
+			continue
+		}
+		if !linesEqual(prev[s:len(prev)-1], next[:overlap-1]) {
+			continue
+		}
+		last, cur := prev[len(prev)-1], next[overlap-1]
+		switch {
+		case last == cur:
+			if overlap == len(next) {
+				return freshRange{}, false
+			}
+			return freshRange{line: overlap}, true
+		case strings.HasPrefix(cur, last):
+			return freshRange{line: overlap - 1, col: len(last)}, true
+		}
+	}
+	return freshRange{}, false
+}
+
+func linesEqual(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// trimTrailingBlank drops the empty lines capture-pane pads a pane with.
+func trimTrailingBlank(lines []string) []string {
+	n := len(lines)
+	for n > 0 && strings.TrimSpace(lines[n-1]) == "" {
+		n--
+	}
+	return lines[:n]
+}
@@ -0,0 +1,65 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// ---------------------------------------------------------------------------
+// diffAppended
+// ---------------------------------------------------------------------------
+
+func TestDiffAppended_tableTests(t *testing.T) {
+	cases := []struct {
+		name       string
+		prev, next string
+		want       freshRange
+		ok         bool
+	}{
+		{"appended lines", "a\nb", "a\nb\nc\nd", freshRange{line: 2}, true},
+		{"nothing new", "a\nb", "a\nb", freshRange{}, false},
+		{"padding ignored", "a\nb\n\n", "a\nb\n\n\n", freshRange{}, false},
+		{"last line grew", "a\n$ make", "a\n$ make test\nok", freshRange{line: 1, col: 6}, true},
+		{"history trimmed at top", "a\nb\nc", "b\nc\nd", freshRange{line: 2}, true},
+		{"screen cleared", "a\nb", "x\ny", freshRange{}, false},
+		{"first snapshot", "", "a", freshRange{}, false},
+	}
+	for _, tc := range cases {
+		got, ok := diffAppended(strings.Split(tc.prev, "\n"), strings.Split(tc.next, "\n"))
+		if ok != tc.ok || got != tc.want {
+			t.Errorf("%s: expected (%+v, %v), got (%+v, %v)", tc.name, tc.want, tc.ok, got, ok)
+		}
+	}
+}
+
+// ---------------------------------------------------------------------------
+// LogView follow refresh
+// ---------------------------------------------------------------------------
+
+func TestLogView_refreshMarksAppendedLinesUntilFaded(t *testing.T) {
+	lv := NewLogView("s", 80, 20)
+	lv.SetContent("one\ntwo")
+	if lv.HasFresh() {
+		t.Fatal("expected initial content not to be highlighted")
+	}
+
+	now := time.Now()
+	lv.RefreshContent("one\ntwo\nthree", now)
+	if !lv.HasFresh() {
+		t.Fatal("expected appended line to be highlighted")
+	}
+	if !strings.Contains(lv.Viewport.View(), "▌three") {
+		t.Errorf("expected gutter marker on the new line, got %q", lv.Viewport.View())
+	}
+
+	if !lv.Fade(now.Add(FreshDuration / 2)) {
+		t.Error("expected highlight to still be visible at half time")
+	}
+	if lv.Fade(now.Add(FreshDuration)) {
+		t.Error("expected highlight to be gone after FreshDuration")
+	}
+	if strings.Contains(lv.Viewport.View(), "▌") {
+		t.Error("expected gutter marker to be cleared after fading")
+	}
+}
@@ -2,38 +2,580 @@ package ui
 
 import (
 	"fmt"
+	"regexp"
 	"strings"
+	"time"
 
 	"github.com/charmbracelet/bubbles/viewport"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/seunggabi/claude-dashboard/internal/conversation"
 	"github.com/seunggabi/claude-dashboard/internal/styles"
 )
 
+// DefaultCollapseLines is how many lines of a long conversation message are
+// shown before it is collapsed behind an expand marker, unless
+// LogView.CollapseLines says otherwise.
+const DefaultCollapseLines = 40
+
+// FreshDuration is how long newly appended lines stay highlighted in follow
+// mode. They are bright for the first half and dimmed for the second.
+const FreshDuration = 4 * time.Second
+
 // LogView holds the log viewer state.
 type LogView struct {
 	Viewport    viewport.Model
 	SessionName string
 	Ready       bool
+
+	// Follow keeps refreshing the log while it is open. Lines appended since
+	// the previous refresh are marked in the left gutter until they fade.
+	Follow   bool
+	lines    []string
+	fresh    freshRange
+	hasFresh bool
+	freshAt  time.Time
+
+	// Conversation mode, set via SetMessages. Messages are rendered one by one
+	// so long ones can be collapsed and navigated individually.
+	Messages     []conversation.Message
+	FormatOpts   conversation.FormatOptions
+	Filter       conversation.Filter
+	Counts       conversation.Counts
+	conversation bool
+	expanded     map[int]bool
+	offsets      []int // first content line of each message
+
+	// Older messages are read a page at a time once the top is reached:
+	// olderLog is the log they come from, empty until the first page, and
+	// olderEnd the offset the next page ends at.
+	olderLog     string
+	olderEnd     int64
+	olderDone    bool
+	loadingOlder bool
+
+	// CollapseLines is how many lines of a long message are shown before it
+	// is collapsed; 0 for DefaultCollapseLines.
+	CollapseLines int
+
+	// Raw shows assistant messages as plain text instead of rendered markdown.
+	Raw bool
+	md  *markdownRenderer
+
+	// Search highlights a term in the lines; matches are the lines holding
+	// it and match the one last jumped to.
+	search  string
+	matches []int
+	match   int
 }
 
 // NewLogView creates a new log viewer.
 func NewLogView(sessionName string, width, height int) LogView {
 	vp := viewport.New(width, height-4)
-	vp.Style = styles.LogViewer
+	// The left padding column is drawn by paint as the fresh-line gutter.
+	vp.Style = styles.LogViewer.PaddingLeft(0)
 
 	return LogView{
 		Viewport:    vp,
 		SessionName: sessionName,
+		Follow:      true,
 	}
 }
 
-// SetContent updates the log content.
+// SetContent updates the log content. Colors in it, as captured from a
+// pane, are kept.
 func (l *LogView) SetContent(content string) {
-	l.Viewport.SetContent(content)
+	l.setLines(closeColors(strings.Split(content, "\n")), time.Time{})
+	l.Viewport.GotoBottom()
+	l.Ready = true
+}
+
+// RefreshContent replaces the log content with a newer snapshot of the same
+// log, highlighting what was appended. The view stays at the bottom if it
+// was there, otherwise the scroll position is kept.
+func (l *LogView) RefreshContent(content string, now time.Time) {
+	atBottom := l.Viewport.AtBottom()
+	l.setLines(closeColors(strings.Split(content, "\n")), now)
+	if atBottom {
+		l.Viewport.GotoBottom()
+	}
+}
+
+// sgr matches an SGR escape sequence, which sets colors and text
+// attributes.
+var sgr = regexp.MustCompile(`\x1b\[[0-9;:]*m`)
+
+// closeColors makes each line carry its own colors: attributes still set at
+// the end of a line are reset there and set again at the start of the next,
+// so they neither run into the gutter and border nor go missing from a line
+// drawn without the ones above it.
+func closeColors(lines []string) []string {
+	open := "" // sequences in effect
+	out := make([]string, len(lines))
+	for i, line := range lines {
+		if !strings.Contains(line, "\x1b") {
+			out[i] = line
+			if open != "" {
+				out[i] = open + line + "\x1b[0m"
+			}
+			continue
+		}
+		text := open + line
+		for _, seq := range sgr.FindAllString(line, -1) {
+			params := seq[2 : len(seq)-1]
+			switch {
+			case params == "" || params == "0":
+				open = ""
+			case strings.HasPrefix(params, "0;"):
+				open = seq
+			default:
+				open += seq
+			}
+		}
+		if open != "" {
+			text += "\x1b[0m"
+		}
+		out[i] = text
+	}
+	return out
+}
+
+// setLines diffs lines against the current content and repaints. A zero now
+// replaces the content without highlighting anything.
+func (l *LogView) setLines(lines []string, now time.Time) {
+	if !now.IsZero() {
+		if r, ok := diffAppended(l.lines, lines); ok {
+			l.fresh, l.hasFresh, l.freshAt = r, true, now
+		}
+	} else {
+		l.hasFresh = false
+	}
+	l.lines = lines
+	l.findMatches()
+	l.paint(now)
+}
+
+// Fade repaints the fresh-line highlight for the current time and reports
+// whether any highlight is still visible.
+func (l *LogView) Fade(now time.Time) bool {
+	if !l.hasFresh {
+		return false
+	}
+	if now.Sub(l.freshAt) >= FreshDuration {
+		l.hasFresh = false
+	}
+	l.paint(now)
+	return l.hasFresh
+}
+
+// HasFresh reports whether newly appended lines are currently highlighted.
+func (l *LogView) HasFresh() bool {
+	return l.hasFresh
+}
+
+// paint renders lines into the viewport with a one-column gutter that marks
+// fresh lines. Text appended to an existing line is highlighted too when the
+// line carries no styling of its own.
+func (l *LogView) paint(now time.Time) {
+	bright := l.hasFresh && now.Sub(l.freshAt) < FreshDuration/2
+	marker := styles.FreshDim.Render("▌")
+	if bright {
+		marker = styles.Fresh.Render("▌")
+	}
+
+	var b strings.Builder
+	for i, line := range l.lines {
+		if i > 0 {
+			b.WriteString("\n")
+		}
+		line = l.highlight(i, line)
+		if !l.hasFresh || i < l.fresh.line {
+			b.WriteString(" " + line)
+			continue
+		}
+		b.WriteString(marker)
+		if bright && i == l.fresh.line && l.fresh.col > 0 &&
+			l.fresh.col <= len(line) && !strings.Contains(line, "\x1b") {
+			line = line[:l.fresh.col] + styles.Fresh.Render(line[l.fresh.col:])
+		}
+		b.WriteString(line)
+	}
+	l.Viewport.SetContent(b.String())
+}
+
+// Search highlights term in the log, ignoring case, and scrolls to its
+// first match from the top of the view on, wrapping around. An empty term
+// ends the search.
+func (l *LogView) Search(term string) {
+	l.search = term
+	l.findMatches()
+	l.match = 0
+	for i, line := range l.matches {
+		if line >= l.Viewport.YOffset {
+			l.match = i
+			break
+		}
+	}
+	l.paint(time.Time{})
+	l.showMatch()
+}
+
+// NextMatch scrolls to the match after the current one, wrapping around.
+func (l *LogView) NextMatch() {
+	if len(l.matches) > 0 {
+		l.match = (l.match + 1) % len(l.matches)
+		l.paint(time.Time{})
+		l.showMatch()
+	}
+}
+
+// PrevMatch scrolls to the match before the current one, wrapping around.
+func (l *LogView) PrevMatch() {
+	if len(l.matches) > 0 {
+		l.match = (l.match + len(l.matches) - 1) % len(l.matches)
+		l.paint(time.Time{})
+		l.showMatch()
+	}
+}
+
+// SearchSummary describes the search and the match shown, e.g.
+// `"panic" 2/5`. Empty without a search.
+func (l *LogView) SearchSummary() string {
+	switch {
+	case l.search == "":
+		return ""
+	case len(l.matches) == 0:
+		return fmt.Sprintf("%q no matches", l.search)
+	}
+	return fmt.Sprintf("%q %d/%d", l.search, l.match+1, len(l.matches))
+}
+
+// findMatches lists the lines holding the search term, keeping the current
+// match in range.
+func (l *LogView) findMatches() {
+	l.matches = nil
+	if l.search == "" {
+		return
+	}
+	term := strings.ToLower(l.search)
+	for i, line := range l.lines {
+		if strings.Contains(strings.ToLower(ansi.Strip(line)), term) {
+			l.matches = append(l.matches, i)
+		}
+	}
+	if l.match >= len(l.matches) {
+		l.match = max(len(l.matches)-1, 0)
+	}
+}
+
+// showMatch scrolls the current match to the middle of the view.
+func (l *LogView) showMatch() {
+	if len(l.matches) > 0 {
+		l.Viewport.SetYOffset(l.matches[l.match] - l.Viewport.Height/2)
+	}
+}
+
+// highlight marks the search term in line i. A line holding it loses its
+// own colors, so the match stands out.
+func (l *LogView) highlight(i int, line string) string {
+	if l.search == "" {
+		return line
+	}
+	if strings.Contains(line, "\x1b") {
+		plain := ansi.Strip(line)
+		if !strings.Contains(strings.ToLower(plain), strings.ToLower(l.search)) {
+			return line
+		}
+		line = plain
+	}
+	lower := strings.ToLower(line)
+	if len(lower) != len(line) {
+		return line // lower casing moved the bytes
+	}
+	style := styles.Match
+	if len(l.matches) > 0 && l.matches[l.match] == i {
+		style = styles.CurrentMatch
+	}
+	term := strings.ToLower(l.search)
+	var b strings.Builder
+	for {
+		j := strings.Index(lower, term)
+		if j < 0 {
+			break
+		}
+		b.WriteString(line[:j] + style.Render(line[j:j+len(term)]))
+		line, lower = line[j+len(term):], lower[j+len(term):]
+	}
+	b.WriteString(line)
+	return b.String()
+}
+
+// SetMessages switches the viewer to conversation mode and shows msgs.
+func (l *LogView) SetMessages(msgs []conversation.Message, opts conversation.FormatOptions) {
+	l.Messages = msgs
+	l.FormatOpts = opts
+	l.conversation = true
+	l.expanded = make(map[int]bool)
+	l.olderLog, l.olderEnd, l.olderDone, l.loadingOlder = "", 0, false, false
+	l.renderMessages(time.Time{})
 	l.Viewport.GotoBottom()
 	l.Ready = true
 }
 
+// RefreshMessages is RefreshContent for conversation mode. Expanded state is
+// kept as long as the window of messages did not move. Older pages stay in
+// front of msgs while msgs carries on from them.
+func (l *LogView) RefreshMessages(msgs []conversation.Message, now time.Time) {
+	atBottom := l.Viewport.AtBottom()
+	if i := l.olderIndex(msgs); i >= 0 {
+		msgs = append(l.Messages[:i:i], msgs...)
+	} else {
+		l.olderLog, l.olderEnd, l.olderDone = "", 0, false
+	}
+	if len(msgs) == 0 || len(l.Messages) == 0 || !msgs[0].Timestamp.Equal(l.Messages[0].Timestamp) {
+		l.expanded = make(map[int]bool)
+	}
+	l.Messages = msgs
+	l.renderMessages(now)
+	if atBottom {
+		l.Viewport.GotoBottom()
+	}
+}
+
+// olderIndex returns where the first of msgs is among the messages shown,
+// when older pages were read, or -1.
+func (l *LogView) olderIndex(msgs []conversation.Message) int {
+	if l.olderLog == "" || len(msgs) == 0 {
+		return -1
+	}
+	first := msgs[0]
+	for i := len(l.Messages) - 1; i >= 0; i-- {
+		m := l.Messages[i]
+		if m.Timestamp.Equal(first.Timestamp) && m.Role == first.Role && m.Content == first.Content {
+			return i
+		}
+	}
+	return -1
+}
+
+// WantsOlder reports whether the viewer is at the top of a conversation
+// with older messages not yet read, and marks them as being read: call
+// PrependMessages or OlderFailed with the result.
+func (l *LogView) WantsOlder() bool {
+	if !l.conversation || !l.Ready || l.loadingOlder || l.olderDone ||
+		len(l.Messages) == 0 || len(l.Messages) >= l.Counts.Matched || !l.Viewport.AtTop() {
+		return false
+	}
+	l.loadingOlder = true
+	return true
+}
+
+// OlderFrom returns the log older pages come from and the offset the next
+// one ends at; log is empty before the first page.
+func (l *LogView) OlderFrom() (log string, end int64) {
+	return l.olderLog, l.olderEnd
+}
+
+// PrependMessages shows the page read from log before the messages shown,
+// keeping the view where it was.
+func (l *LogView) PrependMessages(log string, p conversation.Page) {
+	l.loadingOlder = false
+	l.olderLog, l.olderEnd, l.olderDone = log, p.Start, !p.More()
+	if len(p.Messages) == 0 {
+		return
+	}
+	n := len(p.Messages)
+	expanded := make(map[int]bool, len(l.expanded))
+	for i, e := range l.expanded {
+		expanded[i+n] = e
+	}
+	l.expanded = expanded
+	l.Messages = append(append([]conversation.Message(nil), p.Messages...), l.Messages...)
+	y := l.Viewport.YOffset
+	l.renderMessages(time.Time{})
+	l.Viewport.SetYOffset(l.offsets[n] + y)
+}
+
+// OlderFailed lets the next WantsOlder try again.
+func (l *LogView) OlderFailed() {
+	l.loadingOlder = false
+}
+
+// LoadingOlder reports whether an older page is being read.
+func (l *LogView) LoadingOlder() bool {
+	return l.loadingOlder
+}
+
+// IsConversation reports whether the viewer shows structured messages.
+func (l *LogView) IsConversation() bool {
+	return l.conversation
+}
+
+// FilterSummary describes the active message filter and how many messages
+// it kept, e.g. `user · today — 12/80 msgs`. Empty when no filter is set.
+func (l *LogView) FilterSummary() string {
+	if l.Filter.IsZero() {
+		return ""
+	}
+	return fmt.Sprintf("%s — %d/%d msgs", l.Filter, l.Counts.Matched, l.Counts.Total)
+}
+
+// renderMessages rebuilds the viewport content from Messages, collapsing
+// messages longer than CollapseLines unless expanded. now is passed on to
+// setLines.
+func (l *LogView) renderMessages(now time.Time) {
+	collapse := l.CollapseLines
+	if collapse <= 0 {
+		collapse = DefaultCollapseLines
+	}
+	maxWeight := conversation.MaxWeight(l.Messages)
+	l.offsets = make([]int, len(l.Messages))
+
+	var b strings.Builder
+	line := 0
+	for i, msg := range l.Messages {
+		l.offsets[i] = line
+		b.WriteString(conversation.FormatHeader(msg, maxWeight, l.FormatOpts))
+		b.WriteString("\n")
+		line++
+
+		text := msg.Content
+		if msg.Role == "assistant" && !l.Raw {
+			if l.md == nil {
+				l.md = &markdownRenderer{}
+			}
+			text = l.md.render(text, l.Viewport.Width-2)
+		}
+		body := strings.Split(text, "\n")
+		if len(body) > collapse && !l.expanded[i] {
+			hidden := len(body) - collapse
+			body = append(body[:collapse:collapse],
+				styles.Muted.Render(fmt.Sprintf("… %d more lines (enter: expand)", hidden)))
+		}
+		b.WriteString(strings.Join(body, "\n"))
+		b.WriteString("\n\n")
+		line += len(body) + 1
+	}
+	if len(l.Messages) == 0 {
+		b.WriteString(styles.Muted.Render("No messages match the filter."))
+	}
+	l.setLines(strings.Split(b.String(), "\n"), now)
+}
+
+// CurrentMessage returns the index of the message at the top of the
+// viewport, or -1 outside conversation mode.
+func (l *LogView) CurrentMessage() int {
+	if len(l.offsets) == 0 {
+		return -1
+	}
+	cur := 0
+	for i, off := range l.offsets {
+		if off > l.Viewport.YOffset {
+			break
+		}
+		cur = i
+	}
+	return cur
+}
+
+// ToggleExpand expands or collapses the current message and keeps its header
+// at the top of the viewport.
+func (l *LogView) ToggleExpand() {
+	i := l.CurrentMessage()
+	if i < 0 {
+		return
+	}
+	l.expanded[i] = !l.expanded[i]
+	l.renderMessages(time.Time{})
+	l.Viewport.SetYOffset(l.offsets[i])
+}
+
+// ToggleExpandAll expands every message, or collapses all if any is expanded.
+func (l *LogView) ToggleExpandAll() {
+	i := l.CurrentMessage()
+	if i < 0 {
+		return
+	}
+	expand := true
+	for _, e := range l.expanded {
+		if e {
+			expand = false
+			break
+		}
+	}
+	l.expanded = make(map[int]bool)
+	if expand {
+		for j := range l.Messages {
+			l.expanded[j] = true
+		}
+	}
+	l.renderMessages(time.Time{})
+	l.Viewport.SetYOffset(l.offsets[i])
+}
+
+// ToggleRaw switches assistant messages between rendered markdown and raw
+// text, keeping the current message at the top.
+func (l *LogView) ToggleRaw() {
+	i := l.CurrentMessage()
+	if i < 0 {
+		return
+	}
+	l.Raw = !l.Raw
+	l.renderMessages(time.Time{})
+	l.Viewport.SetYOffset(l.offsets[i])
+}
+
+// NextMessage scrolls the following message header to the top.
+func (l *LogView) NextMessage() {
+	i := l.CurrentMessage()
+	if i < 0 || i+1 >= len(l.offsets) {
+		return
+	}
+	l.Viewport.SetYOffset(l.offsets[i+1])
+}
+
+// PrevMessage scrolls to the start of the current message, or to the
+// previous message when already at its start.
+func (l *LogView) PrevMessage() {
+	i := l.CurrentMessage()
+	if i < 0 {
+		return
+	}
+	if l.Viewport.YOffset > l.offsets[i] {
+		l.Viewport.SetYOffset(l.offsets[i])
+	} else if i > 0 {
+		l.Viewport.SetYOffset(l.offsets[i-1])
+	}
+}
+
+// PrevRole scrolls back to the nearest earlier message sent by role
+// ("user" or "assistant"), including the current one if scrolled into it.
+func (l *LogView) PrevRole(role string) {
+	i := l.CurrentMessage()
+	if i < 0 {
+		return
+	}
+	start := i
+	if l.Viewport.YOffset <= l.offsets[i] {
+		start = i - 1
+	}
+	for j := start; j >= 0; j-- {
+		if l.Messages[j].Role == role {
+			l.Viewport.SetYOffset(l.offsets[j])
+			return
+		}
+	}
+}
+
+// CurrentMessageText returns the full text of the current message.
+func (l *LogView) CurrentMessageText() (string, bool) {
+	i := l.CurrentMessage()
+	if i < 0 {
+		return "", false
+	}
+	return l.Messages[i].Content, true
+}
+
 // SetSize updates the viewport dimensions.
 func (l *LogView) SetSize(width, height int) {
 	l.Viewport.Width = width
@@ -46,6 +588,15 @@ func RenderLogView(lv LogView, width int) string {
 
 	title := styles.Title.Render(fmt.Sprintf(" Logs: %s ", lv.SessionName))
 	b.WriteString(title)
+	if summary := lv.FilterSummary(); summary != "" {
+		b.WriteString("  " + styles.Muted.Render("filter: "+summary))
+	}
+	if summary := lv.SearchSummary(); summary != "" {
+		b.WriteString("  " + styles.Muted.Render("search: "+summary))
+	}
+	if lv.LoadingOlder() {
+		b.WriteString("  " + styles.Muted.Render("loading older messages…"))
+	}
 	b.WriteString("\n")
 	b.WriteString(strings.Repeat("─", width))
 	b.WriteString("\n")
@@ -58,9 +609,14 @@ func RenderLogView(lv LogView, width int) string {
 
 	b.WriteString("\n")
 
-	scrollInfo := styles.Muted.Render(
-		fmt.Sprintf(" %3.f%% ", lv.Viewport.ScrollPercent()*100),
-	)
+	info := fmt.Sprintf(" %3.f%% ", lv.Viewport.ScrollPercent()*100)
+	if lv.Follow {
+		info = " follow " + info
+	}
+	if cur := lv.CurrentMessage(); cur >= 0 {
+		info = fmt.Sprintf(" msg %d/%d ", cur+1, len(lv.Messages)) + info
+	}
+	scrollInfo := styles.Muted.Render(info)
 	bar := lipgloss.PlaceHorizontal(width, lipgloss.Right, scrollInfo)
 	b.WriteString(bar)
 
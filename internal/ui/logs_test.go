@@ -0,0 +1,366 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/charmbracelet/x/ansi"
+	"github.com/seunggabi/claude-dashboard/internal/conversation"
+	"github.com/seunggabi/claude-dashboard/internal/styles"
+)
+
+func longMessages() []conversation.Message {
+	return []conversation.Message{
+		{Role: "user", Content: "short question"},
+		{Role: "assistant", Content: strings.Repeat("- line\n", DefaultCollapseLines+10) + "- tail"},
+		{Role: "user", Content: "thanks"},
+	}
+}
+
+// ---------------------------------------------------------------------------
+// LogView conversation mode
+// ---------------------------------------------------------------------------
+
+func TestLogView_longMessageIsCollapsed(t *testing.T) {
+	lv := NewLogView("s", 80, 200)
+	lv.SetMessages(longMessages(), conversation.FormatOptions{})
+	lv.Viewport.GotoTop()
+	content := lv.Viewport.View()
+	if strings.Contains(content, "tail") {
+		t.Error("expected tail of long message to be hidden")
+	}
+	if !strings.Contains(content, "more lines") {
+		t.Error("expected collapse marker")
+	}
+}
+
+func TestLogView_collapsesAfterTheConfiguredLines(t *testing.T) {
+	lv := NewLogView("s", 80, 200)
+	lv.CollapseLines = 5
+	lv.SetMessages([]conversation.Message{{Role: "user", Content: strings.Repeat("line\n", 7) + "tail"}}, conversation.FormatOptions{})
+	content := ansi.Strip(lv.Viewport.View())
+	if strings.Contains(content, "tail") || !strings.Contains(content, "… 3 more lines") {
+		t.Errorf("expected the message cut after 5 lines, got:\n%s", content)
+	}
+}
+
+func TestLogView_toggleExpandShowsFullMessage(t *testing.T) {
+	lv := NewLogView("s", 80, 14)
+	lv.SetMessages(longMessages(), conversation.FormatOptions{})
+	lv.Viewport.SetYOffset(lv.offsets[1])
+	before := lv.Viewport.TotalLineCount()
+	lv.ToggleExpand()
+	if got := lv.Viewport.TotalLineCount(); got != before+10 {
+		t.Errorf("expected %d lines after expanding, got %d", before+10, got)
+	}
+	if got := lv.CurrentMessage(); got != 1 {
+		t.Errorf("expected current message to stay at 1, got %d", got)
+	}
+}
+
+func TestLogView_currentMessageTextReturnsFullContent(t *testing.T) {
+	lv := NewLogView("s", 80, 14)
+	msgs := longMessages()
+	lv.SetMessages(msgs, conversation.FormatOptions{})
+	lv.Viewport.SetYOffset(lv.offsets[1])
+	text, ok := lv.CurrentMessageText()
+	if !ok || text != msgs[1].Content {
+		t.Errorf("expected full content of message 1, got ok=%v len=%d", ok, len(text))
+	}
+}
+
+func TestLogView_plainContentHasNoCurrentMessage(t *testing.T) {
+	lv := NewLogView("s", 80, 40)
+	lv.SetContent("pane output")
+	if got := lv.CurrentMessage(); got != -1 {
+		t.Errorf("expected -1 outside conversation mode, got %d", got)
+	}
+}
+
+// ---------------------------------------------------------------------------
+// LogView message navigation
+// ---------------------------------------------------------------------------
+
+func navMessages() []conversation.Message {
+	var msgs []conversation.Message
+	for i := 0; i < 6; i++ {
+		role := "user"
+		if i%2 == 1 {
+			role = "assistant"
+		}
+		msgs = append(msgs, conversation.Message{Role: role, Content: strings.Repeat("x\n", 9)})
+	}
+	return msgs
+}
+
+func TestLogView_nextAndPrevMessage(t *testing.T) {
+	lv := NewLogView("s", 80, 14)
+	lv.SetMessages(navMessages(), conversation.FormatOptions{})
+	lv.Viewport.GotoTop()
+
+	lv.NextMessage()
+	lv.NextMessage()
+	if got := lv.CurrentMessage(); got != 2 {
+		t.Fatalf("expected message 2 after two jumps, got %d", got)
+	}
+	lv.PrevMessage()
+	if got := lv.CurrentMessage(); got != 1 {
+		t.Errorf("expected message 1 after jumping back, got %d", got)
+	}
+}
+
+func TestLogView_prevMessageFromMiddleGoesToStart(t *testing.T) {
+	lv := NewLogView("s", 80, 14)
+	lv.SetMessages(navMessages(), conversation.FormatOptions{})
+	lv.Viewport.SetYOffset(lv.offsets[2] + 3)
+	lv.PrevMessage()
+	if lv.Viewport.YOffset != lv.offsets[2] {
+		t.Errorf("expected start of message 2 (%d), got offset %d", lv.offsets[2], lv.Viewport.YOffset)
+	}
+}
+
+func TestLogView_prevRoleSkipsOtherRole(t *testing.T) {
+	lv := NewLogView("s", 80, 14)
+	lv.SetMessages(navMessages(), conversation.FormatOptions{})
+	lv.Viewport.SetYOffset(lv.offsets[3])
+	lv.PrevRole("assistant")
+	if got := lv.CurrentMessage(); got != 1 {
+		t.Errorf("expected previous assistant message 1, got %d", got)
+	}
+	lv.PrevRole("user")
+	if got := lv.CurrentMessage(); got != 0 {
+		t.Errorf("expected previous user message 0, got %d", got)
+	}
+}
+
+// ---------------------------------------------------------------------------
+// LogView older pages
+// ---------------------------------------------------------------------------
+
+// numbered returns messages "msg from" to "msg to-1", a second apart.
+func numbered(from, to int) []conversation.Message {
+	var msgs []conversation.Message
+	for i := from; i < to; i++ {
+		msgs = append(msgs, conversation.Message{
+			Role:      "user",
+			Content:   fmt.Sprintf("msg %d", i),
+			Timestamp: time.Date(2025, 11, 24, 9, 0, i, 0, time.UTC),
+		})
+	}
+	return msgs
+}
+
+func TestLogView_wantsOlderOnlyAtTheTopOnce(t *testing.T) {
+	lv := NewLogView("s", 80, 14)
+	lv.Counts = conversation.Counts{Matched: 20, Total: 20}
+	lv.SetMessages(numbered(10, 20), conversation.FormatOptions{})
+	if lv.WantsOlder() {
+		t.Fatal("expected no older page away from the top")
+	}
+	lv.Viewport.GotoTop()
+	if !lv.WantsOlder() || !lv.LoadingOlder() {
+		t.Fatal("expected an older page at the top")
+	}
+	if lv.WantsOlder() {
+		t.Error("expected one page at a time")
+	}
+}
+
+func TestLogView_prependKeepsTheView(t *testing.T) {
+	lv := NewLogView("s", 80, 14)
+	lv.Counts = conversation.Counts{Matched: 20, Total: 20}
+	lv.SetMessages(numbered(10, 20), conversation.FormatOptions{})
+	lv.Viewport.GotoTop()
+	lv.WantsOlder()
+
+	lv.PrependMessages("/log.jsonl", conversation.Page{Messages: numbered(0, 10), Start: 0})
+	if len(lv.Messages) != 20 || lv.Messages[0].Content != "msg 0" {
+		t.Fatalf("expected the page in front, got %d messages", len(lv.Messages))
+	}
+	if got := lv.CurrentMessage(); got != 10 {
+		t.Errorf("expected msg 10 still at the top, got %d", got)
+	}
+	lv.Viewport.GotoTop()
+	if lv.WantsOlder() {
+		t.Error("expected nothing older after the start of the log")
+	}
+}
+
+func TestLogView_refreshKeepsOlderPages(t *testing.T) {
+	lv := NewLogView("s", 80, 14)
+	lv.SetMessages(numbered(10, 20), conversation.FormatOptions{})
+	lv.PrependMessages("/log.jsonl", conversation.Page{Messages: numbered(0, 10), Start: 100})
+
+	lv.RefreshMessages(numbered(12, 22), time.Now())
+	if len(lv.Messages) != 22 || lv.Messages[0].Content != "msg 0" || lv.Messages[21].Content != "msg 21" {
+		t.Errorf("expected the new messages after the older pages, got %d messages", len(lv.Messages))
+	}
+	if log, end := lv.OlderFrom(); log != "/log.jsonl" || end != 100 {
+		t.Errorf("expected the next page to end where the last began, got %q %d", log, end)
+	}
+
+	lv.RefreshMessages(numbered(40, 50), time.Now())
+	if len(lv.Messages) != 10 {
+		t.Errorf("expected a window that jumped ahead to replace everything, got %d messages", len(lv.Messages))
+	}
+	if log, _ := lv.OlderFrom(); log != "" {
+		t.Errorf("expected older pages to start over, got %q", log)
+	}
+}
+
+// ---------------------------------------------------------------------------
+// LogView filters
+// ---------------------------------------------------------------------------
+
+func TestLogView_emptyFilterResultStaysInConversationMode(t *testing.T) {
+	lv := NewLogView("s", 80, 20)
+	lv.Filter = conversation.Filter{Role: "user", Term: "nothing"}
+	lv.Counts = conversation.Counts{Matched: 0, Total: 12}
+	lv.SetMessages(nil, conversation.FormatOptions{})
+	if !lv.IsConversation() {
+		t.Error("expected conversation mode with an empty filtered result")
+	}
+	if !strings.Contains(lv.Viewport.View(), "No messages match") {
+		t.Error("expected empty-result notice")
+	}
+	if got, want := lv.FilterSummary(), `user · "nothing" — 0/12 msgs`; got != want {
+		t.Errorf("expected summary %q, got %q", want, got)
+	}
+}
+
+// ---------------------------------------------------------------------------
+// LogView markdown rendering
+// ---------------------------------------------------------------------------
+
+func TestLogView_assistantMarkdownIsRenderedUntilRawToggled(t *testing.T) {
+	lv := NewLogView("s", 80, 20)
+	lv.SetMessages([]conversation.Message{
+		{Role: "user", Content: "# not a heading"},
+		{Role: "assistant", Content: "## Plan\n\nUse **bold** here"},
+	}, conversation.FormatOptions{})
+	lv.Viewport.GotoTop()
+
+	if content := lv.Viewport.View(); strings.Contains(content, "**bold**") {
+		t.Errorf("expected markdown emphasis to be rendered, got %q", content)
+	}
+	if !strings.Contains(lv.Viewport.View(), "# not a heading") {
+		t.Error("expected user message to stay raw")
+	}
+
+	lv.ToggleRaw()
+	lv.Viewport.GotoTop()
+	if !strings.Contains(lv.Viewport.View(), "**bold**") {
+		t.Error("expected raw text after toggling")
+	}
+}
+
+// ---------------------------------------------------------------------------
+// LogView search
+// ---------------------------------------------------------------------------
+
+// paneLines returns a pane log of n lines with "panic" on lines 10, 50 and 90.
+func paneLines(n int) string {
+	lines := make([]string, n)
+	for i := range lines {
+		lines[i] = fmt.Sprintf("line %d", i)
+	}
+	for _, i := range []int{10, 50, 90} {
+		lines[i] = fmt.Sprintf("line %d: PANIC: nil map", i)
+	}
+	return strings.Join(lines, "\n")
+}
+
+func TestLogView_searchJumpsBetweenMatches(t *testing.T) {
+	lv := NewLogView("s", 80, 24)
+	lv.SetContent(paneLines(100))
+	lv.Viewport.GotoTop()
+	lv.Search("panic")
+	if got := lv.SearchSummary(); got != `"panic" 1/3` {
+		t.Errorf("expected the first of 3 matches, got %q", got)
+	}
+	if !strings.Contains(lv.Viewport.View(), "line 10: PANIC") {
+		t.Error("expected the first match in view")
+	}
+
+	lv.NextMatch()
+	if !strings.Contains(lv.Viewport.View(), "line 50: PANIC") {
+		t.Error("expected the second match in view")
+	}
+	lv.PrevMatch()
+	lv.PrevMatch()
+	if got := lv.SearchSummary(); got != `"panic" 3/3` {
+		t.Errorf("expected going back from the first match to wrap to the last, got %q", got)
+	}
+}
+
+func TestLogView_searchStartsFromTheView(t *testing.T) {
+	lv := NewLogView("s", 80, 24)
+	lv.SetContent(paneLines(100))
+	lv.Viewport.SetYOffset(40)
+	lv.Search("PANIC")
+	if got := lv.SearchSummary(); got != `"PANIC" 2/3` {
+		t.Errorf("expected the first match below the top of the view, got %q", got)
+	}
+}
+
+func TestLogView_searchWithoutMatchesAndCleared(t *testing.T) {
+	lv := NewLogView("s", 80, 24)
+	lv.SetContent(paneLines(100))
+	lv.Search("segfault")
+	if got := lv.SearchSummary(); got != `"segfault" no matches` {
+		t.Errorf("expected no matches, got %q", got)
+	}
+	lv.NextMatch() // must not panic
+	lv.Search("")
+	if got := lv.SearchSummary(); got != "" {
+		t.Errorf("expected the search cleared, got %q", got)
+	}
+}
+
+func TestLogView_highlightKeepsTheText(t *testing.T) {
+	lv := NewLogView("s", 80, 24)
+	lv.SetContent("a Panic and a panic")
+	lv.Search("panic")
+	if got := lv.highlight(0, "a Panic and a panic"); ansi.Strip(got) != "a Panic and a panic" || !strings.Contains(got, styles.CurrentMatch.Render("Panic")) {
+		t.Errorf("expected both occurrences marked in place, got %q", got)
+	}
+}
+
+func TestLogView_highlightDropsTheColorsOfAMatchingLine(t *testing.T) {
+	lv := NewLogView("s", 80, 24)
+	colored := "\x1b[31m- panic(err)\x1b[0m"
+	lv.SetContent(colored + "\n\x1b[32m+ return err\x1b[0m")
+	lv.Search("panic")
+	if got := lv.highlight(0, colored); strings.Contains(got, "\x1b[31m") || ansi.Strip(got) != "- panic(err)" {
+		t.Errorf("expected the match marked on the plain text, got %q", got)
+	}
+	if got := lv.highlight(1, "\x1b[32m+ return err\x1b[0m"); got != "\x1b[32m+ return err\x1b[0m" {
+		t.Errorf("expected a line without the term left as is, got %q", got)
+	}
+}
+
+// ---------------------------------------------------------------------------
+// closeColors
+// ---------------------------------------------------------------------------
+
+func TestCloseColors_carriesAttributesAcrossLines(t *testing.T) {
+	got := closeColors([]string{
+		"\x1b[1m\x1b[32m+ added",
+		"still green",
+		"\x1b[0;31m- removed\x1b[0m",
+		"plain",
+	})
+	want := []string{
+		"\x1b[1m\x1b[32m+ added\x1b[0m",
+		"\x1b[1m\x1b[32mstill green\x1b[0m",
+		"\x1b[1m\x1b[32m\x1b[0;31m- removed\x1b[0m",
+		"plain",
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("line %d: expected %q, got %q", i, want[i], got[i])
+		}
+	}
+}
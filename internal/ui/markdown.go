@@ -0,0 +1,68 @@
+package ui
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/glamour"
+	glamourstyles "github.com/charmbracelet/glamour/styles"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/seunggabi/claude-dashboard/internal/styles"
+)
+
+// markdownRenderer renders message text as terminal markdown. Rendering is
+// slow compared to the rest of the view, so results are cached per message
+// text until the width changes.
+type markdownRenderer struct {
+	width int
+	tr    *glamour.TermRenderer
+	cache map[string]string
+}
+
+// render returns text rendered for width columns, or text unchanged if it
+// cannot be rendered.
+func (r *markdownRenderer) render(text string, width int) string {
+	if width < 20 {
+		width = 20
+	}
+	if r.tr == nil || r.width != width {
+		// The style follows the theme instead of auto-detection: detection
+		// queries the terminal, whose reply would land in the TUI's input.
+		style := glamourstyles.DarkStyle
+		if !styles.Current.Dark {
+			style = glamourstyles.LightStyle
+		}
+		tr, err := glamour.NewTermRenderer(
+			glamour.WithStandardStyle(style),
+			glamour.WithWordWrap(width),
+		)
+		if err != nil {
+			return text
+		}
+		r.tr, r.width, r.cache = tr, width, make(map[string]string)
+	}
+	if out, ok := r.cache[text]; ok {
+		return out
+	}
+	out, err := r.tr.Render(text)
+	if err != nil {
+		return text
+	}
+	out = trimBlankLines(out)
+	r.cache[text] = out
+	return out
+}
+
+// trimBlankLines drops the padding lines glamour puts around a document;
+// the view adds its own spacing between messages. Padding lines may still
+// carry styling, so they are judged by their visible text.
+func trimBlankLines(s string) string {
+	lines := strings.Split(s, "\n")
+	blank := func(l string) bool { return strings.TrimSpace(ansi.Strip(l)) == "" }
+	for len(lines) > 0 && blank(lines[0]) {
+		lines = lines[1:]
+	}
+	for len(lines) > 0 && blank(lines[len(lines)-1]) {
+		lines = lines[:len(lines)-1]
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,117 @@
+package ui
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/seunggabi/claude-dashboard/internal/conversation"
+	"github.com/seunggabi/claude-dashboard/internal/locale"
+	"github.com/seunggabi/claude-dashboard/internal/monitor"
+	"github.com/seunggabi/claude-dashboard/internal/session"
+	"github.com/seunggabi/claude-dashboard/internal/styles"
+)
+
+// MonitorWindows are the time spans the monitor view can show, cycled with w.
+var MonitorWindows = []time.Duration{5 * time.Minute, 15 * time.Minute, time.Hour}
+
+// MonitorData is what the monitor view plots for one session.
+type MonitorData struct {
+	Session  session.Session
+	Samples  []monitor.Sample
+	Messages []conversation.Message // nil when the conversation log is unavailable
+	Window   time.Duration
+	Now      time.Time
+}
+
+// RenderMonitor renders full-width CPU, memory and token rate charts for a
+// session over the last Window.
+func RenderMonitor(d MonitorData, width, height int) string {
+	var b strings.Builder
+
+	title := styles.Title.Render(fmt.Sprintf(" Monitor: %s ", d.Session.DisplayName()))
+	b.WriteString(title)
+	b.WriteString("  " + styles.Muted.Render("last "+formatWindow(d.Window)))
+	b.WriteString("\n")
+	b.WriteString(strings.Repeat("─", width))
+	b.WriteString("\n")
+
+	cols := width - chartLabelWidth - 2
+	if cols < 10 {
+		cols = 10
+	}
+	// Three panels of a header line plus chart, separated by blank lines,
+	// below the title and rule.
+	chartHeight := (height - 2 - 3*2) / 3
+	if chartHeight < 2 {
+		chartHeight = 2
+	}
+
+	start := d.Now.Add(-d.Window)
+	cpu := make([]ChartPoint, len(d.Samples))
+	mem := make([]ChartPoint, len(d.Samples))
+	for i, s := range d.Samples {
+		cpu[i] = ChartPoint{Time: s.Time, Value: s.CPU}
+		mem[i] = ChartPoint{Time: s.Time, Value: s.Memory}
+	}
+
+	if d.Session.Host != "" {
+		b.WriteString(styles.Header.Render("CPU / MEM"))
+		b.WriteString("  " + styles.Muted.Render("not sampled for remote sessions"))
+		b.WriteString("\n\n")
+	} else {
+		percent := locale.Current().Percent
+		writePanel(&b, "CPU", bucketMax(cpu, start, d.Now, cols), chartHeight, 10, styles.ColorSecondary, percent)
+		b.WriteString("\n\n")
+		writePanel(&b, "MEM", bucketMax(mem, start, d.Now, cols), chartHeight, 1, styles.ColorPrimary, percent)
+		b.WriteString("\n\n")
+	}
+
+	if d.Messages == nil {
+		b.WriteString(styles.Header.Render("TOKENS/MIN"))
+		b.WriteString("  " + styles.Muted.Render("no conversation log for this session"))
+		return b.String()
+	}
+	var out []ChartPoint
+	for _, m := range d.Messages {
+		if m.Role == "assistant" && m.Usage.OutputTokens > 0 {
+			out = append(out, ChartPoint{Time: m.Timestamp, Value: float64(m.Usage.OutputTokens)})
+		}
+	}
+	tokens := func(v float64) string { return conversation.FormatTokens(int(v)) }
+	writePanel(&b, "TOKENS/MIN", rollingRate(out, start, d.Now, cols), chartHeight, 100, styles.ColorSuccess, tokens)
+
+	return b.String()
+}
+
+// writePanel writes a chart with a header showing the latest and peak value.
+func writePanel(b *strings.Builder, name string, values []float64, height int, floor float64, color lipgloss.Color, format func(float64) string) {
+	now, peak := math.NaN(), math.NaN()
+	for _, v := range values {
+		if math.IsNaN(v) {
+			continue
+		}
+		now = v
+		if math.IsNaN(peak) || v > peak {
+			peak = v
+		}
+	}
+	b.WriteString(styles.Header.Render(name))
+	if math.IsNaN(now) {
+		b.WriteString("  " + styles.Muted.Render("collecting samples..."))
+	} else {
+		b.WriteString(fmt.Sprintf("  now %s  peak %s", format(now), format(peak)))
+	}
+	b.WriteString("\n")
+	b.WriteString(RenderChart(values, height, floor, color, format))
+}
+
+// formatWindow formats a monitor window as e.g. "15m" or "1h".
+func formatWindow(d time.Duration) string {
+	if d >= time.Hour && d%time.Hour == 0 {
+		return fmt.Sprintf("%dh", int(d.Hours()))
+	}
+	return fmt.Sprintf("%dm", int(d.Minutes()))
+}
@@ -0,0 +1,96 @@
+package ui
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/seunggabi/claude-dashboard/internal/config"
+)
+
+// FormatPath renders path in at most maxLen columns in the given style (see
+// config.PathStyle*). home is replaced with ~ in the home style; pass "" for
+// paths on another machine. Long paths lose their middle components rather
+// than their prefix, so both the project root and the leaf stay readable.
+func FormatPath(path, home, style string, maxLen int) string {
+	if path == "" {
+		return path
+	}
+	switch style {
+	case config.PathStyleBase:
+		path = baseName(path)
+		if maxLen > 0 {
+			return truncate(path, maxLen)
+		}
+		return path
+	case config.PathStyleFull:
+	default:
+		path = homeRelative(path, home)
+	}
+	return middleEllipsis(path, maxLen)
+}
+
+// pathSeparator guesses the separator of path, which may come from another
+// OS than this one (e.g. a remote host).
+func pathSeparator(path string) string {
+	if !strings.Contains(path, "/") && strings.Contains(path, `\`) {
+		return `\`
+	}
+	return "/"
+}
+
+// baseName returns the last component of path.
+func baseName(path string) string {
+	sep := pathSeparator(path)
+	trimmed := strings.TrimRight(path, sep)
+	if trimmed == "" {
+		return path
+	}
+	return trimmed[strings.LastIndex(trimmed, sep)+1:]
+}
+
+// homeRelative replaces a leading home directory with ~.
+func homeRelative(path, home string) string {
+	if home == "" || !strings.HasPrefix(path, home) {
+		return path
+	}
+	rest := path[len(home):]
+	if rest != "" && !strings.HasPrefix(rest, pathSeparator(path)) {
+		return path // e.g. /home/al vs /home/alice
+	}
+	return "~" + rest
+}
+
+// middleEllipsis shortens path to maxLen columns by replacing middle
+// components with …, keeping the first component and as many trailing ones
+// as fit. When even the last component does not fit, it is cut from the
+// left. maxLen <= 0 means no limit.
+func middleEllipsis(path string, maxLen int) string {
+	if maxLen <= 0 || lipgloss.Width(path) <= maxLen {
+		return path
+	}
+	sep := pathSeparator(path)
+	parts := strings.Split(path, sep)
+	// The head is the first named component, along with whatever roots it:
+	// the empty element before an absolute path, ~ or a drive letter.
+	head := 1
+	if (parts[0] == "" || parts[0] == "~" || strings.HasSuffix(parts[0], ":")) && len(parts) > 2 {
+		head = 2
+	}
+	best := ""
+	for i := len(parts) - 1; i >= head; i-- {
+		candidate := strings.Join(parts[:head], sep) + sep + "…" + sep + strings.Join(parts[i:], sep)
+		if lipgloss.Width(candidate) > maxLen {
+			break
+		}
+		best = candidate
+	}
+	if best != "" {
+		return best
+	}
+	runes := []rune(path)
+	keep := maxLen - 1
+	if keep > len(runes) {
+		keep = len(runes)
+	}
+	return "…" + string(runes[len(runes)-keep:])
+}
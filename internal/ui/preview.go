@@ -0,0 +1,78 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/x/ansi"
+	"github.com/seunggabi/claude-dashboard/internal/conversation"
+	"github.com/seunggabi/claude-dashboard/internal/session"
+	"github.com/seunggabi/claude-dashboard/internal/styles"
+)
+
+// PreviewData is what the preview pane shows for the highlighted session:
+// its pane output for tmux sessions, its last messages otherwise.
+type PreviewData struct {
+	Session  *session.Session // nil when no session is highlighted
+	Content  string           // captured pane output
+	Messages []conversation.Message
+	Err      error
+	Loading  bool // nothing fetched yet for Session
+}
+
+// previewChromeRows is the title and rule above the preview content.
+const previewChromeRows = 2
+
+// RenderPreview renders the preview pane in width columns and height rows.
+// Only the end of the output fits, so the bottom is kept.
+func RenderPreview(d PreviewData, width, height int) string {
+	if d.Session == nil {
+		return styles.Muted.Render("No session selected")
+	}
+
+	var b strings.Builder
+	b.WriteString(styles.Title.Render(fmt.Sprintf(" Preview: %s ", d.Session.DisplayName())))
+	b.WriteString("\n")
+	b.WriteString(strings.Repeat("─", width))
+	b.WriteString("\n")
+
+	var lines []string
+	switch {
+	case d.Err != nil:
+		lines = []string{styles.Error.Render(fmt.Sprintf("Error: %v", d.Err))}
+	case d.Loading:
+		lines = []string{styles.Muted.Render("Loading…")}
+	case d.Messages != nil:
+		lines = previewMessageLines(d.Messages, width)
+	default:
+		lines = strings.Split(strings.TrimRight(d.Content, "\n "), "\n")
+	}
+	if len(lines) == 0 || (len(lines) == 1 && lines[0] == "") {
+		lines = []string{styles.Muted.Render("Nothing to show yet.")}
+	}
+	if room := height - previewChromeRows; room > 0 && len(lines) > room {
+		lines = lines[len(lines)-room:]
+	}
+	b.WriteString(strings.Join(lines, "\n"))
+	return b.String()
+}
+
+// previewMessageLines renders messages as a header line each followed by the
+// text wrapped to width.
+func previewMessageLines(msgs []conversation.Message, width int) []string {
+	var lines []string
+	for i, msg := range msgs {
+		if i > 0 {
+			lines = append(lines, "")
+		}
+		header := fmt.Sprintf("%s %s", msg.Role, msg.Timestamp.Format("15:04:05"))
+		if msg.Role == "user" {
+			lines = append(lines, styles.StatusKey.Render("› "+header))
+		} else {
+			lines = append(lines, styles.Active.Render("◆ "+header))
+		}
+		text := ansi.Wrap(strings.TrimSpace(msg.Content), width, "")
+		lines = append(lines, strings.Split(text, "\n")...)
+	}
+	return lines
+}
@@ -0,0 +1,54 @@
+package ui
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/charmbracelet/x/ansi"
+	"github.com/seunggabi/claude-dashboard/internal/conversation"
+	"github.com/seunggabi/claude-dashboard/internal/session"
+)
+
+// ---------------------------------------------------------------------------
+// RenderPreview
+// ---------------------------------------------------------------------------
+
+func TestRenderPreview_keepsTheEndOfLongOutput(t *testing.T) {
+	s := &session.Session{Name: "cd-api"}
+	var lines []string
+	for i := 0; i < 50; i++ {
+		lines = append(lines, strings.Repeat("x", i%5+1))
+	}
+	lines[49] = "last line"
+	out := ansi.Strip(RenderPreview(PreviewData{Session: s, Content: strings.Join(lines, "\n") + "\n\n"}, 40, 10))
+
+	got := strings.Split(out, "\n")
+	if len(got) != 10 {
+		t.Fatalf("expected 10 lines, got %d:\n%s", len(got), out)
+	}
+	if !strings.Contains(got[0], "Preview: api") || got[9] != "last line" {
+		t.Errorf("expected the title and the last line of output, got:\n%s", out)
+	}
+}
+
+func TestRenderPreview_showsMessagesAndErrors(t *testing.T) {
+	s := &session.Session{Name: "cd-api"}
+	ts := time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)
+	msgs := []conversation.Message{
+		{Role: "user", Content: "fix the tests", Timestamp: ts},
+		{Role: "assistant", Content: "Done.", Timestamp: ts},
+	}
+	out := ansi.Strip(RenderPreview(PreviewData{Session: s, Messages: msgs}, 40, 20))
+	for _, want := range []string{"› user 10:00:00", "fix the tests", "◆ assistant 10:00:00", "Done."} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %q in:\n%s", want, out)
+		}
+	}
+
+	out = ansi.Strip(RenderPreview(PreviewData{Session: s, Err: errors.New("no such session")}, 40, 20))
+	if !strings.Contains(out, "Error: no such session") {
+		t.Errorf("expected the error in:\n%s", out)
+	}
+}
@@ -0,0 +1,267 @@
+package ui
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/seunggabi/claude-dashboard/internal/monitor"
+	"github.com/seunggabi/claude-dashboard/internal/session"
+	"github.com/seunggabi/claude-dashboard/internal/styles"
+)
+
+// Activity levels of a pulse cell, weakest first. A cell covering several
+// samples shows the strongest.
+const (
+	pulseNone = iota
+	pulseIdle
+	pulseWaiting
+	pulseActive
+)
+
+// pulseCells renders n cells of an activity level.
+func pulseCells(level, n int) string {
+	switch level {
+	case pulseActive:
+		return lipgloss.NewStyle().Foreground(styles.ColorSuccess).Render(strings.Repeat("█", n))
+	case pulseWaiting:
+		return styles.Waiting.Render(strings.Repeat("▓", n))
+	case pulseIdle:
+		return styles.Muted.Render(strings.Repeat("·", n))
+	}
+	return strings.Repeat(" ", n)
+}
+
+// pulseTerminalCPU is the CPU percentage above which a terminal session,
+// whose status cannot be detected, counts as active.
+const pulseTerminalCPU = 5.0
+
+// pulseMaxGap caps how long one sample counts for when summing active time,
+// so a pause in sampling is not read as a long stretch of activity.
+const pulseMaxGap = time.Minute
+
+const (
+	pulseNameWidth    = 20
+	pulseSummaryWidth = 22
+)
+
+// PulseRow is the sample history of one session in the pulse view.
+type PulseRow struct {
+	Session session.Session
+	Samples []monitor.Sample
+}
+
+// PulseData is what the pulse view shows: every session's activity over
+// the last Window.
+type PulseData struct {
+	Rows   []PulseRow
+	Window time.Duration
+	Now    time.Time
+}
+
+// pulseLevel returns the activity level of a sample.
+func pulseLevel(s monitor.Sample) int {
+	switch session.Status(s.Status) {
+	case session.StatusActive:
+		return pulseActive
+	case session.StatusWaiting:
+		return pulseWaiting
+	case session.StatusTerminal:
+		if s.CPU >= pulseTerminalCPU {
+			return pulseActive
+		}
+	}
+	return pulseIdle
+}
+
+// pulseTimeline spreads samples over n cells in [start, end). Cells before
+// the first sample are empty; later cells without samples repeat the
+// previous level, as the session kept its state between samples.
+func pulseTimeline(samples []monitor.Sample, start, end time.Time, n int) []int {
+	out := make([]int, n)
+	span := end.Sub(start)
+	if n == 0 || span <= 0 {
+		return out
+	}
+	seen := make([]bool, n)
+	for _, s := range samples {
+		i := bucketIndex(s.Time, start, span, n)
+		if i < 0 {
+			continue
+		}
+		seen[i] = true
+		if l := pulseLevel(s); l > out[i] {
+			out[i] = l
+		}
+	}
+	last := pulseNone
+	for i := range out {
+		if seen[i] {
+			last = out[i]
+		} else {
+			out[i] = last
+		}
+	}
+	return out
+}
+
+// activeTime sums the time a session spent active, counting each active
+// sample until the next one (or now for the latest).
+func activeTime(samples []monitor.Sample, now time.Time) time.Duration {
+	var total time.Duration
+	for i, s := range samples {
+		if pulseLevel(s) != pulseActive {
+			continue
+		}
+		next := now
+		if i+1 < len(samples) {
+			next = samples[i+1].Time
+		}
+		gap := next.Sub(s.Time)
+		if gap > pulseMaxGap {
+			gap = pulseMaxGap
+		}
+		if gap > 0 {
+			total += gap
+		}
+	}
+	return total
+}
+
+// unattended reports whether a session is busy or waiting for input while
+// nobody has been attached to it during the sampled period.
+func unattended(samples []monitor.Sample) bool {
+	if len(samples) == 0 || pulseLevel(samples[len(samples)-1]) < pulseWaiting {
+		return false
+	}
+	for _, s := range samples {
+		if s.Attached {
+			return false
+		}
+	}
+	return true
+}
+
+// RenderPulse renders one activity timeline per session over the last
+// Window, busiest session first, flagging sessions that are working or
+// waiting with nobody attached.
+func RenderPulse(d PulseData, width, height int) string {
+	var b strings.Builder
+
+	b.WriteString(styles.Title.Render(" Pulse "))
+	b.WriteString("  " + styles.Muted.Render(fmt.Sprintf("last %s · %d sessions", formatWindow(d.Window), len(d.Rows))))
+	b.WriteString("\n")
+	b.WriteString(strings.Repeat("─", width))
+	b.WriteString("\n")
+
+	if len(d.Rows) == 0 {
+		b.WriteString(styles.Muted.Render("  No sessions."))
+		return b.String()
+	}
+
+	cols := width - pulseNameWidth - pulseSummaryWidth - 2
+	if cols < 10 {
+		cols = 10
+	}
+	start := d.Now.Add(-d.Window)
+
+	type row struct {
+		PulseRow
+		active time.Duration
+	}
+	rows := make([]row, len(d.Rows))
+	for i, r := range d.Rows {
+		rows[i] = row{PulseRow: r, active: activeTime(r.Samples, d.Now)}
+	}
+	sort.SliceStable(rows, func(i, j int) bool {
+		if rows[i].active != rows[j].active {
+			return rows[i].active > rows[j].active
+		}
+		return rows[i].Session.Name < rows[j].Session.Name
+	})
+
+	// Title, rule, axis and legend take four lines.
+	visible := height - 4
+	if visible < 1 {
+		visible = 1
+	}
+	hidden := 0
+	if len(rows) > visible {
+		// Keep a line for the count of rows that did not fit.
+		visible--
+		if visible < 1 {
+			visible = 1
+		}
+		hidden = len(rows) - visible
+		rows = rows[:visible]
+	}
+
+	for _, r := range rows {
+		name := r.Session.DisplayName()
+		if r.Session.Host != "" {
+			name += "@" + r.Session.Host
+		}
+		b.WriteString(fmt.Sprintf("  %-*s", pulseNameWidth-2, truncate(name, pulseNameWidth-3)))
+		// Runs of one level are styled together to keep the output small.
+		cells := pulseTimeline(r.Samples, start, d.Now, cols)
+		for i := 0; i < len(cells); {
+			j := i
+			for j < len(cells) && cells[j] == cells[i] {
+				j++
+			}
+			b.WriteString(pulseCells(cells[i], j-i))
+			i = j
+		}
+		b.WriteString("  ")
+		if len(r.Samples) == 0 {
+			b.WriteString(styles.Muted.Render("no samples yet"))
+		} else {
+			b.WriteString(fmt.Sprintf("%-10s", formatActive(r.active)+" act"))
+			if unattended(r.Samples) {
+				b.WriteString(styles.Waiting.Render("unattended"))
+			}
+		}
+		b.WriteString("\n")
+	}
+	if hidden > 0 {
+		b.WriteString(styles.Muted.Render(fmt.Sprintf("  … %d more", hidden)))
+		b.WriteString("\n")
+	}
+
+	b.WriteString(strings.Repeat(" ", pulseNameWidth))
+	b.WriteString(styles.Muted.Render(pulseAxis(d.Window, cols)))
+	b.WriteString("\n")
+	b.WriteString(styles.Muted.Render("  legend: "))
+	b.WriteString(pulseCells(pulseActive, 1) + " active  ")
+	b.WriteString(pulseCells(pulseWaiting, 1) + " waiting  ")
+	b.WriteString(pulseCells(pulseIdle, 1) + " idle")
+
+	return b.String()
+}
+
+// pulseAxis labels the start, middle and end of a timeline cols wide.
+func pulseAxis(window time.Duration, cols int) string {
+	left := "-" + formatWindow(window)
+	mid := "-" + formatWindow(window/2)
+	right := "now"
+	axis := []byte(strings.Repeat(" ", cols))
+	copy(axis, left)
+	if m := cols/2 - len(mid)/2; m > len(left) && m+len(mid) < cols-len(right) {
+		copy(axis[m:], mid)
+	}
+	if cols >= len(left)+len(right)+1 {
+		copy(axis[cols-len(right):], right)
+	}
+	return string(axis)
+}
+
+// formatActive formats an active time as e.g. "0m", "34m" or "1h05m".
+func formatActive(d time.Duration) string {
+	m := int(d.Round(time.Minute).Minutes())
+	if m >= 60 {
+		return fmt.Sprintf("%dh%02dm", m/60, m%60)
+	}
+	return fmt.Sprintf("%dm", m)
+}
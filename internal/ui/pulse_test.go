@@ -0,0 +1,98 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/charmbracelet/x/ansi"
+	"github.com/seunggabi/claude-dashboard/internal/monitor"
+	"github.com/seunggabi/claude-dashboard/internal/session"
+)
+
+// ---------------------------------------------------------------------------
+// pulseTimeline / activeTime / unattended
+// ---------------------------------------------------------------------------
+
+func TestPulseTimeline_strongestLevelWinsAndGapsCarryForward(t *testing.T) {
+	start := time.Unix(1700000000, 0)
+	end := start.Add(4 * time.Minute)
+	samples := []monitor.Sample{
+		{Time: start.Add(70 * time.Second), Status: "idle"},
+		{Time: start.Add(80 * time.Second), Status: "active"},
+		{Time: start.Add(150 * time.Second), Status: "waiting"},
+	}
+	got := pulseTimeline(samples, start, end, 4)
+	want := []int{pulseNone, pulseActive, pulseWaiting, pulseWaiting}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("expected %v, got %v", want, got)
+		}
+	}
+}
+
+func TestPulseLevel_terminalSessionActiveByCPU(t *testing.T) {
+	busy := monitor.Sample{Status: string(session.StatusTerminal), CPU: 40}
+	quiet := monitor.Sample{Status: string(session.StatusTerminal), CPU: 0.5}
+	if pulseLevel(busy) != pulseActive {
+		t.Error("expected a busy terminal session to count as active")
+	}
+	if pulseLevel(quiet) != pulseIdle {
+		t.Error("expected a quiet terminal session to count as idle")
+	}
+}
+
+func TestActiveTime_capsGapsBetweenSamples(t *testing.T) {
+	start := time.Unix(1700000000, 0)
+	samples := []monitor.Sample{
+		{Time: start, Status: "active"},
+		{Time: start.Add(30 * time.Second), Status: "idle"},
+		{Time: start.Add(40 * time.Second), Status: "active"},
+	}
+	got := activeTime(samples, start.Add(10*time.Minute))
+	if want := 30*time.Second + pulseMaxGap; got != want {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+}
+
+func TestUnattended_busyWithNobodyAttached(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	samples := []monitor.Sample{{Time: now, Status: "active"}}
+	if !unattended(samples) {
+		t.Error("expected an active session nobody attached to be unattended")
+	}
+	samples = append(samples, monitor.Sample{Time: now, Status: "active", Attached: true})
+	if unattended(samples) {
+		t.Error("expected an attached session not to be unattended")
+	}
+	if unattended([]monitor.Sample{{Time: now, Status: "idle"}}) {
+		t.Error("expected an idle session not to be unattended")
+	}
+}
+
+// ---------------------------------------------------------------------------
+// RenderPulse
+// ---------------------------------------------------------------------------
+
+func TestRenderPulse_busiestSessionFirst(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	sample := func(status string) []monitor.Sample {
+		return []monitor.Sample{{Time: now.Add(-time.Minute), Status: status}}
+	}
+	d := PulseData{
+		Rows: []PulseRow{
+			{Session: session.Session{Name: "cd-quiet"}, Samples: sample("idle")},
+			{Session: session.Session{Name: "cd-busy"}, Samples: sample("active")},
+		},
+		Window: time.Hour,
+		Now:    now,
+	}
+	out := ansi.Strip(RenderPulse(d, 100, 20))
+	busy, quiet := strings.Index(out, "busy"), strings.Index(out, "quiet")
+	if busy < 0 || quiet < 0 || busy > quiet {
+		t.Errorf("expected busy listed before quiet, got:\n%s", out)
+	}
+	if !strings.Contains(out, "unattended") {
+		t.Errorf("expected the busy session flagged unattended, got:\n%s", out)
+	}
+}
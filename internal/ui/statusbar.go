@@ -2,13 +2,65 @@ package ui
 
 import (
 	"fmt"
+	"strings"
+	"time"
 
 	"github.com/charmbracelet/lipgloss"
+	"github.com/seunggabi/claude-dashboard/internal/config"
+	"github.com/seunggabi/claude-dashboard/internal/session"
 	"github.com/seunggabi/claude-dashboard/internal/styles"
 )
 
-// StatusBar renders the bottom status bar.
-func StatusBar(width int, sessionCount int, view string, filter string) string {
+// RefreshState describes how session refreshes are going, so a list that
+// is not changing can be told apart from one that is failing to update.
+type RefreshState struct {
+	Running  bool      // a refresh is in flight
+	Last     time.Time // last successful refresh; zero before the first
+	Failures int       // failed refreshes since Last
+}
+
+// refreshIndicator renders the refresh part of the status bar, e.g.
+// "↻ Updated: 14:03:05  ✗ 2 failed".
+func refreshIndicator(r RefreshState) string {
+	spin := " "
+	if r.Running {
+		spin = "↻"
+	}
+	last := "—"
+	if !r.Last.IsZero() {
+		last = r.Last.Format("15:04:05")
+	}
+	s := styles.StatusKey.Render(spin+" Updated: ") + styles.StatusVal.Render(last)
+	if r.Failures > 0 {
+		s += "  " + styles.Error.Render(fmt.Sprintf("✗ %d failed", r.Failures))
+	}
+	return s
+}
+
+// QuickViews are the numbered filters of the dashboard; Active is the
+// index of the one applied, or -1.
+type QuickViews struct {
+	Views  []config.QuickView
+	Active int
+}
+
+// render lists the views with their keys, e.g. "1 waiting 2 active ▸3 mine".
+func (q QuickViews) render() string {
+	parts := make([]string, len(q.Views))
+	for i, v := range q.Views {
+		key := fmt.Sprintf("%d", i+1)
+		if i == q.Active {
+			parts[i] = styles.StatusKey.Render("▸"+key+" ") + styles.StatusVal.Render(v.Name)
+		} else {
+			parts[i] = styles.StatusKey.Render(key+" ") + styles.Muted.Render(v.Name)
+		}
+	}
+	return strings.Join(parts, " ")
+}
+
+// StatusBar renders the bottom status bar. The quick views are left out
+// when they do not fit.
+func StatusBar(width int, sessionCount int, view string, filter string, host string, refresh RefreshState, quick QuickViews) string {
 	left := styles.StatusKey.Render("Sessions: ") +
 		styles.StatusVal.Render(fmt.Sprintf("%d", sessionCount))
 
@@ -17,8 +69,15 @@ func StatusBar(width int, sessionCount int, view string, filter string) string {
 			styles.StatusVal.Render(filter)
 	}
 
-	right := styles.StatusKey.Render("View: ") +
+	if host != "" {
+		left += "  " + styles.StatusKey.Render("Host: ") +
+			styles.StatusVal.Render(host)
+	}
+	right := refreshIndicator(refresh) + "  " + styles.StatusKey.Render("View: ") +
 		styles.StatusVal.Render(view)
+	if views := quick.render(); views != "" && lipgloss.Width(left)+lipgloss.Width(right)+lipgloss.Width(views)+4 <= width {
+		left += "  " + views
+	}
 
 	gap := width - lipgloss.Width(left) - lipgloss.Width(right)
 	if gap < 0 {
@@ -30,24 +89,53 @@ func StatusBar(width int, sessionCount int, view string, filter string) string {
 	return styles.StatusBar.Width(width).Render(bar)
 }
 
+// HostRollup renders per-host session counts for the header, e.g.
+// "local 3 │ devbox 2 │ gpu ✗". The host selected by filter is highlighted.
+func HostRollup(hosts []session.HostStatus, filter string) string {
+	parts := make([]string, 0, len(hosts))
+	for _, h := range hosts {
+		var part string
+		if h.Pending {
+			part = h.Name + " " + styles.Muted.Render("…")
+		} else if h.Reachable() {
+			part = h.Name + " " + styles.StatusVal.Render(fmt.Sprintf("%d", h.Sessions))
+		} else {
+			part = h.Name + " " + styles.Error.Render("✗")
+		}
+		if h.Name == filter {
+			part = styles.StatusKey.Render("▸") + part
+		}
+		parts = append(parts, part)
+	}
+	return strings.Join(parts, styles.Muted.Render(" │ "))
+}
+
 // HelpBar renders the key hints at the bottom.
 func HelpBar(width int, context string) string {
 	var hints string
 	switch context {
 	case "dashboard":
-		hints = "↑/↓:nav  enter:attach  l:logs  d:detail  n:new  K:kill  ^k:kill-idle  ^s:save(attached)  /:filter  ?:help  q:quit"
+		hints = "↑/↓:nav  enter:attach  l:logs  C:conversations  d:detail  t:test  tab:preview  m:monitor  P:pulse  A:archive  v:density  n:new  p:prompt  #:tag  I:issue  o:open issue  K:kill  ^k:kill-idle  ^r:restart  R:restore  ^s:save(attached)  /:filter  1-9:views  H:host  u/U:undo/redo  ?:help  q:quit"
 	case "logs":
-		hints = "↑/↓/j/k:scroll  pgup/pgdn:page  esc:back  q:quit"
+		hints = "↑/↓/j/k:scroll  pgup/pgdn:page  [/]:msg  u/a:user/asst  R/T:role/today  /:find  n/N:match  F:follow  r:raw/md  enter/E:expand  y:copy msg  e:export  esc:back  q:quit"
 	case "detail":
-		hints = "esc:back  l:logs  K:kill  q:quit"
+		hints = "esc:back  l:logs  o:open issue  K:kill  q:quit"
 	case "create":
-		hints = "tab:next  enter:create  esc:cancel"
+		hints = "tab:next  ↑↓:suggestions  ←→:model  enter:pick/create  esc:cancel"
 	case "confirm":
 		hints = "y:confirm  n:cancel"
 	case "help":
 		hints = "esc:close  q:quit"
+	case "monitor", "pulse":
+		hints = "w:window (5m/15m/1h)  esc:back  q:quit"
 	case "filter":
 		hints = "enter:apply  esc:clear"
+	case "summary":
+		hints = "any key:close"
+	case "conversations":
+		hints = "↑/↓:nav  enter:read  esc:back  q:quit"
+	case "archive":
+		hints = "↑/↓:nav  enter:restore  c:conversation  l:pane history  /:search  esc:back  q:quit"
 	default:
 		hints = "?:help  q:quit"
 	}
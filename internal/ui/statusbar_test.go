@@ -0,0 +1,98 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/charmbracelet/x/ansi"
+	"github.com/seunggabi/claude-dashboard/internal/config"
+	"github.com/seunggabi/claude-dashboard/internal/session"
+)
+
+// ---------------------------------------------------------------------------
+// HostRollup
+// ---------------------------------------------------------------------------
+
+func TestHostRollup_showsCountsAndUnreachableMarker(t *testing.T) {
+	got := HostRollup([]session.HostStatus{
+		{Name: "local", Sessions: 3},
+		{Name: "devbox", Sessions: 2},
+		{Name: "gpu", Err: fmt.Errorf("ssh: timeout")},
+	}, "")
+	for _, want := range []string{"local", "3", "devbox", "2", "gpu", "✗"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("expected %q in rollup %q", want, got)
+		}
+	}
+}
+
+func TestHostRollup_pendingHostShowsNoCount(t *testing.T) {
+	got := HostRollup([]session.HostStatus{{Name: "local", Sessions: 1}, {Name: "devbox", Pending: true}}, "")
+	if !strings.Contains(got, "devbox") || !strings.Contains(got, "…") || strings.Contains(got, "devbox 0") {
+		t.Errorf("expected devbox pending in rollup %q", got)
+	}
+}
+
+func TestHostRollup_marksFilteredHost(t *testing.T) {
+	got := HostRollup([]session.HostStatus{{Name: "local"}, {Name: "devbox"}}, "devbox")
+	if !strings.Contains(got, "▸") {
+		t.Errorf("expected filtered host marker in %q", got)
+	}
+}
+
+// ---------------------------------------------------------------------------
+// StatusBar
+// ---------------------------------------------------------------------------
+
+func TestStatusBar_showsLastRefreshAndFailures(t *testing.T) {
+	last := time.Date(2025, 1, 1, 14, 3, 5, 0, time.Local)
+	got := ansi.Strip(StatusBar(120, 2, "dashboard", "", "", RefreshState{Last: last, Failures: 3}, QuickViews{}))
+	if !strings.Contains(got, "Updated: 14:03:05") {
+		t.Errorf("expected last refresh time in %q", got)
+	}
+	if !strings.Contains(got, "✗ 3 failed") {
+		t.Errorf("expected failure count in %q", got)
+	}
+	if strings.Contains(got, "↻") {
+		t.Errorf("expected no running indicator in %q", got)
+	}
+}
+
+func TestStatusBar_quickViewsMarkActiveAndDropWhenNarrow(t *testing.T) {
+	quick := QuickViews{Views: []config.QuickView{{Name: "waiting"}, {Name: "active"}}, Active: 1}
+	got := ansi.Strip(StatusBar(120, 2, "dashboard", "status:active", "", RefreshState{}, quick))
+	if !strings.Contains(got, "1 waiting ▸2 active") {
+		t.Errorf("expected the views with the active one marked in %q", got)
+	}
+	if got := ansi.Strip(StatusBar(60, 2, "dashboard", "status:active", "", RefreshState{}, quick)); strings.Contains(got, "waiting") {
+		t.Errorf("expected no views when they do not fit in %q", got)
+	}
+}
+
+func TestStatusBar_runningBeforeFirstRefresh(t *testing.T) {
+	got := ansi.Strip(StatusBar(120, 0, "dashboard", "", "", RefreshState{Running: true}, QuickViews{}))
+	if !strings.Contains(got, "↻ Updated: —") {
+		t.Errorf("expected running indicator with no timestamp in %q", got)
+	}
+	if strings.Contains(got, "failed") {
+		t.Errorf("expected no failure count in %q", got)
+	}
+}
+
+// ---------------------------------------------------------------------------
+// HelpBar
+// ---------------------------------------------------------------------------
+
+func TestHelpBar_logsHintsMatchTheKeys(t *testing.T) {
+	got := ansi.Strip(HelpBar(400, "logs"))
+	for _, want := range []string{"enter/E:expand", "e:export"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("expected %q in %q", want, got)
+		}
+	}
+	if strings.Contains(got, "x:export") {
+		t.Errorf("expected no hint for the old export key in %q", got)
+	}
+}
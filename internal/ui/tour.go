@@ -0,0 +1,83 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/seunggabi/claude-dashboard/internal/styles"
+)
+
+// TourAnchor is the part of the dashboard a tour step points at, which
+// decides where its card is drawn.
+type TourAnchor int
+
+const (
+	TourCenter    TourAnchor = iota // the dashboard as a whole
+	TourTable                       // the session table, under its header
+	TourStatusBar                   // the status and help lines at the bottom
+)
+
+// TourStep is one card of the onboarding tour.
+type TourStep struct {
+	Title  string
+	Body   string
+	Anchor TourAnchor
+}
+
+// TourSteps are the cards of the onboarding tour shown on the first run.
+var TourSteps = []TourStep{
+	{"Welcome", "claude-dashboard lists every Claude Code session in tmux, in terminals and on your remote hosts, and shows which need you.", TourCenter},
+	{"The session table", "Each row is a session with its status, CPU, memory and uptime. ↑/↓ move, enter attaches (ctrl+b d comes back), d opens the detail view and tab a live preview.", TourTable},
+	{"Keys", "n starts a session, p sends it a prompt, l reads its conversation and K kills it. / filters, also by field (tag:api status:waiting), 1-9 switch to saved views and u undoes view changes.", TourCenter},
+	{"The status bar", "The bottom lines show the view, filter and last refresh, then the keys of the view you are in.", TourStatusBar},
+	{"That's it", "? lists every key at any time. This tour is not shown again; set tour_done: false in config.yaml to see it once more.", TourCenter},
+}
+
+// tourCardWidth is the widest a tour card is drawn.
+const tourCardWidth = 64
+
+// RenderTourCard renders step (0-based) of TourSteps as a bordered card,
+// with a pointer toward what it describes.
+func RenderTourCard(step, width int) string {
+	s := TourSteps[step]
+	w := min(tourCardWidth, width-4)
+
+	title := styles.StatusKey.Render(s.Title) + styles.Muted.Render(fmt.Sprintf("  %d/%d", step+1, len(TourSteps)))
+	keys := "→/enter next  ← back  esc skip"
+	if step == len(TourSteps)-1 {
+		keys = "enter done  ← back"
+	}
+	body := lipgloss.NewStyle().Width(w - 4).Render(s.Body)
+	card := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(styles.ColorPrimary).
+		Padding(0, 1).
+		Width(w - 2).
+		Render(title + "\n" + body + "\n" + styles.Help.Render(keys))
+
+	pad := strings.Repeat(" ", max((width-lipgloss.Width(card))/2, 0))
+	var b strings.Builder
+	if s.Anchor == TourTable {
+		b.WriteString(pad + styles.StatusKey.Render("  ▲") + "\n")
+	}
+	for _, line := range strings.Split(card, "\n") {
+		b.WriteString(pad + line + "\n")
+	}
+	if s.Anchor == TourStatusBar {
+		b.WriteString(pad + styles.StatusKey.Render("  ▼") + "\n")
+	}
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+// Overlay draws card over the lines of base from row on, replacing them
+// whole; lines past the end of base are not added.
+func Overlay(base, card string, row int) string {
+	lines := strings.Split(base, "\n")
+	for i, line := range strings.Split(card, "\n") {
+		if row+i >= 0 && row+i < len(lines) {
+			lines[row+i] = line
+		}
+	}
+	return strings.Join(lines, "\n")
+}
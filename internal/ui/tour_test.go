@@ -0,0 +1,41 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// ---------------------------------------------------------------------------
+// RenderTourCard
+// ---------------------------------------------------------------------------
+
+func TestRenderTourCard_countsStepsAndFitsWidth(t *testing.T) {
+	for step := range TourSteps {
+		card := RenderTourCard(step, 60)
+		if !strings.Contains(card, TourSteps[step].Title) {
+			t.Errorf("step %d: expected its title, got:\n%s", step, card)
+		}
+		if w := lipgloss.Width(card); w > 60 {
+			t.Errorf("step %d: expected at most 60 columns, got %d", step, w)
+		}
+	}
+	if card := RenderTourCard(1, 100); !strings.Contains(card, "2/5") || !strings.Contains(card, "▲") {
+		t.Errorf("expected the table step to count and point up, got:\n%s", card)
+	}
+	if card := RenderTourCard(len(TourSteps)-1, 100); !strings.Contains(card, "enter done") {
+		t.Errorf("expected the last step to offer done, got:\n%s", card)
+	}
+}
+
+// ---------------------------------------------------------------------------
+// Overlay
+// ---------------------------------------------------------------------------
+
+func TestOverlay_replacesLinesWithinBase(t *testing.T) {
+	got := Overlay("a\nb\nc\nd", "X\nY\nZ", 2)
+	if got != "a\nb\nX\nY" {
+		t.Errorf("expected the card cut at the end of base, got %q", got)
+	}
+}
@@ -0,0 +1,34 @@
+package usage
+
+import "strings"
+
+// DefaultContextWindow is the context window, in tokens, of models not in
+// contextWindows: every current Claude model has one this size.
+const DefaultContextWindow = 200_000
+
+// LongContextWindow is the context window of models run with the 1M-token
+// context, which the logs only tell apart by a prompt larger than the
+// model's usual window.
+const LongContextWindow = 1_000_000
+
+// contextWindows maps model ID prefixes to the context windows of models
+// that differ from DefaultContextWindow.
+var contextWindows = map[string]int{
+	"claude-2.0":     100_000,
+	"claude-instant": 100_000,
+}
+
+// ContextWindow returns the context window of model, matched by longest
+// known prefix, for a conversation whose prompt has reached used tokens.
+func ContextWindow(model string, used int) int {
+	size, best := DefaultContextWindow, ""
+	for prefix, n := range contextWindows {
+		if strings.HasPrefix(model, prefix) && len(prefix) > len(best) {
+			size, best = n, prefix
+		}
+	}
+	if used > size {
+		return LongContextWindow
+	}
+	return size
+}
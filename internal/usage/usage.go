@@ -0,0 +1,44 @@
+// Package usage totals the tokens and spend of every conversation over a
+// period, such as today for the dashboard header.
+package usage
+
+import (
+	"context"
+	"time"
+
+	"github.com/seunggabi/claude-dashboard/internal/conversation"
+)
+
+// Totals is the use of all conversations in a period.
+type Totals struct {
+	From          time.Time
+	To            time.Time
+	Conversations int // conversations with activity in the period
+	Spend         conversation.Spend
+}
+
+// source reads the conversation activity of a period; tests replace it.
+type source func(ctx context.Context, from, to time.Time) ([]conversation.Activity, error)
+
+// Between totals the conversations logged under conversation.ProjectsDir
+// between from and to.
+func Between(ctx context.Context, from, to time.Time) (Totals, error) {
+	return between(ctx, conversation.Activities, from, to)
+}
+
+// Today totals the day of now up to now.
+func Today(ctx context.Context, now time.Time) (Totals, error) {
+	return Between(ctx, conversation.StartOfDay(now), now)
+}
+
+func between(ctx context.Context, read source, from, to time.Time) (Totals, error) {
+	activities, err := read(ctx, from, to)
+	if err != nil {
+		return Totals{}, err
+	}
+	t := Totals{From: from, To: to, Conversations: len(activities)}
+	for _, a := range activities {
+		t.Spend = t.Spend.Plus(a.Spend)
+	}
+	return t, nil
+}
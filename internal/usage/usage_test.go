@@ -0,0 +1,68 @@
+package usage
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/seunggabi/claude-dashboard/internal/conversation"
+)
+
+// ---------------------------------------------------------------------------
+// Between
+// ---------------------------------------------------------------------------
+
+func TestBetween_sumsEveryConversation(t *testing.T) {
+	from := time.Date(2025, 11, 24, 0, 0, 0, 0, time.UTC)
+	to := from.Add(10 * time.Hour)
+	read := func(_ context.Context, gotFrom, gotTo time.Time) ([]conversation.Activity, error) {
+		if !gotFrom.Equal(from) || !gotTo.Equal(to) {
+			t.Errorf("expected %s..%s, got %s..%s", from, to, gotFrom, gotTo)
+		}
+		return []conversation.Activity{
+			{Dir: "/src/api", Spend: conversation.Spend{Cost: 1.5, Tokens: 1000}},
+			{Dir: "/src/web", Spend: conversation.Spend{Cost: 0.25, Tokens: 500, Unpriced: 2}},
+		}, nil
+	}
+
+	got, err := between(context.Background(), read, from, to)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := Totals{From: from, To: to, Conversations: 2, Spend: conversation.Spend{Cost: 1.75, Tokens: 1500, Unpriced: 2}}
+	if got != want {
+		t.Errorf("expected %+v, got %+v", want, got)
+	}
+}
+
+func TestBetween_passesOnReadErrors(t *testing.T) {
+	read := func(context.Context, time.Time, time.Time) ([]conversation.Activity, error) {
+		return nil, errors.New("permission denied")
+	}
+	if _, err := between(context.Background(), read, time.Now(), time.Now()); err == nil {
+		t.Error("expected an error")
+	}
+}
+
+// ---------------------------------------------------------------------------
+// ContextWindow
+// ---------------------------------------------------------------------------
+
+func TestContextWindow(t *testing.T) {
+	tests := []struct {
+		model string
+		used  int
+		want  int
+	}{
+		{"claude-sonnet-4-5-20250929", 150_000, 200_000},
+		{"claude-instant-1.2", 0, 100_000},
+		{"some-new-model", 0, DefaultContextWindow},
+		{"claude-sonnet-4-5", 300_000, LongContextWindow},
+	}
+	for _, tt := range tests {
+		if got := ContextWindow(tt.model, tt.used); got != tt.want {
+			t.Errorf("ContextWindow(%q, %d) = %d, want %d", tt.model, tt.used, got, tt.want)
+		}
+	}
+}
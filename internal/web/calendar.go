@@ -0,0 +1,28 @@
+package web
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+)
+
+// CalendarFunc writes recent session activity as an iCalendar file.
+type CalendarFunc func(ctx context.Context, w io.Writer) error
+
+// EnableCalendar serves the feed written by fn at /calendar.ics, for
+// calendar apps to subscribe to. With a token, the feed URL carries it as
+// ?token=.
+func (s *Server) EnableCalendar(fn CalendarFunc) {
+	s.calendar = fn
+}
+
+func (s *Server) handleCalendar(w http.ResponseWriter, r *http.Request) {
+	var buf bytes.Buffer
+	if err := s.calendar(r.Context(), &buf); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "text/calendar; charset=utf-8")
+	w.Write(buf.Bytes())
+}
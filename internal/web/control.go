@@ -0,0 +1,136 @@
+package web
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/seunggabi/claude-dashboard/internal/session"
+)
+
+// Controller changes sessions on this machine for the control API;
+// *session.Manager is one.
+type Controller interface {
+	Create(ctx context.Context, name, projectDir, claudeArgs string) error
+	Kill(ctx context.Context, name string) error
+	SendCommand(ctx context.Context, name, text string) error
+}
+
+// maxBody is the largest request body the control API reads.
+const maxBody = 64 << 10
+
+// createRequest is the body of POST /api/sessions. Name is without the
+// session prefix, as for the new command.
+type createRequest struct {
+	Name string `json:"name"`
+	Path string `json:"path"`
+	Args string `json:"args"`
+}
+
+// sendRequest is the body of POST /api/sessions/{name}/send.
+type sendRequest struct {
+	Prompt string `json:"prompt"`
+}
+
+// ErrNoToken is returned by EnableControl when the server has no token.
+var ErrNoToken = errors.New("the control API needs a token")
+
+// EnableControl adds the control API, which creates, kills and prompts
+// sessions on this machine with c. It needs a token, since anyone reaching
+// it can run Claude.
+func (s *Server) EnableControl(c Controller) error {
+	if s.token == "" {
+		return ErrNoToken
+	}
+	s.control = c
+	return nil
+}
+
+func (s *Server) handleCreate(w http.ResponseWriter, r *http.Request) {
+	var req createRequest
+	if !decodeBody(w, r, &req) {
+		return
+	}
+	if req.Name == "" {
+		http.Error(w, "name is required", http.StatusBadRequest)
+		return
+	}
+	name := session.SessionPrefix + req.Name
+	if s.find(r.Context(), "", name) != nil {
+		http.Error(w, fmt.Sprintf("session %s already exists", name), http.StatusConflict)
+		return
+	}
+	if err := s.control.Create(r.Context(), req.Name, req.Path, req.Args); err != nil {
+		controlError(w, err, http.StatusBadRequest)
+		return
+	}
+	s.poll(r.Context())
+	view := sessionView{Name: name, Host: session.LocalHost}
+	if found := s.find(r.Context(), "", name); found != nil {
+		view = views([]session.Session{*found})[0]
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(view)
+}
+
+func (s *Server) handleKill(w http.ResponseWriter, r *http.Request) {
+	found := s.find(r.Context(), "", r.PathValue("name"))
+	switch {
+	case found == nil:
+		http.Error(w, fmt.Sprintf("session %s not found", r.PathValue("name")), http.StatusNotFound)
+		return
+	case !found.Managed:
+		http.Error(w, "terminal sessions cannot be killed", http.StatusForbidden)
+		return
+	}
+	if err := s.control.Kill(r.Context(), found.Name); err != nil {
+		controlError(w, err, http.StatusInternalServerError)
+		return
+	}
+	s.poll(r.Context())
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) handleSend(w http.ResponseWriter, r *http.Request) {
+	var req sendRequest
+	if !decodeBody(w, r, &req) {
+		return
+	}
+	found := s.find(r.Context(), "", r.PathValue("name"))
+	switch {
+	case found == nil:
+		http.Error(w, fmt.Sprintf("session %s not found", r.PathValue("name")), http.StatusNotFound)
+		return
+	case !found.Managed:
+		http.Error(w, "terminal sessions cannot be sent prompts", http.StatusForbidden)
+		return
+	}
+	if err := s.control.SendCommand(r.Context(), found.Name, req.Prompt); err != nil {
+		controlError(w, err, http.StatusBadRequest)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// decodeBody reads a JSON request body into v, answering 400 when it
+// cannot.
+func decodeBody(w http.ResponseWriter, r *http.Request, v any) bool {
+	dec := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxBody))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(v); err != nil {
+		http.Error(w, "invalid JSON body: "+err.Error(), http.StatusBadRequest)
+		return false
+	}
+	return true
+}
+
+// controlError answers a failed change with code, or 503 without tmux.
+func controlError(w http.ResponseWriter, err error, code int) {
+	if errors.Is(err, session.ErrNoTmux) {
+		code = http.StatusServiceUnavailable
+	}
+	http.Error(w, err.Error(), code)
+}
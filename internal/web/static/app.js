@@ -0,0 +1,112 @@
+// Read-only view of claude-dashboard sessions. The session list arrives as
+// "sessions" server-sent events; a tapped session shows its conversation
+// tail, refetched whenever the list changes.
+"use strict";
+
+const token = new URLSearchParams(location.search).get("token");
+const list = document.getElementById("sessions");
+const state = document.getElementById("state");
+const empty = document.getElementById("empty");
+const template = document.getElementById("session");
+const open = new Set(); // keys of sessions showing their tail
+
+function api(path) {
+  return token ? `${path}${path.includes("?") ? "&" : "?"}token=${encodeURIComponent(token)}` : path;
+}
+
+function key(s) {
+  return `${s.host}/${s.name}`;
+}
+
+function since(time) {
+  const mins = Math.max(0, Math.floor((Date.now() - new Date(time)) / 60000));
+  if (mins < 60) return `${mins}m`;
+  if (mins < 1440) return `${Math.floor(mins / 60)}h${mins % 60}m`;
+  return `${Math.floor(mins / 1440)}d${Math.floor(mins / 60) % 24}h`;
+}
+
+function render(sessions) {
+  empty.hidden = sessions.length > 0;
+  const items = sessions.map((s) => {
+    const k = key(s);
+    let li = list.querySelector(`[data-key="${CSS.escape(k)}"]`);
+    if (!li) {
+      li = template.content.firstElementChild.cloneNode(true);
+      li.dataset.key = k;
+      li.querySelector(".summary").addEventListener("click", () => toggle(li));
+    }
+    li.className = `session ${s.status}`;
+    li.querySelector(".status").textContent = s.status;
+    li.querySelector(".name").textContent = s.host === "local" ? s.name : `${s.host}:${s.name}`;
+    const meta = [s.project, since(s.started)];
+    if (s.branch) meta.push(s.branch);
+    if (s.attached) meta.push("attached");
+    if (!s.managed) meta.push("terminal");
+    meta.push(s.path);
+    li.querySelector(".meta").textContent = meta.join(" · ");
+    li.querySelector(".prompt").textContent = s.last_prompt || "";
+    li.host = s.host;
+    li.sessionName = s.name;
+    if (open.has(k)) loadTail(li);
+    return li;
+  });
+  list.replaceChildren(...items);
+}
+
+function toggle(li) {
+  const tail = li.querySelector(".tail");
+  if (open.delete(li.dataset.key)) {
+    tail.hidden = true;
+    return;
+  }
+  open.add(li.dataset.key);
+  tail.hidden = false;
+  loadTail(li);
+}
+
+async function loadTail(li) {
+  const tail = li.querySelector(".tail");
+  const path = `/api/sessions/${encodeURIComponent(li.host)}/${encodeURIComponent(li.sessionName)}/tail`;
+  try {
+    const res = await fetch(api(path));
+    if (!res.ok) throw new Error((await res.text()).trim());
+    const messages = await res.json();
+    if (messages.length === 0) {
+      tail.replaceChildren(note("No conversation messages yet."));
+      return;
+    }
+    tail.replaceChildren(...messages.map((m) => {
+      const item = document.createElement("li");
+      item.className = m.role;
+      const role = document.createElement("span");
+      role.className = "role";
+      role.textContent = m.role;
+      item.append(role, m.content);
+      return item;
+    }));
+  } catch (err) {
+    tail.replaceChildren(note(err.message, "error"));
+  }
+}
+
+function note(text, cls = "muted") {
+  const item = document.createElement("li");
+  item.className = cls;
+  item.textContent = text;
+  return item;
+}
+
+function connect() {
+  const events = new EventSource(api("/api/events"));
+  events.addEventListener("sessions", (e) => {
+    state.textContent = `updated ${new Date().toLocaleTimeString()}`;
+    state.className = "muted";
+    render(JSON.parse(e.data));
+  });
+  events.onerror = () => {
+    state.textContent = "disconnected, retrying…";
+    state.className = "error";
+  };
+}
+
+connect();
@@ -0,0 +1,284 @@
+package web
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/seunggabi/claude-dashboard/internal/config"
+	"github.com/seunggabi/claude-dashboard/internal/session"
+)
+
+// Sessions started by a trigger are given promptWait to show claude's
+// prompt before the trigger's prompt is sent, checked every promptPoll.
+var (
+	promptWait = time.Minute
+	promptPoll = time.Second
+)
+
+// maxHookBody is the largest webhook payload read; GitHub sends up to 25MB
+// but issue events are far smaller.
+const maxHookBody = 1 << 20
+
+// placeholder matches one {a.b} of a trigger template.
+var placeholder = regexp.MustCompile(`\{([A-Za-z0-9_.-]+)\}`)
+
+// EnableTriggers serves /hooks/NAME, where each signed webhook matching one
+// of triggers starts a session with c and then sends it the trigger's
+// prompt. The signature stands in for the token, which senders such as
+// GitHub cannot add. Prompts that cannot be sent are reported to onError.
+func (s *Server) EnableTriggers(triggers []config.Trigger, c Controller, onError func(error)) {
+	s.triggers = triggers
+	s.starter = c
+	s.onError = onError
+}
+
+func (s *Server) handleHook(w http.ResponseWriter, r *http.Request) {
+	name := r.PathValue("trigger")
+	var named []config.Trigger
+	for _, t := range s.triggers {
+		if t.Name == name {
+			named = append(named, t)
+		}
+	}
+	if len(named) == 0 {
+		http.Error(w, fmt.Sprintf("no trigger %s", name), http.StatusNotFound)
+		return
+	}
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxHookBody))
+	if err != nil {
+		http.Error(w, "cannot read body: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	// Triggers sharing a name may have different secrets; only those the
+	// payload was signed for are considered.
+	signature := r.Header.Get("X-Hub-Signature-256")
+	var signed []config.Trigger
+	for _, t := range named {
+		if validSignature(t.Secret, body, signature) {
+			signed = append(signed, t)
+		}
+	}
+	if len(signed) == 0 {
+		http.Error(w, "missing or wrong X-Hub-Signature-256", http.StatusUnauthorized)
+		return
+	}
+	var payload any
+	dec := json.NewDecoder(bytes.NewReader(body))
+	dec.UseNumber()
+	if err := dec.Decode(&payload); err != nil {
+		http.Error(w, "invalid JSON body: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	event := r.Header.Get("X-GitHub-Event")
+	for _, t := range signed {
+		if matches(t, event, payload) {
+			s.startTriggered(w, r, t, payload)
+			return
+		}
+	}
+	// Senders retry failed deliveries; one no trigger wants is not a failure.
+	io.WriteString(w, "no trigger matched\n")
+}
+
+// startTriggered creates the session of t for payload and sends its prompt
+// once claude is ready. A session that already exists, e.g. from an earlier
+// delivery of the same event, is left alone.
+func (s *Server) startTriggered(w http.ResponseWriter, r *http.Request, t config.Trigger, payload any) {
+	name, err := expand(t.Session, payload, func(v string) (string, error) { return sessionNamePart(v), nil })
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+		return
+	}
+	path, err := expand(t.Path, payload, commandWord)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+		return
+	}
+	args, err := expand(t.Args, payload, commandWord)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+		return
+	}
+	// The prompt is typed into claude, not given on its command line.
+	prompt, err := expand(t.Prompt, payload, nil)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+		return
+	}
+	full := session.SessionPrefix + name
+	if s.find(r.Context(), "", full) != nil {
+		fmt.Fprintf(w, "session %s already exists\n", full)
+		return
+	}
+	if err := s.starter.Create(r.Context(), name, path, args); err != nil {
+		controlError(w, err, http.StatusUnprocessableEntity)
+		return
+	}
+	s.poll(r.Context())
+	if prompt = strings.Join(strings.Fields(prompt), " "); prompt != "" {
+		go func() {
+			if err := s.sendWhenReady(context.Background(), full, prompt); err != nil && s.onError != nil {
+				s.onError(fmt.Errorf("trigger %s: %w", t.Name, err))
+			}
+		}()
+	}
+	view := sessionView{Name: full, Host: session.LocalHost}
+	if found := s.find(r.Context(), "", full); found != nil {
+		view = views([]session.Session{*found})[0]
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(view)
+}
+
+// sendWhenReady sends prompt to the session name once claude in it waits
+// for input, giving up after promptWait.
+func (s *Server) sendWhenReady(ctx context.Context, name, prompt string) error {
+	deadline := time.Now().Add(promptWait)
+	for {
+		found := s.find(ctx, "", name)
+		switch {
+		case found == nil:
+			return fmt.Errorf("session %s went away before its prompt was sent", name)
+		case found.Exited():
+			return fmt.Errorf("claude exited in %s before its prompt was sent", name)
+		case found.Status == session.StatusIdle || found.Status == session.StatusWaiting:
+			return s.starter.SendCommand(ctx, name, prompt)
+		case time.Now().After(deadline):
+			return fmt.Errorf("claude in %s was not ready for its prompt within %s", name, promptWait)
+		}
+		time.Sleep(promptPoll)
+		s.poll(ctx)
+	}
+}
+
+// validSignature reports whether header, as "sha256=HEX", is the
+// HMAC-SHA256 of body with secret.
+func validSignature(secret string, body []byte, header string) bool {
+	got, ok := strings.CutPrefix(header, "sha256=")
+	if !ok {
+		return false
+	}
+	sum, err := hex.DecodeString(got)
+	if err != nil {
+		return false
+	}
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return hmac.Equal(sum, mac.Sum(nil))
+}
+
+// matches reports whether a payload of event is one t starts a session for.
+func matches(t config.Trigger, event string, payload any) bool {
+	if t.Event != "" && t.Event != event {
+		return false
+	}
+	for field, want := range t.Match {
+		got, ok := lookup(payload, field)
+		if !ok || got != want {
+			return false
+		}
+	}
+	return true
+}
+
+// expand replaces each {a.b} of tmpl by that field of payload, passed
+// through clean when set. A field missing from the payload, or one clean
+// rejects, is an error.
+func expand(tmpl string, payload any, clean func(string) (string, error)) (string, error) {
+	var err error
+	out := placeholder.ReplaceAllStringFunc(tmpl, func(m string) string {
+		field := m[1 : len(m)-1]
+		v, ok := lookup(payload, field)
+		if !ok {
+			if err == nil {
+				err = fmt.Errorf("payload has no %s for %q", field, tmpl)
+			}
+			return ""
+		}
+		if clean != nil {
+			var cleanErr error
+			if v, cleanErr = clean(v); cleanErr != nil && err == nil {
+				err = fmt.Errorf("payload %s for %q: %w", field, tmpl, cleanErr)
+			}
+		}
+		return v
+	})
+	if err != nil {
+		return "", err
+	}
+	return out, nil
+}
+
+// lookup returns the field of payload at a dotted path, such as
+// issue.number, as text. Objects and arrays are not values.
+func lookup(payload any, path string) (string, bool) {
+	v := payload
+	for _, key := range strings.Split(path, ".") {
+		obj, ok := v.(map[string]any)
+		if !ok {
+			return "", false
+		}
+		if v, ok = obj[key]; !ok {
+			return "", false
+		}
+	}
+	switch v := v.(type) {
+	case string:
+		return v, true
+	case json.Number:
+		return v.String(), true
+	case bool:
+		return strconv.FormatBool(v), true
+	}
+	return "", false
+}
+
+// commandWord checks a payload value going into the directory or the
+// arguments of claude, which the payload's sender must not pick: it has to
+// be one word that is not an option, e.g. --dangerously-skip-permissions
+// from an issue title, nor leaves the configured directory, as ../ would.
+func commandWord(v string) (string, error) {
+	switch {
+	case v == "":
+		return "", fmt.Errorf("is empty")
+	case strings.IndexFunc(v, func(r rune) bool { return unicode.IsSpace(r) || unicode.IsControl(r) }) >= 0:
+		return "", fmt.Errorf("%q has spaces", v)
+	case strings.HasPrefix(v, "-"):
+		return "", fmt.Errorf("%q looks like an option", v)
+	case strings.ContainsAny(v, `/\`) || v == "." || v == "..":
+		return "", fmt.Errorf("%q is not a single path element", v)
+	}
+	return v, nil
+}
+
+// sessionNamePart keeps what tmux allows in a session name from a payload
+// value, e.g. an issue title, in lower case with dashes between words.
+func sessionNamePart(v string) string {
+	var b strings.Builder
+	dash := false
+	for _, r := range strings.ToLower(v) {
+		if r >= 'a' && r <= 'z' || r >= '0' && r <= '9' || r == '_' {
+			if dash && b.Len() > 0 {
+				b.WriteByte('-')
+			}
+			b.WriteRune(r)
+			dash = false
+		} else {
+			dash = true
+		}
+	}
+	return b.String()
+}
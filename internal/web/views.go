@@ -0,0 +1,72 @@
+package web
+
+import (
+	"time"
+
+	"github.com/seunggabi/claude-dashboard/internal/conversation"
+	"github.com/seunggabi/claude-dashboard/internal/session"
+)
+
+// sessionView is a session as the API shows it. Uptime is left to the page,
+// from started, so the list only changes when a session does.
+type sessionView struct {
+	Name       string    `json:"name"`
+	Host       string    `json:"host"`
+	Project    string    `json:"project"`
+	Status     string    `json:"status"`
+	Attached   bool      `json:"attached"`
+	Managed    bool      `json:"managed"`
+	Path       string    `json:"path"`
+	Branch     string    `json:"branch,omitempty"`
+	Started    time.Time `json:"started"`
+	Activity   time.Time `json:"activity"`
+	CPU        float64   `json:"cpu"`
+	Memory     float64   `json:"memory"`
+	LastPrompt string    `json:"last_prompt,omitempty"`
+}
+
+func views(sessions []session.Session) []sessionView {
+	out := make([]sessionView, len(sessions))
+	for i, s := range sessions {
+		out[i] = sessionView{
+			Name:       s.Name,
+			Host:       s.HostName(),
+			Project:    s.Project,
+			Status:     string(s.Status),
+			Attached:   s.Attached,
+			Managed:    s.Managed,
+			Path:       s.Path,
+			Started:    s.StartedAt,
+			Activity:   s.Activity,
+			CPU:        s.CPU,
+			Memory:     s.Memory,
+			LastPrompt: s.LastPrompt,
+		}
+		if !s.Git.IsZero() {
+			out[i].Branch = s.Git.String()
+		}
+	}
+	return out
+}
+
+// messageView is a conversation message as the API shows it.
+type messageView struct {
+	Role    string    `json:"role"`
+	Content string    `json:"content"`
+	Time    time.Time `json:"time"`
+	Model   string    `json:"model,omitempty"`
+}
+
+// messageViews converts messages for the API, cutting long ones to
+// maxContent characters.
+func messageViews(messages []conversation.Message) []messageView {
+	out := make([]messageView, len(messages))
+	for i, m := range messages {
+		content := m.Content
+		if r := []rune(content); len(r) > maxContent {
+			content = string(r[:maxContent]) + "…"
+		}
+		out[i] = messageView{Role: m.Role, Content: content, Time: m.Timestamp, Model: m.Model}
+	}
+	return out
+}
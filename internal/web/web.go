@@ -0,0 +1,270 @@
+// Package web serves a dashboard over HTTP: a page, a JSON API of sessions
+// and conversation tails, and server-sent events that push the session list
+// whenever it changes. It is read-only unless the control API or webhook
+// triggers are enabled.
+package web
+
+import (
+	"bytes"
+	"context"
+	"crypto/subtle"
+	"embed"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/seunggabi/claude-dashboard/internal/config"
+	"github.com/seunggabi/claude-dashboard/internal/conversation"
+	"github.com/seunggabi/claude-dashboard/internal/session"
+)
+
+//go:embed static
+var static embed.FS
+
+// DefaultTail and MaxTail bound how many conversation messages a tail holds.
+const (
+	DefaultTail = 20
+	MaxTail     = 200
+)
+
+// maxContent is how many characters of a message a tail carries.
+const maxContent = 4000
+
+// heartbeat is how often an idle event stream is written to, so proxies
+// and phones do not drop it.
+const heartbeat = 20 * time.Second
+
+// ListFunc lists the sessions to show.
+type ListFunc func(ctx context.Context) ([]session.Session, error)
+
+// TailFunc returns the last n conversation messages of a session.
+type TailFunc func(s session.Session, n int) ([]conversation.Message, error)
+
+// Server is the web dashboard. Run polls the session list; the handler
+// answers from the latest poll.
+type Server struct {
+	list     ListFunc
+	tail     TailFunc
+	interval time.Duration
+	token    string     // required as ?token= or a bearer token when set
+	control  Controller // serves the control API when set
+	calendar CalendarFunc
+
+	// Webhook triggers (see EnableTriggers).
+	triggers []config.Trigger
+	starter  Controller
+	onError  func(error)
+
+	mu       sync.Mutex
+	sessions []session.Session
+	snapshot []byte        // sessions as JSON
+	err      string        // error of the last poll
+	changed  chan struct{} // closed, and replaced, when snapshot changes
+}
+
+// minInterval is the shortest interval sessions are listed at.
+const minInterval = time.Second
+
+// New returns a server listing sessions with list every interval (at least
+// a second) and reading conversations with tail. A non-empty token is
+// required from every request.
+func New(list ListFunc, tail TailFunc, interval time.Duration, token string) *Server {
+	return &Server{
+		list:     list,
+		tail:     tail,
+		interval: max(interval, minInterval),
+		token:    token,
+		changed:  make(chan struct{}),
+	}
+}
+
+// Run polls the session list until ctx is done.
+func (s *Server) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+	for {
+		s.poll(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// poll lists sessions and publishes them if they changed.
+func (s *Server) poll(ctx context.Context) {
+	sessions, err := s.list(ctx)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err != nil {
+		s.err = err.Error()
+		return
+	}
+	s.err = ""
+	data, _ := json.Marshal(views(sessions))
+	s.sessions = sessions
+	if bytes.Equal(data, s.snapshot) {
+		return
+	}
+	s.snapshot = data
+	close(s.changed)
+	s.changed = make(chan struct{})
+}
+
+// latest returns the last published session list and a channel closed when
+// the next one is.
+func (s *Server) latest() ([]byte, chan struct{}) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.snapshot, s.changed
+}
+
+// find returns the session name on host (empty for this machine), listing
+// sessions again when the last poll does not have it.
+func (s *Server) find(ctx context.Context, host, name string) *session.Session {
+	lookup := func() *session.Session {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		for i := range s.sessions {
+			if s.sessions[i].Host == host && s.sessions[i].Name == name {
+				found := s.sessions[i]
+				return &found
+			}
+		}
+		return nil
+	}
+	if found := lookup(); found != nil {
+		return found
+	}
+	s.poll(ctx)
+	return lookup()
+}
+
+// Handler returns the HTTP handler of the dashboard. Only GET requests are
+// served, unless the control API or triggers are enabled.
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	assets, _ := fs.Sub(static, "static")
+	mux.Handle("GET /", http.FileServerFS(assets))
+	mux.HandleFunc("GET /api/sessions", s.handleSessions)
+	mux.HandleFunc("GET /api/sessions/{host}/{name}/tail", s.handleTail)
+	mux.HandleFunc("GET /api/events", s.handleEvents)
+	if s.calendar != nil {
+		mux.HandleFunc("GET /calendar.ics", s.handleCalendar)
+	}
+	if s.control != nil {
+		mux.HandleFunc("POST /api/sessions", s.handleCreate)
+		mux.HandleFunc("DELETE /api/sessions/{name}", s.handleKill)
+		mux.HandleFunc("POST /api/sessions/{name}/send", s.handleSend)
+	}
+	if len(s.triggers) == 0 {
+		return s.authorize(mux)
+	}
+	outer := http.NewServeMux()
+	outer.HandleFunc("POST /hooks/{trigger}", s.handleHook)
+	outer.Handle("/", s.authorize(mux))
+	return outer
+}
+
+// authorize rejects requests without the token, when one is set.
+func (s *Server) authorize(next http.Handler) http.Handler {
+	if s.token == "" {
+		return next
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got := r.URL.Query().Get("token")
+		if bearer, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer "); ok {
+			got = bearer
+		}
+		if subtle.ConstantTimeCompare([]byte(got), []byte(s.token)) != 1 {
+			http.Error(w, "missing or wrong token", http.StatusUnauthorized)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func (s *Server) handleSessions(w http.ResponseWriter, r *http.Request) {
+	data, _ := s.latest()
+	if data == nil {
+		s.poll(r.Context())
+		if data, _ = s.latest(); data == nil {
+			s.mu.Lock()
+			msg := s.err
+			s.mu.Unlock()
+			http.Error(w, msg, http.StatusServiceUnavailable)
+			return
+		}
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(data)
+}
+
+func (s *Server) handleTail(w http.ResponseWriter, r *http.Request) {
+	host, name := r.PathValue("host"), r.PathValue("name")
+	if host == session.LocalHost {
+		host = ""
+	}
+	n := DefaultTail
+	if v := r.URL.Query().Get("n"); v != "" {
+		var err error
+		if n, err = strconv.Atoi(v); err != nil || n < 1 {
+			http.Error(w, "n must be a positive number", http.StatusBadRequest)
+			return
+		}
+		n = min(n, MaxTail)
+	}
+
+	found := s.find(r.Context(), host, name)
+	if found == nil {
+		http.Error(w, fmt.Sprintf("session %s not found", name), http.StatusNotFound)
+		return
+	}
+
+	messages, err := s.tail(*found, n)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(messageViews(messages))
+}
+
+// handleEvents streams the session list as "sessions" events: the current
+// one, then each change.
+func (s *Server) handleEvents(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming unsupported", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+
+	ping := time.NewTicker(heartbeat)
+	defer ping.Stop()
+	data, changed := s.latest()
+	for {
+		if data != nil {
+			fmt.Fprintf(w, "event: sessions\ndata: %s\n\n", data)
+			flusher.Flush()
+		}
+		select {
+		case <-r.Context().Done():
+			return
+		case <-ping.C:
+			fmt.Fprint(w, ": ping\n\n")
+			flusher.Flush()
+			data = nil
+		case <-changed:
+			data, changed = s.latest()
+		}
+	}
+}
@@ -0,0 +1,494 @@
+package web
+
+import (
+	"bufio"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/seunggabi/claude-dashboard/internal/config"
+	"github.com/seunggabi/claude-dashboard/internal/conversation"
+	"github.com/seunggabi/claude-dashboard/internal/session"
+)
+
+func testServer(t *testing.T, token string) (*Server, *[]session.Session) {
+	t.Helper()
+	sessions := &[]session.Session{
+		{Name: "cd-api", Project: "api", Status: session.StatusActive, Managed: true, Path: "/src/api"},
+		{Name: "cd-gpu", Host: "devbox", Project: "gpu", Status: session.StatusWaiting, Managed: true},
+	}
+	list := func(context.Context) ([]session.Session, error) { return *sessions, nil }
+	tail := func(s session.Session, n int) ([]conversation.Message, error) {
+		if s.Host != "" {
+			return nil, errors.New("remote")
+		}
+		msgs := []conversation.Message{
+			{Role: "user", Content: "add a health check"},
+			{Role: "assistant", Content: strings.Repeat("x", maxContent+10)},
+		}
+		return msgs[max(0, len(msgs)-n):], nil
+	}
+	return New(list, tail, time.Second, token), sessions
+}
+
+// ---------------------------------------------------------------------------
+// Handler
+// ---------------------------------------------------------------------------
+
+func TestHandler_listsSessions(t *testing.T) {
+	srv, _ := testServer(t, "")
+	rec := httptest.NewRecorder()
+	srv.Handler().ServeHTTP(rec, httptest.NewRequest("GET", "/api/sessions", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body)
+	}
+	var got []sessionView
+	if err := json.Unmarshal(rec.Body.Bytes(), &got); err != nil {
+		t.Fatalf("decoding: %v", err)
+	}
+	if len(got) != 2 || got[0].Host != session.LocalHost || got[1].Host != "devbox" || got[1].Status != "waiting" {
+		t.Errorf("unexpected sessions %+v", got)
+	}
+}
+
+func TestHandler_tailOfLocalSession(t *testing.T) {
+	srv, _ := testServer(t, "")
+	srv.poll(context.Background())
+	rec := httptest.NewRecorder()
+	srv.Handler().ServeHTTP(rec, httptest.NewRequest("GET", "/api/sessions/local/cd-api/tail?n=1", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body)
+	}
+	var got []messageView
+	json.Unmarshal(rec.Body.Bytes(), &got)
+	if len(got) != 1 || got[0].Role != "assistant" || len([]rune(got[0].Content)) != maxContent+1 {
+		t.Errorf("expected the last message cut to %d characters, got %+v", maxContent, got)
+	}
+}
+
+func TestHandler_tailErrors(t *testing.T) {
+	srv, _ := testServer(t, "")
+	srv.poll(context.Background())
+	tests := map[string]int{
+		"/api/sessions/local/cd-nope/tail":    http.StatusNotFound,
+		"/api/sessions/devbox/cd-gpu/tail":    http.StatusNotFound,
+		"/api/sessions/local/cd-api/tail?n=0": http.StatusBadRequest,
+	}
+	for path, want := range tests {
+		rec := httptest.NewRecorder()
+		srv.Handler().ServeHTTP(rec, httptest.NewRequest("GET", path, nil))
+		if rec.Code != want {
+			t.Errorf("%s: expected %d, got %d", path, want, rec.Code)
+		}
+	}
+}
+
+func TestHandler_isReadOnly(t *testing.T) {
+	srv, _ := testServer(t, "")
+	for _, path := range []string{"/api/sessions", "/api/sessions/local/cd-api/tail", "/"} {
+		rec := httptest.NewRecorder()
+		srv.Handler().ServeHTTP(rec, httptest.NewRequest("POST", path, nil))
+		if rec.Code != http.StatusMethodNotAllowed {
+			t.Errorf("POST %s: expected 405, got %d", path, rec.Code)
+		}
+	}
+}
+
+func TestHandler_servesThePage(t *testing.T) {
+	srv, _ := testServer(t, "")
+	rec := httptest.NewRecorder()
+	srv.Handler().ServeHTTP(rec, httptest.NewRequest("GET", "/", nil))
+	if rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), "app.js") {
+		t.Errorf("expected the embedded page, got %d: %.200s", rec.Code, rec.Body)
+	}
+}
+
+func TestHandler_requiresTheToken(t *testing.T) {
+	srv, _ := testServer(t, "s3cret")
+	h := srv.Handler()
+	tests := []struct {
+		path, auth string
+		want       int
+	}{
+		{"/api/sessions", "", http.StatusUnauthorized},
+		{"/api/sessions?token=wrong", "", http.StatusUnauthorized},
+		{"/api/sessions?token=s3cret", "", http.StatusOK},
+		{"/api/sessions", "Bearer s3cret", http.StatusOK},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest("GET", tt.path, nil)
+		if tt.auth != "" {
+			req.Header.Set("Authorization", tt.auth)
+		}
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		if rec.Code != tt.want {
+			t.Errorf("%s %q: expected %d, got %d", tt.path, tt.auth, tt.want, rec.Code)
+		}
+	}
+}
+
+// ---------------------------------------------------------------------------
+// Control
+// ---------------------------------------------------------------------------
+
+// fakeControl records calls and changes the listed sessions like tmux would.
+type fakeControl struct {
+	sessions *[]session.Session
+	calls    []string
+}
+
+func (f *fakeControl) Create(_ context.Context, name, dir, args string) error {
+	if dir == "/missing" {
+		return errors.New("directory does not exist: /missing")
+	}
+	f.calls = append(f.calls, "create "+name+" "+dir+" "+args)
+	*f.sessions = append(*f.sessions, session.Session{Name: session.SessionPrefix + name, Managed: true, Path: dir, Status: session.StatusIdle})
+	return nil
+}
+
+func (f *fakeControl) Kill(_ context.Context, name string) error {
+	f.calls = append(f.calls, "kill "+name)
+	return nil
+}
+
+func (f *fakeControl) SendCommand(_ context.Context, name, text string) error {
+	if text == "" {
+		return errors.New("prompt is empty")
+	}
+	f.calls = append(f.calls, "send "+name+" "+text)
+	return nil
+}
+
+func controlServer(t *testing.T) (http.Handler, *fakeControl) {
+	t.Helper()
+	srv, sessions := testServer(t, "s3cret")
+	*sessions = append(*sessions, session.Session{Name: "term-42", PID: "42", Status: session.StatusTerminal})
+	fake := &fakeControl{sessions: sessions}
+	if err := srv.EnableControl(fake); err != nil {
+		t.Fatalf("EnableControl: %v", err)
+	}
+	return srv.Handler(), fake
+}
+
+func serveControl(h http.Handler, method, path, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	req.Header.Set("Authorization", "Bearer s3cret")
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestControl_needsAToken(t *testing.T) {
+	srv, _ := testServer(t, "")
+	if err := srv.EnableControl(&fakeControl{}); !errors.Is(err, ErrNoToken) {
+		t.Errorf("expected ErrNoToken, got %v", err)
+	}
+	h, fake := controlServer(t)
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest("DELETE", "/api/sessions/cd-api", nil))
+	if rec.Code != http.StatusUnauthorized || len(fake.calls) != 0 {
+		t.Errorf("expected 401 and no change, got %d and %v", rec.Code, fake.calls)
+	}
+}
+
+func TestControl_createsSessions(t *testing.T) {
+	h, fake := controlServer(t)
+	rec := serveControl(h, "POST", "/api/sessions", `{"name": "web", "path": "/src/web", "args": "--model opus"}`)
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("expected 201, got %d: %s", rec.Code, rec.Body)
+	}
+	var got sessionView
+	json.Unmarshal(rec.Body.Bytes(), &got)
+	if got.Name != "cd-web" || got.Path != "/src/web" || got.Host != session.LocalHost {
+		t.Errorf("expected the new session, got %+v", got)
+	}
+	if len(fake.calls) != 1 || fake.calls[0] != "create web /src/web --model opus" {
+		t.Errorf("unexpected calls %v", fake.calls)
+	}
+
+	tests := map[string]int{
+		`{"name": "api"}`:                   http.StatusConflict,
+		`{"path": "/src"}`:                  http.StatusBadRequest,
+		`{"name": "x", "path": "/missing"}`: http.StatusBadRequest,
+		`{"name": "x", "dir": "/src"}`:      http.StatusBadRequest,
+		`not json`:                          http.StatusBadRequest,
+	}
+	for body, want := range tests {
+		if rec := serveControl(h, "POST", "/api/sessions", body); rec.Code != want {
+			t.Errorf("%s: expected %d, got %d", body, want, rec.Code)
+		}
+	}
+}
+
+func TestControl_killsSessions(t *testing.T) {
+	h, fake := controlServer(t)
+	tests := map[string]int{
+		"/api/sessions/cd-api":  http.StatusNoContent,
+		"/api/sessions/cd-nope": http.StatusNotFound,
+		"/api/sessions/cd-gpu":  http.StatusNotFound, // remote
+		"/api/sessions/term-42": http.StatusForbidden,
+	}
+	for path, want := range tests {
+		if rec := serveControl(h, "DELETE", path, ""); rec.Code != want {
+			t.Errorf("DELETE %s: expected %d, got %d", path, want, rec.Code)
+		}
+	}
+	if len(fake.calls) != 1 || fake.calls[0] != "kill cd-api" {
+		t.Errorf("expected only cd-api killed, got %v", fake.calls)
+	}
+}
+
+func TestControl_sendsPrompts(t *testing.T) {
+	h, fake := controlServer(t)
+	if rec := serveControl(h, "POST", "/api/sessions/cd-api/send", `{"prompt": "run the tests"}`); rec.Code != http.StatusNoContent {
+		t.Errorf("expected 204, got %d: %s", rec.Code, rec.Body)
+	}
+	if rec := serveControl(h, "POST", "/api/sessions/cd-api/send", `{"prompt": ""}`); rec.Code != http.StatusBadRequest {
+		t.Errorf("empty prompt: expected 400, got %d", rec.Code)
+	}
+	if rec := serveControl(h, "POST", "/api/sessions/term-42/send", `{"prompt": "hi"}`); rec.Code != http.StatusForbidden {
+		t.Errorf("terminal session: expected 403, got %d", rec.Code)
+	}
+	if len(fake.calls) != 1 || fake.calls[0] != "send cd-api run the tests" {
+		t.Errorf("unexpected calls %v", fake.calls)
+	}
+}
+
+// ---------------------------------------------------------------------------
+// Triggers
+// ---------------------------------------------------------------------------
+
+// promptControl hands the prompts sent to it over sent, since triggers send
+// them in the background.
+type promptControl struct {
+	*fakeControl
+	sent chan string
+}
+
+func (p promptControl) SendCommand(_ context.Context, name, text string) error {
+	p.sent <- name + " " + text
+	return nil
+}
+
+// triggerServer serves a trigger starting a session for issues labelled
+// ai-fix.
+func triggerServer(t *testing.T) (http.Handler, promptControl, chan error) {
+	t.Helper()
+	srv, sessions := testServer(t, "s3cret")
+	c := promptControl{&fakeControl{sessions: sessions}, make(chan string, 1)}
+	errs := make(chan error, 1)
+	srv.EnableTriggers([]config.Trigger{{
+		Name:    "github",
+		Secret:  "hook-key",
+		Event:   "issues",
+		Match:   map[string]string{"action": "labeled", "label.name": "ai-fix"},
+		Session: "fix-{issue.number}",
+		Path:    "/src/{repository.name}",
+		Prompt:  "Fix issue #{issue.number}: {issue.title}\n{issue.body}",
+	}}, c, func(err error) { errs <- err })
+	return srv.Handler(), c, errs
+}
+
+func postHook(h http.Handler, event, secret, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest("POST", "/hooks/github", strings.NewReader(body))
+	req.Header.Set("X-GitHub-Event", event)
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(body))
+	req.Header.Set("X-Hub-Signature-256", "sha256="+hex.EncodeToString(mac.Sum(nil)))
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	return rec
+}
+
+const labeledIssue = `{"action": "labeled", "label": {"name": "ai-fix"},
+	"issue": {"number": 42, "title": "Crash on save", "body": "Steps:\n1. save"},
+	"repository": {"name": "api"}}`
+
+func TestTriggers_startSessionAndSendPrompt(t *testing.T) {
+	defer func(poll time.Duration) { promptPoll = poll }(promptPoll)
+	promptPoll = time.Millisecond
+	h, fake, errs := triggerServer(t)
+
+	rec := postHook(h, "issues", "hook-key", labeledIssue)
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("expected 201, got %d: %s", rec.Code, rec.Body)
+	}
+	select {
+	case sent := <-fake.sent:
+		if want := "cd-fix-42 Fix issue #42: Crash on save Steps: 1. save"; sent != want {
+			t.Errorf("expected %q sent, got %q", want, sent)
+		}
+	case err := <-errs:
+		t.Fatalf("unexpected error: %v", err)
+	case <-time.After(time.Second):
+		t.Fatal("expected the prompt to be sent")
+	}
+	if len(fake.calls) != 1 || fake.calls[0] != "create fix-42 /src/api " {
+		t.Errorf("unexpected calls %q", fake.calls)
+	}
+
+	if rec := postHook(h, "issues", "hook-key", labeledIssue); rec.Code != http.StatusOK || len(fake.calls) != 1 {
+		t.Errorf("expected a redelivery to leave the session alone, got %d and %v", rec.Code, fake.calls)
+	}
+}
+
+func TestTriggers_rejectOrSkipOtherPayloads(t *testing.T) {
+	h, fake, _ := triggerServer(t)
+	if rec := postHook(h, "issues", "wrong-key", labeledIssue); rec.Code != http.StatusUnauthorized {
+		t.Errorf("wrong signature: expected 401, got %d", rec.Code)
+	}
+	if rec := postHook(h, "ping", "hook-key", labeledIssue); rec.Code != http.StatusOK {
+		t.Errorf("other event: expected 200, got %d", rec.Code)
+	}
+	unlabeled := strings.Replace(labeledIssue, "ai-fix", "bug", 1)
+	if rec := postHook(h, "issues", "hook-key", unlabeled); rec.Code != http.StatusOK {
+		t.Errorf("other label: expected 200, got %d", rec.Code)
+	}
+	noNumber := strings.Replace(labeledIssue, `"number": 42, `, "", 1)
+	if rec := postHook(h, "issues", "hook-key", noNumber); rec.Code != http.StatusUnprocessableEntity {
+		t.Errorf("missing field: expected 422, got %d", rec.Code)
+	}
+	req := httptest.NewRequest("POST", "/hooks/gitlab", strings.NewReader("{}"))
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("unknown trigger: expected 404, got %d", rec.Code)
+	}
+	if len(fake.calls) != 0 {
+		t.Errorf("expected no session started, got %v", fake.calls)
+	}
+}
+
+func TestTriggers_refuseHostilePathAndArgs(t *testing.T) {
+	srv, sessions := testServer(t, "s3cret")
+	fake := promptControl{&fakeControl{sessions: sessions}, make(chan string, 1)}
+	srv.EnableTriggers([]config.Trigger{{
+		Name:    "github",
+		Secret:  "hook-key",
+		Session: "fix-{issue.number}",
+		Path:    "/src/{repository.name}",
+		Args:    "--model {issue.model}",
+	}}, fake, func(error) {})
+	h := srv.Handler()
+
+	for _, hostile := range []struct{ repo, model string }{
+		{"api", "x --dangerously-skip-permissions"},
+		{"api", "--dangerously-skip-permissions"},
+		{"api", "opus\t--resume"},
+		{"../../etc", "opus"},
+		{"..", "opus"},
+		{"api/../../home", "opus"},
+		{"-rf", "opus"},
+	} {
+		body := fmt.Sprintf(`{"issue": {"number": 7, "model": %q}, "repository": {"name": %q}}`, hostile.model, hostile.repo)
+		if rec := postHook(h, "issues", "hook-key", body); rec.Code != http.StatusUnprocessableEntity {
+			t.Errorf("%+v: expected 422, got %d: %s", hostile, rec.Code, rec.Body)
+		}
+	}
+	if len(fake.calls) != 0 {
+		t.Errorf("expected no session started, got %q", fake.calls)
+	}
+
+	body := `{"issue": {"number": 7, "model": "opus"}, "repository": {"name": "api"}}`
+	if rec := postHook(h, "issues", "hook-key", body); rec.Code != http.StatusCreated || len(fake.calls) != 1 || fake.calls[0] != "create fix-7 /src/api --model opus" {
+		t.Errorf("expected a plain payload to start the session, got %d and %q", rec.Code, fake.calls)
+	}
+}
+
+func TestSessionNamePart_keepsWordsTmuxAllows(t *testing.T) {
+	if got := sessionNamePart("Crash on save: v1.2!"); got != "crash-on-save-v1-2" {
+		t.Errorf("unexpected name %q", got)
+	}
+}
+
+// ---------------------------------------------------------------------------
+// Calendar
+// ---------------------------------------------------------------------------
+
+func TestCalendar_servedOnlyWhenEnabled(t *testing.T) {
+	srv, _ := testServer(t, "secret")
+	rec := httptest.NewRecorder()
+	srv.Handler().ServeHTTP(rec, httptest.NewRequest("GET", "/calendar.ics?token=secret", nil))
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("expected 404 without a calendar, got %d", rec.Code)
+	}
+
+	srv.EnableCalendar(func(_ context.Context, w io.Writer) error {
+		_, err := io.WriteString(w, "BEGIN:VCALENDAR\r\nEND:VCALENDAR\r\n")
+		return err
+	})
+	rec = httptest.NewRecorder()
+	srv.Handler().ServeHTTP(rec, httptest.NewRequest("GET", "/calendar.ics?token=secret", nil))
+	if rec.Code != http.StatusOK || !strings.HasPrefix(rec.Header().Get("Content-Type"), "text/calendar") {
+		t.Fatalf("expected the feed, got %d %q", rec.Code, rec.Header().Get("Content-Type"))
+	}
+	if !strings.HasPrefix(rec.Body.String(), "BEGIN:VCALENDAR") {
+		t.Errorf("unexpected feed %q", rec.Body)
+	}
+
+	rec = httptest.NewRecorder()
+	srv.Handler().ServeHTTP(rec, httptest.NewRequest("GET", "/calendar.ics", nil))
+	if rec.Code != http.StatusUnauthorized {
+		t.Errorf("expected the feed to need the token, got %d", rec.Code)
+	}
+}
+
+// ---------------------------------------------------------------------------
+// Events
+// ---------------------------------------------------------------------------
+
+func TestEvents_pushesTheListAndItsChanges(t *testing.T) {
+	srv, sessions := testServer(t, "")
+	srv.poll(context.Background())
+	ts := httptest.NewServer(srv.Handler())
+	defer ts.Close()
+
+	res, err := http.Get(ts.URL + "/api/events")
+	if err != nil {
+		t.Fatalf("GET: %v", err)
+	}
+	defer res.Body.Close()
+	if ct := res.Header.Get("Content-Type"); ct != "text/event-stream" {
+		t.Errorf("expected an event stream, got %q", ct)
+	}
+	events := make(chan string)
+	go func() {
+		sc := bufio.NewScanner(res.Body)
+		sc.Buffer(nil, 1<<20)
+		for sc.Scan() {
+			if data, ok := strings.CutPrefix(sc.Text(), "data: "); ok {
+				events <- data
+			}
+		}
+		close(events)
+	}()
+	next := func() string {
+		select {
+		case e := <-events:
+			return e
+		case <-time.After(5 * time.Second):
+			t.Fatal("no event")
+			return ""
+		}
+	}
+
+	if first := next(); !strings.Contains(first, "cd-api") {
+		t.Errorf("expected the current list first, got %s", first)
+	}
+	(*sessions)[0].Status = session.StatusWaiting
+	srv.poll(context.Background())
+	if second := next(); !strings.Contains(second, `"status":"waiting","attached":false,"managed":true,"path":"/src/api"`) {
+		t.Errorf("expected the changed list, got %s", second)
+	}
+}
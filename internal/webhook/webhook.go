@@ -0,0 +1,299 @@
+// Package webhook posts session transitions (see session.Transitions) to
+// the webhooks in the config, as JSON or as Slack or Discord messages.
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/seunggabi/claude-dashboard/internal/config"
+	"github.com/seunggabi/claude-dashboard/internal/session"
+)
+
+// TickInterval is how often idle sessions are checked.
+const TickInterval = 15 * time.Second
+
+// timeout bounds one post.
+const timeout = 10 * time.Second
+
+// maxSnippet is how many characters of the last assistant message a
+// payload carries.
+const maxSnippet = 300
+
+// Payload is the JSON posted for a transition to a WebhookJSON webhook;
+// Text says what happened in a sentence.
+type Payload struct {
+	Event       string    `json:"event"`
+	Session     string    `json:"session"`
+	Host        string    `json:"host"`
+	Project     string    `json:"project"`
+	Path        string    `json:"path"`
+	Status      string    `json:"status"`
+	IdleSeconds int       `json:"idle_seconds,omitempty"`
+	WorkSeconds int       `json:"work_seconds,omitempty"`
+	Waiting     int       `json:"waiting,omitempty"`      // for all_quiet, sessions waiting for input
+	Idle        int       `json:"idle,omitempty"`         // for all_quiet, sessions idle
+	CPU         float64   `json:"cpu,omitempty"`          // for resource_guard, percent used by all local sessions
+	Memory      float64   `json:"memory,omitempty"`       // for resource_guard, percent of memory likewise
+	LastMessage string    `json:"last_message,omitempty"` // of the assistant, cut to maxSnippet
+	At          time.Time `json:"at"`
+	Text        string    `json:"text"`
+}
+
+// NewPayload describes t, with lastMessage as the session's last assistant
+// message if known. An all_quiet payload names no session.
+func NewPayload(t session.Transition, lastMessage string) Payload {
+	if t.Kind == config.WebhookAllQuiet {
+		p := Payload{Event: t.Kind, At: t.At}
+		for _, s := range t.Quiet {
+			if s.Status == session.StatusWaiting {
+				p.Waiting++
+			} else {
+				p.Idle++
+			}
+		}
+		p.Text = p.summary()
+		return p
+	}
+	s := t.Session
+	p := Payload{
+		Event:   t.Kind,
+		Session: s.Name,
+		Host:    s.HostName(),
+		Project: s.Project,
+		Path:    s.Path,
+		Status:  string(s.Status),
+		At:      t.At,
+	}
+	switch t.Kind {
+	case config.WebhookIdle:
+		p.IdleSeconds = int(t.Idle.Seconds())
+	case config.WebhookDone:
+		p.WorkSeconds = int(t.Worked.Seconds())
+	}
+	if snippet := strings.Join(strings.Fields(lastMessage), " "); snippet != "" {
+		if r := []rune(snippet); len(r) > maxSnippet {
+			snippet = string(r[:maxSnippet-1]) + "…"
+		}
+		p.LastMessage = snippet
+	}
+	p.Text = p.summary()
+	return p
+}
+
+// NewGuardPayload describes the local sessions passing the resource guard
+// together, using cpu and memory percent, and the session paused for it,
+// if any.
+func NewGuardPayload(cpu, memory float64, paused *session.Session, at time.Time) Payload {
+	p := Payload{Event: config.WebhookGuard, Host: session.LocalHost, CPU: cpu, Memory: memory, At: at}
+	if paused != nil {
+		p.Session, p.Project, p.Path, p.Status = paused.Name, paused.Project, paused.Path, string(paused.Status)
+	}
+	p.Text = p.summary()
+	return p
+}
+
+// summary says what happened in a sentence, e.g. "cd-api is waiting for
+// input".
+func (p Payload) summary() string {
+	name := p.Session
+	if p.Host != session.LocalHost {
+		name = p.Host + ":" + p.Session
+	}
+	switch p.Event {
+	case config.WebhookWaiting:
+		return name + " is waiting for input"
+	case config.WebhookDone:
+		return fmt.Sprintf("%s finished a task after %s", name, duration(p.WorkSeconds))
+	case config.WebhookIdle:
+		return fmt.Sprintf("%s has been idle for %s", name, duration(p.IdleSeconds))
+	case config.WebhookCrashed:
+		if p.Status == string(session.StatusCrashed) {
+			return name + " crashed: claude exited with an error"
+		}
+		return name + " went away while working"
+	case config.WebhookFinished:
+		if p.Status == string(session.StatusExited) {
+			return name + " finished: claude exited"
+		}
+		return name + " finished"
+	case config.WebhookAllQuiet:
+		return quietSummary(p.Waiting, p.Idle)
+	case config.WebhookGuard:
+		text := fmt.Sprintf("claude sessions use %.0f%% CPU and %.0f%% memory, over the resource guard", p.CPU, p.Memory)
+		if p.Session != "" {
+			text += "; paused " + name
+		}
+		return text
+	}
+	return name + " changed"
+}
+
+// quietSummary says how many sessions are left, e.g. "all 5 sessions are
+// waiting on you: 3 waiting for input, 2 idle".
+func quietSummary(waiting, idle int) string {
+	var parts []string
+	if waiting > 0 {
+		parts = append(parts, fmt.Sprintf("%d waiting for input", waiting))
+	}
+	if idle > 0 {
+		parts = append(parts, fmt.Sprintf("%d idle", idle))
+	}
+	return fmt.Sprintf("all %d sessions are waiting on you: %s", waiting+idle, strings.Join(parts, ", "))
+}
+
+// duration writes seconds as e.g. "12m" or "1h5m".
+func duration(seconds int) string {
+	d := (time.Duration(seconds) * time.Second).Round(time.Minute)
+	if d < time.Minute {
+		return fmt.Sprintf("%ds", seconds)
+	}
+	return strings.TrimSuffix(d.String(), "0s")
+}
+
+// message is the payload as chat text, with markup given bold and quote
+// markers: the summary, the project and the last message quoted.
+func (p Payload) message(bold func(string) string) string {
+	var b strings.Builder
+	if p.Session == "" {
+		b.WriteString(p.Text)
+	} else {
+		b.WriteString(strings.Replace(p.Text, p.Session, bold(p.Session), 1))
+	}
+	if p.Project != "" {
+		fmt.Fprintf(&b, " · %s", p.Project)
+	}
+	if p.LastMessage != "" {
+		b.WriteString("\n> " + p.LastMessage)
+	}
+	return b.String()
+}
+
+// Body returns what is posted for p to a webhook of format.
+func (p Payload) Body(format string) ([]byte, error) {
+	switch format {
+	case config.WebhookSlack:
+		return json.Marshal(map[string]string{"text": p.message(func(s string) string { return "*" + s + "*" })})
+	case config.WebhookDiscord:
+		content := p.message(func(s string) string { return "**" + s + "**" })
+		if r := []rune(content); len(r) > discordLimit {
+			content = string(r[:discordLimit-1]) + "…"
+		}
+		return json.Marshal(map[string]string{"content": content})
+	}
+	return json.Marshal(p)
+}
+
+// discordLimit is the longest message Discord accepts.
+const discordLimit = 2000
+
+// hook is a webhook with the transitions found for it; idle times differ
+// between webhooks.
+type hook struct {
+	config.Webhook
+	transitions *session.Transitions
+}
+
+// Notifier posts transitions to webhooks.
+type Notifier struct {
+	hooks  []hook
+	client *http.Client
+	// LastMessage, when set, returns the last assistant message of a
+	// session for payloads.
+	LastMessage func(session.Session) string
+	// OnError, when set, is called with each failed post.
+	OnError func(error)
+}
+
+// New returns a notifier for hooks.
+func New(hooks []config.Webhook) *Notifier {
+	n := &Notifier{client: &http.Client{Timeout: timeout}}
+	for _, w := range hooks {
+		n.hooks = append(n.hooks, hook{Webhook: w, transitions: session.NewTransitions(w.Idle(), w.Long())})
+	}
+	return n
+}
+
+// Run posts the transitions in events until ctx is done or events is
+// closed. Posts happen in the background, so a slow webhook does not hold
+// up the others.
+func (n *Notifier) Run(ctx context.Context, events <-chan session.Event) {
+	ticker := time.NewTicker(TickInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case e, ok := <-events:
+			if !ok {
+				return
+			}
+			for _, h := range n.hooks {
+				n.send(ctx, h, h.transitions.Feed(e))
+			}
+		case now := <-ticker.C:
+			for _, h := range n.hooks {
+				n.send(ctx, h, h.transitions.Tick(now))
+			}
+		}
+	}
+}
+
+// send posts the transitions h wants.
+func (n *Notifier) send(ctx context.Context, h hook, transitions []session.Transition) {
+	for _, t := range transitions {
+		if !h.Wants(t.Kind) {
+			continue
+		}
+		go func() {
+			last := ""
+			if n.LastMessage != nil {
+				last = n.LastMessage(t.Session)
+			}
+			if err := n.Post(ctx, h.Webhook, NewPayload(t, last)); err != nil && n.OnError != nil {
+				n.OnError(err)
+			}
+		}()
+	}
+}
+
+// Announce posts p to each webhook wanting its event, for events that are
+// not session transitions, and returns the errors of failed posts.
+func (n *Notifier) Announce(ctx context.Context, p Payload) error {
+	var errs []error
+	for _, h := range n.hooks {
+		if h.Wants(p.Event) {
+			errs = append(errs, n.Post(ctx, h.Webhook, p))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Post sends p to w.
+func (n *Notifier) Post(ctx context.Context, w config.Webhook, p Payload) error {
+	body, err := p.Body(w.Format)
+	if err != nil {
+		return err
+	}
+	url := w.URL
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("webhook %s: %w", url, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("webhook %s: %s", url, resp.Status)
+	}
+	return nil
+}
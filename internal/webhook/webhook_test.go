@@ -0,0 +1,185 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/seunggabi/claude-dashboard/internal/config"
+	"github.com/seunggabi/claude-dashboard/internal/session"
+)
+
+// ---------------------------------------------------------------------------
+// Payload
+// ---------------------------------------------------------------------------
+
+func TestNewPayload_describesTheTransition(t *testing.T) {
+	at := time.Date(2025, 11, 24, 9, 0, 0, 0, time.UTC)
+	p := NewPayload(session.Transition{
+		Kind:    config.WebhookIdle,
+		Session: session.Session{Name: "cd-api", Host: "devbox", Project: "api", Status: session.StatusIdle},
+		Idle:    12*time.Minute + 10*time.Second,
+		At:      at,
+	}, "")
+	if p.Event != "idle" || p.Session != "cd-api" || p.Host != "devbox" || p.IdleSeconds != 730 || !p.At.Equal(at) {
+		t.Errorf("unexpected payload %+v", p)
+	}
+	if p.Text != "devbox:cd-api has been idle for 12m" {
+		t.Errorf("unexpected text %q", p.Text)
+	}
+
+	local := NewPayload(session.Transition{Kind: config.WebhookWaiting, Session: session.Session{Name: "cd-web"}}, "")
+	if local.Host != session.LocalHost || local.Text != "cd-web is waiting for input" || local.IdleSeconds != 0 {
+		t.Errorf("unexpected payload %+v", local)
+	}
+}
+
+func TestNewPayload_doneCarriesTheLastMessage(t *testing.T) {
+	p := NewPayload(session.Transition{
+		Kind:    config.WebhookDone,
+		Session: session.Session{Name: "cd-api", Project: "api"},
+		Worked:  65 * time.Minute,
+	}, "All tests\n  pass now. "+strings.Repeat("x", 400))
+	if p.Text != "cd-api finished a task after 1h5m" || p.WorkSeconds != 3900 {
+		t.Errorf("unexpected payload %+v", p)
+	}
+	if !strings.HasPrefix(p.LastMessage, "All tests pass now. xxx") || len([]rune(p.LastMessage)) != maxSnippet {
+		t.Errorf("expected a one-line snippet of %d characters, got %q", maxSnippet, p.LastMessage)
+	}
+}
+
+func TestNewPayload_allQuietCountsTheSessions(t *testing.T) {
+	p := NewPayload(session.Transition{
+		Kind: config.WebhookAllQuiet,
+		Quiet: []session.Session{
+			{Name: "cd-a", Status: session.StatusWaiting},
+			{Name: "cd-b", Status: session.StatusIdle},
+			{Name: "cd-c", Status: session.StatusWaiting},
+		},
+	}, "")
+	if p.Session != "" || p.Waiting != 2 || p.Idle != 1 {
+		t.Errorf("unexpected payload %+v", p)
+	}
+	if p.Text != "all 3 sessions are waiting on you: 2 waiting for input, 1 idle" {
+		t.Errorf("unexpected text %q", p.Text)
+	}
+	body, _ := p.Body(config.WebhookSlack)
+	if want := `{"text":"all 3 sessions are waiting on you: 2 waiting for input, 1 idle"}`; string(body) != want {
+		t.Errorf("expected %s, got %s", want, body)
+	}
+}
+
+func TestBody_chatFormats(t *testing.T) {
+	p := Payload{Event: config.WebhookWaiting, Session: "cd-api", Host: session.LocalHost, Project: "api", LastMessage: "Can I run the migration?"}
+	p.Text = p.summary()
+
+	cases := map[string]string{
+		config.WebhookSlack:   `{"text":"*cd-api* is waiting for input · api\n\u003e Can I run the migration?"}`,
+		config.WebhookDiscord: `{"content":"**cd-api** is waiting for input · api\n\u003e Can I run the migration?"}`,
+	}
+	for format, want := range cases {
+		body, err := p.Body(format)
+		if err != nil || string(body) != want {
+			t.Errorf("%s: expected %s, got %s (%v)", format, want, body, err)
+		}
+	}
+
+	body, _ := p.Body("")
+	var decoded Payload
+	if err := json.Unmarshal(body, &decoded); err != nil || decoded != p {
+		t.Errorf("expected the payload as JSON, got %s", body)
+	}
+
+	p.LastMessage = strings.Repeat("y", 3000)
+	body, _ = p.Body(config.WebhookDiscord)
+	var discord struct{ Content string }
+	json.Unmarshal(body, &discord)
+	if n := len([]rune(discord.Content)); n != discordLimit {
+		t.Errorf("expected discord content cut to %d characters, got %d", discordLimit, n)
+	}
+}
+
+// ---------------------------------------------------------------------------
+// Notifier
+// ---------------------------------------------------------------------------
+
+func TestNotifier_postsTheEventsEachWebhookWants(t *testing.T) {
+	got := make(chan string, 4)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var p Payload
+		if err := json.NewDecoder(r.Body).Decode(&p); err != nil {
+			t.Errorf("decoding: %v", err)
+		}
+		got <- r.URL.Path + " " + p.Event + " " + p.Session
+	}))
+	defer srv.Close()
+
+	n := New([]config.Webhook{
+		{URL: srv.URL + "/all"},
+		{URL: srv.URL + "/crashes", Events: []string{config.WebhookCrashed}},
+	})
+	events := make(chan session.Event)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go n.Run(ctx, events)
+
+	events <- session.Event{Kind: session.StatusChanged, Session: session.Session{Name: "cd-a", Status: session.StatusWaiting}, At: time.Now()}
+	events <- session.Event{Kind: session.SessionRemoved, Session: session.Session{Name: "cd-b", Status: session.StatusActive}, At: time.Now()}
+
+	var posts []string
+	for len(posts) < 3 {
+		select {
+		case p := <-got:
+			posts = append(posts, p)
+		case <-time.After(5 * time.Second):
+			t.Fatalf("expected 3 posts, got %v", posts)
+		}
+	}
+	all := strings.Join(posts, "\n")
+	for _, want := range []string{"/all waiting cd-a", "/all crashed cd-b", "/crashes crashed cd-b"} {
+		if !strings.Contains(all, want) {
+			t.Errorf("expected %q among %v", want, posts)
+		}
+	}
+}
+
+func TestAnnounce_postsTheGuardToWebhooksWantingIt(t *testing.T) {
+	var posts []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var p Payload
+		if err := json.NewDecoder(r.Body).Decode(&p); err != nil {
+			t.Errorf("decoding: %v", err)
+		}
+		posts = append(posts, r.URL.Path+" "+p.Text)
+	}))
+	defer srv.Close()
+
+	n := New([]config.Webhook{
+		{URL: srv.URL + "/all"},
+		{URL: srv.URL + "/crashes", Events: []string{config.WebhookCrashed}},
+	})
+	paused := &session.Session{Name: "cd-build", Status: session.StatusActive}
+	if err := n.Announce(context.Background(), NewGuardPayload(412, 23.5, paused, time.Now())); err != nil {
+		t.Fatalf("Announce: %v", err)
+	}
+	want := "/all claude sessions use 412% CPU and 24% memory, over the resource guard; paused cd-build"
+	if len(posts) != 1 || posts[0] != want {
+		t.Errorf("expected only %q, got %v", want, posts)
+	}
+}
+
+func TestPost_reportsFailedResponses(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "nope", http.StatusInternalServerError)
+	}))
+	defer srv.Close()
+
+	err := New(nil).Post(context.Background(), config.Webhook{URL: srv.URL}, Payload{Event: "waiting"})
+	if err == nil || !strings.Contains(err.Error(), "500") {
+		t.Errorf("expected the status in the error, got %v", err)
+	}
+}